@@ -7,6 +7,7 @@ import (
 	"net/http"
 	"os"
 	"path/filepath"
+	"strconv"
 	"time"
 	"timesheet/api/middleware"
 	"timesheet/internal/config"
@@ -96,7 +97,7 @@ func StartServer(p *tea.Program, refreshChan chan ui.RefreshMsg) {
 	// Create a custom logger for Gin
 	ginLogger := gin.LoggerWithConfig(gin.LoggerConfig{
 		Output:    logFile,
-		SkipPaths: []string{"/health"}, // Skip logging for health checks
+		SkipPaths: []string{"/health", "/metrics"}, // Skip logging for health checks and scrapes
 	})
 
 	router := gin.New()
@@ -115,8 +116,10 @@ func StartServer(p *tea.Program, refreshChan chan ui.RefreshMsg) {
 	// Middleware to extract and convert IP address to IPv4 if necessary
 	router.Use(middleware.RetreiveIP())
 
-	// Helper function to send refresh message
-	sendRefresh := func() {
+	// Helper function to send refresh message to the TUI and a change event
+	// to event stream subscribers
+	sendRefresh := func(c *gin.Context) {
+		publishAPIChange(c)
 		select {
 		case refreshChan <- ui.RefreshMsg{}:
 		default:
@@ -124,119 +127,201 @@ func StartServer(p *tea.Program, refreshChan chan ui.RefreshMsg) {
 		}
 	}
 
-	// Health check endpoint
-	router.GET("/health", func(c *gin.Context) {
-		c.JSON(200, gin.H{
-			"status": "ok",
-		})
-	})
+	// Health check endpoint, also under /api below
+	router.GET("/health", HealthCheck)
+
+	// Prometheus metrics endpoint (opt-in via enableMetrics)
+	if config.GetMetricsEnabled() {
+		router.GET("/metrics", GetMetrics)
+	}
+
+	// Query parameter strictness: unknown parameters are rejected when
+	// strictQueryParams is enabled, or per request with ?strict=true
+	strictMode := config.GetStrictQueryParams()
+	allowQuery := func(allowed ...string) gin.HandlerFunc {
+		return middleware.StrictQueryParams(strictMode, allowed...)
+	}
 
 	// API routes
 	api := router.Group("/api")
 	{
+		// Readiness check that pings the data layer
+		api.GET("/health", allowQuery(), HealthCheck)
+
 		// Timesheet routes
-		api.GET("/timesheet", func(c *gin.Context) {
+		api.GET("/timesheet", allowQuery("from", "to", "limit", "offset"), func(c *gin.Context) {
 			GetTimesheet(c)
 		})
-		api.POST("/timesheet", func(c *gin.Context) {
+		api.POST("/timesheet", allowQuery(), func(c *gin.Context) {
 			CreateTimesheet(c)
-			sendRefresh()
+			sendRefresh(c)
+		})
+		api.GET("/timesheet/date/:date", allowQuery(), GetTimesheetByDate)
+		api.POST("/timesheet/validate", allowQuery(), ValidateTimesheet)
+		api.GET("/timesheet/stats", allowQuery("year"), GetTimesheetStats)
+		api.GET("/timesheet/document", allowQuery("year", "month"), GetTimesheetDocument)
+		api.GET("/timesheet/summary", allowQuery("year", "month"), GetTimesheetSummary)
+		api.GET("/timesheet/unbilled", allowQuery("client"), GetUnbilledTimesheet)
+		api.POST("/timesheet/billed", allowQuery(), func(c *gin.Context) {
+			MarkTimesheetBilled(c)
+			sendRefresh(c)
+		})
+		api.GET("/years", allowQuery("training"), GetYears)
+		api.POST("/timesheet/bulk", allowQuery("overwrite"), func(c *gin.Context) {
+			BulkCreateTimesheet(c)
+			sendRefresh(c)
 		})
-		api.PUT("/timesheet/:id", func(c *gin.Context) {
+		api.POST("/timesheet/fill-idle", allowQuery("year", "month", "dryRun", "exclude"), func(c *gin.Context) {
+			FillIdleTimesheet(c)
+			// A dry run writes nothing, so there's nothing to refresh
+			if dryRun, _ := strconv.ParseBool(c.Query("dryRun")); !dryRun {
+				sendRefresh(c)
+			}
+		})
+		api.PUT("/timesheet/:id", allowQuery(), func(c *gin.Context) {
 			UpdateTimesheet(c)
-			sendRefresh()
+			sendRefresh(c)
+		})
+		api.DELETE("/timesheet/month", allowQuery("year", "month"), func(c *gin.Context) {
+			DeleteTimesheetMonth(c)
+			sendRefresh(c)
 		})
-		api.DELETE("/timesheet/:id", func(c *gin.Context) {
+		api.DELETE("/timesheet/:id", allowQuery(), func(c *gin.Context) {
 			DeleteTimesheet(c)
-			sendRefresh()
+			sendRefresh(c)
 		})
 
 		// Training Budget routes
-		api.GET("/training-budget", func(c *gin.Context) {
+		api.GET("/training-budget", allowQuery("year"), func(c *gin.Context) {
 			GetTrainingBudget(c)
 		})
-		api.POST("/training-budget", func(c *gin.Context) {
+		api.GET("/training-budget/summary", allowQuery("year"), GetTrainingBudgetSummary)
+		api.POST("/training-budget", allowQuery(), func(c *gin.Context) {
 			CreateTrainingBudget(c)
-			sendRefresh()
+			sendRefresh(c)
 		})
-		api.PUT("/training-budget", func(c *gin.Context) {
+		api.PUT("/training-budget", allowQuery(), func(c *gin.Context) {
 			UpdateTrainingBudget(c)
-			sendRefresh()
+			sendRefresh(c)
 		})
-		api.DELETE("/training-budget", func(c *gin.Context) {
+		api.DELETE("/training-budget", allowQuery("id"), func(c *gin.Context) {
 			DeleteTrainingBudget(c)
-			sendRefresh()
+			sendRefresh(c)
+		})
+		api.POST("/training-budget/:id/receipt", allowQuery(), func(c *gin.Context) {
+			UploadTrainingBudgetReceipt(c)
+			sendRefresh(c)
 		})
 
 		// Training Hours route
-		api.GET("/training-hours", func(c *gin.Context) {
+		api.GET("/training-hours", allowQuery("year"), func(c *gin.Context) {
 			GetTrainingHours(c)
 		})
 
+		// Training reconciliation route (timesheet hours vs training budget)
+		api.GET("/training-reconciliation", allowQuery("year"), GetTrainingReconciliation)
+
 		// Vacation Hours route
-		api.GET("/vacation-hours", func(c *gin.Context) {
+		api.GET("/vacation-hours", allowQuery("year"), func(c *gin.Context) {
 			GetVacationHours(c)
 		})
 
 		// Vacation Carryover routes
-		api.GET("/vacation-carryover", GetVacationCarryover)
-		api.POST("/vacation-carryover", SetVacationCarryover)
-		api.DELETE("/vacation-carryover", DeleteVacationCarryover)
-		api.GET("/vacation-summary", GetVacationSummary)
+		api.GET("/vacation-carryover", allowQuery("year"), GetVacationCarryover)
+		api.POST("/vacation-carryover", allowQuery(), SetVacationCarryover)
+		api.DELETE("/vacation-carryover", allowQuery("year"), DeleteVacationCarryover)
+		api.GET("/vacation-summary", allowQuery("year"), GetVacationSummary)
+		api.POST("/vacation/rollover", allowQuery("year"), RolloverVacation)
 
 		// Overview route (training and vacation days left)
-		api.GET("/overview", func(c *gin.Context) {
+		api.GET("/overview", allowQuery("year", "period", "date"), func(c *gin.Context) {
 			GetOverview(c)
 		})
 
 		// Get last client name
-		api.GET("/last-client", GetLastClientName)
+		api.GET("/last-client", allowQuery(), GetLastClientName)
 
 		// Client routes
-		api.GET("/clients", func(c *gin.Context) {
+		api.GET("/clients", allowQuery("active"), func(c *gin.Context) {
 			GetClients(c)
 		})
-		api.GET("/clients/:id", func(c *gin.Context) {
+		api.GET("/clients/:id", allowQuery(), func(c *gin.Context) {
 			GetClient(c)
 		})
-		api.POST("/clients", func(c *gin.Context) {
+		api.POST("/clients", allowQuery(), func(c *gin.Context) {
 			CreateClient(c)
-			sendRefresh()
+			sendRefresh(c)
+		})
+		api.POST("/clients/ensure", allowQuery(), func(c *gin.Context) {
+			EnsureClient(c)
+			sendRefresh(c)
 		})
-		api.PUT("/clients/:id", func(c *gin.Context) {
+		api.PUT("/clients/:id", allowQuery(), func(c *gin.Context) {
 			UpdateClient(c)
-			sendRefresh()
+			sendRefresh(c)
 		})
-		api.DELETE("/clients/:id", func(c *gin.Context) {
+		api.DELETE("/clients/:id", allowQuery(), func(c *gin.Context) {
 			DeleteClient(c)
-			sendRefresh()
+			sendRefresh(c)
+		})
+		api.POST("/clients/:id/duplicate", allowQuery(), func(c *gin.Context) {
+			DuplicateClient(c)
+			sendRefresh(c)
+		})
+		api.GET("/clients/:id/cap-status", allowQuery("year", "month"), func(c *gin.Context) {
+			GetClientCapStatus(c)
+		})
+		api.POST("/clients/:id/end-engagement", allowQuery(), func(c *gin.Context) {
+			EndClientEngagement(c)
+			sendRefresh(c)
 		})
 
 		// Client rate routes
-		api.GET("/clients/:id/rates", func(c *gin.Context) {
+		api.GET("/clients/:id/rates", allowQuery("limit", "offset", "effectiveAfter", "effectiveBefore"), func(c *gin.Context) {
 			GetClientRates(c)
 		})
-		api.POST("/clients/:id/rates", func(c *gin.Context) {
+		api.POST("/clients/:id/rates", allowQuery(), func(c *gin.Context) {
 			CreateClientRate(c)
-			sendRefresh()
+			sendRefresh(c)
 		})
-		api.PUT("/client-rates/:id", func(c *gin.Context) {
+		api.PUT("/client-rates/:id", allowQuery(), func(c *gin.Context) {
 			UpdateClientRate(c)
-			sendRefresh()
+			sendRefresh(c)
 		})
-		api.DELETE("/client-rates/:id", func(c *gin.Context) {
+		api.DELETE("/client-rates/:id", allowQuery(), func(c *gin.Context) {
 			DeleteClientRate(c)
-			sendRefresh()
+			sendRefresh(c)
+		})
+
+		// Expense routes
+		api.GET("/expenses", allowQuery("year", "month"), GetExpenses)
+		api.GET("/expenses/:id", allowQuery(), GetExpense)
+		api.POST("/expenses", allowQuery(), func(c *gin.Context) {
+			CreateExpense(c)
+			sendRefresh(c)
+		})
+		api.PUT("/expenses/:id", allowQuery(), func(c *gin.Context) {
+			UpdateExpense(c)
+			sendRefresh(c)
+		})
+		api.DELETE("/expenses/:id", allowQuery(), func(c *gin.Context) {
+			DeleteExpense(c)
+			sendRefresh(c)
 		})
 
 		// Earnings route
-		api.GET("/earnings", func(c *gin.Context) {
+		api.GET("/earnings", allowQuery("year", "month", "summary", "client", "symbol", "decimals", "grouping"), func(c *gin.Context) {
 			GetEarnings(c)
 		})
+		api.GET("/earnings/total", allowQuery("symbol", "decimals", "grouping"), GetEarningsTotal)
+		api.GET("/earnings/monthly", allowQuery("year", "symbol", "decimals", "grouping"), GetMonthlyEarnings)
+
+		// Live change notifications (server-sent events)
+		api.GET("/events", allowQuery(), StreamEvents)
 
 		// Export routes
-		api.GET("/export/pdf", ExportPDF)
-		api.GET("/export/excel", ExportExcel)
+		api.GET("/export/pdf", allowQuery("year", "month"), ExportPDF)
+		api.GET("/export/excel", allowQuery("year", "month"), ExportExcel)
 	}
 
 	// Start the server
@@ -0,0 +1,88 @@
+package handler
+
+import (
+	"net/http"
+	"timesheet/internal/db"
+	"timesheet/internal/utils"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MarkBilledRequest selects the entries to mark as billed
+type MarkBilledRequest struct {
+	Client     string `json:"client"` // Empty marks every client
+	Year       int    `json:"year" binding:"required"`
+	Month      int    `json:"month"` // 0 marks the whole year
+	InvoiceRef string `json:"invoice_ref"`
+}
+
+// MarkTimesheetBilled handles POST /api/timesheet/billed
+// Marks the unbilled client hours of a month (or year) as billed on an
+// invoice, so they no longer show up in the unbilled report.
+func MarkTimesheetBilled(c *gin.Context) {
+	var req MarkBilledRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if req.Month < 0 || req.Month > 12 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month (must be 1-12, or 0 for the whole year)"})
+		return
+	}
+
+	marked, err := db.MarkEntriesBilled(req.Client, req.Year, req.Month, req.InvoiceRef)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"marked": marked, "invoice_ref": req.InvoiceRef})
+}
+
+// GetUnbilledTimesheet handles GET /api/timesheet/unbilled?client=
+// Returns the client hours that haven't been billed yet, priced like the
+// earnings, with totals per currency. Without ?client= every client is listed.
+func GetUnbilledTimesheet(c *gin.Context) {
+	client := c.Query("client")
+	entries, err := db.GetUnbilledEntries(client)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	formatted := []gin.H{}
+	totalHours := 0.0
+	var currencies []string
+	amounts := make(map[string]float64)
+	for _, entry := range entries {
+		format := moneyQuery{}.format(entry.Currency)
+		formatted = append(formatted, gin.H{
+			"date":         entry.Date,
+			"client_name":  entry.ClientName,
+			"client_hours": entry.ClientHours,
+			"billed_hours": entry.BilledHours,
+			"hourly_rate":  utils.FormatMoney(entry.HourlyRate, format),
+			"amount":       utils.FormatMoney(entry.Earnings, format),
+			"currency":     entry.Currency,
+		})
+		totalHours += entry.ClientHours
+		if _, ok := amounts[entry.Currency]; !ok {
+			currencies = append(currencies, entry.Currency)
+		}
+		amounts[entry.Currency] += entry.Earnings
+	}
+
+	byCurrency := []gin.H{}
+	for _, currency := range currencies {
+		byCurrency = append(byCurrency, gin.H{
+			"currency": currency,
+			"amount":   utils.FormatMoney(amounts[currency], moneyQuery{}.format(currency)),
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"client":      client,
+		"total_hours": totalHours,
+		"by_currency": byCurrency,
+		"entries":     formatted,
+	})
+}
@@ -0,0 +1,69 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"timesheet/internal/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestBillingHandlers(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	id, _ := db.AddClient(db.Client{Name: "Acme", IsActive: true})
+	db.AddClientRate(db.ClientRate{ClientId: id, HourlyRate: 100, EffectiveDate: "2024-01-01"})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-03-01", Client_name: "Acme", Client_hours: 6})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-04-01", Client_name: "Acme", Client_hours: 8})
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/timesheet/unbilled", GetUnbilledTimesheet)
+	router.POST("/api/timesheet/billed", MarkTimesheetBilled)
+
+	unbilled := func() map[string]any {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest("GET", "/api/timesheet/unbilled?client=Acme", nil))
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+		}
+		var response map[string]any
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return response
+	}
+	mark := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest("POST", "/api/timesheet/billed", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	if response := unbilled(); response["total_hours"] != float64(14) {
+		t.Errorf("Expected 14 unbilled hours, got %v", response)
+	}
+
+	if w := mark(`{"client":"Acme","month":3}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without a year, got %d", w.Code)
+	}
+	if w := mark(`{"client":"Acme","year":2024,"month":13}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for month 13, got %d", w.Code)
+	}
+
+	w := mark(`{"client":"Acme","year":2024,"month":3,"invoice_ref":"INV-7"}`)
+	var result map[string]any
+	json.Unmarshal(w.Body.Bytes(), &result)
+	if w.Code != http.StatusOK || result["marked"] != float64(1) {
+		t.Fatalf("Expected 1 entry marked, got %d %s", w.Code, w.Body.String())
+	}
+
+	response := unbilled()
+	entries, _ := response["entries"].([]any)
+	if response["total_hours"] != float64(8) || len(entries) != 1 {
+		t.Errorf("Expected only April to be unbilled, got %v", response)
+	}
+}
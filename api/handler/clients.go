@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 	"strconv"
+	"strings"
 	"time"
+	"timesheet/internal/config"
+	"timesheet/internal/datalayer"
 	"timesheet/internal/db"
 	"timesheet/internal/utils"
 
@@ -60,6 +64,17 @@ func CreateClient(c *gin.Context) {
 		return
 	}
 
+	currency, err := db.NormalizeCurrency(client.Currency)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	client.Currency = currency
+	if err := db.ValidateClient(client); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	id, err := db.AddClient(client)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -90,6 +105,15 @@ func UpdateClient(c *gin.Context) {
 	// Ensure the ID from the URL is used
 	client.Id = id
 
+	if client.Currency, err = db.NormalizeCurrency(client.Currency); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := db.ValidateClient(client); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := db.UpdateClient(client); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -117,8 +141,196 @@ func DeleteClient(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Client deactivated successfully"})
 }
 
-// GetClientRates handles GET /api/clients/:id/rates
-// Returns all rates for a specific client
+// EndClientEngagement handles POST /api/clients/:id/end-engagement
+// Deactivates the client and notes the end date on the rate in effect on
+// that date, in one transaction. Body: {"end_date": "YYYY-MM-DD"}.
+func EndClientEngagement(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return
+	}
+
+	var req struct {
+		EndDate string `json:"end_date" binding:"required"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if _, err := time.Parse("2006-01-02", req.EndDate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid end_date (expected YYYY-MM-DD)"})
+		return
+	}
+
+	end, err := db.EndClientEngagement(id, req.EndDate)
+	if errors.Is(err, db.ErrClientNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"client":      end.Client,
+		"end_date":    end.EndDate,
+		"deactivated": end.Deactivated,
+		"closed_rate": end.ClosedRate,
+	})
+}
+
+// DuplicateClient handles POST /api/clients/:id/duplicate
+// Copies the client and its rate history to a new client, in one
+// transaction. Body: {"name": "New Client", "shift_days": 365}; shift_days
+// is optional and moves every copied rate's effective date.
+func DuplicateClient(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return
+	}
+
+	var req struct {
+		Name      string `json:"name" binding:"required"`
+		ShiftDays int    `json:"shift_days"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	duplicate, err := db.DuplicateClient(id, req.Name, req.ShiftDays)
+	switch {
+	case errors.Is(err, db.ErrClientNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	case errors.Is(err, db.ErrClientExists):
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	case err != nil:
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusCreated, duplicate)
+}
+
+// EnsureClientRequest is the body of POST /api/clients/ensure
+type EnsureClientRequest struct {
+	Name          string   `json:"name" binding:"required"`
+	HourlyRate    *float64 `json:"hourly_rate"`
+	EffectiveDate string   `json:"effective_date"` // YYYY-MM-DD; defaults to today
+	Notes         string   `json:"notes"`
+}
+
+// EnsureClient handles POST /api/clients/ensure
+// Creates the client when it's missing and adds the rate when it isn't
+// stored yet, in one transaction, and returns the resolved client. Repeating
+// the request changes nothing. Responds 201 when something was created, 200
+// otherwise, and 409 when the client has a different rate on that date.
+func EnsureClient(c *gin.Context) {
+	var req EnsureClientRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if strings.TrimSpace(req.Name) == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "name is required"})
+		return
+	}
+
+	var rate *db.ClientRate
+	if req.HourlyRate != nil {
+		if _, err := db.NormalizeHourlyRate(*req.HourlyRate); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if req.EffectiveDate != "" {
+			if _, err := time.Parse("2006-01-02", req.EffectiveDate); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid effective_date (expected YYYY-MM-DD)"})
+				return
+			}
+		}
+		rate = &db.ClientRate{HourlyRate: *req.HourlyRate, EffectiveDate: req.EffectiveDate, Notes: req.Notes}
+	} else if req.EffectiveDate != "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "effective_date needs an hourly_rate"})
+		return
+	}
+
+	ensured, err := db.EnsureClient(req.Name, rate)
+	if errors.Is(err, db.ErrRateConflict) {
+		c.JSON(http.StatusConflict, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	status := http.StatusOK
+	if ensured.ClientCreated || ensured.RateCreated {
+		status = http.StatusCreated
+	}
+	c.JSON(status, gin.H{
+		"client":         ensured.Client,
+		"client_created": ensured.ClientCreated,
+		"rate":           ensured.Rate,
+		"rate_created":   ensured.RateCreated,
+	})
+}
+
+// GetClientCapStatus handles GET /api/clients/:id/cap-status?year=&month=
+// Reports the client hours logged against the client's monthly cap for the
+// month (default: the current month) and against its total cap.
+func GetClientCapStatus(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid client ID"})
+		return
+	}
+
+	now := time.Now()
+	year, month := now.Year(), int(now.Month())
+	if yearParam := c.Query("year"); yearParam != "" {
+		year, err = strconv.Atoi(yearParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year parameter"})
+			return
+		}
+	}
+	if monthParam := c.Query("month"); monthParam != "" {
+		month, err = strconv.Atoi(monthParam)
+		if err != nil || month < 1 || month > 12 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month parameter"})
+			return
+		}
+	}
+
+	dl := datalayer.GetDataLayer()
+	client, err := dl.GetClientById(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+
+	status, err := db.GetClientCapStatus(dl, client, year, month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, status)
+}
+
+// GetClientRates handles GET /api/clients/:id/rates?limit=&offset=&effectiveAfter=&effectiveBefore=
+// Returns a client's rates, newest first. The dates filter on the effective
+// date (exclusive); limit and offset page through the result. X-Total-Count
+// holds the number of matching rates.
 func GetClientRates(c *gin.Context) {
 	idStr := c.Param("id")
 	id, err := strconv.Atoi(idStr)
@@ -127,12 +339,39 @@ func GetClientRates(c *gin.Context) {
 		return
 	}
 
-	rates, err := db.GetClientRates(id)
+	var query db.RateQuery
+	if limitStr := c.Query("limit"); limitStr != "" {
+		query.Limit, err = strconv.Atoi(limitStr)
+		if err != nil || query.Limit < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit (must be a non-negative number)"})
+			return
+		}
+	}
+	if offsetStr := c.Query("offset"); offsetStr != "" {
+		query.Offset, err = strconv.Atoi(offsetStr)
+		if err != nil || query.Offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset (must be a non-negative number)"})
+			return
+		}
+	}
+	query.EffectiveAfter = c.Query("effectiveAfter")
+	query.EffectiveBefore = c.Query("effectiveBefore")
+	for _, date := range []string{query.EffectiveAfter, query.EffectiveBefore} {
+		if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid effectiveAfter or effectiveBefore (expected YYYY-MM-DD)"})
+			return
+		}
+	}
+
+	rates, total, err := db.QueryClientRates(id, query)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
 
+	// The body stays a plain list; the number of matching rates across all
+	// pages is in a header
+	c.Header("X-Total-Count", strconv.Itoa(total))
 	c.JSON(http.StatusOK, rates)
 }
 
@@ -155,6 +394,17 @@ func CreateClientRate(c *gin.Context) {
 	// Ensure the client_id from the URL is used
 	rate.ClientId = clientId
 
+	hourlyRate, err := db.NormalizeHourlyRate(rate.HourlyRate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rate.HourlyRate = hourlyRate
+	if err := db.ValidateRateEndDate(rate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := db.AddClientRate(rate); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -182,6 +432,17 @@ func UpdateClientRate(c *gin.Context) {
 	// Ensure the ID from the URL is used
 	rate.Id = id
 
+	hourlyRate, err := db.NormalizeHourlyRate(rate.HourlyRate)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	rate.HourlyRate = hourlyRate
+	if err := db.ValidateRateEndDate(rate); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	if err := db.UpdateClientRate(rate); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -208,8 +469,8 @@ func DeleteClientRate(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Rate deleted successfully"})
 }
 
-// GetEarnings handles GET /api/earnings?year=YYYY&month=MM
-// Returns earnings overview for a year or specific month
+// GetEarnings handles GET /api/earnings?year=YYYY&month=MM or ?year=YYYY&client=NAME
+// Returns earnings overview for a year, a specific month or one client
 func GetEarnings(c *gin.Context) {
 	yearStr := c.Query("year")
 	if yearStr == "" {
@@ -223,11 +484,28 @@ func GetEarnings(c *gin.Context) {
 		return
 	}
 
+	money, err := moneyQueryFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
 	monthStr := c.Query("month")
 	summaryStr := c.Query("summary")
+	clientName := strings.TrimSpace(c.Query("client"))
 	var overview db.EarningsOverview
 
-	if monthStr != "" {
+	if clientName != "" {
+		if monthStr != "" || summaryStr == "true" {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "client can't be combined with month or summary"})
+			return
+		}
+		overview, err = db.CalculateEarningsForClient(year, clientName)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+	} else if monthStr != "" {
 		// Calculate for specific month
 		month, err := strconv.Atoi(monthStr)
 		if err != nil || month < 1 || month > 12 {
@@ -256,30 +534,192 @@ func GetEarnings(c *gin.Context) {
 		}
 	}
 
-	// Format response with Euro formatting
-	response := formatEarningsResponse(overview)
+	// Format amounts in their currency (or the requested overrides)
+	response := formatEarningsResponse(overview, money)
 	c.JSON(http.StatusOK, response)
 }
 
-// formatEarningsResponse formats the earnings overview with Euro currency formatting
-func formatEarningsResponse(overview db.EarningsOverview) gin.H {
+// GetMonthlyEarnings handles GET /api/earnings/monthly?year=YYYY
+// Returns the totals of each month of the year, including months without
+// client hours, e.g. for a revenue chart
+func GetMonthlyEarnings(c *gin.Context) {
+	year := time.Now().Year()
+	if yearStr := c.Query("year"); yearStr != "" {
+		var err error
+		if year, err = strconv.Atoi(yearStr); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+			return
+		}
+	}
+
+	money, err := moneyQueryFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	overviews, err := db.CalculateMonthlyEarningsForYear(year)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	months := make([]gin.H, 0, len(overviews))
+	for _, overview := range overviews {
+		month := formatEarningsResponse(overview, money)
+		// Only the totals; /api/earnings?month= lists a month's entries
+		delete(month, "entries")
+		months = append(months, month)
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"year":   year,
+		"months": months,
+	})
+}
+
+// GetEarningsTotal handles GET /api/earnings/total
+// Returns per-year earnings subtotals and a grand total across every year
+// that has timesheet entries
+func GetEarningsTotal(c *gin.Context) {
+	money, err := moneyQueryFromRequest(c)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	format := money.format(config.GetBaseCurrency())
+
+	minYear, maxYear, err := db.GetTimesheetYearRange()
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	years := []gin.H{}
+	totalHours := 0.0
+	totalEarnings := 0.0
+	if minYear != 0 {
+		for year := minYear; year <= maxYear; year++ {
+			overview, err := db.CalculateEarningsSummaryForYear(year)
+			if err != nil {
+				c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+				return
+			}
+			years = append(years, gin.H{
+				"year":           year,
+				"total_hours":    overview.TotalHours,
+				"total_earnings": utils.FormatMoney(overview.TotalEarnings, format),
+			})
+			totalHours += overview.TotalHours
+			totalEarnings += overview.TotalEarnings
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"first_year":     minYear,
+		"last_year":      maxYear,
+		"total_hours":    totalHours,
+		"total_earnings": utils.FormatMoney(totalEarnings, format),
+		"currency":       config.GetBaseCurrency(),
+		"years":          years,
+	})
+}
+
+// moneyQuery holds the money format overrides a request chose with the
+// optional symbol, decimals and grouping query parameters, e.g.
+// ?symbol=&decimals=0&grouping=. for CSV use
+type moneyQuery struct {
+	symbol   *string
+	decimals *int
+	grouping *string
+}
+
+// moneyQueryFromRequest reads the money format overrides of a request
+func moneyQueryFromRequest(c *gin.Context) (moneyQuery, error) {
+	var query moneyQuery
+
+	if symbol, ok := c.GetQuery("symbol"); ok {
+		query.symbol = &symbol
+	}
+	if decimalsStr := c.Query("decimals"); decimalsStr != "" {
+		decimals, err := strconv.Atoi(decimalsStr)
+		if err != nil || decimals < 0 || decimals > 6 {
+			return query, errors.New("Invalid decimals (must be 0-6)")
+		}
+		query.decimals = &decimals
+	}
+	if grouping, ok := c.GetQuery("grouping"); ok {
+		query.grouping = &grouping
+	}
+
+	return query, nil
+}
+
+// format returns the money format for amounts in currency (the base currency
+// when empty): the currency's own conventions with the overrides applied
+func (q moneyQuery) format(currency string) utils.MoneyFormat {
+	if currency == "" {
+		currency = config.GetBaseCurrency()
+	}
+	format := utils.MoneyFormatFor(currency)
+	if q.symbol != nil {
+		format.Symbol = *q.symbol
+	}
+	if q.decimals != nil {
+		format.Decimals = *q.decimals
+	}
+	if q.grouping != nil {
+		format.ThousandsSep = *q.grouping
+	}
+	return format
+}
+
+// formatEarningsResponse formats the earnings overview. Amounts are written
+// in the format of their currency with the request's overrides applied.
+func formatEarningsResponse(overview db.EarningsOverview, money moneyQuery) gin.H {
 	// Format individual entries
 	var formattedEntries []gin.H
 	for _, entry := range overview.Entries {
+		entryFormat := money.format(entry.Currency)
 		formattedEntries = append(formattedEntries, gin.H{
 			"date":         entry.Date,
 			"client_name":  entry.ClientName,
 			"client_hours": entry.ClientHours,
-			"hourly_rate":  utils.FormatEuro(entry.HourlyRate),
-			"earnings":     utils.FormatEuro(entry.Earnings),
+			"billed_hours": entry.BilledHours,
+			"hourly_rate":  utils.FormatMoney(entry.HourlyRate, entryFormat),
+			"earnings":     utils.FormatMoney(entry.Earnings, entryFormat),
+			"currency":     entry.Currency,
+
+			"regular_hours":     entry.RegularHours,
+			"overtime_hours":    entry.OvertimeHours,
+			"regular_earnings":  utils.FormatMoney(entry.RegularEarnings, entryFormat),
+			"overtime_earnings": utils.FormatMoney(entry.OvertimeEarnings, entryFormat),
+		})
+	}
+
+	byCurrency := []gin.H{}
+	for _, total := range overview.ByCurrency {
+		byCurrency = append(byCurrency, gin.H{
+			"currency":    total.Currency,
+			"total_hours": total.Hours,
+			"earnings":    utils.FormatMoney(total.Earnings, money.format(total.Currency)),
 		})
 	}
 
-	return gin.H{
+	response := gin.H{
 		"year":           overview.Year,
 		"month":          overview.Month,
 		"total_hours":    overview.TotalHours,
-		"total_earnings": utils.FormatEuro(overview.TotalEarnings),
+		"total_earnings": utils.FormatMoney(overview.TotalEarnings, money.format(overview.Currency)),
+		"currency":       overview.Currency,
+		"by_currency":    byCurrency,
+		"unconverted":    nonNil(overview.Unconverted),
 		"entries":        formattedEntries,
 	}
+	// Only users who record expenses see the expense totals
+	if overview.BillableExpenses != 0 {
+		baseFormat := money.format(overview.Currency)
+		response["billable_expenses"] = utils.FormatMoney(overview.BillableExpenses, baseFormat)
+		response["total_invoiced"] = utils.FormatMoney(overview.TotalInvoiced, baseFormat)
+	}
+	return response
 }
@@ -8,6 +8,7 @@ import (
 	"strconv"
 	"strings"
 	"testing"
+	"timesheet/internal/config"
 	"timesheet/internal/db"
 
 	"github.com/gin-gonic/gin"
@@ -184,6 +185,40 @@ func TestCreateClient(t *testing.T) {
 	}
 }
 
+func TestCreateClientMinBillableHours(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	gin.SetMode(gin.TestMode)
+	post := func(client db.Client) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(client)
+		req := httptest.NewRequest("POST", "/api/clients", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		CreateClient(c)
+		return w
+	}
+
+	w := post(db.Client{Name: "Retainer Client", IsActive: true, MinBillableHours: 4})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var result db.Client
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	stored, err := db.GetClientById(result.Id)
+	if err != nil || stored.MinBillableHours != 4 {
+		t.Errorf("Expected a stored minimum of 4 hours, got %+v (%v)", stored, err)
+	}
+
+	if w := post(db.Client{Name: "Odd Client", IsActive: true, MinBillableHours: -2}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a negative minimum, got %d", w.Code)
+	}
+}
+
 func TestUpdateClient(t *testing.T) {
 	dbPath := setupHandlerTest(t)
 	defer teardownHandlerTest(t, dbPath)
@@ -258,6 +293,195 @@ func TestDeleteClient(t *testing.T) {
 	}
 }
 
+func TestEndClientEngagement(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	id, _ := db.AddClient(db.Client{Name: "Ending Client", IsActive: true})
+	db.AddClientRate(db.ClientRate{ClientId: id, HourlyRate: 90, EffectiveDate: "2024-01-01"})
+
+	gin.SetMode(gin.TestMode)
+	run := func(id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/clients/"+id+"/end-engagement", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{gin.Param{Key: "id", Value: id}}
+		EndClientEngagement(c)
+		return w
+	}
+
+	w := run(strconv.Itoa(id), `{"end_date": "2024-03-31"}`)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Deactivated bool
+		ClosedRate  *db.ClientRate `json:"closed_rate"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if !response.Deactivated {
+		t.Error("Expected the client to be reported as deactivated")
+	}
+	if response.ClosedRate == nil || response.ClosedRate.Notes != "Engagement ended 2024-03-31" {
+		t.Errorf("Expected the closed rate with an end note, got %+v", response.ClosedRate)
+	}
+
+	client, _ := db.GetClientById(id)
+	if client.IsActive {
+		t.Error("Expected client to be deactivated")
+	}
+
+	if w := run(strconv.Itoa(id), `{"end_date": "March 31"}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid date, got %d", w.Code)
+	}
+	if w := run("9999", `{"end_date": "2024-03-31"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown client, got %d", w.Code)
+	}
+}
+
+func TestEnsureClient(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	gin.SetMode(gin.TestMode)
+	run := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/clients/ensure", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		EnsureClient(c)
+		return w
+	}
+
+	body := `{"name": "Pushed Client", "hourly_rate": 90, "effective_date": "2024-01-01"}`
+	w := run(body)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Client        db.Client
+		ClientCreated bool           `json:"client_created"`
+		Rate          *db.ClientRate `json:"rate"`
+		RateCreated   bool           `json:"rate_created"`
+	}
+	json.Unmarshal(w.Body.Bytes(), &response)
+	if response.Client.Id == 0 || !response.ClientCreated || !response.RateCreated || response.Rate == nil {
+		t.Errorf("Expected a new client and rate, got %+v", response)
+	}
+
+	if w := run(body); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 for a repeated request, got %d: %s", w.Code, w.Body.String())
+	}
+	if rates, _ := db.GetClientRates(response.Client.Id); len(rates) != 1 {
+		t.Errorf("Expected 1 rate after repeating the request, got %d", len(rates))
+	}
+
+	if w := run(`{"name": "Pushed Client", "hourly_rate": 95, "effective_date": "2024-01-01"}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for a different rate, got %d", w.Code)
+	}
+	for _, invalid := range []string{
+		`{"hourly_rate": 90}`,
+		`{"name": "Pushed Client", "hourly_rate": 0}`,
+		`{"name": "Pushed Client", "hourly_rate": 90, "effective_date": "January"}`,
+		`{"name": "Pushed Client", "effective_date": "2024-01-01"}`,
+	} {
+		if w := run(invalid); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", invalid, w.Code)
+		}
+	}
+}
+
+func TestGetClientCapStatus(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	id, _ := db.AddClient(db.Client{Name: "Fixed Scope", IsActive: true, MonthlyHourCap: 16})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-02-01", Client_name: "Fixed Scope", Client_hours: 8})
+
+	gin.SetMode(gin.TestMode)
+	run := func(id, query string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("GET", "/api/clients/"+id+"/cap-status?"+query, nil)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{gin.Param{Key: "id", Value: id}}
+		GetClientCapStatus(c)
+		return w
+	}
+
+	w := run(strconv.Itoa(id), "year=2024&month=2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var status db.ClientCapStatus
+	json.Unmarshal(w.Body.Bytes(), &status)
+	if status.Monthly == nil || status.Monthly.Remaining != 8 || status.Monthly.Status != db.CapOK || status.Total != nil {
+		t.Errorf("Unexpected cap status: %+v", status)
+	}
+
+	// Logging up to the cap warns without rejecting the entry
+	body, _ := json.Marshal(db.TimesheetEntry{Date: "2024-02-02", Client_name: "Fixed Scope", Client_hours: 8})
+	req := httptest.NewRequest("POST", "/api/timesheet", bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = req
+	CreateTimesheet(c)
+	if rec.Code != http.StatusCreated || !strings.Contains(rec.Header().Get("Warning"), "16 of 16 monthly hours") {
+		t.Errorf("Expected 201 with a cap warning, got %d and %q", rec.Code, rec.Header().Get("Warning"))
+	}
+
+	if w := run(strconv.Itoa(id), "month=13"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid month, got %d", w.Code)
+	}
+	if w := run("9999", ""); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown client, got %d", w.Code)
+	}
+}
+
+func TestDuplicateClient(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	id, _ := db.AddClient(db.Client{Name: "Tiered Client", IsActive: true})
+	db.AddClientRate(db.ClientRate{ClientId: id, HourlyRate: 90, EffectiveDate: "2024-01-01"})
+
+	gin.SetMode(gin.TestMode)
+	run := func(id, body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest("POST", "/api/clients/"+id+"/duplicate", strings.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{gin.Param{Key: "id", Value: id}}
+		DuplicateClient(c)
+		return w
+	}
+
+	w := run(strconv.Itoa(id), `{"name": "Copied Client", "shift_days": 31}`)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var duplicate db.ClientWithRates
+	json.Unmarshal(w.Body.Bytes(), &duplicate)
+	if duplicate.Name != "Copied Client" || len(duplicate.Rates) != 1 || duplicate.Rates[0].EffectiveDate != "2024-02-01" {
+		t.Errorf("Unexpected duplicate: %+v", duplicate)
+	}
+
+	if w := run(strconv.Itoa(id), `{"name": "Copied Client"}`); w.Code != http.StatusConflict {
+		t.Errorf("Expected status 409 for an existing name, got %d", w.Code)
+	}
+	if w := run("9999", `{"name": "Other Copy"}`); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for an unknown client, got %d", w.Code)
+	}
+	if w := run(strconv.Itoa(id), `{"shift_days": 1}`); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without a name, got %d", w.Code)
+	}
+}
+
 func TestGetClientRates(t *testing.T) {
 	dbPath := setupHandlerTest(t)
 	defer teardownHandlerTest(t, dbPath)
@@ -308,6 +532,50 @@ func TestGetClientRates(t *testing.T) {
 	}
 }
 
+func TestGetClientRatesPaged(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	clientId, _ := db.AddClient(db.Client{Name: "Client A", IsActive: true})
+	for i, date := range []string{"2021-01-01", "2022-01-01", "2023-01-01", "2024-01-01", "2025-01-01"} {
+		db.AddClientRate(db.ClientRate{ClientId: clientId, HourlyRate: float64(100 + 10*i), EffectiveDate: date})
+	}
+
+	gin.SetMode(gin.TestMode)
+	get := func(query string) (*httptest.ResponseRecorder, []db.ClientRate) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/clients/"+strconv.Itoa(clientId)+"/rates?"+query, nil)
+		c.Params = gin.Params{{Key: "id", Value: strconv.Itoa(clientId)}}
+		GetClientRates(c)
+		var rates []db.ClientRate
+		json.Unmarshal(w.Body.Bytes(), &rates)
+		return w, rates
+	}
+
+	w, rates := get("limit=2&offset=1")
+	if w.Code != http.StatusOK || len(rates) != 2 || rates[0].EffectiveDate != "2024-01-01" {
+		t.Errorf("Expected the 2nd and 3rd newest rates, got %d %+v", w.Code, rates)
+	}
+	if total := w.Header().Get("X-Total-Count"); total != "5" {
+		t.Errorf("Expected a total count of 5, got %q", total)
+	}
+
+	w, rates = get("effectiveAfter=2021-01-01&effectiveBefore=2025-01-01")
+	if len(rates) != 3 || rates[0].EffectiveDate != "2024-01-01" || rates[2].EffectiveDate != "2022-01-01" {
+		t.Errorf("Expected the rates between 2021 and 2025, got %+v", rates)
+	}
+	if total := w.Header().Get("X-Total-Count"); total != "3" {
+		t.Errorf("Expected a total count of 3, got %q", total)
+	}
+
+	for _, query := range []string{"limit=-1", "offset=x", "effectiveBefore=2024-13-01"} {
+		if w, _ := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %s, got %d", query, w.Code)
+		}
+	}
+}
+
 func TestCreateClientRate(t *testing.T) {
 	dbPath := setupHandlerTest(t)
 	defer teardownHandlerTest(t, dbPath)
@@ -353,6 +621,42 @@ func TestCreateClientRate(t *testing.T) {
 	}
 }
 
+func TestCreateClientRateValidation(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	clientId, _ := db.AddClient(db.Client{Name: "Client A", IsActive: true})
+
+	gin.SetMode(gin.TestMode)
+	create := func(hourlyRate float64) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(db.ClientRate{HourlyRate: hourlyRate, EffectiveDate: "2024-01-01"})
+		req := httptest.NewRequest("POST", "/api/clients/"+strconv.Itoa(clientId)+"/rates", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = req
+		c.Params = gin.Params{gin.Param{Key: "id", Value: strconv.Itoa(clientId)}}
+		CreateClientRate(c)
+		return w
+	}
+
+	for _, bad := range []float64{0, -25} {
+		if w := create(bad); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for rate %v, got %d", bad, w.Code)
+		}
+	}
+
+	w := create(100.005)
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var result db.ClientRate
+	json.Unmarshal(w.Body.Bytes(), &result)
+	if result.HourlyRate != 100.01 {
+		t.Errorf("Expected the rate to be rounded to 100.01, got %v", result.HourlyRate)
+	}
+}
+
 func TestUpdateClientRate(t *testing.T) {
 	dbPath := setupHandlerTest(t)
 	defer teardownHandlerTest(t, dbPath)
@@ -586,6 +890,271 @@ func TestGetEarnings(t *testing.T) {
 	}
 }
 
+func TestGetEarningsForClient(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	acmeId, _ := db.AddClient(db.Client{Name: "Acme Corp", IsActive: true})
+	globexId, _ := db.AddClient(db.Client{Name: "Globex", IsActive: true})
+	db.AddClientRate(db.ClientRate{ClientId: acmeId, HourlyRate: 100, EffectiveDate: "2024-01-01"})
+	db.AddClientRate(db.ClientRate{ClientId: globexId, HourlyRate: 80, EffectiveDate: "2024-01-01"})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-15", Client_name: "Acme Corp", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-16", Client_name: "Globex", Client_hours: 6})
+
+	gin.SetMode(gin.TestMode)
+	run := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/earnings?"+query, nil)
+		GetEarnings(c)
+		return w
+	}
+
+	w := run("year=2024&client=Globex")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		TotalHours float64 `json:"total_hours"`
+		Entries    []struct {
+			ClientName string `json:"client_name"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.TotalHours != 6 || len(response.Entries) != 1 || response.Entries[0].ClientName != "Globex" {
+		t.Errorf("Expected only Globex's 6 hours, got %s", w.Body.String())
+	}
+
+	// A client without hours gets an empty overview
+	w = run("year=2024&client=Initech")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	response.Entries = nil
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.TotalHours != 0 || len(response.Entries) != 0 {
+		t.Errorf("Expected an empty overview, got %s", w.Body.String())
+	}
+
+	if w := run("year=2024&month=1&client=Globex"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 when combined with month, got %d", w.Code)
+	}
+}
+
+func TestGetMonthlyEarnings(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	clientId, _ := db.AddClient(db.Client{Name: "Acme Corp", IsActive: true})
+	db.AddClientRate(db.ClientRate{ClientId: clientId, HourlyRate: 100, EffectiveDate: "2024-01-01"})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-02-15", Client_name: "Acme Corp", Client_hours: 8})
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/earnings/monthly?year=2024", nil)
+
+	GetMonthlyEarnings(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var response struct {
+		Year   int `json:"year"`
+		Months []struct {
+			Month         int     `json:"month"`
+			TotalHours    float64 `json:"total_hours"`
+			TotalEarnings string  `json:"total_earnings"`
+		} `json:"months"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if response.Year != 2024 || len(response.Months) != 12 {
+		t.Fatalf("Expected 12 months of 2024, got %s", w.Body.String())
+	}
+	if response.Months[0].Month != 1 || response.Months[0].TotalHours != 0 {
+		t.Errorf("Expected an empty January, got %+v", response.Months[0])
+	}
+	if response.Months[1].TotalHours != 8 || response.Months[1].TotalEarnings != "€800,00" {
+		t.Errorf("Unexpected February: %+v", response.Months[1])
+	}
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/earnings/monthly?year=abc", nil)
+	GetMonthlyEarnings(c)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid year, got %d", w.Code)
+	}
+}
+
+func TestGetEarningsBaseCurrencyFormat(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	cfg, _ := config.GetConfig()
+	cfg.BaseCurrency = "GBP"
+	if err := config.SaveConfig(cfg); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	clientId, _ := db.AddClient(db.Client{Name: "Acme Corp", IsActive: true})
+	db.AddClientRate(db.ClientRate{ClientId: clientId, HourlyRate: 150, EffectiveDate: "2024-01-01"})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-15", Client_name: "Acme Corp", Client_hours: 8})
+
+	gin.SetMode(gin.TestMode)
+	earnings := func(query string) map[string]any {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/earnings?year=2024&month=1"+query, nil)
+		GetEarnings(c)
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var response map[string]any
+		if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		return response
+	}
+
+	// Clients without a currency are billed in the base currency's format
+	if got := earnings("")["total_earnings"]; got != "£1,200.00" {
+		t.Errorf("Expected £1,200.00, got %v", got)
+	}
+	// Query overrides still apply on top of it
+	if got := earnings("&symbol=&grouping=&decimals=0")["total_earnings"]; got != "1200" {
+		t.Errorf("Expected 1200, got %v", got)
+	}
+}
+
+func TestGetEarningsMultiCurrency(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	cfg, _ := config.GetConfig()
+	cfg.ExchangeRates = map[string]float64{"USD": 0.5}
+	if err := config.SaveConfig(cfg); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	euroId, _ := db.AddClient(db.Client{Name: "Acme Corp", IsActive: true})
+	usId, _ := db.AddClient(db.Client{Name: "US Corp", IsActive: true, Currency: "USD"})
+	db.AddClientRate(db.ClientRate{ClientId: euroId, HourlyRate: 100, EffectiveDate: "2024-01-01"})
+	db.AddClientRate(db.ClientRate{ClientId: usId, HourlyRate: 80, EffectiveDate: "2024-01-01"})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-15", Client_name: "Acme Corp", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-16", Client_name: "US Corp", Client_hours: 10})
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/earnings?year=2024&month=1", nil)
+
+	GetEarnings(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	var response struct {
+		TotalEarnings string `json:"total_earnings"`
+		Currency      string `json:"currency"`
+		ByCurrency    []struct {
+			Currency string `json:"currency"`
+			Earnings string `json:"earnings"`
+		} `json:"by_currency"`
+		Entries []struct {
+			ClientName string `json:"client_name"`
+			Earnings   string `json:"earnings"`
+			Currency   string `json:"currency"`
+		} `json:"entries"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	// €800 plus $800 at 0.5
+	if response.Currency != "EUR" || response.TotalEarnings != "€1200,00" {
+		t.Errorf("Expected total €1200,00 in EUR, got %s in %s", response.TotalEarnings, response.Currency)
+	}
+	if len(response.ByCurrency) != 2 || response.ByCurrency[1].Currency != "USD" || response.ByCurrency[1].Earnings != "$800.00" {
+		t.Errorf("Unexpected per-currency totals: %+v", response.ByCurrency)
+	}
+	for _, entry := range response.Entries {
+		if entry.ClientName == "US Corp" && (entry.Currency != "USD" || entry.Earnings != "$800.00") {
+			t.Errorf("Expected US Corp earnings $800.00 in USD, got %s in %s", entry.Earnings, entry.Currency)
+		}
+	}
+
+	// Invalid client currencies are rejected
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/clients", bytes.NewBufferString(`{"Name":"Bad","Currency":"DOLLARS"}`))
+	c.Request.Header.Set("Content-Type", "application/json")
+	CreateClient(c)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid currency, got %d", w.Code)
+	}
+}
+
+func TestGetEarningsFormatOverrides(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	clientId, _ := db.AddClient(db.Client{Name: "Acme Corp", IsActive: true})
+	db.AddClientRate(db.ClientRate{
+		ClientId:      clientId,
+		HourlyRate:    125.00,
+		EffectiveDate: "2024-01-01",
+	})
+	for day := 10; day < 20; day++ {
+		db.AddTimesheetEntry(db.TimesheetEntry{
+			Date:         "2024-01-" + strconv.Itoa(day),
+			Client_name:  "Acme Corp",
+			Client_hours: 8,
+		})
+	}
+
+	gin.SetMode(gin.TestMode)
+
+	// No symbol, no decimals, dot grouping
+	req := httptest.NewRequest("GET", "/api/earnings?year=2024&symbol=&decimals=0&grouping=.", nil)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+
+	GetEarnings(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if result["total_earnings"] != "10.000" {
+		t.Errorf("Expected total_earnings 10.000, got %v", result["total_earnings"])
+	}
+
+	// Invalid decimals
+	req = httptest.NewRequest("GET", "/api/earnings?year=2024&decimals=abc", nil)
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = req
+
+	GetEarnings(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
 func TestGetEarningsDefaultYear(t *testing.T) {
 	dbPath := setupHandlerTest(t)
 	defer teardownHandlerTest(t, dbPath)
@@ -614,3 +1183,65 @@ func TestGetEarningsDefaultYear(t *testing.T) {
 		t.Error("Expected year field in response")
 	}
 }
+
+func TestGetEarningsTotal(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	gin.SetMode(gin.TestMode)
+
+	// Empty database
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/earnings/total", nil)
+
+	GetEarningsTotal(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if result["total_earnings"] != "€0,00" {
+		t.Errorf("Expected total_earnings €0,00, got %v", result["total_earnings"])
+	}
+
+	clientId, _ := db.AddClient(db.Client{Name: "Acme Corp", IsActive: true})
+	db.AddClientRate(db.ClientRate{ClientId: clientId, HourlyRate: 100, EffectiveDate: "2022-01-01"})
+	db.AddClientRate(db.ClientRate{ClientId: clientId, HourlyRate: 110, EffectiveDate: "2024-01-01"})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2022-05-02", Client_name: "Acme Corp", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-03-04", Client_name: "Acme Corp", Client_hours: 8})
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/earnings/total", nil)
+
+	GetEarningsTotal(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+
+	if result["first_year"] != float64(2022) || result["last_year"] != float64(2024) {
+		t.Errorf("Expected years 2022-2024, got %v-%v", result["first_year"], result["last_year"])
+	}
+	if result["total_hours"] != float64(16) {
+		t.Errorf("Expected total_hours 16, got %v", result["total_hours"])
+	}
+	if result["total_earnings"] != "€1680,00" {
+		t.Errorf("Expected total_earnings €1680,00, got %v", result["total_earnings"])
+	}
+	years, ok := result["years"].([]interface{})
+	if !ok || len(years) != 3 {
+		t.Fatalf("Expected 3 yearly subtotals (2022-2024), got %v", result["years"])
+	}
+	middle := years[1].(map[string]interface{})
+	if middle["year"] != float64(2023) || middle["total_earnings"] != "€0,00" {
+		t.Errorf("Expected empty 2023 subtotal, got %v", middle)
+	}
+}
@@ -0,0 +1,134 @@
+package handler
+
+import (
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// eventKeepAlive is how often an idle event stream sends a comment, so
+// proxies and browsers don't drop the connection
+var eventKeepAlive = 30 * time.Second
+
+// ChangeEvent tells event stream subscribers that data changed
+type ChangeEvent struct {
+	Source   string `json:"source"`             // "api" or "tui"
+	Action   string `json:"action"`             // "added", "updated", "deleted" or "changed"
+	Resource string `json:"resource,omitempty"` // e.g. "timesheet", "clients"; empty for TUI edits
+	ID       string `json:"id,omitempty"`
+	Time     string `json:"time"` // RFC 3339
+}
+
+// eventBroker fans change events out to every connected stream
+type eventBroker struct {
+	mu          sync.Mutex
+	subscribers map[chan ChangeEvent]struct{}
+}
+
+var events = &eventBroker{subscribers: make(map[chan ChangeEvent]struct{})}
+
+func (b *eventBroker) subscribe() chan ChangeEvent {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	ch := make(chan ChangeEvent, 16)
+	b.subscribers[ch] = struct{}{}
+	return ch
+}
+
+func (b *eventBroker) unsubscribe(ch chan ChangeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	delete(b.subscribers, ch)
+}
+
+// publish sends an event to all subscribers. A subscriber that isn't keeping
+// up misses the event rather than blocking the request that caused it.
+func (b *eventBroker) publish(event ChangeEvent) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for ch := range b.subscribers {
+		select {
+		case ch <- event:
+		default:
+		}
+	}
+}
+
+// PublishTUIChange notifies event stream subscribers that the TUI changed
+// data. The TUI doesn't say what changed, so clients should reload.
+func PublishTUIChange() {
+	events.publish(ChangeEvent{
+		Source: "tui",
+		Action: "changed",
+		Time:   time.Now().Format(time.RFC3339),
+	})
+}
+
+// publishAPIChange notifies event stream subscribers of a successful
+// mutating API request
+func publishAPIChange(c *gin.Context) {
+	if c.Writer.Status() >= http.StatusBadRequest {
+		return
+	}
+
+	var action string
+	switch c.Request.Method {
+	case http.MethodPost:
+		action = "added"
+	case http.MethodPut:
+		action = "updated"
+	case http.MethodDelete:
+		action = "deleted"
+	default:
+		action = "changed"
+	}
+
+	// The resource is the first path segment after /api, e.g. "clients"
+	// for /api/clients/:id/rates
+	resource := strings.TrimPrefix(c.FullPath(), "/api/")
+	if i := strings.Index(resource, "/"); i >= 0 {
+		resource = resource[:i]
+	}
+
+	events.publish(ChangeEvent{
+		Source:   "api",
+		Action:   action,
+		Resource: resource,
+		ID:       c.Param("id"),
+		Time:     time.Now().Format(time.RFC3339),
+	})
+}
+
+// StreamEvents handles GET /api/events
+// Streams a server-sent "change" event whenever data is added, updated or
+// deleted through the API or the TUI, until the client disconnects.
+func StreamEvents(c *gin.Context) {
+	ch := events.subscribe()
+	defer events.unsubscribe(ch)
+
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Status(http.StatusOK)
+	c.Writer.Flush()
+
+	keepAlive := time.NewTicker(eventKeepAlive)
+	defer keepAlive.Stop()
+
+	c.Stream(func(w io.Writer) bool {
+		select {
+		case event := <-ch:
+			c.SSEvent("change", event)
+			return true
+		case <-keepAlive.C:
+			_, err := io.WriteString(w, ": keep-alive\n\n")
+			return err == nil
+		case <-c.Request.Context().Done():
+			return false
+		}
+	})
+}
@@ -0,0 +1,84 @@
+package handler
+
+import (
+	"bufio"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestStreamEvents(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/api/events", StreamEvents)
+	router.POST("/api/expenses", func(c *gin.Context) {
+		CreateExpense(c)
+		publishAPIChange(c)
+	})
+	router.DELETE("/api/expenses/:id", func(c *gin.Context) {
+		DeleteExpense(c)
+		publishAPIChange(c)
+	})
+	server := httptest.NewServer(router)
+	defer server.Close()
+
+	resp, err := http.Get(server.URL + "/api/events")
+	if err != nil {
+		t.Fatalf("Failed to open event stream: %v", err)
+	}
+	defer resp.Body.Close()
+	if ct := resp.Header.Get("Content-Type"); !strings.HasPrefix(ct, "text/event-stream") {
+		t.Fatalf("Expected an event stream, got Content-Type %q", ct)
+	}
+
+	events := make(chan ChangeEvent)
+	go func() {
+		scanner := bufio.NewScanner(resp.Body)
+		for scanner.Scan() {
+			if data, ok := strings.CutPrefix(scanner.Text(), "data:"); ok {
+				var event ChangeEvent
+				json.Unmarshal([]byte(data), &event)
+				events <- event
+			}
+		}
+	}()
+	next := func() ChangeEvent {
+		select {
+		case event := <-events:
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("Timed out waiting for an event")
+			return ChangeEvent{}
+		}
+	}
+
+	// A failed request changes nothing and must not produce an event, so the
+	// first event is the expense being added
+	req, _ := http.NewRequest(http.MethodDelete, server.URL+"/api/expenses/999", nil)
+	if resp, err := http.DefaultClient.Do(req); err != nil || resp.StatusCode != http.StatusNotFound {
+		t.Fatalf("Expected deleting an unknown expense to fail with 404, got %v %v", resp, err)
+	}
+	body := `{"Date":"2024-03-04","Client_name":"Acme","Description":"Train ticket","Amount":42.5}`
+	if resp, err := http.Post(server.URL+"/api/expenses", "application/json", strings.NewReader(body)); err != nil || resp.StatusCode != http.StatusCreated {
+		t.Fatalf("Expected creating an expense to succeed, got %v %v", resp, err)
+	}
+
+	event := next()
+	if event.Source != "api" || event.Action != "added" || event.Resource != "expenses" {
+		t.Errorf("Expected an api/added/expenses event, got %+v", event)
+	}
+
+	PublishTUIChange()
+	event = next()
+	if event.Source != "tui" || event.Action != "changed" {
+		t.Errorf("Expected a tui/changed event, got %+v", event)
+	}
+}
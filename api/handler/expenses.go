@@ -0,0 +1,132 @@
+package handler
+
+import (
+	"database/sql"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+	"timesheet/internal/datalayer"
+	"timesheet/internal/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetExpenses handles GET /api/expenses?year=&month=
+// Returns the expenses of a month, or of the whole year without ?month=.
+// The year defaults to the current year.
+func GetExpenses(c *gin.Context) {
+	year := time.Now().Year()
+	if yearStr := c.Query("year"); yearStr != "" {
+		var err error
+		year, err = strconv.Atoi(yearStr)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year"})
+			return
+		}
+	}
+
+	month := 0
+	if monthStr := c.Query("month"); monthStr != "" {
+		var err error
+		month, err = strconv.Atoi(monthStr)
+		if err != nil || month < 1 || month > 12 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month (must be 1-12)"})
+			return
+		}
+	}
+
+	expenses, err := datalayer.GetDataLayer().GetExpenses(year, month)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, expenses)
+}
+
+// GetExpense handles GET /api/expenses/:id
+func GetExpense(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense ID"})
+		return
+	}
+
+	expense, err := datalayer.GetDataLayer().GetExpense(id)
+	if err != nil {
+		expenseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, expense)
+}
+
+// CreateExpense handles POST /api/expenses
+func CreateExpense(c *gin.Context) {
+	var expense db.Expense
+	if err := c.ShouldBindJSON(&expense); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := db.ValidateExpense(expense); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	id, err := datalayer.GetDataLayer().AddExpense(expense)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	expense.Id = id
+	c.JSON(http.StatusCreated, expense)
+}
+
+// UpdateExpense handles PUT /api/expenses/:id
+func UpdateExpense(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense ID"})
+		return
+	}
+
+	var expense db.Expense
+	if err := c.ShouldBindJSON(&expense); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	expense.Id = id
+	if err := db.ValidateExpense(expense); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	if err := datalayer.GetDataLayer().UpdateExpense(expense); err != nil {
+		expenseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, expense)
+}
+
+// DeleteExpense handles DELETE /api/expenses/:id
+func DeleteExpense(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid expense ID"})
+		return
+	}
+
+	if err := datalayer.GetDataLayer().DeleteExpense(id); err != nil {
+		expenseError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"message": "Expense deleted successfully"})
+}
+
+// expenseError answers 404 for an unknown expense and 500 otherwise
+func expenseError(c *gin.Context, err error) {
+	if errors.Is(err, sql.ErrNoRows) {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Expense not found"})
+		return
+	}
+	c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+}
@@ -0,0 +1,68 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"timesheet/internal/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestExpenseHandlers(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	gin.SetMode(gin.TestMode)
+	request := func(handler gin.HandlerFunc, method, url string, params gin.Params, body any) *httptest.ResponseRecorder {
+		var buf bytes.Buffer
+		if body != nil {
+			json.NewEncoder(&buf).Encode(body)
+		}
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest(method, url, &buf)
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = params
+		handler(c)
+		return w
+	}
+
+	w := request(CreateExpense, "POST", "/api/expenses", nil,
+		db.Expense{Date: "2024-03-04", Client_name: "Acme", Description: "Train ticket", Amount: 42.5, Billable: true})
+	if w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d: %s", w.Code, w.Body.String())
+	}
+	var created db.Expense
+	json.Unmarshal(w.Body.Bytes(), &created)
+	if created.Id == 0 {
+		t.Fatal("Expected the created expense to have an ID")
+	}
+
+	w = request(CreateExpense, "POST", "/api/expenses", nil, db.Expense{Date: "2024-03-04", Description: "Taxi", Amount: 10})
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an expense without a client, got %d", w.Code)
+	}
+
+	w = request(GetExpenses, "GET", "/api/expenses?year=2024&month=3", nil, nil)
+	var expenses []db.Expense
+	json.Unmarshal(w.Body.Bytes(), &expenses)
+	if w.Code != http.StatusOK || len(expenses) != 1 {
+		t.Errorf("Expected 1 expense in March, got %d %s", w.Code, w.Body.String())
+	}
+
+	id := gin.Params{{Key: "id", Value: strconv.Itoa(created.Id)}}
+	created.Amount = 50
+	if w = request(UpdateExpense, "PUT", "/api/expenses/"+strconv.Itoa(created.Id), id, created); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 updating, got %d: %s", w.Code, w.Body.String())
+	}
+	if w = request(DeleteExpense, "DELETE", "/api/expenses/"+strconv.Itoa(created.Id), id, nil); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 deleting, got %d", w.Code)
+	}
+	if w = request(GetExpense, "GET", "/api/expenses/"+strconv.Itoa(created.Id), id, nil); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 after deleting, got %d", w.Code)
+	}
+}
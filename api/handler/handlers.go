@@ -1,20 +1,99 @@
 package handler
 
 import (
+	"errors"
+	"fmt"
 	"net/http"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
 	"time"
 	"timesheet/internal/config"
 	"timesheet/internal/datalayer"
 	"timesheet/internal/db"
+	"timesheet/internal/document"
+	printExcel "timesheet/internal/print-excel"
+	printPDF "timesheet/internal/print-pdf"
 
 	"github.com/gin-gonic/gin"
 )
 
-// GetTimesheet handles GET requests for timesheet entries
+// maxTimesheetPageSize caps the limit parameter of GET /api/timesheet
+const maxTimesheetPageSize = 1000
+
+// GetTimesheet handles GET /api/timesheet?from=YYYY-MM-DD&to=YYYY-MM-DD&limit=N&offset=N
+// All parameters are optional: without any it returns every entry. With
+// them it returns a page of the entries from through to, limit defaulting
+// to and capped at maxTimesheetPageSize. The X-Total-Count header has the
+// number of entries in the whole range.
 func GetTimesheet(c *gin.Context) {
 	dl := datalayer.GetDataLayer()
-	entries, err := dl.GetAllTimesheetEntries(0, 0)
+	from, to := c.Query("from"), c.Query("to")
+	limitParam, offsetParam := c.Query("limit"), c.Query("offset")
+	if from == "" && to == "" && limitParam == "" && offsetParam == "" {
+		entries, err := dl.GetAllTimesheetEntries(0, 0)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		c.Header("X-Total-Count", strconv.Itoa(len(entries)))
+		c.JSON(http.StatusOK, entries)
+		return
+	}
+
+	for _, param := range []struct{ name, date string }{{"from", from}, {"to", to}} {
+		if param.date == "" {
+			continue
+		}
+		if _, err := time.Parse("2006-01-02", param.date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid %s parameter (expected YYYY-MM-DD)", param.name)})
+			return
+		}
+	}
+	if from != "" && to != "" && from > to {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "from must not be after to"})
+		return
+	}
+	limit := maxTimesheetPageSize
+	if limitParam != "" {
+		var err error
+		limit, err = strconv.Atoi(limitParam)
+		if err != nil || limit < 1 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid limit parameter (must be a positive number)"})
+			return
+		}
+		limit = min(limit, maxTimesheetPageSize)
+	}
+	offset := 0
+	if offsetParam != "" {
+		var err error
+		offset, err = strconv.Atoi(offsetParam)
+		if err != nil || offset < 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid offset parameter (must be 0 or more)"})
+			return
+		}
+	}
+
+	entries, total, err := dl.GetTimesheetEntriesInRange(from, to, limit, offset)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.Header("X-Total-Count", strconv.Itoa(total))
+	c.JSON(http.StatusOK, entries)
+}
+
+// GetTimesheetByDate handles GET /api/timesheet/date/:date, returning the
+// entries logged on one day, one per client
+func GetTimesheetByDate(c *gin.Context) {
+	date := c.Param("date")
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date (expected YYYY-MM-DD)"})
+		return
+	}
+
+	entries, err := datalayer.GetDataLayer().GetTimesheetEntriesByDate(date)
 	if err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
@@ -29,16 +108,261 @@ func CreateTimesheet(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
-
 	dl := datalayer.GetDataLayer()
+	onDate, err := dl.GetTimesheetEntriesByDate(entry.Date)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	entry, warning, err := db.ApplyDailyLimit(entry, db.OtherBlocksHours(onDate, entry))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err := db.ValidateTimesheetEntry(entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if warning != "" {
+		// The entry is stored clamped; say so without changing the body
+		c.Header("Warning", fmt.Sprintf("299 - %q", warning))
+	}
+
 	if err := dl.AddTimesheetEntry(entry); err != nil {
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
+	// A client nearing or over its hour cap doesn't block the entry
+	if capWarning, err := db.CapWarning(dl, entry); err == nil && capWarning != "" {
+		c.Writer.Header().Add("Warning", fmt.Sprintf("299 - %q", capWarning))
+	}
 
 	c.JSON(http.StatusCreated, entry)
 }
 
+// ValidateTimesheet handles POST /api/timesheet/validate
+// Runs the entry validations on the posted entry without saving it, so a
+// front-end can show every problem before submitting.
+func ValidateTimesheet(c *gin.Context) {
+	var draft db.TimesheetDraft
+	if err := c.ShouldBindJSON(&draft); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// The other client blocks of the date count towards the day's limit
+	var otherHours float64
+	if _, err := time.Parse("2006-01-02", draft.Date); err == nil {
+		onDate, err := datalayer.GetDataLayer().GetTimesheetEntriesByDate(draft.Date)
+		if err != nil {
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		otherHours = db.OtherBlocksHours(onDate, db.TimesheetEntry{Date: draft.Date, Client_name: draft.Client_name})
+	}
+
+	violations := db.ValidateTimesheetDraft(draft, otherHours)
+	c.JSON(http.StatusOK, gin.H{
+		"valid":      len(violations) == 0,
+		"violations": violations,
+	})
+}
+
+// BulkCreateTimesheet handles POST /api/timesheet/bulk?overwrite=skip|replace|merge|sum
+// Creates many entries at once. Dates that already have an entry are
+// resolved with the overwrite policy; the default skips and reports them.
+func BulkCreateTimesheet(c *gin.Context) {
+	policy, err := db.ParseOverwritePolicy(c.Query("overwrite"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	var entries []db.TimesheetEntry
+	if err := c.ShouldBindJSON(&entries); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	for _, entry := range entries {
+		if _, err := time.Parse("2006-01-02", entry.Date); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date: " + entry.Date})
+			return
+		}
+		// Saving checks each day as a whole, with the blocks already stored
+		if _, _, err := db.ApplyDailyLimit(entry, 0); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		if err := db.ValidateTimesheetEntry(entry); err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+	}
+
+	result, err := db.BulkSaveTimesheetEntries(datalayer.GetDataLayer(), entries, policy)
+	response := gin.H{
+		"overwrite": policy,
+		"created":   nonNil(result.Created),
+		"replaced":  nonNil(result.Replaced),
+		"merged":    nonNil(result.Merged),
+		"summed":    nonNil(result.Summed),
+		"skipped":   nonNil(result.Skipped),
+	}
+	if err != nil {
+		response["error"] = err.Error()
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// FillIdleTimesheet handles POST /api/timesheet/fill-idle?year=&month=&dryRun=&exclude=
+// Records the configured idle hours on every working day of the month (default:
+// the current month) up to yesterday that has nothing logged. Dates listed in
+// exclude (comma-separated) are skipped; with dryRun=true the days that would
+// be filled are returned and nothing is written. Requires idleAutoFill.enabled
+// in the config.
+func FillIdleTimesheet(c *gin.Context) {
+	settings := config.GetIdleAutoFill()
+	if !settings.Enabled {
+		c.JSON(http.StatusForbidden, gin.H{"error": "Idle auto-fill is disabled; enable idleAutoFill in the config"})
+		return
+	}
+
+	now := time.Now()
+	year, month := now.Year(), int(now.Month())
+	if yearParam := c.Query("year"); yearParam != "" {
+		var err error
+		year, err = strconv.Atoi(yearParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year parameter"})
+			return
+		}
+	}
+	if monthParam := c.Query("month"); monthParam != "" {
+		var err error
+		month, err = strconv.Atoi(monthParam)
+		if err != nil || month < 1 || month > 12 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month parameter"})
+			return
+		}
+	}
+
+	dryRun := false
+	if dryRunParam := c.Query("dryRun"); dryRunParam != "" {
+		var err error
+		dryRun, err = strconv.ParseBool(dryRunParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid dryRun parameter (must be true or false)"})
+			return
+		}
+	}
+	excluded := map[string]bool{}
+	if excludeParam := c.Query("exclude"); excludeParam != "" {
+		for _, date := range strings.Split(excludeParam, ",") {
+			date = strings.TrimSpace(date)
+			if _, err := time.Parse("2006-01-02", date); err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("Invalid date %q in exclude (expected YYYY-MM-DD)", date)})
+				return
+			}
+			excluded[date] = true
+		}
+	}
+
+	// Today may still get logged, so only days before it are filled
+	through := now.AddDate(0, 0, -1)
+	dl := datalayer.GetDataLayer()
+	planned, err := db.PlanIdleFillForMonth(dl, year, time.Month(month), config.GetWorkSchedule(), settings.Hours, through)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	planned = slices.DeleteFunc(planned, func(entry db.TimesheetEntry) bool {
+		return excluded[entry.Date]
+	})
+
+	if dryRun {
+		days := []gin.H{}
+		for _, entry := range planned {
+			days = append(days, gin.H{"date": entry.Date, "idle_hours": entry.Idle_hours})
+		}
+		c.JSON(http.StatusOK, gin.H{"dry_run": true, "planned": days})
+		return
+	}
+
+	result, err := db.SaveIdleFill(dl, planned)
+	response := gin.H{
+		"created": nonNil(result.Created),
+		"merged":  nonNil(result.Merged),
+	}
+	if err != nil {
+		response["error"] = err.Error()
+		c.JSON(http.StatusInternalServerError, response)
+		return
+	}
+
+	c.JSON(http.StatusOK, response)
+}
+
+// nonNil turns a nil slice into an empty one so it encodes as [] instead of null
+func nonNil(dates []string) []string {
+	if dates == nil {
+		return []string{}
+	}
+	return dates
+}
+
+// GetTimesheetStats handles GET /api/timesheet/stats?year=
+// Returns days worked, the longest streak of worked workdays, average client
+// hours and the split across clients for a year (default: current year)
+func GetTimesheetStats(c *gin.Context) {
+	year := time.Now().Year()
+	if yearParam := c.Query("year"); yearParam != "" {
+		var err error
+		year, err = strconv.Atoi(yearParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year parameter"})
+			return
+		}
+	}
+
+	dl := datalayer.GetDataLayer()
+	entries, err := dl.GetAllTimesheetEntries(year, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, db.ComputeTimesheetStats(year, entries, config.GetWorkSchedule()))
+}
+
+// GetYears handles GET /api/years?training=true
+// Returns the years that have timesheet entries, sorted, for year pickers.
+// With training=true, years with only training budget entries are included.
+func GetYears(c *gin.Context) {
+	includeTraining := false
+	if trainingParam := c.Query("training"); trainingParam != "" {
+		var err error
+		includeTraining, err = strconv.ParseBool(trainingParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid training parameter"})
+			return
+		}
+	}
+
+	years, err := datalayer.GetDataLayer().GetYearsWithData(includeTraining)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	if years == nil {
+		years = []int{}
+	}
+	c.JSON(http.StatusOK, gin.H{"years": years})
+}
+
 // UpdateTimesheet handles PUT requests to update a timesheet entry
 func UpdateTimesheet(c *gin.Context) {
 	id := c.Param("id")
@@ -52,6 +376,10 @@ func UpdateTimesheet(c *gin.Context) {
 		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
 		return
 	}
+	if err := db.ValidateTimesheetHours(entry); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 
 	updateData := map[string]any{
 		"client_hours":   entry.Client_hours,
@@ -60,9 +388,14 @@ func UpdateTimesheet(c *gin.Context) {
 		"training_hours": entry.Training_hours,
 		"holiday_hours":  entry.Holiday_hours,
 		"sick_hours":     entry.Sick_hours,
+		"note":           entry.Note,
 	}
 	dl := datalayer.GetDataLayer()
 	if err := dl.UpdateTimesheetEntryById(id, updateData); err != nil {
+		if errors.Is(err, db.ErrClientNameRequired) || errors.Is(err, db.ErrDailyLimitExceeded) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
 		return
 	}
@@ -87,16 +420,155 @@ func DeleteTimesheet(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Entry deleted successfully"})
 }
 
-// ExportPDF handles GET requests to export timesheet as PDF
+// DeleteTimesheetMonth handles DELETE /api/timesheet/month?year=YYYY&month=MM
+// Removes every entry of the month and reports how many were deleted. Both
+// parameters are required so a missing one can't wipe the wrong month.
+func DeleteTimesheetMonth(c *gin.Context) {
+	year, err := strconv.Atoi(c.Query("year"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing year parameter"})
+		return
+	}
+	month, err := strconv.Atoi(c.Query("month"))
+	if err != nil || month < 1 || month > 12 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid or missing month parameter"})
+		return
+	}
+
+	deleted, err := datalayer.GetDataLayer().DeleteTimesheetEntriesForMonth(year, time.Month(month))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"deleted": deleted})
+}
+
+// GetTimesheetDocument handles GET /api/timesheet/document?year=YYYY&month=MM
+// Returns the content of the month's timesheet document (every day, the
+// totals and the user's details), as used by the PDF and Excel exports.
+// Defaults to the current month.
+func GetTimesheetDocument(c *gin.Context) {
+	now := time.Now()
+	year, month := now.Year(), int(now.Month())
+	if yearParam := c.Query("year"); yearParam != "" {
+		var err error
+		year, err = strconv.Atoi(yearParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year parameter"})
+			return
+		}
+	}
+	if monthParam := c.Query("month"); monthParam != "" {
+		var err error
+		month, err = strconv.Atoi(monthParam)
+		if err != nil || month < 1 || month > 12 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month parameter"})
+			return
+		}
+	}
+
+	doc, err := document.BuildTimesheet(datalayer.GetDataLayer(), year, time.Month(month))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, doc)
+}
+
+// GetTimesheetSummary handles GET /api/timesheet/summary?year=YYYY&month=MM
+// Returns the month's hours per category, the totals the TUI shows under
+// the timesheet. year and month default to the current month.
+func GetTimesheetSummary(c *gin.Context) {
+	now := time.Now()
+	year, month := now.Year(), int(now.Month())
+	if yearParam := c.Query("year"); yearParam != "" {
+		var err error
+		year, err = strconv.Atoi(yearParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year parameter"})
+			return
+		}
+	}
+	if monthParam := c.Query("month"); monthParam != "" {
+		var err error
+		month, err = strconv.Atoi(monthParam)
+		if err != nil || month < 1 || month > 12 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid month parameter"})
+			return
+		}
+	}
+
+	totals, err := datalayer.GetDataLayer().GetMonthlyTotals(year, time.Month(month))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, struct {
+		Year  int `json:"year"`
+		Month int `json:"month"`
+		db.HourTotals
+	}{year, month, totals})
+}
+
+// ExportPDF handles GET /api/export/pdf?year=YYYY&month=MM, sending the
+// month's timesheet as a PDF download
 func ExportPDF(c *gin.Context) {
-	// TODO: Implement PDF export
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "PDF export not implemented yet"})
+	exportTimesheet(c, "application/pdf", func(doc document.Timesheet) (string, error) {
+		return printPDF.TimesheetToPDF(doc, false)
+	})
 }
 
-// ExportExcel handles GET requests to export timesheet as Excel
+// ExportExcel handles GET /api/export/excel?year=YYYY&month=MM, sending the
+// month's timesheet as an Excel download
 func ExportExcel(c *gin.Context) {
-	// TODO: Implement Excel export
-	c.JSON(http.StatusNotImplemented, gin.H{"error": "Excel export not implemented yet"})
+	exportTimesheet(c, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", printExcel.TimesheetToExcel)
+}
+
+// exportTimesheet builds the timesheet of the requested month, saves it
+// with render like the TUI does and sends the file as an attachment. month
+// is required; year defaults to the current year.
+func exportTimesheet(c *gin.Context, contentType string, render func(document.Timesheet) (string, error)) {
+	year := time.Now().Year()
+	if yearParam := c.Query("year"); yearParam != "" {
+		var err error
+		year, err = strconv.Atoi(yearParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year parameter"})
+			return
+		}
+	}
+	month, err := strconv.Atoi(c.Query("month"))
+	if err != nil || month < 1 || month > 12 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Missing or invalid month parameter (expected 1-12)"})
+		return
+	}
+
+	doc, err := document.BuildTimesheet(datalayer.GetDataLayer(), year, time.Month(month))
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+	path, err := render(doc)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// FileAttachment only guesses the type when none is set
+	c.Header("Content-Type", contentType)
+	c.FileAttachment(path, filepath.Base(path))
+}
+
+// HealthCheck handles GET /health and /api/health. It pings the active data
+// layer and reports 503 when the database (or remote API) is unreachable.
+func HealthCheck(c *gin.Context) {
+	dl := datalayer.GetDataLayer()
+	name := datalayer.Name(dl)
+	if err := dl.Ping(); err != nil {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"status": "error", "db": name, "error": err.Error()})
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"status": "ok", "db": name})
 }
 
 // GetLastClientName handles GET requests for the last client name
@@ -133,6 +605,42 @@ func GetTrainingBudget(c *gin.Context) {
 	c.JSON(http.StatusOK, entries)
 }
 
+// GetTrainingBudgetSummary handles GET /api/training-budget/summary?year=YYYY
+// Returns what the year's training budget entries cost against the
+// configured trainingBudgetTarget. year defaults to the current year;
+// remaining is null when no target is set and negative once it's exceeded.
+func GetTrainingBudgetSummary(c *gin.Context) {
+	year := time.Now().Year()
+	if yearParam := c.Query("year"); yearParam != "" {
+		var err error
+		year, err = strconv.Atoi(yearParam)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year parameter"})
+			return
+		}
+	}
+
+	spent, err := datalayer.GetDataLayer().GetTrainingBudgetSpentForYear(year)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	target := config.GetTrainingBudgetTarget()
+	var remaining *float64
+	if target > 0 {
+		left := target - spent.Spent
+		remaining = &left
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"year":        year,
+		"spent":       spent.Spent,
+		"target":      target,
+		"remaining":   remaining,
+		"entry_count": spent.EntryCount,
+	})
+}
+
 // CreateTrainingBudget handles POST requests to create a new training budget entry
 func CreateTrainingBudget(c *gin.Context) {
 	var entry db.TrainingBudgetEntry
@@ -212,7 +720,7 @@ func GetTrainingHours(c *gin.Context) {
 		return
 	}
 
-	var usedHours int
+	var usedHours float64
 	for _, entry := range entries {
 		usedHours += entry.Training_hours
 	}
@@ -225,7 +733,7 @@ func GetTrainingHours(c *gin.Context) {
 	}
 
 	totalHours := config.TrainingHours.YearlyTarget
-	availableHours := totalHours - usedHours
+	availableHours := float64(totalHours) - usedHours
 
 	// Return all hours information
 	c.JSON(http.StatusOK, gin.H{
@@ -236,6 +744,54 @@ func GetTrainingHours(c *gin.Context) {
 	})
 }
 
+// GetTrainingReconciliation handles GET requests comparing training hours
+// logged on the timesheet with hours recorded in the training budget
+func GetTrainingReconciliation(c *gin.Context) {
+	year := c.Query("year")
+	yearInt := time.Now().Year()
+	if year != "" {
+		var err error
+		yearInt, err = strconv.Atoi(year)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year parameter"})
+			return
+		}
+	}
+
+	dl := datalayer.GetDataLayer()
+	trainingEntries, err := dl.GetTrainingEntriesForYear(yearInt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get training entries"})
+		return
+	}
+	budgetEntries, err := dl.GetTrainingBudgetEntriesForYear(yearInt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get training budget entries"})
+		return
+	}
+
+	report := db.ReconcileTraining(yearInt, trainingEntries, budgetEntries)
+
+	rows := []gin.H{}
+	for _, row := range report.Rows {
+		rows = append(rows, gin.H{
+			"date":           row.Date,
+			"logged_hours":   row.LoggedHours,
+			"budgeted_hours": row.BudgetedHours,
+			"training_names": row.TrainingNames,
+			"status":         row.Status,
+		})
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"year":                 report.Year,
+		"total_logged_hours":   report.TotalLoggedHours,
+		"total_budgeted_hours": report.TotalBudgetedHours,
+		"discrepancies":        len(report.Discrepancies()),
+		"rows":                 rows,
+	})
+}
+
 // GetVacationHours handles GET requests for total vacation hours
 func GetVacationHours(c *gin.Context) {
 	year := c.Query("year")
@@ -271,8 +827,42 @@ func GetVacationHours(c *gin.Context) {
 	})
 }
 
-// GetOverview handles GET requests for overview data (training and vacation days left)
+// getWeekOverview handles GET /api/overview?period=week&date=YYYY-MM-DD
+// Returns the hour totals of the week containing date (default: today). The
+// week begins on the configured weekStart.
+func getWeekOverview(c *gin.Context) {
+	date := time.Now()
+	if dateParam := c.Query("date"); dateParam != "" {
+		var err error
+		date, err = time.ParseInLocation("2006-01-02", dateParam, time.Local)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid date parameter (expected YYYY-MM-DD)"})
+			return
+		}
+	}
+
+	summary, err := db.GetWeekSummary(datalayer.GetDataLayer(), date, config.GetWeekStart(), config.GetWorkSchedule())
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, summary)
+}
+
+// GetOverview handles GET requests for overview data (training and vacation days left).
+// With period=week it returns the hour totals of one week instead.
 func GetOverview(c *gin.Context) {
+	switch c.Query("period") {
+	case "", "year":
+	case "week":
+		getWeekOverview(c)
+		return
+	default:
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid period parameter (must be year or week)"})
+		return
+	}
+
 	year := c.Query("year")
 	var yearInt int
 	var err error
@@ -304,13 +894,13 @@ func GetOverview(c *gin.Context) {
 		return
 	}
 
-	var totalTrainingHours int
+	var totalTrainingHours float64
 	for _, entry := range trainingEntries {
 		totalTrainingHours += entry.Training_hours
 	}
 
-	trainingHoursLeft := cfg.TrainingHours.YearlyTarget - totalTrainingHours
-	trainingDaysLeft := float64(trainingHoursLeft) / 9.0
+	trainingHoursLeft := float64(cfg.TrainingHours.YearlyTarget) - totalTrainingHours
+	trainingDaysLeft := trainingHoursLeft / 9.0
 
 	// Calculate vacation hours using summary (includes carryover)
 	vacationSummary, err := dl.GetVacationSummaryForYear(yearInt)
@@ -319,11 +909,21 @@ func GetOverview(c *gin.Context) {
 		return
 	}
 
-	vacationDaysLeft := float64(vacationSummary.RemainingTotal) / 9.0
+	vacationDaysLeft := vacationSummary.RemainingTotal / 9.0
+
+	// Hours of every category, for the year and per month
+	entries, err := dl.GetAllTimesheetEntries(yearInt, 0)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": "Failed to get timesheet entries"})
+		return
+	}
+	hours := db.SummarizeYearHours(yearInt, entries)
 
 	// Return overview data with carryover breakdown
 	c.JSON(http.StatusOK, gin.H{
-		"year": yearInt,
+		"year":   yearInt,
+		"hours":  hours.Totals,
+		"months": hours.Months,
 		"training": gin.H{
 			"total_hours":     cfg.TrainingHours.YearlyTarget,
 			"used_hours":      totalTrainingHours,
@@ -407,6 +1007,31 @@ func DeleteVacationCarryover(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{"message": "Carryover deleted successfully"})
 }
 
+// RolloverVacation handles POST /api/vacation/rollover?year=YYYY. It
+// carries the year's remaining vacation balance into the next year,
+// replacing any carryover recorded there before.
+func RolloverVacation(c *gin.Context) {
+	year := c.Query("year")
+	if year == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Year parameter is required"})
+		return
+	}
+
+	yearInt, err := strconv.Atoi(year)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid year parameter"})
+		return
+	}
+
+	rollover, err := db.RolloverVacation(datalayer.GetDataLayer(), yearInt)
+	if err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, rollover)
+}
+
 // GetVacationSummary handles GET /api/vacation-summary?year=YYYY
 func GetVacationSummary(c *gin.Context) {
 	year := c.Query("year")
@@ -2,14 +2,19 @@ package handler
 
 import (
 	"bytes"
+	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
 	"path/filepath"
+	"reflect"
 	"strconv"
+	"strings"
 	"testing"
+	"time"
 	"timesheet/internal/config"
 	"timesheet/internal/db"
+	"timesheet/internal/document"
 
 	"github.com/gin-gonic/gin"
 )
@@ -45,6 +50,18 @@ func teardownHandlerTest(t *testing.T, dbPath string) {
 	config.SetConfigPathOverride("")
 }
 
+// firstEntry returns the first of the entries a GetTimesheetEntriesByDate
+// call found, or sql.ErrNoRows when there are none
+func firstEntry(entries []db.TimesheetEntry, err error) (db.TimesheetEntry, error) {
+	if err != nil {
+		return db.TimesheetEntry{}, err
+	}
+	if len(entries) == 0 {
+		return db.TimesheetEntry{}, sql.ErrNoRows
+	}
+	return entries[0], nil
+}
+
 func TestGetTimesheet(t *testing.T) {
 	dbPath := setupHandlerTest(t)
 	defer teardownHandlerTest(t, dbPath)
@@ -88,6 +105,113 @@ func TestGetTimesheet(t *testing.T) {
 	}
 }
 
+func TestGetTimesheetRange(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	for _, date := range []string{"2024-01-10", "2024-01-15", "2024-01-20", "2024-01-25", "2024-02-01"} {
+		db.AddTimesheetEntry(db.TimesheetEntry{Date: date, Client_name: "Client A", Client_hours: 8})
+	}
+
+	gin.SetMode(gin.TestMode)
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/timesheet?"+query, nil)
+		GetTimesheet(c)
+		return w
+	}
+	dates := func(w *httptest.ResponseRecorder) []string {
+		t.Helper()
+		var entries []db.TimesheetEntry
+		if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		var dates []string
+		for _, entry := range entries {
+			dates = append(dates, entry.Date)
+		}
+		return dates
+	}
+
+	// No parameters returns everything, as before
+	w := get("")
+	if got := dates(w); len(got) != 5 || w.Header().Get("X-Total-Count") != "5" {
+		t.Errorf("Expected all 5 entries, got %v (total %s)", got, w.Header().Get("X-Total-Count"))
+	}
+
+	// The range is inclusive; the total counts the whole range
+	w = get("from=2024-01-15&to=2024-01-25&limit=2")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if got := dates(w); len(got) != 2 || got[0] != "2024-01-15" || got[1] != "2024-01-20" {
+		t.Errorf("Expected the first page to be 2024-01-15 and 2024-01-20, got %v", got)
+	}
+	if total := w.Header().Get("X-Total-Count"); total != "3" {
+		t.Errorf("Expected X-Total-Count 3, got %q", total)
+	}
+
+	w = get("from=2024-01-15&to=2024-01-25&limit=2&offset=2")
+	if got := dates(w); len(got) != 1 || got[0] != "2024-01-25" {
+		t.Errorf("Expected the second page to be 2024-01-25, got %v", got)
+	}
+
+	w = get("offset=4")
+	if got := dates(w); len(got) != 1 || got[0] != "2024-02-01" {
+		t.Errorf("Expected an offset without a limit to skip 4 entries, got %v", got)
+	}
+
+	for _, query := range []string{"from=2024-02-01&to=2024-01-01", "from=2024-13-01", "limit=0", "limit=x", "offset=-1"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected status 400, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestGetTimesheetByDate(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-15", Client_name: "Client A", Client_hours: 7.5})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-15", Client_name: "Client B", Client_hours: 1})
+
+	run := func(date string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		gin.SetMode(gin.TestMode)
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/timesheet/date/"+date, nil)
+		c.Params = gin.Params{gin.Param{Key: "date", Value: date}}
+		GetTimesheetByDate(c)
+		return w
+	}
+	entriesOf := func(w *httptest.ResponseRecorder) []db.TimesheetEntry {
+		t.Helper()
+		var entries []db.TimesheetEntry
+		if err := json.Unmarshal(w.Body.Bytes(), &entries); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		return entries
+	}
+
+	w := run("2024-01-15")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	entries := entriesOf(w)
+	if len(entries) != 2 || entries[0].Client_name != "Client A" || entries[0].Client_hours != 7.5 || entries[1].Client_name != "Client B" {
+		t.Errorf("Unexpected entries: %+v", entries)
+	}
+
+	w = run("2024-01-16")
+	if w.Code != http.StatusOK || w.Body.String() != "[]" {
+		t.Errorf("Expected status 200 and no entries for a day without an entry, got %d %s", w.Code, w.Body.String())
+	}
+	if w := run("15-01-2024"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a malformed date, got %d", w.Code)
+	}
+}
+
 func TestCreateTimesheet(t *testing.T) {
 	dbPath := setupHandlerTest(t)
 	defer teardownHandlerTest(t, dbPath)
@@ -127,6 +251,220 @@ func TestCreateTimesheet(t *testing.T) {
 	}
 }
 
+func TestCreateTimesheetRequiresClientName(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	gin.SetMode(gin.TestMode)
+	post := func(entry db.TimesheetEntry) int {
+		body, _ := json.Marshal(entry)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/api/timesheet", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		CreateTimesheet(c)
+		return w.Code
+	}
+
+	if code := post(db.TimesheetEntry{Date: "2024-01-15", Client_name: " ", Client_hours: 8}); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for client hours without a client, got %d", code)
+	}
+	if _, err := firstEntry(db.GetTimesheetEntriesByDate("2024-01-15")); err == nil {
+		t.Error("Expected no entry to be stored")
+	}
+
+	// Hours other than client hours don't need a client
+	if code := post(db.TimesheetEntry{Date: "2024-01-16", Vacation_hours: 8}); code != http.StatusCreated {
+		t.Errorf("Expected status 201 for a vacation day, got %d", code)
+	}
+
+	// Nor can PUT add client hours to an entry without a client
+	entry, err := firstEntry(db.GetTimesheetEntriesByDate("2024-01-16"))
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	body, _ := json.Marshal(db.TimesheetEntry{Client_hours: 4, Vacation_hours: 4})
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("PUT", "/api/timesheet/"+strconv.Itoa(entry.Id), bytes.NewBuffer(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+	c.Params = gin.Params{{Key: "id", Value: strconv.Itoa(entry.Id)}}
+	UpdateTimesheet(c)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for PUT adding client hours without a client, got %d", w.Code)
+	}
+}
+
+func TestCreateTimesheetOverLimit(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	gin.SetMode(gin.TestMode)
+	post := func(entry db.TimesheetEntry) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(entry)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/api/timesheet", bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		CreateTimesheet(c)
+		return w
+	}
+
+	// Rejected by default
+	if w := post(db.TimesheetEntry{Date: "2024-01-15", Client_name: "Acme", Client_hours: 20, Training_hours: 8}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a 28-hour day, got %d", w.Code)
+	}
+	// Another client's block on the day counts towards the limit
+	if w := post(db.TimesheetEntry{Date: "2024-01-17", Client_name: "Acme", Client_hours: 16}); w.Code != http.StatusCreated {
+		t.Fatalf("Expected status 201, got %d", w.Code)
+	}
+	if w := post(db.TimesheetEntry{Date: "2024-01-17", Client_name: "Globex", Client_hours: 10}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a 26-hour day over two clients, got %d", w.Code)
+	}
+
+	if err := config.SaveConfig(config.Config{OverLimitBehavior: config.OverLimitClamp}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	w := post(db.TimesheetEntry{Date: "2024-01-15", Client_name: "Acme", Client_hours: 20, Training_hours: 8})
+	if w.Code != http.StatusCreated || w.Header().Get("Warning") == "" {
+		t.Fatalf("Expected a clamped entry with a warning, got %d %v", w.Code, w.Header())
+	}
+	stored, err := firstEntry(db.GetTimesheetEntriesByDate("2024-01-15"))
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	// Training is cut before client hours
+	if stored.Client_hours != 20 || stored.Training_hours != 4 {
+		t.Errorf("Expected 20 client and 4 training hours, got %+v", stored)
+	}
+
+	if err := config.SaveConfig(config.Config{OverLimitBehavior: config.OverLimitAllow}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if w := post(db.TimesheetEntry{Date: "2024-01-16", Client_name: "Acme", Client_hours: 30}); w.Code != http.StatusCreated {
+		t.Errorf("Expected status 201 with allow, got %d", w.Code)
+	}
+}
+
+func TestUpdateTimesheetOverLimit(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	gin.SetMode(gin.TestMode)
+	if err := db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-15", Client_name: "Acme", Client_hours: 8}); err != nil {
+		t.Fatalf("Failed to add entry: %v", err)
+	}
+	if err := db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-15", Client_name: "Globex", Client_hours: 4}); err != nil {
+		t.Fatalf("Failed to add entry: %v", err)
+	}
+	entries, err := db.GetTimesheetEntriesByDate("2024-01-15")
+	if err != nil {
+		t.Fatalf("Failed to get entries: %v", err)
+	}
+	acme, _ := db.FindClientEntry(entries, "Acme")
+
+	put := func(entry db.TimesheetEntry) *httptest.ResponseRecorder {
+		body, _ := json.Marshal(entry)
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("PUT", "/api/timesheet/"+strconv.Itoa(acme.Id), bytes.NewBuffer(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		c.Params = gin.Params{{Key: "id", Value: strconv.Itoa(acme.Id)}}
+		UpdateTimesheet(c)
+		return w
+	}
+	stored := func() float64 {
+		entries, err := db.GetTimesheetEntriesByDate("2024-01-15")
+		if err != nil {
+			t.Fatalf("Failed to get entries: %v", err)
+		}
+		entry, _ := db.FindClientEntry(entries, "Acme")
+		return entry.Client_hours
+	}
+
+	// Rejected by default
+	if w := put(db.TimesheetEntry{Client_hours: 30}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a 30-hour block, got %d", w.Code)
+	}
+	// Globex's block on the day counts towards the limit
+	if w := put(db.TimesheetEntry{Client_hours: 22}); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for a 26-hour day over two clients, got %d", w.Code)
+	}
+	if hours := stored(); hours != 8 {
+		t.Errorf("Expected the rejected updates to leave 8 hours, got %g", hours)
+	}
+
+	if err := config.SaveConfig(config.Config{OverLimitBehavior: config.OverLimitClamp}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if w := put(db.TimesheetEntry{Client_hours: 30}); w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 with clamp, got %d", w.Code)
+	}
+	if hours := stored(); hours != 20 {
+		t.Errorf("Expected the update to be clamped to 20 hours, got %g", hours)
+	}
+
+	if err := config.SaveConfig(config.Config{OverLimitBehavior: config.OverLimitAllow}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if w := put(db.TimesheetEntry{Client_hours: 30}); w.Code != http.StatusOK {
+		t.Errorf("Expected status 200 with allow, got %d", w.Code)
+	}
+	if hours := stored(); hours != 30 {
+		t.Errorf("Expected 30 hours with allow, got %g", hours)
+	}
+}
+
+func TestValidateTimesheet(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	gin.SetMode(gin.TestMode)
+	validate := func(body string) (int, map[string]any) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/api/timesheet/validate", bytes.NewBufferString(body))
+		c.Request.Header.Set("Content-Type", "application/json")
+		ValidateTimesheet(c)
+		var response map[string]any
+		json.Unmarshal(w.Body.Bytes(), &response)
+		return w.Code, response
+	}
+
+	code, response := validate(`{"Date": "2024-01-15", "Client_name": "Acme", "Client_hours": 8}`)
+	if code != http.StatusOK || response["valid"] != true {
+		t.Errorf("Expected a valid entry, got %d %v", code, response)
+	}
+
+	code, response = validate(`{"Date": "2024-01-15", "Client_hours": 20.25, "Training_hours": 4}`)
+	if code != http.StatusOK || response["valid"] != false {
+		t.Fatalf("Expected an invalid entry, got %d %v", code, response)
+	}
+	fields := map[string]bool{}
+	for _, v := range response["violations"].([]any) {
+		fields[v.(map[string]any)["field"].(string)] = true
+	}
+	for _, field := range []string{"Client_hours", "Total_hours", "Client_name"} {
+		if !fields[field] {
+			t.Errorf("Expected a violation for %s, got %v", field, response["violations"])
+		}
+	}
+
+	// Nothing is written
+	if _, err := firstEntry(db.GetTimesheetEntriesByDate("2024-01-15")); err == nil {
+		t.Error("Expected validation not to store an entry")
+	}
+
+	// The day's other client blocks count towards the limit
+	if err := db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-16", Client_name: "Acme", Client_hours: 16}); err != nil {
+		t.Fatalf("Failed to add entry: %v", err)
+	}
+	code, response = validate(`{"Date": "2024-01-16", "Client_name": "Globex", "Client_hours": 10}`)
+	if code != http.StatusOK || response["valid"] != false {
+		t.Errorf("Expected a 26-hour day over two clients to be invalid, got %d %v", code, response)
+	}
+}
+
 func TestUpdateTimesheet(t *testing.T) {
 	dbPath := setupHandlerTest(t)
 	defer teardownHandlerTest(t, dbPath)
@@ -145,7 +483,7 @@ func TestUpdateTimesheet(t *testing.T) {
 	db.AddTimesheetEntry(entry)
 
 	// Get entry to get ID
-	result, _ := db.GetTimesheetEntryByDate("2024-01-15")
+	result, _ := firstEntry(db.GetTimesheetEntriesByDate("2024-01-15"))
 	entry.Id = result.Id
 	entry.Client_hours = 6
 	entry.Client_name = result.Client_name // Keep original client name
@@ -167,6 +505,41 @@ func TestUpdateTimesheet(t *testing.T) {
 	}
 }
 
+func TestUpdateTimesheetNote(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-16", Client_name: "-", Idle_hours: 8})
+	result, _ := firstEntry(db.GetTimesheetEntriesByDate("2024-01-16"))
+
+	body := `{"Date": "2024-01-16", "Client_name": "-", "Idle_hours": 8, "Note": "Waiting for the client's go-ahead"}`
+	idStr := strconv.Itoa(result.Id)
+	req := httptest.NewRequest("PUT", "/api/timesheet/"+idStr, strings.NewReader(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{gin.Param{Key: "id", Value: idStr}}
+
+	UpdateTimesheet(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	updated, _ := firstEntry(db.GetTimesheetEntriesByDate("2024-01-16"))
+	if updated.Note != "Waiting for the client's go-ahead" {
+		t.Errorf("Expected the note to be saved, got %q", updated.Note)
+	}
+
+	// The note is part of the entries the API returns
+	encoded, _ := json.Marshal(updated)
+	if !strings.Contains(string(encoded), `"Note":"Waiting for the client's go-ahead"`) {
+		t.Errorf("Expected the note in the JSON, got %s", encoded)
+	}
+}
+
 func TestDeleteTimesheet(t *testing.T) {
 	dbPath := setupHandlerTest(t)
 	defer teardownHandlerTest(t, dbPath)
@@ -199,12 +572,128 @@ func TestDeleteTimesheet(t *testing.T) {
 	}
 
 	// Verify deletion
-	_, err := db.GetTimesheetEntryByDate("2024-01-15")
+	_, err := firstEntry(db.GetTimesheetEntriesByDate("2024-01-15"))
 	if err == nil {
 		t.Error("Entry should be deleted")
 	}
 }
 
+func TestDeleteTimesheetMonth(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-15", Client_name: "Client A", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-16", Client_name: "Client A", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-02-01", Client_name: "Client A", Client_hours: 8})
+
+	gin.SetMode(gin.TestMode)
+	deleteMonth := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("DELETE", "/api/timesheet/month?"+query, nil)
+		DeleteTimesheetMonth(c)
+		return w
+	}
+
+	// Both parameters are required
+	for _, query := range []string{"", "year=2024", "month=1", "year=2024&month=13"} {
+		if w := deleteMonth(query); w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected status 400, got %d", query, w.Code)
+		}
+	}
+
+	w := deleteMonth("year=2024&month=1")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var result map[string]int
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if result["deleted"] != 2 {
+		t.Errorf("Expected 2 deleted, got %d", result["deleted"])
+	}
+	if _, err := firstEntry(db.GetTimesheetEntriesByDate("2024-02-01")); err != nil {
+		t.Errorf("Expected February to be kept: %v", err)
+	}
+}
+
+func TestRolloverVacation(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	// 20 hour target, nothing carried in, 12 used: 8 remaining
+	db.SetVacationCarryover(db.VacationCarryover{Year: 2024, SourceYear: 2023})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-05-06", Client_name: "Vacation", Vacation_hours: 12})
+
+	gin.SetMode(gin.TestMode)
+	rollover := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/api/vacation/rollover?"+query, nil)
+		RolloverVacation(c)
+		return w
+	}
+
+	for _, query := range []string{"", "year=last"} {
+		if w := rollover(query); w.Code != http.StatusBadRequest {
+			t.Errorf("%q: expected status 400, got %d", query, w.Code)
+		}
+	}
+
+	// Running twice leaves a single 8 hour carryover
+	for i := 0; i < 2; i++ {
+		w := rollover("year=2024")
+		if w.Code != http.StatusOK {
+			t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+		}
+		var result db.VacationRollover
+		if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		if result.ToYear != 2025 || result.CarryoverHours != 8 {
+			t.Errorf("Expected 8 hours carried into 2025, got %+v", result)
+		}
+	}
+	carryover, err := db.GetVacationCarryoverForYear(2025)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if carryover.CarryoverHours != 8 || carryover.SourceYear != 2024 {
+		t.Errorf("Expected 8 hours from 2024, got %+v", carryover)
+	}
+}
+
+func TestHealthCheck(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	gin.SetMode(gin.TestMode)
+	check := func() (int, map[string]string) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/health", nil)
+		HealthCheck(c)
+		var body map[string]string
+		if err := json.Unmarshal(w.Body.Bytes(), &body); err != nil {
+			t.Fatalf("Failed to unmarshal response: %v", err)
+		}
+		return w.Code, body
+	}
+
+	code, body := check()
+	if code != http.StatusOK || body["status"] != "ok" || body["db"] != "sqlite" {
+		t.Errorf("Expected 200 ok from sqlite, got %d %v", code, body)
+	}
+
+	// A closed database fails the ping
+	db.Close()
+	code, body = check()
+	if code != http.StatusServiceUnavailable || body["status"] != "error" || body["error"] == "" {
+		t.Errorf("Expected 503 with an error, got %d %v", code, body)
+	}
+}
+
 func TestGetLastClientName(t *testing.T) {
 	dbPath := setupHandlerTest(t)
 	defer teardownHandlerTest(t, dbPath)
@@ -548,34 +1037,626 @@ func TestGetOverview(t *testing.T) {
 			t.Errorf("year is not a number: %v", result["year"])
 		}
 	}
+
+	// Every hour category is totalled for the year and per month
+	var overview struct {
+		Training struct {
+			UsedHours float64 `json:"used_hours"`
+		} `json:"training"`
+		Hours  db.HourTotals   `json:"hours"`
+		Months []db.MonthHours `json:"months"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &overview); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if overview.Training.UsedHours != 4 {
+		t.Errorf("Expected 4 used training hours, got %g", overview.Training.UsedHours)
+	}
+	if overview.Hours.TrainingHours != 4 || overview.Hours.VacationHours != 8 || overview.Hours.TotalHours != 12 {
+		t.Errorf("Unexpected year totals %+v", overview.Hours)
+	}
+	if len(overview.Months) != 12 || overview.Months[1].VacationHours != 8 || overview.Months[0].TrainingHours != 4 {
+		t.Errorf("Unexpected month totals %+v", overview.Months)
+	}
+}
+
+func TestGetOverviewWeek(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-03-10", Client_name: "Acme", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-03-11", Client_name: "Acme", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-03-13", Client_name: "Acme", Client_hours: 6, Sick_hours: 2})
+
+	gin.SetMode(gin.TestMode)
+	getWeek := func(url string) (int, db.WeekSummary) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", url, nil)
+		GetOverview(c)
+
+		var summary db.WeekSummary
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+		}
+		return w.Code, summary
+	}
+
+	code, summary := getWeek("/api/overview?period=week&date=2024-03-13")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+	if summary.Start != "2024-03-11" || summary.ISOWeek != 11 || summary.TotalHours != 16 || summary.SickHours != 2 {
+		t.Errorf("Unexpected week summary: %+v", summary)
+	}
+
+	// Weeks starting on Sunday include the 10th
+	cfg, _ := config.GetConfig()
+	cfg.WeekStart = "sunday"
+	if err := config.SaveConfig(cfg); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	_, summary = getWeek("/api/overview?period=week&date=2024-03-13")
+	if summary.Start != "2024-03-10" || summary.TotalHours != 24 {
+		t.Errorf("Expected the Sunday week from 2024-03-10 with 24 hours, got %s with %g", summary.Start, summary.TotalHours)
+	}
+
+	if code, _ := getWeek("/api/overview?period=week&date=13-03-2024"); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid date, got %d", code)
+	}
+	if code, _ := getWeek("/api/overview?period=fortnight"); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid period, got %d", code)
+	}
+}
+
+func TestGetTimesheetDocument(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-04-02", Client_name: "Acme Corp", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-04-03", Client_name: "Acme Corp", Sick_hours: 8})
+
+	gin.SetMode(gin.TestMode)
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/timesheet/document?"+query, nil)
+		GetTimesheetDocument(c)
+		return w
+	}
+
+	w := get("year=2024&month=4")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var doc document.Timesheet
+	if err := json.Unmarshal(w.Body.Bytes(), &doc); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if len(doc.Days) != 30 {
+		t.Errorf("Expected 30 days, got %d", len(doc.Days))
+	}
+	if doc.Totals.Client != 8 || doc.Totals.Sick != 8 || doc.Totals.Total != 16 {
+		t.Errorf("Unexpected totals %+v", doc.Totals)
+	}
+
+	if w := get("year=2024&month=13"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid month, got %d", w.Code)
+	}
+}
+
+func TestGetTimesheetSummary(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-04-02", Client_name: "Acme Corp", Client_hours: 6, Training_hours: 2})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-04-03", Client_name: "Acme Corp", Sick_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-05-01", Client_name: "Acme Corp", Client_hours: 8})
+
+	gin.SetMode(gin.TestMode)
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/timesheet/summary?"+query, nil)
+		GetTimesheetSummary(c)
+		return w
+	}
+
+	w := get("year=2024&month=4")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var summary map[string]float64
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	want := map[string]float64{
+		"year": 2024, "month": 4, "client_hours": 6, "training_hours": 2, "vacation_hours": 0,
+		"idle_hours": 0, "holiday_hours": 0, "sick_hours": 8, "total_hours": 16,
+	}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("Unexpected summary %v, want %v", summary, want)
+	}
+
+	// Without parameters it totals the current month
+	w = get("")
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if now := time.Now(); summary["year"] != float64(now.Year()) || summary["month"] != float64(now.Month()) {
+		t.Errorf("Expected the current month, got %v", summary)
+	}
+
+	if w := get("month=0"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid month, got %d", w.Code)
+	}
+}
+
+func TestGetTrainingBudgetSummary(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	db.AddTrainingBudgetEntry(db.TrainingBudgetEntry{Date: "2024-03-01", Training_name: "Go Workshop", Hours: 8, Cost_without_vat: 1500})
+	db.AddTrainingBudgetEntry(db.TrainingBudgetEntry{Date: "2024-09-12", Training_name: "API Design", Hours: 4, Cost_without_vat: 600})
+	db.AddTrainingBudgetEntry(db.TrainingBudgetEntry{Date: "2023-11-20", Training_name: "Old Course", Hours: 8, Cost_without_vat: 800})
+
+	gin.SetMode(gin.TestMode)
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/training-budget/summary?"+query, nil)
+		GetTrainingBudgetSummary(c)
+		return w
+	}
+
+	// Without a target there's nothing to count down from
+	w := get("year=2024")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+	var summary map[string]any
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	want := map[string]any{"year": 2024.0, "spent": 2100.0, "target": 0.0, "remaining": nil, "entry_count": 2.0}
+	if !reflect.DeepEqual(summary, want) {
+		t.Errorf("Unexpected summary %v, want %v", summary, want)
+	}
+
+	if err := config.SaveConfig(config.Config{TrainingBudgetTarget: 2500}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	w = get("year=2024")
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if summary["target"] != 2500.0 || summary["remaining"] != 400.0 {
+		t.Errorf("Expected 400 of 2500 remaining, got %v", summary)
+	}
+
+	// Without a year it sums the current year
+	w = get("")
+	if err := json.Unmarshal(w.Body.Bytes(), &summary); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if summary["year"] != float64(time.Now().Year()) {
+		t.Errorf("Expected the current year, got %v", summary)
+	}
+
+	if w := get("year=abc"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid year, got %d", w.Code)
+	}
 }
 
 func TestExportPDF(t *testing.T) {
-	req := httptest.NewRequest("GET", "/api/export/pdf", nil)
+	testExport(t, ExportPDF, "application/pdf", ".pdf")
+}
+
+func TestExportExcel(t *testing.T) {
+	testExport(t, ExportExcel, "application/vnd.openxmlformats-officedocument.spreadsheetml.sheet", ".xlsx")
+}
+
+// testExport checks that an export handler sends the month's document as an
+// attachment and rejects a missing or invalid month
+func testExport(t *testing.T, handler gin.HandlerFunc, contentType, extension string) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+	t.Setenv("TIMESHEETZ_EXPORT_DIR", t.TempDir())
+
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-03-04", Client_name: "Acme Corp", Client_hours: 8})
+
+	get := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		gin.SetMode(gin.TestMode)
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", "/api/export?"+query, nil)
+		handler(c)
+		return w
+	}
+
+	w := get("year=2024&month=3")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if got := w.Header().Get("Content-Type"); got != contentType {
+		t.Errorf("Expected Content-Type %s, got %s", contentType, got)
+	}
+	disposition := w.Header().Get("Content-Disposition")
+	if !strings.HasPrefix(disposition, "attachment") || !strings.Contains(disposition, extension) {
+		t.Errorf("Expected a %s attachment, got Content-Disposition %q", extension, disposition)
+	}
+	if w.Body.Len() == 0 {
+		t.Error("Expected the document in the body")
+	}
+
+	for _, query := range []string{"year=2024", "year=2024&month=13", "year=abc&month=3"} {
+		if w := get(query); w.Code != http.StatusBadRequest {
+			t.Errorf("Expected status 400 for %q, got %d", query, w.Code)
+		}
+	}
+}
+
+func TestGetMetrics(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	db.AddClient(db.Client{Name: "Acme Corp", IsActive: true})
+	db.AddClient(db.Client{Name: "Old Corp", IsActive: false})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-15", Client_name: "Acme Corp", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-16", Client_name: "Acme Corp", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-17", Client_name: "Acme Corp", Client_hours: 8})
+
+	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/metrics", nil)
+
+	GetMetrics(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	if ct := w.Header().Get("Content-Type"); !strings.HasPrefix(ct, "text/plain") {
+		t.Errorf("Expected text/plain content type, got %s", ct)
+	}
+
+	body := w.Body.String()
+	for _, want := range []string{
+		"# TYPE timesheetz_timesheet_entries gauge\n",
+		"timesheetz_timesheet_entries 3\n",
+		"timesheetz_clients 2\n",
+		"timesheetz_active_clients 1\n",
+		"# TYPE timesheetz_sync_errors_total counter\n",
+		"timesheetz_sync_last_duration_seconds ",
+	} {
+		if !strings.Contains(body, want) {
+			t.Errorf("Expected metrics output to contain %q, got:\n%s", want, body)
+		}
+	}
+}
+
+func TestFillIdleTimesheet(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-06-03", Client_name: "Acme", Client_hours: 8})
 
 	gin.SetMode(gin.TestMode)
+
+	// Disabled unless the config opts in
+	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	c.Request = req
+	c.Request = httptest.NewRequest("POST", "/api/timesheet/fill-idle?year=2024&month=6", nil)
+	FillIdleTimesheet(c)
+	if w.Code != http.StatusForbidden {
+		t.Fatalf("Expected status 403 while disabled, got %d", w.Code)
+	}
+
+	cfg, _ := config.GetConfig()
+	cfg.IdleAutoFill = config.IdleAutoFill{Enabled: true, Hours: 4}
+	if err := config.SaveConfig(cfg); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
 
-	ExportPDF(c)
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/timesheet/fill-idle?year=2024&month=6", nil)
+	FillIdleTimesheet(c)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	// The default schedule has 16 working days in June 2024, one already logged
+	if created := result["created"].([]interface{}); len(created) != 15 {
+		t.Errorf("Expected 15 created, got %d", len(created))
+	}
+	entry, _ := firstEntry(db.GetTimesheetEntriesByDate("2024-06-04"))
+	if entry.Idle_hours != 4 {
+		t.Errorf("Expected 4 idle hours on 2024-06-04, got %g", entry.Idle_hours)
+	}
+	entry, _ = firstEntry(db.GetTimesheetEntriesByDate("2024-06-03"))
+	if entry.Idle_hours != 0 {
+		t.Errorf("Expected the logged day to be left alone, got %g idle hours", entry.Idle_hours)
+	}
 
-	if w.Code != http.StatusNotImplemented {
-		t.Errorf("Expected status 501, got %d", w.Code)
+	// Invalid month
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/timesheet/fill-idle?month=13", nil)
+	FillIdleTimesheet(c)
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for month=13, got %d", w.Code)
 	}
 }
 
-func TestExportExcel(t *testing.T) {
-	req := httptest.NewRequest("GET", "/api/export/excel", nil)
+func TestFillIdleTimesheetDryRun(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	cfg, _ := config.GetConfig()
+	cfg.IdleAutoFill = config.IdleAutoFill{Enabled: true, Hours: 4}
+	if err := config.SaveConfig(cfg); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	gin.SetMode(gin.TestMode)
+	fillIdle := func(query string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("POST", "/api/timesheet/fill-idle?year=2024&month=6&"+query, nil)
+		FillIdleTimesheet(c)
+		return w
+	}
+
+	w := fillIdle("dryRun=true&exclude=2024-06-03,2024-06-04")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var preview struct {
+		DryRun  bool `json:"dry_run"`
+		Planned []struct {
+			Date      string `json:"date"`
+			IdleHours int    `json:"idle_hours"`
+		} `json:"planned"`
+	}
+	if err := json.Unmarshal(w.Body.Bytes(), &preview); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	// The default schedule has 16 working days in June 2024, two excluded
+	if !preview.DryRun || len(preview.Planned) != 14 {
+		t.Fatalf("Expected a dry run planning 14 days, got %s", w.Body.String())
+	}
+	if preview.Planned[0].Date != "2024-06-05" || preview.Planned[0].IdleHours != 4 {
+		t.Errorf("Expected 4 idle hours on 2024-06-05 first, got %+v", preview.Planned[0])
+	}
+	if entries, _ := db.GetAllTimesheetEntries(2024, 6); len(entries) != 0 {
+		t.Errorf("Expected a dry run to write nothing, found %d entries", len(entries))
+	}
+
+	w = fillIdle("exclude=2024-06-03")
+	var result map[string][]string
+	json.Unmarshal(w.Body.Bytes(), &result)
+	if w.Code != http.StatusOK || len(result["created"]) != 15 {
+		t.Errorf("Expected 15 days filled, got %d %s", w.Code, w.Body.String())
+	}
+	if _, err := firstEntry(db.GetTimesheetEntriesByDate("2024-06-03")); err == nil {
+		t.Error("Expected the excluded day to stay empty")
+	}
+
+	if w = fillIdle("dryRun=maybe"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for dryRun=maybe, got %d", w.Code)
+	}
+	if w = fillIdle("exclude=June"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid excluded date, got %d", w.Code)
+	}
+}
+
+func TestGetTimesheetStats(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-04-01", Client_name: "Acme", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-04-02", Client_name: "Acme", Client_hours: 6})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-04-03", Client_name: "Beta", Client_hours: 4})
+
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/timesheet/stats?year=2024", nil)
+
+	GetTimesheetStats(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	var stats db.TimesheetStats
+	if err := json.Unmarshal(w.Body.Bytes(), &stats); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if stats.DaysWorked != 3 || stats.TotalClientHours != 18 || stats.AverageClientHours != 6 {
+		t.Errorf("Unexpected totals: %+v", stats)
+	}
+	if stats.LongestStreak != 3 {
+		t.Errorf("Expected a 3 day streak, got %d", stats.LongestStreak)
+	}
+	if len(stats.Clients) != 2 || stats.Clients[0].Name != "Acme" || stats.Clients[0].Hours != 14 {
+		t.Errorf("Unexpected client distribution: %+v", stats.Clients)
+	}
+
+	// Invalid year
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/timesheet/stats?year=abc", nil)
+
+	GetTimesheetStats(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestGetYears(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-04-01", Client_name: "Acme", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2022-12-30", Client_name: "Acme", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-05-01", Client_name: "Acme", Client_hours: 8})
+	db.AddTrainingBudgetEntry(db.TrainingBudgetEntry{Date: "2021-06-01", Training_name: "Go course", Hours: 8})
+
+	gin.SetMode(gin.TestMode)
+	getYears := func(url string) (int, []int) {
+		w := httptest.NewRecorder()
+		c, _ := gin.CreateTestContext(w)
+		c.Request = httptest.NewRequest("GET", url, nil)
+		GetYears(c)
+
+		var response struct {
+			Years []int `json:"years"`
+		}
+		if w.Code == http.StatusOK {
+			if err := json.Unmarshal(w.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to unmarshal response: %v", err)
+			}
+		}
+		return w.Code, response.Years
+	}
+
+	code, years := getYears("/api/years")
+	if code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d", code)
+	}
+	if len(years) != 2 || years[0] != 2022 || years[1] != 2024 {
+		t.Errorf("Expected [2022 2024], got %v", years)
+	}
+
+	_, years = getYears("/api/years?training=true")
+	if len(years) != 3 || years[0] != 2021 {
+		t.Errorf("Expected [2021 2022 2024], got %v", years)
+	}
+
+	if code, _ := getYears("/api/years?training=maybe"); code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for an invalid training parameter, got %d", code)
+	}
+}
+
+func TestGetTrainingReconciliation(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-02-01", Training_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-03-05", Training_hours: 4})
+	db.AddTrainingBudgetEntry(db.TrainingBudgetEntry{Date: "2024-02-01", Training_name: "Go Workshop", Hours: 8, Cost_without_vat: 500})
+	db.AddTrainingBudgetEntry(db.TrainingBudgetEntry{Date: "2024-05-20", Training_name: "Kubernetes", Hours: 16, Cost_without_vat: 1200})
+
+	gin.SetMode(gin.TestMode)
 	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/training-reconciliation?year=2024", nil)
+
+	GetTrainingReconciliation(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+
+	var result map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if result["total_logged_hours"] != float64(12) {
+		t.Errorf("Expected total_logged_hours 12, got %v", result["total_logged_hours"])
+	}
+	if result["total_budgeted_hours"] != float64(24) {
+		t.Errorf("Expected total_budgeted_hours 24, got %v", result["total_budgeted_hours"])
+	}
+	if result["discrepancies"] != float64(2) {
+		t.Errorf("Expected 2 discrepancies, got %v", result["discrepancies"])
+	}
+
+	// Invalid year
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("GET", "/api/training-reconciliation?year=abc", nil)
+
+	GetTrainingReconciliation(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
+	}
+}
+
+func TestBulkCreateTimesheet(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-15", Client_name: "Acme", Client_hours: 6})
+
+	body := `[{"Date":"2024-01-15","Client_name":"Acme","Client_hours":8},{"Date":"2024-01-16","Client_name":"Acme","Client_hours":8}]`
 
 	gin.SetMode(gin.TestMode)
+
+	// Default policy skips the existing date
+	w := httptest.NewRecorder()
 	c, _ := gin.CreateTestContext(w)
-	c.Request = req
+	c.Request = httptest.NewRequest("POST", "/api/timesheet/bulk", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	BulkCreateTimesheet(c)
+
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	var result map[string]interface{}
+	if err := json.Unmarshal(w.Body.Bytes(), &result); err != nil {
+		t.Fatalf("Failed to unmarshal response: %v", err)
+	}
+	if result["overwrite"] != "skip" {
+		t.Errorf("Expected default policy skip, got %v", result["overwrite"])
+	}
+	if skipped := result["skipped"].([]interface{}); len(skipped) != 1 || skipped[0] != "2024-01-15" {
+		t.Errorf("Expected 2024-01-15 skipped, got %v", skipped)
+	}
+	if created := result["created"].([]interface{}); len(created) != 1 {
+		t.Errorf("Expected 1 created, got %v", created)
+	}
+	entry, _ := firstEntry(db.GetTimesheetEntriesByDate("2024-01-15"))
+	if entry.Client_hours != 6 {
+		t.Errorf("Expected existing entry to be kept, got %g hours", entry.Client_hours)
+	}
+
+	// Replace overwrites it
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/timesheet/bulk?overwrite=replace", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
 
-	ExportExcel(c)
+	BulkCreateTimesheet(c)
 
-	if w.Code != http.StatusNotImplemented {
-		t.Errorf("Expected status 501, got %d", w.Code)
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d. Body: %s", w.Code, w.Body.String())
+	}
+	entry, _ = firstEntry(db.GetTimesheetEntriesByDate("2024-01-15"))
+	if entry.Client_name != "Acme" || entry.Client_hours != 8 {
+		t.Errorf("Expected entry to be replaced, got %s/%g", entry.Client_name, entry.Client_hours)
+	}
+
+	// Invalid policy
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest("POST", "/api/timesheet/bulk?overwrite=clobber", bytes.NewBufferString(body))
+	c.Request.Header.Set("Content-Type", "application/json")
+
+	BulkCreateTimesheet(c)
+
+	if w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400, got %d", w.Code)
 	}
 }
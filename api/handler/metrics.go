@@ -0,0 +1,62 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"timesheet/internal/datalayer"
+	"timesheet/internal/sync"
+
+	"github.com/gin-gonic/gin"
+)
+
+// GetMetrics handles GET /metrics
+// Returns counters in the Prometheus text exposition format
+func GetMetrics(c *gin.Context) {
+	dl := datalayer.GetDataLayer()
+
+	entries, err := dl.GetAllTimesheetEntries(0, 0)
+	if err != nil {
+		c.String(http.StatusInternalServerError, "# failed to count timesheet entries: %v\n", err)
+		return
+	}
+
+	clients, err := dl.GetAllClients()
+	if err != nil {
+		c.String(http.StatusInternalServerError, "# failed to count clients: %v\n", err)
+		return
+	}
+
+	activeClients := 0
+	for _, client := range clients {
+		if client.IsActive {
+			activeClients++
+		}
+	}
+
+	syncMetrics := sync.GetMetrics()
+	lastSync := 0.0
+	if !syncMetrics.LastSyncTime.IsZero() {
+		lastSync = float64(syncMetrics.LastSyncTime.Unix())
+	}
+
+	var b strings.Builder
+	writeMetric(&b, "timesheetz_timesheet_entries", "gauge", "Number of timesheet entries.", float64(len(entries)))
+	writeMetric(&b, "timesheetz_clients", "gauge", "Number of clients.", float64(len(clients)))
+	writeMetric(&b, "timesheetz_active_clients", "gauge", "Number of active clients.", float64(activeClients))
+	writeMetric(&b, "timesheetz_sync_total", "counter", "Number of completed sync runs.", float64(syncMetrics.SyncCount))
+	writeMetric(&b, "timesheetz_sync_errors_total", "counter", "Number of table errors across all sync runs.", float64(syncMetrics.ErrorCount))
+	writeMetric(&b, "timesheetz_sync_records_pushed_total", "counter", "Number of records pushed to the remote database.", float64(syncMetrics.RecordsPushed))
+	writeMetric(&b, "timesheetz_sync_records_pulled_total", "counter", "Number of records pulled from the remote database.", float64(syncMetrics.RecordsPulled))
+	writeMetric(&b, "timesheetz_sync_last_duration_seconds", "gauge", "Duration of the last sync run.", syncMetrics.LastDuration.Seconds())
+	writeMetric(&b, "timesheetz_sync_last_timestamp_seconds", "gauge", "Unix time the last sync run finished (0 if never).", lastSync)
+
+	c.Data(http.StatusOK, "text/plain; version=0.0.4; charset=utf-8", []byte(b.String()))
+}
+
+// writeMetric appends a single metric with its HELP and TYPE lines
+func writeMetric(b *strings.Builder, name, kind, help string, value float64) {
+	fmt.Fprintf(b, "# HELP %s %s\n", name, help)
+	fmt.Fprintf(b, "# TYPE %s %s\n", name, kind)
+	fmt.Fprintf(b, "%s %g\n", name, value)
+}
@@ -0,0 +1,76 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"timesheet/internal/config"
+	"timesheet/internal/datalayer"
+
+	"github.com/gin-gonic/gin"
+)
+
+// maxReceiptSize caps the size of an uploaded receipt file
+const maxReceiptSize = 10 << 20 // 10 MB
+
+// unsafeFilenameChars matches characters that are replaced in stored receipt names
+var unsafeFilenameChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+
+// UploadTrainingBudgetReceipt handles POST /api/training-budget/:id/receipt
+// Stores the multipart "receipt" file in the receipts directory and records
+// its path on the training budget entry. A previous receipt is replaced.
+func UploadTrainingBudgetReceipt(c *gin.Context) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Invalid ID parameter"})
+		return
+	}
+
+	dl := datalayer.GetDataLayer()
+	entry, err := dl.GetTrainingBudgetEntry(id)
+	if err != nil {
+		c.JSON(http.StatusNotFound, gin.H{"error": "Training budget entry not found"})
+		return
+	}
+
+	file, err := c.FormFile("receipt")
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "Receipt file is required (multipart field \"receipt\")"})
+		return
+	}
+	if file.Size > maxReceiptSize {
+		c.JSON(http.StatusRequestEntityTooLarge, gin.H{"error": fmt.Sprintf("Receipt exceeds %d MB", maxReceiptSize>>20)})
+		return
+	}
+
+	dir := config.GetReceiptsDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to create receipts directory: %v", err)})
+		return
+	}
+
+	name := unsafeFilenameChars.ReplaceAllString(filepath.Base(file.Filename), "_")
+	path := filepath.Join(dir, fmt.Sprintf("%d-%s", id, name))
+	if err := c.SaveUploadedFile(file, path); err != nil {
+		c.JSON(http.StatusInternalServerError, gin.H{"error": fmt.Sprintf("Failed to store receipt: %v", err)})
+		return
+	}
+
+	previous := entry.Receipt_path
+	entry.Receipt_path = path
+	if err := dl.UpdateTrainingBudgetEntry(entry); err != nil {
+		os.Remove(path)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	// Only clean up files we stored ourselves
+	if previous != "" && previous != path && filepath.Dir(previous) == dir {
+		os.Remove(previous)
+	}
+
+	c.JSON(http.StatusOK, entry)
+}
@@ -0,0 +1,106 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"mime/multipart"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"timesheet/internal/db"
+
+	"github.com/gin-gonic/gin"
+)
+
+// uploadReceipt posts a multipart receipt for the training budget entry id
+func uploadReceipt(t *testing.T, id, filename, content string) *httptest.ResponseRecorder {
+	t.Helper()
+	var body bytes.Buffer
+	writer := multipart.NewWriter(&body)
+	if filename != "" {
+		part, err := writer.CreateFormFile("receipt", filename)
+		if err != nil {
+			t.Fatalf("Failed to create form file: %v", err)
+		}
+		part.Write([]byte(content))
+	}
+	writer.Close()
+
+	req := httptest.NewRequest("POST", "/api/training-budget/"+id+"/receipt", &body)
+	req.Header.Set("Content-Type", writer.FormDataContentType())
+	w := httptest.NewRecorder()
+
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(w)
+	c.Request = req
+	c.Params = gin.Params{{Key: "id", Value: id}}
+
+	UploadTrainingBudgetReceipt(c)
+	return w
+}
+
+func TestUploadTrainingBudgetReceipt(t *testing.T) {
+	dbPath := setupHandlerTest(t)
+	defer teardownHandlerTest(t, dbPath)
+
+	dataDir := t.TempDir()
+	t.Setenv("TIMESHEETZ_DB_PATH", filepath.Join(dataDir, "timesheet.db"))
+
+	if err := db.AddTrainingBudgetEntry(db.TrainingBudgetEntry{
+		Date:             "2024-01-15",
+		Training_name:    "Training A",
+		Hours:            8,
+		Cost_without_vat: 100.0,
+	}); err != nil {
+		t.Fatalf("Failed to add entry: %v", err)
+	}
+	stored, err := db.GetTrainingBudgetEntryByDate("2024-01-15")
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	id := strconv.Itoa(stored.Id)
+
+	w := uploadReceipt(t, id, "my invoice.pdf", "receipt data")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200, got %d: %s", w.Code, w.Body.String())
+	}
+
+	var entry db.TrainingBudgetEntry
+	if err := json.Unmarshal(w.Body.Bytes(), &entry); err != nil {
+		t.Fatalf("Failed to parse response: %v", err)
+	}
+	wantPath := filepath.Join(dataDir, "receipts", id+"-my_invoice.pdf")
+	if entry.Receipt_path != wantPath {
+		t.Errorf("Expected receipt path %s, got %s", wantPath, entry.Receipt_path)
+	}
+	if data, err := os.ReadFile(wantPath); err != nil || string(data) != "receipt data" {
+		t.Errorf("Expected stored receipt content, got %q (err %v)", data, err)
+	}
+
+	stored, _ = db.GetTrainingBudgetEntry(stored.Id)
+	if stored.Receipt_path != wantPath {
+		t.Errorf("Expected receipt path to be persisted, got %q", stored.Receipt_path)
+	}
+
+	// A new upload replaces the previous file
+	w = uploadReceipt(t, id, "second.png", "new receipt")
+	if w.Code != http.StatusOK {
+		t.Fatalf("Expected status 200 on replace, got %d", w.Code)
+	}
+	if _, err := os.Stat(wantPath); !os.IsNotExist(err) {
+		t.Errorf("Expected previous receipt to be removed")
+	}
+
+	if w := uploadReceipt(t, "9999", "x.pdf", "x"); w.Code != http.StatusNotFound {
+		t.Errorf("Expected status 404 for unknown entry, got %d", w.Code)
+	}
+	if w := uploadReceipt(t, id, "", ""); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 without a file, got %d", w.Code)
+	}
+	if w := uploadReceipt(t, "abc", "x.pdf", "x"); w.Code != http.StatusBadRequest {
+		t.Errorf("Expected status 400 for invalid id, got %d", w.Code)
+	}
+}
@@ -0,0 +1,47 @@
+package middleware
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StrictQueryParams returns middleware that rejects requests carrying query
+// parameters not listed in allowed with 400 Bad Request, so a typo such as
+// ?yaer=2024 fails loudly instead of silently falling back to a default.
+//
+// When enabled is false the check only runs for requests that opt in with
+// ?strict=true, keeping existing clients working unchanged.
+func StrictQueryParams(enabled bool, allowed ...string) gin.HandlerFunc {
+	allowedSet := map[string]bool{"strict": true}
+	for _, name := range allowed {
+		allowedSet[name] = true
+	}
+
+	return func(c *gin.Context) {
+		query := c.Request.URL.Query()
+		if !enabled && query.Get("strict") != "true" {
+			c.Next()
+			return
+		}
+
+		var unknown []string
+		for name := range query {
+			if !allowedSet[name] {
+				unknown = append(unknown, name)
+			}
+		}
+
+		if len(unknown) > 0 {
+			sort.Strings(unknown)
+			c.AbortWithStatusJSON(http.StatusBadRequest, gin.H{
+				"error": "Unknown query parameter(s): " + strings.Join(unknown, ", "),
+			})
+			return
+		}
+
+		c.Next()
+	}
+}
@@ -0,0 +1,43 @@
+package middleware
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+)
+
+func newStrictRouter(enabled bool) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.GET("/earnings", StrictQueryParams(enabled, "year", "month"), func(c *gin.Context) {
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func TestStrictQueryParams(t *testing.T) {
+	tests := []struct {
+		name     string
+		enabled  bool
+		url      string
+		expected int
+	}{
+		{"lenient ignores typo", false, "/earnings?yaer=2024", http.StatusOK},
+		{"lenient opt-in rejects typo", false, "/earnings?yaer=2024&strict=true", http.StatusBadRequest},
+		{"strict allows known", true, "/earnings?year=2024&month=1", http.StatusOK},
+		{"strict rejects typo", true, "/earnings?yaer=2024", http.StatusBadRequest},
+		{"strict allows no params", true, "/earnings", http.StatusOK},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			newStrictRouter(tt.enabled).ServeHTTP(w, httptest.NewRequest("GET", tt.url, nil))
+			if w.Code != tt.expected {
+				t.Errorf("GET %s = %d, want %d (body: %s)", tt.url, w.Code, tt.expected, w.Body.String())
+			}
+		})
+	}
+}
@@ -42,6 +42,8 @@ func SecurityHeaders() gin.HandlerFunc {
 		c.Header("Access-Control-Allow-Origin", "*")
 		c.Header("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
 		c.Header("Access-Control-Allow-Headers", "Origin, Content-Type, Accept")
+		// Let browser clients read the paging total of GET /api/timesheet
+		c.Header("Access-Control-Expose-Headers", "X-Total-Count")
 
 		// Security headers
 		c.Header("X-Content-Type-Options", "nosniff")
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"timesheet/internal/db"
+	"timesheet/internal/exitcode"
+)
+
+// runClientAudit prints the mismatches between the clients table and the
+// timesheet. It exits with status 1 when there's anything to fix, so it can
+// gate closing a billing period.
+func runClientAudit(dl db.DataLayer, output outputFormat) {
+	audit, err := db.AuditClients(dl)
+	if err != nil {
+		exitcode.Fail(exitcode.Database, "Failed to audit clients: %v", err)
+	}
+
+	output.print(audit, func() {
+		if audit.Clean() {
+			fmt.Println("Clients and timesheet agree: nothing to fix")
+			return
+		}
+		printClientUsage("Client names in the timesheet without a client", audit.UnknownClients)
+		if len(audit.UnusedClients) > 0 {
+			fmt.Printf("Active clients without client hours (%d):\n", len(audit.UnusedClients))
+			for _, name := range audit.UnusedClients {
+				fmt.Printf("  - %s\n", name)
+			}
+		}
+		printClientUsage("Clients with hours on days without a rate", audit.UnratedClients)
+	})
+
+	if !audit.Clean() {
+		exitcode.Exit(exitcode.Failure)
+	}
+}
+
+func printClientUsage(title string, usage []db.ClientUsage) {
+	if len(usage) == 0 {
+		return
+	}
+	fmt.Printf("%s (%d):\n", title, len(usage))
+	for _, u := range usage {
+		fmt.Printf("  - %s: %d entries, %s to %s", u.Name, u.Entries, u.FirstDate, u.LastDate)
+		if u.Suggestion != "" {
+			fmt.Printf(" (did you mean %q?)", u.Suggestion)
+		}
+		fmt.Println()
+	}
+}
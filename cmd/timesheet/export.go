@@ -0,0 +1,75 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"timesheet/internal/config"
+	"timesheet/internal/db"
+	"timesheet/internal/document"
+	"timesheet/internal/exitcode"
+	printCSV "timesheet/internal/print-csv"
+	printExcel "timesheet/internal/print-excel"
+	printPDF "timesheet/internal/print-pdf"
+)
+
+// exportResult is what --export reports
+type exportResult struct {
+	Year   int    `json:"year"`
+	Month  int    `json:"month"`
+	Format string `json:"format"`
+	Path   string `json:"path"`
+}
+
+// exportPeriod resolves the --year/--month flags for --export. Without
+// either it's last month, the one a monthly cron job wants; otherwise the
+// missing one defaults like statementPeriod.
+func exportPeriod(year, month int) (int, time.Month) {
+	if year == 0 && month == 0 {
+		now := time.Now()
+		lastMonth := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, time.Local).AddDate(0, -1, 0)
+		return lastMonth.Year(), lastMonth.Month()
+	}
+	return statementPeriod(year, month)
+}
+
+// runExport saves the month's timesheet document without starting the TUI
+// and prints where it was written. format is pdf, excel or csv; when it's
+// empty the configured sendDocumentType is used, falling back to PDF like
+// the TUI's print key.
+func runExport(dl db.DataLayer, year int, month time.Month, format string, output outputFormat) {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		format = config.GetDocumentType()
+		if format != "excel" && format != "csv" {
+			format = "pdf"
+		}
+	}
+	var render func(document.Timesheet) (string, error)
+	switch format {
+	case "pdf":
+		render = func(doc document.Timesheet) (string, error) {
+			return printPDF.TimesheetToPDF(doc, false)
+		}
+	case "excel":
+		render = printExcel.TimesheetToExcel
+	case "csv":
+		render = printCSV.TimesheetToCSV
+	default:
+		exitcode.Fail(exitcode.Validation, "Invalid --format %q (expected pdf, excel or csv)", format)
+	}
+
+	doc, err := document.BuildTimesheet(dl, year, month)
+	if err != nil {
+		exitcode.Fail(exitcode.Database, "Failed to build the %04d-%02d timesheet: %v", year, month, err)
+	}
+	path, err := render(doc)
+	if err != nil {
+		exitcode.Fail(exitcode.Failure, "Failed to export the %04d-%02d timesheet: %v", year, month, err)
+	}
+
+	result := exportResult{Year: year, Month: int(month), Format: format, Path: path}
+	output.print(result, func() {
+		fmt.Println(path)
+	})
+}
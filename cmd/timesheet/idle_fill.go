@@ -0,0 +1,30 @@
+package main
+
+import (
+	"log"
+	"time"
+	"timesheet/internal/config"
+	"timesheet/internal/datalayer"
+	"timesheet/internal/db"
+)
+
+// fillIdleOnStartup records idle hours on the empty working days of the
+// previous month when idleAutoFill is enabled. It's idempotent: days filled
+// on an earlier start have hours and are left alone.
+func fillIdleOnStartup() {
+	settings := config.GetIdleAutoFill()
+	if !settings.Enabled {
+		return
+	}
+
+	now := time.Now()
+	lastMonth := now.AddDate(0, 0, -now.Day()) // Last day of the previous month
+	result, err := db.FillIdleDays(datalayer.GetDataLayer(), lastMonth.Year(), lastMonth.Month(),
+		config.GetWorkSchedule(), settings.Hours, lastMonth)
+	for _, date := range append(result.Created, result.Merged...) {
+		log.Printf("Recorded idle hours on empty working day %s", date)
+	}
+	if err != nil {
+		log.Printf("Failed to fill idle days for %s: %v", lastMonth.Format("2006-01"), err)
+	}
+}
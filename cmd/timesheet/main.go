@@ -1,18 +1,22 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
 	"log"
 	"net/http"
 	"os"
+	"strings"
 	"time"
 	"timesheet/api/handler"
 	"timesheet/internal/config"
 	"timesheet/internal/db"
+	"timesheet/internal/exitcode"
+	"timesheet/internal/exports"
 	"timesheet/internal/logging"
-	"timesheet/internal/sync"
 	"timesheet/internal/ui"
+	"timesheet/internal/utils"
 	"timesheet/internal/version"
 
 	tea "github.com/charmbracelet/bubbletea"
@@ -32,6 +36,22 @@ type flags struct {
 	dbType      string
 	postgresURL string
 	syncCmd     bool
+	importCSV   string
+	importFile  string
+	onDuplicate string
+	statement   bool
+	invoiceRef  string
+	week        bool
+	date        string
+	verifyStmt  bool
+	year        int
+	month       int
+	anonymize   bool
+	auditClient bool
+	export      bool
+	format      string
+	checkUpdate bool
+	output      outputFormat
 }
 
 // setupFlags defines and parses command line flags
@@ -48,7 +68,23 @@ func setupFlags() flags {
 	dbTypeFlag := flag.String("db-type", "", "Database type: sqlite or postgres")
 	postgresURLFlag := flag.String("postgres-url", "", "PostgreSQL connection URL")
 	versionFlag := flag.Bool("version", false, "Show version and exit")
+	checkUpdateFlag := flag.Bool("check-update", false, "Check GitHub for a newer release (cached for a day) and exit")
 	syncFlag := flag.Bool("sync", false, "Sync SQLite and PostgreSQL databases (requires both to be configured)")
+	jsonFlag := flag.Bool("json", false, "Print command output (--sync, --import, --import-clients, --statement, --verify-statement, --week, --audit-clients, --export, --check-update) as JSON")
+	importClientsFlag := flag.String("import-clients", "", "Import clients and rate history from a CSV file (client,hourly_rate,effective_date[,notes]) and exit")
+	importFlag := flag.String("import", "", "Import timesheet entries from a CSV file (date,client,client_hours,vacation_hours,idle_hours,training_hours,sick_hours,holiday_hours) and exit")
+	onDuplicateFlag := flag.String("on-duplicate", "skip", "What --import does with a date that already has an entry: skip, overwrite, merge or sum")
+	statementFlag := flag.Bool("statement", false, "Write a tamper-evident monthly statement (see --year, --month) and record its SHA-256")
+	invoiceFlag := flag.String("invoice", "", "With --statement, mark the month's unbilled client hours as billed on this invoice reference")
+	verifyStatementFlag := flag.Bool("verify-statement", false, "Check a month's data against its recorded statement hash; exits 1 on mismatch")
+	yearFlag := flag.Int("year", 0, "Year for --statement, --verify-statement and --export (default: current year)")
+	monthFlag := flag.Int("month", 0, "Month (1-12) for --statement, --verify-statement and --export (default: current month)")
+	weekFlag := flag.Bool("week", false, "Print the hour totals of the current week (see --date) and exit")
+	dateFlag := flag.String("date", "", "Any day (YYYY-MM-DD) in the week for --week (default: today)")
+	auditClientsFlag := flag.Bool("audit-clients", false, "List client names, clients and rates that don't match up, and exit; exits 1 when anything needs fixing")
+	anonymizeFlag := flag.Bool("anonymize", false, "Show clients as Client A, Client B, ... in the TUI and its exports (stored data is unchanged)")
+	exportFlag := flag.Bool("export", false, "Save a month's timesheet document (see --year, --month, --format; default: last month), print its path and exit")
+	formatFlag := flag.String("format", "", "Document format for --export: pdf, excel or csv (default: sendDocumentType from the config)")
 
 	// Custom usage message
 	flag.Usage = func() {
@@ -66,10 +102,28 @@ func setupFlags() flags {
 		fmt.Fprintf(os.Stderr, "  %s --port 3000     Run API server on port 3000\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --db-type postgres --postgres-url \"postgres://...\"  Use PostgreSQL\n", os.Args[0])
 		fmt.Fprintf(os.Stderr, "  %s --sync --postgres-url \"postgres://...\"  Sync SQLite to PostgreSQL\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --import-clients clients.csv  Import clients and rates\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --import hours.csv --on-duplicate sum  Import hours, adding up duplicate dates\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --sync --json | jq .records_pushed  Machine-readable output\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --statement --year 2024 --month 5  Issue the May 2024 statement\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --verify-statement --year 2024 --month 5  Verify it later\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --statement --month 5 --invoice INV-2024-007  Invoice May and mark its hours billed\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --week --date 2024-03-13  Hours logged in that week\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --audit-clients  Check client names and rates before invoicing\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --anonymize     Hide client names for screenshots and demos\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --export --format excel  Save last month's timesheet, e.g. from cron\n", os.Args[0])
+		fmt.Fprintf(os.Stderr, "  %s --check-update  See whether a newer release is out\n", os.Args[0])
 	}
 
-	// Parse flags
-	flag.Parse()
+	// Parse flags. flag prints the problem and the usage; exit with the
+	// validation status rather than flag's own 2, which means a config error
+	flag.CommandLine.Init(os.Args[0], flag.ContinueOnError)
+	if err := flag.CommandLine.Parse(os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			os.Exit(0)
+		}
+		exitcode.Exit(exitcode.Validation)
+	}
 
 	// Check for version flag
 	if *versionFlag {
@@ -77,7 +131,13 @@ func setupFlags() flags {
 		os.Exit(0)
 	}
 
+	output := outputText
+	if *jsonFlag {
+		output = outputJSON
+	}
+
 	return flags{
+		output:      output,
 		noTUI:       *noTUI,
 		tuiOnly:     *tuiOnly,
 		add:         *addFlag,
@@ -89,6 +149,21 @@ func setupFlags() flags {
 		dbType:      *dbTypeFlag,
 		postgresURL: *postgresURLFlag,
 		syncCmd:     *syncFlag,
+		importCSV:   *importClientsFlag,
+		importFile:  *importFlag,
+		onDuplicate: *onDuplicateFlag,
+		statement:   *statementFlag,
+		invoiceRef:  strings.TrimSpace(*invoiceFlag),
+		week:        *weekFlag,
+		date:        *dateFlag,
+		verifyStmt:  *verifyStatementFlag,
+		year:        *yearFlag,
+		month:       *monthFlag,
+		anonymize:   *anonymizeFlag,
+		auditClient: *auditClientsFlag,
+		export:      *exportFlag,
+		format:      *formatFlag,
+		checkUpdate: *checkUpdateFlag,
 	}
 }
 
@@ -102,8 +177,15 @@ func main() {
 		os.Exit(0)
 	}
 
-	// Clear the screen (only if we have a terminal)
-	if !flags.noTUI {
+	// Like --version, checking for an update needs no config or database
+	if flags.checkUpdate {
+		runCheckUpdate(flags.output)
+		os.Exit(0)
+	}
+
+	// Clear the screen (only if we have a terminal and aren't emitting JSON
+	// or a path for scripts)
+	if !flags.noTUI && !flags.export && flags.output != outputJSON {
 		fmt.Print("\033[H\033[2J")
 	}
 
@@ -116,12 +198,16 @@ func main() {
 
 	// Set verbose mode
 	logging.SetVerbose(flags.verbose)
+	flags.output.claimStdout()
 	log.Println("Verbose mode set to:", flags.verbose)
 
 	// Read configuration file (and create if it doesn't exist)
 	config.RequireConfig()
 	log.Println("Config file checked/created")
 
+	// Switch to JSON log lines when configured
+	logging.SetJSON(config.GetLogFormat() == "json")
+
 	// If dev flag is set, set runtime development mode
 	if flags.dev {
 		log.Println("Development mode flag detected")
@@ -131,9 +217,7 @@ func main() {
 	// Add panic recovery at the top level
 	defer func() {
 		if r := recover(); r != nil {
-			fmt.Fprintf(os.Stderr, "PANIC RECOVERED: %v\n", r)
-			log.Printf("Panic recovered: %v", r)
-			os.Exit(1)
+			exitcode.Fail(exitcode.Failure, "PANIC RECOVERED: %v", r)
 		}
 	}()
 
@@ -157,71 +241,112 @@ func main() {
 	dbType := config.GetDBType()
 	log.Printf("Using database type: %s", dbType)
 
-	// Initialize database based on type
+	// Tidy the export directory according to the configured retention
+	exports.PruneOnStartup()
+
+	// Open the database: the PostgreSQL URL in postgres mode, otherwise the
+	// configured database path. A postgres:// URL there selects PostgreSQL too.
+	dsn := config.GetDBPath()
 	if dbType == "postgres" {
-		// PostgreSQL mode
-		postgresURL := config.GetPostgresURL()
-		if postgresURL == "" {
-			log.Fatal("PostgreSQL URL required when using postgres db type. Set via --postgres-url, TIMESHEETZ_POSTGRES_URL, or config file.")
+		dsn = config.GetPostgresURL()
+		if dsn == "" {
+			exitcode.Fail(exitcode.Config, "PostgreSQL URL required when using postgres db type. Set via --postgres-url, TIMESHEETZ_POSTGRES_URL, or config file.")
 		}
+	}
 
-		log.Println("Attempting to connect to PostgreSQL...")
-		if err := db.ConnectPostgres(postgresURL); err != nil {
-			log.Fatalf("Failed to connect to PostgreSQL: %v", err)
+	log.Println("Opening database (ensuring schema is up to date)...")
+	backend, err := db.OpenBackend(dsn)
+	if err != nil {
+		exitcode.Fail(exitcode.Database, "Failed to open database: %v", err)
+	}
+	defer db.CloseBackend(backend)
+	if _, ok := backend.(*db.PostgresDBLayer); ok && dbType != "postgres" {
+		dbType = "postgres"
+		config.SetRuntimeDBType(dbType)
+	}
+	log.Printf("Database opened successfully (%s)", dbType)
+
+	// Handle --anonymize: pseudonyms are assigned from the full client list
+	// so each client gets the same one in every view
+	if flags.anonymize {
+		var names []string
+		if clients, err := backend.GetAllClients(); err == nil {
+			for _, client := range clients {
+				names = append(names, client.Name)
+			}
+		} else {
+			log.Printf("Warning: could not load clients for --anonymize: %v", err)
 		}
-		defer db.ClosePostgres()
-		log.Println("PostgreSQL connected successfully")
+		utils.SetAnonymize(true, names)
+	}
 
-		// Initialize schema
-		log.Println("Initializing PostgreSQL schema...")
-		if err := db.InitializePostgresDatabase(); err != nil {
-			log.Fatalf("Error initializing PostgreSQL database: %v", err)
+	// Handle --init flag: OpenBackend already initialized the schema
+	if flags.init {
+		log.Println("Database initialized")
+		// If just initializing, exit after success
+		if len(flag.Args()) == 0 {
+			os.Exit(0)
 		}
-		log.Println("PostgreSQL schema initialized successfully")
+	}
 
-		// Handle --init flag for postgres
-		if flags.init {
-			log.Println("PostgreSQL database reinitialized")
-			if len(flag.Args()) == 0 {
-				os.Exit(0)
-			}
+	// Handle --import-clients: load clients and rate history from CSV
+	if flags.importCSV != "" {
+		if dbType == "postgres" {
+			exitcode.Fail(exitcode.Validation, "--import-clients imports into the local SQLite database; run it without --db-type postgres and use --sync afterwards")
 		}
-	} else {
-		// SQLite mode (default)
-		dbPath := config.GetDBPath()
-		log.Printf("Database path: %s", dbPath)
+		runClientImport(flags.importCSV, flags.output)
+		os.Exit(0)
+	}
 
-		if _, err := os.Stat(dbPath); err != nil && !os.IsNotExist(err) {
-			log.Fatalf("Error checking database: %v", err)
+	// Handle --import: load timesheet entries from CSV
+	if flags.importFile != "" {
+		if dbType == "postgres" {
+			exitcode.Fail(exitcode.Validation, "--import imports into the local SQLite database; run it without --db-type postgres and use --sync afterwards")
 		}
-
-		// Always run InitializeDatabase: it's idempotent (CREATE TABLE IF NOT
-		// EXISTS / ALTER TABLE error-tolerant) and lets new tables added in
-		// later releases reach existing databases without a manual migration.
-		log.Println("Initializing database (ensuring schema is up to date)...")
-		if err := db.InitializeDatabase(dbPath); err != nil {
-			log.Fatalf("Error initializing database: %v", err)
+		policy, err := db.ParseOverwritePolicy(flags.onDuplicate)
+		if err != nil {
+			exitcode.Fail(exitcode.Validation, "Invalid --on-duplicate: %v", err)
 		}
+		runTimesheetImport(flags.importFile, policy, flags.output)
+		os.Exit(0)
+	}
 
-		log.Println("Attempting to connect to database...")
-		if err := db.Connect(dbPath); err != nil {
-			log.Fatalf("Failed to connect to database: %v", err)
+	if flags.invoiceRef != "" && !flags.statement {
+		exitcode.Fail(exitcode.Validation, "--invoice marks the hours of a --statement as billed; use it together with --statement")
+	}
+
+	// Handle --statement / --verify-statement: hashes are recorded in the
+	// local SQLite database
+	if flags.statement || flags.verifyStmt {
+		if dbType == "postgres" {
+			exitcode.Fail(exitcode.Validation, "--statement and --verify-statement use the local SQLite database; run them without --db-type postgres")
 		}
-		defer db.Close()
-		log.Println("Database connected successfully")
-
-		// Handle database initialization if requested
-		if flags.init {
-			log.Println("Init flag detected, reinitializing database...")
-			if err := db.InitializeDatabase(dbPath); err != nil {
-				log.Fatalf("Error initializing database: %v", err)
-			}
-			log.Println("Database reinitialized successfully")
-			// If just initializing, exit after success
-			if len(flag.Args()) == 0 {
-				os.Exit(0)
-			}
+		year, month := statementPeriod(flags.year, flags.month)
+		if flags.statement {
+			runStatement(year, month, flags.invoiceRef, flags.output)
+		} else {
+			runVerifyStatement(year, month, flags.output)
 		}
+		os.Exit(0)
+	}
+
+	// Handle --week: print the week's totals from the open database
+	if flags.week {
+		runWeekSummary(backend, flags.date, flags.output)
+		os.Exit(0)
+	}
+
+	// Handle --export: save a month's document from the open database
+	if flags.export {
+		year, month := exportPeriod(flags.year, flags.month)
+		runExport(backend, year, month, flags.format, flags.output)
+		os.Exit(0)
+	}
+
+	// Handle --audit-clients: reconcile clients and rates with the timesheet
+	if flags.auditClient {
+		runClientAudit(backend, flags.output)
+		os.Exit(0)
 	}
 
 	// Handle --sync command: sync between SQLite and PostgreSQL
@@ -232,63 +357,47 @@ func main() {
 		// For sync, we need both databases connected
 		postgresURL := config.GetPostgresURL()
 		if postgresURL == "" {
-			log.Fatal("PostgreSQL URL required for sync. Set via --postgres-url, TIMESHEETZ_POSTGRES_URL, or config file.")
+			exitcode.Fail(exitcode.Config, "PostgreSQL URL required for sync. Set via --postgres-url, TIMESHEETZ_POSTGRES_URL, or config file.")
 		}
 
-		// Always connect to SQLite for sync (regardless of db-type setting)
+		// Sync always needs both databases, regardless of the db-type setting
+		if !db.IsPostgresDSN(postgresURL) {
+			exitcode.Fail(exitcode.Config, "PostgreSQL URL for sync is not a PostgreSQL connection string")
+		}
 		dbPath := db.GetDBPath()
 		log.Printf("Connecting to SQLite for sync at: %s", dbPath)
-		if err := db.Connect(dbPath); err != nil {
-			log.Fatalf("Failed to connect to SQLite: %v", err)
-		}
-		defer db.Close()
-
-		// Run SQLite migrations to ensure sync columns exist
-		if err := db.InitializeDatabase(dbPath); err != nil {
-			log.Fatalf("Failed to initialize SQLite: %v", err)
+		sqliteLayer, err := db.OpenBackend(dbPath)
+		if err != nil {
+			exitcode.Fail(exitcode.Database, "Failed to open SQLite: %v", err)
 		}
+		defer db.CloseBackend(sqliteLayer)
 
-		// Always connect to PostgreSQL for sync
 		log.Println("Connecting to PostgreSQL for sync...")
-		if err := db.ConnectPostgres(postgresURL); err != nil {
-			log.Fatalf("Failed to connect to PostgreSQL: %v", err)
-		}
-		defer db.ClosePostgres()
-
-		// Initialize PostgreSQL schema
-		if err := db.InitializePostgresDatabase(); err != nil {
-			log.Fatalf("Error initializing PostgreSQL database: %v", err)
+		postgresLayer, err := db.OpenBackend(postgresURL)
+		if err != nil {
+			exitcode.Fail(exitcode.Database, "Failed to open PostgreSQL: %v", err)
 		}
+		defer db.CloseBackend(postgresLayer)
 
-		// Create sync service and run sync
-		fmt.Println("Starting database sync...")
-		syncService := sync.NewSyncService(db.GetSQLiteDB(), db.GetPostgresDB(), time.Minute)
-
-		if err := syncService.Sync(sync.SyncBidirectional); err != nil {
-			log.Fatalf("Sync failed: %v", err)
-		}
-
-		stats := syncService.GetLastSyncStats()
-		fmt.Printf("Sync completed in %v\n", stats.Duration)
-		fmt.Printf("  Records pushed (local -> remote): %d\n", stats.RecordsPushed)
-		fmt.Printf("  Records pulled (remote -> local): %d\n", stats.RecordsPulled)
-		fmt.Printf("  Tables processed: %d\n", stats.TablesProcessed)
-		if len(stats.Errors) > 0 {
-			fmt.Printf("  Errors: %d\n", len(stats.Errors))
-			for _, e := range stats.Errors {
-				fmt.Printf("    - %s\n", e)
+		if err := runSync(db.GetSQLiteDB(), db.GetPostgresDB(), flags.output); err != nil {
+			if flags.output == outputJSON {
+				exitcode.Exit(exitcode.Database)
 			}
+			exitcode.Fail(exitcode.Database, "Sync failed: %v", err)
 		}
 		os.Exit(0)
 	}
 
+	// Record idle hours on last month's empty working days (opt-in)
+	fillIdleOnStartup()
+
 	// Start the TUI if requested
 	if flags.tuiOnly {
 		log.Println("Starting TUI only mode...")
 		model := ui.NewAppModel(flags.add)
 		p := tea.NewProgram(model, tea.WithAltScreen())
 		if _, err := p.Run(); err != nil {
-			log.Fatalf("Error running TUI: %v", err)
+			exitcode.Fail(exitcode.Failure, "Error running TUI: %v", err)
 		}
 		os.Exit(0)
 	}
@@ -306,6 +415,8 @@ func main() {
 	log.Println("Initializing UI...")
 	app := ui.NewAppModel(flags.add)
 	refreshChan := app.GetRefreshChan()
+	// Let the in-process API's event stream follow edits made in the TUI
+	app.SetChangeNotifier(handler.PublishTUIChange)
 	log.Println("UI initialized")
 
 	// Create the UI program first
@@ -364,8 +475,7 @@ func main() {
 	// Run the UI program
 	log.Println("Starting UI program...")
 	if _, err := p.Run(); err != nil {
-		log.Printf("Error running program: %v", err)
-		os.Exit(1)
+		exitcode.Fail(exitcode.Failure, "Error running program: %v", err)
 	}
 
 	// Clean up the terminal
@@ -373,3 +483,90 @@ func main() {
 	fmt.Print("\033[2J")   // Clear screen
 	fmt.Print("\033[H")    // Move cursor to top-left
 }
+
+// runClientImport imports clients and rates from a CSV file and prints a report
+func runClientImport(path string, output outputFormat) {
+	file, err := os.Open(path)
+	if err != nil {
+		exitcode.Fail(exitcode.Validation, "Failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	report, err := db.ImportClientsCSV(file)
+	if err != nil {
+		exitcode.Fail(exitcode.Database, "Import failed, nothing was imported: %v", err)
+	}
+
+	if report.Conflicts == nil {
+		report.Conflicts = []db.ClientImportIssue{}
+	}
+	if report.Unmatched == nil {
+		report.Unmatched = []db.ClientImportIssue{}
+	}
+	output.print(report, func() { printClientImportReport(path, report) })
+}
+
+// runTimesheetImport imports timesheet entries from a CSV file and prints a report
+func runTimesheetImport(path string, policy db.OverwritePolicy, output outputFormat) {
+	file, err := os.Open(path)
+	if err != nil {
+		exitcode.Fail(exitcode.Validation, "Failed to open %s: %v", path, err)
+	}
+	defer file.Close()
+
+	report, err := db.ImportTimesheetCSV(file, policy)
+	if err != nil {
+		exitcode.Fail(exitcode.Database, "Import failed, nothing was imported: %v", err)
+	}
+
+	if report.Conflicts == nil {
+		report.Conflicts = []db.TimesheetImportConflict{}
+	}
+	if report.Unmatched == nil {
+		report.Unmatched = []db.TimesheetImportIssue{}
+	}
+	output.print(report, func() { printTimesheetImportReport(path, report) })
+}
+
+// printTimesheetImportReport prints the human-readable import summary
+func printTimesheetImportReport(path string, report db.TimesheetImportReport) {
+	fmt.Printf("Imported timesheet entries from %s (duplicates: %s)\n", path, report.Policy)
+	fmt.Printf("  Created: %d\n", report.Created)
+	fmt.Printf("  Replaced: %d\n", report.Replaced)
+	fmt.Printf("  Merged: %d\n", report.Merged)
+	fmt.Printf("  Summed: %d\n", report.Summed)
+	fmt.Printf("  Skipped: %d\n", report.Skipped)
+	if len(report.Conflicts) > 0 {
+		fmt.Printf("  Duplicate dates: %d\n", len(report.Conflicts))
+		for _, c := range report.Conflicts {
+			fmt.Printf("    - line %d: %s clashes with %s, %s\n", c.Line, c.Date, c.With, c.Resolution)
+		}
+	}
+	if len(report.Unmatched) > 0 {
+		fmt.Printf("  Unmatched rows: %d\n", len(report.Unmatched))
+		for _, u := range report.Unmatched {
+			fmt.Printf("    - line %d: %s\n", u.Line, u.Reason)
+		}
+	}
+}
+
+// printClientImportReport prints the human-readable import summary
+func printClientImportReport(path string, report db.ClientImportReport) {
+	fmt.Printf("Imported clients from %s\n", path)
+	fmt.Printf("  Clients created: %d\n", report.ClientsCreated)
+	fmt.Printf("  Clients already present: %d\n", report.ClientsReused)
+	fmt.Printf("  Rates created: %d\n", report.RatesCreated)
+	fmt.Printf("  Rates already present: %d\n", report.RatesSkipped)
+	if len(report.Conflicts) > 0 {
+		fmt.Printf("  Conflicts: %d\n", len(report.Conflicts))
+		for _, c := range report.Conflicts {
+			fmt.Printf("    - line %d: %s\n", c.Line, c.Reason)
+		}
+	}
+	if len(report.Unmatched) > 0 {
+		fmt.Printf("  Unmatched rows: %d\n", len(report.Unmatched))
+		for _, u := range report.Unmatched {
+			fmt.Printf("    - line %d: %s\n", u.Line, u.Reason)
+		}
+	}
+}
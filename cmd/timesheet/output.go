@@ -0,0 +1,40 @@
+package main
+
+import (
+	"encoding/json"
+	"os"
+	"timesheet/internal/exitcode"
+	"timesheet/internal/logging"
+)
+
+// outputFormat selects how CLI report commands (--sync, --import-clients, …)
+// print their results
+type outputFormat int
+
+const (
+	outputText outputFormat = iota // Human-readable lines (default)
+	outputJSON                     // A single JSON document on stdout, for jq and scripts
+)
+
+// print writes v as indented JSON when the format is outputJSON, otherwise
+// it runs text to print the human-readable version
+func (f outputFormat) print(v any, text func()) {
+	if f != outputJSON {
+		text()
+		return
+	}
+
+	enc := json.NewEncoder(os.Stdout)
+	enc.SetIndent("", "  ")
+	if err := enc.Encode(v); err != nil {
+		exitcode.Fail(exitcode.Failure, "Failed to encode JSON output: %v", err)
+	}
+}
+
+// claimStdout keeps stdout for the JSON document by sending verbose
+// messages to stderr
+func (f outputFormat) claimStdout() {
+	if f == outputJSON {
+		logging.SetConsole(os.Stderr)
+	}
+}
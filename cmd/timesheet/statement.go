@@ -0,0 +1,132 @@
+package main
+
+import (
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"time"
+	"timesheet/internal/db"
+	"timesheet/internal/exitcode"
+	"timesheet/internal/exports"
+)
+
+// statementPeriod resolves the --year/--month flags, defaulting to the
+// current month
+func statementPeriod(year, month int) (int, time.Month) {
+	now := time.Now()
+	if year == 0 {
+		year = now.Year()
+	}
+	if month == 0 {
+		month = int(now.Month())
+	}
+	if month < 1 || month > 12 {
+		exitcode.Fail(exitcode.Validation, "Invalid --month %d (must be 1-12)", month)
+	}
+	return year, time.Month(month)
+}
+
+// statementResult is what --statement reports
+type statementResult struct {
+	Year       int     `json:"year"`
+	Month      int     `json:"month"`
+	Path       string  `json:"path"`
+	Entries    int     `json:"entries"`
+	TotalHours float64 `json:"total_hours"`
+	SHA256     string  `json:"sha256"`
+	InvoiceRef string  `json:"invoice_ref,omitempty"`
+	Billed     int     `json:"billed,omitempty"` // Entries marked billed on InvoiceRef
+}
+
+// runStatement writes the month's statement document to the export
+// directory and records its hash. With an invoice reference the statement
+// is the invoice's basis, so the month's unbilled client hours are marked
+// billed on it.
+func runStatement(year int, month time.Month, invoiceRef string, output outputFormat) {
+	entries, err := db.GetAllTimesheetEntries(year, month)
+	if err != nil {
+		exitcode.Fail(exitcode.Database, "Failed to load timesheet entries: %v", err)
+	}
+
+	statement, err := db.NewStatement(year, month, entries)
+	if err != nil {
+		exitcode.Fail(exitcode.Failure, "Failed to build statement: %v", err)
+	}
+
+	if err := db.RecordStatement(statement); err != nil {
+		if errors.Is(err, db.ErrStatementChanged) {
+			exitcode.Fail(exitcode.Failure, "The %04d-%02d statement was already issued and the data has changed since; run --verify-statement for details", year, month)
+		}
+		exitcode.Fail(exitcode.Database, "Failed to record statement: %v", err)
+	}
+
+	path, err := exports.Path(fmt.Sprintf("statement_%04d-%02d.json", year, month))
+	if err != nil {
+		exitcode.Fail(exitcode.Failure, "Failed to prepare statement file: %v", err)
+	}
+	data, err := json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		exitcode.Fail(exitcode.Failure, "Failed to encode statement: %v", err)
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		exitcode.Fail(exitcode.Failure, "Failed to write statement: %v", err)
+	}
+
+	result := statementResult{
+		Year:       year,
+		Month:      int(month),
+		Path:       path,
+		Entries:    len(statement.Entries),
+		TotalHours: statement.TotalHours,
+		SHA256:     statement.SHA256,
+		InvoiceRef: invoiceRef,
+	}
+	if invoiceRef != "" {
+		result.Billed, err = db.MarkEntriesBilled("", year, int(month), invoiceRef)
+		if err != nil {
+			exitcode.Fail(exitcode.Database, "Statement written to %s, but marking entries billed failed: %v", path, err)
+		}
+	}
+	output.print(result, func() {
+		fmt.Printf("Statement for %04d-%02d written to %s\n", year, month, path)
+		fmt.Printf("  Entries: %d\n", result.Entries)
+		fmt.Printf("  Total hours: %g\n", result.TotalHours)
+		fmt.Printf("  SHA-256: %s\n", result.SHA256)
+		if invoiceRef != "" {
+			fmt.Printf("  Marked billed on %s: %d entries\n", invoiceRef, result.Billed)
+		}
+	})
+}
+
+// runVerifyStatement recomputes the month's hash and compares it with the
+// recorded statement. It exits with status 1 when they differ.
+func runVerifyStatement(year int, month time.Month, output outputFormat) {
+	entries, err := db.GetAllTimesheetEntries(year, month)
+	if err != nil {
+		exitcode.Fail(exitcode.Database, "Failed to load timesheet entries: %v", err)
+	}
+
+	result, err := db.VerifyStatement(year, month, entries)
+	if errors.Is(err, sql.ErrNoRows) {
+		exitcode.Fail(exitcode.Failure, "No statement was issued for %04d-%02d; create one with --statement", year, month)
+	}
+	if err != nil {
+		exitcode.Fail(exitcode.Database, "Failed to verify statement: %v", err)
+	}
+
+	output.print(result, func() {
+		if result.Match {
+			fmt.Printf("Statement for %04d-%02d verified: data unchanged since %s\n", year, month, result.RecordedAt)
+		} else {
+			fmt.Printf("Statement for %04d-%02d does NOT match: data changed since %s\n", year, month, result.RecordedAt)
+		}
+		fmt.Printf("  Recorded SHA-256: %s\n", result.RecordedHash)
+		fmt.Printf("  Current SHA-256:  %s\n", result.CurrentHash)
+	})
+
+	if !result.Match {
+		exitcode.Exit(exitcode.Failure)
+	}
+}
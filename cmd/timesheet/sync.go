@@ -0,0 +1,47 @@
+package main
+
+import (
+	"database/sql"
+	"fmt"
+	"log"
+	"time"
+	"timesheet/internal/sync"
+)
+
+// runSync syncs the local SQLite database with the remote PostgreSQL one
+// and prints the stats. With JSON output the stats are printed even when
+// the sync fails, so scripts can read the errors.
+func runSync(localDB, remoteDB *sql.DB, output outputFormat) error {
+	log.Println("Starting database sync...")
+	syncService := sync.NewSyncService(localDB, remoteDB, time.Minute)
+
+	syncErr := syncService.Sync(sync.SyncBidirectional)
+	if syncErr != nil && output != outputJSON {
+		return syncErr
+	}
+
+	stats := syncService.GetLastSyncStats()
+	syncErrors := stats.Errors
+	if syncErrors == nil {
+		syncErrors = []string{}
+	}
+	output.print(struct {
+		DurationMs      int64    `json:"duration_ms"`
+		RecordsPushed   int      `json:"records_pushed"`
+		RecordsPulled   int      `json:"records_pulled"`
+		TablesProcessed int      `json:"tables_processed"`
+		Errors          []string `json:"errors"`
+	}{stats.Duration.Milliseconds(), stats.RecordsPushed, stats.RecordsPulled, stats.TablesProcessed, syncErrors}, func() {
+		fmt.Printf("Sync completed in %v\n", stats.Duration)
+		fmt.Printf("  Records pushed (local -> remote): %d\n", stats.RecordsPushed)
+		fmt.Printf("  Records pulled (remote -> local): %d\n", stats.RecordsPulled)
+		fmt.Printf("  Tables processed: %d\n", stats.TablesProcessed)
+		if len(stats.Errors) > 0 {
+			fmt.Printf("  Errors: %d\n", len(stats.Errors))
+			for _, e := range stats.Errors {
+				fmt.Printf("    - %s\n", e)
+			}
+		}
+	})
+	return syncErr
+}
@@ -0,0 +1,78 @@
+package main
+
+import (
+	"bytes"
+	"database/sql"
+	"encoding/json"
+	"io"
+	"log"
+	"os"
+	"testing"
+	"timesheet/internal/db"
+	"timesheet/internal/logging"
+
+	_ "modernc.org/sqlite"
+)
+
+// openSchemaDB opens an in-memory SQLite database with the app's schema
+func openSchemaDB(t *testing.T) *sql.DB {
+	t.Helper()
+	conn, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("open: %v", err)
+	}
+	t.Cleanup(func() { conn.Close() })
+	if err := db.ApplySQLiteSchema(conn); err != nil {
+		t.Fatalf("schema: %v", err)
+	}
+	return conn
+}
+
+// TestRunSync_JSONStdout checks that --sync --json leaves a single JSON
+// document on stdout, even with --verbose progress messages.
+func TestRunSync_JSONStdout(t *testing.T) {
+	r, w, err := os.Pipe()
+	if err != nil {
+		t.Fatalf("pipe: %v", err)
+	}
+	devNull, err := os.Open(os.DevNull)
+	if err != nil {
+		t.Fatalf("open %s: %v", os.DevNull, err)
+	}
+	defer devNull.Close()
+	stdout, stderr := os.Stdout, os.Stderr
+	os.Stdout, os.Stderr = w, devNull
+	logOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	logging.SetConsole(w)
+	logging.SetVerbose(true)
+	t.Cleanup(func() {
+		os.Stdout, os.Stderr = stdout, stderr
+		log.SetOutput(logOutput)
+		logging.SetConsole(stdout)
+		logging.SetVerbose(false)
+	})
+
+	outputJSON.claimStdout()
+	syncErr := runSync(openSchemaDB(t), openSchemaDB(t), outputJSON)
+	w.Close()
+	out, err := io.ReadAll(r)
+	if err != nil {
+		t.Fatalf("read stdout: %v", err)
+	}
+	if syncErr != nil {
+		t.Fatalf("sync: %v", syncErr)
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(out))
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		t.Fatalf("stdout is not JSON: %v\n%s", err, out)
+	}
+	if dec.More() {
+		t.Fatalf("stdout holds more than one JSON document:\n%s", out)
+	}
+	if _, ok := doc["records_pushed"]; !ok {
+		t.Errorf("missing records_pushed in %s", out)
+	}
+}
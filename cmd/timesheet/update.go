@@ -0,0 +1,39 @@
+package main
+
+import (
+	"fmt"
+	"timesheet/internal/updater"
+	"timesheet/internal/version"
+)
+
+// updateCheckResult is what --check-update reports
+type updateCheckResult struct {
+	Current         string `json:"current"`
+	Latest          string `json:"latest,omitempty"`
+	UpdateAvailable bool   `json:"update_available"`
+	Error           string `json:"error,omitempty"` // Why the check couldn't be done
+}
+
+// runCheckUpdate prints whether a newer release than this build is out.
+// Being offline or rate limited isn't a failure: it's reported as a check
+// that couldn't be done.
+func runCheckUpdate(output outputFormat) {
+	checker := updater.NewUpdateChecker("joelgrimberg", "timesheetz")
+	latest, available, err := checker.CheckForUpdateCached(version.Version, updater.DefaultCachePath())
+
+	result := updateCheckResult{Current: version.Version, Latest: latest, UpdateAvailable: available}
+	if err != nil {
+		result.Error = err.Error()
+	}
+
+	output.print(result, func() {
+		switch {
+		case err != nil:
+			fmt.Printf("Could not check for updates: %v\n", err)
+		case available:
+			fmt.Printf("Update available: %s (you have %s)\n", latest, version.Version)
+		default:
+			fmt.Printf("Timesheetz %s is up to date\n", version.Version)
+		}
+	})
+}
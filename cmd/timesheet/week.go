@@ -0,0 +1,38 @@
+package main
+
+import (
+	"fmt"
+	"time"
+	"timesheet/internal/config"
+	"timesheet/internal/db"
+	"timesheet/internal/exitcode"
+)
+
+// runWeekSummary prints the hour totals of the week containing date
+// (YYYY-MM-DD, default today)
+func runWeekSummary(dl db.DataLayer, date string, output outputFormat) {
+	day := time.Now()
+	if date != "" {
+		var err error
+		day, err = time.ParseInLocation("2006-01-02", date, time.Local)
+		if err != nil {
+			exitcode.Fail(exitcode.Validation, "Invalid --date %q (expected YYYY-MM-DD)", date)
+		}
+	}
+
+	summary, err := db.GetWeekSummary(dl, day, config.GetWeekStart(), config.GetWorkSchedule())
+	if err != nil {
+		exitcode.Fail(exitcode.Database, "Failed to summarize week: %v", err)
+	}
+
+	output.print(summary, func() {
+		fmt.Printf("Week %d of %d (%s to %s)\n", summary.ISOWeek, summary.ISOYear, summary.Start, summary.End)
+		fmt.Printf("  Client hours: %g\n", summary.ClientHours)
+		fmt.Printf("  Vacation hours: %g\n", summary.VacationHours)
+		fmt.Printf("  Idle hours: %g\n", summary.IdleHours)
+		fmt.Printf("  Training hours: %g\n", summary.TrainingHours)
+		fmt.Printf("  Sick hours: %g\n", summary.SickHours)
+		fmt.Printf("  Holiday hours: %g\n", summary.HolidayHours)
+		fmt.Printf("  Total: %g of %d expected (%d days logged)\n", summary.TotalHours, summary.ExpectedHours, summary.DaysLogged)
+	})
+}
@@ -19,8 +19,12 @@ func (a *ClientAdapter) GetAllTimesheetEntries(year int, month time.Month) ([]db
 	return a.client.GetAllTimesheetEntries(year, month)
 }
 
-func (a *ClientAdapter) GetTimesheetEntryByDate(date string) (db.TimesheetEntry, error) {
-	return a.client.GetTimesheetEntryByDate(date)
+func (a *ClientAdapter) GetTimesheetEntriesByDate(date string) ([]db.TimesheetEntry, error) {
+	return a.client.GetTimesheetEntriesByDate(date)
+}
+
+func (a *ClientAdapter) GetTimesheetEntriesInRange(from, to string, limit, offset int) ([]db.TimesheetEntry, int, error) {
+	return a.client.GetTimesheetEntriesInRange(from, to, limit, offset)
 }
 
 func (a *ClientAdapter) AddTimesheetEntry(entry db.TimesheetEntry) error {
@@ -43,10 +47,18 @@ func (a *ClientAdapter) DeleteTimesheetEntry(id string) error {
 	return a.client.DeleteTimesheetEntry(id)
 }
 
+func (a *ClientAdapter) DeleteTimesheetEntriesForMonth(year int, month time.Month) (int, error) {
+	return a.client.DeleteTimesheetEntriesForMonth(year, month)
+}
+
 func (a *ClientAdapter) GetLastClientName() (string, error) {
 	return a.client.GetLastClientName()
 }
 
+func (a *ClientAdapter) GetYearsWithData(includeTraining bool) ([]int, error) {
+	return a.client.GetYearsWithData(includeTraining)
+}
+
 func (a *ClientAdapter) GetTrainingEntriesForYear(year int) ([]db.TimesheetEntry, error) {
 	return a.client.GetTrainingEntriesForYear(year)
 }
@@ -55,10 +67,22 @@ func (a *ClientAdapter) GetVacationEntriesForYear(year int) ([]db.TimesheetEntry
 	return a.client.GetVacationEntriesForYear(year)
 }
 
-func (a *ClientAdapter) GetVacationHoursForYear(year int) (int, error) {
+func (a *ClientAdapter) GetVacationHoursForYear(year int) (float64, error) {
 	return a.client.GetVacationHoursForYear(year)
 }
 
+func (a *ClientAdapter) GetMonthlyTotals(year int, month time.Month) (db.HourTotals, error) {
+	return a.client.GetMonthlyTotals(year, month)
+}
+
+func (a *ClientAdapter) GetSickHoursForYear(year int) (float64, error) {
+	return a.client.GetSickHoursForYear(year)
+}
+
+func (a *ClientAdapter) GetIdleHoursForYear(year int) (float64, error) {
+	return a.client.GetIdleHoursForYear(year)
+}
+
 func (a *ClientAdapter) GetVacationCarryoverForYear(year int) (db.VacationCarryover, error) {
 	return a.client.GetVacationCarryoverForYear(year)
 }
@@ -95,6 +119,10 @@ func (a *ClientAdapter) GetTrainingBudgetEntriesForYear(year int) ([]db.Training
 	return a.client.GetTrainingBudgetEntriesForYear(year)
 }
 
+func (a *ClientAdapter) GetTrainingBudgetSpentForYear(year int) (db.TrainingBudgetSpent, error) {
+	return a.client.GetTrainingBudgetSpentForYear(year)
+}
+
 func (a *ClientAdapter) AddTrainingBudgetEntry(entry db.TrainingBudgetEntry) error {
 	return a.client.AddTrainingBudgetEntry(entry)
 }
@@ -183,6 +211,28 @@ func (a *ClientAdapter) GetClientRateByName(clientName string, date string) (flo
 	return a.client.GetClientRateByName(clientName, date)
 }
 
+// Expense operations
+
+func (a *ClientAdapter) GetExpenses(year, month int) ([]db.Expense, error) {
+	return a.client.GetExpenses(year, month)
+}
+
+func (a *ClientAdapter) GetExpense(id int) (db.Expense, error) {
+	return a.client.GetExpense(id)
+}
+
+func (a *ClientAdapter) AddExpense(expense db.Expense) (int, error) {
+	return a.client.AddExpense(expense)
+}
+
+func (a *ClientAdapter) UpdateExpense(expense db.Expense) error {
+	return a.client.UpdateExpense(expense)
+}
+
+func (a *ClientAdapter) DeleteExpense(id int) error {
+	return a.client.DeleteExpense(id)
+}
+
 // Earnings operations
 
 func (a *ClientAdapter) CalculateEarningsForYear(year int) (db.EarningsOverview, error) {
@@ -31,6 +31,8 @@ func TestClientAdapter_AllMethods(t *testing.T) {
 		switch r.URL.Path {
 		case "/api/timesheet":
 			json.NewEncoder(w).Encode(entries)
+		case "/api/timesheet/date/2024-01-15":
+			json.NewEncoder(w).Encode(entries)
 		case "/api/last-client":
 			json.NewEncoder(w).Encode(map[string]string{"client_name": "Client A"})
 		case "/api/training-budget":
@@ -50,9 +52,9 @@ func TestClientAdapter_AllMethods(t *testing.T) {
 		t.Errorf("GetAllTimesheetEntries failed: %v", err)
 	}
 
-	_, err = adapter.GetTimesheetEntryByDate("2024-01-15")
+	_, err = adapter.GetTimesheetEntriesByDate("2024-01-15")
 	if err != nil {
-		t.Errorf("GetTimesheetEntryByDate failed: %v", err)
+		t.Errorf("GetTimesheetEntriesByDate failed: %v", err)
 	}
 
 	err = adapter.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-01-16", Client_name: "Client B"})
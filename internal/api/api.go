@@ -14,7 +14,7 @@ type TrainingBudgetEntry struct {
 	Id               int     `json:"id"`
 	Date             string  `json:"date"`
 	Training_name    string  `json:"training_name"`
-	Hours            int     `json:"hours"`
+	Hours            float64 `json:"hours"`
 	Cost_without_vat float64 `json:"cost_without_vat"`
 }
 
@@ -116,13 +116,13 @@ func GetTrainingHours(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var totalHours int
+	var totalHours float64
 	for _, entry := range entries {
 		totalHours += entry.Training_hours
 	}
 
 	response := struct {
-		TotalHours int `json:"total_hours"`
+		TotalHours float64 `json:"total_hours"`
 	}{
 		TotalHours: totalHours,
 	}
@@ -159,13 +159,13 @@ func GetVacationHours(w http.ResponseWriter, r *http.Request) {
 	}
 
 	totalHours := config.VacationHours.YearlyTarget
-	availableHours := totalHours - usedHours
+	availableHours := float64(totalHours) - usedHours
 
 	response := struct {
-		Year           int `json:"year"`
-		TotalHours     int `json:"total_hours"`
-		UsedHours      int `json:"used_hours"`
-		AvailableHours int `json:"available_hours"`
+		Year           int     `json:"year"`
+		TotalHours     int     `json:"total_hours"`
+		UsedHours      float64 `json:"used_hours"`
+		AvailableHours float64 `json:"available_hours"`
 	}{
 		Year:           yearInt,
 		TotalHours:     totalHours,
@@ -5,13 +5,16 @@ import (
 	"encoding/json"
 	"fmt"
 	"io"
+	"mime"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 	"time"
 	"timesheet/internal/config"
 	"timesheet/internal/db"
 	"timesheet/internal/logging"
+	"timesheet/internal/utils"
 )
 
 // Client is an HTTP client for the timesheet API
@@ -32,20 +35,27 @@ func NewClient(baseURL string) *Client {
 
 // makeRequest makes an HTTP request and returns the response body
 func (c *Client) makeRequest(method, endpoint string, body interface{}) ([]byte, error) {
+	respBody, _, err := c.makeRequestWithHeader(method, endpoint, body)
+	return respBody, err
+}
+
+// makeRequestWithHeader makes an HTTP request and returns the response body
+// and headers
+func (c *Client) makeRequestWithHeader(method, endpoint string, body interface{}) ([]byte, http.Header, error) {
 	url := c.baseURL + endpoint
 
 	var reqBody io.Reader
 	if body != nil {
 		jsonData, err := json.Marshal(body)
 		if err != nil {
-			return nil, fmt.Errorf("failed to marshal request body: %w", err)
+			return nil, nil, fmt.Errorf("failed to marshal request body: %w", err)
 		}
 		reqBody = bytes.NewBuffer(jsonData)
 	}
 
 	req, err := http.NewRequest(method, url, reqBody)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, nil, fmt.Errorf("failed to create request: %w", err)
 	}
 
 	if body != nil {
@@ -54,20 +64,52 @@ func (c *Client) makeRequest(method, endpoint string, body interface{}) ([]byte,
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return nil, fmt.Errorf("failed to make request: %w", err)
+		return nil, nil, fmt.Errorf("failed to make request: %w", err)
 	}
 	defer resp.Body.Close()
 
 	respBody, err := io.ReadAll(resp.Body)
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, nil, fmt.Errorf("failed to read response body: %w", err)
+	}
+
+	// A proxy or load balancer in front of the API answers with an HTML
+	// page; report that instead of letting the caller fail to decode it
+	contentType := resp.Header.Get("Content-Type")
+	if len(respBody) > 0 && !isJSONResponse(contentType, respBody) {
+		if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+			return nil, nil, fmt.Errorf("remote returned %d: %s", resp.StatusCode, bodySnippet(respBody))
+		}
+		return nil, nil, fmt.Errorf("remote returned %d with non-JSON content (%s): %s", resp.StatusCode, contentType, bodySnippet(respBody))
 	}
 
 	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
-		return nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
+		return nil, nil, fmt.Errorf("API request failed with status %d: %s", resp.StatusCode, string(respBody))
 	}
 
-	return respBody, nil
+	return respBody, resp.Header, nil
+}
+
+// maxBodySnippet is how much of an unexpected response body ends up in errors
+const maxBodySnippet = 200
+
+// isJSONResponse reports whether a response carries JSON. Responses without
+// a specific content type (e.g. sniffed as text/plain) are judged by their body.
+func isJSONResponse(contentType string, body []byte) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil || mediaType == "" || mediaType == "text/plain" {
+		return json.Valid(body)
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
+
+// bodySnippet returns the start of body on a single line, for error messages
+func bodySnippet(body []byte) string {
+	snippet := strings.Join(strings.Fields(string(body)), " ")
+	if len(snippet) > maxBodySnippet {
+		snippet = snippet[:maxBodySnippet] + "..."
+	}
+	return snippet
 }
 
 // GetAllTimesheetEntries retrieves all timesheet entries
@@ -105,21 +147,52 @@ func (c *Client) GetAllTimesheetEntries(year int, month time.Month) ([]db.Timesh
 	return entries, nil
 }
 
-// GetTimesheetEntryByDate retrieves a timesheet entry by date
-func (c *Client) GetTimesheetEntryByDate(date string) (db.TimesheetEntry, error) {
-	// Get all entries and find the one with matching date
-	entries, err := c.GetAllTimesheetEntries(0, 0)
+// GetTimesheetEntriesInRange retrieves a page of the entries dated from
+// through to and the number of entries in the whole range
+func (c *Client) GetTimesheetEntriesInRange(from, to string, limit, offset int) ([]db.TimesheetEntry, int, error) {
+	params := url.Values{}
+	if from != "" {
+		params.Set("from", from)
+	}
+	if to != "" {
+		params.Set("to", to)
+	}
+	if limit > 0 {
+		params.Set("limit", strconv.Itoa(limit))
+	}
+	if offset > 0 {
+		params.Set("offset", strconv.Itoa(offset))
+	}
+
+	data, header, err := c.makeRequestWithHeader("GET", "/api/timesheet?"+params.Encode(), nil)
 	if err != nil {
-		return db.TimesheetEntry{}, err
+		return nil, 0, err
 	}
 
-	for _, entry := range entries {
-		if entry.Date == date {
-			return entry, nil
-		}
+	var entries []db.TimesheetEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, 0, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	total, err := strconv.Atoi(header.Get("X-Total-Count"))
+	if err != nil {
+		return nil, 0, fmt.Errorf("invalid X-Total-Count header %q", header.Get("X-Total-Count"))
+	}
+	return entries, total, nil
+}
 
-	return db.TimesheetEntry{}, fmt.Errorf("entry not found for date %s", date)
+// GetTimesheetEntriesByDate retrieves the entries logged on date, one per
+// client
+func (c *Client) GetTimesheetEntriesByDate(date string) ([]db.TimesheetEntry, error) {
+	data, err := c.makeRequest("GET", "/api/timesheet/date/"+url.PathEscape(date), nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var entries []db.TimesheetEntry
+	if err := json.Unmarshal(data, &entries); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return entries, nil
 }
 
 // AddTimesheetEntry creates a new timesheet entry
@@ -144,14 +217,19 @@ func (c *Client) UpdateTimesheetEntryById(id string, data map[string]any) error
 	return err
 }
 
-// DeleteTimesheetEntryByDate deletes a timesheet entry by date
+// DeleteTimesheetEntryByDate deletes all entries logged on date
 func (c *Client) DeleteTimesheetEntryByDate(date string) error {
-	// First, get the entry to find its ID
-	entry, err := c.GetTimesheetEntryByDate(date)
+	// First, get the entries to find their IDs
+	entries, err := c.GetTimesheetEntriesByDate(date)
 	if err != nil {
 		return err
 	}
-	return c.DeleteTimesheetEntry(strconv.Itoa(entry.Id))
+	for _, entry := range entries {
+		if err := c.DeleteTimesheetEntry(strconv.Itoa(entry.Id)); err != nil {
+			return err
+		}
+	}
+	return nil
 }
 
 // DeleteTimesheetEntry deletes a timesheet entry by ID
@@ -160,6 +238,23 @@ func (c *Client) DeleteTimesheetEntry(id string) error {
 	return err
 }
 
+// DeleteTimesheetEntriesForMonth deletes every entry of a month and returns
+// how many were deleted
+func (c *Client) DeleteTimesheetEntriesForMonth(year int, month time.Month) (int, error) {
+	data, err := c.makeRequest("DELETE", fmt.Sprintf("/api/timesheet/month?year=%d&month=%d", year, int(month)), nil)
+	if err != nil {
+		return 0, err
+	}
+
+	var result struct {
+		Deleted int `json:"deleted"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+	return result.Deleted, nil
+}
+
 // GetLastClientName returns the last client name
 func (c *Client) GetLastClientName() (string, error) {
 	data, err := c.makeRequest("GET", "/api/last-client", nil)
@@ -177,6 +272,27 @@ func (c *Client) GetLastClientName() (string, error) {
 	return result.ClientName, nil
 }
 
+// GetYearsWithData retrieves the years that have timesheet entries
+func (c *Client) GetYearsWithData(includeTraining bool) ([]int, error) {
+	endpoint := "/api/years"
+	if includeTraining {
+		endpoint += "?training=true"
+	}
+	data, err := c.makeRequest("GET", endpoint, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	var result struct {
+		Years []int `json:"years"`
+	}
+	if err := json.Unmarshal(data, &result); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
+	}
+
+	return result.Years, nil
+}
+
 // GetTrainingEntriesForYear retrieves training entries for a year
 func (c *Client) GetTrainingEntriesForYear(year int) ([]db.TimesheetEntry, error) {
 	// Get all entries and filter for training hours > 0
@@ -216,13 +332,13 @@ func (c *Client) GetVacationEntriesForYear(year int) ([]db.TimesheetEntry, error
 }
 
 // GetVacationHoursForYear returns total vacation hours for a year
-func (c *Client) GetVacationHoursForYear(year int) (int, error) {
+func (c *Client) GetVacationHoursForYear(year int) (float64, error) {
 	entries, err := c.GetVacationEntriesForYear(year)
 	if err != nil {
 		return 0, err
 	}
 
-	total := 0
+	var total float64
 	for _, entry := range entries {
 		total += entry.Vacation_hours
 	}
@@ -230,6 +346,42 @@ func (c *Client) GetVacationHoursForYear(year int) (int, error) {
 	return total, nil
 }
 
+// GetSickHoursForYear returns total sick hours for a year
+func (c *Client) GetSickHoursForYear(year int) (float64, error) {
+	return c.sumHoursForYear(year, func(entry db.TimesheetEntry) float64 { return entry.Sick_hours })
+}
+
+// GetIdleHoursForYear returns total idle hours for a year
+func (c *Client) GetIdleHoursForYear(year int) (float64, error) {
+	return c.sumHoursForYear(year, func(entry db.TimesheetEntry) float64 { return entry.Idle_hours })
+}
+
+// GetMonthlyTotals returns the hours of each category logged in a month
+func (c *Client) GetMonthlyTotals(year int, month time.Month) (db.HourTotals, error) {
+	entries, err := c.GetAllTimesheetEntries(year, month)
+	if err != nil {
+		return db.HourTotals{}, err
+	}
+	return db.SumHourTotals(entries), nil
+}
+
+// sumHoursForYear adds up one kind of hours over the entries of a year
+func (c *Client) sumHoursForYear(year int, hours func(db.TimesheetEntry) float64) (float64, error) {
+	entries, err := c.GetAllTimesheetEntries(0, 0)
+	if err != nil {
+		return 0, err
+	}
+
+	var total float64
+	yearStr := strconv.Itoa(year)
+	for _, entry := range entries {
+		if len(entry.Date) >= 4 && entry.Date[:4] == yearStr {
+			total += hours(entry)
+		}
+	}
+	return total, nil
+}
+
 // GetVacationCarryoverForYear retrieves carryover hours for a specific year
 func (c *Client) GetVacationCarryoverForYear(year int) (db.VacationCarryover, error) {
 	endpoint := fmt.Sprintf("/api/vacation-carryover?year=%d", year)
@@ -308,6 +460,21 @@ func (c *Client) GetTrainingBudgetEntriesForYear(year int) ([]db.TrainingBudgetE
 	return entries, nil
 }
 
+// GetTrainingBudgetSpentForYear adds up the cost of a year's training
+// budget entries
+func (c *Client) GetTrainingBudgetSpentForYear(year int) (db.TrainingBudgetSpent, error) {
+	entries, err := c.GetTrainingBudgetEntriesForYear(year)
+	if err != nil {
+		return db.TrainingBudgetSpent{}, err
+	}
+
+	spent := db.TrainingBudgetSpent{EntryCount: len(entries)}
+	for _, entry := range entries {
+		spent.Spent += entry.Cost_without_vat
+	}
+	return spent, nil
+}
+
 // AddTrainingBudgetEntry creates a new training budget entry
 func (c *Client) AddTrainingBudgetEntry(entry db.TrainingBudgetEntry) error {
 	_, err := c.makeRequest("POST", "/api/training-budget", entry)
@@ -555,6 +722,9 @@ func (c *Client) GetClientRateForDate(clientId int, date string) (db.ClientRate,
 	if !found {
 		return db.ClientRate{}, fmt.Errorf("no rate found for client %d on date %s", clientId, date)
 	}
+	if validRate.Ended(date) {
+		return db.ClientRate{}, fmt.Errorf("no rate found for client %d on date %s: rate ended on %s", clientId, date, validRate.EndDate)
+	}
 
 	return validRate, nil
 }
@@ -574,164 +744,172 @@ func (c *Client) GetClientRateByName(clientName string, date string) (float64, e
 	return rate.HourlyRate, nil
 }
 
-// Earnings Methods
+// Expense Methods
 
-// CalculateEarningsForYear calculates total earnings for a specific year
-func (c *Client) CalculateEarningsForYear(year int) (db.EarningsOverview, error) {
-	endpoint := fmt.Sprintf("/api/earnings?year=%d", year)
+// GetExpenses retrieves the expenses of a month, or of the whole year when
+// month is 0
+func (c *Client) GetExpenses(year, month int) ([]db.Expense, error) {
+	endpoint := fmt.Sprintf("/api/expenses?year=%d", year)
+	if month != 0 {
+		endpoint += fmt.Sprintf("&month=%d", month)
+	}
 	data, err := c.makeRequest("GET", endpoint, nil)
 	if err != nil {
-		return db.EarningsOverview{}, err
-	}
-
-	// The API returns formatted data, we need to parse it
-	var response struct {
-		Year          int    `json:"year"`
-		Month         int    `json:"month"`
-		TotalHours    int    `json:"total_hours"`
-		TotalEarnings string `json:"total_earnings"` // Formatted as Euro string
-		Entries       []struct {
-			Date        string `json:"date"`
-			ClientName  string `json:"client_name"`
-			ClientHours int    `json:"client_hours"`
-			HourlyRate  string `json:"hourly_rate"` // Formatted as Euro string
-			Earnings    string `json:"earnings"`    // Formatted as Euro string
-		} `json:"entries"`
+		return nil, err
 	}
 
-	if err := json.Unmarshal(data, &response); err != nil {
-		return db.EarningsOverview{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	var expenses []db.Expense
+	if err := json.Unmarshal(data, &expenses); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	return expenses, nil
+}
 
-	// Convert back to EarningsOverview with parsed Euro values
-	overview := db.EarningsOverview{
-		Year:       response.Year,
-		Month:      response.Month,
-		TotalHours: response.TotalHours,
+// GetExpense retrieves an expense by ID
+func (c *Client) GetExpense(id int) (db.Expense, error) {
+	data, err := c.makeRequest("GET", fmt.Sprintf("/api/expenses/%d", id), nil)
+	if err != nil {
+		return db.Expense{}, err
 	}
 
-	// Parse total earnings
-	totalEarnings, _ := parseEuroFromAPI(response.TotalEarnings)
-	overview.TotalEarnings = totalEarnings
-
-	// Parse entries
-	for _, entry := range response.Entries {
-		hourlyRate, _ := parseEuroFromAPI(entry.HourlyRate)
-		earnings, _ := parseEuroFromAPI(entry.Earnings)
-
-		overview.Entries = append(overview.Entries, db.EarningsEntry{
-			Date:        entry.Date,
-			ClientName:  entry.ClientName,
-			ClientHours: entry.ClientHours,
-			HourlyRate:  hourlyRate,
-			Earnings:    earnings,
-		})
+	var expense db.Expense
+	if err := json.Unmarshal(data, &expense); err != nil {
+		return db.Expense{}, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
-
-	return overview, nil
+	return expense, nil
 }
 
-// CalculateEarningsSummaryForYear calculates earnings summary grouped by client and rate
-func (c *Client) CalculateEarningsSummaryForYear(year int) (db.EarningsOverview, error) {
-	endpoint := fmt.Sprintf("/api/earnings?year=%d&summary=true", year)
-	data, err := c.makeRequest("GET", endpoint, nil)
+// AddExpense creates a new expense and returns its ID
+func (c *Client) AddExpense(expense db.Expense) (int, error) {
+	data, err := c.makeRequest("POST", "/api/expenses", expense)
 	if err != nil {
-		return db.EarningsOverview{}, err
-	}
-
-	// Same parsing logic as CalculateEarningsForYear
-	var response struct {
-		Year          int    `json:"year"`
-		Month         int    `json:"month"`
-		TotalHours    int    `json:"total_hours"`
-		TotalEarnings string `json:"total_earnings"`
-		Entries       []struct {
-			Date        string `json:"date"`
-			ClientName  string `json:"client_name"`
-			ClientHours int    `json:"client_hours"`
-			HourlyRate  string `json:"hourly_rate"`
-			Earnings    string `json:"earnings"`
-		} `json:"entries"`
+		return 0, err
 	}
 
-	if err := json.Unmarshal(data, &response); err != nil {
-		return db.EarningsOverview{}, fmt.Errorf("failed to unmarshal response: %w", err)
+	var created db.Expense
+	if err := json.Unmarshal(data, &created); err != nil {
+		return 0, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
+	return created.Id, nil
+}
 
-	// Convert back to EarningsOverview with parsed Euro values
-	overview := db.EarningsOverview{
-		Year:       response.Year,
-		Month:      response.Month,
-		TotalHours: response.TotalHours,
-	}
+// UpdateExpense updates an existing expense
+func (c *Client) UpdateExpense(expense db.Expense) error {
+	_, err := c.makeRequest("PUT", fmt.Sprintf("/api/expenses/%d", expense.Id), expense)
+	return err
+}
 
-	// Parse total earnings
-	totalEarnings, _ := parseEuroFromAPI(response.TotalEarnings)
-	overview.TotalEarnings = totalEarnings
+// DeleteExpense deletes an expense
+func (c *Client) DeleteExpense(id int) error {
+	_, err := c.makeRequest("DELETE", fmt.Sprintf("/api/expenses/%d", id), nil)
+	return err
+}
 
-	// Parse entries
-	for _, entry := range response.Entries {
-		hourlyRate, _ := parseEuroFromAPI(entry.HourlyRate)
-		earnings, _ := parseEuroFromAPI(entry.Earnings)
+// Earnings Methods
 
-		overview.Entries = append(overview.Entries, db.EarningsEntry{
-			Date:        entry.Date,
-			ClientName:  entry.ClientName,
-			ClientHours: entry.ClientHours,
-			HourlyRate:  hourlyRate,
-			Earnings:    earnings,
-		})
-	}
+// CalculateEarningsForYear calculates total earnings for a specific year
+func (c *Client) CalculateEarningsForYear(year int) (db.EarningsOverview, error) {
+	return c.getEarnings(fmt.Sprintf("/api/earnings?year=%d", year))
+}
 
-	return overview, nil
+// CalculateEarningsSummaryForYear calculates earnings summary grouped by client and rate
+func (c *Client) CalculateEarningsSummaryForYear(year int) (db.EarningsOverview, error) {
+	return c.getEarnings(fmt.Sprintf("/api/earnings?year=%d&summary=true", year))
 }
 
 // CalculateEarningsForMonth calculates total earnings for a specific month
 func (c *Client) CalculateEarningsForMonth(year int, month int) (db.EarningsOverview, error) {
-	endpoint := fmt.Sprintf("/api/earnings?year=%d&month=%d", year, month)
+	return c.getEarnings(fmt.Sprintf("/api/earnings?year=%d&month=%d", year, month))
+}
+
+// earningsResponse is the /api/earnings response. Amounts are formatted
+// money strings such as "€100,50" or "$1,080.00".
+type earningsResponse struct {
+	Year          int     `json:"year"`
+	Month         int     `json:"month"`
+	TotalHours    float64 `json:"total_hours"`
+	TotalEarnings string  `json:"total_earnings"`
+	Currency      string  `json:"currency"`
+	ByCurrency    []struct {
+		Currency   string  `json:"currency"`
+		TotalHours float64 `json:"total_hours"`
+		Earnings   string  `json:"earnings"`
+	} `json:"by_currency"`
+	Unconverted      []string `json:"unconverted"`
+	BillableExpenses string   `json:"billable_expenses"` // Only present when there are any
+	TotalInvoiced    string   `json:"total_invoiced"`
+	Entries          []struct {
+		Date        string  `json:"date"`
+		ClientName  string  `json:"client_name"`
+		ClientHours float64 `json:"client_hours"`
+		BilledHours float64 `json:"billed_hours"`
+		HourlyRate  string  `json:"hourly_rate"`
+		Earnings    string  `json:"earnings"`
+		Currency    string  `json:"currency"`
+
+		RegularHours     float64 `json:"regular_hours"`
+		OvertimeHours    float64 `json:"overtime_hours"`
+		RegularEarnings  string  `json:"regular_earnings"`
+		OvertimeEarnings string  `json:"overtime_earnings"`
+	} `json:"entries"`
+}
+
+// getEarnings fetches an earnings endpoint and parses the formatted amounts
+// back into an EarningsOverview
+func (c *Client) getEarnings(endpoint string) (db.EarningsOverview, error) {
 	data, err := c.makeRequest("GET", endpoint, nil)
 	if err != nil {
 		return db.EarningsOverview{}, err
 	}
 
-	// Same parsing logic as CalculateEarningsForYear
-	var response struct {
-		Year          int    `json:"year"`
-		Month         int    `json:"month"`
-		TotalHours    int    `json:"total_hours"`
-		TotalEarnings string `json:"total_earnings"`
-		Entries       []struct {
-			Date        string `json:"date"`
-			ClientName  string `json:"client_name"`
-			ClientHours int    `json:"client_hours"`
-			HourlyRate  string `json:"hourly_rate"`
-			Earnings    string `json:"earnings"`
-		} `json:"entries"`
-	}
-
+	var response earningsResponse
 	if err := json.Unmarshal(data, &response); err != nil {
 		return db.EarningsOverview{}, fmt.Errorf("failed to unmarshal response: %w", err)
 	}
 
 	overview := db.EarningsOverview{
-		Year:       response.Year,
-		Month:      response.Month,
-		TotalHours: response.TotalHours,
+		Year:        response.Year,
+		Month:       response.Month,
+		TotalHours:  response.TotalHours,
+		Currency:    response.Currency,
+		Unconverted: response.Unconverted,
 	}
 
-	totalEarnings, _ := parseEuroFromAPI(response.TotalEarnings)
+	totalEarnings, _ := parseMoneyFromAPI(response.TotalEarnings)
 	overview.TotalEarnings = totalEarnings
+	overview.TotalInvoiced = totalEarnings
+	if response.BillableExpenses != "" {
+		overview.BillableExpenses, _ = parseMoneyFromAPI(response.BillableExpenses)
+		overview.TotalInvoiced, _ = parseMoneyFromAPI(response.TotalInvoiced)
+	}
+
+	for _, total := range response.ByCurrency {
+		earnings, _ := parseMoneyFromAPI(total.Earnings)
+		overview.ByCurrency = append(overview.ByCurrency, db.CurrencyTotal{
+			Currency: total.Currency,
+			Hours:    total.TotalHours,
+			Earnings: earnings,
+		})
+	}
 
 	for _, entry := range response.Entries {
-		hourlyRate, _ := parseEuroFromAPI(entry.HourlyRate)
-		earnings, _ := parseEuroFromAPI(entry.Earnings)
+		hourlyRate, _ := parseMoneyFromAPI(entry.HourlyRate)
+		earnings, _ := parseMoneyFromAPI(entry.Earnings)
+		regularEarnings, _ := parseMoneyFromAPI(entry.RegularEarnings)
+		overtimeEarnings, _ := parseMoneyFromAPI(entry.OvertimeEarnings)
 
 		overview.Entries = append(overview.Entries, db.EarningsEntry{
-			Date:        entry.Date,
-			ClientName:  entry.ClientName,
-			ClientHours: entry.ClientHours,
-			HourlyRate:  hourlyRate,
-			Earnings:    earnings,
+			Date:             entry.Date,
+			ClientName:       entry.ClientName,
+			ClientHours:      entry.ClientHours,
+			BilledHours:      entry.BilledHours,
+			HourlyRate:       hourlyRate,
+			Earnings:         earnings,
+			Currency:         entry.Currency,
+			RegularHours:     entry.RegularHours,
+			OvertimeHours:    entry.OvertimeHours,
+			RegularEarnings:  regularEarnings,
+			OvertimeEarnings: overtimeEarnings,
 		})
 	}
 
@@ -756,23 +934,10 @@ func (c *Client) GetClientWithRates(clientId int) (db.ClientWithRates, error) {
 	}, nil
 }
 
-// parseEuroFromAPI parses a Euro string from the API (e.g., "€100,50") to float64
-func parseEuroFromAPI(euroStr string) (float64, error) {
-	// We can just use the existing ParseEuro function from utils
-	// But since we're in the api package and want to avoid circular imports,
-	// we'll implement a simple version here
-
-	// Remove € symbol (works with UTF-8)
-	cleanStr := strings.TrimSpace(euroStr)
-	cleanStr = strings.TrimPrefix(cleanStr, "€")
-	cleanStr = strings.TrimSpace(cleanStr)
-
-	// Replace comma with dot
-	cleanStr = strings.Replace(cleanStr, ",", ".", 1)
-
-	var value float64
-	_, err := fmt.Sscanf(cleanStr, "%f", &value)
-	return value, err
+// parseMoneyFromAPI parses a money string from the API (e.g. "€100,50" or
+// "$1,234.50") to float64
+func parseMoneyFromAPI(money string) (float64, error) {
+	return utils.ParseMoney(money)
 }
 
 // Ping checks if the API is accessible
@@ -5,6 +5,7 @@ import (
 	"net/http"
 	"net/http/httptest"
 	"os"
+	"strings"
 	"testing"
 	"time"
 	"timesheet/internal/db"
@@ -99,6 +100,41 @@ func TestClient_makeRequest(t *testing.T) {
 	}
 }
 
+func TestClient_makeRequestNonJSON(t *testing.T) {
+	page := "<html>\n  <head><title>502 Bad Gateway</title></head>\n  <body>" + strings.Repeat("x", 500) + "</body>\n</html>"
+
+	tests := []struct {
+		name     string
+		status   int
+		expected string
+	}{
+		{"Proxy error page", http.StatusBadGateway, "remote returned 502: <html> <head><title>502 Bad Gateway</title>"},
+		{"HTML with success status", http.StatusOK, "remote returned 200 with non-JSON content (text/html; charset=utf-8): <html>"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", "text/html; charset=utf-8")
+				w.WriteHeader(tt.status)
+				w.Write([]byte(page))
+			}))
+			defer server.Close()
+
+			_, err := NewClient(server.URL).makeRequest("GET", "/api/timesheet", nil)
+			if err == nil {
+				t.Fatal("Expected error but got none")
+			}
+			if !strings.HasPrefix(err.Error(), tt.expected) {
+				t.Errorf("Expected error starting with %q, got %q", tt.expected, err.Error())
+			}
+			if len(err.Error()) > len(tt.expected)+maxBodySnippet {
+				t.Errorf("Expected the body to be truncated, got %d characters", len(err.Error()))
+			}
+		})
+	}
+}
+
 func TestClient_GetAllTimesheetEntries(t *testing.T) {
 	entries := []db.TimesheetEntry{
 		{Id: 1, Date: "2024-01-15", Client_name: "Client A", Client_hours: 8},
@@ -135,29 +171,32 @@ func TestClient_GetAllTimesheetEntries(t *testing.T) {
 	}
 }
 
-func TestClient_GetTimesheetEntryByDate(t *testing.T) {
-	entries := []db.TimesheetEntry{
-		{Id: 1, Date: "2024-01-15", Client_name: "Client A"},
-	}
-
+func TestClient_GetTimesheetEntriesByDate(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
-		json.NewEncoder(w).Encode(entries)
+		if r.URL.Path != "/api/timesheet/date/2024-01-15" {
+			w.Write([]byte(`[]`))
+			return
+		}
+		json.NewEncoder(w).Encode([]db.TimesheetEntry{
+			{Id: 1, Date: "2024-01-15", Client_name: "Client A"},
+			{Id: 2, Date: "2024-01-15", Client_name: "Client B"},
+		})
 	}))
 	defer server.Close()
 
 	client := NewClient(server.URL)
-	entry, err := client.GetTimesheetEntryByDate("2024-01-15")
+	entries, err := client.GetTimesheetEntriesByDate("2024-01-15")
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
-	if entry.Date != "2024-01-15" {
-		t.Errorf("Expected date 2024-01-15, got %s", entry.Date)
+	if len(entries) != 2 || entries[1].Client_name != "Client B" {
+		t.Errorf("Expected both client blocks, got %+v", entries)
 	}
 
-	// Test not found
-	_, err = client.GetTimesheetEntryByDate("2024-01-16")
-	if err == nil {
-		t.Error("Expected error for non-existent date")
+	// A day without entries
+	entries, err = client.GetTimesheetEntriesByDate("2024-01-16")
+	if err != nil || len(entries) != 0 {
+		t.Errorf("Expected no entries for a day without any, got %+v, %v", entries, err)
 	}
 }
 
@@ -224,14 +263,12 @@ func TestClient_DeleteTimesheetEntry(t *testing.T) {
 }
 
 func TestClient_DeleteTimesheetEntryByDate(t *testing.T) {
-	entries := []db.TimesheetEntry{
-		{Id: 1, Date: "2024-01-15"},
-	}
-
+	var deleted []string
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		if r.Method == "GET" {
-			json.NewEncoder(w).Encode(entries)
+			json.NewEncoder(w).Encode([]db.TimesheetEntry{{Id: 1, Date: "2024-01-15"}, {Id: 2, Date: "2024-01-15"}})
 		} else if r.Method == "DELETE" {
+			deleted = append(deleted, r.URL.Path)
 			w.WriteHeader(http.StatusOK)
 		}
 	}))
@@ -242,6 +279,28 @@ func TestClient_DeleteTimesheetEntryByDate(t *testing.T) {
 	if err != nil {
 		t.Fatalf("Unexpected error: %v", err)
 	}
+	if len(deleted) != 2 || deleted[0] != "/api/timesheet/1" || deleted[1] != "/api/timesheet/2" {
+		t.Errorf("Expected every block of the date to be deleted, got %v", deleted)
+	}
+}
+
+func TestClient_DeleteTimesheetEntriesForMonth(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "DELETE" || r.URL.Path != "/api/timesheet/month" || r.URL.RawQuery != "year=2024&month=3" {
+			t.Errorf("Unexpected request %s %s", r.Method, r.URL)
+		}
+		json.NewEncoder(w).Encode(map[string]int{"deleted": 4})
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	deleted, err := client.DeleteTimesheetEntriesForMonth(2024, time.March)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if deleted != 4 {
+		t.Errorf("Expected 4 deleted, got %d", deleted)
+	}
 }
 
 func TestClient_GetLastClientName(t *testing.T) {
@@ -320,7 +379,7 @@ func TestClient_GetVacationHoursForYear(t *testing.T) {
 		t.Fatalf("Unexpected error: %v", err)
 	}
 	if total != 12 {
-		t.Errorf("Expected 12 hours, got %d", total)
+		t.Errorf("Expected 12 hours, got %g", total)
 	}
 }
 
@@ -347,6 +406,27 @@ func TestClient_GetTrainingBudgetEntriesForYear(t *testing.T) {
 	}
 }
 
+func TestClient_GetTrainingBudgetSpentForYear(t *testing.T) {
+	entries := []db.TrainingBudgetEntry{
+		{Id: 1, Date: "2024-01-15", Training_name: "Training A", Hours: 8, Cost_without_vat: 100.0},
+		{Id: 2, Date: "2024-06-01", Training_name: "Training B", Hours: 4, Cost_without_vat: 25.5},
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(entries)
+	}))
+	defer server.Close()
+
+	client := NewClient(server.URL)
+	spent, err := client.GetTrainingBudgetSpentForYear(2024)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if spent.Spent != 125.5 || spent.EntryCount != 2 {
+		t.Errorf("Expected 125.5 spent on 2 entries, got %+v", spent)
+	}
+}
+
 func TestClient_AddTrainingBudgetEntry(t *testing.T) {
 	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.WriteHeader(http.StatusCreated)
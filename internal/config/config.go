@@ -10,6 +10,7 @@ import (
 	"strings"
 	"time"
 	"timesheet/internal/dbcheck"
+	"timesheet/internal/exitcode"
 	"timesheet/internal/logging"
 	"timesheet/internal/workschedule"
 
@@ -43,6 +44,13 @@ type TrainingHours struct {
 type VacationHours struct {
 	YearlyTarget int    `json:"yearlyTarget"`
 	Category     string `json:"category"`
+	MaxCarryover int    `json:"maxCarryover,omitempty"` // Most hours a rollover carries into the next year; 0 carries everything
+}
+
+// IdleAutoFill configures filling empty working days with idle hours
+type IdleAutoFill struct {
+	Enabled bool `json:"enabled"`
+	Hours   int  `json:"hours"` // Idle hours per day; 0 uses the work schedule's hours for that weekday
 }
 
 // WorkSchedule represents the expected hours per weekday. Used to compute the
@@ -95,6 +103,12 @@ type Config struct {
 	// API Server Configuration
 	StartAPIServer bool `json:"startAPIServer"`
 	APIPort        int  `json:"apiPort"`
+	EnableMetrics  bool `json:"enableMetrics"` // Expose Prometheus metrics on /metrics
+	// Reject API requests with unknown query parameters (400) instead of
+	// ignoring them. Clients can also opt in per request with ?strict=true.
+	StrictQueryParams bool `json:"strictQueryParams"`
+	// Log format: "text" (default) or "json" for one JSON object per line
+	LogFormat string `json:"logFormat,omitempty"`
 
 	// API Client Configuration (for remote mode)
 	APIMode    string `json:"apiMode"`    // "local", "dual", or "remote" (default: "local")
@@ -104,6 +118,13 @@ type Config struct {
 	DBLocation  string `json:"dbLocation"`
 	DBType      string `json:"dbType"`      // "sqlite" (default) or "postgres"
 	PostgresURL string `json:"postgresURL"` // PostgreSQL connection string
+	// How long (milliseconds) a SQLite write waits for a lock held by
+	// another process, e.g. the API server and the TUI, before failing with
+	// "database is locked". Default 5000.
+	SQLiteBusyTimeout int `json:"sqliteBusyTimeout,omitempty"`
+	// Most connections timesheetz keeps open to the SQLite database.
+	// Default 1, which serializes this process's writes.
+	SQLiteMaxOpenConns int `json:"sqliteMaxOpenConns,omitempty"`
 
 	// Development Settings
 	DevelopmentMode bool `json:"developmentMode"`
@@ -111,6 +132,32 @@ type Config struct {
 	// Document Settings
 	SendDocumentType string `json:"sendDocumentType"`
 	ExportLanguage   string `json:"exportLanguage"` // "en" or "nl" (default: "en")
+	ExportDir        string `json:"exportDir"`      // Directory exports are saved to (default: current directory)
+	// Exports in exportDir older than this many days are removed on startup
+	// (0 keeps everything). With archiveOldExports they're moved to
+	// exportDir/archive instead.
+	ExportRetentionDays int  `json:"exportRetentionDays"`
+	ArchiveOldExports   bool `json:"archiveOldExports"`
+
+	// Earnings currencies. Clients billed in another currency are converted
+	// to baseCurrency (default EUR) using exchangeRates: the value of one unit
+	// of each currency in the base currency, e.g. {"USD": 0.92}.
+	BaseCurrency  string             `json:"baseCurrency,omitempty"`
+	ExchangeRates map[string]float64 `json:"exchangeRates,omitempty"`
+	// Unit client hours are invoiced in: "hour" (default), "quarter-hour",
+	// "half-day" or "day" (standardDailyHours). Each day's hours are rounded
+	// up to whole units before they're multiplied by the rate, e.g. 6 hours
+	// bill as a full day. clientInvoiceUnits overrides it per client name.
+	InvoiceUnit        string            `json:"invoiceUnit,omitempty"`
+	ClientInvoiceUnits map[string]string `json:"clientInvoiceUnits,omitempty"`
+	// Billed hours past overtimeThreshold on a day (per client) earn the
+	// rate times overtimeMultiplier (default 1.5). A threshold of 0 turns
+	// overtime off.
+	OvertimeThreshold  float64 `json:"overtimeThreshold,omitempty"`
+	OvertimeMultiplier float64 `json:"overtimeMultiplier,omitempty"`
+	// Decimal places hourly rates are rounded to when saved (0-4). Unset
+	// means 2, i.e. cents.
+	RatePrecision *int `json:"ratePrecision,omitempty"`
 
 	// Email Configuration
 	SendToOthers   bool   `json:"sendToOthers"`
@@ -125,9 +172,58 @@ type Config struct {
 	// Vacation Hours Configuration
 	VacationHours VacationHours `json:"vacationHours"`
 
+	// Optional yearly targets for sick and idle hours. The Info tab shows
+	// the hours used against them and what's left; 0 sets no target.
+	SickTarget int `json:"sickTarget,omitempty"`
+	IdleTarget int `json:"idleTarget,omitempty"`
+
 	// Work Schedule (expected hours per weekday). Drives the monthly target
 	// shown in the timesheet footer.
 	WorkSchedule WorkSchedule `json:"workSchedule"`
+	// Weekdays you work (e.g. ["monday", "tuesday", "wednesday", "thursday"]).
+	// Other weekdays are expected to stay empty: they count towards no target
+	// and are never reported as missing. Empty means every weekday with hours
+	// in workSchedule. Weekend styling is unaffected.
+	WorkingDays []string `json:"workingDays,omitempty"`
+	// First day of the week for weekly totals (e.g. "sunday"). Default monday.
+	WeekStart string `json:"weekStart,omitempty"`
+	// Weekdays marked as weekend in the timesheet and exports (e.g.
+	// ["friday", "saturday"], or ["sunday"] for a one-day weekend). Default
+	// saturday and sunday.
+	WeekendDays []string `json:"weekendDays,omitempty"`
+	// Hour (0-23) a new day starts for --add and the TUI's "today" key.
+	// Entries made before it count towards the previous day, e.g. 4 for
+	// working past midnight. Default 0 (midnight).
+	DayBoundaryHour int `json:"dayBoundaryHour,omitempty"`
+	// What happens to an entry whose hours add up to more than 24: "reject"
+	// (default) refuses it, "clamp" trims it to 24 hours with a warning and
+	// "allow" stores it as entered.
+	OverLimitBehavior string `json:"overLimitBehavior,omitempty"`
+	// When set, the timesheet form asks for a training's name and cost when
+	// training hours are logged and saves a matching training budget entry
+	// for the same day along with the entry.
+	LinkTrainingBudget bool `json:"linkTrainingBudget,omitempty"`
+	// Yearly training budget, in the currency of the entries' cost without
+	// VAT. /api/training-budget/summary reports what's left of it; 0 sets
+	// no target.
+	TrainingBudgetTarget float64 `json:"trainingBudgetTarget,omitempty"`
+	// Percentage (1-100) of a client's monthly or total hour cap at which
+	// logging hours starts to warn. Default 90.
+	CapWarningPercent int `json:"capWarningPercent,omitempty"`
+	// Tab the TUI opens on (e.g. "earnings"). Empty reopens the tab that was
+	// active when the TUI was last closed.
+	DefaultView string `json:"defaultView,omitempty"`
+	// Length of a standard working day in hours (default 8). Used where a
+	// "full day" is needed and the work schedule doesn't say, e.g. a
+	// vacation day on a weekday without scheduled hours.
+	StandardDailyHours int `json:"standardDailyHours,omitempty"`
+	// Record idle hours on working days that have nothing logged. When
+	// enabled, the previous month is filled on startup; the API can fill a
+	// month on demand.
+	IdleAutoFill IdleAutoFill `json:"idleAutoFill"`
+	// Public holiday calendar the timesheet's holiday key imports: a
+	// built-in country code ("nl") or the path of an iCal/ICS file.
+	HolidaySource string `json:"holidaySource,omitempty"`
 }
 
 // SetRuntimeDevMode sets the runtime development mode
@@ -146,6 +242,12 @@ func SetRuntimePort(port int) {
 	logging.Log("Runtime API port set to: %v", port)
 }
 
+// noPortMessage explains how to set the API port; %s is the config file name
+const noPortMessage = `Error: No port specified. Please either:
+  1. Add 'apiPort' to your %s file
+  2. Run the program with --port flag
+  3. Run the program with --no-tui flag if you don't need the API server`
+
 // GetAPIPort returns the API port to use
 func GetAPIPort() int {
 	// Check runtime flag first
@@ -155,37 +257,28 @@ func GetAPIPort() int {
 
 	// Fall back to config file
 	configPath := GetConfigPath()
-	configFile, err := os.ReadFile(configPath)
+	configFile, err := readConfigJSON(configPath)
 	if err != nil {
 		// In non-interactive mode (like Docker), default to 8080 instead of exiting
 		if os.Getenv("TIMESHEETZ_NO_TUI") == "true" || !term.IsTerminal(int(os.Stdin.Fd())) {
 			logging.Log("Warning: Could not read config file, defaulting to port 8080")
 			return 8080
 		}
-		fmt.Println("Error: No port specified. Please either:")
-		fmt.Println("  1. Add 'apiPort' to your config.json file")
-		fmt.Println("  2. Run the program with --port flag")
-		fmt.Println("  3. Run the program with --no-tui flag if you don't need the API server")
-		os.Exit(1)
+		exitcode.Fail(exitcode.Config, noPortMessage, filepath.Base(configPath))
 	}
 	var config Config
 	if err := json.Unmarshal(configFile, &config); err != nil {
-		fmt.Println("Error: Invalid config.json file. Please check your configuration.")
-		os.Exit(1)
+		exitcode.Fail(exitcode.Config, "Error: Invalid %s file. Please check your configuration.", filepath.Base(configPath))
 	}
 	if config.APIPort == 0 {
-		fmt.Println("Error: No port specified. Please either:")
-		fmt.Println("  1. Add 'apiPort' to your config.json file")
-		fmt.Println("  2. Run the program with --port flag")
-		fmt.Println("  3. Run the program with --no-tui flag if you don't need the API server")
-		os.Exit(1)
+		exitcode.Fail(exitcode.Config, noPortMessage, filepath.Base(configPath))
 	}
 	return config.APIPort
 }
 
 func GetStartAPIServer() bool {
 	configPath := GetConfigPath()
-	configFile, err := os.ReadFile(configPath)
+	configFile, err := readConfigJSON(configPath)
 	if err != nil {
 		fmt.Println("Error reading config file:", err)
 		return false
@@ -200,6 +293,49 @@ func GetStartAPIServer() bool {
 	return config.StartAPIServer
 }
 
+// GetMetricsEnabled reports whether the API server should expose /metrics.
+// TIMESHEETZ_METRICS=true|false overrides the enableMetrics config setting.
+func GetMetricsEnabled() bool {
+	if envMetrics := os.Getenv("TIMESHEETZ_METRICS"); envMetrics != "" {
+		return envMetrics == "true"
+	}
+
+	config, err := GetConfig()
+	if err != nil {
+		return false
+	}
+	return config.EnableMetrics
+}
+
+// GetLogFormat returns the log format, "text" or "json".
+// TIMESHEETZ_LOG_FORMAT overrides the logFormat config setting.
+func GetLogFormat() string {
+	format := os.Getenv("TIMESHEETZ_LOG_FORMAT")
+	if format == "" {
+		if config, err := GetConfig(); err == nil {
+			format = config.LogFormat
+		}
+	}
+	if strings.EqualFold(strings.TrimSpace(format), "json") {
+		return "json"
+	}
+	return "text"
+}
+
+// GetStrictQueryParams reports whether the API rejects unknown query
+// parameters. TIMESHEETZ_STRICT_QUERY=true|false overrides the config file.
+func GetStrictQueryParams() bool {
+	if envStrict := os.Getenv("TIMESHEETZ_STRICT_QUERY"); envStrict != "" {
+		return envStrict == "true"
+	}
+
+	config, err := GetConfig()
+	if err != nil {
+		return false
+	}
+	return config.StrictQueryParams
+}
+
 func checkConfig() bool {
 	// Check if the config file exists
 	_, err := os.Stat("config.json")
@@ -214,7 +350,7 @@ func checkConfig() bool {
 // GetEmailConfig reads the configuration file and returns email-related settings
 func GetEmailConfig() (name string, companysendToOthers bool, recipientEmail, senderEmail, replyToEmail, resendAPIKey string, err error) {
 	configPath := GetConfigPath()
-	configFile, err := os.ReadFile(configPath)
+	configFile, err := readConfigJSON(configPath)
 	if err != nil {
 		return "", false, "", "", "", "", fmt.Errorf("error reading config file: %w", err)
 	}
@@ -230,7 +366,7 @@ func GetEmailConfig() (name string, companysendToOthers bool, recipientEmail, se
 
 func GetDocumentType() string {
 	configPath := GetConfigPath()
-	configFile, err := os.ReadFile(configPath)
+	configFile, err := readConfigJSON(configPath)
 	if err != nil {
 		log.Printf("error reading config file: %v", err)
 		return ""
@@ -247,7 +383,7 @@ func GetDocumentType() string {
 
 func GetExportLanguage() string {
 	configPath := GetConfigPath()
-	configFile, err := os.ReadFile(configPath)
+	configFile, err := readConfigJSON(configPath)
 	if err != nil {
 		return "en"
 	}
@@ -263,9 +399,170 @@ func GetExportLanguage() string {
 	return config.ExportLanguage
 }
 
+// GetExportDir returns the directory exports are saved to, or "" for the
+// current directory. TIMESHEETZ_EXPORT_DIR overrides the exportDir setting.
+func GetExportDir() string {
+	dir := os.Getenv("TIMESHEETZ_EXPORT_DIR")
+	if dir == "" {
+		config, err := GetConfig()
+		if err != nil {
+			return ""
+		}
+		dir = config.ExportDir
+	}
+
+	// Expand ~ in path if present
+	if strings.HasPrefix(dir, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			dir = filepath.Join(homeDir, dir[2:])
+		}
+	}
+	return dir
+}
+
+// GetExportRetention returns how many days exports are kept (0 = forever)
+// and whether older exports are archived rather than deleted
+func GetExportRetention() (days int, archive bool) {
+	config, err := GetConfig()
+	if err != nil {
+		return 0, false
+	}
+	return config.ExportRetentionDays, config.ArchiveOldExports
+}
+
+// GetBaseCurrency returns the currency earnings totals are reported in
+func GetBaseCurrency() string {
+	config, err := GetConfig()
+	if err != nil || strings.TrimSpace(config.BaseCurrency) == "" {
+		return "EUR"
+	}
+	return strings.ToUpper(strings.TrimSpace(config.BaseCurrency))
+}
+
+// GetExchangeRates returns the configured exchange rates keyed by upper-case
+// currency code. Rates that aren't positive are ignored.
+func GetExchangeRates() map[string]float64 {
+	rates := map[string]float64{}
+	config, err := GetConfig()
+	if err != nil {
+		return rates
+	}
+	for code, rate := range config.ExchangeRates {
+		if rate <= 0 {
+			log.Printf("Ignoring exchange rate for %s: must be positive", code)
+			continue
+		}
+		rates[strings.ToUpper(strings.TrimSpace(code))] = rate
+	}
+	return rates
+}
+
+// Invoice units client hours can be billed in
+const (
+	InvoiceUnitHour        = "hour"
+	InvoiceUnitQuarterHour = "quarter-hour"
+	InvoiceUnitHalfDay     = "half-day"
+	InvoiceUnitDay         = "day"
+)
+
+// InvoiceUnits holds the invoice unit of each client
+type InvoiceUnits struct {
+	Default string
+	Clients map[string]string // Client name -> unit, for clients that differ
+}
+
+// For returns the invoice unit of a client
+func (u InvoiceUnits) For(clientName string) string {
+	if unit, ok := u.Clients[clientName]; ok {
+		return unit
+	}
+	return u.Default
+}
+
+// normalizeInvoiceUnit lower-cases a unit and checks it's known
+func normalizeInvoiceUnit(unit string) (string, bool) {
+	unit = strings.ToLower(strings.TrimSpace(unit))
+	switch unit {
+	case InvoiceUnitHour, InvoiceUnitQuarterHour, InvoiceUnitHalfDay, InvoiceUnitDay:
+		return unit, true
+	}
+	return "", false
+}
+
+// GetInvoiceUnits returns the configured invoice units. Unknown units are
+// logged and ignored, so those hours are billed in the default unit (or by
+// the hour).
+func GetInvoiceUnits() InvoiceUnits {
+	units := InvoiceUnits{Default: InvoiceUnitHour, Clients: map[string]string{}}
+	cfg, err := GetConfig()
+	if err != nil {
+		return units
+	}
+	if cfg.InvoiceUnit != "" {
+		if unit, ok := normalizeInvoiceUnit(cfg.InvoiceUnit); ok {
+			units.Default = unit
+		} else {
+			log.Printf("Ignoring invoiceUnit %q: must be hour, quarter-hour, half-day or day", cfg.InvoiceUnit)
+		}
+	}
+	for client, unit := range cfg.ClientInvoiceUnits {
+		normalized, ok := normalizeInvoiceUnit(unit)
+		if !ok {
+			log.Printf("Ignoring invoice unit %q for %s: must be hour, quarter-hour, half-day or day", unit, client)
+			continue
+		}
+		units.Clients[client] = normalized
+	}
+	return units
+}
+
+// DefaultOvertimeMultiplier is the factor overtime hours are billed at when
+// overtimeMultiplier isn't set
+const DefaultOvertimeMultiplier = 1.5
+
+// GetOvertime returns the daily hours past which client hours are overtime
+// (0 when overtime is off) and the factor their rate is multiplied by
+func GetOvertime() (threshold, multiplier float64) {
+	cfg, err := GetConfig()
+	if err != nil || cfg.OvertimeThreshold == 0 {
+		return 0, DefaultOvertimeMultiplier
+	}
+	if cfg.OvertimeThreshold < 0 {
+		log.Printf("Ignoring overtimeThreshold %g: must be positive", cfg.OvertimeThreshold)
+		return 0, DefaultOvertimeMultiplier
+	}
+	multiplier = DefaultOvertimeMultiplier
+	if cfg.OvertimeMultiplier < 0 {
+		log.Printf("Ignoring overtimeMultiplier %g: must be positive", cfg.OvertimeMultiplier)
+	} else if cfg.OvertimeMultiplier > 0 {
+		multiplier = cfg.OvertimeMultiplier
+	}
+	return cfg.OvertimeThreshold, multiplier
+}
+
+// DefaultRatePrecision is the number of decimals hourly rates are rounded
+// to when ratePrecision isn't set
+const DefaultRatePrecision = 2
+
+// maxRatePrecision is the most decimals ratePrecision may ask for
+const maxRatePrecision = 4
+
+// GetRatePrecision returns the number of decimals hourly rates are rounded to
+func GetRatePrecision() int {
+	cfg, err := GetConfig()
+	if err != nil || cfg.RatePrecision == nil {
+		return DefaultRatePrecision
+	}
+	if p := *cfg.RatePrecision; p < 0 || p > maxRatePrecision {
+		log.Printf("Ignoring ratePrecision %d: must be between 0 and %d", p, maxRatePrecision)
+		return DefaultRatePrecision
+	}
+	return *cfg.RatePrecision
+}
+
 func GetUserConfig() (name string, companyName string, freeSpeech string, err error) {
 	configPath := GetConfigPath()
-	configFile, err := os.ReadFile(configPath)
+	configFile, err := readConfigJSON(configPath)
 	if err != nil {
 		return "", "", "", fmt.Errorf("error reading config file: %w", err)
 	}
@@ -568,6 +865,7 @@ func RequireConfig() {
 						Options(
 							huh.NewOption("PDF", "pdf"),
 							huh.NewOption("Excel", "excel"),
+							huh.NewOption("CSV", "csv"),
 						).
 						Value(&config.SendDocumentType),
 				),
@@ -641,31 +939,27 @@ func RequireConfig() {
 
 			err := form.Run()
 			if err != nil {
-				fmt.Println("Error running form:", err)
-				os.Exit(1)
+				exitcode.Fail(exitcode.Config, "Error running form: %v", err)
 			}
 
 			// Convert port string to integer
 			port, err := strconv.Atoi(portStr)
 			if err != nil {
-				fmt.Println("Error: Invalid port number")
-				os.Exit(1)
+				exitcode.Fail(exitcode.Config, "Error: Invalid port number")
 			}
 			config.APIPort = port
 
 			// Convert training hours string to integer
 			trainingHours, err := strconv.Atoi(trainingHoursStr)
 			if err != nil {
-				fmt.Println("Error: Invalid training hours number")
-				os.Exit(1)
+				exitcode.Fail(exitcode.Config, "Error: Invalid training hours number")
 			}
 			config.TrainingHours.YearlyTarget = trainingHours
 
 			// Convert vacation hours string to integer
 			vacationHours, err := strconv.Atoi(vacationHoursStr)
 			if err != nil {
-				fmt.Println("Error: Invalid vacation hours number")
-				os.Exit(1)
+				exitcode.Fail(exitcode.Config, "Error: Invalid vacation hours number")
 			}
 			config.VacationHours.YearlyTarget = vacationHours
 
@@ -732,7 +1026,8 @@ func RequireConfig() {
 }
 
 // GetConfigPath returns the path to the config file
-// Uses XDG Base Directory Specification: ~/.config/timesheetz/config.json
+// Uses XDG Base Directory Specification: ~/.config/timesheetz/config.json, or
+// config.toml / config.yaml when there's no config.json.
 // Tests can override this via SetConfigPathOverride.
 func GetConfigPath() string {
 	if configPathOverride != "" {
@@ -740,9 +1035,9 @@ func GetConfigPath() string {
 	}
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		log.Fatalf("Failed to get user home directory: %v", err)
+		exitcode.Fail(exitcode.Config, "Failed to get user home directory: %v", err)
 	}
-	return filepath.Join(homeDir, ".config", "timesheetz", "config.json")
+	return findConfigFile(filepath.Join(homeDir, ".config", "timesheetz"))
 }
 
 // SaveConfig saves the configuration to a file
@@ -755,7 +1050,9 @@ func SaveConfig(config Config) error {
 		return fmt.Errorf("failed to create config directory: %w", err)
 	}
 
-	configJSON, err := json.MarshalIndent(config, "", "  ")
+	// Written in the format of the config file, JSON unless the user
+	// created a config.toml or config.yaml
+	configData, err := encodeConfig(configPath, config)
 	if err != nil {
 		return fmt.Errorf("failed to marshal config: %w", err)
 	}
@@ -767,7 +1064,7 @@ func SaveConfig(config Config) error {
 	if strings.TrimSpace(config.PostgresURL) != "" {
 		perm = 0600
 	}
-	if err := os.WriteFile(configPath, configJSON, perm); err != nil {
+	if err := os.WriteFile(configPath, configData, perm); err != nil {
 		return fmt.Errorf("failed to write config file: %w", err)
 	}
 	// os.WriteFile only sets perms on creation. Force perms if the file
@@ -789,7 +1086,7 @@ func GetDevelopmentMode() bool {
 
 	// Fall back to config file
 	configPath := GetConfigPath()
-	configFile, err := os.ReadFile(configPath)
+	configFile, err := readConfigJSON(configPath)
 	if err != nil {
 		log.Printf("error reading config file: %v", err)
 		return false
@@ -818,7 +1115,7 @@ func GetConfig() (Config, error) {
 		"configPath": configPath,
 	}
 
-	configFile, err := os.ReadFile(configPath)
+	configFile, err := readConfigJSON(configPath)
 	if err != nil {
 		debugInfo["error"] = fmt.Sprintf("Error reading config file: %v", err)
 		writeDebugToFile(debugInfo)
@@ -853,17 +1150,28 @@ func writeDebugToFile(debugInfo map[string]interface{}) {
 	os.WriteFile(debugPath, debugJSON, 0644)
 }
 
-// GetDBPath returns the path to the database file, using config if set
+// DBPathEnv names the environment variable that overrides the database
+// location, e.g. with a volume mounted in a container
+const DBPathEnv = "TIMESHEETZ_DB_PATH"
+
+// GetDBPathFromEnv returns the database location set in DBPathEnv with ~
+// expanded, or "" when it isn't set
+func GetDBPathFromEnv() string {
+	dbPath := os.Getenv(DBPathEnv)
+	if strings.HasPrefix(dbPath, "~/") {
+		homeDir, err := os.UserHomeDir()
+		if err == nil {
+			dbPath = filepath.Join(homeDir, dbPath[2:])
+		}
+	}
+	return dbPath
+}
+
+// GetDBPath returns the path to the database file: TIMESHEETZ_DB_PATH, then
+// the dbLocation of the config, then ~/.local/share/timesheetz/
 func GetDBPath() string {
 	// Check environment variable first (useful for Docker/containerized deployments)
-	if dbPath := os.Getenv("TIMESHEETZ_DB_PATH"); dbPath != "" {
-		// Expand ~ in path if present
-		if strings.HasPrefix(dbPath, "~/") {
-			homeDir, err := os.UserHomeDir()
-			if err == nil {
-				dbPath = filepath.Join(homeDir, dbPath[2:])
-			}
-		}
+	if dbPath := GetDBPathFromEnv(); dbPath != "" {
 		return dbPath
 	}
 
@@ -883,11 +1191,17 @@ func GetDBPath() string {
 	// Default location: ~/.local/share/timesheetz/
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		log.Fatalf("Failed to get user home directory: %v", err)
+		exitcode.Fail(exitcode.Config, "Failed to get user home directory: %v", err)
 	}
 	return filepath.Join(homeDir, ".local", "share", "timesheetz", "timesheet.db")
 }
 
+// GetReceiptsDir returns the directory where training budget receipts are
+// stored, next to the database file
+func GetReceiptsDir() string {
+	return filepath.Join(filepath.Dir(GetDBPath()), "receipts")
+}
+
 // GetAPIMode returns the API mode: "local", "dual", or "remote"
 func GetAPIMode() string {
 	// Check environment variable first
@@ -999,7 +1313,8 @@ func GetDBType() string {
 
 // GetWorkSchedule returns the user's weekly schedule. Falls back to the
 // default (Mon/Tue/Wed/Fri × 9) when no schedule is configured (e.g. older
-// config files written before this field existed).
+// config files written before this field existed). Weekdays missing from
+// workingDays, when set, get zero hours.
 func GetWorkSchedule() workschedule.Schedule {
 	cfg, err := GetConfig()
 	if err != nil {
@@ -1007,9 +1322,211 @@ func GetWorkSchedule() workschedule.Schedule {
 	}
 	s := cfg.WorkSchedule.ToSchedule()
 	if s.IsZero() {
-		return workschedule.Default()
+		s = workschedule.Default()
+	}
+	return s.WithWorkingDays(parseWeekdays("workingDays", cfg.WorkingDays))
+}
+
+// GetWeekStart returns the configured first day of the week (default Monday)
+func GetWeekStart() time.Weekday {
+	cfg, err := GetConfig()
+	if err != nil || cfg.WeekStart == "" {
+		return time.Monday
+	}
+	d, err := workschedule.ParseWeekday(cfg.WeekStart)
+	if err != nil {
+		log.Printf("Ignoring weekStart: %v", err)
+		return time.Monday
+	}
+	return d
+}
+
+// GetWeekendDays returns the weekdays marked as weekend (default Saturday
+// and Sunday). A list without any recognized day falls back to the default.
+func GetWeekendDays() []time.Weekday {
+	cfg, err := GetConfig()
+	if err != nil || len(cfg.WeekendDays) == 0 {
+		return []time.Weekday{time.Saturday, time.Sunday}
+	}
+	days := parseWeekdays("weekendDays", cfg.WeekendDays)
+	if len(days) == 0 {
+		return []time.Weekday{time.Saturday, time.Sunday}
+	}
+	return days
+}
+
+// GetDefaultView returns the configured tab the TUI opens on, lowercased,
+// or "" when none is set. The TUI checks that the view exists.
+func GetDefaultView() string {
+	cfg, err := GetConfig()
+	if err != nil {
+		return ""
+	}
+	return strings.ToLower(strings.TrimSpace(cfg.DefaultView))
+}
+
+// GetDayBoundaryHour returns the hour a new day starts for new entries.
+// Hours outside 0-23 are ignored.
+func GetDayBoundaryHour() int {
+	cfg, err := GetConfig()
+	if err != nil {
+		return 0
+	}
+	if cfg.DayBoundaryHour < 0 || cfg.DayBoundaryHour > 23 {
+		log.Printf("Ignoring dayBoundaryHour %d: must be between 0 and 23", cfg.DayBoundaryHour)
+		return 0
+	}
+	return cfg.DayBoundaryHour
+}
+
+// Behaviors for entries with more than 24 hours in a day
+const (
+	OverLimitReject = "reject"
+	OverLimitClamp  = "clamp"
+	OverLimitAllow  = "allow"
+)
+
+// GetOverLimitBehavior returns what to do with entries over 24 hours in a
+// day. Unknown values are logged and treated as reject.
+func GetOverLimitBehavior() string {
+	cfg, err := GetConfig()
+	if err != nil || cfg.OverLimitBehavior == "" {
+		return OverLimitReject
+	}
+	behavior := strings.ToLower(strings.TrimSpace(cfg.OverLimitBehavior))
+	switch behavior {
+	case OverLimitReject, OverLimitClamp, OverLimitAllow:
+		return behavior
+	}
+	log.Printf("Ignoring overLimitBehavior %q: must be reject, clamp or allow", cfg.OverLimitBehavior)
+	return OverLimitReject
+}
+
+// DefaultCapWarningPercent is the share of an hour cap that triggers a
+// warning when capWarningPercent isn't set
+const DefaultCapWarningPercent = 90
+
+// GetCapWarningPercent returns the percentage of a client's hour cap at
+// which logging hours warns. Values outside 1-100 are logged and replaced
+// by the default.
+func GetCapWarningPercent() int {
+	cfg, err := GetConfig()
+	if err != nil || cfg.CapWarningPercent == 0 {
+		return DefaultCapWarningPercent
+	}
+	if cfg.CapWarningPercent < 1 || cfg.CapWarningPercent > 100 {
+		log.Printf("Ignoring capWarningPercent %d: must be between 1 and 100", cfg.CapWarningPercent)
+		return DefaultCapWarningPercent
+	}
+	return cfg.CapWarningPercent
+}
+
+// GetVacationMaxCarryover returns the most vacation hours a year-end
+// rollover carries into the next year, 0 meaning no cap
+func GetVacationMaxCarryover() int {
+	cfg, err := GetConfig()
+	if err != nil || cfg.VacationHours.MaxCarryover < 0 {
+		return 0
+	}
+	return cfg.VacationHours.MaxCarryover
+}
+
+// GetLinkTrainingBudget reports whether logging training hours also creates
+// a training budget entry (off by default)
+func GetLinkTrainingBudget() bool {
+	cfg, err := GetConfig()
+	if err != nil {
+		return false
+	}
+	return cfg.LinkTrainingBudget
+}
+
+// GetTrainingBudgetTarget returns the yearly training budget, 0 when none
+// is configured
+func GetTrainingBudgetTarget() float64 {
+	cfg, err := GetConfig()
+	if err != nil {
+		return 0
+	}
+	return cfg.TrainingBudgetTarget
+}
+
+// DefaultStandardDailyHours is the standard working day length when none is configured
+const DefaultStandardDailyHours = 8
+
+// GetStandardDailyHours returns the length of a standard working day in hours
+func GetStandardDailyHours() int {
+	cfg, err := GetConfig()
+	if err != nil || cfg.StandardDailyHours <= 0 {
+		return DefaultStandardDailyHours
+	}
+	return cfg.StandardDailyHours
+}
+
+// DefaultSQLiteBusyTimeout is how long (milliseconds) SQLite waits for a lock
+// when none is configured
+const DefaultSQLiteBusyTimeout = 5000
+
+// GetSQLiteBusyTimeout returns how long a SQLite statement waits for a lock
+// held by another connection, in milliseconds
+func GetSQLiteBusyTimeout() int {
+	cfg, err := GetConfig()
+	if err != nil || cfg.SQLiteBusyTimeout <= 0 {
+		return DefaultSQLiteBusyTimeout
+	}
+	return cfg.SQLiteBusyTimeout
+}
+
+// GetSQLiteMaxOpenConns returns the size of the SQLite connection pool
+// (default 1)
+func GetSQLiteMaxOpenConns() int {
+	cfg, err := GetConfig()
+	if err != nil || cfg.SQLiteMaxOpenConns <= 0 {
+		return 1
+	}
+	return cfg.SQLiteMaxOpenConns
+}
+
+// GetIdleAutoFill returns the idle auto-fill settings (disabled by default)
+func GetIdleAutoFill() IdleAutoFill {
+	cfg, err := GetConfig()
+	if err != nil {
+		return IdleAutoFill{}
+	}
+	return cfg.IdleAutoFill
+}
+
+// GetHolidaySource returns the configured public holiday calendar, or "" when
+// none is set
+func GetHolidaySource() string {
+	cfg, err := GetConfig()
+	if err != nil {
+		return ""
+	}
+	source := strings.TrimSpace(cfg.HolidaySource)
+
+	// Expand ~ in path if present
+	if strings.HasPrefix(source, "~/") {
+		if homeDir, err := os.UserHomeDir(); err == nil {
+			source = filepath.Join(homeDir, source[2:])
+		}
+	}
+	return source
+}
+
+// parseWeekdays converts a list of weekday names from setting to weekdays,
+// skipping (and logging) names it doesn't recognize
+func parseWeekdays(setting string, names []string) []time.Weekday {
+	var days []time.Weekday
+	for _, name := range names {
+		d, err := workschedule.ParseWeekday(name)
+		if err != nil {
+			log.Printf("Ignoring %s entry: %v", setting, err)
+			continue
+		}
+		days = append(days, d)
 	}
-	return s
+	return days
 }
 
 // GetPostgresURL returns the PostgreSQL connection URL
@@ -4,7 +4,10 @@ import (
 	"log"
 	"os"
 	"path/filepath"
+	"slices"
+	"strings"
 	"testing"
+	"time"
 )
 
 // disableLogging temporarily disables logging during tests
@@ -243,3 +246,300 @@ func TestGetDevelopmentMode(t *testing.T) {
 	// Reset runtime dev mode for other tests
 	SetRuntimeDevMode(false)
 }
+
+func TestGetDBPath(t *testing.T) {
+	cleanup := setupTestConfig(t)
+	defer cleanup()
+	t.Setenv("TIMESHEETZ_DB_PATH", "")
+
+	if err := SaveConfig(Config{DBLocation: "/srv/config.db"}); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	if got := GetDBPath(); got != "/srv/config.db" {
+		t.Errorf("Expected the config's dbLocation, got %q", got)
+	}
+
+	// The env var wins over the config
+	t.Setenv("TIMESHEETZ_DB_PATH", "/data/timesheet.db")
+	if got := GetDBPath(); got != "/data/timesheet.db" {
+		t.Errorf("Expected TIMESHEETZ_DB_PATH, got %q", got)
+	}
+
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("TIMESHEETZ_DB_PATH", "~/volume/timesheet.db")
+	if got, want := GetDBPath(), filepath.Join(home, "volume", "timesheet.db"); got != want {
+		t.Errorf("Expected ~ to be expanded to %q, got %q", want, got)
+	}
+}
+
+func TestGetWorkScheduleWorkingDays(t *testing.T) {
+	restoreLogging := disableLogging()
+	defer restoreLogging()
+
+	cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	// Four-day week: Friday has hours in the schedule but isn't worked
+	testConfig := Config{
+		WorkSchedule: WorkSchedule{Monday: 8, Tuesday: 8, Wednesday: 8, Thursday: 8, Friday: 8},
+		WorkingDays:  []string{"monday", "tuesday", "wednesday", "thursday", "someday"},
+	}
+	if err := SaveConfig(testConfig); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	s := GetWorkSchedule()
+	if s.WeeklyTotal() != 32 {
+		t.Errorf("Expected a 32 hour week, got %d", s.WeeklyTotal())
+	}
+	if s.IsWorkingDay(time.Friday) {
+		t.Error("Expected Friday not to be a working day")
+	}
+}
+
+func TestGetWeekendDays(t *testing.T) {
+	restoreLogging := disableLogging()
+	defer restoreLogging()
+
+	cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	tests := []struct {
+		weekendDays []string
+		want        []time.Weekday
+	}{
+		{nil, []time.Weekday{time.Saturday, time.Sunday}},
+		{[]string{"friday", "saturday"}, []time.Weekday{time.Friday, time.Saturday}},
+		{[]string{"Sunday"}, []time.Weekday{time.Sunday}},
+		{[]string{"someday"}, []time.Weekday{time.Saturday, time.Sunday}},
+	}
+	for _, tt := range tests {
+		if err := SaveConfig(Config{WeekendDays: tt.weekendDays}); err != nil {
+			t.Fatalf("Failed to save config: %v", err)
+		}
+		if got := GetWeekendDays(); !slices.Equal(got, tt.want) {
+			t.Errorf("GetWeekendDays() with %v = %v, want %v", tt.weekendDays, got, tt.want)
+		}
+	}
+}
+
+func TestGetStandardDailyHours(t *testing.T) {
+	restoreLogging := disableLogging()
+	defer restoreLogging()
+
+	cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	if err := SaveConfig(Config{}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if h := GetStandardDailyHours(); h != DefaultStandardDailyHours {
+		t.Errorf("Expected default of %d hours, got %d", DefaultStandardDailyHours, h)
+	}
+
+	if err := SaveConfig(Config{StandardDailyHours: 6}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if h := GetStandardDailyHours(); h != 6 {
+		t.Errorf("Expected 6 hours, got %d", h)
+	}
+}
+
+func TestGetRatePrecision(t *testing.T) {
+	restoreLogging := disableLogging()
+	defer restoreLogging()
+
+	cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	if err := SaveConfig(Config{}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if p := GetRatePrecision(); p != DefaultRatePrecision {
+		t.Errorf("Expected default precision %d, got %d", DefaultRatePrecision, p)
+	}
+
+	for _, tt := range []struct{ set, want int }{{0, 0}, {4, 4}, {7, DefaultRatePrecision}, {-1, DefaultRatePrecision}} {
+		precision := tt.set
+		if err := SaveConfig(Config{RatePrecision: &precision}); err != nil {
+			t.Fatalf("Failed to save config: %v", err)
+		}
+		if p := GetRatePrecision(); p != tt.want {
+			t.Errorf("ratePrecision %d: expected %d, got %d", tt.set, tt.want, p)
+		}
+	}
+}
+
+func TestGetDayBoundaryHour(t *testing.T) {
+	restoreLogging := disableLogging()
+	defer restoreLogging()
+
+	cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	for _, tt := range []struct{ set, want int }{{0, 0}, {4, 4}, {24, 0}, {-1, 0}} {
+		if err := SaveConfig(Config{DayBoundaryHour: tt.set}); err != nil {
+			t.Fatalf("Failed to save config: %v", err)
+		}
+		if h := GetDayBoundaryHour(); h != tt.want {
+			t.Errorf("dayBoundaryHour %d: expected %d, got %d", tt.set, tt.want, h)
+		}
+	}
+}
+
+func TestGetOverLimitBehavior(t *testing.T) {
+	restoreLogging := disableLogging()
+	defer restoreLogging()
+
+	cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	for _, tt := range []struct{ set, want string }{
+		{"", OverLimitReject},
+		{"clamp", OverLimitClamp},
+		{" Allow ", OverLimitAllow},
+		{"ignore", OverLimitReject},
+	} {
+		if err := SaveConfig(Config{OverLimitBehavior: tt.set}); err != nil {
+			t.Fatalf("Failed to save config: %v", err)
+		}
+		if behavior := GetOverLimitBehavior(); behavior != tt.want {
+			t.Errorf("overLimitBehavior %q: expected %q, got %q", tt.set, tt.want, behavior)
+		}
+	}
+}
+
+func TestGetCapWarningPercent(t *testing.T) {
+	restoreLogging := disableLogging()
+	defer restoreLogging()
+
+	cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	for _, tt := range []struct{ set, want int }{
+		{0, DefaultCapWarningPercent},
+		{75, 75},
+		{100, 100},
+		{150, DefaultCapWarningPercent},
+		{-5, DefaultCapWarningPercent},
+	} {
+		if err := SaveConfig(Config{CapWarningPercent: tt.set}); err != nil {
+			t.Fatalf("Failed to save config: %v", err)
+		}
+		if percent := GetCapWarningPercent(); percent != tt.want {
+			t.Errorf("capWarningPercent %d: expected %d, got %d", tt.set, tt.want, percent)
+		}
+	}
+}
+
+func TestGetInvoiceUnits(t *testing.T) {
+	restoreLogging := disableLogging()
+	defer restoreLogging()
+
+	cleanup := setupTestConfig(t)
+	defer cleanup()
+
+	if err := SaveConfig(Config{}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if unit := GetInvoiceUnits().For("Acme"); unit != InvoiceUnitHour {
+		t.Errorf("Expected hour by default, got %q", unit)
+	}
+
+	if err := SaveConfig(Config{
+		InvoiceUnit:        "Day",
+		ClientInvoiceUnits: map[string]string{"Acme": "quarter-hour", "Beta": "fortnight"},
+	}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	units := GetInvoiceUnits()
+	if unit := units.For("Acme"); unit != InvoiceUnitQuarterHour {
+		t.Errorf("Expected Acme's quarter-hour, got %q", unit)
+	}
+	if unit := units.For("Beta"); unit != InvoiceUnitDay {
+		t.Errorf("Expected an unknown unit to fall back to the default day, got %q", unit)
+	}
+}
+
+func TestTOMLAndYAMLConfig(t *testing.T) {
+	restoreLogging := disableLogging()
+	defer restoreLogging()
+	defer SetConfigPathOverride("")
+
+	files := map[string]string{
+		"config.toml": `
+name = "Test User"
+apiPort = 9090
+weekStart = "sunday"
+
+[workSchedule]
+monday = 8
+
+[exchangeRates]
+USD = 0.92
+`,
+		"config.yaml": `
+name: Test User
+apiPort: 9090
+weekStart: sunday
+workSchedule:
+  monday: 8
+exchangeRates:
+  USD: 0.92
+`,
+	}
+	for name, content := range files {
+		path := filepath.Join(t.TempDir(), name)
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatalf("Failed to write %s: %v", name, err)
+		}
+		SetConfigPathOverride(path)
+
+		cfg, err := GetConfig()
+		if err != nil {
+			t.Fatalf("GetConfig(%s) failed: %v", name, err)
+		}
+		if cfg.Name != "Test User" || cfg.APIPort != 9090 || cfg.WorkSchedule.Monday != 8 || cfg.ExchangeRates["USD"] != 0.92 {
+			t.Errorf("%s: unexpected config %+v", name, cfg)
+		}
+		if got := GetWeekStart(); got != time.Sunday {
+			t.Errorf("%s: GetWeekStart() = %v, want Sunday", name, got)
+		}
+
+		// Saving keeps the file's format
+		cfg.CompanyName = "Test Company"
+		if err := SaveConfig(cfg); err != nil {
+			t.Fatalf("SaveConfig(%s) failed: %v", name, err)
+		}
+		saved, _ := os.ReadFile(path)
+		if strings.HasPrefix(strings.TrimSpace(string(saved)), "{") {
+			t.Errorf("%s was rewritten as JSON:\n%s", name, saved)
+		}
+		if strings.Contains(string(saved), "9090.0") {
+			t.Errorf("%s: whole numbers should stay integers:\n%s", name, saved)
+		}
+		cfg, err = GetConfig()
+		if err != nil || cfg.CompanyName != "Test Company" || cfg.APIPort != 9090 {
+			t.Errorf("%s: reading the saved config back gave %+v, %v", name, cfg, err)
+		}
+	}
+}
+
+func TestFindConfigFile(t *testing.T) {
+	dir := t.TempDir()
+	if got := findConfigFile(dir); got != filepath.Join(dir, "config.json") {
+		t.Errorf("Expected config.json when there is no config, got %s", got)
+	}
+
+	os.WriteFile(filepath.Join(dir, "config.yaml"), []byte("name: x\n"), 0644)
+	if got := findConfigFile(dir); got != filepath.Join(dir, "config.yaml") {
+		t.Errorf("Expected config.yaml, got %s", got)
+	}
+
+	// JSON wins when there are several
+	os.WriteFile(filepath.Join(dir, "config.json"), []byte("{}"), 0644)
+	if got := findConfigFile(dir); got != filepath.Join(dir, "config.json") {
+		t.Errorf("Expected config.json to be preferred, got %s", got)
+	}
+}
@@ -0,0 +1,89 @@
+package config
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// configFileNames are the config files looked for in the config directory,
+// in order of preference. The setup form writes config.json.
+var configFileNames = []string{"config.json", "config.toml", "config.yaml", "config.yml"}
+
+// findConfigFile returns the first config file in dir that exists, or
+// config.json when there is none yet
+func findConfigFile(dir string) string {
+	for _, name := range configFileNames {
+		path := filepath.Join(dir, name)
+		if _, err := os.Stat(path); err == nil {
+			return path
+		}
+	}
+	return filepath.Join(dir, configFileNames[0])
+}
+
+// configFormat returns "json", "toml" or "yaml" based on the file extension
+func configFormat(path string) string {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".toml":
+		return "toml"
+	case ".yaml", ".yml":
+		return "yaml"
+	default:
+		return "json"
+	}
+}
+
+// readConfigJSON reads a config file and returns its contents as JSON. TOML
+// and YAML files are converted, so every format uses the json tags of Config
+// for its keys (e.g. apiPort, workSchedule.monday).
+func readConfigJSON(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var raw map[string]any
+	switch configFormat(path) {
+	case "toml":
+		if err := toml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("error parsing config TOML: %w", err)
+		}
+	case "yaml":
+		if err := yaml.Unmarshal(data, &raw); err != nil {
+			return nil, fmt.Errorf("error parsing config YAML: %w", err)
+		}
+	default:
+		return data, nil
+	}
+	return json.Marshal(raw)
+}
+
+// encodeConfig renders a config in the format of the file it's saved to
+func encodeConfig(path string, config Config) ([]byte, error) {
+	configJSON, err := json.MarshalIndent(config, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+
+	format := configFormat(path)
+	if format == "json" {
+		return configJSON, nil
+	}
+
+	// YAML is a superset of JSON; decoding the JSON as YAML keeps whole
+	// numbers as integers, so they aren't written as 8080.0
+	var raw map[string]any
+	if err := yaml.Unmarshal(configJSON, &raw); err != nil {
+		return nil, err
+	}
+	if format == "toml" {
+		return toml.Marshal(raw)
+	}
+	return yaml.Marshal(raw)
+}
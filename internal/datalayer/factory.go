@@ -71,3 +71,18 @@ func GetDataLayer() db.DataLayer {
 func ResetDataLayer() {
 	dataLayerInstance = nil
 }
+
+// Name returns the kind of a data layer as reported by the health check:
+// "sqlite", "postgres", "dual" or "remote"
+func Name(dl db.DataLayer) string {
+	switch dl.(type) {
+	case *db.PostgresDBLayer:
+		return "postgres"
+	case *db.DualLayer:
+		return "dual"
+	case *api.ClientAdapter:
+		return "remote"
+	default:
+		return "sqlite"
+	}
+}
@@ -0,0 +1,105 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Backend is the kind of database a DSN points at
+type Backend string
+
+const (
+	BackendSQLite   Backend = "sqlite"
+	BackendPostgres Backend = "postgres"
+)
+
+// postgresKeywords mark a key/value PostgreSQL DSN such as
+// "host=localhost dbname=timesheet"
+var postgresKeywords = []string{"host=", "dbname=", "user=", "port=", "sslmode="}
+
+// ParseBackendDSN works out which backend a DSN is for and returns the
+// connection string to hand to its driver.
+//
+// postgres:// and postgresql:// URLs and key/value DSNs ("host=… dbname=…")
+// select PostgreSQL. sqlite:// URLs select SQLite with the prefix removed
+// (sqlite:///abs/path.db is /abs/path.db); anything else without a scheme,
+// such as a file path or :memory:, is a SQLite database too.
+func ParseBackendDSN(dsn string) (Backend, string, error) {
+	dsn = strings.TrimSpace(dsn)
+	if dsn == "" {
+		return "", "", fmt.Errorf("no database configured")
+	}
+
+	lower := strings.ToLower(dsn)
+	switch {
+	case strings.HasPrefix(lower, "postgres://"), strings.HasPrefix(lower, "postgresql://"):
+		return BackendPostgres, dsn, nil
+	case strings.HasPrefix(lower, "sqlite://"):
+		path := dsn[len("sqlite://"):]
+		if path == "" {
+			return "", "", fmt.Errorf("sqlite DSN %q has no database path", dsn)
+		}
+		return BackendSQLite, path, nil
+	}
+
+	if scheme, _, ok := strings.Cut(lower, "://"); ok && !strings.ContainsAny(scheme, `/\`) {
+		return "", "", fmt.Errorf("unsupported database scheme %q (use postgres:// or a SQLite file path)", scheme+"://")
+	}
+	for _, keyword := range postgresKeywords {
+		if strings.HasPrefix(lower, keyword) || strings.Contains(lower, " "+keyword) {
+			return BackendPostgres, dsn, nil
+		}
+	}
+	return BackendSQLite, dsn, nil
+}
+
+// IsPostgresDSN reports whether dsn is a PostgreSQL connection string
+func IsPostgresDSN(dsn string) bool {
+	backend, _, err := ParseBackendDSN(dsn)
+	return err == nil && backend == BackendPostgres
+}
+
+// OpenBackend connects to the database dsn points at (see ParseBackendDSN),
+// brings its schema up to date and returns the DataLayer that reads and
+// writes it. Close it with CloseBackend.
+func OpenBackend(dsn string) (DataLayer, error) {
+	backend, conn, err := ParseBackendDSN(dsn)
+	if err != nil {
+		return nil, err
+	}
+
+	if backend == BackendPostgres {
+		if err := ConnectPostgres(conn); err != nil {
+			return nil, err
+		}
+		if err := InitializePostgresDatabase(); err != nil {
+			ClosePostgres()
+			return nil, fmt.Errorf("failed to initialize postgres schema: %w", err)
+		}
+		return &PostgresDBLayer{}, nil
+	}
+
+	// InitializeDatabase is idempotent (CREATE TABLE IF NOT EXISTS /
+	// error-tolerant ALTER TABLE), so existing databases pick up tables added
+	// in later releases
+	if err := InitializeDatabase(conn); err != nil {
+		return nil, err
+	}
+	// Reconnecting would hand an in-memory database a fresh, empty one
+	if conn != ":memory:" {
+		if err := Connect(conn); err != nil {
+			return nil, err
+		}
+	}
+	return &LocalDBLayer{}, nil
+}
+
+// CloseBackend closes the connection behind a DataLayer returned by OpenBackend
+func CloseBackend(dl DataLayer) {
+	switch dl.(type) {
+	case *PostgresDBLayer:
+		ClosePostgres()
+	case *LocalDBLayer:
+		Close()
+	}
+}
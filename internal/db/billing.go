@@ -0,0 +1,76 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// Billing status lives in the billed and invoice_ref columns of the local
+// timesheet table. Like statements it's local only: it isn't synced to
+// PostgreSQL, and editing an entry's hours leaves its status as it was.
+
+// MarkEntriesBilled flags the unbilled entries with client hours in a month
+// (or the whole year when month is 0) as billed on invoiceRef, which may be
+// empty. An empty clientName marks every client. It returns the number of
+// entries marked; entries billed before keep their invoice reference.
+func MarkEntriesBilled(clientName string, year, month int, invoiceRef string) (int, error) {
+	start, end := expenseDateBounds(year, month)
+	query := `UPDATE timesheet SET billed = 1, invoice_ref = ?
+              WHERE date >= ? AND date < ? AND COALESCE(client_hours, 0) > 0 AND billed = 0`
+	args := []any{strings.TrimSpace(invoiceRef), start, end}
+	if clientName != "" {
+		query += " AND client_name = ?"
+		args = append(args, clientName)
+	}
+
+	result, err := db.Exec(query, args...)
+	if err != nil {
+		return 0, fmt.Errorf("failed to mark entries billed: %w", err)
+	}
+	marked, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("error checking rows affected: %w", err)
+	}
+	return int(marked), nil
+}
+
+// GetUnbilledEntries returns the entries with client hours that haven't been
+// billed yet, oldest first, priced the way earnings are: billed hours in the
+// client's invoice unit times the rate on the day, plus any overtime. An
+// empty clientName reports every client.
+func GetUnbilledEntries(clientName string) ([]EarningsEntry, error) {
+	cache, err := buildRateCache()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build rate cache: %w", err)
+	}
+	invoices := loadInvoicing(cache.minimums)
+
+	query := `SELECT date, client_name, client_hours FROM timesheet
+              WHERE COALESCE(client_hours, 0) > 0 AND billed = 0`
+	var args []any
+	if clientName != "" {
+		query += " AND client_name = ?"
+		args = append(args, clientName)
+	}
+	query += " ORDER BY date ASC, client_name ASC"
+
+	rows, err := db.Query(query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query unbilled entries: %w", err)
+	}
+	defer rows.Close()
+
+	entries := []EarningsEntry{}
+	for rows.Next() {
+		var date, client string
+		var hours float64
+		if err := rows.Scan(&date, &client, &hours); err != nil {
+			return nil, fmt.Errorf("failed to scan unbilled entry: %w", err)
+		}
+		entry := invoices.price(client, hours, cache.getRateFromCache(client, date))
+		entry.Date = date
+		entry.Currency = cache.currencies[client]
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
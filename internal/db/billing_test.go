@@ -0,0 +1,72 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"timesheet/internal/config"
+)
+
+func TestMarkEntriesBilled(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+
+	for _, name := range []string{"Acme", "Globex"} {
+		id, _ := AddClient(Client{Name: name, IsActive: true})
+		AddClientRate(ClientRate{ClientId: id, HourlyRate: 100, EffectiveDate: "2024-01-01"})
+	}
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-02-28", Client_name: "Acme", Client_hours: 8})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-03-01", Client_name: "Acme", Client_hours: 6})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-03-04", Client_name: "Globex", Client_hours: 4})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-03-05", Vacation_hours: 8})
+
+	unbilled, err := GetUnbilledEntries("")
+	if err != nil {
+		t.Fatalf("GetUnbilledEntries failed: %v", err)
+	}
+	// The vacation day has no client hours, so there's nothing to bill
+	if len(unbilled) != 3 {
+		t.Fatalf("Expected 3 unbilled entries, got %+v", unbilled)
+	}
+
+	marked, err := MarkEntriesBilled("Acme", 2024, 3, "INV-7")
+	if err != nil {
+		t.Fatalf("MarkEntriesBilled failed: %v", err)
+	}
+	if marked != 1 {
+		t.Errorf("Expected 1 entry marked, got %d", marked)
+	}
+	// Marking again doesn't touch entries that were already billed
+	if marked, _ := MarkEntriesBilled("Acme", 2024, 3, "INV-8"); marked != 0 {
+		t.Errorf("Expected billed entries to be left alone, got %d marked", marked)
+	}
+
+	unbilled, err = GetUnbilledEntries("Acme")
+	if err != nil {
+		t.Fatalf("GetUnbilledEntries failed: %v", err)
+	}
+	if len(unbilled) != 1 || unbilled[0].Date != "2024-02-28" || unbilled[0].Earnings != 800 {
+		t.Errorf("Expected only February's Acme entry to be unbilled, got %+v", unbilled)
+	}
+
+	var ref string
+	db.QueryRow(`SELECT invoice_ref FROM timesheet WHERE date = '2024-03-01'`).Scan(&ref)
+	if ref != "INV-7" {
+		t.Errorf("Expected invoice reference INV-7, got %q", ref)
+	}
+
+	// Editing a billed entry keeps its status
+	UpdateTimesheetEntry(TimesheetEntry{Date: "2024-03-01", Client_name: "Acme", Client_hours: 7})
+	if unbilled, _ := GetUnbilledEntries("Acme"); len(unbilled) != 1 {
+		t.Errorf("Expected the edited entry to stay billed, got %+v", unbilled)
+	}
+
+	if marked, _ := MarkEntriesBilled("", 2024, 0, ""); marked != 2 {
+		t.Errorf("Expected the rest of 2024 to be marked, got %d", marked)
+	}
+	if unbilled, _ := GetUnbilledEntries(""); len(unbilled) != 0 {
+		t.Errorf("Expected nothing left to bill, got %+v", unbilled)
+	}
+}
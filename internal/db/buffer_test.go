@@ -122,10 +122,12 @@ func TestVacationSummary_BufferIncludedAndCascadeOrder(t *testing.T) {
 	}
 
 	// Use 35 vacation hours in 2026. Cascade: 20 (carryover) → 15 (buffer) → 0 (current).
-	if err := AddTimesheetEntry(TimesheetEntry{
-		Date: "2026-04-01", Client_name: "Vacation", Vacation_hours: 35,
-	}); err != nil {
-		t.Fatalf("AddTimesheetEntry: %v", err)
+	for date, hours := range map[string]float64{"2026-04-01": 24, "2026-04-02": 11} {
+		if err := AddTimesheetEntry(TimesheetEntry{
+			Date: date, Client_name: "Vacation", Vacation_hours: hours,
+		}); err != nil {
+			t.Fatalf("AddTimesheetEntry: %v", err)
+		}
 	}
 
 	summary, err := GetVacationSummaryForYear(2026)
@@ -134,25 +136,25 @@ func TestVacationSummary_BufferIncludedAndCascadeOrder(t *testing.T) {
 	}
 
 	if summary.CarryoverHours != 20 {
-		t.Errorf("CarryoverHours: want 20, got %d", summary.CarryoverHours)
+		t.Errorf("CarryoverHours: want 20, got %g", summary.CarryoverHours)
 	}
 	if summary.BufferHours != 50 {
-		t.Errorf("BufferHours: want 50, got %d", summary.BufferHours)
+		t.Errorf("BufferHours: want 50, got %g", summary.BufferHours)
 	}
 	if summary.TotalAvailable != 187+20+50 {
-		t.Errorf("TotalAvailable: want %d, got %d", 187+20+50, summary.TotalAvailable)
+		t.Errorf("TotalAvailable: want %d, got %g", 187+20+50, summary.TotalAvailable)
 	}
 	if summary.UsedFromCarryover != 20 {
-		t.Errorf("UsedFromCarryover: want 20, got %d", summary.UsedFromCarryover)
+		t.Errorf("UsedFromCarryover: want 20, got %g", summary.UsedFromCarryover)
 	}
 	if summary.UsedFromBuffer != 15 {
-		t.Errorf("UsedFromBuffer: want 15, got %d", summary.UsedFromBuffer)
+		t.Errorf("UsedFromBuffer: want 15, got %g", summary.UsedFromBuffer)
 	}
 	if summary.UsedFromCurrent != 0 {
-		t.Errorf("UsedFromCurrent: want 0, got %d", summary.UsedFromCurrent)
+		t.Errorf("UsedFromCurrent: want 0, got %g", summary.UsedFromCurrent)
 	}
 	if summary.RemainingTotal != 187+20+50-35 {
-		t.Errorf("RemainingTotal: want %d, got %d", 187+20+50-35, summary.RemainingTotal)
+		t.Errorf("RemainingTotal: want %d, got %g", 187+20+50-35, summary.RemainingTotal)
 	}
 }
 
@@ -169,10 +171,13 @@ func TestVacationSummary_BufferSpillsToCurrent(t *testing.T) {
 	if err := UpsertBufferEntry(BufferEntry{Year: 2026, Month: 1, Hours: 20}); err != nil {
 		t.Fatal(err)
 	}
-	if err := AddTimesheetEntry(TimesheetEntry{
-		Date: "2026-05-01", Client_name: "Vacation", Vacation_hours: 45,
-	}); err != nil {
-		t.Fatal(err)
+	// 45 vacation hours, spread over two days to stay within a day's 24 hours
+	for date, hours := range map[string]float64{"2026-05-01": 24, "2026-05-04": 21} {
+		if err := AddTimesheetEntry(TimesheetEntry{
+			Date: date, Client_name: "Vacation", Vacation_hours: hours,
+		}); err != nil {
+			t.Fatal(err)
+		}
 	}
 
 	summary, err := GetVacationSummaryForYear(2026)
@@ -180,12 +185,12 @@ func TestVacationSummary_BufferSpillsToCurrent(t *testing.T) {
 		t.Fatal(err)
 	}
 	if summary.UsedFromCarryover != 10 {
-		t.Errorf("UsedFromCarryover: want 10, got %d", summary.UsedFromCarryover)
+		t.Errorf("UsedFromCarryover: want 10, got %g", summary.UsedFromCarryover)
 	}
 	if summary.UsedFromBuffer != 20 {
-		t.Errorf("UsedFromBuffer: want 20, got %d", summary.UsedFromBuffer)
+		t.Errorf("UsedFromBuffer: want 20, got %g", summary.UsedFromBuffer)
 	}
 	if summary.UsedFromCurrent != 15 {
-		t.Errorf("UsedFromCurrent: want 15, got %d", summary.UsedFromCurrent)
+		t.Errorf("UsedFromCurrent: want 15, got %g", summary.UsedFromCurrent)
 	}
 }
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+)
+
+// OverwritePolicy decides what a bulk operation does when a target date
+// already has a timesheet entry for the same client. Every bulk feature (copy month, paste range,
+// bulk create, …) must honor it so hand-entered corrections are never
+// clobbered silently.
+type OverwritePolicy string
+
+const (
+	// OverwriteSkip leaves existing entries untouched and reports them (default)
+	OverwriteSkip OverwritePolicy = "skip"
+	// OverwriteReplace replaces existing entries with the incoming values
+	OverwriteReplace OverwritePolicy = "replace"
+	// OverwriteMerge keeps existing values and only fills fields that are empty
+	OverwriteMerge OverwritePolicy = "merge"
+	// OverwriteSum adds the incoming hours to the existing ones
+	OverwriteSum OverwritePolicy = "sum"
+)
+
+// ParseOverwritePolicy parses a policy name. An empty string yields the
+// default, OverwriteSkip; "overwrite" is accepted for OverwriteReplace.
+func ParseOverwritePolicy(s string) (OverwritePolicy, error) {
+	switch policy := OverwritePolicy(strings.ToLower(strings.TrimSpace(s))); policy {
+	case "":
+		return OverwriteSkip, nil
+	case "overwrite":
+		return OverwriteReplace, nil
+	case OverwriteSkip, OverwriteReplace, OverwriteMerge, OverwriteSum:
+		return policy, nil
+	default:
+		return "", fmt.Errorf("invalid overwrite policy %q (must be skip, replace, merge or sum)", s)
+	}
+}
+
+// BulkResult reports, per date, what a bulk operation did
+type BulkResult struct {
+	Created  []string
+	Replaced []string
+	Merged   []string
+	Summed   []string
+	Skipped  []string // Existing entries left untouched
+}
+
+// MergeTimesheetEntries fills the empty fields of existing with the values
+// from incoming. Fields that already have a value are kept.
+func MergeTimesheetEntries(existing, incoming TimesheetEntry) TimesheetEntry {
+	merged := existing
+	if merged.Client_name == "" {
+		merged.Client_name = incoming.Client_name
+	}
+	if merged.Client_hours == 0 {
+		merged.Client_hours = incoming.Client_hours
+	}
+	if merged.Vacation_hours == 0 {
+		merged.Vacation_hours = incoming.Vacation_hours
+	}
+	if merged.Idle_hours == 0 {
+		merged.Idle_hours = incoming.Idle_hours
+	}
+	if merged.Training_hours == 0 {
+		merged.Training_hours = incoming.Training_hours
+	}
+	if merged.Sick_hours == 0 {
+		merged.Sick_hours = incoming.Sick_hours
+	}
+	if merged.Holiday_hours == 0 {
+		merged.Holiday_hours = incoming.Holiday_hours
+	}
+	if merged.Note == "" {
+		merged.Note = incoming.Note
+	}
+	return merged
+}
+
+// SumTimesheetEntries adds the hours of incoming to existing. The existing
+// client name is kept unless it's empty.
+func SumTimesheetEntries(existing, incoming TimesheetEntry) TimesheetEntry {
+	summed := existing
+	if summed.Client_name == "" {
+		summed.Client_name = incoming.Client_name
+	}
+	summed.Client_hours += incoming.Client_hours
+	summed.Vacation_hours += incoming.Vacation_hours
+	summed.Idle_hours += incoming.Idle_hours
+	summed.Training_hours += incoming.Training_hours
+	summed.Sick_hours += incoming.Sick_hours
+	summed.Holiday_hours += incoming.Holiday_hours
+	if summed.Note == "" {
+		summed.Note = incoming.Note
+	}
+	return summed
+}
+
+// BulkSaveTimesheetEntries writes entries through dl, resolving dates that
+// already have an entry for the same client according to policy. An entry
+// for another client is added as a block next to the ones already there. It
+// stops at the first write error and returns what was done so far.
+func BulkSaveTimesheetEntries(dl DataLayer, entries []TimesheetEntry, policy OverwritePolicy) (BulkResult, error) {
+	var result BulkResult
+
+	for _, entry := range entries {
+		onDate, err := dl.GetTimesheetEntriesByDate(entry.Date)
+		if err != nil {
+			return result, fmt.Errorf("failed to look up entries for %s: %w", entry.Date, err)
+		}
+		existing, found := FindClientEntry(onDate, entry.Client_name)
+		if !found {
+			// No entry for this client on this date yet
+			if err := dl.AddTimesheetEntry(entry); err != nil {
+				return result, fmt.Errorf("failed to create entry for %s: %w", entry.Date, err)
+			}
+			result.Created = append(result.Created, entry.Date)
+			continue
+		}
+
+		switch policy {
+		case OverwriteReplace:
+			entry.Id = existing.Id
+			if err := dl.UpdateTimesheetEntry(entry); err != nil {
+				return result, fmt.Errorf("failed to replace entry for %s: %w", entry.Date, err)
+			}
+			result.Replaced = append(result.Replaced, entry.Date)
+		case OverwriteMerge:
+			merged := MergeTimesheetEntries(existing, entry)
+			if merged == existing {
+				result.Skipped = append(result.Skipped, entry.Date)
+				continue
+			}
+			if err := dl.UpdateTimesheetEntry(merged); err != nil {
+				return result, fmt.Errorf("failed to merge entry for %s: %w", entry.Date, err)
+			}
+			result.Merged = append(result.Merged, entry.Date)
+		case OverwriteSum:
+			if err := dl.UpdateTimesheetEntry(SumTimesheetEntries(existing, entry)); err != nil {
+				return result, fmt.Errorf("failed to sum entry for %s: %w", entry.Date, err)
+			}
+			result.Summed = append(result.Summed, entry.Date)
+		default:
+			result.Skipped = append(result.Skipped, entry.Date)
+		}
+	}
+
+	return result, nil
+}
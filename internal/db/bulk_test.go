@@ -0,0 +1,97 @@
+package db
+
+import "testing"
+
+func TestParseOverwritePolicy(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected OverwritePolicy
+		wantErr  bool
+	}{
+		{"", OverwriteSkip, false},
+		{"skip", OverwriteSkip, false},
+		{"Replace", OverwriteReplace, false},
+		{" merge ", OverwriteMerge, false},
+		{"sum", OverwriteSum, false},
+		{"overwrite", OverwriteReplace, false},
+		{"clobber", "", true},
+	}
+
+	for _, tt := range tests {
+		policy, err := ParseOverwritePolicy(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseOverwritePolicy(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+		}
+		if policy != tt.expected {
+			t.Errorf("ParseOverwritePolicy(%q) = %q, want %q", tt.input, policy, tt.expected)
+		}
+	}
+}
+
+func TestBulkSaveTimesheetEntries(t *testing.T) {
+	policies := []struct {
+		policy       OverwritePolicy
+		wantClient   string
+		wantHours    float64
+		wantTraining float64
+	}{
+		{OverwriteSkip, "Acme", 6, 0},
+		{OverwriteReplace, "Acme", 8, 2},
+		{OverwriteMerge, "Acme", 6, 2},
+		{OverwriteSum, "Acme", 14, 2},
+	}
+
+	for _, p := range policies {
+		t.Run(string(p.policy), func(t *testing.T) {
+			dbPath := setupTestDB(t)
+			defer teardownTestDB(t, dbPath)
+
+			if err := AddTimesheetEntry(TimesheetEntry{Date: "2024-01-15", Client_name: "Acme", Client_hours: 6}); err != nil {
+				t.Fatalf("Failed to add entry: %v", err)
+			}
+
+			entries := []TimesheetEntry{
+				{Date: "2024-01-15", Client_name: "Acme", Client_hours: 8, Training_hours: 2},
+				{Date: "2024-01-16", Client_name: "Acme", Client_hours: 8},
+				// Another client on a taken date is a block of its own
+				{Date: "2024-01-15", Client_name: "Globex", Client_hours: 2},
+			}
+			result, err := BulkSaveTimesheetEntries(&LocalDBLayer{}, entries, p.policy)
+			if err != nil {
+				t.Fatalf("BulkSaveTimesheetEntries failed: %v", err)
+			}
+
+			if len(result.Created) != 2 || result.Created[0] != "2024-01-16" || result.Created[1] != "2024-01-15" {
+				t.Errorf("Expected 2024-01-16 and Globex's 2024-01-15 to be created, got %v", result.Created)
+			}
+			existing, err := firstEntry(GetTimesheetEntriesByDate("2024-01-15"))
+			if err != nil {
+				t.Fatalf("Failed to get entry: %v", err)
+			}
+			if existing.Client_name != p.wantClient || existing.Client_hours != p.wantHours || existing.Training_hours != p.wantTraining {
+				t.Errorf("Existing entry = %s/%g/%g, want %s/%g/%g",
+					existing.Client_name, existing.Client_hours, existing.Training_hours,
+					p.wantClient, p.wantHours, p.wantTraining)
+			}
+
+			switch p.policy {
+			case OverwriteSkip:
+				if len(result.Skipped) != 1 {
+					t.Errorf("Expected 1 skipped date, got %v", result.Skipped)
+				}
+			case OverwriteReplace:
+				if len(result.Replaced) != 1 {
+					t.Errorf("Expected 1 replaced date, got %v", result.Replaced)
+				}
+			case OverwriteMerge:
+				if len(result.Merged) != 1 {
+					t.Errorf("Expected 1 merged date, got %v", result.Merged)
+				}
+			case OverwriteSum:
+				if len(result.Summed) != 1 {
+					t.Errorf("Expected 1 summed date, got %v", result.Summed)
+				}
+			}
+		})
+	}
+}
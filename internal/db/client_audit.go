@@ -0,0 +1,126 @@
+package db
+
+import (
+	"sort"
+	"strings"
+)
+
+// ClientUsage is a client name with the timesheet entries that bill it
+type ClientUsage struct {
+	Name       string `json:"name"`
+	Entries    int    `json:"entries"`
+	FirstDate  string `json:"first_date"`
+	LastDate   string `json:"last_date"`
+	Suggestion string `json:"suggestion,omitempty"` // A client whose name differs only in case or spacing
+}
+
+// ClientAudit lists the mismatches between the clients table and the client
+// names typed into the timesheet
+type ClientAudit struct {
+	UnknownClients []ClientUsage `json:"unknown_clients"` // Names with client hours but no clients row
+	UnusedClients  []string      `json:"unused_clients"`  // Active clients without client hours
+	UnratedClients []ClientUsage `json:"unrated_clients"` // Entries on days the client had no rate
+}
+
+// Clean reports whether the audit found nothing to fix
+func (a ClientAudit) Clean() bool {
+	return len(a.UnknownClients) == 0 && len(a.UnusedClients) == 0 && len(a.UnratedClients) == 0
+}
+
+// AuditClients compares the clients and their rates with the timesheet.
+// Only entries with client hours count: those are the ones that get billed.
+func AuditClients(dl DataLayer) (ClientAudit, error) {
+	clients, err := dl.GetAllClients()
+	if err != nil {
+		return ClientAudit{}, err
+	}
+	entries, err := dl.GetAllTimesheetEntries(0, 0)
+	if err != nil {
+		return ClientAudit{}, err
+	}
+
+	byName := make(map[string]Client, len(clients))
+	byFoldedName := make(map[string]string, len(clients))
+	for _, client := range clients {
+		byName[client.Name] = client
+		byFoldedName[foldClientName(client.Name)] = client.Name
+	}
+
+	// Rates are only loaded for clients that have entries
+	rates := make(map[int][]ClientRate)
+	unknown := make(map[string]*ClientUsage)
+	unrated := make(map[string]*ClientUsage)
+	used := make(map[string]bool)
+	for _, entry := range entries {
+		if entry.Client_hours <= 0 {
+			continue
+		}
+		used[entry.Client_name] = true
+
+		client, ok := byName[entry.Client_name]
+		if !ok {
+			addClientUsage(unknown, entry)
+			continue
+		}
+		clientRates, loaded := rates[client.Id]
+		if !loaded {
+			if clientRates, err = dl.GetClientRates(client.Id); err != nil {
+				return ClientAudit{}, err
+			}
+			rates[client.Id] = clientRates
+		}
+		if !hasRateOn(clientRates, entry.Date) {
+			addClientUsage(unrated, entry)
+		}
+	}
+
+	audit := ClientAudit{
+		UnknownClients: sortedClientUsage(unknown),
+		UnusedClients:  []string{},
+		UnratedClients: sortedClientUsage(unrated),
+	}
+	for i, usage := range audit.UnknownClients {
+		if name, ok := byFoldedName[foldClientName(usage.Name)]; ok {
+			audit.UnknownClients[i].Suggestion = name
+		}
+	}
+	for _, client := range clients {
+		if client.IsActive && !used[client.Name] {
+			audit.UnusedClients = append(audit.UnusedClients, client.Name)
+		}
+	}
+	sort.Strings(audit.UnusedClients)
+	return audit, nil
+}
+
+// foldClientName normalizes a name for spotting near-misses such as
+// "acme  corp" for "Acme Corp"
+func foldClientName(name string) string {
+	return strings.ToLower(strings.Join(strings.Fields(name), " "))
+}
+
+// hasRateOn reports whether one of rates (newest first) is in effect on date
+func hasRateOn(rates []ClientRate, date string) bool {
+	_, ok := rateOn(rates, date)
+	return ok
+}
+
+func addClientUsage(usage map[string]*ClientUsage, entry TimesheetEntry) {
+	u, ok := usage[entry.Client_name]
+	if !ok {
+		u = &ClientUsage{Name: entry.Client_name, FirstDate: entry.Date, LastDate: entry.Date}
+		usage[entry.Client_name] = u
+	}
+	u.Entries++
+	u.FirstDate = min(u.FirstDate, entry.Date)
+	u.LastDate = max(u.LastDate, entry.Date)
+}
+
+func sortedClientUsage(usage map[string]*ClientUsage) []ClientUsage {
+	sorted := make([]ClientUsage, 0, len(usage))
+	for _, u := range usage {
+		sorted = append(sorted, *u)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
@@ -0,0 +1,53 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAuditClients(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	acme, _ := AddClient(Client{Name: "Acme Corp", IsActive: true})
+	AddClientRate(ClientRate{ClientId: acme, HourlyRate: 100, EffectiveDate: "2024-03-01"})
+	AddClient(Client{Name: "Globex", IsActive: true})
+	AddClient(Client{Name: "Initech", IsActive: false})
+
+	for _, entry := range []TimesheetEntry{
+		{Date: "2024-02-28", Client_name: "Acme Corp", Client_hours: 8}, // Before the first rate
+		{Date: "2024-03-04", Client_name: "Acme Corp", Client_hours: 8},
+		{Date: "2024-03-05", Client_name: "acme corp", Client_hours: 6},
+		{Date: "2024-03-06", Client_name: "Umbrella", Client_hours: 4},
+		{Date: "2024-03-07", Client_name: "Umbrella", Client_hours: 4},
+		{Date: "2024-03-08", Client_name: "Globex", Vacation_hours: 8}, // No client hours
+	} {
+		if err := AddTimesheetEntry(entry); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+	}
+
+	audit, err := AuditClients(&LocalDBLayer{})
+	if err != nil {
+		t.Fatalf("AuditClients failed: %v", err)
+	}
+	if audit.Clean() {
+		t.Fatal("Expected the audit to find problems")
+	}
+
+	wantUnknown := []ClientUsage{
+		{Name: "Umbrella", Entries: 2, FirstDate: "2024-03-06", LastDate: "2024-03-07"},
+		{Name: "acme corp", Entries: 1, FirstDate: "2024-03-05", LastDate: "2024-03-05", Suggestion: "Acme Corp"},
+	}
+	if !reflect.DeepEqual(audit.UnknownClients, wantUnknown) {
+		t.Errorf("Unknown clients:\n got %+v\nwant %+v", audit.UnknownClients, wantUnknown)
+	}
+	// Inactive clients aren't expected to have hours
+	if !reflect.DeepEqual(audit.UnusedClients, []string{"Globex"}) {
+		t.Errorf("Expected Globex to be unused, got %v", audit.UnusedClients)
+	}
+	wantUnrated := []ClientUsage{{Name: "Acme Corp", Entries: 1, FirstDate: "2024-02-28", LastDate: "2024-02-28"}}
+	if !reflect.DeepEqual(audit.UnratedClients, wantUnrated) {
+		t.Errorf("Unrated clients:\n got %+v\nwant %+v", audit.UnratedClients, wantUnrated)
+	}
+}
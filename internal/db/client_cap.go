@@ -0,0 +1,130 @@
+package db
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"timesheet/internal/config"
+)
+
+// Cap states reported by CapUsage
+const (
+	CapOK          = "ok"
+	CapApproaching = "approaching" // At or past capWarningPercent of the cap
+	CapExceeded    = "exceeded"
+)
+
+// CapUsage is how much of one hour cap is used
+type CapUsage struct {
+	Cap       int     `json:"cap"`
+	Logged    float64 `json:"logged"`
+	Remaining float64 `json:"remaining"` // Negative when the cap is exceeded
+	Percent   float64 `json:"percent"`
+	Status    string  `json:"status"`
+}
+
+// ClientCapStatus reports a client's monthly and total hour caps. A cap the
+// client doesn't have is nil.
+type ClientCapStatus struct {
+	Client  string    `json:"client"`
+	Year    int       `json:"year"`
+	Month   int       `json:"month"`
+	Monthly *CapUsage `json:"monthly"`
+	Total   *CapUsage `json:"total"`
+}
+
+// GetClientCapStatus totals the client hours logged for client in the given
+// month and over all time, and compares them with the client's caps
+func GetClientCapStatus(dl DataLayer, client Client, year, month int) (ClientCapStatus, error) {
+	status := ClientCapStatus{Client: client.Name, Year: year, Month: month}
+	if client.MonthlyHourCap <= 0 && client.TotalHourCap <= 0 {
+		return status, nil
+	}
+
+	entries, err := dl.GetAllTimesheetEntries(0, 0)
+	if err != nil {
+		return status, fmt.Errorf("failed to get timesheet entries: %w", err)
+	}
+	prefix := fmt.Sprintf("%04d-%02d-", year, month)
+	var monthHours, totalHours float64
+	for _, entry := range entries {
+		if entry.Client_name != client.Name || entry.Client_hours <= 0 {
+			continue
+		}
+		totalHours += entry.Client_hours
+		if strings.HasPrefix(entry.Date, prefix) {
+			monthHours += entry.Client_hours
+		}
+	}
+
+	threshold := config.GetCapWarningPercent()
+	if client.MonthlyHourCap > 0 {
+		status.Monthly = newCapUsage(client.MonthlyHourCap, monthHours, threshold)
+	}
+	if client.TotalHourCap > 0 {
+		status.Total = newCapUsage(client.TotalHourCap, totalHours, threshold)
+	}
+	return status, nil
+}
+
+func newCapUsage(limit int, logged float64, threshold int) *CapUsage {
+	usage := &CapUsage{
+		Cap:       limit,
+		Logged:    logged,
+		Remaining: float64(limit) - logged,
+		Percent:   logged / float64(limit) * 100,
+		Status:    CapOK,
+	}
+	switch {
+	case logged > float64(limit):
+		usage.Status = CapExceeded
+	case logged*100 >= float64(limit*threshold):
+		usage.Status = CapApproaching
+	}
+	return usage
+}
+
+// CapWarning returns a warning when the hours logged for the client of
+// entry are near or over one of its caps, counting the month of entry's
+// date. It returns "" for clients without a cap or that aren't in the
+// clients table.
+func CapWarning(dl DataLayer, entry TimesheetEntry) (string, error) {
+	if entry.Client_hours <= 0 {
+		return "", nil
+	}
+	date, err := time.Parse("2006-01-02", entry.Date)
+	if err != nil {
+		return "", nil
+	}
+	client, err := dl.GetClientByName(entry.Client_name)
+	if err != nil {
+		return "", nil
+	}
+	status, err := GetClientCapStatus(dl, client, date.Year(), int(date.Month()))
+	if err != nil {
+		return "", err
+	}
+
+	var warnings []string
+	for _, check := range []struct {
+		name  string
+		usage *CapUsage
+	}{
+		{"monthly", status.Monthly},
+		{"total", status.Total},
+	} {
+		switch {
+		case check.usage == nil || check.usage.Status == CapOK:
+		case check.usage.Status == CapExceeded:
+			warnings = append(warnings, fmt.Sprintf("%g of %d %s hours logged, %g over the cap",
+				check.usage.Logged, check.usage.Cap, check.name, -check.usage.Remaining))
+		default:
+			warnings = append(warnings, fmt.Sprintf("%g of %d %s hours logged, %g left",
+				check.usage.Logged, check.usage.Cap, check.name, check.usage.Remaining))
+		}
+	}
+	if len(warnings) == 0 {
+		return "", nil
+	}
+	return client.Name + ": " + strings.Join(warnings, "; "), nil
+}
@@ -0,0 +1,75 @@
+package db
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"timesheet/internal/config"
+)
+
+func TestGetClientCapStatus(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+
+	id, err := AddClient(Client{Name: "Fixed Scope", IsActive: true, MonthlyHourCap: 20, TotalHourCap: 30})
+	if err != nil {
+		t.Fatalf("AddClient failed: %v", err)
+	}
+	client, _ := GetClientById(id)
+	if client.MonthlyHourCap != 20 || client.TotalHourCap != 30 {
+		t.Fatalf("Expected caps of 20 and 30 hours, got %+v", client)
+	}
+
+	dl := &LocalDBLayer{}
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-01-31", Client_name: "Fixed Scope", Client_hours: 8})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-02-01", Client_name: "Fixed Scope", Client_hours: 8})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-02-02", Client_name: "Other Client", Client_hours: 8})
+
+	status, err := GetClientCapStatus(dl, client, 2024, 2)
+	if err != nil {
+		t.Fatalf("GetClientCapStatus failed: %v", err)
+	}
+	if status.Monthly == nil || status.Monthly.Logged != 8 || status.Monthly.Remaining != 12 || status.Monthly.Status != CapOK {
+		t.Errorf("Unexpected monthly usage: %+v", status.Monthly)
+	}
+	if status.Total == nil || status.Total.Logged != 16 || status.Total.Status != CapOK {
+		t.Errorf("Unexpected total usage: %+v", status.Total)
+	}
+	if warning, _ := CapWarning(dl, TimesheetEntry{Date: "2024-02-01", Client_name: "Fixed Scope", Client_hours: 8}); warning != "" {
+		t.Errorf("Expected no warning below the threshold, got %q", warning)
+	}
+
+	// 18 of 20 monthly hours passes the default 90%; 26 of 30 total hours does not
+	entry := TimesheetEntry{Date: "2024-02-05", Client_name: "Fixed Scope", Client_hours: 10}
+	AddTimesheetEntry(entry)
+	status, _ = GetClientCapStatus(dl, client, 2024, 2)
+	if status.Monthly.Status != CapApproaching || status.Total.Status != CapOK {
+		t.Errorf("Expected the monthly cap to be approaching, got %+v / %+v", status.Monthly, status.Total)
+	}
+	warning, err := CapWarning(dl, entry)
+	if err != nil || !strings.Contains(warning, "18 of 20 monthly hours logged, 2 left") {
+		t.Errorf("Unexpected warning %q (%v)", warning, err)
+	}
+
+	entry = TimesheetEntry{Date: "2024-02-06", Client_name: "Fixed Scope", Client_hours: 8}
+	AddTimesheetEntry(entry)
+	status, _ = GetClientCapStatus(dl, client, 2024, 2)
+	if status.Monthly.Status != CapExceeded || status.Monthly.Remaining != -6 || status.Total.Status != CapExceeded {
+		t.Errorf("Expected both caps to be exceeded, got %+v / %+v", status.Monthly, status.Total)
+	}
+	warning, _ = CapWarning(dl, entry)
+	if !strings.Contains(warning, "6 over the cap") || !strings.Contains(warning, "34 of 30 total hours") {
+		t.Errorf("Unexpected warning %q", warning)
+	}
+
+	// Clients without caps, or not in the clients table, never warn
+	if warning, _ := CapWarning(dl, TimesheetEntry{Date: "2024-02-02", Client_name: "Other Client", Client_hours: 8}); warning != "" {
+		t.Errorf("Expected no warning for an unknown client, got %q", warning)
+	}
+	if err := UpdateClient(Client{Id: id, Name: "Fixed Scope", IsActive: true, MonthlyHourCap: -1}); err == nil {
+		t.Error("Expected an error for a negative cap")
+	}
+}
@@ -2,16 +2,60 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
+	"math"
+	"slices"
+	"strconv"
+	"strings"
 	"time"
+	"timesheet/internal/config"
 )
 
+// ErrClientNotFound is returned when a client ID doesn't exist
+var ErrClientNotFound = errors.New("client not found")
+
+// ErrInvalidRate is returned when an hourly rate isn't a positive amount
+var ErrInvalidRate = errors.New("invalid hourly rate")
+
+// ErrInvalidRateEndDate is returned when a rate's end date isn't a date on
+// or after its effective date
+var ErrInvalidRateEndDate = errors.New("invalid rate end date")
+
 // Client represents a client record
 type Client struct {
 	Id        int
 	Name      string
 	CreatedAt string
 	IsActive  bool
+	Currency  string // ISO 4217 code the client is billed in; empty means the base currency
+	// MinBillableHours is the least a day with client hours is billed at;
+	// 0 means no minimum
+	MinBillableHours float64
+	// Contracted hour caps per calendar month and over the whole engagement;
+	// 0 means no cap
+	MonthlyHourCap int
+	TotalHourCap   int
+}
+
+// ValidateMinBillableHours checks a client's minimum billable hours fit in a
+// day. 0 means the client has no minimum.
+func ValidateMinBillableHours(hours float64) error {
+	if hours < 0 || hours > MaxDailyHours {
+		return fmt.Errorf("invalid minimum billable hours %v (must be between 0 and %d)", hours, MaxDailyHours)
+	}
+	return nil
+}
+
+// ValidateClient checks a client's minimum billable hours and hour caps
+func ValidateClient(client Client) error {
+	if err := ValidateMinBillableHours(client.MinBillableHours); err != nil {
+		return err
+	}
+	if client.MonthlyHourCap < 0 || client.TotalHourCap < 0 {
+		return errors.New("hour caps can't be negative")
+	}
+	return nil
 }
 
 // ClientRate represents a rate for a client at a specific date
@@ -20,6 +64,7 @@ type ClientRate struct {
 	ClientId      int
 	HourlyRate    float64
 	EffectiveDate string // YYYY-MM-DD format
+	EndDate       string // Last day the rate applies (YYYY-MM-DD); empty for open-ended rates
 	Notes         string
 	CreatedAt     string
 }
@@ -34,25 +79,40 @@ type ClientWithRates struct {
 type EarningsEntry struct {
 	Date        string
 	ClientName  string
-	ClientHours int
+	ClientHours float64
+	BilledHours float64 // ClientHours rounded up to the client's invoice unit; Earnings bills these
 	HourlyRate  float64
 	Earnings    float64
+	Currency    string // Currency of HourlyRate and Earnings
+
+	// BilledHours split at the overtime threshold, and what each part earns.
+	// Without overtime configured everything is regular.
+	RegularHours     float64
+	OvertimeHours    float64
+	RegularEarnings  float64
+	OvertimeEarnings float64
 }
 
 // EarningsOverview represents aggregated earnings for a period
 type EarningsOverview struct {
 	Year          int
 	Month         int // 0 for yearly, 1-12 for monthly
-	TotalHours    int
-	TotalEarnings float64
+	TotalHours    float64
+	TotalEarnings float64 // In Currency, converted with the configured exchange rates
+	Currency      string  // The base currency
+	ByCurrency    []CurrencyTotal
+	Unconverted   []string // Currencies without an exchange rate, left out of TotalEarnings
 	Entries       []EarningsEntry
+
+	BillableExpenses float64 // Billable expenses in the period, in Currency
+	TotalInvoiced    float64 // TotalEarnings plus BillableExpenses
 }
 
 // Client CRUD Operations
 
 // GetAllClients retrieves all clients from the database
 func GetAllClients() ([]Client, error) {
-	query := `SELECT id, name, created_at, is_active FROM clients ORDER BY name ASC`
+	query := `SELECT id, name, created_at, is_active, COALESCE(currency, ''), COALESCE(min_billable_hours, 0), COALESCE(monthly_hour_cap, 0), COALESCE(total_hour_cap, 0) FROM clients ORDER BY name ASC`
 
 	rows, err := db.Query(query)
 	if err != nil {
@@ -65,7 +125,7 @@ func GetAllClients() ([]Client, error) {
 	for rows.Next() {
 		var client Client
 		var isActive int
-		if err := rows.Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive); err != nil {
+		if err := rows.Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive, &client.Currency, &client.MinBillableHours, &client.MonthlyHourCap, &client.TotalHourCap); err != nil {
 			return nil, fmt.Errorf("failed to scan client: %w", err)
 		}
 		client.IsActive = isActive == 1
@@ -81,7 +141,7 @@ func GetAllClients() ([]Client, error) {
 
 // GetActiveClients retrieves only active clients
 func GetActiveClients() ([]Client, error) {
-	query := `SELECT id, name, created_at, is_active FROM clients WHERE is_active = 1 ORDER BY name ASC`
+	query := `SELECT id, name, created_at, is_active, COALESCE(currency, ''), COALESCE(min_billable_hours, 0), COALESCE(monthly_hour_cap, 0), COALESCE(total_hour_cap, 0) FROM clients WHERE is_active = 1 ORDER BY name ASC`
 
 	rows, err := db.Query(query)
 	if err != nil {
@@ -94,7 +154,7 @@ func GetActiveClients() ([]Client, error) {
 	for rows.Next() {
 		var client Client
 		var isActive int
-		if err := rows.Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive); err != nil {
+		if err := rows.Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive, &client.Currency, &client.MinBillableHours, &client.MonthlyHourCap, &client.TotalHourCap); err != nil {
 			return nil, fmt.Errorf("failed to scan client: %w", err)
 		}
 		client.IsActive = isActive == 1
@@ -110,14 +170,14 @@ func GetActiveClients() ([]Client, error) {
 
 // GetClientById retrieves a specific client by ID
 func GetClientById(id int) (Client, error) {
-	query := `SELECT id, name, created_at, is_active FROM clients WHERE id = ?`
+	query := `SELECT id, name, created_at, is_active, COALESCE(currency, ''), COALESCE(min_billable_hours, 0), COALESCE(monthly_hour_cap, 0), COALESCE(total_hour_cap, 0) FROM clients WHERE id = ?`
 
 	var client Client
 	var isActive int
-	err := db.QueryRow(query, id).Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive)
+	err := db.QueryRow(query, id).Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive, &client.Currency, &client.MinBillableHours, &client.MonthlyHourCap, &client.TotalHourCap)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return Client{}, fmt.Errorf("client not found")
+			return Client{}, ErrClientNotFound
 		}
 		return Client{}, fmt.Errorf("failed to query client: %w", err)
 	}
@@ -128,11 +188,11 @@ func GetClientById(id int) (Client, error) {
 
 // GetClientByName retrieves a specific client by name
 func GetClientByName(name string) (Client, error) {
-	query := `SELECT id, name, created_at, is_active FROM clients WHERE name = ?`
+	query := `SELECT id, name, created_at, is_active, COALESCE(currency, ''), COALESCE(min_billable_hours, 0), COALESCE(monthly_hour_cap, 0), COALESCE(total_hour_cap, 0) FROM clients WHERE name = ?`
 
 	var client Client
 	var isActive int
-	err := db.QueryRow(query, name).Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive)
+	err := db.QueryRow(query, name).Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive, &client.Currency, &client.MinBillableHours, &client.MonthlyHourCap, &client.TotalHourCap)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return Client{}, fmt.Errorf("client not found")
@@ -146,7 +206,21 @@ func GetClientByName(name string) (Client, error) {
 
 // AddClient creates a new client and returns the new client ID
 func AddClient(client Client) (int, error) {
-	query := `INSERT INTO clients (name, created_at, updated_at, is_active) VALUES (?, ?, ?, ?)`
+	return addClient(db, client)
+}
+
+// addClient inserts a client using ex, which may be the database or a
+// caller-owned transaction
+func addClient(ex sqlExecer, client Client) (int, error) {
+	query := `INSERT INTO clients (name, created_at, updated_at, is_active, currency, min_billable_hours, monthly_hour_cap, total_hour_cap) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+
+	currency, err := NormalizeCurrency(client.Currency)
+	if err != nil {
+		return 0, err
+	}
+	if err := ValidateClient(client); err != nil {
+		return 0, err
+	}
 
 	now := NowTimestamp()
 	isActive := 0
@@ -154,7 +228,7 @@ func AddClient(client Client) (int, error) {
 		isActive = 1
 	}
 
-	result, err := db.Exec(query, client.Name, now, now, isActive)
+	result, err := ex.Exec(query, client.Name, now, now, isActive, currency, client.MinBillableHours, client.MonthlyHourCap, client.TotalHourCap)
 	if err != nil {
 		return 0, fmt.Errorf("failed to add client: %w", err)
 	}
@@ -169,14 +243,22 @@ func AddClient(client Client) (int, error) {
 
 // UpdateClient updates an existing client
 func UpdateClient(client Client) error {
-	query := `UPDATE clients SET name = ?, is_active = ?, updated_at = ? WHERE id = ?`
+	query := `UPDATE clients SET name = ?, is_active = ?, currency = ?, min_billable_hours = ?, monthly_hour_cap = ?, total_hour_cap = ?, updated_at = ? WHERE id = ?`
+
+	currency, err := NormalizeCurrency(client.Currency)
+	if err != nil {
+		return err
+	}
+	if err := ValidateClient(client); err != nil {
+		return err
+	}
 
 	isActive := 0
 	if client.IsActive {
 		isActive = 1
 	}
 
-	result, err := db.Exec(query, client.Name, isActive, NowTimestamp(), client.Id)
+	result, err := db.Exec(query, client.Name, isActive, currency, client.MinBillableHours, client.MonthlyHourCap, client.TotalHourCap, NowTimestamp(), client.Id)
 	if err != nil {
 		return fmt.Errorf("failed to update client: %w", err)
 	}
@@ -272,19 +354,147 @@ func DeactivateClient(id int) error {
 	return nil
 }
 
+// EngagementEnd reports what EndClientEngagement changed
+type EngagementEnd struct {
+	Client      Client
+	EndDate     string
+	Deactivated bool        // False when the client was already inactive
+	ClosedRate  *ClientRate // The rate in effect on EndDate, now ending on it; nil when there was none
+}
+
+// engagementEndNote is the note added to the rate an engagement ended on
+func engagementEndNote(endDate string) string {
+	return "Engagement ended " + endDate
+}
+
+// EndClientEngagement deactivates a client and ends the rate in effect on
+// the end date there (setting its end date and noting it), in one
+// transaction. Ending an engagement again on the same date changes nothing.
+func EndClientEngagement(clientId int, endDate string) (EngagementEnd, error) {
+	if _, err := time.Parse("2006-01-02", endDate); err != nil {
+		return EngagementEnd{}, fmt.Errorf("invalid end date %q (expected YYYY-MM-DD)", endDate)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return EngagementEnd{}, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	end := EngagementEnd{EndDate: endDate}
+	var isActive int
+	err = tx.QueryRow(`SELECT id, name, created_at, is_active, COALESCE(currency, ''), COALESCE(min_billable_hours, 0), COALESCE(monthly_hour_cap, 0), COALESCE(total_hour_cap, 0) FROM clients WHERE id = ?`, clientId).
+		Scan(&end.Client.Id, &end.Client.Name, &end.Client.CreatedAt, &isActive, &end.Client.Currency, &end.Client.MinBillableHours, &end.Client.MonthlyHourCap, &end.Client.TotalHourCap)
+	if err == sql.ErrNoRows {
+		return EngagementEnd{}, ErrClientNotFound
+	}
+	if err != nil {
+		return EngagementEnd{}, fmt.Errorf("failed to look up client: %w", err)
+	}
+
+	now := NowTimestamp()
+	if isActive == 1 {
+		if _, err := tx.Exec(`UPDATE clients SET is_active = 0, updated_at = ? WHERE id = ?`, now, clientId); err != nil {
+			return EngagementEnd{}, fmt.Errorf("failed to deactivate client: %w", err)
+		}
+		end.Deactivated = true
+	}
+
+	var rate ClientRate
+	err = tx.QueryRow(`SELECT id, client_id, hourly_rate, effective_date, COALESCE(end_date, ''), notes, created_at
+	          FROM client_rates
+	          WHERE client_id = ? AND effective_date <= ?
+	          ORDER BY effective_date DESC, created_at DESC
+	          LIMIT 1`, clientId, endDate).
+		Scan(&rate.Id, &rate.ClientId, &rate.HourlyRate, &rate.EffectiveDate, &rate.EndDate, &rate.Notes, &rate.CreatedAt)
+	switch {
+	case err == sql.ErrNoRows:
+		// No rate to close
+	case err != nil:
+		return EngagementEnd{}, fmt.Errorf("failed to query client rate: %w", err)
+	default:
+		changed := false
+		note := engagementEndNote(endDate)
+		if !strings.Contains(rate.Notes, note) {
+			if rate.Notes != "" {
+				rate.Notes += "; "
+			}
+			rate.Notes += note
+			changed = true
+		}
+		// The rate stops applying after the end date
+		if rate.EndDate == "" || rate.EndDate > endDate {
+			rate.EndDate = endDate
+			changed = true
+		}
+		if changed {
+			if _, err := tx.Exec(`UPDATE client_rates SET notes = ?, end_date = ?, updated_at = ? WHERE id = ?`, rate.Notes, rate.EndDate, now, rate.Id); err != nil {
+				return EngagementEnd{}, fmt.Errorf("failed to update client rate: %w", err)
+			}
+		}
+		end.ClosedRate = &rate
+	}
+
+	if err := tx.Commit(); err != nil {
+		return EngagementEnd{}, fmt.Errorf("failed to commit: %w", err)
+	}
+	end.Client.IsActive = false
+	return end, nil
+}
+
 // Client Rate Operations
 
 // GetClientRates retrieves all rates for a specific client
 // Returns rates in descending order by effective_date (newest first)
 func GetClientRates(clientId int) ([]ClientRate, error) {
-	query := `SELECT id, client_id, hourly_rate, effective_date, notes, created_at
-	          FROM client_rates
-	          WHERE client_id = ?
+	rates, _, err := QueryClientRates(clientId, RateQuery{})
+	return rates, err
+}
+
+// RateQuery filters and pages a client's rate history. Zero values don't
+// filter; a Limit of 0 returns every matching rate.
+type RateQuery struct {
+	EffectiveAfter  string // Only rates effective after this date (YYYY-MM-DD)
+	EffectiveBefore string // Only rates effective before this date (YYYY-MM-DD)
+	Limit           int
+	Offset          int
+}
+
+// QueryClientRates returns a page of a client's rates, newest first, along
+// with the number of rates that match the filters across all pages
+func QueryClientRates(clientId int, q RateQuery) ([]ClientRate, int, error) {
+	where := " WHERE client_id = ?"
+	args := []any{clientId}
+	if q.EffectiveAfter != "" {
+		where += " AND effective_date > ?"
+		args = append(args, q.EffectiveAfter)
+	}
+	if q.EffectiveBefore != "" {
+		where += " AND effective_date < ?"
+		args = append(args, q.EffectiveBefore)
+	}
+
+	var total int
+	if err := db.QueryRow("SELECT COUNT(*) FROM client_rates"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count client rates: %w", err)
+	}
+
+	query := `SELECT id, client_id, hourly_rate, effective_date, COALESCE(end_date, ''), notes, created_at
+	          FROM client_rates` + where + `
 	          ORDER BY effective_date DESC, created_at DESC`
+	if q.Limit > 0 || q.Offset > 0 {
+		// SQLite needs a LIMIT before OFFSET; -1 means no limit
+		limit := q.Limit
+		if limit <= 0 {
+			limit = -1
+		}
+		query += " LIMIT ? OFFSET ?"
+		args = append(args, limit, q.Offset)
+	}
 
-	rows, err := db.Query(query, clientId)
+	rows, err := db.Query(query, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query client rates: %w", err)
+		return nil, 0, fmt.Errorf("failed to query client rates: %w", err)
 	}
 	defer rows.Close()
 
@@ -293,27 +503,27 @@ func GetClientRates(clientId int) ([]ClientRate, error) {
 	for rows.Next() {
 		var rate ClientRate
 		if err := rows.Scan(&rate.Id, &rate.ClientId, &rate.HourlyRate,
-			&rate.EffectiveDate, &rate.Notes, &rate.CreatedAt); err != nil {
-			return nil, fmt.Errorf("failed to scan client rate: %w", err)
+			&rate.EffectiveDate, &rate.EndDate, &rate.Notes, &rate.CreatedAt); err != nil {
+			return nil, 0, fmt.Errorf("failed to scan client rate: %w", err)
 		}
 		rates = append(rates, rate)
 	}
 
 	if err := rows.Err(); err != nil {
-		return nil, err
+		return nil, 0, err
 	}
 
-	return rates, nil
+	return rates, total, nil
 }
 
 // GetClientRateById retrieves a specific rate by ID
 func GetClientRateById(id int) (ClientRate, error) {
-	query := `SELECT id, client_id, hourly_rate, effective_date, notes, created_at
+	query := `SELECT id, client_id, hourly_rate, effective_date, COALESCE(end_date, ''), notes, created_at
 	          FROM client_rates WHERE id = ?`
 
 	var rate ClientRate
 	err := db.QueryRow(query, id).Scan(&rate.Id, &rate.ClientId, &rate.HourlyRate,
-		&rate.EffectiveDate, &rate.Notes, &rate.CreatedAt)
+		&rate.EffectiveDate, &rate.EndDate, &rate.Notes, &rate.CreatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return ClientRate{}, fmt.Errorf("client rate not found")
@@ -324,14 +534,86 @@ func GetClientRateById(id int) (ClientRate, error) {
 	return rate, nil
 }
 
+// NormalizeHourlyRate checks an hourly rate is positive and rounds it to the
+// configured precision (two decimals by default), so the stored rate is the
+// amount that's billed
+func NormalizeHourlyRate(rate float64) (float64, error) {
+	return roundHourlyRate(rate, config.GetRatePrecision())
+}
+
+// roundHourlyRate checks rate is positive and rounds it to decimals places
+func roundHourlyRate(rate float64, decimals int) (float64, error) {
+	if math.IsNaN(rate) || math.IsInf(rate, 0) || rate <= 0 {
+		return 0, fmt.Errorf("%w %v: must be positive", ErrInvalidRate, rate)
+	}
+	scale := math.Pow10(decimals)
+	rounded := math.Round(rate*scale) / scale
+	if rounded <= 0 {
+		return 0, fmt.Errorf("%w %v: rounds to zero at %d decimals", ErrInvalidRate, rate, decimals)
+	}
+	return rounded, nil
+}
+
+// ValidateRateEndDate checks a rate's optional end date is a YYYY-MM-DD
+// date on or after the day the rate takes effect
+func ValidateRateEndDate(rate ClientRate) error {
+	if rate.EndDate == "" {
+		return nil
+	}
+	if _, err := time.Parse("2006-01-02", rate.EndDate); err != nil {
+		return fmt.Errorf("%w %q: expected YYYY-MM-DD", ErrInvalidRateEndDate, rate.EndDate)
+	}
+	if rate.EndDate < rate.EffectiveDate {
+		return fmt.Errorf("%w %s: before the effective date %s", ErrInvalidRateEndDate, rate.EndDate, rate.EffectiveDate)
+	}
+	return nil
+}
+
+// Ended reports whether a fixed-term rate no longer applies on date
+func (r ClientRate) Ended(date string) bool {
+	if len(date) > 10 {
+		date = date[:10]
+	}
+	return r.EndDate != "" && date > r.EndDate
+}
+
+// rateOn returns the rate in effect on date from rates sorted newest first:
+// the most recent rate effective on or before date, unless it has ended by
+// then. A newer rate replaces an older one whether or not that one ended.
+func rateOn(rates []ClientRate, date string) (ClientRate, bool) {
+	for _, rate := range rates {
+		if rate.EffectiveDate <= date {
+			if rate.Ended(date) {
+				return ClientRate{}, false
+			}
+			return rate, true
+		}
+	}
+	return ClientRate{}, false
+}
+
 // AddClientRate adds a new rate for a client
 func AddClientRate(rate ClientRate) error {
-	query := `INSERT INTO client_rates (client_id, hourly_rate, effective_date, notes, created_at, updated_at)
-	          VALUES (?, ?, ?, ?, ?, ?)`
+	return addClientRate(db, rate)
+}
+
+// addClientRate inserts a rate using ex, which may be the database or a
+// caller-owned transaction
+func addClientRate(ex sqlExecer, rate ClientRate) error {
+	query := `INSERT INTO client_rates (client_id, hourly_rate, effective_date, end_date, notes, created_at, updated_at)
+	          VALUES (?, ?, ?, ?, ?, ?, ?)`
+
+	hourlyRate, err := NormalizeHourlyRate(rate.HourlyRate)
+	if err != nil {
+		return err
+	}
+	if err := ValidateRateEndDate(rate); err != nil {
+		return err
+	}
 
 	now := NowTimestamp()
 
-	_, err := db.Exec(query, rate.ClientId, rate.HourlyRate, rate.EffectiveDate, rate.Notes, now, now)
+	_, err = ex.Exec(query, rate.ClientId, hourlyRate, rate.EffectiveDate, rate.EndDate, rate.Notes, now, now)
 	if err != nil {
 		return fmt.Errorf("failed to add client rate: %w", err)
 	}
@@ -342,10 +624,18 @@ func AddClientRate(rate ClientRate) error {
 // UpdateClientRate updates an existing rate
 func UpdateClientRate(rate ClientRate) error {
 	query := `UPDATE client_rates
-	          SET hourly_rate = ?, effective_date = ?, notes = ?, updated_at = ?
+	          SET hourly_rate = ?, effective_date = ?, end_date = ?, notes = ?, updated_at = ?
 	          WHERE id = ?`
 
-	result, err := db.Exec(query, rate.HourlyRate, rate.EffectiveDate, rate.Notes, NowTimestamp(), rate.Id)
+	hourlyRate, err := NormalizeHourlyRate(rate.HourlyRate)
+	if err != nil {
+		return err
+	}
+	if err := ValidateRateEndDate(rate); err != nil {
+		return err
+	}
+
+	result, err := db.Exec(query, hourlyRate, rate.EffectiveDate, rate.EndDate, rate.Notes, NowTimestamp(), rate.Id)
 	if err != nil {
 		return fmt.Errorf("failed to update client rate: %w", err)
 	}
@@ -407,9 +697,10 @@ func DeleteClientRate(id int) error {
 // Rate Lookup Functions
 
 // GetClientRateForDate returns the rate that was effective on the given date
-// If multiple rates exist for the same date, returns the most recently created one
+// If multiple rates exist for the same date, returns the most recently created one.
+// A rate whose end date has passed no longer applies.
 func GetClientRateForDate(clientId int, date string) (ClientRate, error) {
-	query := `SELECT id, client_id, hourly_rate, effective_date, notes, created_at
+	query := `SELECT id, client_id, hourly_rate, effective_date, COALESCE(end_date, ''), notes, created_at
 	          FROM client_rates
 	          WHERE client_id = ? AND effective_date <= ?
 	          ORDER BY effective_date DESC, created_at DESC
@@ -417,13 +708,16 @@ func GetClientRateForDate(clientId int, date string) (ClientRate, error) {
 
 	var rate ClientRate
 	err := db.QueryRow(query, clientId, date).Scan(&rate.Id, &rate.ClientId,
-		&rate.HourlyRate, &rate.EffectiveDate, &rate.Notes, &rate.CreatedAt)
+		&rate.HourlyRate, &rate.EffectiveDate, &rate.EndDate, &rate.Notes, &rate.CreatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return ClientRate{}, fmt.Errorf("no rate found for client on date %s", date)
 		}
 		return ClientRate{}, fmt.Errorf("failed to query client rate: %w", err)
 	}
+	if rate.Ended(date) {
+		return ClientRate{}, fmt.Errorf("no rate found for client on date %s: rate ended on %s", date, rate.EndDate)
+	}
 
 	return rate, nil
 }
@@ -451,8 +745,10 @@ func GetClientRateByName(clientName string, date string) (float64, error) {
 
 // rateCache holds cached client and rate information for efficient lookups
 type rateCache struct {
-	clientsByName map[string]int              // clientName -> clientId
-	ratesByClient map[int][]ClientRate        // clientId -> sorted rates (newest first)
+	clientsByName map[string]int       // clientName -> clientId
+	ratesByClient map[int][]ClientRate // clientId -> sorted rates (newest first)
+	currencies    map[string]string    // clientName -> billing currency ("" = base)
+	minimums      map[string]float64   // clientName -> minimum billable hours
 }
 
 // buildRateCache creates a cache of all clients and their rates
@@ -461,6 +757,8 @@ func buildRateCache() (*rateCache, error) {
 	cache := &rateCache{
 		clientsByName: make(map[string]int),
 		ratesByClient: make(map[int][]ClientRate),
+		currencies:    make(map[string]string),
+		minimums:      make(map[string]float64),
 	}
 
 	// Load all clients into cache
@@ -470,10 +768,12 @@ func buildRateCache() (*rateCache, error) {
 	}
 	for _, client := range clients {
 		cache.clientsByName[client.Name] = client.Id
+		cache.currencies[client.Name] = client.Currency
+		cache.minimums[client.Name] = client.MinBillableHours
 	}
 
 	// Load all rates for all clients
-	query := `SELECT id, client_id, hourly_rate, effective_date, notes, created_at
+	query := `SELECT id, client_id, hourly_rate, effective_date, COALESCE(end_date, ''), notes, created_at
 	          FROM client_rates
 	          ORDER BY client_id, effective_date DESC`
 
@@ -486,7 +786,7 @@ func buildRateCache() (*rateCache, error) {
 	for rows.Next() {
 		var rate ClientRate
 		if err := rows.Scan(&rate.Id, &rate.ClientId, &rate.HourlyRate,
-			&rate.EffectiveDate, &rate.Notes, &rate.CreatedAt); err != nil {
+			&rate.EffectiveDate, &rate.EndDate, &rate.Notes, &rate.CreatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan rate: %w", err)
 		}
 		cache.ratesByClient[rate.ClientId] = append(cache.ratesByClient[rate.ClientId], rate)
@@ -496,7 +796,8 @@ func buildRateCache() (*rateCache, error) {
 }
 
 // getRateFromCache gets the rate for a client on a specific date from the cache
-// Returns the rate that was effective on the given date (most recent rate where effective_date <= date)
+// Returns the rate that was effective on the given date (most recent rate where
+// effective_date <= date), or 0 when there's none or it has ended
 func (c *rateCache) getRateFromCache(clientName string, date string) float64 {
 	// Get client ID
 	clientId, ok := c.clientsByName[clientName]
@@ -504,26 +805,28 @@ func (c *rateCache) getRateFromCache(clientName string, date string) float64 {
 		return 0.0
 	}
 
-	// Get rates for this client
-	rates, ok := c.ratesByClient[clientId]
-	if !ok || len(rates) == 0 {
-		return 0.0
-	}
-
-	// Find the most recent rate where effective_date <= date
 	// Rates are sorted by effective_date DESC (newest first)
-	for _, rate := range rates {
-		if rate.EffectiveDate <= date {
-			return rate.HourlyRate
-		}
+	rate, ok := rateOn(c.ratesByClient[clientId], date)
+	if !ok {
+		return 0.0
 	}
-
-	// No rate found for this date
-	return 0.0
+	return rate.HourlyRate
 }
 
 // CalculateEarningsForYear calculates total earnings for a specific year
 func CalculateEarningsForYear(year int) (EarningsOverview, error) {
+	return calculateEarningsForYear(year, "")
+}
+
+// CalculateEarningsForClient calculates the earnings of one client for a
+// specific year. A client without hours gets an empty overview.
+func CalculateEarningsForClient(year int, clientName string) (EarningsOverview, error) {
+	return calculateEarningsForYear(year, clientName)
+}
+
+// calculateEarningsForYear calculates the earnings for a year, of all
+// clients when clientName is empty
+func calculateEarningsForYear(year int, clientName string) (EarningsOverview, error) {
 	// Build rate cache once for all lookups - eliminates N+1 query problem
 	cache, err := buildRateCache()
 	if err != nil {
@@ -535,11 +838,27 @@ func CalculateEarningsForYear(year int) (EarningsOverview, error) {
 	if err != nil {
 		return EarningsOverview{}, fmt.Errorf("failed to get timesheet entries: %w", err)
 	}
+	expenses, err := GetExpenses(year, 0)
+	if err != nil {
+		return EarningsOverview{}, fmt.Errorf("failed to get expenses: %w", err)
+	}
+
+	if clientName != "" {
+		entries = slices.DeleteFunc(entries, func(entry TimesheetEntry) bool {
+			return entry.Client_name != clientName
+		})
+		expenses = slices.DeleteFunc(expenses, func(expense Expense) bool {
+			return expense.Client_name != clientName
+		})
+	}
+	return buildEarningsOverview(cache, loadInvoicing(cache.minimums), year, 0, entries, expenses), nil
+}
 
-	// Pre-allocate slice with capacity for typical year's work days (250-365)
-	earningsEntries := make([]EarningsEntry, 0, 300)
-	var totalHours int
-	var totalEarnings float64
+// buildEarningsOverview prices the client hours of entries with the rates in
+// cache and adds the billable expenses. month is 0 for a yearly overview.
+func buildEarningsOverview(cache *rateCache, invoices invoicing, year, month int, entries []TimesheetEntry, expenses []Expense) EarningsOverview {
+	earningsEntries := make([]EarningsEntry, 0, len(entries))
+	var totalHours float64
 
 	// For each entry, calculate earnings
 	for _, entry := range entries {
@@ -550,27 +869,23 @@ func CalculateEarningsForYear(year int) (EarningsOverview, error) {
 		// Get the rate from cache (no database query!)
 		rate := cache.getRateFromCache(entry.Client_name, entry.Date)
 
-		earnings := float64(entry.Client_hours) * rate
-
-		earningsEntries = append(earningsEntries, EarningsEntry{
-			Date:        entry.Date,
-			ClientName:  entry.Client_name,
-			ClientHours: entry.Client_hours,
-			HourlyRate:  rate,
-			Earnings:    earnings,
-		})
+		priced := invoices.price(entry.Client_name, entry.Client_hours, rate)
+		priced.Date = entry.Date
+		priced.Currency = cache.currencies[entry.Client_name]
+		earningsEntries = append(earningsEntries, priced)
 
 		totalHours += entry.Client_hours
-		totalEarnings += earnings
 	}
 
-	return EarningsOverview{
-		Year:          year,
-		Month:         0,
-		TotalHours:    totalHours,
-		TotalEarnings: totalEarnings,
-		Entries:       earningsEntries,
-	}, nil
+	overview := EarningsOverview{
+		Year:       year,
+		Month:      month,
+		TotalHours: totalHours,
+		Entries:    earningsEntries,
+	}
+	applyCurrencies(&overview)
+	applyExpenses(&overview, expenses, cache.currencies)
+	return overview
 }
 
 // CalculateEarningsSummaryForYear calculates earnings grouped by client and rate
@@ -580,6 +895,7 @@ func CalculateEarningsSummaryForYear(year int) (EarningsOverview, error) {
 	if err != nil {
 		return EarningsOverview{}, fmt.Errorf("failed to build rate cache: %w", err)
 	}
+	invoices := loadInvoicing(cache.minimums)
 
 	// Get all timesheet entries for the year with client_hours > 0
 	entries, err := GetAllTimesheetEntries(year, 0)
@@ -587,12 +903,12 @@ func CalculateEarningsSummaryForYear(year int) (EarningsOverview, error) {
 		return EarningsOverview{}, fmt.Errorf("failed to get timesheet entries: %w", err)
 	}
 
-	// Map to aggregate: key = "ClientName|Rate", value = total hours
+	// Map to aggregate: key = "ClientName|Rate", value = totals of the days
 	type ClientRateKey struct {
 		ClientName string
 		Rate       float64
 	}
-	aggregated := make(map[ClientRateKey]int)
+	aggregated := make(map[ClientRateKey]EarningsEntry)
 
 	// Aggregate hours by client and rate
 	for _, entry := range entries {
@@ -607,35 +923,37 @@ func CalculateEarningsSummaryForYear(year int) (EarningsOverview, error) {
 			ClientName: entry.Client_name,
 			Rate:       rate,
 		}
-		aggregated[key] += entry.Client_hours
+		aggregated[key] = aggregated[key].plus(invoices.price(entry.Client_name, entry.Client_hours, rate))
 	}
 
 	// Convert aggregated data to EarningsEntry slice
 	// Pre-allocate for number of unique client-rate combinations
 	earningsEntries := make([]EarningsEntry, 0, len(aggregated))
-	var totalHours int
-	var totalEarnings float64
-
-	for key, hours := range aggregated {
-		earnings := float64(hours) * key.Rate
-		earningsEntries = append(earningsEntries, EarningsEntry{
-			Date:        "", // No specific date in summary view
-			ClientName:  key.ClientName,
-			ClientHours: hours,
-			HourlyRate:  key.Rate,
-			Earnings:    earnings,
-		})
-		totalHours += hours
-		totalEarnings += earnings
+	var totalHours float64
+
+	for key, total := range aggregated {
+		total.Date = "" // No specific date in summary view
+		total.ClientName = key.ClientName
+		total.HourlyRate = key.Rate
+		total.Currency = cache.currencies[key.ClientName]
+		earningsEntries = append(earningsEntries, total)
+		totalHours += total.ClientHours
 	}
 
-	return EarningsOverview{
-		Year:          year,
-		Month:         0,
-		TotalHours:    totalHours,
-		TotalEarnings: totalEarnings,
-		Entries:       earningsEntries,
-	}, nil
+	overview := EarningsOverview{
+		Year:       year,
+		Month:      0,
+		TotalHours: totalHours,
+		Entries:    earningsEntries,
+	}
+	applyCurrencies(&overview)
+
+	expenses, err := GetExpenses(year, 0)
+	if err != nil {
+		return EarningsOverview{}, fmt.Errorf("failed to get expenses: %w", err)
+	}
+	applyExpenses(&overview, expenses, cache.currencies)
+	return overview, nil
 }
 
 // CalculateEarningsForMonth calculates total earnings for a specific month
@@ -651,42 +969,62 @@ func CalculateEarningsForMonth(year int, month int) (EarningsOverview, error) {
 	if err != nil {
 		return EarningsOverview{}, fmt.Errorf("failed to get timesheet entries: %w", err)
 	}
+	expenses, err := GetExpenses(year, month)
+	if err != nil {
+		return EarningsOverview{}, fmt.Errorf("failed to get expenses: %w", err)
+	}
+	return buildEarningsOverview(cache, loadInvoicing(cache.minimums), year, month, entries, expenses), nil
+}
 
-	// Pre-allocate slice with capacity for typical month's work days (20-30)
-	earningsEntries := make([]EarningsEntry, 0, 30)
-	var totalHours int
-	var totalEarnings float64
+// CalculateMonthlyEarningsForYear calculates the earnings of each month of
+// a year, January first. Months without client hours are included with zero
+// totals. The rates, entries and expenses are loaded once for the year.
+func CalculateMonthlyEarningsForYear(year int) ([]EarningsOverview, error) {
+	cache, err := buildRateCache()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build rate cache: %w", err)
+	}
+	invoices := loadInvoicing(cache.minimums)
 
-	// For each entry, calculate earnings
+	entries, err := GetAllTimesheetEntries(year, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timesheet entries: %w", err)
+	}
+	expenses, err := GetExpenses(year, 0)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get expenses: %w", err)
+	}
+
+	var entriesByMonth [12][]TimesheetEntry
 	for _, entry := range entries {
-		if entry.Client_hours <= 0 {
-			continue
+		if month := dateMonth(entry.Date); month != 0 {
+			entriesByMonth[month-1] = append(entriesByMonth[month-1], entry)
 		}
+	}
+	var expensesByMonth [12][]Expense
+	for _, expense := range expenses {
+		if month := dateMonth(expense.Date); month != 0 {
+			expensesByMonth[month-1] = append(expensesByMonth[month-1], expense)
+		}
+	}
 
-		// Get the rate from cache (no database query!)
-		rate := cache.getRateFromCache(entry.Client_name, entry.Date)
-
-		earnings := float64(entry.Client_hours) * rate
-
-		earningsEntries = append(earningsEntries, EarningsEntry{
-			Date:        entry.Date,
-			ClientName:  entry.Client_name,
-			ClientHours: entry.Client_hours,
-			HourlyRate:  rate,
-			Earnings:    earnings,
-		})
-
-		totalHours += entry.Client_hours
-		totalEarnings += earnings
+	months := make([]EarningsOverview, 12)
+	for i := range months {
+		months[i] = buildEarningsOverview(cache, invoices, year, i+1, entriesByMonth[i], expensesByMonth[i])
 	}
+	return months, nil
+}
 
-	return EarningsOverview{
-		Year:          year,
-		Month:         month,
-		TotalHours:    totalHours,
-		TotalEarnings: totalEarnings,
-		Entries:       earningsEntries,
-	}, nil
+// dateMonth returns the month of a YYYY-MM-DD date, or 0 when it has none
+func dateMonth(date string) int {
+	if len(date) < 7 {
+		return 0
+	}
+	month, err := strconv.Atoi(date[5:7])
+	if err != nil || month < 1 || month > 12 {
+		return 0
+	}
+	return month
 }
 
 // GetClientWithRates retrieves a client along with all their rate history
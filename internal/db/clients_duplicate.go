@@ -0,0 +1,67 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrClientExists is returned when a new client would reuse an existing name
+var ErrClientExists = errors.New("a client with that name already exists")
+
+// DuplicateClient copies a client and its whole rate history to a new,
+// active client called name, in one transaction. Each rate's effective date
+// moves by shiftDays (0 keeps the dates). Settings such as the currency and
+// hour caps are copied too.
+func DuplicateClient(clientId int, name string, shiftDays int) (ClientWithRates, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return ClientWithRates{}, errors.New("client name is required")
+	}
+
+	source, err := GetClientWithRates(clientId)
+	if err != nil {
+		return ClientWithRates{}, err
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return ClientWithRates{}, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	_, err = findClientIdTx(tx, name)
+	switch {
+	case err == nil:
+		return ClientWithRates{}, fmt.Errorf("%w: %s", ErrClientExists, name)
+	case !errors.Is(err, sql.ErrNoRows):
+		return ClientWithRates{}, err
+	}
+
+	copied := source.Client
+	copied.Name = name
+	copied.IsActive = true
+	newId, err := addClient(tx, copied)
+	if err != nil {
+		return ClientWithRates{}, err
+	}
+
+	for _, rate := range source.Rates {
+		effective, err := time.Parse("2006-01-02", rate.EffectiveDate)
+		if err != nil {
+			return ClientWithRates{}, fmt.Errorf("rate %d has an invalid effective date %q", rate.Id, rate.EffectiveDate)
+		}
+		rate.ClientId = newId
+		rate.EffectiveDate = effective.AddDate(0, 0, shiftDays).Format("2006-01-02")
+		if err := addClientRate(tx, rate); err != nil {
+			return ClientWithRates{}, err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return ClientWithRates{}, fmt.Errorf("failed to commit: %w", err)
+	}
+	return GetClientWithRates(newId)
+}
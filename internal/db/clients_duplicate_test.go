@@ -0,0 +1,51 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestDuplicateClient(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	id, _ := AddClient(Client{Name: "Acme Corp", IsActive: false, Currency: "USD", MonthlyHourCap: 120})
+	AddClientRate(ClientRate{ClientId: id, HourlyRate: 90, EffectiveDate: "2023-01-01", Notes: "Junior"})
+	AddClientRate(ClientRate{ClientId: id, HourlyRate: 100, EffectiveDate: "2024-01-01", Notes: "Senior"})
+
+	duplicate, err := DuplicateClient(id, " Beta Corp ", 366)
+	if err != nil {
+		t.Fatalf("DuplicateClient failed: %v", err)
+	}
+	if duplicate.Id == id || duplicate.Name != "Beta Corp" || !duplicate.IsActive {
+		t.Errorf("Expected a new active client, got %+v", duplicate.Client)
+	}
+	if duplicate.Currency != "USD" || duplicate.MonthlyHourCap != 120 {
+		t.Errorf("Expected the client settings to be copied, got %+v", duplicate.Client)
+	}
+	if len(duplicate.Rates) != 2 {
+		t.Fatalf("Expected 2 copied rates, got %+v", duplicate.Rates)
+	}
+	// Newest first, moved by a (leap) year
+	if duplicate.Rates[0].EffectiveDate != "2025-01-01" || duplicate.Rates[0].HourlyRate != 100 || duplicate.Rates[0].Notes != "Senior" {
+		t.Errorf("Unexpected newest rate: %+v", duplicate.Rates[0])
+	}
+	if duplicate.Rates[1].EffectiveDate != "2024-01-02" {
+		t.Errorf("Expected the oldest rate on 2024-01-02, got %+v", duplicate.Rates[1])
+	}
+
+	// The source is untouched
+	if rates, _ := GetClientRates(id); len(rates) != 2 || rates[0].EffectiveDate != "2024-01-01" {
+		t.Errorf("Expected the source rates to be unchanged, got %+v", rates)
+	}
+
+	if _, err := DuplicateClient(id, "Beta Corp", 0); !errors.Is(err, ErrClientExists) {
+		t.Errorf("Expected ErrClientExists, got %v", err)
+	}
+	if _, err := DuplicateClient(9999, "Gamma Corp", 0); !errors.Is(err, ErrClientNotFound) {
+		t.Errorf("Expected ErrClientNotFound, got %v", err)
+	}
+	if clients, _ := GetAllClients(); len(clients) != 2 {
+		t.Errorf("Expected 2 clients, got %d", len(clients))
+	}
+}
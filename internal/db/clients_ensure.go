@@ -0,0 +1,107 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ErrRateConflict is returned when a client already has a different rate on
+// the requested effective date
+var ErrRateConflict = errors.New("client already has a different rate on that date")
+
+// EnsuredClient reports what EnsureClient resolved and changed
+type EnsuredClient struct {
+	Client        Client
+	ClientCreated bool
+	Rate          *ClientRate // The rate on the effective date; nil when no rate was asked for
+	RateCreated   bool
+}
+
+// EnsureClient makes sure a client named name exists and, when rate is not
+// nil, that it has rate.HourlyRate from rate.EffectiveDate (today when
+// empty). Missing pieces are created in one transaction; calling it again
+// with the same arguments changes nothing. A different rate already stored
+// on that date is left alone and reported as ErrRateConflict.
+//
+// The client is inserted with ON CONFLICT DO NOTHING before it's read back,
+// so the transaction holds the write lock from its first statement and
+// concurrent callers can't both create the same client or rate.
+func EnsureClient(name string, rate *ClientRate) (EnsuredClient, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return EnsuredClient{}, errors.New("client name is required")
+	}
+
+	var wanted ClientRate
+	if rate != nil {
+		hourlyRate, err := NormalizeHourlyRate(rate.HourlyRate)
+		if err != nil {
+			return EnsuredClient{}, err
+		}
+		wanted = ClientRate{HourlyRate: hourlyRate, EffectiveDate: rate.EffectiveDate, Notes: rate.Notes}
+		if wanted.EffectiveDate == "" {
+			wanted.EffectiveDate = time.Now().Format("2006-01-02")
+		}
+		if _, err := time.Parse("2006-01-02", wanted.EffectiveDate); err != nil {
+			return EnsuredClient{}, fmt.Errorf("invalid effective date %q (expected YYYY-MM-DD)", wanted.EffectiveDate)
+		}
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return EnsuredClient{}, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	var ensured EnsuredClient
+	now := NowTimestamp()
+	result, err := tx.Exec(`INSERT INTO clients (name, created_at, updated_at, is_active) VALUES (?, ?, ?, 1)
+	          ON CONFLICT(name) DO NOTHING`, name, now, now)
+	if err != nil {
+		return EnsuredClient{}, fmt.Errorf("failed to add client: %w", err)
+	}
+	if inserted, err := result.RowsAffected(); err == nil && inserted > 0 {
+		ensured.ClientCreated = true
+	}
+
+	var isActive int
+	client := &ensured.Client
+	err = tx.QueryRow(`SELECT id, name, created_at, is_active, COALESCE(currency, ''), COALESCE(min_billable_hours, 0), COALESCE(monthly_hour_cap, 0), COALESCE(total_hour_cap, 0) FROM clients WHERE name = ?`, name).
+		Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive, &client.Currency, &client.MinBillableHours, &client.MonthlyHourCap, &client.TotalHourCap)
+	if err != nil {
+		return EnsuredClient{}, fmt.Errorf("failed to look up client: %w", err)
+	}
+	client.IsActive = isActive == 1
+
+	if rate != nil {
+		wanted.ClientId = client.Id
+		existing, err := findClientRateTx(tx, client.Id, wanted.EffectiveDate)
+		switch {
+		case errors.Is(err, sql.ErrNoRows):
+			if err := addClientRate(tx, wanted); err != nil {
+				return EnsuredClient{}, err
+			}
+			ensured.RateCreated = true
+		case err != nil:
+			return EnsuredClient{}, err
+		case existing != wanted.HourlyRate:
+			return EnsuredClient{}, fmt.Errorf("%w: %s has %.2f on %s", ErrRateConflict, name, existing, wanted.EffectiveDate)
+		}
+		var stored ClientRate
+		err = tx.QueryRow(`SELECT id, client_id, hourly_rate, effective_date, COALESCE(end_date, ''), COALESCE(notes, ''), created_at
+		          FROM client_rates WHERE client_id = ? AND effective_date = ? ORDER BY id LIMIT 1`, client.Id, wanted.EffectiveDate).
+			Scan(&stored.Id, &stored.ClientId, &stored.HourlyRate, &stored.EffectiveDate, &stored.EndDate, &stored.Notes, &stored.CreatedAt)
+		if err != nil {
+			return EnsuredClient{}, fmt.Errorf("failed to query client rate: %w", err)
+		}
+		ensured.Rate = &stored
+	}
+
+	if err := tx.Commit(); err != nil {
+		return EnsuredClient{}, fmt.Errorf("failed to commit: %w", err)
+	}
+	return ensured, nil
+}
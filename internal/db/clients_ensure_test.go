@@ -0,0 +1,69 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestEnsureClient(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	rate := &ClientRate{HourlyRate: 95, EffectiveDate: "2024-01-01"}
+	ensured, err := EnsureClient(" Acme Corp ", rate)
+	if err != nil {
+		t.Fatalf("EnsureClient failed: %v", err)
+	}
+	if !ensured.ClientCreated || !ensured.RateCreated {
+		t.Errorf("Expected the client and rate to be created, got %+v", ensured)
+	}
+	if ensured.Client.Name != "Acme Corp" || !ensured.Client.IsActive {
+		t.Errorf("Unexpected client: %+v", ensured.Client)
+	}
+	if ensured.Rate == nil || ensured.Rate.Id == 0 || ensured.Rate.HourlyRate != 95 {
+		t.Errorf("Unexpected rate: %+v", ensured.Rate)
+	}
+
+	// Repeating the call changes nothing
+	again, err := EnsureClient("Acme Corp", rate)
+	if err != nil {
+		t.Fatalf("EnsureClient failed: %v", err)
+	}
+	if again.ClientCreated || again.RateCreated || again.Client.Id != ensured.Client.Id || again.Rate.Id != ensured.Rate.Id {
+		t.Errorf("Expected the existing client and rate, got %+v", again)
+	}
+	clients, _ := GetAllClients()
+	rates, _ := GetClientRates(ensured.Client.Id)
+	if len(clients) != 1 || len(rates) != 1 {
+		t.Errorf("Expected 1 client and 1 rate, got %d and %d", len(clients), len(rates))
+	}
+
+	// A new rate is added to the existing client
+	newer, err := EnsureClient("Acme Corp", &ClientRate{HourlyRate: 105, EffectiveDate: "2025-01-01"})
+	if err != nil {
+		t.Fatalf("EnsureClient failed: %v", err)
+	}
+	if newer.ClientCreated || !newer.RateCreated {
+		t.Errorf("Expected only the rate to be created, got %+v", newer)
+	}
+
+	// Without a rate only the client is ensured
+	if plain, err := EnsureClient("Acme Corp", nil); err != nil || plain.Rate != nil || plain.ClientCreated {
+		t.Errorf("Expected the existing client without a rate, got %+v (%v)", plain, err)
+	}
+
+	_, err = EnsureClient("Acme Corp", &ClientRate{HourlyRate: 80, EffectiveDate: "2024-01-01"})
+	if !errors.Is(err, ErrRateConflict) {
+		t.Errorf("Expected ErrRateConflict, got %v", err)
+	}
+	if rates, _ := GetClientRates(ensured.Client.Id); len(rates) != 2 {
+		t.Errorf("Expected the conflicting rate to be left out, got %+v", rates)
+	}
+
+	if _, err := EnsureClient("  ", nil); err == nil {
+		t.Error("Expected an error for an empty name")
+	}
+	if _, err := EnsureClient("Acme Corp", &ClientRate{HourlyRate: -1}); err == nil {
+		t.Error("Expected an error for a negative rate")
+	}
+}
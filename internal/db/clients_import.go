@@ -0,0 +1,187 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ClientImportIssue describes a CSV row that was not imported
+type ClientImportIssue struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// ClientImportReport summarizes the outcome of ImportClientsCSV
+type ClientImportReport struct {
+	ClientsCreated int                 `json:"clients_created"`
+	ClientsReused  int                 `json:"clients_reused"`
+	RatesCreated   int                 `json:"rates_created"`
+	RatesSkipped   int                 `json:"rates_skipped"` // Identical rate already present for that client and date
+	Conflicts      []ClientImportIssue `json:"conflicts"`
+	Unmatched      []ClientImportIssue `json:"unmatched"`
+}
+
+// ImportClientsCSV reads clients and their rate history from CSV and adds
+// them to the database in a single transaction.
+//
+// Expected columns: client, hourly_rate, effective_date (YYYY-MM-DD), and an
+// optional notes column. A header row is skipped when present. A row with
+// only a client name creates the client without a rate. Existing clients are
+// reused; a rate that differs from one already stored for the same client
+// and date is reported as a conflict and left untouched. Malformed rows are
+// reported as unmatched. Any database error rolls back the whole import.
+func ImportClientsCSV(r io.Reader) (ClientImportReport, error) {
+	var report ClientImportReport
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return report, fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return report, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	// Clients seen in this import, so repeated names map to one row
+	clientIds := map[string]int{}
+
+	for i, record := range records {
+		line := i + 1
+		if i == 0 && isClientImportHeader(record) {
+			continue
+		}
+		if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") {
+			continue
+		}
+
+		name := strings.TrimSpace(record[0])
+		if name == "" {
+			report.Unmatched = append(report.Unmatched, ClientImportIssue{Line: line, Reason: "missing client name"})
+			continue
+		}
+
+		var rate ClientRate
+		hasRate := len(record) > 1 && strings.TrimSpace(record[1]) != ""
+		if hasRate {
+			rate, err = parseClientImportRate(record)
+			if err != nil {
+				report.Unmatched = append(report.Unmatched, ClientImportIssue{Line: line, Reason: err.Error()})
+				continue
+			}
+		}
+
+		clientId, ok := clientIds[name]
+		if !ok {
+			clientId, err = findClientIdTx(tx, name)
+			switch {
+			case err == nil:
+				report.ClientsReused++
+			case errors.Is(err, sql.ErrNoRows):
+				clientId, err = addClient(tx, Client{Name: name, IsActive: true})
+				if err != nil {
+					return report, err
+				}
+				report.ClientsCreated++
+			default:
+				return report, err
+			}
+			clientIds[name] = clientId
+		}
+
+		if !hasRate {
+			continue
+		}
+
+		existing, err := findClientRateTx(tx, clientId, rate.EffectiveDate)
+		switch {
+		case err == nil:
+			if existing == rate.HourlyRate {
+				report.RatesSkipped++
+			} else {
+				report.Conflicts = append(report.Conflicts, ClientImportIssue{
+					Line:   line,
+					Reason: fmt.Sprintf("%s already has rate %.2f on %s (CSV has %.2f)", name, existing, rate.EffectiveDate, rate.HourlyRate),
+				})
+			}
+			continue
+		case !errors.Is(err, sql.ErrNoRows):
+			return report, err
+		}
+
+		rate.ClientId = clientId
+		if err := addClientRate(tx, rate); err != nil {
+			return report, err
+		}
+		report.RatesCreated++
+	}
+
+	if err := tx.Commit(); err != nil {
+		return report, fmt.Errorf("failed to commit import: %w", err)
+	}
+	return report, nil
+}
+
+// isClientImportHeader reports whether the first CSV row is a header
+func isClientImportHeader(record []string) bool {
+	if len(record) < 2 {
+		return strings.EqualFold(strings.TrimSpace(record[0]), "client")
+	}
+	_, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+	return err != nil && strings.TrimSpace(record[1]) != ""
+}
+
+// parseClientImportRate validates the rate columns of a CSV row
+func parseClientImportRate(record []string) (ClientRate, error) {
+	hourlyRate, err := strconv.ParseFloat(strings.TrimSpace(record[1]), 64)
+	if err == nil {
+		hourlyRate, err = NormalizeHourlyRate(hourlyRate)
+	}
+	if err != nil {
+		return ClientRate{}, fmt.Errorf("invalid hourly rate %q", record[1])
+	}
+	if len(record) < 3 || strings.TrimSpace(record[2]) == "" {
+		return ClientRate{}, fmt.Errorf("missing effective date")
+	}
+	date := strings.TrimSpace(record[2])
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return ClientRate{}, fmt.Errorf("invalid effective date %q (expected YYYY-MM-DD)", date)
+	}
+
+	rate := ClientRate{HourlyRate: hourlyRate, EffectiveDate: date}
+	if len(record) > 3 {
+		rate.Notes = strings.TrimSpace(record[3])
+	}
+	return rate, nil
+}
+
+// findClientIdTx looks up a client id by name inside tx
+func findClientIdTx(tx *sql.Tx, name string) (int, error) {
+	var id int
+	err := tx.QueryRow(`SELECT id FROM clients WHERE name = ?`, name).Scan(&id)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("failed to query client: %w", err)
+	}
+	return id, err
+}
+
+// findClientRateTx looks up the rate stored for a client on an exact date inside tx
+func findClientRateTx(tx *sql.Tx, clientId int, effectiveDate string) (float64, error) {
+	var rate float64
+	err := tx.QueryRow(`SELECT hourly_rate FROM client_rates WHERE client_id = ? AND effective_date = ? LIMIT 1`,
+		clientId, effectiveDate).Scan(&rate)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, fmt.Errorf("failed to query client rate: %w", err)
+	}
+	return rate, err
+}
@@ -0,0 +1,92 @@
+package db
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestImportClientsCSV(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	// Pre-existing client with a rate that conflicts with the CSV
+	existingId, err := AddClient(Client{Name: "Globex", IsActive: true})
+	if err != nil {
+		t.Fatalf("AddClient failed: %v", err)
+	}
+	if err := AddClientRate(ClientRate{ClientId: existingId, HourlyRate: 90, EffectiveDate: "2024-01-01"}); err != nil {
+		t.Fatalf("AddClientRate failed: %v", err)
+	}
+
+	csvData := `client,hourly_rate,effective_date,notes
+Acme Corp,100,2023-01-01,start
+Acme Corp,110.50,2024-01-01,indexation
+Globex,95,2024-01-01,
+Globex,90,2024-01-01,
+Initech,,,
+,80,2024-01-01,
+Umbrella,abc,2024-01-01,
+Umbrella,80,01-01-2024,
+`
+	report, err := ImportClientsCSV(strings.NewReader(csvData))
+	if err != nil {
+		t.Fatalf("ImportClientsCSV failed: %v", err)
+	}
+
+	if report.ClientsCreated != 2 {
+		t.Errorf("Expected 2 clients created (Acme Corp, Initech), got %d", report.ClientsCreated)
+	}
+	if report.ClientsReused != 1 {
+		t.Errorf("Expected 1 client reused (Globex), got %d", report.ClientsReused)
+	}
+	if report.RatesCreated != 2 {
+		t.Errorf("Expected 2 rates created, got %d", report.RatesCreated)
+	}
+	if report.RatesSkipped != 1 {
+		t.Errorf("Expected 1 identical rate skipped, got %d", report.RatesSkipped)
+	}
+	if len(report.Conflicts) != 1 || report.Conflicts[0].Line != 4 {
+		t.Errorf("Expected one conflict on line 4, got %+v", report.Conflicts)
+	}
+	if len(report.Unmatched) != 3 {
+		t.Errorf("Expected 3 unmatched rows, got %+v", report.Unmatched)
+	}
+
+	acme, err := GetClientByName("Acme Corp")
+	if err != nil {
+		t.Fatalf("GetClientByName failed: %v", err)
+	}
+	rates, err := GetClientRates(acme.Id)
+	if err != nil {
+		t.Fatalf("GetClientRates failed: %v", err)
+	}
+	if len(rates) != 2 {
+		t.Errorf("Expected 2 rates for Acme Corp, got %d", len(rates))
+	}
+
+	// Conflicting rate must be left untouched
+	rate, err := GetClientRateByName("Globex", "2024-06-01")
+	if err != nil {
+		t.Fatalf("GetClientRateByName failed: %v", err)
+	}
+	if rate != 90 {
+		t.Errorf("Expected Globex rate to stay 90, got %.2f", rate)
+	}
+
+	if _, err := GetClientByName("Umbrella"); err == nil {
+		t.Errorf("Expected Umbrella not to be created from unmatched rows")
+	}
+}
+
+func TestImportClientsCSVWithoutHeader(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	report, err := ImportClientsCSV(strings.NewReader("Acme Corp,100,2024-01-01\n"))
+	if err != nil {
+		t.Fatalf("ImportClientsCSV failed: %v", err)
+	}
+	if report.ClientsCreated != 1 || report.RatesCreated != 1 {
+		t.Errorf("Expected 1 client and 1 rate, got %+v", report)
+	}
+}
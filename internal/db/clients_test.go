@@ -1,6 +1,8 @@
 package db
 
 import (
+	"errors"
+	"math"
 	"testing"
 	"time"
 )
@@ -134,6 +136,56 @@ func TestDeactivateClient(t *testing.T) {
 	}
 }
 
+func TestEndClientEngagement(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	id, _ := AddClient(Client{Name: "Test Client", IsActive: true})
+	AddClientRate(ClientRate{ClientId: id, HourlyRate: 100, EffectiveDate: "2024-01-01", Notes: "Initial"})
+	AddClientRate(ClientRate{ClientId: id, HourlyRate: 120, EffectiveDate: "2024-07-01"})
+
+	end, err := EndClientEngagement(id, "2024-06-30")
+	if err != nil {
+		t.Fatalf("EndClientEngagement failed: %v", err)
+	}
+	if !end.Deactivated {
+		t.Error("Expected the client to be reported as deactivated")
+	}
+	if end.ClosedRate == nil || end.ClosedRate.HourlyRate != 100 {
+		t.Fatalf("Expected the rate in effect on the end date to be closed, got %+v", end.ClosedRate)
+	}
+	if end.ClosedRate.Notes != "Initial; Engagement ended 2024-06-30" {
+		t.Errorf("Unexpected rate notes %q", end.ClosedRate.Notes)
+	}
+	if end.ClosedRate.EndDate != "2024-06-30" {
+		t.Errorf("Expected the rate to end on 2024-06-30, got %q", end.ClosedRate.EndDate)
+	}
+
+	client, _ := GetClientById(id)
+	if client.IsActive {
+		t.Error("Expected client to be inactive")
+	}
+
+	// Ending again on the same date changes nothing
+	end, err = EndClientEngagement(id, "2024-06-30")
+	if err != nil {
+		t.Fatalf("EndClientEngagement failed: %v", err)
+	}
+	if end.Deactivated {
+		t.Error("Expected an inactive client not to be deactivated again")
+	}
+	if end.ClosedRate.Notes != "Initial; Engagement ended 2024-06-30" {
+		t.Errorf("Expected the note not to be added twice, got %q", end.ClosedRate.Notes)
+	}
+
+	if _, err := EndClientEngagement(9999, "2024-06-30"); !errors.Is(err, ErrClientNotFound) {
+		t.Errorf("Expected ErrClientNotFound, got %v", err)
+	}
+	if _, err := EndClientEngagement(id, "30-06-2024"); err == nil {
+		t.Error("Expected an invalid end date to be rejected")
+	}
+}
+
 func TestDeleteClient(t *testing.T) {
 	dbPath := setupTestDB(t)
 	defer teardownTestDB(t, dbPath)
@@ -311,6 +363,67 @@ func TestUpdateClientRate(t *testing.T) {
 	}
 }
 
+func TestClientRateValidation(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	clientId, _ := AddClient(Client{Name: "Test Client", IsActive: true})
+
+	if err := AddClientRate(ClientRate{ClientId: clientId, HourlyRate: 100.005, EffectiveDate: "2024-01-01"}); err != nil {
+		t.Fatalf("AddClientRate failed: %v", err)
+	}
+	rates, _ := GetClientRates(clientId)
+	if len(rates) != 1 || rates[0].HourlyRate != 100.01 {
+		t.Fatalf("Expected the rate to be stored as 100.01, got %+v", rates)
+	}
+
+	for _, bad := range []float64{0, -50, 0.001} {
+		if err := AddClientRate(ClientRate{ClientId: clientId, HourlyRate: bad, EffectiveDate: "2024-02-01"}); !errors.Is(err, ErrInvalidRate) {
+			t.Errorf("Expected rate %v to be rejected with ErrInvalidRate, got %v", bad, err)
+		}
+	}
+
+	rate := rates[0]
+	rate.HourlyRate = -1
+	if err := UpdateClientRate(rate); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("Expected a negative update to be rejected, got %v", err)
+	}
+	rate.HourlyRate = 99.994
+	if err := UpdateClientRate(rate); err != nil {
+		t.Fatalf("UpdateClientRate failed: %v", err)
+	}
+	updated, _ := GetClientRateById(rate.Id)
+	if updated.HourlyRate != 99.99 {
+		t.Errorf("Expected the update to be stored as 99.99, got %v", updated.HourlyRate)
+	}
+}
+
+func TestRoundHourlyRate(t *testing.T) {
+	tests := []struct {
+		rate     float64
+		decimals int
+		want     float64
+	}{
+		{100.005, 2, 100.01},
+		{100.004, 2, 100},
+		{87.5, 0, 88},
+		{12.34567, 4, 12.3457},
+	}
+	for _, tt := range tests {
+		got, err := roundHourlyRate(tt.rate, tt.decimals)
+		if err != nil || got != tt.want {
+			t.Errorf("roundHourlyRate(%v, %d) = %v, %v; want %v", tt.rate, tt.decimals, got, err, tt.want)
+		}
+	}
+
+	if _, err := roundHourlyRate(0.4, 0); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("Expected a rate rounding to zero to be rejected, got %v", err)
+	}
+	if _, err := roundHourlyRate(math.NaN(), 2); !errors.Is(err, ErrInvalidRate) {
+		t.Errorf("Expected NaN to be rejected, got %v", err)
+	}
+}
+
 func TestDeleteClientRate(t *testing.T) {
 	dbPath := setupTestDB(t)
 	defer teardownTestDB(t, dbPath)
@@ -370,11 +483,11 @@ func TestCalculateEarningsForYear(t *testing.T) {
 		t.Fatalf("CalculateEarningsForYear failed: %v", err)
 	}
 
-	expectedHours := 23
+	expectedHours := 23.0
 	expectedEarnings := 2300.00
 
 	if earnings.TotalHours != expectedHours {
-		t.Errorf("Expected %d hours, got %d", expectedHours, earnings.TotalHours)
+		t.Errorf("Expected %g hours, got %g", expectedHours, earnings.TotalHours)
 	}
 	if earnings.TotalEarnings != expectedEarnings {
 		t.Errorf("Expected earnings %.2f, got %.2f", expectedEarnings, earnings.TotalEarnings)
@@ -437,6 +550,64 @@ func TestCalculateEarningsWithRateChange(t *testing.T) {
 	}
 }
 
+func TestClientRateEndDate(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	clientId, _ := AddClient(Client{Name: "Fixed Term", IsActive: true})
+	if err := AddClientRate(ClientRate{ClientId: clientId, HourlyRate: 100, EffectiveDate: "2024-01-01", EndDate: "2024-03-31"}); err != nil {
+		t.Fatalf("AddClientRate failed: %v", err)
+	}
+
+	// The rate applies through its end date, not after it
+	if rate, err := GetClientRateForDate(clientId, "2024-03-31"); err != nil || rate.HourlyRate != 100 || rate.EndDate != "2024-03-31" {
+		t.Errorf("Expected the rate on its last day, got %+v, %v", rate, err)
+	}
+	if _, err := GetClientRateForDate(clientId, "2024-04-01"); err == nil {
+		t.Error("Expected no rate after the end date")
+	}
+	if rate, _ := GetClientRateByName("Fixed Term", "2024-04-01"); rate != 0 {
+		t.Errorf("Expected rate 0 after the end date, got %v", rate)
+	}
+
+	// Hours after the end are priced at zero
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-03-29", Client_name: "Fixed Term", Client_hours: 8})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-04-02", Client_name: "Fixed Term", Client_hours: 8})
+	earnings, err := CalculateEarningsForYear(2024)
+	if err != nil {
+		t.Fatalf("CalculateEarningsForYear failed: %v", err)
+	}
+	if earnings.TotalEarnings != 800 {
+		t.Errorf("Expected earnings 800, got %.2f", earnings.TotalEarnings)
+	}
+
+	// A newer rate takes over again
+	if err := AddClientRate(ClientRate{ClientId: clientId, HourlyRate: 120, EffectiveDate: "2024-04-02"}); err != nil {
+		t.Fatalf("AddClientRate failed: %v", err)
+	}
+	if rate, _ := GetClientRateByName("Fixed Term", "2024-04-02"); rate != 120 {
+		t.Errorf("Expected the newer rate 120, got %v", rate)
+	}
+
+	// Removing the end date makes the rate open-ended
+	rates, _ := GetClientRates(clientId)
+	first := rates[len(rates)-1]
+	first.EndDate = ""
+	if err := UpdateClientRate(first); err != nil {
+		t.Fatalf("UpdateClientRate failed: %v", err)
+	}
+	if rate, _ := GetClientRateByName("Fixed Term", "2024-04-01"); rate != 100 {
+		t.Errorf("Expected the open-ended rate 100, got %v", rate)
+	}
+
+	for _, endDate := range []string{"2023-12-31", "31-03-2024"} {
+		err := AddClientRate(ClientRate{ClientId: clientId, HourlyRate: 100, EffectiveDate: "2024-01-01", EndDate: endDate})
+		if !errors.Is(err, ErrInvalidRateEndDate) {
+			t.Errorf("End date %s: expected ErrInvalidRateEndDate, got %v", endDate, err)
+		}
+	}
+}
+
 func TestCalculateEarningsForMonth(t *testing.T) {
 	dbPath := setupTestDB(t)
 	defer teardownTestDB(t, dbPath)
@@ -466,11 +637,11 @@ func TestCalculateEarningsForMonth(t *testing.T) {
 		t.Fatalf("CalculateEarningsForMonth failed: %v", err)
 	}
 
-	expectedHours := 15    // 10 + 5
+	expectedHours := 15.0  // 10 + 5
 	expectedEarnings := 1500.00 // 15 * 100
 
 	if earnings.TotalHours != expectedHours {
-		t.Errorf("Expected %d hours, got %d", expectedHours, earnings.TotalHours)
+		t.Errorf("Expected %g hours, got %g", expectedHours, earnings.TotalHours)
 	}
 	if earnings.TotalEarnings != expectedEarnings {
 		t.Errorf("Expected earnings %.2f, got %.2f", expectedEarnings, earnings.TotalEarnings)
@@ -480,6 +651,87 @@ func TestCalculateEarningsForMonth(t *testing.T) {
 	}
 }
 
+func TestCalculateEarningsForClient(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	acmeId, _ := AddClient(Client{Name: "Acme Corp", IsActive: true})
+	globexId, _ := AddClient(Client{Name: "Globex", IsActive: true})
+	AddClientRate(ClientRate{ClientId: acmeId, HourlyRate: 100, EffectiveDate: "2024-01-01"})
+	AddClientRate(ClientRate{ClientId: globexId, HourlyRate: 80, EffectiveDate: "2024-01-01"})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-01-15", Client_name: "Acme Corp", Client_hours: 8})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-01-16", Client_name: "Globex", Client_hours: 6})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-02-01", Client_name: "Acme Corp", Client_hours: 4})
+	AddExpense(Expense{Date: "2024-01-15", Client_name: "Acme Corp", Description: "Train ticket", Amount: 40, Billable: true})
+	AddExpense(Expense{Date: "2024-01-16", Client_name: "Globex", Description: "Hotel", Amount: 90, Billable: true})
+
+	earnings, err := CalculateEarningsForClient(2024, "Acme Corp")
+	if err != nil {
+		t.Fatalf("CalculateEarningsForClient failed: %v", err)
+	}
+	if earnings.TotalHours != 12 || earnings.TotalEarnings != 1200 || len(earnings.Entries) != 2 {
+		t.Errorf("Expected 12 hours and 1200 earnings over 2 entries, got %+v", earnings)
+	}
+	for _, entry := range earnings.Entries {
+		if entry.ClientName != "Acme Corp" {
+			t.Errorf("Expected only Acme Corp entries, got %+v", entry)
+		}
+	}
+	if earnings.BillableExpenses != 40 {
+		t.Errorf("Expected only Acme Corp's expenses, got %.2f", earnings.BillableExpenses)
+	}
+
+	// A client without hours gets an empty overview
+	earnings, err = CalculateEarningsForClient(2024, "Initech")
+	if err != nil {
+		t.Fatalf("CalculateEarningsForClient failed: %v", err)
+	}
+	if earnings.TotalHours != 0 || earnings.TotalEarnings != 0 || len(earnings.Entries) != 0 {
+		t.Errorf("Expected an empty overview, got %+v", earnings)
+	}
+}
+
+func TestCalculateMonthlyEarningsForYear(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	clientId, _ := AddClient(Client{Name: "Test Client", IsActive: true})
+	AddClientRate(ClientRate{ClientId: clientId, HourlyRate: 100, EffectiveDate: "2024-01-01"})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-01-15", Client_name: "Test Client", Client_hours: 8})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-03-15", Client_name: "Test Client", Client_hours: 6})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-03-18", Client_name: "Test Client", Client_hours: 4})
+	AddTimesheetEntry(TimesheetEntry{Date: "2025-03-18", Client_name: "Test Client", Client_hours: 8})
+	AddExpense(Expense{Date: "2024-03-15", Client_name: "Test Client", Description: "Train ticket", Amount: 40, Billable: true})
+
+	months, err := CalculateMonthlyEarningsForYear(2024)
+	if err != nil {
+		t.Fatalf("CalculateMonthlyEarningsForYear failed: %v", err)
+	}
+	if len(months) != 12 {
+		t.Fatalf("Expected 12 months, got %d", len(months))
+	}
+	for i, month := range months {
+		if month.Year != 2024 || month.Month != i+1 {
+			t.Errorf("Expected 2024-%02d at index %d, got %d-%02d", i+1, i, month.Year, month.Month)
+		}
+	}
+	if months[0].TotalHours != 8 || months[0].TotalEarnings != 800 {
+		t.Errorf("Unexpected January: %+v", months[0])
+	}
+	if months[2].TotalHours != 10 || months[2].TotalEarnings != 1000 || months[2].BillableExpenses != 40 {
+		t.Errorf("Unexpected March: %+v", months[2])
+	}
+	if months[1].TotalHours != 0 || months[1].TotalEarnings != 0 || len(months[1].Entries) != 0 {
+		t.Errorf("Expected February to be empty, got %+v", months[1])
+	}
+
+	// Each month matches the single-month calculation
+	march, _ := CalculateEarningsForMonth(2024, 3)
+	if march.TotalEarnings != months[2].TotalEarnings || march.TotalInvoiced != months[2].TotalInvoiced {
+		t.Errorf("Expected March to match CalculateEarningsForMonth, got %+v and %+v", months[2], march)
+	}
+}
+
 func TestEarningsWithNoRate(t *testing.T) {
 	dbPath := setupTestDB(t)
 	defer teardownTestDB(t, dbPath)
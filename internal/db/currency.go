@@ -0,0 +1,83 @@
+package db
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"timesheet/internal/config"
+)
+
+// CurrencyTotal is the hours and earnings billed in one currency
+type CurrencyTotal struct {
+	Currency string
+	Hours    float64
+	Earnings float64 // In Currency, before conversion
+}
+
+// NormalizeCurrency upper-cases a currency code and checks it's a
+// three-letter ISO 4217 code. An empty code (the base currency) is allowed.
+func NormalizeCurrency(code string) (string, error) {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		return "", nil
+	}
+	if len(code) != 3 {
+		return "", fmt.Errorf("invalid currency %q (must be a 3-letter code such as USD)", code)
+	}
+	for _, r := range code {
+		if r < 'A' || r > 'Z' {
+			return "", fmt.Errorf("invalid currency %q (must be a 3-letter code such as USD)", code)
+		}
+	}
+	return code, nil
+}
+
+// applyCurrencies totals the overview's entries per currency and converts
+// them to the configured base currency
+func applyCurrencies(overview *EarningsOverview) {
+	summarizeCurrencies(overview, config.GetBaseCurrency(), config.GetExchangeRates())
+}
+
+// summarizeCurrencies fills in the entries' currency (empty means base), the
+// per-currency totals and TotalEarnings in base. rates holds the value of one
+// unit of each currency in base; currencies without a rate are reported in
+// Unconverted instead of being added to TotalEarnings.
+func summarizeCurrencies(overview *EarningsOverview, base string, rates map[string]float64) {
+	totals := map[string]*CurrencyTotal{}
+	for i := range overview.Entries {
+		entry := &overview.Entries[i]
+		if entry.Currency == "" {
+			entry.Currency = base
+		}
+		total, ok := totals[entry.Currency]
+		if !ok {
+			total = &CurrencyTotal{Currency: entry.Currency}
+			totals[entry.Currency] = total
+		}
+		total.Hours += entry.ClientHours
+		total.Earnings += entry.Earnings
+	}
+
+	overview.Currency = base
+	overview.TotalEarnings = 0
+	overview.ByCurrency = make([]CurrencyTotal, 0, len(totals))
+	overview.Unconverted = nil
+	for _, total := range totals {
+		overview.ByCurrency = append(overview.ByCurrency, *total)
+	}
+	sort.Slice(overview.ByCurrency, func(i, j int) bool {
+		return overview.ByCurrency[i].Currency < overview.ByCurrency[j].Currency
+	})
+
+	for _, total := range overview.ByCurrency {
+		rate := 1.0
+		if total.Currency != base {
+			var ok bool
+			if rate, ok = rates[total.Currency]; !ok {
+				overview.Unconverted = append(overview.Unconverted, total.Currency)
+				continue
+			}
+		}
+		overview.TotalEarnings += total.Earnings * rate
+	}
+}
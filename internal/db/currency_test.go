@@ -0,0 +1,96 @@
+package db
+
+import (
+	"math"
+	"path/filepath"
+	"testing"
+	"timesheet/internal/config"
+)
+
+func TestNormalizeCurrency(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected string
+		wantErr  bool
+	}{
+		{"", "", false},
+		{"usd", "USD", false},
+		{" Gbp ", "GBP", false},
+		{"EURO", "", true},
+		{"U$D", "", true},
+	}
+
+	for _, tt := range tests {
+		code, err := NormalizeCurrency(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("NormalizeCurrency(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if code != tt.expected {
+			t.Errorf("NormalizeCurrency(%q) = %q, want %q", tt.input, code, tt.expected)
+		}
+	}
+}
+
+func TestCalculateEarningsMultiCurrency(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+	if err := config.SaveConfig(config.Config{ExchangeRates: map[string]float64{"usd": 0.9}}); err != nil {
+		t.Fatalf("Failed to save test config: %v", err)
+	}
+
+	for _, c := range []Client{
+		{Name: "Euro Client", IsActive: true},
+		{Name: "US Client", IsActive: true, Currency: "usd"},
+		{Name: "UK Client", IsActive: true, Currency: "GBP"},
+	} {
+		id, err := AddClient(c)
+		if err != nil {
+			t.Fatalf("Failed to add client %s: %v", c.Name, err)
+		}
+		AddClientRate(ClientRate{ClientId: id, HourlyRate: 100, EffectiveDate: "2024-01-01"})
+	}
+
+	client, _ := GetClientByName("US Client")
+	if client.Currency != "USD" {
+		t.Errorf("Expected currency to be stored as USD, got %q", client.Currency)
+	}
+
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-01-15", Client_name: "Euro Client", Client_hours: 8})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-01-16", Client_name: "US Client", Client_hours: 10})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-01-17", Client_name: "UK Client", Client_hours: 2})
+
+	earnings, err := CalculateEarningsSummaryForYear(2024)
+	if err != nil {
+		t.Fatalf("CalculateEarningsSummaryForYear failed: %v", err)
+	}
+
+	if earnings.Currency != "EUR" {
+		t.Errorf("Expected base currency EUR, got %q", earnings.Currency)
+	}
+	// €800 plus $1000 at 0.9; the GBP hours have no exchange rate
+	if math.Abs(earnings.TotalEarnings-1700) > 0.001 {
+		t.Errorf("Expected converted total 1700, got %.2f", earnings.TotalEarnings)
+	}
+	if len(earnings.Unconverted) != 1 || earnings.Unconverted[0] != "GBP" {
+		t.Errorf("Expected GBP to be unconverted, got %v", earnings.Unconverted)
+	}
+
+	if len(earnings.ByCurrency) != 3 {
+		t.Fatalf("Expected totals for 3 currencies, got %+v", earnings.ByCurrency)
+	}
+	for _, total := range earnings.ByCurrency {
+		want := map[string]float64{"EUR": 800, "GBP": 200, "USD": 1000}[total.Currency]
+		if total.Earnings != want {
+			t.Errorf("%s total = %.2f, want %.2f", total.Currency, total.Earnings, want)
+		}
+	}
+	for _, entry := range earnings.Entries {
+		if entry.ClientName == "Euro Client" && entry.Currency != "EUR" {
+			t.Errorf("Expected the base currency on clients without one, got %q", entry.Currency)
+		}
+	}
+}
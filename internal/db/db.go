@@ -2,13 +2,16 @@ package db
 
 import (
 	"database/sql"
+	"errors"
 	"fmt"
-	"log"
+	"math"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 	"time"
 	"timesheet/internal/config"
+	"timesheet/internal/exitcode"
 	"timesheet/internal/logging"
 
 	_ "modernc.org/sqlite"
@@ -24,7 +27,7 @@ func Connect(dbPath string) error {
 	}
 
 	var err error
-	db, err = sql.Open("sqlite", dbPath)
+	db, err = openSQLite(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -54,6 +57,19 @@ func Connect(dbPath string) error {
 	return nil
 }
 
+// openSQLite opens the SQLite database at dbPath with the configured busy
+// timeout and pool size. The timeout goes in the DSN rather than a PRAGMA
+// statement so every pooled connection gets it, not just the first one.
+func openSQLite(dbPath string) (*sql.DB, error) {
+	dsn := fmt.Sprintf("%s?_pragma=busy_timeout(%d)", dbPath, config.GetSQLiteBusyTimeout())
+	conn, err := sql.Open("sqlite", dsn)
+	if err != nil {
+		return nil, err
+	}
+	conn.SetMaxOpenConns(config.GetSQLiteMaxOpenConns())
+	return conn, nil
+}
+
 // Close closes the database connection
 func Close() {
 	if db != nil {
@@ -72,13 +88,75 @@ type TimesheetEntry struct {
 	Id             int
 	Date           string
 	Client_name    string
-	Client_hours   int
-	Vacation_hours int
-	Idle_hours     int
-	Training_hours int
-	Total_hours    int
-	Sick_hours     int
-	Holiday_hours  int
+	Client_hours   float64
+	Vacation_hours float64
+	Idle_hours     float64
+	Training_hours float64
+	Total_hours    float64
+	Sick_hours     float64
+	Holiday_hours  float64
+	Note           string // Free text, e.g. why a day had idle hours
+}
+
+// ErrClientNameRequired is returned when client hours are stored without a
+// client name: earnings are looked up by client, so such hours can't be billed
+var ErrClientNameRequired = errors.New("client name is required when client hours are logged")
+
+// ErrInvalidHours is returned for hours that are negative or not a multiple
+// of half an hour
+var ErrInvalidHours = fmt.Errorf("hours must be non-negative multiples of %g", hourIncrement)
+
+// ValidateTimesheetEntry checks an entry before it's stored
+func ValidateTimesheetEntry(entry TimesheetEntry) error {
+	if err := ValidateTimesheetHours(entry); err != nil {
+		return err
+	}
+	if entry.Client_hours > 0 && strings.TrimSpace(entry.Client_name) == "" {
+		return fmt.Errorf("%s: %w", entry.Date, ErrClientNameRequired)
+	}
+	return nil
+}
+
+// ValidateTimesheetHours checks only the hours of an entry, for updates that
+// don't replace the rest of it
+func ValidateTimesheetHours(entry TimesheetEntry) error {
+	for _, hours := range []float64{entry.Client_hours, entry.Vacation_hours, entry.Idle_hours,
+		entry.Training_hours, entry.Sick_hours, entry.Holiday_hours} {
+		if !validHours(hours) {
+			return fmt.Errorf("%s: %w (got %g)", entry.Date, ErrInvalidHours, hours)
+		}
+	}
+	return nil
+}
+
+// validHours reports whether hours can be logged: zero or more, in steps of
+// hourIncrement
+func validHours(hours float64) bool {
+	return hours >= 0 && math.Mod(hours, hourIncrement) == 0
+}
+
+// prepareTimesheetEntry applies the over-limit behavior to an entry, with
+// the hours q holds in the other blocks of its date, and validates it,
+// returning the entry to store. A clamped entry is logged.
+func prepareTimesheetEntry(q rowQuerier, entry TimesheetEntry) (TimesheetEntry, error) {
+	otherHours, err := otherBlocksHours(q, entry)
+	if err != nil {
+		return TimesheetEntry{}, err
+	}
+	return prepareTimesheetEntryWith(entry, otherHours)
+}
+
+// prepareTimesheetEntryWith is prepareTimesheetEntry for a caller that
+// already knows the hours of the date's other blocks
+func prepareTimesheetEntryWith(entry TimesheetEntry, otherHours float64) (TimesheetEntry, error) {
+	entry, warning, err := ApplyDailyLimit(entry, otherHours)
+	if err != nil {
+		return TimesheetEntry{}, err
+	}
+	if warning != "" {
+		logging.Log("Warning: %s", warning)
+	}
+	return entry, ValidateTimesheetEntry(entry)
 }
 
 // VacationCarryover represents vacation hours carried over from previous year
@@ -106,19 +184,32 @@ type BufferEntry struct {
 // VacationSummary provides comprehensive vacation hours breakdown for a year
 type VacationSummary struct {
 	Year              int
-	YearlyTarget      int
-	CarryoverHours    int
-	BufferHours       int
-	TotalAvailable    int
-	UsedHours         int
-	UsedFromCarryover int
-	UsedFromBuffer    int
-	UsedFromCurrent   int
-	RemainingTotal    int
-}
-
-// GetDBPath returns the path to the database file
+	YearlyTarget      float64
+	CarryoverHours    float64
+	BufferHours       float64
+	TotalAvailable    float64
+	UsedHours         float64
+	UsedFromCarryover float64
+	UsedFromBuffer    float64
+	UsedFromCurrent   float64
+	RemainingTotal    float64
+}
+
+// GetDBPath returns the path to the database file. TIMESHEETZ_DB_PATH wins
+// over development mode and the default location; its parent directories
+// are created when it's a SQLite file.
 func GetDBPath() string {
+	if dbPath := config.GetDBPathFromEnv(); dbPath != "" {
+		backend, path, err := ParseBackendDSN(dbPath)
+		if err == nil && backend == BackendSQLite && path != ":memory:" {
+			if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+				exitcode.Fail(exitcode.Database, "Failed to create directory for %s: %v", config.DBPathEnv, err)
+			}
+		}
+		logging.Log("Using database from %s at: %s", config.DBPathEnv, dbPath)
+		return dbPath
+	}
+
 	// Check if development mode is enabled
 	if config.GetDevelopmentMode() {
 		// In development mode, use a local database file
@@ -130,18 +221,18 @@ func GetDBPath() string {
 	// In production mode, use ~/.local/share/timesheetz/
 	homeDir, err := os.UserHomeDir()
 	if err != nil {
-		log.Fatalf("Failed to get user home directory: %v", err)
+		exitcode.Fail(exitcode.Database, "Failed to get user home directory: %v", err)
 	}
 
 	// Create timesheet directory if it doesn't exist
 	timesheetDir := filepath.Join(homeDir, ".local", "share", "timesheetz")
 	if err := os.MkdirAll(timesheetDir, 0755); err != nil {
-		log.Fatalf("Failed to create timesheet directory: %v", err)
+		exitcode.Fail(exitcode.Database, "Failed to create timesheet directory: %v", err)
 	}
 
 	// Ensure directory has correct permissions
 	if err := os.Chmod(timesheetDir, 0755); err != nil {
-		log.Fatalf("Failed to set directory permissions: %v", err)
+		exitcode.Fail(exitcode.Database, "Failed to set directory permissions: %v", err)
 	}
 
 	dbPath := filepath.Join(timesheetDir, "timesheet.db")
@@ -169,7 +260,7 @@ func InitializeDatabase(dbPath string) error {
 	}
 
 	var err error
-	db, err = sql.Open("sqlite", dbPath)
+	db, err = openSQLite(dbPath)
 	if err != nil {
 		return fmt.Errorf("failed to open database: %w", err)
 	}
@@ -206,21 +297,21 @@ func ApplySQLiteSchema(conn *sql.DB) error {
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			date TEXT NOT NULL,
 			client_name TEXT NOT NULL,
-			client_hours INTEGER DEFAULT NULL,
-			vacation_hours INTEGER DEFAULT NULL,
-			idle_hours INTEGER DEFAULT NULL,
-			training_hours INTEGER DEFAULT NULL,
-			sick_hours INTEGER DEFAULT NULL,
-			holiday_hours INTEGER DEFAULT NULL
+			client_hours REAL DEFAULT NULL,
+			vacation_hours REAL DEFAULT NULL,
+			idle_hours REAL DEFAULT NULL,
+			training_hours REAL DEFAULT NULL,
+			sick_hours REAL DEFAULT NULL,
+			holiday_hours REAL DEFAULT NULL,
+			created_at TEXT,
+			updated_at TEXT
 		);`,
 		`CREATE INDEX IF NOT EXISTS idx_client_name ON timesheet(client_name);`,
-		`CREATE INDEX IF NOT EXISTS idx_timesheet_date ON timesheet(date);`,
-		`CREATE INDEX IF NOT EXISTS idx_timesheet_date_client ON timesheet(date, client_name);`,
 		`CREATE TABLE IF NOT EXISTS training_budget (
 			id INTEGER PRIMARY KEY AUTOINCREMENT,
 			date TEXT NOT NULL,
 			training_name TEXT NOT NULL,
-			hours INTEGER NOT NULL,
+			hours REAL NOT NULL,
 			cost_without_vat DECIMAL(10,2) NOT NULL
 		);`,
 		`CREATE INDEX IF NOT EXISTS idx_training_date ON training_budget(date);`,
@@ -237,6 +328,7 @@ func ApplySQLiteSchema(conn *sql.DB) error {
 			client_id INTEGER NOT NULL,
 			hourly_rate DECIMAL(10,2) NOT NULL,
 			effective_date TEXT NOT NULL,
+			end_date TEXT,
 			notes TEXT,
 			created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			FOREIGN KEY (client_id) REFERENCES clients(id) ON DELETE CASCADE
@@ -265,6 +357,19 @@ func ApplySQLiteSchema(conn *sql.DB) error {
 			UNIQUE(year, month)
 		);`,
 		`CREATE INDEX IF NOT EXISTS idx_buffer_hours_year ON buffer_hours(year);`,
+		// expenses holds costs such as travel and materials incurred for a
+		// client; billable ones are added to the earnings totals
+		`CREATE TABLE IF NOT EXISTS expenses (
+			id INTEGER PRIMARY KEY AUTOINCREMENT,
+			date TEXT NOT NULL,
+			client_name TEXT NOT NULL,
+			description TEXT NOT NULL,
+			amount REAL NOT NULL,
+			billable INTEGER NOT NULL DEFAULT 1,
+			created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+		);`,
+		`CREATE INDEX IF NOT EXISTS idx_expenses_date ON expenses(date);`,
 		// tombstones records every delete so bidirectional sync can propagate
 		// removals instead of re-inserting whichever side still has the row.
 		// record_key is the natural sync key for the table_name (date, name,
@@ -276,6 +381,32 @@ func ApplySQLiteSchema(conn *sql.DB) error {
 			PRIMARY KEY (table_name, record_key)
 		);`,
 		`CREATE INDEX IF NOT EXISTS idx_tombstones_table ON tombstones(table_name);`,
+		// statements records the hash of each issued monthly statement so it
+		// can later be verified against the data. Local only, never synced.
+		`CREATE TABLE IF NOT EXISTS statements (
+			year INTEGER NOT NULL,
+			month INTEGER NOT NULL,
+			sha256 TEXT NOT NULL,
+			entry_count INTEGER NOT NULL,
+			created_at TEXT NOT NULL,
+			PRIMARY KEY (year, month)
+		);`,
+		// holidays is the imported public holiday calendar that fills
+		// holiday_hours. Local only, never synced.
+		`CREATE TABLE IF NOT EXISTS holidays (
+			date TEXT PRIMARY KEY,
+			name TEXT NOT NULL
+		);`,
+		// sync_migration records how far the initial sync migration got with
+		// each table, so a restarted migration resumes. Local only, never
+		// synced.
+		`CREATE TABLE IF NOT EXISTS sync_migration (
+			table_name TEXT PRIMARY KEY,
+			done INTEGER NOT NULL DEFAULT 0,
+			records_pushed INTEGER NOT NULL DEFAULT 0,
+			attempts INTEGER NOT NULL DEFAULT 0,
+			last_error TEXT NOT NULL DEFAULT ''
+		);`,
 	}
 
 	for _, stmt := range stmts {
@@ -293,20 +424,71 @@ func ApplySQLiteSchema(conn *sql.DB) error {
 		}
 	}
 
+	// Migration: Add receipt_path to training_budget for receipt attachments
+	_, err = conn.Exec(`ALTER TABLE training_budget ADD COLUMN receipt_path TEXT;`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+		logging.Log("Note: Could not add training_budget.receipt_path column: %v", err)
+	}
+
+	// Migration: Add currency to clients for multi-currency billing
+	_, err = conn.Exec(`ALTER TABLE clients ADD COLUMN currency TEXT;`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+		logging.Log("Note: Could not add clients.currency column: %v", err)
+	}
+
+	// Migration: Add min_billable_hours to clients so short days can be
+	// billed at a client's minimum block
+	_, err = conn.Exec(`ALTER TABLE clients ADD COLUMN min_billable_hours REAL;`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+		logging.Log("Note: Could not add clients.min_billable_hours column: %v", err)
+	}
+
+	// Migration: Add monthly_hour_cap and total_hour_cap to clients for
+	// contracts with a fixed number of hours
+	for _, column := range []string{"monthly_hour_cap", "total_hour_cap"} {
+		_, err = conn.Exec(`ALTER TABLE clients ADD COLUMN ` + column + ` INTEGER;`)
+		if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+			logging.Log("Note: Could not add clients.%s column: %v", column, err)
+		}
+	}
+
+	// Migration: Add end_date to client_rates for fixed-term contracts
+	_, err = conn.Exec(`ALTER TABLE client_rates ADD COLUMN end_date TEXT;`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+		logging.Log("Note: Could not add client_rates.end_date column: %v", err)
+	}
+
+	// Migration: Add note to timesheet for context on a day, e.g. why it had
+	// idle hours
+	_, err = conn.Exec(`ALTER TABLE timesheet ADD COLUMN note TEXT;`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+		logging.Log("Note: Could not add timesheet.note column: %v", err)
+	}
+
+	// Migration: Add billed and invoice_ref to timesheet so invoiced client
+	// hours can be told apart from work that still has to be billed
+	_, err = conn.Exec(`ALTER TABLE timesheet ADD COLUMN billed INTEGER NOT NULL DEFAULT 0;`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+		logging.Log("Note: Could not add timesheet.billed column: %v", err)
+	}
+	_, err = conn.Exec(`ALTER TABLE timesheet ADD COLUMN invoice_ref TEXT;`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+		logging.Log("Note: Could not add timesheet.invoice_ref column: %v", err)
+	}
+
+	// Migration: Add total_hours as a generated column so queries against the
+	// database don't have to add up the hour columns. SQLite only allows
+	// VIRTUAL generated columns in ALTER TABLE; it's computed when read.
+	// Databases created before half hours were allowed keep INTEGER hour
+	// columns: SQLite stores 4.5 in those as a REAL anyway, so they need no
+	// rebuild.
+	_, err = conn.Exec(`ALTER TABLE timesheet ADD COLUMN total_hours REAL GENERATED ALWAYS AS ` + timesheetTotalHoursExpr + ` VIRTUAL;`)
+	if err != nil && !strings.Contains(err.Error(), "duplicate column name") {
+		logging.Log("Note: Could not add timesheet.total_hours column: %v", err)
+	}
+
 	// Migration: Add updated_at columns for sync support
-	syncMigrations := []struct {
-		table  string
-		column string
-	}{
-		{"timesheet", "created_at"},
-		{"timesheet", "updated_at"},
-		{"training_budget", "created_at"},
-		{"training_budget", "updated_at"},
-		{"clients", "updated_at"},
-		{"client_rates", "updated_at"},
-	}
-
-	for _, m := range syncMigrations {
+	for _, m := range syncTimestampColumns {
 		// SQLite doesn't allow DEFAULT CURRENT_TIMESTAMP in ALTER TABLE, so we use NULL default
 		sqlStmt := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN %s TEXT;`, m.table, m.column)
 		_, err = conn.Exec(sqlStmt)
@@ -315,45 +497,202 @@ func ApplySQLiteSchema(conn *sql.DB) error {
 		}
 	}
 
+	// Migration: One timesheet entry per client per date
+	if err := migrateUniqueTimesheetDateClient(conn); err != nil {
+		logging.Log("Note: Could not make timesheet (date, client_name) unique: %v", err)
+	}
+
 	// Set default values for existing rows that have NULL timestamps
-	_, _ = conn.Exec(`UPDATE timesheet SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;`)
-	_, _ = conn.Exec(`UPDATE timesheet SET updated_at = CURRENT_TIMESTAMP WHERE updated_at IS NULL;`)
-	_, _ = conn.Exec(`UPDATE training_budget SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL;`)
-	_, _ = conn.Exec(`UPDATE training_budget SET updated_at = CURRENT_TIMESTAMP WHERE updated_at IS NULL;`)
-	_, _ = conn.Exec(`UPDATE clients SET updated_at = CURRENT_TIMESTAMP WHERE updated_at IS NULL;`)
-	_, _ = conn.Exec(`UPDATE client_rates SET updated_at = CURRENT_TIMESTAMP WHERE updated_at IS NULL;`)
+	backfillSyncTimestamps(conn, "?")
+
+	return nil
+}
+
+// syncTimestampColumns are the timestamp columns sync compares that older
+// databases may have left NULL
+var syncTimestampColumns = []struct {
+	table  string
+	column string
+}{
+	{"timesheet", "created_at"},
+	{"timesheet", "updated_at"},
+	{"training_budget", "created_at"},
+	{"training_budget", "updated_at"},
+	{"clients", "updated_at"},
+	{"client_rates", "updated_at"},
+}
+
+// backfillSyncTimestamps fills the NULL timestamps with the current time in
+// the NowTimestamp layout. CURRENT_TIMESTAMP isn't used: PostgreSQL renders
+// it with fractions and a zone, which breaks sync's string comparison.
+// placeholder is the driver's first parameter ("?" or "$1").
+func backfillSyncTimestamps(conn *sql.DB, placeholder string) {
+	now := NowTimestamp()
+	for _, m := range syncTimestampColumns {
+		query := fmt.Sprintf(`UPDATE %s SET %s = %s WHERE %s IS NULL`, m.table, m.column, placeholder, m.column)
+		if _, err := conn.Exec(query, now); err != nil {
+			logging.Log("Note: Could not backfill %s.%s: %v", m.table, m.column, err)
+		}
+	}
+}
 
+// migrateUniqueTimesheetDateClient replaces the older date indexes with a
+// unique one on (date, client_name), so AddTimesheetEntry can upsert on it.
+// Rows that share a date and client are merged first. The SQL works on both
+// SQLite and Postgres.
+func migrateUniqueTimesheetDateClient(conn *sql.DB) error {
+	tx, err := conn.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	merged, err := mergeDuplicateTimesheetBlocks(tx)
+	if err != nil {
+		return err
+	}
+
+	stmts := []string{
+		`CREATE UNIQUE INDEX IF NOT EXISTS idx_timesheet_date_client_unique ON timesheet(date, client_name)`,
+		`DROP INDEX IF EXISTS idx_timesheet_date`,
+		`DROP INDEX IF EXISTS idx_timesheet_date_unique`,
+		`DROP INDEX IF EXISTS idx_timesheet_date_client`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return err
+	}
+	if merged > 0 {
+		logging.Log("Note: Merged %d duplicate timesheet entries", merged)
+	}
 	return nil
 }
 
+// mergeDuplicateTimesheetBlocks folds the rows that share a date and client
+// into the most recently updated one, adding up their hours like
+// --on-duplicate sum. It returns the number of rows folded away. The
+// placeholders are numbered so the SQL runs on SQLite and Postgres alike.
+func mergeDuplicateTimesheetBlocks(tx *sql.Tx) (int, error) {
+	type block struct {
+		id           int
+		date, client string
+		hours        [6]float64
+	}
+
+	rows, err := tx.Query(`SELECT id, date, COALESCE(client_name, ''),
+		COALESCE(client_hours, 0), COALESCE(vacation_hours, 0), COALESCE(idle_hours, 0),
+		COALESCE(training_hours, 0), COALESCE(sick_hours, 0), COALESCE(holiday_hours, 0)
+		FROM timesheet
+		ORDER BY date, COALESCE(client_name, ''), COALESCE(updated_at, '') DESC, id DESC`)
+	if err != nil {
+		return 0, err
+	}
+	var blocks []block
+	for rows.Next() {
+		var b block
+		if err := rows.Scan(&b.id, &b.date, &b.client,
+			&b.hours[0], &b.hours[1], &b.hours[2], &b.hours[3], &b.hours[4], &b.hours[5]); err != nil {
+			rows.Close()
+			return 0, err
+		}
+		blocks = append(blocks, b)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return 0, err
+	}
+
+	merged := 0
+	now := NowTimestamp()
+	for i := 0; i < len(blocks); {
+		keep := blocks[i]
+		j := i + 1
+		for ; j < len(blocks) && blocks[j].date == keep.date && blocks[j].client == keep.client; j++ {
+			for k, h := range blocks[j].hours {
+				keep.hours[k] += h
+			}
+			if _, err := tx.Exec(`DELETE FROM timesheet WHERE id = $1`, blocks[j].id); err != nil {
+				return 0, fmt.Errorf("failed to merge timesheet entry %d: %w", blocks[j].id, err)
+			}
+			merged++
+		}
+		if j > i+1 {
+			_, err := tx.Exec(`UPDATE timesheet SET client_hours = $1, vacation_hours = $2, idle_hours = $3,
+				training_hours = $4, sick_hours = $5, holiday_hours = $6, updated_at = $7 WHERE id = $8`,
+				keep.hours[0], keep.hours[1], keep.hours[2], keep.hours[3], keep.hours[4], keep.hours[5], now, keep.id)
+			if err != nil {
+				return 0, fmt.Errorf("failed to merge timesheet entries on %s: %w", keep.date, err)
+			}
+		}
+		i = j
+	}
+	return merged, nil
+}
+
+// timesheetUpsertConflict turns an INSERT into the timesheet into an upsert
+// on the date and client: a day can have a block per client, and a second
+// entry for the same client replaces the hours of the first instead of
+// adding a row. created_at and the billing columns are left alone.
+const timesheetUpsertConflict = `ON CONFLICT(date, client_name) DO UPDATE SET
+	client_hours = excluded.client_hours,
+	vacation_hours = excluded.vacation_hours, idle_hours = excluded.idle_hours,
+	training_hours = excluded.training_hours, sick_hours = excluded.sick_hours,
+	holiday_hours = excluded.holiday_hours, note = excluded.note,
+	updated_at = excluded.updated_at`
+
+// timesheetTotalHoursExpr sums the hour categories of a timesheet row. Both
+// the SQLite and Postgres layers select it, and it defines their generated
+// total_hours column, so the totals can't drift apart.
+const timesheetTotalHoursExpr = `(COALESCE(client_hours, 0) + COALESCE(vacation_hours, 0) + COALESCE(idle_hours, 0) +
+	 COALESCE(training_hours, 0) + COALESCE(sick_hours, 0) + COALESCE(holiday_hours, 0))`
+
+// timesheetSelectColumns lists the timesheet columns in TimesheetEntry scan
+// order. The hour columns are DEFAULT NULL, so each is coalesced to 0: a NULL
+// can't be scanned into an int and would turn total_hours into NULL.
+const timesheetSelectColumns = `id, date, client_name,
+	COALESCE(client_hours, 0), COALESCE(vacation_hours, 0), COALESCE(idle_hours, 0),
+	COALESCE(training_hours, 0), COALESCE(sick_hours, 0), COALESCE(holiday_hours, 0),
+	` + timesheetTotalHoursExpr + ` AS total_hours, COALESCE(note, '')`
+
+// yearDateBounds returns the half-open range [start, end) covering year.
+// Comparing dates against it works for both plain YYYY-MM-DD values and
+// dates stored with a time suffix, unlike BETWEEN with a YYYY-12-31 bound.
+func yearDateBounds(year int) (string, string) {
+	return fmt.Sprintf("%04d-01-01", year), fmt.Sprintf("%04d-01-01", year+1)
+}
+
 // GetAllTimesheetEntries retrieves entries from the timesheet table
 // If year and month are provided (non-zero), it filters entries for that specific month
 func GetAllTimesheetEntries(year int, month time.Month) ([]TimesheetEntry, error) {
 	var query string
 	var args []any
 
-	baseQuery := "SELECT id, date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, " +
-		"(client_hours + vacation_hours + idle_hours + training_hours + sick_hours + holiday_hours) AS total_hours " +
-		"FROM timesheet"
+	baseQuery := "SELECT " + timesheetSelectColumns + " FROM timesheet"
 
 	if year != 0 && month != 0 {
 		// Filter by specific month and year
 		startDate := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
-		endDate := time.Date(year, month+1, 0, 23, 59, 59, 999999999, time.UTC).Format("2006-01-02")
+		endDate := time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
 
-		query = baseQuery + " WHERE date BETWEEN ? AND ?"
+		query = baseQuery + " WHERE date >= ? AND date < ?"
 		args = []any{startDate, endDate}
 	} else if year != 0 {
 		// Filter by year only (all months in the year)
-		startDate := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
-		endDate := time.Date(year, 12, 31, 23, 59, 59, 999999999, time.UTC).Format("2006-01-02")
+		startDate, endDate := yearDateBounds(year)
 
-		query = baseQuery + " WHERE date BETWEEN ? AND ?"
+		query = baseQuery + " WHERE date >= ? AND date < ?"
 		args = []any{startDate, endDate}
 	} else {
 		// Get all entries
 		query = baseQuery
 	}
+	// Stable ordering so dual-mode comparisons and the UI don't depend on
+	// storage order
+	query += " ORDER BY date ASC, client_name ASC"
 
 	rows, err := db.Query(query, args...)
 	if err != nil {
@@ -376,7 +715,7 @@ func GetAllTimesheetEntries(year int, month time.Month) ([]TimesheetEntry, error
 	for rows.Next() {
 		var entry TimesheetEntry
 		if err := rows.Scan(&entry.Id, &entry.Date, &entry.Client_name, &entry.Client_hours,
-			&entry.Vacation_hours, &entry.Idle_hours, &entry.Training_hours, &entry.Sick_hours, &entry.Holiday_hours, &entry.Total_hours); err != nil {
+			&entry.Vacation_hours, &entry.Idle_hours, &entry.Training_hours, &entry.Sick_hours, &entry.Holiday_hours, &entry.Total_hours, &entry.Note); err != nil {
 			return nil, err
 		}
 		entries = append(entries, entry)
@@ -389,41 +728,77 @@ func GetAllTimesheetEntries(year int, month time.Month) ([]TimesheetEntry, error
 	return entries, nil
 }
 
-// GetTimesheetEntryByDate retrieves a single timesheet entry by date
-func GetTimesheetEntryByDate(date string) (TimesheetEntry, error) {
-	query := `SELECT id, date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours,
-              (client_hours + vacation_hours + idle_hours + training_hours + holiday_hours + sick_hours) AS total_hours
-              FROM timesheet WHERE date = ?`
+// GetTimesheetEntriesByDate retrieves the client blocks logged on date in
+// the order they were added. A day without entries returns an empty slice.
+func GetTimesheetEntriesByDate(date string) ([]TimesheetEntry, error) {
+	query := "SELECT " + timesheetSelectColumns + " FROM timesheet WHERE date = ? ORDER BY client_name"
 
-	var entry TimesheetEntry
-	err := db.QueryRow(query, date).Scan(
-		&entry.Id,
-		&entry.Date,
-		&entry.Client_name,
-		&entry.Client_hours,
-		&entry.Vacation_hours,
-		&entry.Idle_hours,
-		&entry.Training_hours,
-		&entry.Sick_hours,
-		&entry.Holiday_hours,
-		&entry.Total_hours,
-	)
+	rows, err := db.Query(query, date)
 	if err != nil {
-		return TimesheetEntry{}, err
+		return nil, err
 	}
+	defer rows.Close()
 
-	return entry, nil
+	entries := []TimesheetEntry{}
+	for rows.Next() {
+		var entry TimesheetEntry
+		err := rows.Scan(
+			&entry.Id,
+			&entry.Date,
+			&entry.Client_name,
+			&entry.Client_hours,
+			&entry.Vacation_hours,
+			&entry.Idle_hours,
+			&entry.Training_hours,
+			&entry.Sick_hours,
+			&entry.Holiday_hours,
+			&entry.Total_hours,
+			&entry.Note,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
 }
 
+// FindClientEntry returns the block of entries logged for clientName
+func FindClientEntry(entries []TimesheetEntry, clientName string) (TimesheetEntry, bool) {
+	for _, entry := range entries {
+		if entry.Client_name == clientName {
+			return entry, true
+		}
+	}
+	return TimesheetEntry{}, false
+}
+
+// DeleteClientEntry removes the block of clientName on date through dl,
+// leaving the blocks of other clients alone
+func DeleteClientEntry(dl DataLayer, date, clientName string) error {
+	onDate, err := dl.GetTimesheetEntriesByDate(date)
+	if err != nil {
+		return err
+	}
+	if entry, ok := FindClientEntry(onDate, clientName); ok {
+		return dl.DeleteTimesheetEntry(strconv.Itoa(entry.Id))
+	}
+	return nil
+}
+
+// AddTimesheetEntry stores entry, replacing the block already logged for
+// its client on its date if there is one
 func AddTimesheetEntry(entry TimesheetEntry) error {
-	// Remove debug output
-	// fmt.Printf("DEBUG: AddTimesheetEntry - Date: %s, Client: %s, VacationHours: %d\n",
-	// 	entry.Date, entry.Client_name, entry.Vacation_hours)
+	entry, err := prepareTimesheetEntry(db, entry)
+	if err != nil {
+		return err
+	}
 
 	now := NowTimestamp()
-	query := `INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, created_at, updated_at)
-              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := db.Exec(query,
+	query := `INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, note, created_at, updated_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+              ` + timesheetUpsertConflict
+	_, err = db.Exec(query,
 		entry.Date,
 		entry.Client_name,
 		entry.Client_hours,
@@ -432,6 +807,7 @@ func AddTimesheetEntry(entry TimesheetEntry) error {
 		entry.Training_hours,
 		entry.Sick_hours,
 		entry.Holiday_hours,
+		entry.Note,
 		now, now)
 	if err != nil {
 		return err
@@ -441,15 +817,20 @@ func AddTimesheetEntry(entry TimesheetEntry) error {
 	return nil
 }
 
-// UpdateTimesheetEntry updates an existing Timesheet entry by date
+// UpdateTimesheetEntry updates an existing Timesheet entry. An entry with
+// an Id is updated by Id, so its client can change; otherwise the block for
+// its client on its date is updated.
 func UpdateTimesheetEntry(entry TimesheetEntry) error {
+	entry, err := prepareTimesheetEntry(db, entry)
+	if err != nil {
+		return err
+	}
+
 	query := `UPDATE timesheet
               SET client_name = ?, client_hours = ?,
                   vacation_hours = ?, idle_hours = ?, training_hours = ?, holiday_hours = ?, sick_hours = ?,
-                  updated_at = ?
-              WHERE date = ?`
-
-	result, err := db.Exec(query,
+                  note = ?, updated_at = ?`
+	args := []any{
 		entry.Client_name,
 		entry.Client_hours,
 		entry.Vacation_hours,
@@ -457,8 +838,33 @@ func UpdateTimesheetEntry(entry TimesheetEntry) error {
 		entry.Training_hours,
 		entry.Holiday_hours,
 		entry.Sick_hours,
+		entry.Note,
 		NowTimestamp(),
-		entry.Date)
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if entry.Id != 0 {
+		query += " WHERE id = ?"
+		args = append(args, entry.Id)
+		// Moving the block to another client retires its old key
+		var date, clientName string
+		err := tx.QueryRow(`SELECT date, client_name FROM timesheet WHERE id = ?`, entry.Id).Scan(&date, &clientName)
+		if err == nil && clientName != entry.Client_name {
+			if err := WriteSqliteTombstone(tx, TombstoneTableTimesheet, TombstoneKeyTimesheet(date, clientName)); err != nil {
+				return err
+			}
+		}
+	} else {
+		query += " WHERE date = ? AND client_name = ?"
+		args = append(args, entry.Date, entry.Client_name)
+	}
+
+	result, err := tx.Exec(query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update record: %w", err)
 	}
@@ -469,31 +875,36 @@ func UpdateTimesheetEntry(entry TimesheetEntry) error {
 		return fmt.Errorf("error checking rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("no entry found with date %s", entry.Date)
+		return fmt.Errorf("no entry found for %s on %s", entry.Client_name, entry.Date)
 	}
 
-	return nil
+	return tx.Commit()
 }
 
-// PutTimesheetEntry inserts a new timesheet entry with the current date
-func PutTimesheetEntry(clientHours, vacationHours, idleHours, trainingHours, holidayHours, sickHours float64) (int64, error) {
+// PutTimesheetEntry stores a timesheet entry for clientName with the
+// current date and returns its ID. An empty clientName is rejected so that no
+// placeholder client ends up in client or earnings reports.
+//
+// No API route uses this helper: POST /api/timesheet goes through the data
+// layer's AddTimesheetEntry, which takes a full TimesheetEntry (date
+// included). PutTimesheetEntry is a local-only convenience for "log hours
+// for today".
+func PutTimesheetEntry(clientName string, clientHours, vacationHours, idleHours, trainingHours, holidayHours, sickHours float64) (int64, error) {
+	if strings.TrimSpace(clientName) == "" {
+		return 0, fmt.Errorf("client name is required")
+	}
+
 	// Get current date in YYYY-MM-DD format
 	currentDate := time.Now().Format("2006-01-02")
 
+	// An entry already logged today for clientName is replaced. LastInsertId isn't set
+	// when the upsert updates, so the ID is read back with RETURNING.
 	now := NowTimestamp()
-	stmt, err := db.Prepare("INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, holiday_hours, sick_hours, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)")
-	if err != nil {
-		return 0, err
-	}
-	defer stmt.Close()
-
-	result, err := stmt.Exec(currentDate, "default", clientHours, vacationHours, idleHours, trainingHours, holidayHours, sickHours, now, now)
-	if err != nil {
-		return 0, err
-	}
-
-	// Return the new entry's ID
-	id, err := result.LastInsertId()
+	var id int64
+	err := db.QueryRow(`INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, holiday_hours, sick_hours, created_at, updated_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?)
+              `+timesheetUpsertConflict+` RETURNING id`,
+		currentDate, clientName, clientHours, vacationHours, idleHours, trainingHours, holidayHours, sickHours, now, now).Scan(&id)
 	if err != nil {
 		return 0, err
 	}
@@ -501,62 +912,105 @@ func PutTimesheetEntry(clientHours, vacationHours, idleHours, trainingHours, hol
 	return id, nil
 }
 
+// UpdateTimesheetEntryById updates some fields of the entry with id (see
+// updateTimesheetEntryByIdTx)
 func UpdateTimesheetEntryById(id string, data map[string]any) error {
-	// Validate allowed fields to prevent SQL injection
-	allowedFields := map[string]bool{
-		"client_hours":   true,
-		"vacation_hours": true,
-		"idle_hours":     true,
-		"training_hours": true,
-		"holiday_hours":  true,
-		"sick_hours":     true,
-	}
-
-	// Start building the query
-	query := "UPDATE timesheet SET "
-
-	// Add each field to be updated
-	values := []any{}
-	setStatements := []string{}
+	return WithTransaction(func(tx *sql.Tx) error {
+		return updateTimesheetEntryByIdTx(tx, id, data)
+	})
+}
 
-	for key, val := range data {
-		// Check if the field is allowed
-		if !allowedFields[key] {
-			return fmt.Errorf("field %s is not allowed for update", key)
-		}
-		setStatements = append(setStatements, key+" = ?")
-		values = append(values, val)
+// updateTimesheetEntryByIdTx loads the row with id inside tx, applies the
+// fields in data to it and stores it, after the same over-limit handling
+// and validation as a full update. It uses $N placeholders, which both
+// PostgreSQL and the SQLite driver accept.
+func updateTimesheetEntryByIdTx(tx *sql.Tx, id string, data map[string]any) error {
+	var entry TimesheetEntry
+	err := tx.QueryRow("SELECT "+timesheetSelectColumns+" FROM timesheet WHERE id = $1", id).Scan(
+		&entry.Id,
+		&entry.Date,
+		&entry.Client_name,
+		&entry.Client_hours,
+		&entry.Vacation_hours,
+		&entry.Idle_hours,
+		&entry.Training_hours,
+		&entry.Sick_hours,
+		&entry.Holiday_hours,
+		&entry.Total_hours,
+		&entry.Note,
+	)
+	if errors.Is(err, sql.ErrNoRows) {
+		return fmt.Errorf("no entry found with id %s", id)
 	}
-
-	if len(setStatements) == 0 {
-		return fmt.Errorf("no valid fields to update")
+	if err != nil {
+		return fmt.Errorf("failed to query entry %s: %w", id, err)
 	}
 
-	query += strings.Join(setStatements, ", ")
-	query += ", updated_at = ? WHERE id = ?"
-	values = append(values, NowTimestamp(), id)
+	entry, err = patchTimesheetEntry(entry, data)
+	if err != nil {
+		return err
+	}
+	entry, err = prepareTimesheetEntry(tx, entry)
+	if err != nil {
+		return err
+	}
 
-	// Execute the query
-	result, err := db.Exec(query, values...)
+	_, err = tx.Exec(`UPDATE timesheet
+		SET client_hours = $1, vacation_hours = $2, idle_hours = $3, training_hours = $4,
+		    holiday_hours = $5, sick_hours = $6, note = $7, updated_at = $8
+		WHERE id = $9`,
+		entry.Client_hours, entry.Vacation_hours, entry.Idle_hours, entry.Training_hours,
+		entry.Holiday_hours, entry.Sick_hours, entry.Note, NowTimestamp(), entry.Id)
 	if err != nil {
 		return fmt.Errorf("failed to update record: %w", err)
 	}
+	return nil
+}
 
-	// Check if any rows were affected
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("error checking rows affected: %w", err)
+// patchTimesheetEntry sets the fields of a by-id update on entry. Only the
+// hour columns and the note may be updated this way.
+func patchTimesheetEntry(entry TimesheetEntry, data map[string]any) (TimesheetEntry, error) {
+	hourFields := map[string]*float64{
+		"client_hours":   &entry.Client_hours,
+		"vacation_hours": &entry.Vacation_hours,
+		"idle_hours":     &entry.Idle_hours,
+		"training_hours": &entry.Training_hours,
+		"holiday_hours":  &entry.Holiday_hours,
+		"sick_hours":     &entry.Sick_hours,
 	}
-	if rowsAffected == 0 {
-		return fmt.Errorf("no entry found with id %s", id)
+
+	for key, val := range data {
+		if key == "note" {
+			note, ok := val.(string)
+			if !ok {
+				return TimesheetEntry{}, fmt.Errorf("field note must be a string")
+			}
+			entry.Note = note
+			continue
+		}
+		field, ok := hourFields[key]
+		if !ok {
+			return TimesheetEntry{}, fmt.Errorf("field %s is not allowed for update", key)
+		}
+		switch hours := val.(type) {
+		case float64:
+			*field = hours
+		case int:
+			*field = float64(hours)
+		default:
+			return TimesheetEntry{}, fmt.Errorf("field %s must be a number", key)
+		}
 	}
 
-	return nil
+	if len(data) == 0 {
+		return TimesheetEntry{}, fmt.Errorf("no valid fields to update")
+	}
+	return entry, nil
 }
 
-// DeleteTimesheetEntryByDate removes a timesheet entry by its date.
-// A tombstone is written for the same date so bidirectional sync can
-// propagate the delete instead of having the other DB re-insert the row.
+// DeleteTimesheetEntryByDate removes every client block logged on date.
+// A tombstone is written for each block so bidirectional sync can
+// propagate the delete instead of having the other DB re-insert the rows.
 func DeleteTimesheetEntryByDate(date string) error {
 	tx, err := db.Begin()
 	if err != nil {
@@ -564,25 +1018,44 @@ func DeleteTimesheetEntryByDate(date string) error {
 	}
 	defer tx.Rollback()
 
-	res, err := tx.Exec(`DELETE FROM timesheet WHERE date = ?`, date)
+	clientNames, err := queryTimesheetClientNames(tx, `SELECT client_name FROM timesheet WHERE date = ?`, date)
 	if err != nil {
-		return fmt.Errorf("failed to delete record: %w", err)
+		return fmt.Errorf("failed to look up entries: %w", err)
 	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to check rows affected: %w", err)
+	if _, err := tx.Exec(`DELETE FROM timesheet WHERE date = ?`, date); err != nil {
+		return fmt.Errorf("failed to delete record: %w", err)
 	}
-	if affected > 0 {
-		if err := WriteSqliteTombstone(tx, TombstoneTableTimesheet, date); err != nil {
+	for _, clientName := range clientNames {
+		if err := WriteSqliteTombstone(tx, TombstoneTableTimesheet, TombstoneKeyTimesheet(date, clientName)); err != nil {
 			return err
 		}
 	}
 	return tx.Commit()
 }
 
+// queryTimesheetClientNames returns the client_name column of the rows
+// query selects inside tx
+func queryTimesheetClientNames(tx *sql.Tx, query string, args ...any) ([]string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var clientNames []string
+	for rows.Next() {
+		var clientName string
+		if err := rows.Scan(&clientName); err != nil {
+			return nil, err
+		}
+		clientNames = append(clientNames, clientName)
+	}
+	return clientNames, rows.Err()
+}
+
 // DeleteTimesheetEntry removes a timesheet entry by its ID. The row's date
-// is captured before the delete so a tombstone keyed by date (the sync key)
-// can be written.
+// and client are captured before the delete so a tombstone keyed by them
+// (the sync key) can be written.
 func DeleteTimesheetEntry(id string) error {
 	tx, err := db.Begin()
 	if err != nil {
@@ -590,8 +1063,8 @@ func DeleteTimesheetEntry(id string) error {
 	}
 	defer tx.Rollback()
 
-	var date string
-	err = tx.QueryRow(`SELECT date FROM timesheet WHERE id = ?`, id).Scan(&date)
+	var date, clientName string
+	err = tx.QueryRow(`SELECT date, client_name FROM timesheet WHERE id = ?`, id).Scan(&date, &clientName)
 	if err == sql.ErrNoRows {
 		return tx.Commit()
 	}
@@ -602,12 +1075,61 @@ func DeleteTimesheetEntry(id string) error {
 	if _, err := tx.Exec(`DELETE FROM timesheet WHERE id = ?`, id); err != nil {
 		return fmt.Errorf("failed to delete record: %w", err)
 	}
-	if err := WriteSqliteTombstone(tx, TombstoneTableTimesheet, date); err != nil {
+	if err := WriteSqliteTombstone(tx, TombstoneTableTimesheet, TombstoneKeyTimesheet(date, clientName)); err != nil {
 		return err
 	}
 	return tx.Commit()
 }
 
+// DeleteTimesheetEntriesForMonth removes every timesheet entry of a month
+// and returns how many were deleted. Each gets a tombstone, like
+// DeleteTimesheetEntryByDate.
+func DeleteTimesheetEntriesForMonth(year int, month time.Month) (int, error) {
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	start, end := expenseDateBounds(year, int(month))
+	keys, err := queryTimesheetKeys(tx, `SELECT date, client_name FROM timesheet WHERE date >= ? AND date < ?`, start, end)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up entries: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM timesheet WHERE date >= ? AND date < ?`, start, end); err != nil {
+		return 0, fmt.Errorf("failed to delete records: %w", err)
+	}
+	for _, key := range keys {
+		if err := WriteSqliteTombstone(tx, TombstoneTableTimesheet, key); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(keys), nil
+}
+
+// queryTimesheetKeys returns the tombstone keys of the rows query selects
+// (date, client_name) inside tx
+func queryTimesheetKeys(tx *sql.Tx, query string, args ...any) ([]string, error) {
+	rows, err := tx.Query(query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var date, clientName string
+		if err := rows.Scan(&date, &clientName); err != nil {
+			return nil, err
+		}
+		keys = append(keys, TombstoneKeyTimesheet(date, clientName))
+	}
+	return keys, rows.Err()
+}
+
 func Ping() error {
 	return db.Ping()
 }
@@ -626,14 +1148,38 @@ func GetLastClientName() (string, error) {
 	return clientName, nil
 }
 
+// GetTimesheetYearRange returns the first and last year that have timesheet
+// entries. Both are 0 when the timesheet is empty.
+func GetTimesheetYearRange() (minYear int, maxYear int, err error) {
+	var minDate, maxDate sql.NullString
+	err = db.QueryRow(`SELECT MIN(date), MAX(date) FROM timesheet`).Scan(&minDate, &maxDate)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get timesheet year range: %w", err)
+	}
+	if !minDate.Valid || !maxDate.Valid {
+		return 0, 0, nil
+	}
+
+	minTime, err := time.Parse("2006-01-02", minDate.String)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid date %q in timesheet: %w", minDate.String, err)
+	}
+	maxTime, err := time.Parse("2006-01-02", maxDate.String)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid date %q in timesheet: %w", maxDate.String, err)
+	}
+	return minTime.Year(), maxTime.Year(), nil
+}
+
 // GetVacationEntriesForYear returns all vacation days with vacation_hours > 0 from the timesheet table
 func GetVacationEntriesForYear(year int) ([]TimesheetEntry, error) {
+	startDate, endDate := yearDateBounds(year)
 	rows, err := db.Query(`
-		SELECT id, date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, (client_hours + vacation_hours + idle_hours + training_hours + sick_hours + holiday_hours) AS total_hours
+		SELECT `+timesheetSelectColumns+`
 		FROM timesheet
-		WHERE strftime('%Y', date) = ? AND vacation_hours > 0
-		ORDER BY date DESC
-	`, fmt.Sprintf("%d", year))
+		WHERE date >= ? AND date < ? AND COALESCE(vacation_hours, 0) > 0
+		ORDER BY date DESC, client_name ASC
+	`, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query timesheet vacation entries: %w", err)
 	}
@@ -643,7 +1189,7 @@ func GetVacationEntriesForYear(year int) ([]TimesheetEntry, error) {
 	entries := make([]TimesheetEntry, 0, 30)
 	for rows.Next() {
 		var entry TimesheetEntry
-		if err := rows.Scan(&entry.Id, &entry.Date, &entry.Client_name, &entry.Client_hours, &entry.Vacation_hours, &entry.Idle_hours, &entry.Training_hours, &entry.Sick_hours, &entry.Holiday_hours, &entry.Total_hours); err != nil {
+		if err := rows.Scan(&entry.Id, &entry.Date, &entry.Client_name, &entry.Client_hours, &entry.Vacation_hours, &entry.Idle_hours, &entry.Training_hours, &entry.Sick_hours, &entry.Holiday_hours, &entry.Total_hours, &entry.Note); err != nil {
 			return nil, fmt.Errorf("failed to scan timesheet vacation entry: %w", err)
 		}
 		entries = append(entries, entry)
@@ -652,19 +1198,43 @@ func GetVacationEntriesForYear(year int) ([]TimesheetEntry, error) {
 }
 
 // GetVacationHoursForYear returns the total vacation hours used in a given year (from timesheet table only)
-func GetVacationHoursForYear(year int) (int, error) {
-	var total int
+func GetVacationHoursForYear(year int) (float64, error) {
+	var total float64
+	startDate, endDate := yearDateBounds(year)
 	err := db.QueryRow(`
 		SELECT COALESCE(SUM(vacation_hours), 0)
 		FROM timesheet
-		WHERE strftime('%Y', date) = ? AND vacation_hours > 0
-	`, fmt.Sprintf("%d", year)).Scan(&total)
+		WHERE date >= ? AND date < ? AND COALESCE(vacation_hours, 0) > 0
+	`, startDate, endDate).Scan(&total)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get vacation hours from timesheet table: %w", err)
 	}
 	return total, nil
 }
 
+// GetSickHoursForYear returns the total sick hours logged in a given year
+func GetSickHoursForYear(year int) (float64, error) {
+	return sumHoursForYear(db, func(int) string { return "?" }, "sick_hours", year)
+}
+
+// GetIdleHoursForYear returns the total idle hours logged in a given year
+func GetIdleHoursForYear(year int) (float64, error) {
+	return sumHoursForYear(db, func(int) string { return "?" }, "idle_hours", year)
+}
+
+// sumHoursForYear adds up an hour column of the timesheet for year on conn.
+// placeholder returns the driver's nth parameter ("?" or "$n").
+func sumHoursForYear(conn *sql.DB, placeholder func(n int) string, column string, year int) (float64, error) {
+	var total float64
+	startDate, endDate := yearDateBounds(year)
+	query := fmt.Sprintf("SELECT COALESCE(SUM(%s), 0) FROM timesheet WHERE date >= %s AND date < %s",
+		column, placeholder(1), placeholder(2))
+	if err := conn.QueryRow(query, startDate, endDate).Scan(&total); err != nil {
+		return 0, fmt.Errorf("failed to get %s for %d: %w", strings.ReplaceAll(column, "_", " "), year, err)
+	}
+	return total, nil
+}
+
 // GetVacationCarryoverForYear returns carryover hours for a specific year
 func GetVacationCarryoverForYear(year int) (VacationCarryover, error) {
 	var carryover VacationCarryover
@@ -742,7 +1312,7 @@ func DeleteVacationCarryover(year int) error {
 // calculateAutoCarryover computes the carryover for a year by looking at
 // the previous year's remaining vacation hours. This is only called when
 // no explicit carryover record exists for the given year.
-func calculateAutoCarryover(year int, yearlyTarget int) (int, error) {
+func calculateAutoCarryover(year int, yearlyTarget float64) (float64, error) {
 	// Get previous year's explicit carryover (don't recurse — only look one level back)
 	prevCarryover, err := GetVacationCarryoverForYear(year - 1)
 	if err != nil {
@@ -755,7 +1325,7 @@ func calculateAutoCarryover(year int, yearlyTarget int) (int, error) {
 		return 0, fmt.Errorf("failed to get previous year used hours: %w", err)
 	}
 
-	prevAvailable := yearlyTarget + prevCarryover.CarryoverHours
+	prevAvailable := yearlyTarget + float64(prevCarryover.CarryoverHours)
 	remaining := prevAvailable - prevUsed
 
 	// Don't carry over negative values
@@ -774,7 +1344,7 @@ func GetVacationSummaryForYear(year int) (VacationSummary, error) {
 	if err != nil {
 		return summary, fmt.Errorf("failed to get config: %w", err)
 	}
-	summary.YearlyTarget = cfg.VacationHours.YearlyTarget
+	summary.YearlyTarget = float64(cfg.VacationHours.YearlyTarget)
 
 	// 2. Get carryover hours — auto-calculate if no explicit record exists
 	carryover, err := GetVacationCarryoverForYear(year)
@@ -789,7 +1359,7 @@ func GetVacationSummaryForYear(year int) (VacationSummary, error) {
 		}
 		summary.CarryoverHours = autoCarryover
 	} else {
-		summary.CarryoverHours = carryover.CarryoverHours
+		summary.CarryoverHours = float64(carryover.CarryoverHours)
 	}
 
 	// 3. Get banked buffer hours for the year
@@ -797,7 +1367,7 @@ func GetVacationSummaryForYear(year int) (VacationSummary, error) {
 	if err != nil {
 		return summary, fmt.Errorf("failed to get buffer hours: %w", err)
 	}
-	summary.BufferHours = bufferHours
+	summary.BufferHours = float64(bufferHours)
 
 	// 4. Get used hours from timesheet
 	usedHours, err := GetVacationHoursForYear(year)
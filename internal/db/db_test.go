@@ -1,16 +1,19 @@
 package db
 
 import (
+	"database/sql"
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
 	"strconv"
+	"sync"
 	"testing"
 	"time"
 	"timesheet/internal/config"
 )
 
-func setupTestDB(t *testing.T) string {
+func setupTestDB(t testing.TB) string {
 	// Use in-memory database for testing
 	dbPath := ":memory:"
 
@@ -23,11 +26,23 @@ func setupTestDB(t *testing.T) string {
 	return dbPath
 }
 
-func teardownTestDB(t *testing.T, dbPath string) {
+func teardownTestDB(t testing.TB, dbPath string) {
 	Close()
 	// No need to remove in-memory database
 }
 
+// firstEntry returns the first of the entries a GetTimesheetEntriesByDate
+// call found, or sql.ErrNoRows when there are none
+func firstEntry(entries []TimesheetEntry, err error) (TimesheetEntry, error) {
+	if err != nil {
+		return TimesheetEntry{}, err
+	}
+	if len(entries) == 0 {
+		return TimesheetEntry{}, sql.ErrNoRows
+	}
+	return entries[0], nil
+}
+
 func TestConnect(t *testing.T) {
 	dbPath := setupTestDB(t)
 	defer teardownTestDB(t, dbPath)
@@ -57,8 +72,7 @@ func TestInitializeDatabase(t *testing.T) {
 	// Verify indexes were created
 	expectedIndexes := []string{
 		"idx_client_name",
-		"idx_timesheet_date",
-		"idx_timesheet_date_client",
+		"idx_timesheet_date_client_unique",
 		"idx_training_date",
 		"idx_clients_name",
 		"idx_clients_active",
@@ -135,38 +149,188 @@ func TestGetAllTimesheetEntries(t *testing.T) {
 	}
 }
 
-func TestGetTimesheetEntryByDate(t *testing.T) {
+func TestGetAllTimesheetEntriesOrderedByDate(t *testing.T) {
 	dbPath := setupTestDB(t)
 	defer teardownTestDB(t, dbPath)
 
-	entry := TimesheetEntry{
-		Date:           "2024-01-15",
-		Client_name:    "Client A",
-		Client_hours:   8,
-		Vacation_hours: 0,
-		Idle_hours:     0,
-		Training_hours: 0,
-		Sick_hours:     0,
-		Holiday_hours:  0,
+	// Insert out of order so storage order differs from date order
+	for _, date := range []string{"2024-03-10", "2024-01-05", "2024-02-20", "2024-01-01"} {
+		if err := AddTimesheetEntry(TimesheetEntry{Date: date, Client_name: "Client A", Client_hours: 8}); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
 	}
 
-	if err := AddTimesheetEntry(entry); err != nil {
-		t.Fatalf("Failed to add entry: %v", err)
+	entries, err := GetAllTimesheetEntries(0, 0)
+	if err != nil {
+		t.Fatalf("Failed to get entries: %v", err)
+	}
+	want := []string{"2024-01-01", "2024-01-05", "2024-02-20", "2024-03-10"}
+	if len(entries) != len(want) {
+		t.Fatalf("Expected %d entries, got %d", len(want), len(entries))
 	}
+	for i, date := range want {
+		if entries[i].Date != date {
+			t.Errorf("Entry %d: expected date %s, got %s", i, date, entries[i].Date)
+		}
+	}
+}
 
-	// Test getting entry by date
-	result, err := GetTimesheetEntryByDate("2024-01-15")
+func TestPutTimesheetEntry(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	if _, err := PutTimesheetEntry("", 8, 0, 0, 0, 0, 0); err == nil {
+		t.Errorf("Expected error for empty client name")
+	}
+
+	id, err := PutTimesheetEntry("Acme Corp", 8, 0, 0, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("PutTimesheetEntry failed: %v", err)
+	}
+	if id <= 0 {
+		t.Errorf("Expected positive ID, got %d", id)
+	}
+
+	entry, err := firstEntry(GetTimesheetEntriesByDate(time.Now().Format("2006-01-02")))
 	if err != nil {
 		t.Fatalf("Failed to get entry: %v", err)
 	}
-	if result.Client_name != "Client A" {
-		t.Errorf("Expected Client A, got %s", result.Client_name)
+	if entry.Client_name != "Acme Corp" {
+		t.Errorf("Expected client name 'Acme Corp', got '%s'", entry.Client_name)
+	}
+
+	// A second call on the same day for the same client replaces the entry
+	again, err := PutTimesheetEntry("Acme Corp", 4, 0, 0, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("PutTimesheetEntry failed: %v", err)
+	}
+	if again != id {
+		t.Errorf("Expected ID %d, got %d", id, again)
+	}
+	entry, _ = firstEntry(GetTimesheetEntriesByDate(time.Now().Format("2006-01-02")))
+	if entry.Client_name != "Acme Corp" || entry.Client_hours != 4 {
+		t.Errorf("Expected the replaced entry, got %+v", entry)
+	}
+
+	// Another client gets a block of its own
+	other, err := PutTimesheetEntry("Globex", 2, 0, 0, 0, 0, 0)
+	if err != nil {
+		t.Fatalf("PutTimesheetEntry failed: %v", err)
+	}
+	if other == id {
+		t.Errorf("Expected a new ID for another client, got %d", other)
+	}
+}
+
+func TestAddTimesheetEntryUpsert(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	if err := AddTimesheetEntry(TimesheetEntry{Date: "2024-03-04", Client_name: "Acme Corp", Client_hours: 8}); err != nil {
+		t.Fatalf("AddTimesheetEntry failed: %v", err)
+	}
+	if err := AddTimesheetEntry(TimesheetEntry{Date: "2024-03-04", Client_name: "Acme Corp", Client_hours: 4, Sick_hours: 4}); err != nil {
+		t.Fatalf("Second AddTimesheetEntry failed: %v", err)
+	}
+
+	entries, err := GetAllTimesheetEntries(2024, time.March)
+	if err != nil {
+		t.Fatalf("GetAllTimesheetEntries failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected 1 entry, got %d", len(entries))
+	}
+	if entries[0].Client_hours != 4 || entries[0].Sick_hours != 4 {
+		t.Errorf("Expected the second entry's hours, got %+v", entries[0])
+	}
+}
+
+func TestMigrateUniqueTimesheetDateClient(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	// Recreate the pre-migration schema, which allowed duplicate dates
+	if _, err := db.Exec(`DROP INDEX idx_timesheet_date_client_unique`); err != nil {
+		t.Fatalf("Failed to drop index: %v", err)
+	}
+	rows := []struct {
+		date      string
+		client    string
+		hours     float64
+		updatedAt string
+	}{
+		{"2024-03-04", "Acme", 8, "2024-03-04 09:00:00"},
+		{"2024-03-04", "Acme", 6, "2024-03-05 09:00:00"},
+		{"2024-03-04", "Acme", 1, "2024-03-04 10:00:00"},
+		{"2024-03-04", "Globex", 2, "2024-03-04 09:00:00"},
+		{"2024-03-05", "Other", 8, "2024-03-05 09:00:00"},
+	}
+	for _, r := range rows {
+		if _, err := db.Exec(`INSERT INTO timesheet (date, client_name, client_hours, created_at, updated_at) VALUES (?, ?, ?, ?, ?)`,
+			r.date, r.client, r.hours, r.updatedAt, r.updatedAt); err != nil {
+			t.Fatalf("Failed to insert row: %v", err)
+		}
+	}
+
+	if err := ApplySQLiteSchema(db); err != nil {
+		t.Fatalf("ApplySQLiteSchema failed: %v", err)
+	}
+
+	entries, err := GetTimesheetEntriesByDate("2024-03-04")
+	if err != nil {
+		t.Fatalf("GetTimesheetEntriesByDate failed: %v", err)
+	}
+	if len(entries) != 2 {
+		t.Fatalf("Expected a block per client after the migration, got %+v", entries)
+	}
+	if acme, _ := FindClientEntry(entries, "Acme"); acme.Client_hours != 15 {
+		t.Errorf("Expected the duplicates' hours to be added up, got %+v", acme)
+	}
+
+	// The unique index is back, so adding the same client again upserts
+	if err := AddTimesheetEntry(TimesheetEntry{Date: "2024-03-04", Client_name: "Globex", Client_hours: 3}); err != nil {
+		t.Fatalf("AddTimesheetEntry failed: %v", err)
+	}
+	if entries, _ := GetAllTimesheetEntries(2024, time.March); len(entries) != 3 {
+		t.Errorf("Expected 3 entries, got %d", len(entries))
+	}
+}
+
+func TestGetTimesheetEntriesByDate(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	for _, entry := range []TimesheetEntry{
+		{Date: "2024-01-15", Client_name: "Client A", Client_hours: 5},
+		{Date: "2024-01-15", Client_name: "Client B", Client_hours: 3},
+		{Date: "2024-01-15", Client_name: "Client A", Client_hours: 6},
+	} {
+		if err := AddTimesheetEntry(entry); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+	}
+
+	// A block per client, the same client's second entry replacing its hours
+	result, err := GetTimesheetEntriesByDate("2024-01-15")
+	if err != nil {
+		t.Fatalf("Failed to get entries: %v", err)
+	}
+	if len(result) != 2 || result[0].Client_name != "Client A" || result[0].Client_hours != 6 || result[1].Client_name != "Client B" {
+		t.Errorf("Expected blocks for Client A (6h) and Client B, got %+v", result)
+	}
+
+	// Deleting one block leaves the other
+	if err := DeleteTimesheetEntry(strconv.Itoa(result[1].Id)); err != nil {
+		t.Fatalf("DeleteTimesheetEntry failed: %v", err)
+	}
+	if result, _ := GetTimesheetEntriesByDate("2024-01-15"); len(result) != 1 || result[0].Client_name != "Client A" {
+		t.Errorf("Expected only Client A to be left, got %+v", result)
 	}
 
 	// Test non-existent date
-	_, err = GetTimesheetEntryByDate("2024-01-16")
-	if err == nil {
-		t.Error("Expected error for non-existent date")
+	result, err = GetTimesheetEntriesByDate("2024-01-16")
+	if err != nil || len(result) != 0 {
+		t.Errorf("Expected no entries for a non-existent date, got %+v, %v", result, err)
 	}
 }
 
@@ -191,7 +355,7 @@ func TestAddTimesheetEntry(t *testing.T) {
 	}
 
 	// Verify entry was added
-	result, err := GetTimesheetEntryByDate("2024-01-15")
+	result, err := firstEntry(GetTimesheetEntriesByDate("2024-01-15"))
 	if err != nil {
 		t.Fatalf("Failed to get entry: %v", err)
 	}
@@ -199,7 +363,7 @@ func TestAddTimesheetEntry(t *testing.T) {
 		t.Errorf("Expected Client A, got %s", result.Client_name)
 	}
 	if result.Vacation_hours != 2 {
-		t.Errorf("Expected 2 vacation hours, got %d", result.Vacation_hours)
+		t.Errorf("Expected 2 vacation hours, got %g", result.Vacation_hours)
 	}
 }
 
@@ -231,15 +395,15 @@ func TestUpdateTimesheetEntry(t *testing.T) {
 	}
 
 	// Verify update
-	result, err := GetTimesheetEntryByDate("2024-01-15")
+	result, err := firstEntry(GetTimesheetEntriesByDate("2024-01-15"))
 	if err != nil {
 		t.Fatalf("Failed to get entry: %v", err)
 	}
 	if result.Client_hours != 6 {
-		t.Errorf("Expected 6 client hours, got %d", result.Client_hours)
+		t.Errorf("Expected 6 client hours, got %g", result.Client_hours)
 	}
 	if result.Vacation_hours != 2 {
-		t.Errorf("Expected 2 vacation hours, got %d", result.Vacation_hours)
+		t.Errorf("Expected 2 vacation hours, got %g", result.Vacation_hours)
 	}
 
 	// Test updating non-existent entry
@@ -250,6 +414,48 @@ func TestUpdateTimesheetEntry(t *testing.T) {
 	}
 }
 
+func TestTimesheetEntryNote(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	entry := TimesheetEntry{Date: "2024-02-05", Client_name: "-", Idle_hours: 8, Note: "Bench, waiting for project start"}
+	if err := AddTimesheetEntry(entry); err != nil {
+		t.Fatalf("AddTimesheetEntry failed: %v", err)
+	}
+	entries, err := GetTimesheetEntriesByDate("2024-02-05")
+	if err != nil || len(entries) != 1 {
+		t.Fatalf("Expected one entry, got %v, %v", entries, err)
+	}
+	if entries[0].Note != entry.Note {
+		t.Errorf("Expected note %q, got %q", entry.Note, entries[0].Note)
+	}
+
+	entry.Note = "Project postponed"
+	if err := UpdateTimesheetEntry(entry); err != nil {
+		t.Fatalf("UpdateTimesheetEntry failed: %v", err)
+	}
+	monthEntries, err := GetAllTimesheetEntries(2024, 2)
+	if err != nil || len(monthEntries) != 1 || monthEntries[0].Note != "Project postponed" {
+		t.Errorf("Expected the updated note, got %+v, %v", monthEntries, err)
+	}
+
+	// Merging keeps the stored note and fills an empty one
+	merged := MergeTimesheetEntries(monthEntries[0], TimesheetEntry{Note: "other"})
+	if merged.Note != "Project postponed" {
+		t.Errorf("Expected the stored note to be kept, got %q", merged.Note)
+	}
+	if merged := MergeTimesheetEntries(TimesheetEntry{}, TimesheetEntry{Note: "other"}); merged.Note != "other" {
+		t.Errorf("Expected the incoming note, got %q", merged.Note)
+	}
+
+	// Entries without a note read back as empty
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-02-06", Client_name: "Client A", Client_hours: 8})
+	entries, _ = GetTimesheetEntriesByDate("2024-02-06")
+	if len(entries) != 1 || entries[0].Note != "" {
+		t.Errorf("Expected an empty note, got %+v", entries)
+	}
+}
+
 func TestUpdateTimesheetEntryById(t *testing.T) {
 	dbPath := setupTestDB(t)
 	defer teardownTestDB(t, dbPath)
@@ -270,7 +476,7 @@ func TestUpdateTimesheetEntryById(t *testing.T) {
 	}
 
 	// Get the entry to get its ID
-	result, err := GetTimesheetEntryByDate("2024-01-15")
+	result, err := firstEntry(GetTimesheetEntriesByDate("2024-01-15"))
 	if err != nil {
 		t.Fatalf("Failed to get entry: %v", err)
 	}
@@ -331,12 +537,59 @@ func TestDeleteTimesheetEntryByDate(t *testing.T) {
 	}
 
 	// Verify deletion
-	_, err = GetTimesheetEntryByDate("2024-01-15")
+	_, err = firstEntry(GetTimesheetEntriesByDate("2024-01-15"))
 	if err == nil {
 		t.Error("Expected error for deleted entry")
 	}
 }
 
+func TestDeleteTimesheetEntriesForMonth(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	for _, entry := range []TimesheetEntry{
+		{Date: "2024-02-29", Client_name: "Client A", Client_hours: 8},
+		{Date: "2024-03-01", Client_name: "Client A", Client_hours: 5},
+		{Date: "2024-03-01", Client_name: "Client B", Client_hours: 3},
+		{Date: "2024-03-31", Client_name: "Client A", Client_hours: 8},
+		{Date: "2024-04-01", Client_name: "Client A", Client_hours: 8},
+	} {
+		if err := AddTimesheetEntry(entry); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+	}
+
+	deleted, err := DeleteTimesheetEntriesForMonth(2024, time.March)
+	if err != nil {
+		t.Fatalf("DeleteTimesheetEntriesForMonth failed: %v", err)
+	}
+	if deleted != 3 {
+		t.Errorf("Expected 3 entries deleted, got %d", deleted)
+	}
+
+	entries, err := GetAllTimesheetEntries(2024, time.March)
+	if err != nil {
+		t.Fatalf("Failed to get entries: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected March to be empty, got %d entries", len(entries))
+	}
+	for _, date := range []string{"2024-02-29", "2024-04-01"} {
+		if _, err := firstEntry(GetTimesheetEntriesByDate(date)); err != nil {
+			t.Errorf("Expected the entry on %s to be kept: %v", date, err)
+		}
+	}
+	if !tombstoneExists(t, TombstoneTableTimesheet, TombstoneKeyTimesheet("2024-03-01", "Client B")) {
+		t.Error("Expected a tombstone for each deleted client block")
+	}
+
+	// An empty month deletes nothing
+	deleted, err = DeleteTimesheetEntriesForMonth(2024, time.March)
+	if err != nil || deleted != 0 {
+		t.Errorf("Expected nothing deleted on the second run, got %d, %v", deleted, err)
+	}
+}
+
 func TestDeleteTimesheetEntry(t *testing.T) {
 	dbPath := setupTestDB(t)
 	defer teardownTestDB(t, dbPath)
@@ -357,7 +610,7 @@ func TestDeleteTimesheetEntry(t *testing.T) {
 	}
 
 	// Get the entry to get its ID
-	result, err := GetTimesheetEntryByDate("2024-01-15")
+	result, err := firstEntry(GetTimesheetEntriesByDate("2024-01-15"))
 	if err != nil {
 		t.Fatalf("Failed to get entry: %v", err)
 	}
@@ -369,7 +622,7 @@ func TestDeleteTimesheetEntry(t *testing.T) {
 	}
 
 	// Verify deletion
-	_, err = GetTimesheetEntryByDate("2024-01-15")
+	_, err = firstEntry(GetTimesheetEntriesByDate("2024-01-15"))
 	if err == nil {
 		t.Error("Expected error for deleted entry")
 	}
@@ -467,7 +720,7 @@ func TestGetVacationEntriesForYear(t *testing.T) {
 		t.Errorf("Expected 1 vacation entry, got %d", len(entries))
 	}
 	if entries[0].Vacation_hours != 8 {
-		t.Errorf("Expected 8 vacation hours, got %d", entries[0].Vacation_hours)
+		t.Errorf("Expected 8 vacation hours, got %g", entries[0].Vacation_hours)
 	}
 }
 
@@ -508,7 +761,39 @@ func TestGetVacationHoursForYear(t *testing.T) {
 		t.Fatalf("Failed to get vacation hours: %v", err)
 	}
 	if total != 12 {
-		t.Errorf("Expected 12 vacation hours, got %d", total)
+		t.Errorf("Expected 12 vacation hours, got %g", total)
+	}
+}
+
+func TestGetSickAndIdleHoursForYear(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	entries := []TimesheetEntry{
+		{Date: "2024-01-15", Client_name: "Sick", Sick_hours: 8},
+		{Date: "2024-03-04", Client_name: "Acme", Client_hours: 4, Sick_hours: 4, Idle_hours: 1.5},
+		{Date: "2024-06-10", Client_name: "-", Idle_hours: 8},
+		{Date: "2025-01-02", Client_name: "Sick", Sick_hours: 8},
+	}
+	for _, entry := range entries {
+		if err := AddTimesheetEntry(entry); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+	}
+
+	sick, err := GetSickHoursForYear(2024)
+	if err != nil {
+		t.Fatalf("Failed to get sick hours: %v", err)
+	}
+	if sick != 12 {
+		t.Errorf("Expected 12 sick hours, got %g", sick)
+	}
+	idle, err := GetIdleHoursForYear(2024)
+	if err != nil {
+		t.Fatalf("Failed to get idle hours: %v", err)
+	}
+	if idle != 9.5 {
+		t.Errorf("Expected 9.5 idle hours, got %g", idle)
 	}
 }
 
@@ -575,7 +860,84 @@ func TestGetTrainingEntriesForYear(t *testing.T) {
 		t.Errorf("Expected 1 training entry, got %d", len(entries))
 	}
 	if entries[0].Training_hours != 4 {
-		t.Errorf("Expected 4 training hours, got %d", entries[0].Training_hours)
+		t.Errorf("Expected 4 training hours, got %g", entries[0].Training_hours)
+	}
+}
+
+// insertSparseTimesheetRows stores rows that only set one hour column,
+// leaving the others NULL as older versions and direct SQL edits do
+func insertSparseTimesheetRows(t *testing.T) {
+	t.Helper()
+	stmts := []string{
+		`INSERT INTO timesheet (date, client_name, vacation_hours) VALUES ('2024-03-01', 'Client A', 8)`,
+		`INSERT INTO timesheet (date, client_name, training_hours) VALUES ('2024-03-02', 'Client A', 4)`,
+		`INSERT INTO timesheet (date, client_name, idle_hours) VALUES ('2024-03-03', 'Client A', 2)`,
+		`INSERT INTO timesheet (date, client_name, vacation_hours) VALUES ('2024-12-31 09:00:00', 'Client A', 3)`,
+		`INSERT INTO timesheet (date, client_name, vacation_hours) VALUES ('2025-01-01', 'Client A', 5)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := db.Exec(stmt); err != nil {
+			t.Fatalf("Failed to insert sparse row: %v", err)
+		}
+	}
+}
+
+func TestNullHourColumns(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+	insertSparseTimesheetRows(t)
+
+	entries, err := GetAllTimesheetEntries(2024, 0)
+	if err != nil {
+		t.Fatalf("Failed to get entries with NULL hours: %v", err)
+	}
+	if len(entries) != 4 {
+		t.Fatalf("Expected 4 entries in 2024, got %d", len(entries))
+	}
+	if entries[2].Idle_hours != 2 || entries[2].Total_hours != 2 || entries[2].Client_hours != 0 {
+		t.Errorf("Expected idle-only entry with total 2, got %+v", entries[2])
+	}
+
+	entry, err := firstEntry(GetTimesheetEntriesByDate("2024-03-02"))
+	if err != nil {
+		t.Fatalf("Failed to get entry with NULL hours: %v", err)
+	}
+	if entry.Training_hours != 4 || entry.Total_hours != 4 {
+		t.Errorf("Expected 4 training hours and total 4, got %+v", entry)
+	}
+
+	vacation, err := GetVacationEntriesForYear(2024)
+	if err != nil {
+		t.Fatalf("Failed to get vacation entries: %v", err)
+	}
+	if len(vacation) != 2 {
+		t.Fatalf("Expected 2 vacation entries in 2024, got %d", len(vacation))
+	}
+	if vacation[0].Date != "2024-12-31 09:00:00" || vacation[0].Total_hours != 3 {
+		t.Errorf("Expected the timestamped 2024-12-31 entry first, got %+v", vacation[0])
+	}
+
+	hours, err := GetVacationHoursForYear(2024)
+	if err != nil {
+		t.Fatalf("Failed to get vacation hours: %v", err)
+	}
+	if hours != 11 {
+		t.Errorf("Expected 11 vacation hours in 2024, got %g", hours)
+	}
+	hours, err = GetVacationHoursForYear(2025)
+	if err != nil {
+		t.Fatalf("Failed to get vacation hours: %v", err)
+	}
+	if hours != 5 {
+		t.Errorf("Expected 5 vacation hours in 2025, got %g", hours)
+	}
+
+	training, err := GetTrainingEntriesForYear(2024)
+	if err != nil {
+		t.Fatalf("Failed to get training entries: %v", err)
+	}
+	if len(training) != 1 || training[0].Training_hours != 4 || training[0].Vacation_hours != 0 {
+		t.Errorf("Expected one training-only entry, got %+v", training)
 	}
 }
 
@@ -612,6 +974,35 @@ func TestGetTrainingBudgetEntriesForYear(t *testing.T) {
 	}
 }
 
+func TestGetTrainingBudgetSpentForYear(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	for _, entry := range []TrainingBudgetEntry{
+		{Date: "2024-01-15", Training_name: "Training A", Hours: 8, Cost_without_vat: 100.0},
+		{Date: "2024-12-31", Training_name: "Training B", Hours: 4, Cost_without_vat: 50.5},
+		{Date: "2025-01-01", Training_name: "Training C", Hours: 8, Cost_without_vat: 900.0},
+	} {
+		if err := AddTrainingBudgetEntry(entry); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+	}
+
+	spent, err := GetTrainingBudgetSpentForYear(2024)
+	if err != nil {
+		t.Fatalf("GetTrainingBudgetSpentForYear failed: %v", err)
+	}
+	if want := (TrainingBudgetSpent{Spent: 150.5, EntryCount: 2}); spent != want {
+		t.Errorf("Expected %+v, got %+v", want, spent)
+	}
+
+	// A year without entries spent nothing
+	spent, err = GetTrainingBudgetSpentForYear(2023)
+	if err != nil || spent != (TrainingBudgetSpent{}) {
+		t.Errorf("Expected nothing spent in 2023, got %+v (%v)", spent, err)
+	}
+}
+
 func TestAddTrainingBudgetEntry(t *testing.T) {
 	dbPath := setupTestDB(t)
 	defer teardownTestDB(t, dbPath)
@@ -676,7 +1067,7 @@ func TestUpdateTrainingBudgetEntry(t *testing.T) {
 		t.Errorf("Expected Training B, got %s", updated.Training_name)
 	}
 	if updated.Hours != 10 {
-		t.Errorf("Expected 10 hours, got %d", updated.Hours)
+		t.Errorf("Expected 10 hours, got %g", updated.Hours)
 	}
 }
 
@@ -844,7 +1235,7 @@ func TestAutoCarryover_FromPreviousYear(t *testing.T) {
 		t.Fatalf("Failed to get 2025 vacation hours: %v", err)
 	}
 	if used != 140 {
-		t.Fatalf("Expected 140 used hours in 2025, got %d", used)
+		t.Fatalf("Expected 140 used hours in 2025, got %g", used)
 	}
 
 	// Get 2026 summary — no explicit carryover record exists, should auto-calculate
@@ -854,12 +1245,12 @@ func TestAutoCarryover_FromPreviousYear(t *testing.T) {
 	}
 
 	// 2025 remaining = 187 - 140 = 47 (no carryover into 2025)
-	expectedCarryover := 47
+	expectedCarryover := 47.0
 	if summary.CarryoverHours != expectedCarryover {
-		t.Errorf("Expected auto-carryover of %d, got %d", expectedCarryover, summary.CarryoverHours)
+		t.Errorf("Expected auto-carryover of %g, got %g", expectedCarryover, summary.CarryoverHours)
 	}
 	if summary.TotalAvailable != 187+expectedCarryover {
-		t.Errorf("Expected total available %d, got %d", 187+expectedCarryover, summary.TotalAvailable)
+		t.Errorf("Expected total available %g, got %g", 187+expectedCarryover, summary.TotalAvailable)
 	}
 }
 
@@ -882,7 +1273,7 @@ func TestAutoCarryover_WithExplicitPrevYearCarryover(t *testing.T) {
 
 	// Add 143 vacation hours in 2025
 	for i := 0; i < 15; i++ {
-		hours := 9
+		hours := 9.0
 		if i == 14 {
 			hours = 8 // 14*9 + 8 = 134... need 143
 		}
@@ -922,7 +1313,7 @@ func TestAutoCarryover_WithExplicitPrevYearCarryover(t *testing.T) {
 
 	used, _ := GetVacationHoursForYear(2025)
 	if used != 143 {
-		t.Fatalf("Expected 143 used hours, got %d", used)
+		t.Fatalf("Expected 143 used hours, got %g", used)
 	}
 
 	// 2026 auto-carryover: 187 + 14 (explicit 2025 carryover) - 143 = 58
@@ -932,7 +1323,7 @@ func TestAutoCarryover_WithExplicitPrevYearCarryover(t *testing.T) {
 	}
 
 	if summary.CarryoverHours != 58 {
-		t.Errorf("Expected auto-carryover of 58, got %d", summary.CarryoverHours)
+		t.Errorf("Expected auto-carryover of 58, got %g", summary.CarryoverHours)
 	}
 }
 
@@ -955,7 +1346,7 @@ func TestAutoCarryover_ExplicitOverridesAuto(t *testing.T) {
 		t.Fatalf("Failed to get summary: %v", err)
 	}
 	if summary.CarryoverHours != 178 {
-		t.Errorf("Expected auto-carryover of 178, got %d", summary.CarryoverHours)
+		t.Errorf("Expected auto-carryover of 178, got %g", summary.CarryoverHours)
 	}
 
 	// Now set explicit carryover that overrides auto-calculation
@@ -975,7 +1366,7 @@ func TestAutoCarryover_ExplicitOverridesAuto(t *testing.T) {
 		t.Fatalf("Failed to get summary: %v", err)
 	}
 	if summary.CarryoverHours != 50 {
-		t.Errorf("Expected explicit carryover of 50, got %d", summary.CarryoverHours)
+		t.Errorf("Expected explicit carryover of 50, got %g", summary.CarryoverHours)
 	}
 }
 
@@ -1002,7 +1393,7 @@ func TestAutoCarryover_NegativeRemainingClampsToZero(t *testing.T) {
 		t.Fatalf("Failed to get summary: %v", err)
 	}
 	if summary.CarryoverHours != 0 {
-		t.Errorf("Expected 0 carryover (negative clamped), got %d", summary.CarryoverHours)
+		t.Errorf("Expected 0 carryover (negative clamped), got %g", summary.CarryoverHours)
 	}
 }
 
@@ -1018,6 +1409,176 @@ func TestAutoCarryover_NoPreviousYearData(t *testing.T) {
 		t.Fatalf("Failed to get summary: %v", err)
 	}
 	if summary.CarryoverHours != 187 {
-		t.Errorf("Expected 187 carryover (full unused year), got %d", summary.CarryoverHours)
+		t.Errorf("Expected 187 carryover (full unused year), got %g", summary.CarryoverHours)
+	}
+}
+
+func TestAddTimesheetEntryRequiresClientName(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	err := AddTimesheetEntry(TimesheetEntry{Date: "2024-01-15", Client_hours: 8})
+	if !errors.Is(err, ErrClientNameRequired) {
+		t.Fatalf("Expected ErrClientNameRequired, got %v", err)
+	}
+
+	if err := AddTimesheetEntry(TimesheetEntry{Date: "2024-01-15", Sick_hours: 8}); err != nil {
+		t.Fatalf("Expected a sick day without a client to be stored: %v", err)
+	}
+	err = UpdateTimesheetEntry(TimesheetEntry{Date: "2024-01-15", Client_hours: 8})
+	if !errors.Is(err, ErrClientNameRequired) {
+		t.Errorf("Expected ErrClientNameRequired on update, got %v", err)
+	}
+}
+
+func TestHalfHourEntries(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	if err := AddTimesheetEntry(TimesheetEntry{Date: "2024-01-15", Client_name: "Acme", Client_hours: 4.5, Training_hours: 3.5}); err != nil {
+		t.Fatalf("Failed to add entry: %v", err)
+	}
+	entry, err := firstEntry(GetTimesheetEntriesByDate("2024-01-15"))
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if entry.Client_hours != 4.5 || entry.Training_hours != 3.5 || entry.Total_hours != 8 {
+		t.Errorf("Expected 4.5 client and 3.5 training hours totalling 8, got %+v", entry)
+	}
+
+	if err := UpdateTimesheetEntryById(strconv.Itoa(entry.Id), map[string]any{"client_hours": 5.5}); err != nil {
+		t.Fatalf("Failed to update entry: %v", err)
+	}
+	if entry, _ := firstEntry(GetTimesheetEntriesByDate("2024-01-15")); entry.Client_hours != 5.5 || entry.Total_hours != 9 {
+		t.Errorf("Expected 5.5 client hours totalling 9, got %+v", entry)
+	}
+
+	for _, hours := range []float64{4.25, -0.5} {
+		err := AddTimesheetEntry(TimesheetEntry{Date: "2024-01-16", Client_name: "Acme", Client_hours: hours})
+		if !errors.Is(err, ErrInvalidHours) {
+			t.Errorf("Expected ErrInvalidHours for %g hours, got %v", hours, err)
+		}
+	}
+}
+
+func TestTimesheetTotalHoursColumn(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	if err := AddTimesheetEntry(TimesheetEntry{Date: "2024-01-15", Client_name: "Acme", Client_hours: 6, Training_hours: 2, Sick_hours: 1}); err != nil {
+		t.Fatalf("Failed to add entry: %v", err)
+	}
+	// Hour columns left NULL count as 0
+	if _, err := db.Exec(`INSERT INTO timesheet (date, client_name, vacation_hours) VALUES ('2024-01-16', '-', 8)`); err != nil {
+		t.Fatalf("Failed to insert entry: %v", err)
+	}
+
+	rows, err := db.Query(`SELECT date, total_hours FROM timesheet ORDER BY date`)
+	if err != nil {
+		t.Fatalf("Failed to query total_hours: %v", err)
+	}
+	defer rows.Close()
+
+	want := map[string]int{"2024-01-15": 9, "2024-01-16": 8}
+	for rows.Next() {
+		var date string
+		var total int
+		if err := rows.Scan(&date, &total); err != nil {
+			t.Fatalf("Failed to scan row: %v", err)
+		}
+		if total != want[date] {
+			t.Errorf("total_hours for %s = %d, want %d", date, total, want[date])
+		}
+	}
+
+	// The column follows updates
+	if err := UpdateTimesheetEntry(TimesheetEntry{Date: "2024-01-16", Client_name: "-", Vacation_hours: 4}); err != nil {
+		t.Fatalf("Failed to update entry: %v", err)
+	}
+	var total int
+	if err := db.QueryRow(`SELECT total_hours FROM timesheet WHERE date = '2024-01-16'`).Scan(&total); err != nil {
+		t.Fatalf("Failed to query total_hours: %v", err)
+	}
+	if total != 4 {
+		t.Errorf("total_hours after update = %d, want 4", total)
+	}
+}
+
+func TestConcurrentWritesWaitForLock(t *testing.T) {
+	cleanup := setupTestConfig(t, 0)
+	defer cleanup()
+	dbPath := filepath.Join(t.TempDir(), "timesheet.db")
+	if err := InitializeDatabase(dbPath); err != nil {
+		t.Fatal(err)
+	}
+	if err := Connect(dbPath); err != nil {
+		t.Fatal(err)
+	}
+	defer Close()
+
+	// A second handle stands in for another process (e.g. the API server)
+	// holding the write lock for a moment
+	other, err := openSQLite(dbPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer other.Close()
+	tx, err := other.Begin()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tx.Exec(`INSERT INTO timesheet (date, client_name, client_hours) VALUES ('2025-03-03', 'Other', 8)`); err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	for _, date := range []string{"2025-03-04", "2025-03-05"} {
+		wg.Add(1)
+		go func(date string) {
+			defer wg.Done()
+			errs <- AddTimesheetEntry(TimesheetEntry{Date: date, Client_name: "Acme", Client_hours: 8})
+		}(date)
+	}
+	time.Sleep(200 * time.Millisecond)
+	if err := tx.Commit(); err != nil {
+		t.Fatal(err)
+	}
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		if err != nil {
+			t.Errorf("concurrent write failed instead of waiting: %v", err)
+		}
+	}
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM timesheet`).Scan(&count); err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("expected 3 entries, got %d", count)
+	}
+}
+
+func TestGetDBPathFromEnv(t *testing.T) {
+	dbPath := filepath.Join(t.TempDir(), "volume", "data", "timesheet.db")
+	t.Setenv("TIMESHEETZ_DB_PATH", dbPath)
+
+	// The env var wins over development mode
+	config.SetRuntimeDevMode(true)
+	defer config.SetRuntimeDevMode(false)
+
+	if got := GetDBPath(); got != dbPath {
+		t.Errorf("GetDBPath() = %q, want %q", got, dbPath)
+	}
+	if info, err := os.Stat(filepath.Dir(dbPath)); err != nil || !info.IsDir() {
+		t.Errorf("Expected the parent directory to be created: %v", err)
+	}
+
+	// Without it, development mode uses the local file
+	t.Setenv("TIMESHEETZ_DB_PATH", "")
+	if got := GetDBPath(); got != "timesheet.db" {
+		t.Errorf("GetDBPath() without the env var = %q, want timesheet.db", got)
 	}
 }
@@ -22,16 +22,32 @@ func NewDualLayer(local DataLayer, remote DataLayer) *DualLayer {
 	}
 }
 
+// logMismatch reports a difference between the local and remote results of
+// an operation as a structured warning
+func logMismatch(operation, msg string, fields map[string]any) {
+	fields["operation"] = operation
+	logging.LogFields(logging.LevelWarn, "DUAL MODE: "+msg, fields)
+}
+
+// logEarningsMismatch reports differing local and remote earnings totals
+func logEarningsMismatch(operation string, fields map[string]any, localHours, localEarnings, remoteHours, remoteEarnings float64) {
+	fields["localHours"] = localHours
+	fields["localEarnings"] = localEarnings
+	fields["remoteHours"] = remoteHours
+	fields["remoteEarnings"] = remoteEarnings
+	logMismatch(operation, "Earnings mismatch", fields)
+}
+
 // compareEntries compares two slices of entries and logs differences
 func (d *DualLayer) compareEntries(local, remote []TimesheetEntry, operation string) {
 	if len(local) != len(remote) {
-		logging.Log("DUAL MODE: %s - Entry count mismatch: local=%d, remote=%d", operation, len(local), len(remote))
+		logMismatch(operation, "Entry count mismatch", map[string]any{"local": len(local), "remote": len(remote)})
 		return
 	}
 
 	for i := range local {
 		if !reflect.DeepEqual(local[i], remote[i]) {
-			logging.Log("DUAL MODE: %s - Entry mismatch at index %d: local=%+v, remote=%+v", operation, i, local[i], remote[i])
+			logMismatch(operation, "Entry mismatch", map[string]any{"index": i, "local": local[i], "remote": remote[i]})
 		}
 	}
 }
@@ -39,13 +55,13 @@ func (d *DualLayer) compareEntries(local, remote []TimesheetEntry, operation str
 // compareTrainingBudgetEntries compares two slices of training budget entries
 func (d *DualLayer) compareTrainingBudgetEntries(local, remote []TrainingBudgetEntry, operation string) {
 	if len(local) != len(remote) {
-		logging.Log("DUAL MODE: %s - Training budget entry count mismatch: local=%d, remote=%d", operation, len(local), len(remote))
+		logMismatch(operation, "Training budget entry count mismatch", map[string]any{"local": len(local), "remote": len(remote)})
 		return
 	}
 
 	for i := range local {
 		if !reflect.DeepEqual(local[i], remote[i]) {
-			logging.Log("DUAL MODE: %s - Training budget entry mismatch at index %d: local=%+v, remote=%+v", operation, i, local[i], remote[i])
+			logMismatch(operation, "Training budget entry mismatch", map[string]any{"index": i, "local": local[i], "remote": remote[i]})
 		}
 	}
 }
@@ -76,31 +92,59 @@ func (d *DualLayer) GetAllTimesheetEntries(year int, month time.Month) ([]Timesh
 	return nil, fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
 }
 
-// GetTimesheetEntryByDate reads from both sources and compares
-func (d *DualLayer) GetTimesheetEntryByDate(date string) (TimesheetEntry, error) {
-	localEntry, localErr := d.local.GetTimesheetEntryByDate(date)
-	remoteEntry, remoteErr := d.remote.GetTimesheetEntryByDate(date)
+// GetTimesheetEntriesByDate reads from both sources and compares
+func (d *DualLayer) GetTimesheetEntriesByDate(date string) ([]TimesheetEntry, error) {
+	localEntries, localErr := d.local.GetTimesheetEntriesByDate(date)
+	remoteEntries, remoteErr := d.remote.GetTimesheetEntriesByDate(date)
 
 	// If both succeed, compare
 	if localErr == nil && remoteErr == nil {
-		if !reflect.DeepEqual(localEntry, remoteEntry) {
-			logging.Log("DUAL MODE: GetTimesheetEntryByDate - Entry mismatch for date %s: local=%+v, remote=%+v", date, localEntry, remoteEntry)
+		if !reflect.DeepEqual(localEntries, remoteEntries) {
+			logMismatch("GetTimesheetEntriesByDate", "Entry mismatch", map[string]any{"date": date, "local": localEntries, "remote": remoteEntries})
 		}
-		return localEntry, nil
+		return localEntries, nil
 	}
 
 	// If only one succeeds, log warning and return that one
 	if localErr != nil && remoteErr == nil {
 		logging.Log("DUAL MODE: Local DB failed, using remote: %v", localErr)
-		return remoteEntry, nil
+		return remoteEntries, nil
 	}
 	if localErr == nil && remoteErr != nil {
 		logging.Log("DUAL MODE: Remote API failed, using local: %v", remoteErr)
-		return localEntry, nil
+		return localEntries, nil
 	}
 
 	// Both failed
-	return TimesheetEntry{}, fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
+	return nil, fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
+}
+
+// GetTimesheetEntriesInRange reads from both sources and compares
+func (d *DualLayer) GetTimesheetEntriesInRange(from, to string, limit, offset int) ([]TimesheetEntry, int, error) {
+	localEntries, localTotal, localErr := d.local.GetTimesheetEntriesInRange(from, to, limit, offset)
+	remoteEntries, remoteTotal, remoteErr := d.remote.GetTimesheetEntriesInRange(from, to, limit, offset)
+
+	// If both succeed, compare
+	if localErr == nil && remoteErr == nil {
+		if localTotal != remoteTotal {
+			logMismatch("GetTimesheetEntriesInRange", "Total count mismatch", map[string]any{"local": localTotal, "remote": remoteTotal})
+		}
+		d.compareEntries(localEntries, remoteEntries, "GetTimesheetEntriesInRange")
+		return localEntries, localTotal, nil
+	}
+
+	// If only one succeeds, log warning and return that one
+	if localErr != nil && remoteErr == nil {
+		logging.Log("DUAL MODE: Local DB failed, using remote: %v", localErr)
+		return remoteEntries, remoteTotal, nil
+	}
+	if localErr == nil && remoteErr != nil {
+		logging.Log("DUAL MODE: Remote API failed, using local: %v", remoteErr)
+		return localEntries, localTotal, nil
+	}
+
+	// Both failed
+	return nil, 0, fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
 }
 
 // AddTimesheetEntry writes to both sources
@@ -128,10 +172,10 @@ func (d *DualLayer) AddTimesheetEntry(entry TimesheetEntry) error {
 	// If at least one succeeds, validate by reading back
 	if localErr == nil && remoteErr == nil {
 		// Read back from both to validate
-		localRead, _ := d.local.GetTimesheetEntryByDate(entry.Date)
-		remoteRead, _ := d.remote.GetTimesheetEntryByDate(entry.Date)
+		localRead, _ := d.local.GetTimesheetEntriesByDate(entry.Date)
+		remoteRead, _ := d.remote.GetTimesheetEntriesByDate(entry.Date)
 		if !reflect.DeepEqual(localRead, remoteRead) {
-			logging.Log("DUAL MODE: AddTimesheetEntry validation failed - entries differ after write")
+			logMismatch("AddTimesheetEntry", "Entries differ after write", map[string]any{"date": entry.Date, "local": localRead, "remote": remoteRead})
 		}
 	}
 
@@ -161,10 +205,10 @@ func (d *DualLayer) UpdateTimesheetEntry(entry TimesheetEntry) error {
 
 	// If at least one succeeds, validate by reading back
 	if localErr == nil && remoteErr == nil {
-		localRead, _ := d.local.GetTimesheetEntryByDate(entry.Date)
-		remoteRead, _ := d.remote.GetTimesheetEntryByDate(entry.Date)
+		localRead, _ := d.local.GetTimesheetEntriesByDate(entry.Date)
+		remoteRead, _ := d.remote.GetTimesheetEntriesByDate(entry.Date)
 		if !reflect.DeepEqual(localRead, remoteRead) {
-			logging.Log("DUAL MODE: UpdateTimesheetEntry validation failed - entries differ after update")
+			logMismatch("UpdateTimesheetEntry", "Entries differ after update", map[string]any{"date": entry.Date, "local": localRead, "remote": remoteRead})
 		}
 	}
 
@@ -247,6 +291,36 @@ func (d *DualLayer) DeleteTimesheetEntry(id string) error {
 	return remoteErr
 }
 
+// DeleteTimesheetEntriesForMonth deletes the month from both sources and
+// returns the number of entries deleted locally
+func (d *DualLayer) DeleteTimesheetEntriesForMonth(year int, month time.Month) (int, error) {
+	localCount, localErr := d.local.DeleteTimesheetEntriesForMonth(year, month)
+	remoteCount, remoteErr := d.remote.DeleteTimesheetEntriesForMonth(year, month)
+
+	if localErr != nil {
+		logging.Log("DUAL MODE: Local DB delete failed: %v", localErr)
+	}
+	if remoteErr != nil {
+		logging.Log("DUAL MODE: Remote API delete failed: %v", remoteErr)
+	}
+
+	// If both fail, return error
+	if localErr != nil && remoteErr != nil {
+		return 0, fmt.Errorf("both local and remote deletes failed: local=%v, remote=%v", localErr, remoteErr)
+	}
+
+	// Return local error if it exists, otherwise remote error (or nil)
+	if localErr != nil {
+		return remoteCount, fmt.Errorf("local delete failed: %w", localErr)
+	}
+	if remoteErr == nil && localCount != remoteCount {
+		logMismatch("DeleteTimesheetEntriesForMonth", "Deleted count mismatch", map[string]any{
+			"year": year, "month": int(month), "local": localCount, "remote": remoteCount,
+		})
+	}
+	return localCount, remoteErr
+}
+
 // GetLastClientName reads from both sources and compares
 func (d *DualLayer) GetLastClientName() (string, error) {
 	localName, localErr := d.local.GetLastClientName()
@@ -274,6 +348,33 @@ func (d *DualLayer) GetLastClientName() (string, error) {
 	return "", fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
 }
 
+// GetYearsWithData reads from both sources and compares
+func (d *DualLayer) GetYearsWithData(includeTraining bool) ([]int, error) {
+	localYears, localErr := d.local.GetYearsWithData(includeTraining)
+	remoteYears, remoteErr := d.remote.GetYearsWithData(includeTraining)
+
+	// If both succeed, compare
+	if localErr == nil && remoteErr == nil {
+		if fmt.Sprint(localYears) != fmt.Sprint(remoteYears) {
+			logging.Log("DUAL MODE: GetYearsWithData - Mismatch: local=%v, remote=%v", localYears, remoteYears)
+		}
+		return localYears, nil
+	}
+
+	// If only one succeeds, log warning and return that one
+	if localErr != nil && remoteErr == nil {
+		logging.Log("DUAL MODE: Local DB failed, using remote: %v", localErr)
+		return remoteYears, nil
+	}
+	if localErr == nil && remoteErr != nil {
+		logging.Log("DUAL MODE: Remote API failed, using local: %v", remoteErr)
+		return localYears, nil
+	}
+
+	// Both failed
+	return nil, fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
+}
+
 // GetTrainingEntriesForYear reads from both sources and compares
 func (d *DualLayer) GetTrainingEntriesForYear(year int) ([]TimesheetEntry, error) {
 	localEntries, localErr := d.local.GetTrainingEntriesForYear(year)
@@ -325,14 +426,14 @@ func (d *DualLayer) GetVacationEntriesForYear(year int) ([]TimesheetEntry, error
 }
 
 // GetVacationHoursForYear reads from both sources and compares
-func (d *DualLayer) GetVacationHoursForYear(year int) (int, error) {
+func (d *DualLayer) GetVacationHoursForYear(year int) (float64, error) {
 	localHours, localErr := d.local.GetVacationHoursForYear(year)
 	remoteHours, remoteErr := d.remote.GetVacationHoursForYear(year)
 
 	// If both succeed, compare
 	if localErr == nil && remoteErr == nil {
 		if localHours != remoteHours {
-			logging.Log("DUAL MODE: GetVacationHoursForYear - Mismatch for year %d: local=%d, remote=%d", year, localHours, remoteHours)
+			logging.Log("DUAL MODE: GetVacationHoursForYear - Mismatch for year %d: local=%g, remote=%g", year, localHours, remoteHours)
 		}
 		return localHours, nil
 	}
@@ -351,6 +452,61 @@ func (d *DualLayer) GetVacationHoursForYear(year int) (int, error) {
 	return 0, fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
 }
 
+// GetSickHoursForYear reads from both sources and compares
+func (d *DualLayer) GetSickHoursForYear(year int) (float64, error) {
+	return d.compareHoursForYear("GetSickHoursForYear", year, d.local.GetSickHoursForYear, d.remote.GetSickHoursForYear)
+}
+
+// GetIdleHoursForYear reads from both sources and compares
+func (d *DualLayer) GetIdleHoursForYear(year int) (float64, error) {
+	return d.compareHoursForYear("GetIdleHoursForYear", year, d.local.GetIdleHoursForYear, d.remote.GetIdleHoursForYear)
+}
+
+// GetMonthlyTotals reads from both sources and compares
+func (d *DualLayer) GetMonthlyTotals(year int, month time.Month) (HourTotals, error) {
+	localTotals, localErr := d.local.GetMonthlyTotals(year, month)
+	remoteTotals, remoteErr := d.remote.GetMonthlyTotals(year, month)
+
+	if localErr == nil && remoteErr == nil {
+		if localTotals != remoteTotals {
+			logging.Log("DUAL MODE: GetMonthlyTotals - Mismatch for %s %d: local=%+v, remote=%+v", month, year, localTotals, remoteTotals)
+		}
+		return localTotals, nil
+	}
+	if localErr != nil && remoteErr == nil {
+		logging.Log("DUAL MODE: Local DB failed, using remote: %v", localErr)
+		return remoteTotals, nil
+	}
+	if localErr == nil && remoteErr != nil {
+		logging.Log("DUAL MODE: Remote API failed, using local: %v", remoteErr)
+		return localTotals, nil
+	}
+	return HourTotals{}, fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
+}
+
+// compareHoursForYear reads a yearly hour total from both sources, logs a
+// mismatch and prefers the local value like GetVacationHoursForYear
+func (d *DualLayer) compareHoursForYear(operation string, year int, local, remote func(int) (float64, error)) (float64, error) {
+	localHours, localErr := local(year)
+	remoteHours, remoteErr := remote(year)
+
+	if localErr == nil && remoteErr == nil {
+		if localHours != remoteHours {
+			logging.Log("DUAL MODE: %s - Mismatch for year %d: local=%g, remote=%g", operation, year, localHours, remoteHours)
+		}
+		return localHours, nil
+	}
+	if localErr != nil && remoteErr == nil {
+		logging.Log("DUAL MODE: Local DB failed, using remote: %v", localErr)
+		return remoteHours, nil
+	}
+	if localErr == nil && remoteErr != nil {
+		logging.Log("DUAL MODE: Remote API failed, using local: %v", remoteErr)
+		return localHours, nil
+	}
+	return 0, fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
+}
+
 // GetTrainingBudgetEntriesForYear reads from both sources and compares
 func (d *DualLayer) GetTrainingBudgetEntriesForYear(year int) ([]TrainingBudgetEntry, error) {
 	localEntries, localErr := d.local.GetTrainingBudgetEntriesForYear(year)
@@ -376,6 +532,28 @@ func (d *DualLayer) GetTrainingBudgetEntriesForYear(year int) ([]TrainingBudgetE
 	return nil, fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
 }
 
+// GetTrainingBudgetSpentForYear reads from both sources and compares
+func (d *DualLayer) GetTrainingBudgetSpentForYear(year int) (TrainingBudgetSpent, error) {
+	localSpent, localErr := d.local.GetTrainingBudgetSpentForYear(year)
+	remoteSpent, remoteErr := d.remote.GetTrainingBudgetSpentForYear(year)
+
+	if localErr == nil && remoteErr == nil {
+		if localSpent != remoteSpent {
+			logging.Log("DUAL MODE: GetTrainingBudgetSpentForYear - Mismatch for %d: local=%+v, remote=%+v", year, localSpent, remoteSpent)
+		}
+		return localSpent, nil
+	}
+	if localErr != nil && remoteErr == nil {
+		logging.Log("DUAL MODE: Local DB failed, using remote: %v", localErr)
+		return remoteSpent, nil
+	}
+	if localErr == nil && remoteErr != nil {
+		logging.Log("DUAL MODE: Remote API failed, using local: %v", remoteErr)
+		return localSpent, nil
+	}
+	return TrainingBudgetSpent{}, fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
+}
+
 // AddTrainingBudgetEntry writes to both sources
 func (d *DualLayer) AddTrainingBudgetEntry(entry TrainingBudgetEntry) error {
 	localErr := d.local.AddTrainingBudgetEntry(entry)
@@ -456,7 +634,7 @@ func (d *DualLayer) GetTrainingBudgetEntry(id int) (TrainingBudgetEntry, error)
 	// If both succeed, compare
 	if localErr == nil && remoteErr == nil {
 		if !reflect.DeepEqual(localEntry, remoteEntry) {
-			logging.Log("DUAL MODE: GetTrainingBudgetEntry - Entry mismatch for id %d: local=%+v, remote=%+v", id, localEntry, remoteEntry)
+			logMismatch("GetTrainingBudgetEntry", "Entry mismatch", map[string]any{"id": id, "local": localEntry, "remote": remoteEntry})
 		}
 		return localEntry, nil
 	}
@@ -483,7 +661,7 @@ func (d *DualLayer) GetTrainingBudgetEntryByDate(date string) (TrainingBudgetEnt
 	// If both succeed, compare
 	if localErr == nil && remoteErr == nil {
 		if !reflect.DeepEqual(localEntry, remoteEntry) {
-			logging.Log("DUAL MODE: GetTrainingBudgetEntryByDate - Entry mismatch for date %s: local=%+v, remote=%+v", date, localEntry, remoteEntry)
+			logMismatch("GetTrainingBudgetEntryByDate", "Entry mismatch", map[string]any{"date": date, "local": localEntry, "remote": remoteEntry})
 		}
 		return localEntry, nil
 	}
@@ -502,6 +680,127 @@ func (d *DualLayer) GetTrainingBudgetEntryByDate(date string) (TrainingBudgetEnt
 	return TrainingBudgetEntry{}, fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
 }
 
+// GetExpenses reads from both sources and compares
+func (d *DualLayer) GetExpenses(year, month int) ([]Expense, error) {
+	localExpenses, localErr := d.local.GetExpenses(year, month)
+	remoteExpenses, remoteErr := d.remote.GetExpenses(year, month)
+
+	// If both succeed, compare
+	if localErr == nil && remoteErr == nil {
+		if len(localExpenses) != len(remoteExpenses) {
+			logMismatch("GetExpenses", "Count mismatch", map[string]any{"local": len(localExpenses), "remote": len(remoteExpenses)})
+		}
+		return localExpenses, nil
+	}
+
+	// If only one succeeds, log warning and return that one
+	if localErr != nil && remoteErr == nil {
+		logging.Log("DUAL MODE: Local DB failed, using remote: %v", localErr)
+		return remoteExpenses, nil
+	}
+	if localErr == nil && remoteErr != nil {
+		logging.Log("DUAL MODE: Remote API failed, using local: %v", remoteErr)
+		return localExpenses, nil
+	}
+
+	// Both failed
+	return nil, fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
+}
+
+// GetExpense reads from both sources and compares
+func (d *DualLayer) GetExpense(id int) (Expense, error) {
+	localExpense, localErr := d.local.GetExpense(id)
+	remoteExpense, remoteErr := d.remote.GetExpense(id)
+
+	// If both succeed, compare
+	if localErr == nil && remoteErr == nil {
+		if !reflect.DeepEqual(localExpense, remoteExpense) {
+			logging.Log("DUAL MODE: GetExpense - Mismatch for id %d: local=%+v, remote=%+v", id, localExpense, remoteExpense)
+		}
+		return localExpense, nil
+	}
+
+	// If only one succeeds, log warning and return that one
+	if localErr != nil && remoteErr == nil {
+		logging.Log("DUAL MODE: Local DB failed, using remote: %v", localErr)
+		return remoteExpense, nil
+	}
+	if localErr == nil && remoteErr != nil {
+		logging.Log("DUAL MODE: Remote API failed, using local: %v", remoteErr)
+		return localExpense, nil
+	}
+
+	// Both failed
+	return Expense{}, fmt.Errorf("both local and remote failed: local=%v, remote=%v", localErr, remoteErr)
+}
+
+// AddExpense writes to both sources
+func (d *DualLayer) AddExpense(expense Expense) (int, error) {
+	localId, localErr := d.local.AddExpense(expense)
+	remoteId, remoteErr := d.remote.AddExpense(expense)
+
+	if localErr != nil {
+		logging.Log("DUAL MODE: Local DB write failed: %v", localErr)
+	}
+	if remoteErr != nil {
+		logging.Log("DUAL MODE: Remote API write failed: %v", remoteErr)
+	}
+
+	if localErr != nil && remoteErr != nil {
+		return 0, fmt.Errorf("both local and remote writes failed: local=%v, remote=%v", localErr, remoteErr)
+	}
+
+	// Return local ID if successful, otherwise remote ID
+	if localErr == nil {
+		return localId, nil
+	}
+	return remoteId, remoteErr
+}
+
+// UpdateExpense writes to both sources
+func (d *DualLayer) UpdateExpense(expense Expense) error {
+	localErr := d.local.UpdateExpense(expense)
+	remoteErr := d.remote.UpdateExpense(expense)
+
+	if localErr != nil {
+		logging.Log("DUAL MODE: Local DB update failed: %v", localErr)
+	}
+	if remoteErr != nil {
+		logging.Log("DUAL MODE: Remote API update failed: %v", remoteErr)
+	}
+
+	if localErr != nil && remoteErr != nil {
+		return fmt.Errorf("both local and remote updates failed: local=%v, remote=%v", localErr, remoteErr)
+	}
+
+	if localErr != nil {
+		return fmt.Errorf("local update failed: %w", localErr)
+	}
+	return remoteErr
+}
+
+// DeleteExpense deletes from both sources
+func (d *DualLayer) DeleteExpense(id int) error {
+	localErr := d.local.DeleteExpense(id)
+	remoteErr := d.remote.DeleteExpense(id)
+
+	if localErr != nil {
+		logging.Log("DUAL MODE: Local DB delete failed: %v", localErr)
+	}
+	if remoteErr != nil {
+		logging.Log("DUAL MODE: Remote API delete failed: %v", remoteErr)
+	}
+
+	if localErr != nil && remoteErr != nil {
+		return fmt.Errorf("both local and remote deletes failed: local=%v, remote=%v", localErr, remoteErr)
+	}
+
+	if localErr != nil {
+		return fmt.Errorf("local delete failed: %w", localErr)
+	}
+	return remoteErr
+}
+
 // Ping checks both sources
 func (d *DualLayer) Ping() error {
 	localErr := d.local.Ping()
@@ -526,13 +825,13 @@ func (d *DualLayer) Ping() error {
 // compareClients compares two slices of clients
 func (d *DualLayer) compareClients(local, remote []Client, operation string) {
 	if len(local) != len(remote) {
-		logging.Log("DUAL MODE: %s - Client count mismatch: local=%d, remote=%d", operation, len(local), len(remote))
+		logMismatch(operation, "Client count mismatch", map[string]any{"local": len(local), "remote": len(remote)})
 		return
 	}
 
 	for i := range local {
 		if !reflect.DeepEqual(local[i], remote[i]) {
-			logging.Log("DUAL MODE: %s - Client mismatch at index %d: local=%+v, remote=%+v", operation, i, local[i], remote[i])
+			logMismatch(operation, "Client mismatch", map[string]any{"index": i, "local": local[i], "remote": remote[i]})
 		}
 	}
 }
@@ -540,13 +839,13 @@ func (d *DualLayer) compareClients(local, remote []Client, operation string) {
 // compareClientRates compares two slices of client rates
 func (d *DualLayer) compareClientRates(local, remote []ClientRate, operation string) {
 	if len(local) != len(remote) {
-		logging.Log("DUAL MODE: %s - Client rate count mismatch: local=%d, remote=%d", operation, len(local), len(remote))
+		logMismatch(operation, "Client rate count mismatch", map[string]any{"local": len(local), "remote": len(remote)})
 		return
 	}
 
 	for i := range local {
 		if !reflect.DeepEqual(local[i], remote[i]) {
-			logging.Log("DUAL MODE: %s - Client rate mismatch at index %d: local=%+v, remote=%+v", operation, i, local[i], remote[i])
+			logMismatch(operation, "Client rate mismatch", map[string]any{"index": i, "local": local[i], "remote": remote[i]})
 		}
 	}
 }
@@ -601,7 +900,7 @@ func (d *DualLayer) GetClientById(id int) (Client, error) {
 
 	if localErr == nil && remoteErr == nil {
 		if !reflect.DeepEqual(localClient, remoteClient) {
-			logging.Log("DUAL MODE: GetClientById - Client mismatch for id %d: local=%+v, remote=%+v", id, localClient, remoteClient)
+			logMismatch("GetClientById", "Client mismatch", map[string]any{"id": id, "local": localClient, "remote": remoteClient})
 		}
 		return localClient, nil
 	}
@@ -624,7 +923,7 @@ func (d *DualLayer) GetClientByName(name string) (Client, error) {
 
 	if localErr == nil && remoteErr == nil {
 		if !reflect.DeepEqual(localClient, remoteClient) {
-			logging.Log("DUAL MODE: GetClientByName - Client mismatch for name %s: local=%+v, remote=%+v", name, localClient, remoteClient)
+			logMismatch("GetClientByName", "Client mismatch", map[string]any{"name": name, "local": localClient, "remote": remoteClient})
 		}
 		return localClient, nil
 	}
@@ -755,7 +1054,7 @@ func (d *DualLayer) GetClientRateById(id int) (ClientRate, error) {
 
 	if localErr == nil && remoteErr == nil {
 		if !reflect.DeepEqual(localRate, remoteRate) {
-			logging.Log("DUAL MODE: GetClientRateById - Rate mismatch for id %d: local=%+v, remote=%+v", id, localRate, remoteRate)
+			logMismatch("GetClientRateById", "Rate mismatch", map[string]any{"id": id, "local": localRate, "remote": remoteRate})
 		}
 		return localRate, nil
 	}
@@ -841,7 +1140,7 @@ func (d *DualLayer) GetClientRateForDate(clientId int, date string) (ClientRate,
 
 	if localErr == nil && remoteErr == nil {
 		if !reflect.DeepEqual(localRate, remoteRate) {
-			logging.Log("DUAL MODE: GetClientRateForDate - Rate mismatch for client %d on %s: local=%+v, remote=%+v", clientId, date, localRate, remoteRate)
+			logMismatch("GetClientRateForDate", "Rate mismatch", map[string]any{"clientId": clientId, "date": date, "local": localRate, "remote": remoteRate})
 		}
 		return localRate, nil
 	}
@@ -864,7 +1163,7 @@ func (d *DualLayer) GetClientRateByName(clientName string, date string) (float64
 
 	if localErr == nil && remoteErr == nil {
 		if localRate != remoteRate {
-			logging.Log("DUAL MODE: GetClientRateByName - Rate mismatch for %s on %s: local=%.2f, remote=%.2f", clientName, date, localRate, remoteRate)
+			logMismatch("GetClientRateByName", "Rate mismatch", map[string]any{"client": clientName, "date": date, "local": localRate, "remote": remoteRate})
 		}
 		return localRate, nil
 	}
@@ -890,8 +1189,8 @@ func (d *DualLayer) CalculateEarningsForYear(year int) (EarningsOverview, error)
 	if localErr == nil && remoteErr == nil {
 		// Compare totals
 		if localEarnings.TotalHours != remoteEarnings.TotalHours || localEarnings.TotalEarnings != remoteEarnings.TotalEarnings {
-			logging.Log("DUAL MODE: CalculateEarningsForYear - Earnings mismatch for year %d: local(hours=%d, earnings=%.2f), remote(hours=%d, earnings=%.2f)",
-				year, localEarnings.TotalHours, localEarnings.TotalEarnings, remoteEarnings.TotalHours, remoteEarnings.TotalEarnings)
+			logEarningsMismatch("CalculateEarningsForYear", map[string]any{"year": year},
+				localEarnings.TotalHours, localEarnings.TotalEarnings, remoteEarnings.TotalHours, remoteEarnings.TotalEarnings)
 		}
 		return localEarnings, nil
 	}
@@ -915,8 +1214,8 @@ func (d *DualLayer) CalculateEarningsSummaryForYear(year int) (EarningsOverview,
 	if localErr == nil && remoteErr == nil {
 		// Compare totals
 		if localEarnings.TotalHours != remoteEarnings.TotalHours || localEarnings.TotalEarnings != remoteEarnings.TotalEarnings {
-			logging.Log("DUAL MODE: CalculateEarningsSummaryForYear - Earnings mismatch for year %d: local(hours=%d, earnings=%.2f), remote(hours=%d, earnings=%.2f)",
-				year, localEarnings.TotalHours, localEarnings.TotalEarnings, remoteEarnings.TotalHours, remoteEarnings.TotalEarnings)
+			logEarningsMismatch("CalculateEarningsSummaryForYear", map[string]any{"year": year},
+				localEarnings.TotalHours, localEarnings.TotalEarnings, remoteEarnings.TotalHours, remoteEarnings.TotalEarnings)
 		}
 		return localEarnings, nil
 	}
@@ -940,8 +1239,8 @@ func (d *DualLayer) CalculateEarningsForMonth(year int, month int) (EarningsOver
 	if localErr == nil && remoteErr == nil {
 		// Compare totals
 		if localEarnings.TotalHours != remoteEarnings.TotalHours || localEarnings.TotalEarnings != remoteEarnings.TotalEarnings {
-			logging.Log("DUAL MODE: CalculateEarningsForMonth - Earnings mismatch for %d/%d: local(hours=%d, earnings=%.2f), remote(hours=%d, earnings=%.2f)",
-				year, month, localEarnings.TotalHours, localEarnings.TotalEarnings, remoteEarnings.TotalHours, remoteEarnings.TotalEarnings)
+			logEarningsMismatch("CalculateEarningsForMonth", map[string]any{"year": year, "month": month},
+				localEarnings.TotalHours, localEarnings.TotalEarnings, remoteEarnings.TotalHours, remoteEarnings.TotalEarnings)
 		}
 		return localEarnings, nil
 	}
@@ -964,7 +1263,7 @@ func (d *DualLayer) GetClientWithRates(clientId int) (ClientWithRates, error) {
 
 	if localErr == nil && remoteErr == nil {
 		if !reflect.DeepEqual(localData, remoteData) {
-			logging.Log("DUAL MODE: GetClientWithRates - Data mismatch for client %d", clientId)
+			logMismatch("GetClientWithRates", "Data mismatch", map[string]any{"clientId": clientId})
 		}
 		return localData, nil
 	}
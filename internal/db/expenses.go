@@ -0,0 +1,188 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"timesheet/internal/config"
+)
+
+// Expense is a cost incurred for a client on a day, such as travel or
+// materials. Billable expenses are passed on to the client and count
+// towards the earnings totals.
+type Expense struct {
+	Id          int
+	Date        string // YYYY-MM-DD format
+	Client_name string
+	Description string
+	Amount      float64 // In the client's currency
+	Billable    bool
+}
+
+// expenseSelectColumns is the column list scanned by scanExpense
+const expenseSelectColumns = "id, date, client_name, description, amount, billable"
+
+// ValidateExpense checks an expense before it's stored
+func ValidateExpense(expense Expense) error {
+	if _, err := time.Parse("2006-01-02", expense.Date); err != nil {
+		return fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", expense.Date)
+	}
+	if strings.TrimSpace(expense.Client_name) == "" {
+		return errors.New("client name is required")
+	}
+	if strings.TrimSpace(expense.Description) == "" {
+		return errors.New("description is required")
+	}
+	if expense.Amount < 0 {
+		return errors.New("amount can't be negative")
+	}
+	return nil
+}
+
+// expenseDateBounds returns the [start, end) dates of a month, or of the
+// whole year when month is 0
+func expenseDateBounds(year, month int) (string, string) {
+	if month == 0 {
+		return yearDateBounds(year)
+	}
+	start := time.Date(year, time.Month(month), 1, 0, 0, 0, 0, time.UTC)
+	return start.Format("2006-01-02"), start.AddDate(0, 1, 0).Format("2006-01-02")
+}
+
+// scanExpense reads one row selected with expenseSelectColumns
+func scanExpense(row interface{ Scan(...any) error }) (Expense, error) {
+	var expense Expense
+	err := row.Scan(
+		&expense.Id,
+		&expense.Date,
+		&expense.Client_name,
+		&expense.Description,
+		&expense.Amount,
+		&expense.Billable,
+	)
+	return expense, err
+}
+
+// scanExpenses reads all rows selected with expenseSelectColumns
+func scanExpenses(rows *sql.Rows) ([]Expense, error) {
+	defer rows.Close()
+
+	expenses := []Expense{}
+	for rows.Next() {
+		expense, err := scanExpense(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan expense: %w", err)
+		}
+		expenses = append(expenses, expense)
+	}
+	return expenses, rows.Err()
+}
+
+// GetExpenses retrieves the expenses of a month, or of the whole year when
+// month is 0, oldest first
+func GetExpenses(year, month int) ([]Expense, error) {
+	start, end := expenseDateBounds(year, month)
+	rows, err := db.Query(`SELECT `+expenseSelectColumns+` FROM expenses
+		WHERE date >= ? AND date < ?
+		ORDER BY date ASC, id ASC`, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expenses: %w", err)
+	}
+	return scanExpenses(rows)
+}
+
+// GetExpense retrieves a single expense by ID
+func GetExpense(id int) (Expense, error) {
+	row := db.QueryRow(`SELECT `+expenseSelectColumns+` FROM expenses WHERE id = ?`, id)
+	return scanExpense(row)
+}
+
+// AddExpense adds a new expense and returns its ID
+func AddExpense(expense Expense) (int, error) {
+	if err := ValidateExpense(expense); err != nil {
+		return 0, err
+	}
+	now := NowTimestamp()
+	result, err := db.Exec(`INSERT INTO expenses (date, client_name, description, amount, billable, created_at, updated_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?)`,
+		expense.Date, expense.Client_name, expense.Description, expense.Amount, expense.Billable, now, now)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add expense: %w", err)
+	}
+	id, err := result.LastInsertId()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get expense ID: %w", err)
+	}
+	return int(id), nil
+}
+
+// UpdateExpense updates an existing expense
+func UpdateExpense(expense Expense) error {
+	if err := ValidateExpense(expense); err != nil {
+		return err
+	}
+	result, err := db.Exec(`UPDATE expenses
+              SET date = ?, client_name = ?, description = ?, amount = ?, billable = ?, updated_at = ?
+              WHERE id = ?`,
+		expense.Date, expense.Client_name, expense.Description, expense.Amount, expense.Billable, NowTimestamp(), expense.Id)
+	if err != nil {
+		return fmt.Errorf("failed to update expense: %w", err)
+	}
+	return requireAffected(result, expense.Id)
+}
+
+// DeleteExpense removes an expense
+func DeleteExpense(id int) error {
+	result, err := db.Exec(`DELETE FROM expenses WHERE id = ?`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete expense: %w", err)
+	}
+	return requireAffected(result, id)
+}
+
+// requireAffected turns an update or delete that matched no expense into
+// sql.ErrNoRows
+func requireAffected(result sql.Result, id int) error {
+	n, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to check affected rows: %w", err)
+	}
+	if n == 0 {
+		return fmt.Errorf("expense %d: %w", id, sql.ErrNoRows)
+	}
+	return nil
+}
+
+// applyExpenses adds the billable expenses to an overview whose currencies
+// have been summarized. currencies maps client names to their currency.
+func applyExpenses(overview *EarningsOverview, expenses []Expense, currencies map[string]string) {
+	addBillableExpenses(overview, expenses, currencies, config.GetExchangeRates())
+}
+
+// addBillableExpenses converts the billable expenses to the overview's
+// currency and adds them to BillableExpenses and TotalInvoiced. Expenses in
+// a currency without an exchange rate are reported in Unconverted.
+func addBillableExpenses(overview *EarningsOverview, expenses []Expense, currencies map[string]string, rates map[string]float64) {
+	overview.BillableExpenses = 0
+	for _, expense := range expenses {
+		if !expense.Billable {
+			continue
+		}
+		currency := currencies[expense.Client_name]
+		rate := 1.0
+		if currency != "" && currency != overview.Currency {
+			var ok bool
+			if rate, ok = rates[currency]; !ok {
+				if !slices.Contains(overview.Unconverted, currency) {
+					overview.Unconverted = append(overview.Unconverted, currency)
+				}
+				continue
+			}
+		}
+		overview.BillableExpenses += expense.Amount * rate
+	}
+	overview.TotalInvoiced = overview.TotalEarnings + overview.BillableExpenses
+}
@@ -0,0 +1,106 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestExpenseCRUD(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	id, err := AddExpense(Expense{Date: "2024-03-04", Client_name: "Acme", Description: "Train ticket", Amount: 42.5, Billable: true})
+	if err != nil {
+		t.Fatalf("AddExpense failed: %v", err)
+	}
+	if _, err := AddExpense(Expense{Date: "2024-04-01", Client_name: "Acme", Description: "Laptop stand", Amount: 30}); err != nil {
+		t.Fatalf("AddExpense failed: %v", err)
+	}
+
+	march, err := GetExpenses(2024, 3)
+	if err != nil {
+		t.Fatalf("GetExpenses failed: %v", err)
+	}
+	if len(march) != 1 || march[0].Id != id || march[0].Amount != 42.5 || !march[0].Billable {
+		t.Errorf("Unexpected March expenses: %+v", march)
+	}
+	year, err := GetExpenses(2024, 0)
+	if err != nil {
+		t.Fatalf("GetExpenses failed: %v", err)
+	}
+	if len(year) != 2 || year[1].Billable {
+		t.Errorf("Unexpected 2024 expenses: %+v", year)
+	}
+
+	expense := march[0]
+	expense.Amount = 50
+	if err := UpdateExpense(expense); err != nil {
+		t.Fatalf("UpdateExpense failed: %v", err)
+	}
+	if got, err := GetExpense(id); err != nil || got.Amount != 50 {
+		t.Errorf("Expected the updated amount, got %+v (%v)", got, err)
+	}
+
+	if err := DeleteExpense(id); err != nil {
+		t.Fatalf("DeleteExpense failed: %v", err)
+	}
+	if err := DeleteExpense(id); !errors.Is(err, sql.ErrNoRows) {
+		t.Errorf("Expected sql.ErrNoRows deleting a missing expense, got %v", err)
+	}
+
+	if _, err := AddExpense(Expense{Date: "2024-03-04", Description: "Taxi", Amount: 10}); err == nil {
+		t.Error("Expected an expense without a client to be rejected")
+	}
+	if _, err := AddExpense(Expense{Date: "2024-03-04", Client_name: "Acme", Description: "Refund", Amount: -10}); err == nil {
+		t.Error("Expected a negative amount to be rejected")
+	}
+}
+
+func TestEarningsIncludeBillableExpenses(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	clientId, _ := AddClient(Client{Name: "Acme", IsActive: true})
+	AddClientRate(ClientRate{ClientId: clientId, HourlyRate: 100, EffectiveDate: "2024-01-01"})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-03-04", Client_name: "Acme", Client_hours: 8})
+	AddExpense(Expense{Date: "2024-03-04", Client_name: "Acme", Description: "Train ticket", Amount: 40, Billable: true})
+	AddExpense(Expense{Date: "2024-03-05", Client_name: "Acme", Description: "Lunch", Amount: 15})
+
+	overview, err := CalculateEarningsForMonth(2024, 3)
+	if err != nil {
+		t.Fatalf("CalculateEarningsForMonth failed: %v", err)
+	}
+	if overview.TotalEarnings != 800 || overview.BillableExpenses != 40 || overview.TotalInvoiced != 840 {
+		t.Errorf("Expected 800 earnings + 40 expenses = 840, got %.2f + %.2f = %.2f",
+			overview.TotalEarnings, overview.BillableExpenses, overview.TotalInvoiced)
+	}
+
+	// Months without expenses are unaffected
+	overview, err = CalculateEarningsForMonth(2024, 4)
+	if err != nil {
+		t.Fatalf("CalculateEarningsForMonth failed: %v", err)
+	}
+	if overview.BillableExpenses != 0 || overview.TotalInvoiced != overview.TotalEarnings {
+		t.Errorf("Expected no expenses in April, got %+v", overview)
+	}
+}
+
+func TestAddBillableExpensesConvertsCurrencies(t *testing.T) {
+	overview := EarningsOverview{Currency: "EUR", TotalEarnings: 100}
+	expenses := []Expense{
+		{Client_name: "Acme", Amount: 10, Billable: true},
+		{Client_name: "Globex", Amount: 20, Billable: true},
+		{Client_name: "Initech", Amount: 5, Billable: true},
+	}
+	currencies := map[string]string{"Globex": "USD", "Initech": "GBP"}
+
+	addBillableExpenses(&overview, expenses, currencies, map[string]float64{"USD": 0.5})
+
+	if overview.BillableExpenses != 20 || overview.TotalInvoiced != 120 {
+		t.Errorf("Expected 10 + 20 USD at 0.5 = 20 in expenses, got %.2f (total %.2f)", overview.BillableExpenses, overview.TotalInvoiced)
+	}
+	if len(overview.Unconverted) != 1 || overview.Unconverted[0] != "GBP" {
+		t.Errorf("Expected GBP to be unconverted, got %v", overview.Unconverted)
+	}
+}
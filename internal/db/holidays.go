@@ -0,0 +1,263 @@
+package db
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"time"
+	"timesheet/internal/workschedule"
+)
+
+// Public holidays are imported into the local holidays table from an
+// iCal/ICS file or a built-in country calendar. Like statements the table is
+// local only; the holiday hours it fills are regular timesheet entries and
+// sync as usual.
+
+// ErrHolidaysNeedSQLite is returned when holidays are imported or read
+// without the local SQLite database, e.g. with --db-type postgres
+var ErrHolidaysNeedSQLite = errors.New("holiday import needs the local SQLite database; run without --db-type postgres")
+
+// Holiday is one public holiday
+type Holiday struct {
+	Date string `json:"date"`
+	Name string `json:"name"`
+}
+
+// ParseHolidaysICS reads the all-day events of an iCal/ICS calendar. Each
+// event's DTSTART is its date and its SUMMARY its name. Recurrence rules
+// aren't expanded, so a calendar needs an event for every year it covers.
+func ParseHolidaysICS(r io.Reader) ([]Holiday, error) {
+	var lines []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		// A line starting with a space or tab continues the previous one
+		if len(lines) > 0 && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read calendar: %w", err)
+	}
+
+	var holidays []Holiday
+	var current *Holiday
+	for i, line := range lines {
+		name, value, ok := strings.Cut(line, ":")
+		if !ok {
+			continue
+		}
+		// Drop parameters such as DTSTART;VALUE=DATE
+		name, _, _ = strings.Cut(strings.ToUpper(name), ";")
+		switch {
+		case name == "BEGIN" && strings.EqualFold(value, "VEVENT"):
+			current = &Holiday{}
+		case name == "END" && strings.EqualFold(value, "VEVENT") && current != nil:
+			if current.Date == "" {
+				return nil, fmt.Errorf("line %d: event %q has no DTSTART", i+1, current.Name)
+			}
+			holidays = append(holidays, *current)
+			current = nil
+		case name == "DTSTART" && current != nil:
+			if len(value) < 8 {
+				return nil, fmt.Errorf("line %d: invalid DTSTART %q", i+1, value)
+			}
+			date, err := time.Parse("20060102", value[:8])
+			if err != nil {
+				return nil, fmt.Errorf("line %d: invalid DTSTART %q", i+1, value)
+			}
+			current.Date = date.Format("2006-01-02")
+		case name == "SUMMARY" && current != nil:
+			current.Name = unescapeICSText(value)
+		}
+	}
+	return holidays, nil
+}
+
+// unescapeICSText undoes the escaping of an iCal TEXT value
+func unescapeICSText(s string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(s)
+}
+
+// BuiltinHolidays returns the public holidays of a country in a year. Only
+// "nl" (the Netherlands) is built in; for anything else use an ICS file.
+func BuiltinHolidays(country string, year int) ([]Holiday, error) {
+	switch strings.ToLower(strings.TrimSpace(country)) {
+	case "nl":
+		easter := easterSunday(year)
+		kingsDay := time.Date(year, time.April, 27, 0, 0, 0, 0, time.UTC)
+		if kingsDay.Weekday() == time.Sunday {
+			kingsDay = kingsDay.AddDate(0, 0, -1)
+		}
+		days := []struct {
+			date time.Time
+			name string
+		}{
+			{time.Date(year, time.January, 1, 0, 0, 0, 0, time.UTC), "Nieuwjaarsdag"},
+			{easter, "Eerste Paasdag"},
+			{easter.AddDate(0, 0, 1), "Tweede Paasdag"},
+			{kingsDay, "Koningsdag"},
+			{time.Date(year, time.May, 5, 0, 0, 0, 0, time.UTC), "Bevrijdingsdag"},
+			{easter.AddDate(0, 0, 39), "Hemelvaartsdag"},
+			{easter.AddDate(0, 0, 49), "Eerste Pinksterdag"},
+			{easter.AddDate(0, 0, 50), "Tweede Pinksterdag"},
+			{time.Date(year, time.December, 25, 0, 0, 0, 0, time.UTC), "Eerste Kerstdag"},
+			{time.Date(year, time.December, 26, 0, 0, 0, 0, time.UTC), "Tweede Kerstdag"},
+		}
+		holidays := make([]Holiday, len(days))
+		for i, day := range days {
+			holidays[i] = Holiday{Date: day.date.Format("2006-01-02"), Name: day.name}
+		}
+		sort.Slice(holidays, func(i, j int) bool { return holidays[i].Date < holidays[j].Date })
+		return holidays, nil
+	default:
+		return nil, fmt.Errorf("no built-in holidays for %q (supported: nl)", country)
+	}
+}
+
+// easterSunday returns the date of Easter Sunday in the Gregorian calendar
+// (the anonymous Gregorian algorithm)
+func easterSunday(year int) time.Time {
+	a := year % 19
+	b, c := year/100, year%100
+	d, e := b/4, b%4
+	f := (b + 8) / 25
+	g := (b - f + 1) / 3
+	h := (19*a + b - d - g + 15) % 30
+	i, k := c/4, c%4
+	l := (32 + 2*e + 2*i - h - k) % 7
+	m := (a + 11*h + 22*l) / 451
+	month := (h + l - 7*m + 114) / 31
+	day := (h+l-7*m+114)%31 + 1
+	return time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.UTC)
+}
+
+// LoadHolidaySource returns the holidays of a holidaySource setting: a
+// built-in country code such as "nl" (for the given year) or the path of an
+// ICS file (every event in it).
+func LoadHolidaySource(source string, year int) ([]Holiday, error) {
+	source = strings.TrimSpace(source)
+	if source == "" {
+		return nil, fmt.Errorf("no holiday source configured")
+	}
+	if !strings.HasSuffix(strings.ToLower(source), ".ics") {
+		return BuiltinHolidays(source, year)
+	}
+	file, err := os.Open(source)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open holiday calendar: %w", err)
+	}
+	defer file.Close()
+	return ParseHolidaysICS(file)
+}
+
+// ImportHolidays stores holidays in the holidays table. A date that's
+// already there gets the new name. It returns the number of holidays stored.
+func ImportHolidays(holidays []Holiday) (int, error) {
+	if db == nil {
+		return 0, ErrHolidaysNeedSQLite
+	}
+	tx, err := db.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	for _, holiday := range holidays {
+		if _, err := time.Parse("2006-01-02", holiday.Date); err != nil {
+			return 0, fmt.Errorf("invalid holiday date %q", holiday.Date)
+		}
+		_, err := tx.Exec(`INSERT INTO holidays (date, name) VALUES (?, ?)
+			ON CONFLICT(date) DO UPDATE SET name = excluded.name`, holiday.Date, holiday.Name)
+		if err != nil {
+			return 0, fmt.Errorf("failed to import holiday %s: %w", holiday.Date, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, fmt.Errorf("failed to commit holidays: %w", err)
+	}
+	return len(holidays), nil
+}
+
+// GetHolidaysForMonth returns the imported holidays of a month, by date
+func GetHolidaysForMonth(year int, month time.Month) ([]Holiday, error) {
+	if db == nil {
+		return nil, ErrHolidaysNeedSQLite
+	}
+	start, end := expenseDateBounds(year, int(month))
+	rows, err := db.Query(`SELECT date, name FROM holidays WHERE date >= ? AND date < ? ORDER BY date`, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query holidays: %w", err)
+	}
+	defer rows.Close()
+
+	var holidays []Holiday
+	for rows.Next() {
+		var holiday Holiday
+		if err := rows.Scan(&holiday.Date, &holiday.Name); err != nil {
+			return nil, fmt.Errorf("failed to scan holiday: %w", err)
+		}
+		holidays = append(holidays, holiday)
+	}
+	return holidays, rows.Err()
+}
+
+// PlanHolidayFill returns the holiday entries to record: one for every
+// holiday on a working day that has no hours logged, with the schedule's
+// hours for that weekday. Days with any hours are left alone, so client
+// work logged on a holiday is never overwritten.
+func PlanHolidayFill(holidays []Holiday, entries []TimesheetEntry, schedule workschedule.Schedule) []TimesheetEntry {
+	logged := map[string]bool{}
+	for _, entry := range entries {
+		date := entry.Date
+		if len(date) > 10 {
+			date = date[:10]
+		}
+		total := entry.Client_hours + entry.Vacation_hours + entry.Idle_hours +
+			entry.Training_hours + entry.Sick_hours + entry.Holiday_hours
+		if total > 0 {
+			logged[date] = true
+		}
+	}
+
+	var planned []TimesheetEntry
+	for _, holiday := range holidays {
+		day, err := time.Parse("2006-01-02", holiday.Date)
+		if err != nil || logged[holiday.Date] || schedule[day.Weekday()] <= 0 {
+			continue
+		}
+		planned = append(planned, TimesheetEntry{Date: holiday.Date, Holiday_hours: float64(schedule[day.Weekday()])})
+	}
+	return planned
+}
+
+// PlanHolidayFillForMonth loads a month's imported holidays and entries and
+// returns the holiday entries to record for it (see PlanHolidayFill)
+func PlanHolidayFillForMonth(dl DataLayer, year int, month time.Month, schedule workschedule.Schedule) ([]TimesheetEntry, error) {
+	holidays, err := GetHolidaysForMonth(year, month)
+	if err != nil {
+		return nil, err
+	}
+	entries, err := dl.GetAllTimesheetEntries(year, month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load timesheet entries: %w", err)
+	}
+	return PlanHolidayFill(holidays, entries, schedule), nil
+}
+
+// ApplyHolidaysForMonth records holiday hours on the month's imported
+// holidays (see PlanHolidayFillForMonth). Dates that already have an
+// all-zero entry are merged, like an idle fill.
+func ApplyHolidaysForMonth(dl DataLayer, year int, month time.Month, schedule workschedule.Schedule) (BulkResult, error) {
+	planned, err := PlanHolidayFillForMonth(dl, year, month, schedule)
+	if err != nil {
+		return BulkResult{}, err
+	}
+	return BulkSaveTimesheetEntries(dl, planned, OverwriteMerge)
+}
@@ -0,0 +1,146 @@
+package db
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+	"timesheet/internal/workschedule"
+)
+
+func TestParseHolidaysICS(t *testing.T) {
+	ics := strings.Join([]string{
+		"BEGIN:VCALENDAR",
+		"VERSION:2.0",
+		"BEGIN:VEVENT",
+		"DTSTART;VALUE=DATE:20240401",
+		"SUMMARY:Easter Monday",
+		"END:VEVENT",
+		"BEGIN:VEVENT",
+		"SUMMARY:Ascension Day\\, public",
+		"  holiday",
+		"DTSTART:20240509T000000Z",
+		"END:VEVENT",
+		"END:VCALENDAR",
+	}, "\r\n")
+
+	holidays, err := ParseHolidaysICS(strings.NewReader(ics))
+	if err != nil {
+		t.Fatalf("ParseHolidaysICS failed: %v", err)
+	}
+	want := []Holiday{
+		{Date: "2024-04-01", Name: "Easter Monday"},
+		{Date: "2024-05-09", Name: "Ascension Day, public holiday"},
+	}
+	if len(holidays) != len(want) {
+		t.Fatalf("Expected %d holidays, got %+v", len(want), holidays)
+	}
+	for i := range want {
+		if holidays[i] != want[i] {
+			t.Errorf("holiday %d = %+v, want %+v", i, holidays[i], want[i])
+		}
+	}
+
+	if _, err := ParseHolidaysICS(strings.NewReader("BEGIN:VEVENT\nDTSTART:2024\nEND:VEVENT\n")); err == nil {
+		t.Error("Expected an error for an invalid DTSTART")
+	}
+}
+
+func TestBuiltinHolidays(t *testing.T) {
+	holidays, err := BuiltinHolidays("NL", 2025)
+	if err != nil {
+		t.Fatalf("BuiltinHolidays failed: %v", err)
+	}
+	names := map[string]string{}
+	for _, holiday := range holidays {
+		names[holiday.Date] = holiday.Name
+	}
+	for date, name := range map[string]string{
+		"2025-04-20": "Eerste Paasdag",
+		"2025-04-21": "Tweede Paasdag",
+		"2025-04-26": "Koningsdag", // The 27th is a Sunday
+		"2025-05-29": "Hemelvaartsdag",
+		"2025-06-09": "Tweede Pinksterdag",
+		"2025-12-25": "Eerste Kerstdag",
+	} {
+		if names[date] != name {
+			t.Errorf("%s = %q, want %q", date, names[date], name)
+		}
+	}
+
+	if _, err := BuiltinHolidays("xx", 2025); err == nil {
+		t.Error("Expected an error for an unknown country")
+	}
+}
+
+func TestHolidaysWithoutSQLite(t *testing.T) {
+	previous := db
+	db = nil
+	defer func() { db = previous }()
+
+	if _, err := ImportHolidays([]Holiday{{Date: "2024-04-01", Name: "Easter Monday"}}); !errors.Is(err, ErrHolidaysNeedSQLite) {
+		t.Errorf("ImportHolidays: expected ErrHolidaysNeedSQLite, got %v", err)
+	}
+	if _, err := GetHolidaysForMonth(2024, time.April); !errors.Is(err, ErrHolidaysNeedSQLite) {
+		t.Errorf("GetHolidaysForMonth: expected ErrHolidaysNeedSQLite, got %v", err)
+	}
+}
+
+func TestApplyHolidaysForMonth(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	if _, err := ImportHolidays([]Holiday{
+		{Date: "2024-04-01", Name: "Easter Monday"},   // Empty day
+		{Date: "2024-04-02", Name: "Made up holiday"}, // All-zero entry
+		{Date: "2024-04-03", Name: "Worked holiday"},  // Client hours logged
+		{Date: "2024-04-06", Name: "Weekend holiday"}, // Saturday
+		{Date: "2024-05-09", Name: "Ascension Day"},   // Another month
+	}); err != nil {
+		t.Fatalf("ImportHolidays failed: %v", err)
+	}
+	for _, entry := range []TimesheetEntry{
+		{Date: "2024-04-02"},
+		{Date: "2024-04-03", Client_name: "Acme", Client_hours: 6},
+	} {
+		if err := AddTimesheetEntry(entry); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+	}
+
+	result, err := ApplyHolidaysForMonth(&LocalDBLayer{}, 2024, time.April, workschedule.Default())
+	if err != nil {
+		t.Fatalf("ApplyHolidaysForMonth failed: %v", err)
+	}
+	if len(result.Created) != 1 || result.Created[0] != "2024-04-01" {
+		t.Errorf("Expected 2024-04-01 to be created, got %v", result.Created)
+	}
+	if len(result.Merged) != 1 || result.Merged[0] != "2024-04-02" {
+		t.Errorf("Expected 2024-04-02 to be merged, got %v", result.Merged)
+	}
+
+	for date, want := range map[string]float64{"2024-04-01": 9, "2024-04-02": 9, "2024-04-03": 0} {
+		entry, err := firstEntry(GetTimesheetEntriesByDate(date))
+		if err != nil {
+			t.Fatalf("Failed to get entry for %s: %v", date, err)
+		}
+		if entry.Holiday_hours != want {
+			t.Errorf("%s: holiday hours = %g, want %g", date, entry.Holiday_hours, want)
+		}
+	}
+	if entry, _ := firstEntry(GetTimesheetEntriesByDate("2024-04-03")); entry.Client_hours != 6 {
+		t.Errorf("Expected the client hours on 2024-04-03 to stay 6, got %g", entry.Client_hours)
+	}
+	if _, err := firstEntry(GetTimesheetEntriesByDate("2024-04-06")); err == nil {
+		t.Error("Expected no entry on a weekend holiday")
+	}
+
+	// Applying it again has nothing left to fill
+	result, err = ApplyHolidaysForMonth(&LocalDBLayer{}, 2024, time.April, workschedule.Default())
+	if err != nil {
+		t.Fatalf("Second ApplyHolidaysForMonth failed: %v", err)
+	}
+	if len(result.Created)+len(result.Merged) != 0 {
+		t.Errorf("Expected nothing to fill on the second run, got %+v", result)
+	}
+}
@@ -0,0 +1,80 @@
+package db
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"timesheet/internal/workschedule"
+)
+
+// PlanIdleFill returns the idle entries to record for a month: one for every
+// working day up to and including through that has no hours logged. Days with
+// any hours (client work, vacation, a public holiday, …) are left alone, as are
+// imported holidays that haven't been filled yet and days the schedule has no
+// hours for. hours is the idle hours per day; 0 uses the schedule's hours for
+// that weekday.
+func PlanIdleFill(year int, month time.Month, entries []TimesheetEntry, holidays []Holiday, schedule workschedule.Schedule, hours int, through time.Time) []TimesheetEntry {
+	logged := map[string]bool{}
+	for _, holiday := range holidays {
+		logged[holiday.Date] = true
+	}
+	for _, entry := range entries {
+		date := entry.Date
+		if len(date) > 10 {
+			date = date[:10]
+		}
+		total := entry.Client_hours + entry.Vacation_hours + entry.Idle_hours +
+			entry.Training_hours + entry.Sick_hours + entry.Holiday_hours
+		if total > 0 {
+			logged[date] = true
+		}
+	}
+
+	var planned []TimesheetEntry
+	for _, date := range workschedule.MissingDays(year, month, logged, schedule, through) {
+		day, err := time.Parse("2006-01-02", date)
+		if err != nil {
+			continue
+		}
+		dayHours := hours
+		if dayHours <= 0 {
+			dayHours = schedule[day.Weekday()]
+		}
+		planned = append(planned, TimesheetEntry{Date: date, Idle_hours: float64(dayHours)})
+	}
+	return planned
+}
+
+// PlanIdleFillForMonth loads a month's entries and imported holidays and
+// returns the idle entries to record for it (see PlanIdleFill). Nothing is
+// written, so callers can show the plan and let the user drop days before
+// calling SaveIdleFill. Without the local SQLite database there are no
+// imported holidays to skip.
+func PlanIdleFillForMonth(dl DataLayer, year int, month time.Month, schedule workschedule.Schedule, hours int, through time.Time) ([]TimesheetEntry, error) {
+	entries, err := dl.GetAllTimesheetEntries(year, month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load timesheet entries: %w", err)
+	}
+	holidays, err := GetHolidaysForMonth(year, month)
+	if err != nil && !errors.Is(err, ErrHolidaysNeedSQLite) {
+		return nil, err
+	}
+	return PlanIdleFill(year, month, entries, holidays, schedule, hours, through), nil
+}
+
+// SaveIdleFill records planned idle entries. Dates that already have an
+// all-zero entry are merged, so nothing that was entered by hand is
+// overwritten.
+func SaveIdleFill(dl DataLayer, planned []TimesheetEntry) (BulkResult, error) {
+	return BulkSaveTimesheetEntries(dl, planned, OverwriteMerge)
+}
+
+// FillIdleDays records idle hours on the empty working days of a month (see
+// PlanIdleFillForMonth and SaveIdleFill).
+func FillIdleDays(dl DataLayer, year int, month time.Month, schedule workschedule.Schedule, hours int, through time.Time) (BulkResult, error) {
+	planned, err := PlanIdleFillForMonth(dl, year, month, schedule, hours, through)
+	if err != nil {
+		return BulkResult{}, err
+	}
+	return SaveIdleFill(dl, planned)
+}
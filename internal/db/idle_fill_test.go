@@ -0,0 +1,103 @@
+package db
+
+import (
+	"testing"
+	"time"
+	"timesheet/internal/workschedule"
+)
+
+func TestFillIdleDays(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	// June 2024 starts on a Saturday; the default schedule works Mon/Tue/Wed/Fri
+	existing := []TimesheetEntry{
+		{Date: "2024-06-03", Client_name: "Acme", Client_hours: 8}, // Worked
+		{Date: "2024-06-04"},                   // Entry without hours
+		{Date: "2024-06-05", Holiday_hours: 9}, // Public holiday
+	}
+	for _, entry := range existing {
+		if err := AddTimesheetEntry(entry); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+	}
+
+	through := time.Date(2024, 6, 7, 0, 0, 0, 0, time.Local)
+	result, err := FillIdleDays(&LocalDBLayer{}, 2024, time.June, workschedule.Default(), 0, through)
+	if err != nil {
+		t.Fatalf("FillIdleDays failed: %v", err)
+	}
+	if len(result.Created) != 1 || result.Created[0] != "2024-06-07" {
+		t.Errorf("Expected 2024-06-07 to be created, got %v", result.Created)
+	}
+	if len(result.Merged) != 1 || result.Merged[0] != "2024-06-04" {
+		t.Errorf("Expected 2024-06-04 to be merged, got %v", result.Merged)
+	}
+
+	for date, want := range map[string]float64{"2024-06-03": 0, "2024-06-04": 9, "2024-06-05": 0, "2024-06-07": 9} {
+		entry, err := firstEntry(GetTimesheetEntriesByDate(date))
+		if err != nil {
+			t.Fatalf("Failed to get entry for %s: %v", date, err)
+		}
+		if entry.Idle_hours != want {
+			t.Errorf("%s: idle hours = %g, want %g", date, entry.Idle_hours, want)
+		}
+	}
+	if _, err := firstEntry(GetTimesheetEntriesByDate("2024-06-06")); err == nil {
+		t.Errorf("Expected no entry on a non-working Thursday")
+	}
+
+	// Running it again has nothing left to fill
+	result, err = FillIdleDays(&LocalDBLayer{}, 2024, time.June, workschedule.Default(), 4, through)
+	if err != nil {
+		t.Fatalf("Second FillIdleDays failed: %v", err)
+	}
+	if len(result.Created)+len(result.Merged) != 0 {
+		t.Errorf("Expected nothing to fill on the second run, got %+v", result)
+	}
+}
+
+func TestPlanIdleFillConfiguredHours(t *testing.T) {
+	schedule := workschedule.Schedule{time.Monday: 8, time.Tuesday: 4}
+	through := time.Date(2024, 6, 30, 0, 0, 0, 0, time.Local)
+
+	planned := PlanIdleFill(2024, time.June, nil, nil, schedule, 6, through)
+	// June 2024 has four Mondays and four Tuesdays
+	if len(planned) != 8 {
+		t.Fatalf("Expected 8 planned days, got %d", len(planned))
+	}
+	for _, entry := range planned {
+		if entry.Idle_hours != 6 {
+			t.Errorf("%s: idle hours = %g, want the configured 6", entry.Date, entry.Idle_hours)
+		}
+	}
+}
+
+func TestFillIdleDaysSkipsImportedHolidays(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	// Whit Monday is imported but the holiday fill hasn't been run yet
+	if _, err := ImportHolidays([]Holiday{{Date: "2024-05-20", Name: "Whit Monday"}}); err != nil {
+		t.Fatalf("Failed to import holidays: %v", err)
+	}
+
+	through := time.Date(2024, 5, 21, 0, 0, 0, 0, time.Local)
+	if _, err := FillIdleDays(&LocalDBLayer{}, 2024, time.May, workschedule.Default(), 0, through); err != nil {
+		t.Fatalf("FillIdleDays failed: %v", err)
+	}
+	if _, err := firstEntry(GetTimesheetEntriesByDate("2024-05-20")); err == nil {
+		t.Errorf("Expected the imported holiday to be left for the holiday fill")
+	}
+	if _, err := firstEntry(GetTimesheetEntriesByDate("2024-05-21")); err != nil {
+		t.Errorf("Expected the working day after the holiday to be filled: %v", err)
+	}
+
+	result, err := ApplyHolidaysForMonth(&LocalDBLayer{}, 2024, time.May, workschedule.Default())
+	if err != nil {
+		t.Fatalf("ApplyHolidaysForMonth failed: %v", err)
+	}
+	if len(result.Created) != 1 || result.Created[0] != "2024-05-20" {
+		t.Errorf("Expected the holiday to still be filled, got %+v", result)
+	}
+}
@@ -91,7 +91,7 @@ func TestEndToEndClientRatesAndEarnings(t *testing.T) {
 		t.Errorf("Expected year 2024, got %d", overview2024.Year)
 	}
 	if overview2024.TotalHours != 16 {
-		t.Errorf("Expected 16 total hours in 2024, got %d", overview2024.TotalHours)
+		t.Errorf("Expected 16 total hours in 2024, got %g", overview2024.TotalHours)
 	}
 	// 8 hours * €100 + 8 hours * €120 = €800 + €960 = €1760
 	expectedEarnings2024 := 1760.00
@@ -151,7 +151,7 @@ func TestEndToEndClientRatesAndEarnings(t *testing.T) {
 		t.Errorf("Expected year 2025, got %d", overview2025.Year)
 	}
 	if overview2025.TotalHours != 8 {
-		t.Errorf("Expected 8 total hours in 2025, got %d", overview2025.TotalHours)
+		t.Errorf("Expected 8 total hours in 2025, got %g", overview2025.TotalHours)
 	}
 	// 8 hours * €150 = €1200
 	expectedEarnings2025 := 1200.00
@@ -169,7 +169,7 @@ func TestEndToEndClientRatesAndEarnings(t *testing.T) {
 		t.Errorf("Expected month 8, got %d", monthlyOverview.Month)
 	}
 	if monthlyOverview.TotalHours != 8 {
-		t.Errorf("Expected 8 hours in August, got %d", monthlyOverview.TotalHours)
+		t.Errorf("Expected 8 hours in August, got %g", monthlyOverview.TotalHours)
 	}
 	if monthlyOverview.TotalEarnings != 960.00 {
 		t.Errorf("Expected €960 in August, got €%.2f", monthlyOverview.TotalEarnings)
@@ -0,0 +1,103 @@
+package db
+
+import (
+	"math"
+	"timesheet/internal/config"
+)
+
+// invoiceUnitHours returns the length of an invoice unit in hours. Days are
+// dayHours long.
+func invoiceUnitHours(unit string, dayHours int) float64 {
+	switch unit {
+	case config.InvoiceUnitQuarterHour:
+		return 0.25
+	case config.InvoiceUnitHalfDay:
+		return float64(dayHours) / 2
+	case config.InvoiceUnitDay:
+		return float64(dayHours)
+	default:
+		return 1
+	}
+}
+
+// billedHours raises a day's client hours to the client's minimum and rounds
+// them up to whole invoice units, e.g. 6 hours bill as 8 with a unit of an
+// 8-hour day, and 2 hours bill as 4 with a minimum of 4. The logged hours are
+// reported unchanged; only the billed quantity is rounded.
+func billedHours(hours float64, unit string, dayHours int, minimum float64) float64 {
+	if hours <= 0 {
+		return 0
+	}
+	billed := math.Max(hours, minimum)
+	size := invoiceUnitHours(unit, dayHours)
+	if size <= 0 {
+		return billed
+	}
+	// The epsilon keeps float error from adding a unit to exact multiples
+	return math.Ceil(billed/size-1e-9) * size
+}
+
+// invoicing rounds client hours to each client's minimum and invoice unit
+// and prices the hours past the overtime threshold
+type invoicing struct {
+	units    config.InvoiceUnits
+	dayHours int
+	minimums map[string]float64 // clientName -> minimum billable hours
+
+	overtimeThreshold  float64 // Billed hours a day before overtime starts; 0 = no overtime
+	overtimeMultiplier float64
+}
+
+// loadInvoicing reads the invoice units, day length and overtime settings
+// from the config. minimums holds the clients' minimum billable hours by name.
+func loadInvoicing(minimums map[string]float64) invoicing {
+	threshold, multiplier := config.GetOvertime()
+	return invoicing{
+		units:              config.GetInvoiceUnits(),
+		dayHours:           config.GetStandardDailyHours(),
+		minimums:           minimums,
+		overtimeThreshold:  threshold,
+		overtimeMultiplier: multiplier,
+	}
+}
+
+// billed returns the hours a client is billed for a day with hours logged
+func (i invoicing) billed(clientName string, hours float64) float64 {
+	return billedHours(hours, i.units.For(clientName), i.dayHours, i.minimums[clientName])
+}
+
+// price bills a day's client hours at rate. The billed hours up to the
+// overtime threshold are regular; the rest earn rate times the overtime
+// multiplier. Date and Currency are left for the caller.
+func (i invoicing) price(clientName string, hours, rate float64) EarningsEntry {
+	billed := i.billed(clientName, hours)
+	var overtime float64
+	if i.overtimeThreshold > 0 && billed > i.overtimeThreshold {
+		overtime = billed - i.overtimeThreshold
+	}
+	entry := EarningsEntry{
+		ClientName:       clientName,
+		ClientHours:      hours,
+		BilledHours:      billed,
+		HourlyRate:       rate,
+		RegularHours:     billed - overtime,
+		OvertimeHours:    overtime,
+		RegularEarnings:  (billed - overtime) * rate,
+		OvertimeEarnings: overtime * rate * i.overtimeMultiplier,
+	}
+	entry.Earnings = entry.RegularEarnings + entry.OvertimeEarnings
+	return entry
+}
+
+// plus adds the hours and earnings of other to e, for totals over several
+// days at the same rate
+func (e EarningsEntry) plus(other EarningsEntry) EarningsEntry {
+	e.ClientHours += other.ClientHours
+	e.BilledHours += other.BilledHours
+	e.RegularHours += other.RegularHours
+	e.OvertimeHours += other.OvertimeHours
+	e.RegularEarnings += other.RegularEarnings
+	e.OvertimeEarnings += other.OvertimeEarnings
+	e.Earnings += other.Earnings
+	return e
+}
@@ -0,0 +1,189 @@
+package db
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+	"timesheet/internal/config"
+)
+
+func TestBilledHours(t *testing.T) {
+	tests := []struct {
+		hours   float64
+		unit    string
+		minimum float64
+		want    float64
+	}{
+		{6, config.InvoiceUnitHour, 0, 6},
+		{6, config.InvoiceUnitQuarterHour, 0, 6},
+		{3, config.InvoiceUnitHalfDay, 0, 4},
+		{4, config.InvoiceUnitHalfDay, 0, 4},
+		{5, config.InvoiceUnitHalfDay, 0, 8},
+		{1, config.InvoiceUnitDay, 0, 8},
+		{8, config.InvoiceUnitDay, 0, 8},
+		{10, config.InvoiceUnitDay, 0, 16},
+		{0, config.InvoiceUnitDay, 0, 0},
+		{2, config.InvoiceUnitHour, 4, 4},
+		{6, config.InvoiceUnitHour, 4, 6},
+		{1, config.InvoiceUnitHour, 2.5, 3},
+		{1, config.InvoiceUnitQuarterHour, 2.5, 2.5},
+		{5, config.InvoiceUnitHalfDay, 6, 8},
+		{0, config.InvoiceUnitHour, 4, 0},
+	}
+	for _, tt := range tests {
+		if got := billedHours(tt.hours, tt.unit, 8, tt.minimum); got != tt.want {
+			t.Errorf("billedHours(%v, %q, 8, %v) = %v, want %v", tt.hours, tt.unit, tt.minimum, got, tt.want)
+		}
+	}
+}
+
+func TestCalculateEarningsWithInvoiceUnits(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+	if err := config.SaveConfig(config.Config{
+		InvoiceUnit:        "half-day",
+		ClientInvoiceUnits: map[string]string{"Daily Client": "day"},
+	}); err != nil {
+		t.Fatalf("Failed to save test config: %v", err)
+	}
+
+	for _, name := range []string{"Daily Client", "Other Client"} {
+		id, _ := AddClient(Client{Name: name, IsActive: true})
+		AddClientRate(ClientRate{ClientId: id, HourlyRate: 100, EffectiveDate: "2024-01-01"})
+	}
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-02-05", Client_name: "Daily Client", Client_hours: 6})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-02-06", Client_name: "Other Client", Client_hours: 3})
+
+	earnings, err := CalculateEarningsForMonth(2024, int(time.February))
+	if err != nil {
+		t.Fatalf("CalculateEarningsForMonth failed: %v", err)
+	}
+
+	// Hours are reported as logged; only the billed quantity is rounded
+	if earnings.TotalHours != 9 {
+		t.Errorf("Expected 9 logged hours, got %g", earnings.TotalHours)
+	}
+	// 6h as a full day (8h) plus 3h as a half day (4h), at 100 an hour
+	if earnings.TotalEarnings != 1200 {
+		t.Errorf("Expected earnings of 1200, got %.2f", earnings.TotalEarnings)
+	}
+	for _, entry := range earnings.Entries {
+		if entry.ClientName == "Daily Client" && (entry.ClientHours != 6 || entry.BilledHours != 8) {
+			t.Errorf("Expected 6 hours billed as 8, got %+v", entry)
+		}
+	}
+
+	summary, err := CalculateEarningsSummaryForYear(2024)
+	if err != nil {
+		t.Fatalf("CalculateEarningsSummaryForYear failed: %v", err)
+	}
+	if summary.TotalEarnings != 1200 {
+		t.Errorf("Expected summary earnings of 1200, got %.2f", summary.TotalEarnings)
+	}
+}
+
+func TestCalculateEarningsWithMinimumBillableHours(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+
+	id, err := AddClient(Client{Name: "Retainer Client", IsActive: true, MinBillableHours: 4})
+	if err != nil {
+		t.Fatalf("AddClient failed: %v", err)
+	}
+	AddClientRate(ClientRate{ClientId: id, HourlyRate: 100, EffectiveDate: "2024-01-01"})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-02-05", Client_name: "Retainer Client", Client_hours: 2})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-02-06", Client_name: "Retainer Client", Client_hours: 6})
+
+	earnings, err := CalculateEarningsForMonth(2024, int(time.February))
+	if err != nil {
+		t.Fatalf("CalculateEarningsForMonth failed: %v", err)
+	}
+	if earnings.TotalHours != 8 {
+		t.Errorf("Expected 8 logged hours, got %g", earnings.TotalHours)
+	}
+	// The 2-hour day is billed as 4, the 6-hour day as logged
+	if earnings.TotalEarnings != 1000 {
+		t.Errorf("Expected earnings of 1000, got %.2f", earnings.TotalEarnings)
+	}
+
+	client, err := GetClientById(id)
+	if err != nil {
+		t.Fatalf("GetClientById failed: %v", err)
+	}
+	if client.MinBillableHours != 4 {
+		t.Errorf("Expected a minimum of 4 hours, got %v", client.MinBillableHours)
+	}
+
+	for _, minimum := range []float64{-1, MaxDailyHours + 1} {
+		client.MinBillableHours = minimum
+		if err := UpdateClient(client); err == nil {
+			t.Errorf("Expected an error for a minimum of %v hours", minimum)
+		}
+	}
+}
+
+func TestCalculateEarningsWithOvertime(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+	if err := config.SaveConfig(config.Config{OvertimeThreshold: 8, OvertimeMultiplier: 1.25}); err != nil {
+		t.Fatalf("Failed to save test config: %v", err)
+	}
+
+	id, _ := AddClient(Client{Name: "Acme", IsActive: true})
+	AddClientRate(ClientRate{ClientId: id, HourlyRate: 100, EffectiveDate: "2024-01-01"})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-03-04", Client_name: "Acme", Client_hours: 10})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-03-05", Client_name: "Acme", Client_hours: 6})
+
+	earnings, err := CalculateEarningsForMonth(2024, int(time.March))
+	if err != nil {
+		t.Fatalf("CalculateEarningsForMonth failed: %v", err)
+	}
+	// 8 + 6 regular hours at 100, 2 overtime hours at 125
+	if earnings.TotalEarnings != 1650 {
+		t.Errorf("Expected earnings of 1650, got %.2f", earnings.TotalEarnings)
+	}
+	for _, entry := range earnings.Entries {
+		switch entry.Date {
+		case "2024-03-04":
+			if entry.RegularHours != 8 || entry.OvertimeHours != 2 || entry.RegularEarnings != 800 || entry.OvertimeEarnings != 250 || entry.Earnings != 1050 {
+				t.Errorf("Expected 8 regular and 2 overtime hours, got %+v", entry)
+			}
+		case "2024-03-05":
+			if entry.RegularHours != 6 || entry.OvertimeHours != 0 || entry.Earnings != 600 {
+				t.Errorf("Expected 6 regular hours, got %+v", entry)
+			}
+		}
+	}
+
+	summary, err := CalculateEarningsSummaryForYear(2024)
+	if err != nil {
+		t.Fatalf("CalculateEarningsSummaryForYear failed: %v", err)
+	}
+	if len(summary.Entries) != 1 {
+		t.Fatalf("Expected one summary entry, got %d", len(summary.Entries))
+	}
+	if entry := summary.Entries[0]; entry.RegularHours != 14 || entry.OvertimeHours != 2 || entry.Earnings != 1650 {
+		t.Errorf("Unexpected summary entry %+v", entry)
+	}
+
+	// Without a threshold every hour is regular
+	if err := config.SaveConfig(config.Config{}); err != nil {
+		t.Fatalf("Failed to save test config: %v", err)
+	}
+	earnings, err = CalculateEarningsForMonth(2024, int(time.March))
+	if err != nil {
+		t.Fatalf("CalculateEarningsForMonth failed: %v", err)
+	}
+	if earnings.TotalEarnings != 1600 {
+		t.Errorf("Expected earnings of 1600 without overtime, got %.2f", earnings.TotalEarnings)
+	}
+}
@@ -9,18 +9,24 @@ import (
 type DataLayer interface {
 	// Timesheet operations
 	GetAllTimesheetEntries(year int, month time.Month) ([]TimesheetEntry, error)
-	GetTimesheetEntryByDate(date string) (TimesheetEntry, error)
+	GetTimesheetEntriesByDate(date string) ([]TimesheetEntry, error)
+	GetTimesheetEntriesInRange(from, to string, limit, offset int) ([]TimesheetEntry, int, error)
 	AddTimesheetEntry(entry TimesheetEntry) error
 	UpdateTimesheetEntry(entry TimesheetEntry) error
 	UpdateTimesheetEntryById(id string, data map[string]any) error
 	DeleteTimesheetEntryByDate(date string) error
 	DeleteTimesheetEntry(id string) error
+	DeleteTimesheetEntriesForMonth(year int, month time.Month) (int, error)
 	GetLastClientName() (string, error)
+	GetYearsWithData(includeTraining bool) ([]int, error)
 
 	// Training operations
 	GetTrainingEntriesForYear(year int) ([]TimesheetEntry, error)
 	GetVacationEntriesForYear(year int) ([]TimesheetEntry, error)
-	GetVacationHoursForYear(year int) (int, error)
+	GetVacationHoursForYear(year int) (float64, error)
+	GetSickHoursForYear(year int) (float64, error)
+	GetIdleHoursForYear(year int) (float64, error)
+	GetMonthlyTotals(year int, month time.Month) (HourTotals, error)
 
 	// Vacation carryover operations
 	GetVacationCarryoverForYear(year int) (VacationCarryover, error)
@@ -36,12 +42,20 @@ type DataLayer interface {
 
 	// Training budget operations
 	GetTrainingBudgetEntriesForYear(year int) ([]TrainingBudgetEntry, error)
+	GetTrainingBudgetSpentForYear(year int) (TrainingBudgetSpent, error)
 	AddTrainingBudgetEntry(entry TrainingBudgetEntry) error
 	UpdateTrainingBudgetEntry(entry TrainingBudgetEntry) error
 	DeleteTrainingBudgetEntry(id int) error
 	GetTrainingBudgetEntry(id int) (TrainingBudgetEntry, error)
 	GetTrainingBudgetEntryByDate(date string) (TrainingBudgetEntry, error)
 
+	// Expense operations
+	GetExpenses(year, month int) ([]Expense, error)
+	GetExpense(id int) (Expense, error)
+	AddExpense(expense Expense) (int, error)
+	UpdateExpense(expense Expense) error
+	DeleteExpense(id int) error
+
 	// Client operations
 	GetAllClients() ([]Client, error)
 	GetActiveClients() ([]Client, error)
@@ -78,8 +92,12 @@ func (l *LocalDBLayer) GetAllTimesheetEntries(year int, month time.Month) ([]Tim
 	return GetAllTimesheetEntries(year, month)
 }
 
-func (l *LocalDBLayer) GetTimesheetEntryByDate(date string) (TimesheetEntry, error) {
-	return GetTimesheetEntryByDate(date)
+func (l *LocalDBLayer) GetTimesheetEntriesByDate(date string) ([]TimesheetEntry, error) {
+	return GetTimesheetEntriesByDate(date)
+}
+
+func (l *LocalDBLayer) GetTimesheetEntriesInRange(from, to string, limit, offset int) ([]TimesheetEntry, int, error) {
+	return GetTimesheetEntriesInRange(from, to, limit, offset)
 }
 
 func (l *LocalDBLayer) AddTimesheetEntry(entry TimesheetEntry) error {
@@ -102,10 +120,18 @@ func (l *LocalDBLayer) DeleteTimesheetEntry(id string) error {
 	return DeleteTimesheetEntry(id)
 }
 
+func (l *LocalDBLayer) DeleteTimesheetEntriesForMonth(year int, month time.Month) (int, error) {
+	return DeleteTimesheetEntriesForMonth(year, month)
+}
+
 func (l *LocalDBLayer) GetLastClientName() (string, error) {
 	return GetLastClientName()
 }
 
+func (l *LocalDBLayer) GetYearsWithData(includeTraining bool) ([]int, error) {
+	return GetYearsWithData(includeTraining)
+}
+
 func (l *LocalDBLayer) GetTrainingEntriesForYear(year int) ([]TimesheetEntry, error) {
 	return GetTrainingEntriesForYear(year)
 }
@@ -114,10 +140,22 @@ func (l *LocalDBLayer) GetVacationEntriesForYear(year int) ([]TimesheetEntry, er
 	return GetVacationEntriesForYear(year)
 }
 
-func (l *LocalDBLayer) GetVacationHoursForYear(year int) (int, error) {
+func (l *LocalDBLayer) GetVacationHoursForYear(year int) (float64, error) {
 	return GetVacationHoursForYear(year)
 }
 
+func (l *LocalDBLayer) GetSickHoursForYear(year int) (float64, error) {
+	return GetSickHoursForYear(year)
+}
+
+func (l *LocalDBLayer) GetIdleHoursForYear(year int) (float64, error) {
+	return GetIdleHoursForYear(year)
+}
+
+func (l *LocalDBLayer) GetMonthlyTotals(year int, month time.Month) (HourTotals, error) {
+	return GetMonthlyTotals(year, month)
+}
+
 func (l *LocalDBLayer) GetVacationCarryoverForYear(year int) (VacationCarryover, error) {
 	return GetVacationCarryoverForYear(year)
 }
@@ -154,6 +192,10 @@ func (l *LocalDBLayer) GetTrainingBudgetEntriesForYear(year int) ([]TrainingBudg
 	return GetTrainingBudgetEntriesForYear(year)
 }
 
+func (l *LocalDBLayer) GetTrainingBudgetSpentForYear(year int) (TrainingBudgetSpent, error) {
+	return GetTrainingBudgetSpentForYear(year)
+}
+
 func (l *LocalDBLayer) AddTrainingBudgetEntry(entry TrainingBudgetEntry) error {
 	return AddTrainingBudgetEntry(entry)
 }
@@ -180,6 +222,26 @@ func (l *LocalDBLayer) Ping() error {
 
 // Client operations
 
+func (l *LocalDBLayer) GetExpenses(year, month int) ([]Expense, error) {
+	return GetExpenses(year, month)
+}
+
+func (l *LocalDBLayer) GetExpense(id int) (Expense, error) {
+	return GetExpense(id)
+}
+
+func (l *LocalDBLayer) AddExpense(expense Expense) (int, error) {
+	return AddExpense(expense)
+}
+
+func (l *LocalDBLayer) UpdateExpense(expense Expense) error {
+	return UpdateExpense(expense)
+}
+
+func (l *LocalDBLayer) DeleteExpense(id int) error {
+	return DeleteExpense(id)
+}
+
 func (l *LocalDBLayer) GetAllClients() ([]Client, error) {
 	return GetAllClients()
 }
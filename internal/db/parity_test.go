@@ -0,0 +1,195 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"reflect"
+	"strconv"
+	"testing"
+	"timesheet/internal/config"
+)
+
+// setupParityDBs points the Postgres layer at a second in-memory SQLite
+// database. The modernc.org/sqlite driver accepts $N placeholders, so the
+// Postgres queries run unchanged against it.
+func setupParityDBs(t testing.TB) {
+	t.Helper()
+	dbPath := setupTestDB(t)
+	t.Cleanup(func() { teardownTestDB(t, dbPath) })
+
+	remote, err := sql.Open("sqlite", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open stand-in Postgres database: %v", err)
+	}
+	remote.SetMaxOpenConns(1)
+	if err := ApplySQLiteSchema(remote); err != nil {
+		t.Fatalf("Failed to apply schema: %v", err)
+	}
+	prev := pgDB
+	pgDB = remote
+	t.Cleanup(func() {
+		pgDB = prev
+		remote.Close()
+	})
+
+	stmts := []string{
+		`INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours)
+			VALUES ('2024-05-01', 'Client A', 1, 2, 3, 4, 5, 6)`,
+		`INSERT INTO timesheet (date, client_name, client_hours) VALUES ('2024-05-02', 'Client A', 8)`,
+		`INSERT INTO timesheet (date, client_name, vacation_hours, sick_hours) VALUES ('2024-05-03', 'Client B', 4, 4)`,
+		`INSERT INTO timesheet (date, client_name, training_hours) VALUES ('2024-06-01', 'Client B', 8)`,
+	}
+	for _, conn := range []*sql.DB{db, remote} {
+		for _, stmt := range stmts {
+			if _, err := conn.Exec(stmt); err != nil {
+				t.Fatalf("Failed to seed timesheet: %v", err)
+			}
+		}
+	}
+}
+
+func TestTimesheetQueriesMatchAcrossBackends(t *testing.T) {
+	setupParityDBs(t)
+	local := &LocalDBLayer{}
+	postgres := &PostgresDBLayer{}
+
+	for _, date := range []string{"2024-05-01", "2024-05-02", "2024-05-03", "2024-06-01"} {
+		want, err := local.GetTimesheetEntriesByDate(date)
+		if err != nil {
+			t.Fatalf("SQLite GetTimesheetEntriesByDate(%s): %v", date, err)
+		}
+		got, err := postgres.GetTimesheetEntriesByDate(date)
+		if err != nil {
+			t.Fatalf("Postgres GetTimesheetEntriesByDate(%s): %v", date, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("GetTimesheetEntriesByDate(%s) differs: SQLite %+v, Postgres %+v", date, want, got)
+		}
+	}
+	if entry, _ := firstEntry(local.GetTimesheetEntriesByDate("2024-05-01")); entry.Total_hours != 21 {
+		t.Errorf("Expected total of 21 hours, got %g", entry.Total_hours)
+	}
+
+	queries := map[string]func(DataLayer) ([]TimesheetEntry, error){
+		"GetAllTimesheetEntries(month)": func(dl DataLayer) ([]TimesheetEntry, error) { return dl.GetAllTimesheetEntries(2024, 5) },
+		"GetAllTimesheetEntries(year)":  func(dl DataLayer) ([]TimesheetEntry, error) { return dl.GetAllTimesheetEntries(2024, 0) },
+		"GetVacationEntriesForYear":     func(dl DataLayer) ([]TimesheetEntry, error) { return dl.GetVacationEntriesForYear(2024) },
+		"GetTrainingEntriesForYear":     func(dl DataLayer) ([]TimesheetEntry, error) { return dl.GetTrainingEntriesForYear(2024) },
+		"GetTimesheetEntriesInRange": func(dl DataLayer) ([]TimesheetEntry, error) {
+			entries, _, err := dl.GetTimesheetEntriesInRange("2024-05-02", "2024-06-01", 2, 1)
+			return entries, err
+		},
+	}
+	for name, query := range queries {
+		want, err := query(local)
+		if err != nil {
+			t.Fatalf("SQLite %s: %v", name, err)
+		}
+		got, err := query(postgres)
+		if err != nil {
+			t.Fatalf("Postgres %s: %v", name, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s differs:\nSQLite   %+v\nPostgres %+v", name, want, got)
+		}
+	}
+
+	wantHours, err := local.GetVacationHoursForYear(2024)
+	if err != nil {
+		t.Fatalf("SQLite GetVacationHoursForYear: %v", err)
+	}
+	gotHours, err := postgres.GetVacationHoursForYear(2024)
+	if err != nil {
+		t.Fatalf("Postgres GetVacationHoursForYear: %v", err)
+	}
+	if gotHours != wantHours {
+		t.Errorf("GetVacationHoursForYear differs: SQLite %g, Postgres %g", wantHours, gotHours)
+	}
+}
+
+func TestDailyLimitCountsOtherClientBlocks(t *testing.T) {
+	setupParityDBs(t)
+	cleanup := setupTestConfig(t, 0)
+	defer cleanup()
+
+	// Client A has 8 hours on 2024-05-02 in both databases
+	for name, dl := range map[string]DataLayer{"SQLite": &LocalDBLayer{}, "Postgres": &PostgresDBLayer{}} {
+		err := dl.AddTimesheetEntry(TimesheetEntry{Date: "2024-05-02", Client_name: "Client B", Client_hours: 20})
+		if !errors.Is(err, ErrDailyLimitExceeded) {
+			t.Errorf("%s: expected a 28-hour day over two blocks to be rejected, got %v", name, err)
+		}
+		if err := dl.AddTimesheetEntry(TimesheetEntry{Date: "2024-05-02", Client_name: "Client B", Client_hours: 16}); err != nil {
+			t.Errorf("%s: expected a 24-hour day to be stored: %v", name, err)
+		}
+		// Client A's own block is replaced, not counted twice
+		if err := dl.UpdateTimesheetEntry(TimesheetEntry{Date: "2024-05-02", Client_name: "Client A", Client_hours: 8}); err != nil {
+			t.Errorf("%s: expected Client A's block to be updated: %v", name, err)
+		}
+		// So is a partial update by id
+		onDate, err := dl.GetTimesheetEntriesByDate("2024-05-02")
+		if err != nil {
+			t.Fatalf("%s: failed to get entries: %v", name, err)
+		}
+		clientA, _ := FindClientEntry(onDate, "Client A")
+		err = dl.UpdateTimesheetEntryById(strconv.Itoa(clientA.Id), map[string]any{"client_hours": 10.0})
+		if !errors.Is(err, ErrDailyLimitExceeded) {
+			t.Errorf("%s: expected a 26-hour day from an update by id to be rejected, got %v", name, err)
+		}
+	}
+
+	if err := config.SaveConfig(config.Config{OverLimitBehavior: config.OverLimitClamp}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	if err := AddTimesheetEntry(TimesheetEntry{Date: "2024-05-02", Client_name: "Client C", Client_hours: 4}); err != nil {
+		t.Fatalf("Expected the entry to be clamped: %v", err)
+	}
+	entries, err := GetTimesheetEntriesByDate("2024-05-02")
+	if err != nil {
+		t.Fatalf("Failed to get entries: %v", err)
+	}
+	if c, ok := FindClientEntry(entries, "Client C"); !ok || c.Client_hours != 0 {
+		t.Errorf("Expected Client C to be clamped to 0 hours on a full day, got %+v", entries)
+	}
+
+	violations := ValidateTimesheetDraft(TimesheetDraft{Date: "2024-05-02", Client_name: "Client C", Client_hours: 4}, 24)
+	if len(violations) != 0 {
+		t.Errorf("Expected no violations with clamp, got %v", violations)
+	}
+	if err := config.SaveConfig(config.Config{}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	violations = ValidateTimesheetDraft(TimesheetDraft{Date: "2024-05-02", Client_name: "Client C", Client_hours: 4}, 24)
+	if len(violations) != 1 || violations[0].Field != "Total_hours" {
+		t.Errorf("Expected a Total_hours violation, got %v", violations)
+	}
+}
+
+func TestBlocksOfADayAreOrderedByClient(t *testing.T) {
+	setupParityDBs(t)
+
+	for name, dl := range map[string]DataLayer{"SQLite": &LocalDBLayer{}, "Postgres": &PostgresDBLayer{}} {
+		for _, client := range []string{"Zeta", "Acme", "Mid"} {
+			if err := dl.AddTimesheetEntry(TimesheetEntry{Date: "2024-07-01", Client_name: client, Client_hours: 2}); err != nil {
+				t.Fatalf("%s: failed to add entry: %v", name, err)
+			}
+		}
+
+		byDate, err := dl.GetTimesheetEntriesByDate("2024-07-01")
+		if err != nil {
+			t.Fatalf("%s: GetTimesheetEntriesByDate failed: %v", name, err)
+		}
+		all, err := dl.GetAllTimesheetEntries(2024, 7)
+		if err != nil {
+			t.Fatalf("%s: GetAllTimesheetEntries failed: %v", name, err)
+		}
+		for _, entries := range [][]TimesheetEntry{byDate, all} {
+			var clients []string
+			for _, entry := range entries {
+				clients = append(clients, entry.Client_name)
+			}
+			if want := []string{"Acme", "Mid", "Zeta"}; !reflect.DeepEqual(clients, want) {
+				t.Errorf("%s: expected blocks in client order %v, got %v", name, want, clients)
+			}
+		}
+	}
+}
@@ -3,7 +3,6 @@ package db
 import (
 	"database/sql"
 	"fmt"
-	"strings"
 	"time"
 	"timesheet/internal/config"
 	"timesheet/internal/logging"
@@ -72,23 +71,23 @@ func (p *PostgresDBLayer) GetAllTimesheetEntries(year int, month time.Month) ([]
 	var args []any
 	argNum := 1
 
-	baseQuery := `SELECT id, date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours,
-		(client_hours + vacation_hours + idle_hours + training_hours + sick_hours + holiday_hours) AS total_hours
-		FROM timesheet`
+	baseQuery := "SELECT " + timesheetSelectColumns + " FROM timesheet"
 
 	if year != 0 && month != 0 {
 		startDate := time.Date(year, month, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
-		endDate := time.Date(year, month+1, 0, 23, 59, 59, 999999999, time.UTC).Format("2006-01-02")
-		query = baseQuery + fmt.Sprintf(" WHERE date BETWEEN $%d AND $%d", argNum, argNum+1)
+		endDate := time.Date(year, month+1, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
+		query = baseQuery + fmt.Sprintf(" WHERE date >= $%d AND date < $%d", argNum, argNum+1)
 		args = []any{startDate, endDate}
 	} else if year != 0 {
-		startDate := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC).Format("2006-01-02")
-		endDate := time.Date(year, 12, 31, 23, 59, 59, 999999999, time.UTC).Format("2006-01-02")
-		query = baseQuery + fmt.Sprintf(" WHERE date BETWEEN $%d AND $%d", argNum, argNum+1)
+		startDate, endDate := yearDateBounds(year)
+		query = baseQuery + fmt.Sprintf(" WHERE date >= $%d AND date < $%d", argNum, argNum+1)
 		args = []any{startDate, endDate}
 	} else {
 		query = baseQuery
 	}
+	// Stable ordering so dual-mode comparisons and the UI don't depend on
+	// storage order
+	query += " ORDER BY date ASC, client_name ASC"
 
 	rows, err := pgDB.Query(query, args...)
 	if err != nil {
@@ -110,7 +109,7 @@ func (p *PostgresDBLayer) GetAllTimesheetEntries(year int, month time.Month) ([]
 		var entry TimesheetEntry
 		if err := rows.Scan(&entry.Id, &entry.Date, &entry.Client_name, &entry.Client_hours,
 			&entry.Vacation_hours, &entry.Idle_hours, &entry.Training_hours, &entry.Sick_hours,
-			&entry.Holiday_hours, &entry.Total_hours); err != nil {
+			&entry.Holiday_hours, &entry.Total_hours, &entry.Note); err != nil {
 			return nil, err
 		}
 		entries = append(entries, entry)
@@ -119,44 +118,92 @@ func (p *PostgresDBLayer) GetAllTimesheetEntries(year int, month time.Month) ([]
 	return entries, rows.Err()
 }
 
-func (p *PostgresDBLayer) GetTimesheetEntryByDate(date string) (TimesheetEntry, error) {
-	query := `SELECT id, date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours,
-		(client_hours + vacation_hours + idle_hours + training_hours + holiday_hours + sick_hours) AS total_hours
-		FROM timesheet WHERE date = $1`
+func (p *PostgresDBLayer) GetTimesheetEntriesByDate(date string) ([]TimesheetEntry, error) {
+	query := "SELECT " + timesheetSelectColumns + " FROM timesheet WHERE date = $1 ORDER BY client_name"
 
-	var entry TimesheetEntry
-	err := pgDB.QueryRow(query, date).Scan(
-		&entry.Id, &entry.Date, &entry.Client_name, &entry.Client_hours,
-		&entry.Vacation_hours, &entry.Idle_hours, &entry.Training_hours,
-		&entry.Sick_hours, &entry.Holiday_hours, &entry.Total_hours,
-	)
+	rows, err := pgDB.Query(query, date)
 	if err != nil {
-		return TimesheetEntry{}, err
+		return nil, err
 	}
-	return entry, nil
+	defer rows.Close()
+
+	entries := []TimesheetEntry{}
+	for rows.Next() {
+		var entry TimesheetEntry
+		err := rows.Scan(
+			&entry.Id, &entry.Date, &entry.Client_name, &entry.Client_hours,
+			&entry.Vacation_hours, &entry.Idle_hours, &entry.Training_hours,
+			&entry.Sick_hours, &entry.Holiday_hours, &entry.Total_hours, &entry.Note,
+		)
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, rows.Err()
+}
+
+func (p *PostgresDBLayer) GetTimesheetEntriesInRange(from, to string, limit, offset int) ([]TimesheetEntry, int, error) {
+	return queryTimesheetRange(pgDB, func(n int) string { return fmt.Sprintf("$%d", n) }, from, to, limit, offset)
 }
 
+// AddTimesheetEntry stores entry, replacing the block already logged for
+// its client on its date if there is one
 func (p *PostgresDBLayer) AddTimesheetEntry(entry TimesheetEntry) error {
+	entry, err := prepareTimesheetEntry(pgDB, entry)
+	if err != nil {
+		return err
+	}
+
 	now := NowTimestamp()
-	query := `INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)`
-	_, err := pgDB.Exec(query,
+	query := `INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, note, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		` + timesheetUpsertConflict
+	_, err = pgDB.Exec(query,
 		entry.Date, entry.Client_name, entry.Client_hours, entry.Vacation_hours,
 		entry.Idle_hours, entry.Training_hours, entry.Sick_hours, entry.Holiday_hours,
-		now, now)
+		entry.Note, now, now)
 	return err
 }
 
 func (p *PostgresDBLayer) UpdateTimesheetEntry(entry TimesheetEntry) error {
+	entry, err := prepareTimesheetEntry(pgDB, entry)
+	if err != nil {
+		return err
+	}
+
 	query := `UPDATE timesheet
 		SET client_name = $1, client_hours = $2, vacation_hours = $3, idle_hours = $4,
-		    training_hours = $5, holiday_hours = $6, sick_hours = $7, updated_at = $8
-		WHERE date = $9`
-
-	result, err := pgDB.Exec(query,
+		    training_hours = $5, holiday_hours = $6, sick_hours = $7, note = $8, updated_at = $9`
+	args := []any{
 		entry.Client_name, entry.Client_hours, entry.Vacation_hours,
 		entry.Idle_hours, entry.Training_hours, entry.Holiday_hours,
-		entry.Sick_hours, NowTimestamp(), entry.Date)
+		entry.Sick_hours, entry.Note, NowTimestamp(),
+	}
+
+	tx, err := pgDB.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	if entry.Id != 0 {
+		query += " WHERE id = $10"
+		args = append(args, entry.Id)
+		// Moving the block to another client retires its old key
+		var date, clientName string
+		err := tx.QueryRow(`SELECT date, client_name FROM timesheet WHERE id = $1`, entry.Id).Scan(&date, &clientName)
+		if err == nil && clientName != entry.Client_name {
+			if err := WritePostgresTombstone(tx, TombstoneTableTimesheet, TombstoneKeyTimesheet(date, clientName)); err != nil {
+				return err
+			}
+		}
+	} else {
+		query += " WHERE date = $10 AND client_name = $11"
+		args = append(args, entry.Date, entry.Client_name)
+	}
+
+	result, err := tx.Exec(query, args...)
 	if err != nil {
 		return fmt.Errorf("failed to update record: %w", err)
 	}
@@ -166,9 +213,9 @@ func (p *PostgresDBLayer) UpdateTimesheetEntry(entry TimesheetEntry) error {
 		return fmt.Errorf("error checking rows affected: %w", err)
 	}
 	if rowsAffected == 0 {
-		return fmt.Errorf("no entry found with date %s", entry.Date)
+		return fmt.Errorf("no entry found for %s on %s", entry.Client_name, entry.Date)
 	}
-	return nil
+	return tx.Commit()
 }
 
 func (p *PostgresDBLayer) UpdateTimesheetEntryById(id string, data map[string]any) error {
@@ -182,16 +229,15 @@ func (p *PostgresDBLayer) DeleteTimesheetEntryByDate(date string) error {
 	}
 	defer tx.Rollback()
 
-	res, err := tx.Exec(`DELETE FROM timesheet WHERE date = $1`, date)
+	clientNames, err := queryTimesheetClientNames(tx, `SELECT client_name FROM timesheet WHERE date = $1`, date)
 	if err != nil {
-		return fmt.Errorf("failed to delete record: %w", err)
+		return fmt.Errorf("failed to look up entries: %w", err)
 	}
-	affected, err := res.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to check rows affected: %w", err)
+	if _, err := tx.Exec(`DELETE FROM timesheet WHERE date = $1`, date); err != nil {
+		return fmt.Errorf("failed to delete record: %w", err)
 	}
-	if affected > 0 {
-		if err := WritePostgresTombstone(tx, TombstoneTableTimesheet, date); err != nil {
+	for _, clientName := range clientNames {
+		if err := WritePostgresTombstone(tx, TombstoneTableTimesheet, TombstoneKeyTimesheet(date, clientName)); err != nil {
 			return err
 		}
 	}
@@ -205,8 +251,8 @@ func (p *PostgresDBLayer) DeleteTimesheetEntry(id string) error {
 	}
 	defer tx.Rollback()
 
-	var date string
-	err = tx.QueryRow(`SELECT date FROM timesheet WHERE id = $1`, id).Scan(&date)
+	var date, clientName string
+	err = tx.QueryRow(`SELECT date, client_name FROM timesheet WHERE id = $1`, id).Scan(&date, &clientName)
 	if err == sql.ErrNoRows {
 		return tx.Commit()
 	}
@@ -217,12 +263,38 @@ func (p *PostgresDBLayer) DeleteTimesheetEntry(id string) error {
 	if _, err := tx.Exec(`DELETE FROM timesheet WHERE id = $1`, id); err != nil {
 		return fmt.Errorf("failed to delete record: %w", err)
 	}
-	if err := WritePostgresTombstone(tx, TombstoneTableTimesheet, date); err != nil {
+	if err := WritePostgresTombstone(tx, TombstoneTableTimesheet, TombstoneKeyTimesheet(date, clientName)); err != nil {
 		return err
 	}
 	return tx.Commit()
 }
 
+func (p *PostgresDBLayer) DeleteTimesheetEntriesForMonth(year int, month time.Month) (int, error) {
+	tx, err := pgDB.Begin()
+	if err != nil {
+		return 0, fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer tx.Rollback()
+
+	start, end := expenseDateBounds(year, int(month))
+	keys, err := queryTimesheetKeys(tx, `SELECT date, client_name FROM timesheet WHERE date >= $1 AND date < $2`, start, end)
+	if err != nil {
+		return 0, fmt.Errorf("failed to look up entries: %w", err)
+	}
+	if _, err := tx.Exec(`DELETE FROM timesheet WHERE date >= $1 AND date < $2`, start, end); err != nil {
+		return 0, fmt.Errorf("failed to delete records: %w", err)
+	}
+	for _, key := range keys {
+		if err := WritePostgresTombstone(tx, TombstoneTableTimesheet, key); err != nil {
+			return 0, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return 0, err
+	}
+	return len(keys), nil
+}
+
 func (p *PostgresDBLayer) GetLastClientName() (string, error) {
 	query := `SELECT client_name FROM timesheet ORDER BY date DESC LIMIT 1`
 	var clientName string
@@ -239,17 +311,14 @@ func (p *PostgresDBLayer) GetLastClientName() (string, error) {
 // Training/Vacation operations
 
 func (p *PostgresDBLayer) GetTrainingEntriesForYear(year int) ([]TimesheetEntry, error) {
-	startDate := fmt.Sprintf("%d-01-01", year)
-	endDate := fmt.Sprintf("%d-12-31", year)
+	startDate, endDate := yearDateBounds(year)
 
 	rows, err := pgDB.Query(`
-		SELECT id, date, client_name, client_hours, training_hours, vacation_hours,
-		       idle_hours, holiday_hours, sick_hours,
-		       (client_hours + training_hours + vacation_hours + idle_hours + holiday_hours + sick_hours) as total_hours
+		SELECT `+timesheetSelectColumns+`
 		FROM timesheet
-		WHERE date BETWEEN $1 AND $2
-		AND training_hours > 0
-		ORDER BY date DESC
+		WHERE date >= $1 AND date < $2
+		AND COALESCE(training_hours, 0) > 0
+		ORDER BY date DESC, client_name ASC
 	`, startDate, endDate)
 	if err != nil {
 		return nil, err
@@ -261,8 +330,8 @@ func (p *PostgresDBLayer) GetTrainingEntriesForYear(year int) ([]TimesheetEntry,
 		var entry TimesheetEntry
 		err := rows.Scan(
 			&entry.Id, &entry.Date, &entry.Client_name, &entry.Client_hours,
-			&entry.Training_hours, &entry.Vacation_hours, &entry.Idle_hours,
-			&entry.Holiday_hours, &entry.Sick_hours, &entry.Total_hours,
+			&entry.Vacation_hours, &entry.Idle_hours, &entry.Training_hours,
+			&entry.Sick_hours, &entry.Holiday_hours, &entry.Total_hours, &entry.Note,
 		)
 		if err != nil {
 			return nil, err
@@ -273,13 +342,13 @@ func (p *PostgresDBLayer) GetTrainingEntriesForYear(year int) ([]TimesheetEntry,
 }
 
 func (p *PostgresDBLayer) GetVacationEntriesForYear(year int) ([]TimesheetEntry, error) {
+	startDate, endDate := yearDateBounds(year)
 	rows, err := pgDB.Query(`
-		SELECT id, date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours,
-		       (client_hours + vacation_hours + idle_hours + training_hours + sick_hours + holiday_hours) AS total_hours
+		SELECT `+timesheetSelectColumns+`
 		FROM timesheet
-		WHERE EXTRACT(YEAR FROM date::date) = $1 AND vacation_hours > 0
-		ORDER BY date DESC
-	`, year)
+		WHERE date >= $1 AND date < $2 AND COALESCE(vacation_hours, 0) > 0
+		ORDER BY date DESC, client_name ASC
+	`, startDate, endDate)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query timesheet vacation entries: %w", err)
 	}
@@ -290,7 +359,7 @@ func (p *PostgresDBLayer) GetVacationEntriesForYear(year int) ([]TimesheetEntry,
 		var entry TimesheetEntry
 		if err := rows.Scan(&entry.Id, &entry.Date, &entry.Client_name, &entry.Client_hours,
 			&entry.Vacation_hours, &entry.Idle_hours, &entry.Training_hours,
-			&entry.Sick_hours, &entry.Holiday_hours, &entry.Total_hours); err != nil {
+			&entry.Sick_hours, &entry.Holiday_hours, &entry.Total_hours, &entry.Note); err != nil {
 			return nil, fmt.Errorf("failed to scan timesheet vacation entry: %w", err)
 		}
 		entries = append(entries, entry)
@@ -298,19 +367,32 @@ func (p *PostgresDBLayer) GetVacationEntriesForYear(year int) ([]TimesheetEntry,
 	return entries, nil
 }
 
-func (p *PostgresDBLayer) GetVacationHoursForYear(year int) (int, error) {
-	var total int
+func (p *PostgresDBLayer) GetVacationHoursForYear(year int) (float64, error) {
+	var total float64
+	startDate, endDate := yearDateBounds(year)
 	err := pgDB.QueryRow(`
 		SELECT COALESCE(SUM(vacation_hours), 0)
 		FROM timesheet
-		WHERE EXTRACT(YEAR FROM date::date) = $1 AND vacation_hours > 0
-	`, year).Scan(&total)
+		WHERE date >= $1 AND date < $2 AND COALESCE(vacation_hours, 0) > 0
+	`, startDate, endDate).Scan(&total)
 	if err != nil {
 		return 0, fmt.Errorf("failed to get vacation hours from timesheet table: %w", err)
 	}
 	return total, nil
 }
 
+func (p *PostgresDBLayer) GetSickHoursForYear(year int) (float64, error) {
+	return sumHoursForYear(pgDB, func(n int) string { return fmt.Sprintf("$%d", n) }, "sick_hours", year)
+}
+
+func (p *PostgresDBLayer) GetIdleHoursForYear(year int) (float64, error) {
+	return sumHoursForYear(pgDB, func(n int) string { return fmt.Sprintf("$%d", n) }, "idle_hours", year)
+}
+
+func (p *PostgresDBLayer) GetMonthlyTotals(year int, month time.Month) (HourTotals, error) {
+	return queryMonthlyTotals(pgDB, func(n int) string { return fmt.Sprintf("$%d", n) }, year, month)
+}
+
 // Vacation carryover operations
 
 func (p *PostgresDBLayer) GetVacationCarryoverForYear(year int) (VacationCarryover, error) {
@@ -382,7 +464,7 @@ func (p *PostgresDBLayer) DeleteVacationCarryover(year int) error {
 // calculateAutoCarryoverPostgres computes the carryover for a year by looking at
 // the previous year's remaining vacation hours. Only called when no explicit
 // carryover record exists for the given year.
-func (p *PostgresDBLayer) calculateAutoCarryover(year int, yearlyTarget int) (int, error) {
+func (p *PostgresDBLayer) calculateAutoCarryover(year int, yearlyTarget float64) (float64, error) {
 	// Get previous year's explicit carryover (don't recurse — only look one level back)
 	prevCarryover, err := p.GetVacationCarryoverForYear(year - 1)
 	if err != nil {
@@ -395,7 +477,7 @@ func (p *PostgresDBLayer) calculateAutoCarryover(year int, yearlyTarget int) (in
 		return 0, fmt.Errorf("failed to get previous year used hours: %w", err)
 	}
 
-	prevAvailable := yearlyTarget + prevCarryover.CarryoverHours
+	prevAvailable := yearlyTarget + float64(prevCarryover.CarryoverHours)
 	remaining := prevAvailable - prevUsed
 
 	// Don't carry over negative values
@@ -412,7 +494,7 @@ func (p *PostgresDBLayer) GetVacationSummaryForYear(year int) (VacationSummary,
 	if err != nil {
 		return summary, fmt.Errorf("failed to get config: %w", err)
 	}
-	summary.YearlyTarget = cfg.VacationHours.YearlyTarget
+	summary.YearlyTarget = float64(cfg.VacationHours.YearlyTarget)
 
 	// Get carryover hours — auto-calculate if no explicit record exists
 	carryover, err := p.GetVacationCarryoverForYear(year)
@@ -427,14 +509,14 @@ func (p *PostgresDBLayer) GetVacationSummaryForYear(year int) (VacationSummary,
 		}
 		summary.CarryoverHours = autoCarryover
 	} else {
-		summary.CarryoverHours = carryover.CarryoverHours
+		summary.CarryoverHours = float64(carryover.CarryoverHours)
 	}
 
 	bufferHours, err := p.GetBufferTotalForYear(year)
 	if err != nil {
 		return summary, fmt.Errorf("failed to get buffer hours: %w", err)
 	}
-	summary.BufferHours = bufferHours
+	summary.BufferHours = float64(bufferHours)
 
 	usedHours, err := p.GetVacationHoursForYear(year)
 	if err != nil {
@@ -548,12 +630,16 @@ func (p *PostgresDBLayer) DeleteBufferEntry(year, month int) error {
 
 // Training budget operations
 
+func (p *PostgresDBLayer) GetTrainingBudgetSpentForYear(year int) (TrainingBudgetSpent, error) {
+	return queryTrainingBudgetSpent(pgDB, func(n int) string { return fmt.Sprintf("$%d", n) }, year)
+}
+
 func (p *PostgresDBLayer) GetTrainingBudgetEntriesForYear(year int) ([]TrainingBudgetEntry, error) {
 	startDate := fmt.Sprintf("%d-01-01", year)
 	endDate := fmt.Sprintf("%d-12-31", year)
 
 	rows, err := pgDB.Query(`
-		SELECT id, date, training_name, hours, cost_without_vat
+		SELECT id, date, training_name, hours, cost_without_vat, COALESCE(receipt_path, '')
 		FROM training_budget
 		WHERE date BETWEEN $1 AND $2
 		ORDER BY date DESC
@@ -566,7 +652,7 @@ func (p *PostgresDBLayer) GetTrainingBudgetEntriesForYear(year int) ([]TrainingB
 	entries := make([]TrainingBudgetEntry, 0, 50)
 	for rows.Next() {
 		var entry TrainingBudgetEntry
-		err := rows.Scan(&entry.Id, &entry.Date, &entry.Training_name, &entry.Hours, &entry.Cost_without_vat)
+		err := rows.Scan(&entry.Id, &entry.Date, &entry.Training_name, &entry.Hours, &entry.Cost_without_vat, &entry.Receipt_path)
 		if err != nil {
 			return nil, err
 		}
@@ -577,17 +663,17 @@ func (p *PostgresDBLayer) GetTrainingBudgetEntriesForYear(year int) ([]TrainingB
 
 func (p *PostgresDBLayer) AddTrainingBudgetEntry(entry TrainingBudgetEntry) error {
 	now := NowTimestamp()
-	query := `INSERT INTO training_budget (date, training_name, hours, cost_without_vat, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := pgDB.Exec(query, entry.Date, entry.Training_name, entry.Hours, entry.Cost_without_vat, now, now)
+	query := `INSERT INTO training_budget (date, training_name, hours, cost_without_vat, receipt_path, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := pgDB.Exec(query, entry.Date, entry.Training_name, entry.Hours, entry.Cost_without_vat, entry.Receipt_path, now, now)
 	return err
 }
 
 func (p *PostgresDBLayer) UpdateTrainingBudgetEntry(entry TrainingBudgetEntry) error {
 	query := `UPDATE training_budget
-		SET date = $1, training_name = $2, hours = $3, cost_without_vat = $4, updated_at = $5
-		WHERE id = $6`
-	_, err := pgDB.Exec(query, entry.Date, entry.Training_name, entry.Hours, entry.Cost_without_vat, NowTimestamp(), entry.Id)
+		SET date = $1, training_name = $2, hours = $3, cost_without_vat = $4, receipt_path = $5, updated_at = $6
+		WHERE id = $7`
+	_, err := pgDB.Exec(query, entry.Date, entry.Training_name, entry.Hours, entry.Cost_without_vat, entry.Receipt_path, NowTimestamp(), entry.Id)
 	return err
 }
 
@@ -617,9 +703,9 @@ func (p *PostgresDBLayer) DeleteTrainingBudgetEntry(id int) error {
 }
 
 func (p *PostgresDBLayer) GetTrainingBudgetEntry(id int) (TrainingBudgetEntry, error) {
-	query := `SELECT id, date, training_name, hours, cost_without_vat FROM training_budget WHERE id = $1`
+	query := `SELECT id, date, training_name, hours, cost_without_vat, COALESCE(receipt_path, '') FROM training_budget WHERE id = $1`
 	var entry TrainingBudgetEntry
-	err := pgDB.QueryRow(query, id).Scan(&entry.Id, &entry.Date, &entry.Training_name, &entry.Hours, &entry.Cost_without_vat)
+	err := pgDB.QueryRow(query, id).Scan(&entry.Id, &entry.Date, &entry.Training_name, &entry.Hours, &entry.Cost_without_vat, &entry.Receipt_path)
 	if err != nil {
 		return TrainingBudgetEntry{}, err
 	}
@@ -627,19 +713,74 @@ func (p *PostgresDBLayer) GetTrainingBudgetEntry(id int) (TrainingBudgetEntry, e
 }
 
 func (p *PostgresDBLayer) GetTrainingBudgetEntryByDate(date string) (TrainingBudgetEntry, error) {
-	query := `SELECT id, date, training_name, hours, cost_without_vat FROM training_budget WHERE date = $1`
+	query := `SELECT id, date, training_name, hours, cost_without_vat, COALESCE(receipt_path, '') FROM training_budget WHERE date = $1`
 	var entry TrainingBudgetEntry
-	err := pgDB.QueryRow(query, date).Scan(&entry.Id, &entry.Date, &entry.Training_name, &entry.Hours, &entry.Cost_without_vat)
+	err := pgDB.QueryRow(query, date).Scan(&entry.Id, &entry.Date, &entry.Training_name, &entry.Hours, &entry.Cost_without_vat, &entry.Receipt_path)
 	if err != nil {
 		return TrainingBudgetEntry{}, err
 	}
 	return entry, nil
 }
 
+// Expense operations
+
+func (p *PostgresDBLayer) GetExpenses(year, month int) ([]Expense, error) {
+	start, end := expenseDateBounds(year, month)
+	rows, err := pgDB.Query(`SELECT `+expenseSelectColumns+` FROM expenses
+		WHERE date >= $1 AND date < $2
+		ORDER BY date ASC, id ASC`, start, end)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query expenses: %w", err)
+	}
+	return scanExpenses(rows)
+}
+
+func (p *PostgresDBLayer) GetExpense(id int) (Expense, error) {
+	row := pgDB.QueryRow(`SELECT `+expenseSelectColumns+` FROM expenses WHERE id = $1`, id)
+	return scanExpense(row)
+}
+
+func (p *PostgresDBLayer) AddExpense(expense Expense) (int, error) {
+	if err := ValidateExpense(expense); err != nil {
+		return 0, err
+	}
+	now := NowTimestamp()
+	var id int
+	err := pgDB.QueryRow(`INSERT INTO expenses (date, client_name, description, amount, billable, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7) RETURNING id`,
+		expense.Date, expense.Client_name, expense.Description, expense.Amount, expense.Billable, now, now).Scan(&id)
+	if err != nil {
+		return 0, fmt.Errorf("failed to add expense: %w", err)
+	}
+	return id, nil
+}
+
+func (p *PostgresDBLayer) UpdateExpense(expense Expense) error {
+	if err := ValidateExpense(expense); err != nil {
+		return err
+	}
+	result, err := pgDB.Exec(`UPDATE expenses
+		SET date = $1, client_name = $2, description = $3, amount = $4, billable = $5, updated_at = $6
+		WHERE id = $7`,
+		expense.Date, expense.Client_name, expense.Description, expense.Amount, expense.Billable, NowTimestamp(), expense.Id)
+	if err != nil {
+		return fmt.Errorf("failed to update expense: %w", err)
+	}
+	return requireAffected(result, expense.Id)
+}
+
+func (p *PostgresDBLayer) DeleteExpense(id int) error {
+	result, err := pgDB.Exec(`DELETE FROM expenses WHERE id = $1`, id)
+	if err != nil {
+		return fmt.Errorf("failed to delete expense: %w", err)
+	}
+	return requireAffected(result, id)
+}
+
 // Client operations
 
 func (p *PostgresDBLayer) GetAllClients() ([]Client, error) {
-	query := `SELECT id, name, created_at, is_active FROM clients ORDER BY name ASC`
+	query := `SELECT id, name, created_at, is_active, COALESCE(currency, ''), COALESCE(min_billable_hours, 0), COALESCE(monthly_hour_cap, 0), COALESCE(total_hour_cap, 0) FROM clients ORDER BY name ASC`
 	rows, err := pgDB.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query clients: %w", err)
@@ -650,7 +791,7 @@ func (p *PostgresDBLayer) GetAllClients() ([]Client, error) {
 	for rows.Next() {
 		var client Client
 		var isActive int
-		if err := rows.Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive); err != nil {
+		if err := rows.Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive, &client.Currency, &client.MinBillableHours, &client.MonthlyHourCap, &client.TotalHourCap); err != nil {
 			return nil, fmt.Errorf("failed to scan client: %w", err)
 		}
 		client.IsActive = isActive == 1
@@ -660,7 +801,7 @@ func (p *PostgresDBLayer) GetAllClients() ([]Client, error) {
 }
 
 func (p *PostgresDBLayer) GetActiveClients() ([]Client, error) {
-	query := `SELECT id, name, created_at, is_active FROM clients WHERE is_active = 1 ORDER BY name ASC`
+	query := `SELECT id, name, created_at, is_active, COALESCE(currency, ''), COALESCE(min_billable_hours, 0), COALESCE(monthly_hour_cap, 0), COALESCE(total_hour_cap, 0) FROM clients WHERE is_active = 1 ORDER BY name ASC`
 	rows, err := pgDB.Query(query)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query active clients: %w", err)
@@ -671,7 +812,7 @@ func (p *PostgresDBLayer) GetActiveClients() ([]Client, error) {
 	for rows.Next() {
 		var client Client
 		var isActive int
-		if err := rows.Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive); err != nil {
+		if err := rows.Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive, &client.Currency, &client.MinBillableHours, &client.MonthlyHourCap, &client.TotalHourCap); err != nil {
 			return nil, fmt.Errorf("failed to scan client: %w", err)
 		}
 		client.IsActive = isActive == 1
@@ -681,10 +822,10 @@ func (p *PostgresDBLayer) GetActiveClients() ([]Client, error) {
 }
 
 func (p *PostgresDBLayer) GetClientById(id int) (Client, error) {
-	query := `SELECT id, name, created_at, is_active FROM clients WHERE id = $1`
+	query := `SELECT id, name, created_at, is_active, COALESCE(currency, ''), COALESCE(min_billable_hours, 0), COALESCE(monthly_hour_cap, 0), COALESCE(total_hour_cap, 0) FROM clients WHERE id = $1`
 	var client Client
 	var isActive int
-	err := pgDB.QueryRow(query, id).Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive)
+	err := pgDB.QueryRow(query, id).Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive, &client.Currency, &client.MinBillableHours, &client.MonthlyHourCap, &client.TotalHourCap)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return Client{}, fmt.Errorf("client not found")
@@ -696,10 +837,10 @@ func (p *PostgresDBLayer) GetClientById(id int) (Client, error) {
 }
 
 func (p *PostgresDBLayer) GetClientByName(name string) (Client, error) {
-	query := `SELECT id, name, created_at, is_active FROM clients WHERE name = $1`
+	query := `SELECT id, name, created_at, is_active, COALESCE(currency, ''), COALESCE(min_billable_hours, 0), COALESCE(monthly_hour_cap, 0), COALESCE(total_hour_cap, 0) FROM clients WHERE name = $1`
 	var client Client
 	var isActive int
-	err := pgDB.QueryRow(query, name).Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive)
+	err := pgDB.QueryRow(query, name).Scan(&client.Id, &client.Name, &client.CreatedAt, &isActive, &client.Currency, &client.MinBillableHours, &client.MonthlyHourCap, &client.TotalHourCap)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return Client{}, fmt.Errorf("client not found")
@@ -711,7 +852,14 @@ func (p *PostgresDBLayer) GetClientByName(name string) (Client, error) {
 }
 
 func (p *PostgresDBLayer) AddClient(client Client) (int, error) {
-	query := `INSERT INTO clients (name, created_at, updated_at, is_active) VALUES ($1, $2, $3, $4) RETURNING id`
+	query := `INSERT INTO clients (name, created_at, updated_at, is_active, currency, min_billable_hours, monthly_hour_cap, total_hour_cap) VALUES ($1, $2, $3, $4, $5, $6, $7, $8) RETURNING id`
+	currency, err := NormalizeCurrency(client.Currency)
+	if err != nil {
+		return 0, err
+	}
+	if err := ValidateClient(client); err != nil {
+		return 0, err
+	}
 	now := NowTimestamp()
 	isActive := 0
 	if client.IsActive {
@@ -719,7 +867,7 @@ func (p *PostgresDBLayer) AddClient(client Client) (int, error) {
 	}
 
 	var id int
-	err := pgDB.QueryRow(query, client.Name, now, now, isActive).Scan(&id)
+	err = pgDB.QueryRow(query, client.Name, now, now, isActive, currency, client.MinBillableHours, client.MonthlyHourCap, client.TotalHourCap).Scan(&id)
 	if err != nil {
 		return 0, fmt.Errorf("failed to add client: %w", err)
 	}
@@ -727,13 +875,20 @@ func (p *PostgresDBLayer) AddClient(client Client) (int, error) {
 }
 
 func (p *PostgresDBLayer) UpdateClient(client Client) error {
-	query := `UPDATE clients SET name = $1, is_active = $2, updated_at = $3 WHERE id = $4`
+	query := `UPDATE clients SET name = $1, is_active = $2, currency = $3, min_billable_hours = $4, monthly_hour_cap = $5, total_hour_cap = $6, updated_at = $7 WHERE id = $8`
+	currency, err := NormalizeCurrency(client.Currency)
+	if err != nil {
+		return err
+	}
+	if err := ValidateClient(client); err != nil {
+		return err
+	}
 	isActive := 0
 	if client.IsActive {
 		isActive = 1
 	}
 
-	result, err := pgDB.Exec(query, client.Name, isActive, NowTimestamp(), client.Id)
+	result, err := pgDB.Exec(query, client.Name, isActive, currency, client.MinBillableHours, client.MonthlyHourCap, client.TotalHourCap, NowTimestamp(), client.Id)
 	if err != nil {
 		return fmt.Errorf("failed to update client: %w", err)
 	}
@@ -821,7 +976,7 @@ func (p *PostgresDBLayer) DeactivateClient(id int) error {
 // Client rate operations
 
 func (p *PostgresDBLayer) GetClientRates(clientId int) ([]ClientRate, error) {
-	query := `SELECT id, client_id, hourly_rate, effective_date, notes, created_at
+	query := `SELECT id, client_id, hourly_rate, effective_date, COALESCE(end_date, ''), notes, created_at
 		FROM client_rates
 		WHERE client_id = $1
 		ORDER BY effective_date DESC, created_at DESC`
@@ -836,7 +991,7 @@ func (p *PostgresDBLayer) GetClientRates(clientId int) ([]ClientRate, error) {
 	for rows.Next() {
 		var rate ClientRate
 		if err := rows.Scan(&rate.Id, &rate.ClientId, &rate.HourlyRate,
-			&rate.EffectiveDate, &rate.Notes, &rate.CreatedAt); err != nil {
+			&rate.EffectiveDate, &rate.EndDate, &rate.Notes, &rate.CreatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan client rate: %w", err)
 		}
 		rates = append(rates, rate)
@@ -845,12 +1000,12 @@ func (p *PostgresDBLayer) GetClientRates(clientId int) ([]ClientRate, error) {
 }
 
 func (p *PostgresDBLayer) GetClientRateById(id int) (ClientRate, error) {
-	query := `SELECT id, client_id, hourly_rate, effective_date, notes, created_at
+	query := `SELECT id, client_id, hourly_rate, effective_date, COALESCE(end_date, ''), notes, created_at
 		FROM client_rates WHERE id = $1`
 
 	var rate ClientRate
 	err := pgDB.QueryRow(query, id).Scan(&rate.Id, &rate.ClientId, &rate.HourlyRate,
-		&rate.EffectiveDate, &rate.Notes, &rate.CreatedAt)
+		&rate.EffectiveDate, &rate.EndDate, &rate.Notes, &rate.CreatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return ClientRate{}, fmt.Errorf("client rate not found")
@@ -861,10 +1016,17 @@ func (p *PostgresDBLayer) GetClientRateById(id int) (ClientRate, error) {
 }
 
 func (p *PostgresDBLayer) AddClientRate(rate ClientRate) error {
-	query := `INSERT INTO client_rates (client_id, hourly_rate, effective_date, notes, created_at, updated_at)
-		VALUES ($1, $2, $3, $4, $5, $6)`
+	query := `INSERT INTO client_rates (client_id, hourly_rate, effective_date, end_date, notes, created_at, updated_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	hourlyRate, err := NormalizeHourlyRate(rate.HourlyRate)
+	if err != nil {
+		return err
+	}
+	if err := ValidateRateEndDate(rate); err != nil {
+		return err
+	}
 	now := NowTimestamp()
-	_, err := pgDB.Exec(query, rate.ClientId, rate.HourlyRate, rate.EffectiveDate, rate.Notes, now, now)
+	_, err = pgDB.Exec(query, rate.ClientId, hourlyRate, rate.EffectiveDate, rate.EndDate, rate.Notes, now, now)
 	if err != nil {
 		return fmt.Errorf("failed to add client rate: %w", err)
 	}
@@ -872,8 +1034,15 @@ func (p *PostgresDBLayer) AddClientRate(rate ClientRate) error {
 }
 
 func (p *PostgresDBLayer) UpdateClientRate(rate ClientRate) error {
-	query := `UPDATE client_rates SET hourly_rate = $1, effective_date = $2, notes = $3, updated_at = $4 WHERE id = $5`
-	result, err := pgDB.Exec(query, rate.HourlyRate, rate.EffectiveDate, rate.Notes, NowTimestamp(), rate.Id)
+	query := `UPDATE client_rates SET hourly_rate = $1, effective_date = $2, end_date = $3, notes = $4, updated_at = $5 WHERE id = $6`
+	hourlyRate, err := NormalizeHourlyRate(rate.HourlyRate)
+	if err != nil {
+		return err
+	}
+	if err := ValidateRateEndDate(rate); err != nil {
+		return err
+	}
+	result, err := pgDB.Exec(query, hourlyRate, rate.EffectiveDate, rate.EndDate, rate.Notes, NowTimestamp(), rate.Id)
 	if err != nil {
 		return fmt.Errorf("failed to update client rate: %w", err)
 	}
@@ -928,7 +1097,7 @@ func (p *PostgresDBLayer) DeleteClientRate(id int) error {
 }
 
 func (p *PostgresDBLayer) GetClientRateForDate(clientId int, date string) (ClientRate, error) {
-	query := `SELECT id, client_id, hourly_rate, effective_date, notes, created_at
+	query := `SELECT id, client_id, hourly_rate, effective_date, COALESCE(end_date, ''), notes, created_at
 		FROM client_rates
 		WHERE client_id = $1 AND effective_date <= $2
 		ORDER BY effective_date DESC, created_at DESC
@@ -936,13 +1105,16 @@ func (p *PostgresDBLayer) GetClientRateForDate(clientId int, date string) (Clien
 
 	var rate ClientRate
 	err := pgDB.QueryRow(query, clientId, date).Scan(&rate.Id, &rate.ClientId,
-		&rate.HourlyRate, &rate.EffectiveDate, &rate.Notes, &rate.CreatedAt)
+		&rate.HourlyRate, &rate.EffectiveDate, &rate.EndDate, &rate.Notes, &rate.CreatedAt)
 	if err != nil {
 		if err == sql.ErrNoRows {
 			return ClientRate{}, fmt.Errorf("no rate found for client on date %s", date)
 		}
 		return ClientRate{}, fmt.Errorf("failed to query client rate: %w", err)
 	}
+	if rate.Ended(date) {
+		return ClientRate{}, fmt.Errorf("no rate found for client on date %s: rate ended on %s", date, rate.EndDate)
+	}
 	return rate, nil
 }
 
@@ -965,12 +1137,16 @@ func (p *PostgresDBLayer) GetClientRateByName(clientName string, date string) (f
 type pgRateCache struct {
 	clientsByName map[string]int
 	ratesByClient map[int][]ClientRate
+	currencies    map[string]string
+	minimums      map[string]float64
 }
 
 func (p *PostgresDBLayer) buildRateCache() (*pgRateCache, error) {
 	cache := &pgRateCache{
 		clientsByName: make(map[string]int),
 		ratesByClient: make(map[int][]ClientRate),
+		currencies:    make(map[string]string),
+		minimums:      make(map[string]float64),
 	}
 
 	clients, err := p.GetAllClients()
@@ -979,9 +1155,11 @@ func (p *PostgresDBLayer) buildRateCache() (*pgRateCache, error) {
 	}
 	for _, client := range clients {
 		cache.clientsByName[client.Name] = client.Id
+		cache.currencies[client.Name] = client.Currency
+		cache.minimums[client.Name] = client.MinBillableHours
 	}
 
-	query := `SELECT id, client_id, hourly_rate, effective_date, notes, created_at
+	query := `SELECT id, client_id, hourly_rate, effective_date, COALESCE(end_date, ''), notes, created_at
 		FROM client_rates
 		ORDER BY client_id, effective_date DESC`
 
@@ -994,7 +1172,7 @@ func (p *PostgresDBLayer) buildRateCache() (*pgRateCache, error) {
 	for rows.Next() {
 		var rate ClientRate
 		if err := rows.Scan(&rate.Id, &rate.ClientId, &rate.HourlyRate,
-			&rate.EffectiveDate, &rate.Notes, &rate.CreatedAt); err != nil {
+			&rate.EffectiveDate, &rate.EndDate, &rate.Notes, &rate.CreatedAt); err != nil {
 			return nil, fmt.Errorf("failed to scan rate: %w", err)
 		}
 		cache.ratesByClient[rate.ClientId] = append(cache.ratesByClient[rate.ClientId], rate)
@@ -1008,61 +1186,104 @@ func (c *pgRateCache) getRateFromCache(clientName string, date string) float64 {
 		return 0.0
 	}
 
-	rates, ok := c.ratesByClient[clientId]
-	if !ok || len(rates) == 0 {
+	rate, ok := rateOn(c.ratesByClient[clientId], date)
+	if !ok {
 		return 0.0
 	}
+	return rate.HourlyRate
+}
 
-	for _, rate := range rates {
-		if rate.EffectiveDate <= date {
-			return rate.HourlyRate
-		}
-	}
-	return 0.0
+// pgRatedHours is the client hours of an entry with the rate in effect on
+// its date
+type pgRatedHours struct {
+	Date        string
+	ClientName  string
+	ClientHours float64
+	Rate        float64
 }
 
-func (p *PostgresDBLayer) CalculateEarningsForYear(year int) (EarningsOverview, error) {
-	cache, err := p.buildRateCache()
+// pgRatedHoursQuery picks the rate of each entry with client hours in a
+// correlated subquery, the scalar form of a LATERAL join that the SQLite
+// stand-in of the tests runs too: the newest rate of the entry's client that
+// took effect on or before its date, found through
+// idx_client_rates_client_date. Like rateOn, that rate counts as 0 when it
+// ended before the date, and an entry without a client or rate gets 0. $1
+// and $2 bound the dates.
+const pgRatedHoursQuery = `SELECT t.date, t.client_name, t.client_hours, COALESCE((
+		SELECT CASE WHEN cr.end_date IS NULL OR cr.end_date = '' OR SUBSTR(t.date, 1, 10) <= cr.end_date
+			THEN cr.hourly_rate ELSE 0 END
+		FROM client_rates cr
+		WHERE cr.client_id = c.id AND cr.effective_date <= t.date
+		ORDER BY cr.effective_date DESC, cr.id DESC
+		LIMIT 1
+	), 0)
+	FROM timesheet t
+	LEFT JOIN clients c ON c.name = t.client_name
+	WHERE t.client_hours > 0 AND t.date >= $1 AND t.date < $2
+	ORDER BY t.date ASC, t.id ASC`
+
+// queryRatedHours lets the database pick the rate of every entry with client
+// hours in the year, or in the month when it isn't 0
+func queryRatedHours(conn *sql.DB, year, month int) ([]pgRatedHours, error) {
+	start, end := expenseDateBounds(year, month)
+	rows, err := conn.Query(pgRatedHoursQuery, start, end)
 	if err != nil {
-		return EarningsOverview{}, fmt.Errorf("failed to build rate cache: %w", err)
+		return nil, err
 	}
+	defer rows.Close()
 
-	entries, err := p.GetAllTimesheetEntries(year, 0)
-	if err != nil {
-		return EarningsOverview{}, fmt.Errorf("failed to get timesheet entries: %w", err)
+	rated := []pgRatedHours{}
+	for rows.Next() {
+		var r pgRatedHours
+		if err := rows.Scan(&r.Date, &r.ClientName, &r.ClientHours, &r.Rate); err != nil {
+			return nil, err
+		}
+		rated = append(rated, r)
 	}
+	return rated, rows.Err()
+}
 
-	earningsEntries := make([]EarningsEntry, 0, 300)
-	var totalHours int
-	var totalEarnings float64
+// ratedHoursInMemory is the Go version of queryRatedHours: it loads every
+// entry of the period and looks the rates up in cache
+func (p *PostgresDBLayer) ratedHoursInMemory(cache *pgRateCache, year, month int) ([]pgRatedHours, error) {
+	entries, err := p.GetAllTimesheetEntries(year, time.Month(month))
+	if err != nil {
+		return nil, err
+	}
 
+	rated := make([]pgRatedHours, 0, len(entries))
 	for _, entry := range entries {
 		if entry.Client_hours <= 0 {
 			continue
 		}
-
-		rate := cache.getRateFromCache(entry.Client_name, entry.Date)
-		earnings := float64(entry.Client_hours) * rate
-
-		earningsEntries = append(earningsEntries, EarningsEntry{
+		rated = append(rated, pgRatedHours{
 			Date:        entry.Date,
 			ClientName:  entry.Client_name,
 			ClientHours: entry.Client_hours,
-			HourlyRate:  rate,
-			Earnings:    earnings,
+			Rate:        cache.getRateFromCache(entry.Client_name, entry.Date),
 		})
+	}
+	return rated, nil
+}
 
-		totalHours += entry.Client_hours
-		totalEarnings += earnings
+// ratedHours returns the client hours of the period with their rates,
+// picked in SQL. When that query fails the rates are looked up in Go.
+func (p *PostgresDBLayer) ratedHours(cache *pgRateCache, year, month int) ([]pgRatedHours, error) {
+	rated, err := queryRatedHours(pgDB, year, month)
+	if err == nil {
+		return rated, nil
 	}
+	logging.Log("Earnings query failed, looking up rates in memory: %v", err)
 
-	return EarningsOverview{
-		Year:          year,
-		Month:         0,
-		TotalHours:    totalHours,
-		TotalEarnings: totalEarnings,
-		Entries:       earningsEntries,
-	}, nil
+	rated, err = p.ratedHoursInMemory(cache, year, month)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get timesheet entries: %w", err)
+	}
+	return rated, nil
+}
+
+func (p *PostgresDBLayer) CalculateEarningsForYear(year int) (EarningsOverview, error) {
+	return p.calculateEarnings(year, 0)
 }
 
 func (p *PostgresDBLayer) CalculateEarningsSummaryForYear(year int) (EarningsOverview, error) {
@@ -1070,96 +1291,95 @@ func (p *PostgresDBLayer) CalculateEarningsSummaryForYear(year int) (EarningsOve
 	if err != nil {
 		return EarningsOverview{}, fmt.Errorf("failed to build rate cache: %w", err)
 	}
+	invoices := loadInvoicing(cache.minimums)
 
-	entries, err := p.GetAllTimesheetEntries(year, 0)
+	rated, err := p.ratedHours(cache, year, 0)
 	if err != nil {
-		return EarningsOverview{}, fmt.Errorf("failed to get timesheet entries: %w", err)
+		return EarningsOverview{}, err
 	}
 
 	type ClientRateKey struct {
 		ClientName string
 		Rate       float64
 	}
-	aggregated := make(map[ClientRateKey]int)
-
-	for _, entry := range entries {
-		if entry.Client_hours <= 0 {
-			continue
-		}
+	aggregated := make(map[ClientRateKey]EarningsEntry)
 
-		rate := cache.getRateFromCache(entry.Client_name, entry.Date)
-		key := ClientRateKey{ClientName: entry.Client_name, Rate: rate}
-		aggregated[key] += entry.Client_hours
+	for _, r := range rated {
+		key := ClientRateKey{ClientName: r.ClientName, Rate: r.Rate}
+		aggregated[key] = aggregated[key].plus(invoices.price(r.ClientName, r.ClientHours, r.Rate))
 	}
 
 	earningsEntries := make([]EarningsEntry, 0, len(aggregated))
-	var totalHours int
-	var totalEarnings float64
-
-	for key, hours := range aggregated {
-		earnings := float64(hours) * key.Rate
-		earningsEntries = append(earningsEntries, EarningsEntry{
-			Date:        "",
-			ClientName:  key.ClientName,
-			ClientHours: hours,
-			HourlyRate:  key.Rate,
-			Earnings:    earnings,
-		})
-		totalHours += hours
-		totalEarnings += earnings
+	var totalHours float64
+
+	for key, total := range aggregated {
+		total.ClientName = key.ClientName
+		total.HourlyRate = key.Rate
+		total.Currency = cache.currencies[key.ClientName]
+		earningsEntries = append(earningsEntries, total)
+		totalHours += total.ClientHours
 	}
 
-	return EarningsOverview{
-		Year:          year,
-		Month:         0,
-		TotalHours:    totalHours,
-		TotalEarnings: totalEarnings,
-		Entries:       earningsEntries,
-	}, nil
+	overview := EarningsOverview{
+		Year:       year,
+		Month:      0,
+		TotalHours: totalHours,
+		Entries:    earningsEntries,
+	}
+	applyCurrencies(&overview)
+
+	expenses, err := p.GetExpenses(year, 0)
+	if err != nil {
+		return EarningsOverview{}, fmt.Errorf("failed to get expenses: %w", err)
+	}
+	applyExpenses(&overview, expenses, cache.currencies)
+	return overview, nil
 }
 
 func (p *PostgresDBLayer) CalculateEarningsForMonth(year int, month int) (EarningsOverview, error) {
+	return p.calculateEarnings(year, month)
+}
+
+// calculateEarnings prices every entry with client hours of the year, or of
+// the month when it isn't 0
+func (p *PostgresDBLayer) calculateEarnings(year, month int) (EarningsOverview, error) {
 	cache, err := p.buildRateCache()
 	if err != nil {
 		return EarningsOverview{}, fmt.Errorf("failed to build rate cache: %w", err)
 	}
+	invoices := loadInvoicing(cache.minimums)
 
-	entries, err := p.GetAllTimesheetEntries(year, time.Month(month))
+	rated, err := p.ratedHours(cache, year, month)
 	if err != nil {
-		return EarningsOverview{}, fmt.Errorf("failed to get timesheet entries: %w", err)
+		return EarningsOverview{}, err
 	}
 
-	earningsEntries := make([]EarningsEntry, 0, 30)
-	var totalHours int
-	var totalEarnings float64
-
-	for _, entry := range entries {
-		if entry.Client_hours <= 0 {
-			continue
-		}
+	earningsEntries := make([]EarningsEntry, 0, len(rated))
+	var totalHours float64
 
-		rate := cache.getRateFromCache(entry.Client_name, entry.Date)
-		earnings := float64(entry.Client_hours) * rate
+	for _, r := range rated {
+		priced := invoices.price(r.ClientName, r.ClientHours, r.Rate)
+		priced.Date = r.Date
+		priced.Currency = cache.currencies[r.ClientName]
+		earningsEntries = append(earningsEntries, priced)
 
-		earningsEntries = append(earningsEntries, EarningsEntry{
-			Date:        entry.Date,
-			ClientName:  entry.Client_name,
-			ClientHours: entry.Client_hours,
-			HourlyRate:  rate,
-			Earnings:    earnings,
-		})
+		totalHours += r.ClientHours
+	}
 
-		totalHours += entry.Client_hours
-		totalEarnings += earnings
+	overview := EarningsOverview{
+		Year:       year,
+		Month:      month,
+		TotalHours: totalHours,
+		Entries:    earningsEntries,
 	}
+	applyCurrencies(&overview)
 
-	return EarningsOverview{
-		Year:          year,
-		Month:         month,
-		TotalHours:    totalHours,
-		TotalEarnings: totalEarnings,
-		Entries:       earningsEntries,
-	}, nil
+	expenses, err := p.GetExpenses(year, month)
+	if err != nil {
+		return EarningsOverview{}, fmt.Errorf("failed to get expenses: %w", err)
+	}
+	applyExpenses(&overview, expenses, cache.currencies)
+	return overview, nil
 }
 
 func (p *PostgresDBLayer) GetClientWithRates(clientId int) (ClientWithRates, error) {
@@ -1187,48 +1407,7 @@ func (p *PostgresDBLayer) Ping() error {
 
 // UpdateTimesheetEntryByIdPostgres updates a timesheet entry by ID for PostgreSQL
 func UpdateTimesheetEntryByIdPostgres(id string, data map[string]any) error {
-	allowedFields := map[string]bool{
-		"client_hours":   true,
-		"vacation_hours": true,
-		"idle_hours":     true,
-		"training_hours": true,
-		"holiday_hours":  true,
-		"sick_hours":     true,
-	}
-
-	query := "UPDATE timesheet SET "
-	values := []any{}
-	setStatements := []string{}
-	argNum := 1
-
-	for key, val := range data {
-		if !allowedFields[key] {
-			return fmt.Errorf("field %s is not allowed for update", key)
-		}
-		setStatements = append(setStatements, fmt.Sprintf("%s = $%d", key, argNum))
-		values = append(values, val)
-		argNum++
-	}
-
-	if len(setStatements) == 0 {
-		return fmt.Errorf("no valid fields to update")
-	}
-
-	query += strings.Join(setStatements, ", ")
-	query += fmt.Sprintf(", updated_at = $%d WHERE id = $%d", argNum, argNum+1)
-	values = append(values, NowTimestamp(), id)
-
-	result, err := pgDB.Exec(query, values...)
-	if err != nil {
-		return fmt.Errorf("failed to update record: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("error checking rows affected: %w", err)
-	}
-	if rowsAffected == 0 {
-		return fmt.Errorf("no entry found with id %s", id)
-	}
-	return nil
+	return runInTransaction(pgDB, func(tx *sql.Tx) error {
+		return updateTimesheetEntryByIdTx(tx, id, data)
+	})
 }
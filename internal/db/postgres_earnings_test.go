@@ -0,0 +1,146 @@
+package db
+
+import (
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+)
+
+// seedParityRates gives Client A a rate that changes mid-May and Client B a
+// rate that ended before its hours, on the stand-in Postgres database
+func seedParityRates(t testing.TB) {
+	t.Helper()
+	stmts := []string{
+		`INSERT INTO clients (id, name) VALUES (1, 'Client A'), (2, 'Client B')`,
+		`INSERT INTO client_rates (client_id, hourly_rate, effective_date, end_date, notes) VALUES
+			(1, 100, '2024-01-01', NULL, ''),
+			(1, 120, '2024-05-02', '', ''),
+			(2, 90, '2024-01-01', '2024-04-30', '')`,
+		`INSERT INTO timesheet (date, client_name, client_hours) VALUES
+			('2024-05-06', 'Client B', 2),
+			('2024-05-04', 'Unknown', 3),
+			('2023-12-31', 'Client A', 8)`,
+	}
+	for _, stmt := range stmts {
+		if _, err := pgDB.Exec(stmt); err != nil {
+			t.Fatalf("Failed to seed rates: %v", err)
+		}
+	}
+}
+
+func TestRatedHoursQueryMatchesInMemory(t *testing.T) {
+	setupParityDBs(t)
+	seedParityRates(t)
+	postgres := &PostgresDBLayer{}
+
+	cache, err := postgres.buildRateCache()
+	if err != nil {
+		t.Fatalf("buildRateCache failed: %v", err)
+	}
+	for _, month := range []int{0, 5, 6} {
+		want, err := postgres.ratedHoursInMemory(cache, 2024, month)
+		if err != nil {
+			t.Fatalf("ratedHoursInMemory(2024, %d) failed: %v", month, err)
+		}
+		got, err := queryRatedHours(pgDB, 2024, month)
+		if err != nil {
+			t.Fatalf("queryRatedHours(2024, %d) failed: %v", month, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("month %d: SQL rates %+v, in memory %+v", month, got, want)
+		}
+	}
+
+	// The rate changes on the 2nd of May; Client B's rate has ended
+	rated, err := queryRatedHours(pgDB, 2024, 5)
+	if err != nil {
+		t.Fatalf("queryRatedHours failed: %v", err)
+	}
+	rates := map[string]float64{}
+	for _, r := range rated {
+		rates[r.Date+" "+r.ClientName] = r.Rate
+	}
+	want := map[string]float64{
+		"2024-05-01 Client A": 100,
+		"2024-05-02 Client A": 120,
+		"2024-05-06 Client B": 0,
+		"2024-05-04 Unknown":  0,
+	}
+	if !reflect.DeepEqual(rates, want) {
+		t.Errorf("Expected rates %v, got %v", want, rates)
+	}
+
+	overview, err := postgres.CalculateEarningsForYear(2024)
+	if err != nil {
+		t.Fatalf("CalculateEarningsForYear failed: %v", err)
+	}
+	if overview.TotalHours != 14 || overview.TotalEarnings != 100+8*120 {
+		t.Errorf("Expected 14 hours earning 1060, got %g hours earning %g", overview.TotalHours, overview.TotalEarnings)
+	}
+}
+
+// BenchmarkPostgresRatedHours compares picking the rates in SQL with looking
+// them up in Go. It runs on the SQLite stand-in for Postgres, so it shows
+// the difference in work done rather than real Postgres timings.
+func BenchmarkPostgresRatedHours(b *testing.B) {
+	for _, clients := range []int{5, 30} {
+		b.Run(fmt.Sprintf("entries=%d", clients*366), func(b *testing.B) {
+			setupParityDBs(b)
+			seedBenchmarkYear(b, clients)
+			postgres := &PostgresDBLayer{}
+
+			b.Run("sql", func(b *testing.B) {
+				for b.Loop() {
+					if _, err := queryRatedHours(pgDB, 2024, 0); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+			b.Run("in-memory", func(b *testing.B) {
+				for b.Loop() {
+					cache, err := postgres.buildRateCache()
+					if err != nil {
+						b.Fatal(err)
+					}
+					if _, err := postgres.ratedHoursInMemory(cache, 2024, 0); err != nil {
+						b.Fatal(err)
+					}
+				}
+			})
+		})
+	}
+}
+
+// seedBenchmarkYear logs 8 client hours on every day of 2024 for each of
+// clients clients, whose rate changes every quarter
+func seedBenchmarkYear(b *testing.B, clients int) {
+	b.Helper()
+	tx, err := pgDB.Begin()
+	if err != nil {
+		b.Fatalf("Failed to begin: %v", err)
+	}
+	defer tx.Rollback()
+
+	for c := range clients {
+		name := fmt.Sprintf("Benchmark client %d", c)
+		if _, err := tx.Exec(`INSERT INTO clients (id, name) VALUES ($1, $2)`, 100+c, name); err != nil {
+			b.Fatalf("Failed to seed client: %v", err)
+		}
+		for quarter := range 4 {
+			if _, err := tx.Exec(`INSERT INTO client_rates (client_id, hourly_rate, effective_date, notes) VALUES ($1, $2, $3, '')`,
+				100+c, 100+quarter*5, fmt.Sprintf("2024-%02d-01", quarter*3+1)); err != nil {
+				b.Fatalf("Failed to seed rate: %v", err)
+			}
+		}
+		for day := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC); day.Year() == 2024; day = day.AddDate(0, 0, 1) {
+			if _, err := tx.Exec(`INSERT INTO timesheet (date, client_name, client_hours) VALUES ($1, $2, 8)`,
+				day.Format("2006-01-02"), name); err != nil {
+				b.Fatalf("Failed to seed entry: %v", err)
+			}
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		b.Fatalf("Failed to commit: %v", err)
+	}
+}
@@ -30,26 +30,24 @@ func InitializePostgresDatabase() error {
 			id SERIAL PRIMARY KEY,
 			date TEXT NOT NULL,
 			client_name TEXT NOT NULL,
-			client_hours INTEGER DEFAULT NULL,
-			vacation_hours INTEGER DEFAULT NULL,
-			idle_hours INTEGER DEFAULT NULL,
-			training_hours INTEGER DEFAULT NULL,
-			sick_hours INTEGER DEFAULT NULL,
-			holiday_hours INTEGER DEFAULT NULL,
+			client_hours DOUBLE PRECISION DEFAULT NULL,
+			vacation_hours DOUBLE PRECISION DEFAULT NULL,
+			idle_hours DOUBLE PRECISION DEFAULT NULL,
+			training_hours DOUBLE PRECISION DEFAULT NULL,
+			sick_hours DOUBLE PRECISION DEFAULT NULL,
+			holiday_hours DOUBLE PRECISION DEFAULT NULL,
 			client_id INTEGER REFERENCES clients(id),
 			created_at TEXT DEFAULT CURRENT_TIMESTAMP,
 			updated_at TEXT DEFAULT CURRENT_TIMESTAMP
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_client_name ON timesheet(client_name)`,
-		`CREATE INDEX IF NOT EXISTS idx_timesheet_date ON timesheet(date)`,
-		`CREATE INDEX IF NOT EXISTS idx_timesheet_date_client ON timesheet(date, client_name)`,
 
 		// Training budget table
 		`CREATE TABLE IF NOT EXISTS training_budget (
 			id SERIAL PRIMARY KEY,
 			date TEXT NOT NULL,
 			training_name TEXT NOT NULL,
-			hours INTEGER NOT NULL,
+			hours DOUBLE PRECISION NOT NULL,
 			cost_without_vat DECIMAL(10,2) NOT NULL,
 			created_at TEXT DEFAULT CURRENT_TIMESTAMP,
 			updated_at TEXT DEFAULT CURRENT_TIMESTAMP
@@ -62,6 +60,7 @@ func InitializePostgresDatabase() error {
 			client_id INTEGER NOT NULL,
 			hourly_rate DECIMAL(10,2) NOT NULL,
 			effective_date TEXT NOT NULL,
+			end_date TEXT,
 			notes TEXT,
 			created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
 			updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
@@ -95,6 +94,19 @@ func InitializePostgresDatabase() error {
 			UNIQUE(year, month)
 		)`,
 		`CREATE INDEX IF NOT EXISTS idx_buffer_hours_year ON buffer_hours(year)`,
+
+		// Expenses table (travel, materials, ... per client and day)
+		`CREATE TABLE IF NOT EXISTS expenses (
+			id SERIAL PRIMARY KEY,
+			date TEXT NOT NULL,
+			client_name TEXT NOT NULL,
+			description TEXT NOT NULL,
+			amount DECIMAL(10,2) NOT NULL,
+			billable BOOLEAN NOT NULL DEFAULT TRUE,
+			created_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP,
+			updated_at TEXT NOT NULL DEFAULT CURRENT_TIMESTAMP
+		)`,
+		`CREATE INDEX IF NOT EXISTS idx_expenses_date ON expenses(date)`,
 		// tombstones records every delete so bidirectional sync can propagate
 		// removals instead of re-inserting whichever side still has the row.
 		// record_key is the natural sync key for the table_name (date, name,
@@ -114,35 +126,111 @@ func InitializePostgresDatabase() error {
 		}
 	}
 
-	// Migration: Add updated_at columns for sync support (for existing tables)
-	migrations := []struct {
-		table  string
-		column string
-	}{
-		{"timesheet", "created_at"},
-		{"timesheet", "updated_at"},
-		{"training_budget", "created_at"},
-		{"training_budget", "updated_at"},
-		{"clients", "updated_at"},
-		{"client_rates", "updated_at"},
-	}
-
-	for _, m := range migrations {
-		sql := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s TEXT DEFAULT CURRENT_TIMESTAMP`, m.table, m.column)
+	// Migration: Add updated_at columns for sync support (for existing tables).
+	// They're added without a default so the rows they add are backfilled
+	// in the NowTimestamp layout below.
+	for _, m := range syncTimestampColumns {
+		sql := fmt.Sprintf(`ALTER TABLE %s ADD COLUMN IF NOT EXISTS %s TEXT`, m.table, m.column)
 		_, err := pgDB.Exec(sql)
 		if err != nil && !strings.Contains(err.Error(), "already exists") {
 			logging.Log("Note: Could not add %s.%s column: %v", m.table, m.column, err)
 		}
 	}
 
+	// Migration: Store hours as DOUBLE PRECISION so half hours can be logged
+	if err := migratePostgresHourColumns(); err != nil {
+		logging.Log("Note: Could not convert hour columns to DOUBLE PRECISION: %v", err)
+	}
+
+	// Migration: Add total_hours as a generated column so queries against the
+	// database don't have to add up the hour columns
+	if _, err := pgDB.Exec(`ALTER TABLE timesheet ADD COLUMN IF NOT EXISTS total_hours DOUBLE PRECISION GENERATED ALWAYS AS ` + timesheetTotalHoursExpr + ` STORED`); err != nil {
+		logging.Log("Note: Could not add timesheet.total_hours column: %v", err)
+	}
+
+	// Migration: Add receipt_path to training_budget for receipt attachments
+	if _, err := pgDB.Exec(`ALTER TABLE training_budget ADD COLUMN IF NOT EXISTS receipt_path TEXT`); err != nil {
+		logging.Log("Note: Could not add training_budget.receipt_path column: %v", err)
+	}
+
+	// Migration: Add currency to clients for multi-currency billing
+	if _, err := pgDB.Exec(`ALTER TABLE clients ADD COLUMN IF NOT EXISTS currency TEXT`); err != nil {
+		logging.Log("Note: Could not add clients.currency column: %v", err)
+	}
+
+	// Migration: Add min_billable_hours to clients so short days can be
+	// billed at a client's minimum block
+	if _, err := pgDB.Exec(`ALTER TABLE clients ADD COLUMN IF NOT EXISTS min_billable_hours DOUBLE PRECISION`); err != nil {
+		logging.Log("Note: Could not add clients.min_billable_hours column: %v", err)
+	}
+
+	// Migration: Add monthly_hour_cap and total_hour_cap to clients for
+	// contracts with a fixed number of hours
+	for _, column := range []string{"monthly_hour_cap", "total_hour_cap"} {
+		if _, err := pgDB.Exec(`ALTER TABLE clients ADD COLUMN IF NOT EXISTS ` + column + ` INTEGER`); err != nil {
+			logging.Log("Note: Could not add clients.%s column: %v", column, err)
+		}
+	}
+
+	// Migration: Add end_date to client_rates for fixed-term contracts
+	if _, err := pgDB.Exec(`ALTER TABLE client_rates ADD COLUMN IF NOT EXISTS end_date TEXT`); err != nil {
+		logging.Log("Note: Could not add client_rates.end_date column: %v", err)
+	}
+
+	// Migration: Add note to timesheet for context on a day, e.g. why it had
+	// idle hours
+	if _, err := pgDB.Exec(`ALTER TABLE timesheet ADD COLUMN IF NOT EXISTS note TEXT`); err != nil {
+		logging.Log("Note: Could not add timesheet.note column: %v", err)
+	}
+
+	// Migration: One timesheet entry per client per date
+	if err := migrateUniqueTimesheetDateClient(pgDB); err != nil {
+		logging.Log("Note: Could not make timesheet (date, client_name) unique: %v", err)
+	}
+
 	// Set default values for existing rows that have NULL timestamps
-	pgDB.Exec(`UPDATE timesheet SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL`)
-	pgDB.Exec(`UPDATE timesheet SET updated_at = CURRENT_TIMESTAMP WHERE updated_at IS NULL`)
-	pgDB.Exec(`UPDATE training_budget SET created_at = CURRENT_TIMESTAMP WHERE created_at IS NULL`)
-	pgDB.Exec(`UPDATE training_budget SET updated_at = CURRENT_TIMESTAMP WHERE updated_at IS NULL`)
-	pgDB.Exec(`UPDATE clients SET updated_at = CURRENT_TIMESTAMP WHERE updated_at IS NULL`)
-	pgDB.Exec(`UPDATE client_rates SET updated_at = CURRENT_TIMESTAMP WHERE updated_at IS NULL`)
+	backfillSyncTimestamps(pgDB, "$1")
 
 	logging.Log("PostgreSQL database initialized successfully")
 	return nil
 }
+
+// migratePostgresHourColumns converts the INTEGER hour columns of databases
+// created before half hours were allowed. Postgres can't change the type of
+// a column the generated total_hours depends on, so total_hours is dropped
+// in the same transaction and added back by the caller.
+func migratePostgresHourColumns() error {
+	var dataType string
+	err := pgDB.QueryRow(`SELECT data_type FROM information_schema.columns
+		WHERE table_name = 'timesheet' AND column_name = 'client_hours'`).Scan(&dataType)
+	if err != nil {
+		return err
+	}
+	if dataType != "integer" {
+		return nil
+	}
+
+	tx, err := pgDB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+
+	stmts := []string{
+		`ALTER TABLE timesheet DROP COLUMN IF EXISTS total_hours`,
+		`ALTER TABLE timesheet
+			ALTER COLUMN client_hours TYPE DOUBLE PRECISION,
+			ALTER COLUMN vacation_hours TYPE DOUBLE PRECISION,
+			ALTER COLUMN idle_hours TYPE DOUBLE PRECISION,
+			ALTER COLUMN training_hours TYPE DOUBLE PRECISION,
+			ALTER COLUMN sick_hours TYPE DOUBLE PRECISION,
+			ALTER COLUMN holiday_hours TYPE DOUBLE PRECISION`,
+		`ALTER TABLE training_budget ALTER COLUMN hours TYPE DOUBLE PRECISION`,
+	}
+	for _, stmt := range stmts {
+		if _, err := tx.Exec(stmt); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
@@ -0,0 +1,81 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"slices"
+	"time"
+)
+
+// maxRangeFillDays caps a range fill, so a start day picked in the wrong
+// year doesn't write hundreds of entries
+const maxRangeFillDays = 366
+
+// PlanRangeFill copies template onto every day from from through to (both
+// YYYY-MM-DD and inclusive, in either order), leaving out the days that are
+// in weekendDays
+func PlanRangeFill(from, to string, template TimesheetEntry, weekendDays []time.Weekday) ([]TimesheetEntry, error) {
+	start, err := time.Parse("2006-01-02", from)
+	if err != nil {
+		return nil, fmt.Errorf("invalid from date %q (expected YYYY-MM-DD)", from)
+	}
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return nil, fmt.Errorf("invalid to date %q (expected YYYY-MM-DD)", to)
+	}
+	if end.Before(start) {
+		start, end = end, start
+	}
+	if days := int(end.Sub(start).Hours()/24) + 1; days > maxRangeFillDays {
+		return nil, fmt.Errorf("range of %d days is longer than %d days", days, maxRangeFillDays)
+	}
+
+	var planned []TimesheetEntry
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if slices.Contains(weekendDays, day.Weekday()) {
+			continue
+		}
+		entry := template
+		entry.Id = 0
+		entry.Date = day.Format("2006-01-02")
+		planned = append(planned, entry)
+	}
+	return planned, nil
+}
+
+// SaveRangeFill upserts the planned entries: a date that already has a
+// block for the entry's client gets the entry's values, the blocks of other
+// clients are left alone. Data layers backed by a database write every date
+// in one transaction, so a failure leaves the range untouched; the remote
+// API has no transactions and saves the dates one after the other.
+func SaveRangeFill(dl DataLayer, planned []TimesheetEntry) (BulkResult, error) {
+	var result BulkResult
+
+	t, ok := dl.(Transactor)
+	if !ok {
+		return BulkSaveTimesheetEntries(dl, planned, OverwriteReplace)
+	}
+
+	err := t.WithTransaction(func(tx *sql.Tx) error {
+		for _, entry := range planned {
+			prepared, err := prepareTimesheetEntry(tx, entry)
+			if err != nil {
+				return fmt.Errorf("%s: %w", entry.Date, err)
+			}
+			created, err := upsertTimesheetEntryTx(tx, prepared)
+			if err != nil {
+				return err
+			}
+			if created {
+				result.Created = append(result.Created, entry.Date)
+			} else {
+				result.Replaced = append(result.Replaced, entry.Date)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return BulkResult{}, err
+	}
+	return result, nil
+}
@@ -0,0 +1,97 @@
+package db
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestPlanRangeFill(t *testing.T) {
+	weekend := []time.Weekday{time.Saturday, time.Sunday}
+	template := TimesheetEntry{Id: 7, Vacation_hours: 8}
+
+	// 2024-03-08 is a Friday; the end may come before the start
+	planned, err := PlanRangeFill("2024-03-12", "2024-03-08", template, weekend)
+	if err != nil {
+		t.Fatalf("PlanRangeFill failed: %v", err)
+	}
+	var dates []string
+	for _, entry := range planned {
+		if entry.Id != 0 || entry.Vacation_hours != 8 {
+			t.Errorf("%s: expected a new entry with the template's hours, got %+v", entry.Date, entry)
+		}
+		dates = append(dates, entry.Date)
+	}
+	if want := []string{"2024-03-08", "2024-03-11", "2024-03-12"}; !reflect.DeepEqual(dates, want) {
+		t.Errorf("Expected %v, got %v", want, dates)
+	}
+
+	// Without weekend days every day is filled
+	planned, err = PlanRangeFill("2024-03-08", "2024-03-12", template, nil)
+	if err != nil || len(planned) != 5 {
+		t.Errorf("Expected 5 days without weekend days, got %d (%v)", len(planned), err)
+	}
+
+	if _, err := PlanRangeFill("2024-03-08", "12-03-2024", template, weekend); err == nil {
+		t.Error("Expected an error for an invalid date")
+	}
+	if _, err := PlanRangeFill("2020-01-01", "2024-01-01", template, weekend); err == nil {
+		t.Error("Expected an error for a range of several years")
+	}
+}
+
+func TestSaveRangeFill(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	for _, entry := range []TimesheetEntry{
+		{Date: "2024-03-11", Client_name: "Acme", Client_hours: 8},
+		{Date: "2024-03-12", Vacation_hours: 4},
+	} {
+		if err := AddTimesheetEntry(entry); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+	}
+
+	planned, err := PlanRangeFill("2024-03-11", "2024-03-13", TimesheetEntry{Vacation_hours: 8}, nil)
+	if err != nil {
+		t.Fatalf("PlanRangeFill failed: %v", err)
+	}
+	result, err := SaveRangeFill(&LocalDBLayer{}, planned)
+	if err != nil {
+		t.Fatalf("SaveRangeFill failed: %v", err)
+	}
+	if want := []string{"2024-03-11", "2024-03-13"}; !reflect.DeepEqual(result.Created, want) {
+		t.Errorf("Expected %v to be created, got %v", want, result.Created)
+	}
+	if want := []string{"2024-03-12"}; !reflect.DeepEqual(result.Replaced, want) {
+		t.Errorf("Expected %v to be replaced, got %v", want, result.Replaced)
+	}
+
+	// The Acme block of the 11th stays next to the vacation
+	entries, err := GetTimesheetEntriesByDate("2024-03-11")
+	if err != nil {
+		t.Fatalf("Failed to get entries: %v", err)
+	}
+	if acme, ok := FindClientEntry(entries, "Acme"); len(entries) != 2 || !ok || acme.Client_hours != 8 {
+		t.Errorf("Expected the Acme block to be kept, got %+v", entries)
+	}
+	for _, date := range []string{"2024-03-12", "2024-03-13"} {
+		entry, err := firstEntry(GetTimesheetEntriesByDate(date))
+		if err != nil {
+			t.Fatalf("Failed to get entry for %s: %v", date, err)
+		}
+		if entry.Vacation_hours != 8 {
+			t.Errorf("%s: vacation hours = %g, want 8", date, entry.Vacation_hours)
+		}
+	}
+
+	// The remote layer has no transactions and saves the dates one by one
+	result, err = SaveRangeFill(remoteLayer{DataLayer: &LocalDBLayer{}}, planned)
+	if err != nil {
+		t.Fatalf("SaveRangeFill without transactions failed: %v", err)
+	}
+	if len(result.Replaced) != 3 {
+		t.Errorf("Expected all 3 dates to be replaced, got %+v", result)
+	}
+}
@@ -0,0 +1,166 @@
+package db
+
+import (
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// ErrStatementChanged is returned when a statement is issued for a month
+// that already has a recorded hash and the data no longer matches it
+var ErrStatementChanged = errors.New("a statement with a different hash was already issued for this month")
+
+// StatementEntry is the canonical form of a timesheet entry in a statement.
+// It leaves out ids and timestamps so the hash only depends on the hours.
+type StatementEntry struct {
+	Date          string  `json:"date"`
+	ClientName    string  `json:"client_name"`
+	ClientHours   float64 `json:"client_hours"`
+	VacationHours float64 `json:"vacation_hours"`
+	IdleHours     float64 `json:"idle_hours"`
+	TrainingHours float64 `json:"training_hours"`
+	SickHours     float64 `json:"sick_hours"`
+	HolidayHours  float64 `json:"holiday_hours"`
+	TotalHours    float64 `json:"total_hours"`
+}
+
+// Statement is a tamper-evident monthly statement: the month's entries in
+// canonical order together with the SHA-256 of that entry set
+type Statement struct {
+	Year        int              `json:"year"`
+	Month       int              `json:"month"`
+	GeneratedAt string           `json:"generated_at"`
+	TotalHours  float64          `json:"total_hours"`
+	Entries     []StatementEntry `json:"entries"`
+	SHA256      string           `json:"sha256"`
+}
+
+// StatementVerification compares a recorded statement hash with the current data
+type StatementVerification struct {
+	Year         int    `json:"year"`
+	Month        int    `json:"month"`
+	RecordedHash string `json:"recorded_hash"`
+	RecordedAt   string `json:"recorded_at"`
+	CurrentHash  string `json:"current_hash"`
+	Match        bool   `json:"match"`
+}
+
+// NewStatement builds the statement for a month from its timesheet entries
+func NewStatement(year int, month time.Month, entries []TimesheetEntry) (Statement, error) {
+	canonical := canonicalStatementEntries(entries)
+	hash, err := hashStatementEntries(canonical)
+	if err != nil {
+		return Statement{}, err
+	}
+
+	statement := Statement{
+		Year:        year,
+		Month:       int(month),
+		GeneratedAt: NowTimestamp(),
+		Entries:     canonical,
+		SHA256:      hash,
+	}
+	for _, entry := range canonical {
+		statement.TotalHours += entry.TotalHours
+	}
+	return statement, nil
+}
+
+// canonicalStatementEntries converts entries to statement form, ordered by
+// date and client so storage order never affects the hash
+func canonicalStatementEntries(entries []TimesheetEntry) []StatementEntry {
+	canonical := make([]StatementEntry, 0, len(entries))
+	for _, e := range entries {
+		canonical = append(canonical, StatementEntry{
+			Date:          e.Date,
+			ClientName:    e.Client_name,
+			ClientHours:   e.Client_hours,
+			VacationHours: e.Vacation_hours,
+			IdleHours:     e.Idle_hours,
+			TrainingHours: e.Training_hours,
+			SickHours:     e.Sick_hours,
+			HolidayHours:  e.Holiday_hours,
+			TotalHours:    e.Client_hours + e.Vacation_hours + e.Idle_hours + e.Training_hours + e.Sick_hours + e.Holiday_hours,
+		})
+	}
+	sort.SliceStable(canonical, func(i, j int) bool {
+		if canonical[i].Date != canonical[j].Date {
+			return canonical[i].Date < canonical[j].Date
+		}
+		return canonical[i].ClientName < canonical[j].ClientName
+	})
+	return canonical
+}
+
+// hashStatementEntries returns the hex SHA-256 of the JSON encoding of entries
+func hashStatementEntries(entries []StatementEntry) (string, error) {
+	data, err := json.Marshal(entries)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode statement entries: %w", err)
+	}
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// RecordStatement stores the hash of an issued statement. Issuing the same
+// statement again is a no-op; if the month already has a different hash,
+// ErrStatementChanged is returned and the recorded hash is kept.
+func RecordStatement(statement Statement) error {
+	recorded, _, err := GetStatementHash(statement.Year, time.Month(statement.Month))
+	switch {
+	case err == nil:
+		if recorded != statement.SHA256 {
+			return ErrStatementChanged
+		}
+		return nil
+	case !errors.Is(err, sql.ErrNoRows):
+		return err
+	}
+
+	_, err = db.Exec(`INSERT INTO statements (year, month, sha256, entry_count, created_at) VALUES (?, ?, ?, ?, ?)`,
+		statement.Year, statement.Month, statement.SHA256, len(statement.Entries), statement.GeneratedAt)
+	if err != nil {
+		return fmt.Errorf("failed to record statement: %w", err)
+	}
+	return nil
+}
+
+// GetStatementHash returns the recorded hash of a month's statement and when
+// it was issued. It returns sql.ErrNoRows when no statement was issued.
+func GetStatementHash(year int, month time.Month) (hash, createdAt string, err error) {
+	err = db.QueryRow(`SELECT sha256, created_at FROM statements WHERE year = ? AND month = ?`, year, int(month)).
+		Scan(&hash, &createdAt)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return "", "", fmt.Errorf("failed to query statement: %w", err)
+	}
+	return hash, createdAt, err
+}
+
+// VerifyStatement recomputes the hash of a month's entries and compares it
+// with the recorded statement. It returns sql.ErrNoRows when no statement
+// was issued for the month.
+func VerifyStatement(year int, month time.Month, entries []TimesheetEntry) (StatementVerification, error) {
+	recorded, recordedAt, err := GetStatementHash(year, month)
+	if err != nil {
+		return StatementVerification{}, err
+	}
+
+	current, err := hashStatementEntries(canonicalStatementEntries(entries))
+	if err != nil {
+		return StatementVerification{}, err
+	}
+
+	return StatementVerification{
+		Year:         year,
+		Month:        int(month),
+		RecordedHash: recorded,
+		RecordedAt:   recordedAt,
+		CurrentHash:  current,
+		Match:        recorded == current,
+	}, nil
+}
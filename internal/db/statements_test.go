@@ -0,0 +1,67 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestStatementRecordAndVerify(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-05-02", Client_name: "Acme", Client_hours: 8})
+	AddTimesheetEntry(TimesheetEntry{Date: "2024-05-01", Client_name: "Acme", Client_hours: 6, Training_hours: 2})
+
+	if _, err := VerifyStatement(2024, time.May, nil); !errors.Is(err, sql.ErrNoRows) {
+		t.Fatalf("Expected sql.ErrNoRows before a statement is issued, got %v", err)
+	}
+
+	entries, _ := GetAllTimesheetEntries(2024, time.May)
+	statement, err := NewStatement(2024, time.May, entries)
+	if err != nil {
+		t.Fatalf("NewStatement failed: %v", err)
+	}
+	if len(statement.Entries) != 2 || statement.Entries[0].Date != "2024-05-01" || statement.TotalHours != 16 {
+		t.Errorf("Unexpected statement contents: %+v", statement)
+	}
+	if len(statement.SHA256) != 64 {
+		t.Errorf("Expected a hex SHA-256, got %q", statement.SHA256)
+	}
+
+	// The hash doesn't depend on the order the entries come in
+	reversed := []TimesheetEntry{entries[1], entries[0]}
+	if again, _ := NewStatement(2024, time.May, reversed); again.SHA256 != statement.SHA256 {
+		t.Errorf("Expected the same hash regardless of entry order")
+	}
+
+	if err := RecordStatement(statement); err != nil {
+		t.Fatalf("RecordStatement failed: %v", err)
+	}
+	if err := RecordStatement(statement); err != nil {
+		t.Errorf("Re-issuing an unchanged statement should succeed, got %v", err)
+	}
+
+	result, err := VerifyStatement(2024, time.May, entries)
+	if err != nil || !result.Match {
+		t.Fatalf("Expected unchanged data to verify, got %+v, %v", result, err)
+	}
+
+	// Change an entry after the statement was issued
+	UpdateTimesheetEntry(TimesheetEntry{Date: "2024-05-02", Client_name: "Acme", Client_hours: 7})
+	entries, _ = GetAllTimesheetEntries(2024, time.May)
+
+	result, err = VerifyStatement(2024, time.May, entries)
+	if err != nil {
+		t.Fatalf("VerifyStatement failed: %v", err)
+	}
+	if result.Match || result.RecordedHash != statement.SHA256 {
+		t.Errorf("Expected a mismatch against the recorded hash, got %+v", result)
+	}
+
+	changed, _ := NewStatement(2024, time.May, entries)
+	if err := RecordStatement(changed); !errors.Is(err, ErrStatementChanged) {
+		t.Errorf("Expected ErrStatementChanged, got %v", err)
+	}
+}
@@ -8,18 +8,15 @@ import (
 // GetTrainingEntriesForYear retrieves all training entries for a specific year
 func GetTrainingEntriesForYear(year int) ([]TimesheetEntry, error) {
 	// Calculate start and end dates for the year
-	startDate := fmt.Sprintf("%d-01-01", year)
-	endDate := fmt.Sprintf("%d-12-31", year)
+	startDate, endDate := yearDateBounds(year)
 
 	// Query the database
 	rows, err := db.Query(`
-		SELECT id, date, client_name, client_hours, training_hours, vacation_hours, 
-		       idle_hours, holiday_hours, sick_hours,
-		       (client_hours + training_hours + vacation_hours + idle_hours + holiday_hours + sick_hours) as total_hours
+		SELECT `+timesheetSelectColumns+`
 		FROM timesheet
-		WHERE date BETWEEN ? AND ?
-		AND training_hours > 0
-		ORDER BY date DESC
+		WHERE date >= ? AND date < ?
+		AND COALESCE(training_hours, 0) > 0
+		ORDER BY date DESC, client_name ASC
 	`, startDate, endDate)
 	if err != nil {
 		return nil, err
@@ -36,12 +33,13 @@ func GetTrainingEntriesForYear(year int) ([]TimesheetEntry, error) {
 			&entry.Date,
 			&entry.Client_name,
 			&entry.Client_hours,
-			&entry.Training_hours,
 			&entry.Vacation_hours,
 			&entry.Idle_hours,
-			&entry.Holiday_hours,
+			&entry.Training_hours,
 			&entry.Sick_hours,
+			&entry.Holiday_hours,
 			&entry.Total_hours,
+			&entry.Note,
 		)
 		if err != nil {
 			return nil, err
@@ -57,8 +55,9 @@ type TrainingBudgetEntry struct {
 	Id               int
 	Date             string
 	Training_name    string
-	Hours            int
+	Hours            float64
 	Cost_without_vat float64
+	Receipt_path     string // Attached receipt file, empty when there is none
 }
 
 // GetTrainingBudgetEntriesForYear retrieves all training budget entries for a specific year
@@ -69,7 +68,7 @@ func GetTrainingBudgetEntriesForYear(year int) ([]TrainingBudgetEntry, error) {
 
 	// Query the database
 	rows, err := db.Query(`
-		SELECT id, date, training_name, hours, cost_without_vat
+		SELECT id, date, training_name, hours, cost_without_vat, COALESCE(receipt_path, '')
 		FROM training_budget
 		WHERE date BETWEEN ? AND ?
 		ORDER BY date DESC
@@ -90,6 +89,7 @@ func GetTrainingBudgetEntriesForYear(year int) ([]TrainingBudgetEntry, error) {
 			&entry.Training_name,
 			&entry.Hours,
 			&entry.Cost_without_vat,
+			&entry.Receipt_path,
 		)
 		if err != nil {
 			return nil, err
@@ -100,16 +100,45 @@ func GetTrainingBudgetEntriesForYear(year int) ([]TrainingBudgetEntry, error) {
 	return entries, nil
 }
 
+// TrainingBudgetSpent is what a year's training budget entries cost
+type TrainingBudgetSpent struct {
+	Spent      float64 `json:"spent"` // Sum of cost_without_vat
+	EntryCount int     `json:"entry_count"`
+}
+
+// GetTrainingBudgetSpentForYear adds up the cost of a year's training
+// budget entries
+func GetTrainingBudgetSpentForYear(year int) (TrainingBudgetSpent, error) {
+	return queryTrainingBudgetSpent(db, func(int) string { return "?" }, year)
+}
+
+// queryTrainingBudgetSpent runs GetTrainingBudgetSpentForYear on conn.
+// placeholder returns the driver's nth parameter ("?" or "$n").
+func queryTrainingBudgetSpent(conn *sql.DB, placeholder func(n int) string, year int) (TrainingBudgetSpent, error) {
+	var spent TrainingBudgetSpent
+	start, end := yearDateBounds(year)
+	err := conn.QueryRow(`
+		SELECT COALESCE(SUM(cost_without_vat), 0), COUNT(*)
+		FROM training_budget
+		WHERE date >= `+placeholder(1)+` AND date < `+placeholder(2), start, end).Scan(
+		&spent.Spent, &spent.EntryCount)
+	if err != nil {
+		return TrainingBudgetSpent{}, fmt.Errorf("failed to total training budget for %d: %w", year, err)
+	}
+	return spent, nil
+}
+
 // AddTrainingBudgetEntry adds a new training budget entry
 func AddTrainingBudgetEntry(entry TrainingBudgetEntry) error {
 	now := NowTimestamp()
-	query := `INSERT INTO training_budget (date, training_name, hours, cost_without_vat, created_at, updated_at)
-              VALUES (?, ?, ?, ?, ?, ?)`
+	query := `INSERT INTO training_budget (date, training_name, hours, cost_without_vat, receipt_path, created_at, updated_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?)`
 	_, err := db.Exec(query,
 		entry.Date,
 		entry.Training_name,
 		entry.Hours,
 		entry.Cost_without_vat,
+		entry.Receipt_path,
 		now, now)
 	return err
 }
@@ -117,13 +146,14 @@ func AddTrainingBudgetEntry(entry TrainingBudgetEntry) error {
 // UpdateTrainingBudgetEntry updates an existing training budget entry
 func UpdateTrainingBudgetEntry(entry TrainingBudgetEntry) error {
 	query := `UPDATE training_budget
-              SET date = ?, training_name = ?, hours = ?, cost_without_vat = ?, updated_at = ?
+              SET date = ?, training_name = ?, hours = ?, cost_without_vat = ?, receipt_path = ?, updated_at = ?
               WHERE id = ?`
 	_, err := db.Exec(query,
 		entry.Date,
 		entry.Training_name,
 		entry.Hours,
 		entry.Cost_without_vat,
+		entry.Receipt_path,
 		NowTimestamp(),
 		entry.Id)
 	return err
@@ -159,7 +189,7 @@ func DeleteTrainingBudgetEntry(id int) error {
 
 // GetTrainingBudgetEntry retrieves a single training budget entry by ID
 func GetTrainingBudgetEntry(id int) (TrainingBudgetEntry, error) {
-	query := `SELECT id, date, training_name, hours, cost_without_vat
+	query := `SELECT id, date, training_name, hours, cost_without_vat, COALESCE(receipt_path, '')
               FROM training_budget WHERE id = ?`
 
 	var entry TrainingBudgetEntry
@@ -169,6 +199,7 @@ func GetTrainingBudgetEntry(id int) (TrainingBudgetEntry, error) {
 		&entry.Training_name,
 		&entry.Hours,
 		&entry.Cost_without_vat,
+		&entry.Receipt_path,
 	)
 	if err != nil {
 		return TrainingBudgetEntry{}, err
@@ -179,7 +210,7 @@ func GetTrainingBudgetEntry(id int) (TrainingBudgetEntry, error) {
 
 // GetTrainingBudgetEntryByDate retrieves a single training budget entry by date
 func GetTrainingBudgetEntryByDate(date string) (TrainingBudgetEntry, error) {
-	query := `SELECT id, date, training_name, hours, cost_without_vat
+	query := `SELECT id, date, training_name, hours, cost_without_vat, COALESCE(receipt_path, '')
               FROM training_budget WHERE date = ?`
 
 	var entry TrainingBudgetEntry
@@ -189,6 +220,7 @@ func GetTrainingBudgetEntryByDate(date string) (TrainingBudgetEntry, error) {
 		&entry.Training_name,
 		&entry.Hours,
 		&entry.Cost_without_vat,
+		&entry.Receipt_path,
 	)
 	if err != nil {
 		return TrainingBudgetEntry{}, err
@@ -0,0 +1,336 @@
+package db
+
+import (
+	"database/sql"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// timesheetImportColumns are the CSV columns ImportTimesheetCSV reads, in order
+var timesheetImportColumns = []string{"date", "client", "client_hours", "vacation_hours", "idle_hours", "training_hours", "sick_hours", "holiday_hours"}
+
+// timesheetImportAliases maps the other column names a header may use, such
+// as those of the CSV export, to timesheetImportColumns. Header columns
+// that match neither (e.g. the export's Day and Total) are ignored.
+var timesheetImportAliases = map[string]string{
+	"client_name": "client",
+	"hours":       "client_hours",
+	"vacation":    "vacation_hours",
+	"idle":        "idle_hours",
+	"training":    "training_hours",
+	"sick":        "sick_hours",
+	"holiday":     "holiday_hours",
+}
+
+// TimesheetImportIssue describes a CSV row that was not imported
+type TimesheetImportIssue struct {
+	Line   int    `json:"line"`
+	Reason string `json:"reason"`
+}
+
+// TimesheetImportConflict reports how a row whose date and client were
+// already taken was resolved
+type TimesheetImportConflict struct {
+	Line       int    `json:"line"`
+	Date       string `json:"date"`
+	With       string `json:"with"`       // "existing entry", or the earlier CSV line with the same date and client
+	Resolution string `json:"resolution"` // skipped, replaced, merged or summed
+}
+
+// TimesheetImportReport summarizes the outcome of ImportTimesheetCSV
+type TimesheetImportReport struct {
+	Policy    OverwritePolicy           `json:"policy"`
+	Created   int                       `json:"created"`
+	Replaced  int                       `json:"replaced"`
+	Merged    int                       `json:"merged"`
+	Summed    int                       `json:"summed"`
+	Skipped   int                       `json:"skipped"`
+	Conflicts []TimesheetImportConflict `json:"conflicts"`
+	Unmatched []TimesheetImportIssue    `json:"unmatched"`
+}
+
+// ImportTimesheetCSV reads timesheet entries from CSV and saves them in a
+// single transaction.
+//
+// Expected columns: date (YYYY-MM-DD), client, client_hours, vacation_hours,
+// idle_hours, training_hours, sick_hours and holiday_hours; missing trailing
+// hour columns count as 0. A header row, when present, may name the columns
+// in another order or as the CSV export does, so an exported month can be
+// imported again; rows of days without an entry are skipped. A date and
+// client that are already in the database, or that appear earlier in the
+// file, are resolved with policy and reported as a conflict; another client
+// on the same date is added as a separate block. Malformed rows, and rows
+// whose merged or summed block the day limit rejects, are reported as
+// unmatched. Any database error rolls back the whole import.
+func ImportTimesheetCSV(r io.Reader, policy OverwritePolicy) (TimesheetImportReport, error) {
+	report := TimesheetImportReport{Policy: policy}
+
+	reader := csv.NewReader(r)
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+	records, err := reader.ReadAll()
+	if err != nil {
+		return report, fmt.Errorf("failed to read CSV: %w", err)
+	}
+
+	err = WithTransaction(func(tx *sql.Tx) error {
+		// Line of the row that wrote each date and client in this import
+		importedOn := map[string]int{}
+
+		// Column of timesheetImportColumns each CSV column holds, from the
+		// header; nil reads the columns in their default order
+		var layout []int
+
+		for i, record := range records {
+			line := i + 1
+			if i == 0 && isTimesheetImportHeader(record) {
+				layout = timesheetImportLayout(record)
+				continue
+			}
+			if len(record) == 0 || (len(record) == 1 && strings.TrimSpace(record[0]) == "") {
+				continue
+			}
+
+			fields, err := arrangeTimesheetImportRow(record, layout)
+			if err == nil && isEmptyTimesheetImportDay(fields) {
+				continue
+			}
+			var entry TimesheetEntry
+			if err == nil {
+				entry, err = parseTimesheetImportRow(tx, fields)
+			}
+			if err != nil {
+				report.Unmatched = append(report.Unmatched, TimesheetImportIssue{Line: line, Reason: err.Error()})
+				continue
+			}
+
+			existing, err := getTimesheetEntryTx(tx, entry.Date, entry.Client_name)
+			if errors.Is(err, sql.ErrNoRows) {
+				if err := insertTimesheetEntryTx(tx, entry); err != nil {
+					return err
+				}
+				report.Created++
+				importedOn[TombstoneKeyTimesheet(entry.Date, entry.Client_name)] = line
+				continue
+			}
+			if err != nil {
+				return err
+			}
+
+			conflict := TimesheetImportConflict{Line: line, Date: entry.Date, With: "existing entry"}
+			if earlier, ok := importedOn[TombstoneKeyTimesheet(entry.Date, entry.Client_name)]; ok {
+				conflict.With = fmt.Sprintf("line %d", earlier)
+			}
+
+			var updated TimesheetEntry
+			switch policy {
+			case OverwriteReplace:
+				updated, conflict.Resolution = entry, "replaced"
+			case OverwriteMerge:
+				updated = MergeTimesheetEntries(existing, entry)
+				if updated == existing {
+					conflict.Resolution = "skipped"
+				} else {
+					conflict.Resolution = "merged"
+				}
+			case OverwriteSum:
+				updated, conflict.Resolution = SumTimesheetEntries(existing, entry), "summed"
+			default:
+				conflict.Resolution = "skipped"
+			}
+
+			// Only the row itself has been checked so far; a merged or
+			// summed block must stay within the day limit too
+			if conflict.Resolution != "skipped" {
+				updated, err = prepareTimesheetEntry(tx, updated)
+				if err != nil {
+					report.Unmatched = append(report.Unmatched, TimesheetImportIssue{Line: line, Reason: err.Error()})
+					continue
+				}
+			}
+
+			switch conflict.Resolution {
+			case "replaced":
+				report.Replaced++
+			case "merged":
+				report.Merged++
+			case "summed":
+				report.Summed++
+			default:
+				report.Skipped++
+			}
+			report.Conflicts = append(report.Conflicts, conflict)
+
+			if conflict.Resolution == "skipped" {
+				continue
+			}
+			if err := updateTimesheetEntryTx(tx, updated); err != nil {
+				return err
+			}
+			importedOn[TombstoneKeyTimesheet(entry.Date, entry.Client_name)] = line
+		}
+		return nil
+	})
+	return report, err
+}
+
+// isTimesheetImportHeader reports whether the first CSV row is a header
+func isTimesheetImportHeader(record []string) bool {
+	return len(record) > 0 && timesheetImportColumnName(record[0]) == timesheetImportColumns[0]
+}
+
+// timesheetImportColumnName normalizes a header cell, dropping the byte
+// order mark the CSV export starts with, and resolves aliases
+func timesheetImportColumnName(cell string) string {
+	name := strings.ToLower(strings.TrimSpace(strings.TrimPrefix(cell, utf8BOM)))
+	if alias, ok := timesheetImportAliases[name]; ok {
+		return alias
+	}
+	return name
+}
+
+// utf8BOM is the byte order mark CSV files written for Excel start with
+const utf8BOM = "\ufeff"
+
+// timesheetImportLayout maps each header column to its index in
+// timesheetImportColumns, or -1 for a column that isn't imported
+func timesheetImportLayout(header []string) []int {
+	layout := make([]int, len(header))
+	for i, cell := range header {
+		layout[i] = -1
+		name := timesheetImportColumnName(cell)
+		for j, column := range timesheetImportColumns {
+			if name == column {
+				layout[i] = j
+				break
+			}
+		}
+	}
+	return layout
+}
+
+// arrangeTimesheetImportRow puts the cells of record in the order of
+// timesheetImportColumns according to layout
+func arrangeTimesheetImportRow(record []string, layout []int) ([]string, error) {
+	if layout == nil {
+		return record, nil
+	}
+	if len(record) > len(layout) {
+		return nil, fmt.Errorf("too many columns (%d, expected at most %d)", len(record), len(layout))
+	}
+	fields := make([]string, len(timesheetImportColumns))
+	for i, cell := range record {
+		if layout[i] >= 0 {
+			fields[layout[i]] = cell
+		}
+	}
+	return fields, nil
+}
+
+// isEmptyTimesheetImportDay reports whether a row only has a date, like the
+// CSV export's rows for days without an entry
+func isEmptyTimesheetImportDay(fields []string) bool {
+	for _, cell := range fields[1:] {
+		if strings.TrimSpace(cell) != "" {
+			return false
+		}
+	}
+	return len(fields) > 1
+}
+
+// parseTimesheetImportRow validates a CSV row and converts it to an entry.
+// The day limit counts the other blocks tx holds on the row's date.
+func parseTimesheetImportRow(tx *sql.Tx, record []string) (TimesheetEntry, error) {
+	if len(record) > len(timesheetImportColumns) {
+		return TimesheetEntry{}, fmt.Errorf("too many columns (%d, expected at most %d)", len(record), len(timesheetImportColumns))
+	}
+
+	date := strings.TrimSpace(record[0])
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return TimesheetEntry{}, fmt.Errorf("invalid date %q (expected YYYY-MM-DD)", date)
+	}
+	entry := TimesheetEntry{Date: date}
+	if len(record) > 1 {
+		entry.Client_name = strings.TrimSpace(record[1])
+	}
+
+	hours := []*float64{&entry.Client_hours, &entry.Vacation_hours, &entry.Idle_hours,
+		&entry.Training_hours, &entry.Sick_hours, &entry.Holiday_hours}
+	for i, field := range hours {
+		col := i + 2
+		if col >= len(record) || strings.TrimSpace(record[col]) == "" {
+			continue
+		}
+		value, err := strconv.ParseFloat(strings.TrimSpace(record[col]), 64)
+		if err != nil || value < 0 {
+			return TimesheetEntry{}, fmt.Errorf("invalid %s %q", timesheetImportColumns[col], record[col])
+		}
+		*field = value
+	}
+	entry, err := prepareTimesheetEntry(tx, entry)
+	if errors.Is(err, ErrClientNameRequired) {
+		return TimesheetEntry{}, ErrClientNameRequired
+	}
+	if err != nil {
+		return TimesheetEntry{}, err
+	}
+	return entry, nil
+}
+
+// getTimesheetEntryTx looks up the block of clientName on date inside tx
+func getTimesheetEntryTx(tx *sql.Tx, date, clientName string) (TimesheetEntry, error) {
+	var entry TimesheetEntry
+	err := tx.QueryRow("SELECT "+timesheetSelectColumns+" FROM timesheet WHERE date = ? AND client_name = ?", date, clientName).Scan(
+		&entry.Id,
+		&entry.Date,
+		&entry.Client_name,
+		&entry.Client_hours,
+		&entry.Vacation_hours,
+		&entry.Idle_hours,
+		&entry.Training_hours,
+		&entry.Sick_hours,
+		&entry.Holiday_hours,
+		&entry.Total_hours,
+		&entry.Note,
+	)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return TimesheetEntry{}, fmt.Errorf("failed to query entry for %s: %w", date, err)
+	}
+	// Callers compare entries, so leave out the derived total
+	entry.Total_hours = 0
+	return entry, err
+}
+
+// insertTimesheetEntryTx adds an entry inside tx
+func insertTimesheetEntryTx(tx *sql.Tx, entry TimesheetEntry) error {
+	now := NowTimestamp()
+	_, err := tx.Exec(`INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, note, created_at, updated_at)
+              VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`,
+		entry.Date, entry.Client_name, entry.Client_hours, entry.Vacation_hours, entry.Idle_hours,
+		entry.Training_hours, entry.Sick_hours, entry.Holiday_hours, entry.Note, now, now)
+	if err != nil {
+		return fmt.Errorf("failed to create entry for %s: %w", entry.Date, err)
+	}
+	return nil
+}
+
+// updateTimesheetEntryTx overwrites the block of entry.Client_name on
+// entry.Date inside tx
+func updateTimesheetEntryTx(tx *sql.Tx, entry TimesheetEntry) error {
+	_, err := tx.Exec(`UPDATE timesheet
+              SET client_hours = ?,
+                  vacation_hours = ?, idle_hours = ?, training_hours = ?, sick_hours = ?, holiday_hours = ?,
+                  note = ?, updated_at = ?
+              WHERE date = ? AND client_name = ?`,
+		entry.Client_hours, entry.Vacation_hours, entry.Idle_hours,
+		entry.Training_hours, entry.Sick_hours, entry.Holiday_hours, entry.Note, NowTimestamp(), entry.Date, entry.Client_name)
+	if err != nil {
+		return fmt.Errorf("failed to update entry for %s: %w", entry.Date, err)
+	}
+	return nil
+}
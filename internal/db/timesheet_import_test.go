@@ -0,0 +1,205 @@
+package db
+
+import (
+	"strings"
+	"testing"
+	"timesheet/internal/config"
+)
+
+const timesheetImportCSV = `date,client,client_hours,vacation_hours,idle_hours,training_hours,sick_hours,holiday_hours
+2024-03-04,Acme,8
+2024-03-05,Acme,6,0,0,2
+2024-03-05,Acme,2
+2024-03-06,Acme,eight
+03/07/2024,Acme,8
+`
+
+func TestImportTimesheetCSV(t *testing.T) {
+	policies := []struct {
+		policy       OverwritePolicy
+		wantExisting float64 // client hours on 2024-03-04, which is already in the DB with 4
+		wantRepeated float64 // client hours on 2024-03-05, which appears twice in the CSV
+		resolution   string
+	}{
+		{OverwriteSkip, 4, 6, "skipped"},
+		{OverwriteReplace, 8, 2, "replaced"},
+		{OverwriteSum, 12, 8, "summed"},
+	}
+
+	for _, p := range policies {
+		t.Run(string(p.policy), func(t *testing.T) {
+			dbPath := setupTestDB(t)
+			defer teardownTestDB(t, dbPath)
+
+			if err := AddTimesheetEntry(TimesheetEntry{Date: "2024-03-04", Client_name: "Acme", Client_hours: 4}); err != nil {
+				t.Fatalf("Failed to add entry: %v", err)
+			}
+
+			report, err := ImportTimesheetCSV(strings.NewReader(timesheetImportCSV), p.policy)
+			if err != nil {
+				t.Fatalf("ImportTimesheetCSV failed: %v", err)
+			}
+
+			if report.Created != 1 {
+				t.Errorf("Expected 1 created entry, got %d", report.Created)
+			}
+			if len(report.Unmatched) != 2 {
+				t.Errorf("Expected 2 unmatched rows, got %v", report.Unmatched)
+			}
+			if len(report.Conflicts) != 2 {
+				t.Fatalf("Expected 2 conflicts, got %v", report.Conflicts)
+			}
+			if c := report.Conflicts[0]; c.Line != 2 || c.With != "existing entry" || c.Resolution != p.resolution {
+				t.Errorf("Unexpected conflict for the existing date: %+v", c)
+			}
+			if c := report.Conflicts[1]; c.Line != 4 || c.With != "line 3" || c.Resolution != p.resolution {
+				t.Errorf("Unexpected conflict for the repeated date: %+v", c)
+			}
+
+			existing, err := firstEntry(GetTimesheetEntriesByDate("2024-03-04"))
+			if err != nil {
+				t.Fatalf("Failed to get entry: %v", err)
+			}
+			if existing.Client_hours != p.wantExisting {
+				t.Errorf("Expected %g client hours on 2024-03-04, got %g", p.wantExisting, existing.Client_hours)
+			}
+			repeated, err := firstEntry(GetTimesheetEntriesByDate("2024-03-05"))
+			if err != nil {
+				t.Fatalf("Failed to get entry: %v", err)
+			}
+			if repeated.Client_hours != p.wantRepeated {
+				t.Errorf("Expected %g client hours on 2024-03-05, got %g", p.wantRepeated, repeated.Client_hours)
+			}
+		})
+	}
+}
+
+func TestImportTimesheetCSVMergeKeepsExistingValues(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	if err := AddTimesheetEntry(TimesheetEntry{Date: "2024-03-04", Client_name: "Acme", Client_hours: 8}); err != nil {
+		t.Fatalf("Failed to add entry: %v", err)
+	}
+
+	csvData := "2024-03-04,Acme,6,0,0,2\n2024-03-04,Acme,4\n"
+	report, err := ImportTimesheetCSV(strings.NewReader(csvData), OverwriteMerge)
+	if err != nil {
+		t.Fatalf("ImportTimesheetCSV failed: %v", err)
+	}
+	if report.Merged != 1 || report.Skipped != 1 {
+		t.Errorf("Expected 1 merged and 1 skipped row, got %d merged and %d skipped", report.Merged, report.Skipped)
+	}
+
+	entry, err := firstEntry(GetTimesheetEntriesByDate("2024-03-04"))
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if entry.Client_hours != 8 || entry.Training_hours != 2 {
+		t.Errorf("Expected 8 client and 2 training hours, got %g and %g", entry.Client_hours, entry.Training_hours)
+	}
+}
+
+func TestImportTimesheetCSVSumRespectsDailyLimit(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+	cleanup := setupTestConfig(t, 0)
+	defer cleanup()
+
+	// Each row fits in a day on its own, but not summed
+	csvData := "2024-03-04,Acme,16\n2024-03-04,Acme,16\n"
+	report, err := ImportTimesheetCSV(strings.NewReader(csvData), OverwriteSum)
+	if err != nil {
+		t.Fatalf("ImportTimesheetCSV failed: %v", err)
+	}
+	if report.Created != 1 || report.Summed != 0 || len(report.Unmatched) != 1 || report.Unmatched[0].Line != 2 {
+		t.Errorf("Expected line 1 created and line 2 rejected, got %+v", report)
+	}
+	entry, err := firstEntry(GetTimesheetEntriesByDate("2024-03-04"))
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if entry.Client_hours != 16 {
+		t.Errorf("Expected the first row's 16 hours to be kept, got %g", entry.Client_hours)
+	}
+
+	if err := config.SaveConfig(config.Config{OverLimitBehavior: config.OverLimitClamp}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	report, err = ImportTimesheetCSV(strings.NewReader("2024-03-04,Acme,16\n"), OverwriteSum)
+	if err != nil {
+		t.Fatalf("ImportTimesheetCSV failed: %v", err)
+	}
+	if report.Summed != 1 || len(report.Unmatched) != 0 {
+		t.Errorf("Expected the row to be summed and clamped, got %+v", report)
+	}
+	entry, err = firstEntry(GetTimesheetEntriesByDate("2024-03-04"))
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if entry.Client_hours != MaxDailyHours {
+		t.Errorf("Expected the sum to be clamped to %d hours, got %g", MaxDailyHours, entry.Client_hours)
+	}
+}
+
+func TestImportTimesheetCSVHalfHours(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	csvData := "2024-03-04,Acme,4.5,0,0,3.5\n2024-03-05,Acme,4.25\n"
+	report, err := ImportTimesheetCSV(strings.NewReader(csvData), OverwriteSkip)
+	if err != nil {
+		t.Fatalf("ImportTimesheetCSV failed: %v", err)
+	}
+	if report.Created != 1 || len(report.Unmatched) != 1 || report.Unmatched[0].Line != 2 {
+		t.Errorf("Expected line 1 created and line 2 unmatched, got %+v", report)
+	}
+
+	entry, err := firstEntry(GetTimesheetEntriesByDate("2024-03-04"))
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if entry.Client_hours != 4.5 || entry.Training_hours != 3.5 {
+		t.Errorf("Expected 4.5 client and 3.5 training hours, got %g and %g", entry.Client_hours, entry.Training_hours)
+	}
+}
+
+func TestImportTimesheetCSVExportFormat(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	// As written by the CSV export: a byte order mark, a Day and a Total
+	// column, the hour columns in another order and empty days
+	exported := "\ufeff" +
+		"Date,Day,Client,Hours,Training,Vacation,Idle,Holiday,Sick,Total\n" +
+		"2024-03-01,Friday,\"Café, Inc\",7.5,0,0,0.5,0,0,8\n" +
+		"2024-03-02,Saturday,,,,,,,,\n" +
+		"2024-03-04,Monday,Vacation,0,0,8,0,0,0,8\n" +
+		"2024-03-05,Tuesday,Acme,8,0,0,0,0,0,8,extra\n"
+
+	report, err := ImportTimesheetCSV(strings.NewReader(exported), OverwriteSkip)
+	if err != nil {
+		t.Fatalf("ImportTimesheetCSV failed: %v", err)
+	}
+	if report.Created != 2 {
+		t.Errorf("Expected 2 created entries, got %d", report.Created)
+	}
+	if len(report.Unmatched) != 1 || report.Unmatched[0].Line != 5 {
+		t.Errorf("Expected line 5 to be unmatched, got %v", report.Unmatched)
+	}
+
+	entry, err := firstEntry(GetTimesheetEntriesByDate("2024-03-01"))
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if entry.Client_name != "Café, Inc" || entry.Client_hours != 7.5 || entry.Idle_hours != 0.5 {
+		t.Errorf("Unexpected entry for 2024-03-01: %+v", entry)
+	}
+	vacation, err := firstEntry(GetTimesheetEntriesByDate("2024-03-04"))
+	if err != nil {
+		t.Fatalf("Failed to get entry: %v", err)
+	}
+	if vacation.Vacation_hours != 8 || vacation.Client_hours != 0 {
+		t.Errorf("Unexpected entry for 2024-03-04: %+v", vacation)
+	}
+}
@@ -0,0 +1,78 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// GetTimesheetEntriesInRange returns a page of the timesheet entries dated
+// from through to (both YYYY-MM-DD and inclusive; an empty bound is open),
+// ordered by date, together with the number of entries in the whole range.
+// limit 0 returns every entry from offset on.
+func GetTimesheetEntriesInRange(from, to string, limit, offset int) ([]TimesheetEntry, int, error) {
+	return queryTimesheetRange(db, func(int) string { return "?" }, from, to, limit, offset)
+}
+
+// queryTimesheetRange runs GetTimesheetEntriesInRange on conn. placeholder
+// returns the driver's nth parameter ("?" or "$n").
+func queryTimesheetRange(conn *sql.DB, placeholder func(n int) string, from, to string, limit, offset int) ([]TimesheetEntry, int, error) {
+	var where string
+	var args []any
+	if from != "" {
+		args = append(args, from)
+		where += " AND date >= " + placeholder(len(args))
+	}
+	if to != "" {
+		// Compare against the next day so dates stored with a time suffix
+		// on the last day are included
+		day, err := time.Parse("2006-01-02", to)
+		if err != nil {
+			return nil, 0, fmt.Errorf("invalid to date %q (expected YYYY-MM-DD)", to)
+		}
+		args = append(args, day.AddDate(0, 0, 1).Format("2006-01-02"))
+		where += " AND date < " + placeholder(len(args))
+	}
+
+	var total int
+	if err := conn.QueryRow("SELECT COUNT(*) FROM timesheet WHERE 1 = 1"+where, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count timesheet entries: %w", err)
+	}
+
+	query := "SELECT " + timesheetSelectColumns + " FROM timesheet WHERE 1 = 1" + where + " ORDER BY date ASC, client_name ASC"
+	if limit > 0 {
+		args = append(args, limit)
+		query += " LIMIT " + placeholder(len(args))
+	}
+	if offset > 0 {
+		if limit <= 0 {
+			// SQLite needs a LIMIT before OFFSET; -1 there and ALL in
+			// Postgres mean no limit
+			if placeholder(1) == "?" {
+				query += " LIMIT -1"
+			} else {
+				query += " LIMIT ALL"
+			}
+		}
+		args = append(args, offset)
+		query += " OFFSET " + placeholder(len(args))
+	}
+
+	rows, err := conn.Query(query, args...)
+	if err != nil {
+		return nil, 0, err
+	}
+	defer rows.Close()
+
+	entries := []TimesheetEntry{}
+	for rows.Next() {
+		var entry TimesheetEntry
+		if err := rows.Scan(&entry.Id, &entry.Date, &entry.Client_name, &entry.Client_hours,
+			&entry.Vacation_hours, &entry.Idle_hours, &entry.Training_hours, &entry.Sick_hours,
+			&entry.Holiday_hours, &entry.Total_hours, &entry.Note); err != nil {
+			return nil, 0, err
+		}
+		entries = append(entries, entry)
+	}
+	return entries, total, rows.Err()
+}
@@ -0,0 +1,109 @@
+package db
+
+import (
+	"sort"
+	"time"
+	"timesheet/internal/workschedule"
+)
+
+// ClientStats summarizes the client hours logged for one client
+type ClientStats struct {
+	Name    string  `json:"name"`
+	Hours   float64 `json:"hours"`
+	Days    int     `json:"days"`
+	Percent float64 `json:"percent"` // Share of the year's client hours
+}
+
+// TimesheetStats is a personal-analytics summary of a year's timesheet
+type TimesheetStats struct {
+	Year               int           `json:"year"`
+	DaysWorked         int           `json:"days_worked"` // Days with client hours
+	TotalClientHours   float64       `json:"total_client_hours"`
+	AverageClientHours float64       `json:"average_client_hours"` // Per worked day
+	LongestStreak      int           `json:"longest_streak"`       // Consecutive worked workdays
+	LongestStreakStart string        `json:"longest_streak_start,omitempty"`
+	LongestStreakEnd   string        `json:"longest_streak_end,omitempty"`
+	Clients            []ClientStats `json:"clients"` // Most hours first
+}
+
+// ComputeTimesheetStats summarizes entries for year. A streak counts
+// consecutive days with client hours; days the schedule has no hours for and
+// public holidays are skipped instead of breaking it.
+func ComputeTimesheetStats(year int, entries []TimesheetEntry, schedule workschedule.Schedule) TimesheetStats {
+	stats := TimesheetStats{Year: year, Clients: []ClientStats{}}
+
+	worked := map[string]bool{}
+	holidays := map[string]bool{}
+	byClient := map[string]*ClientStats{}
+
+	for _, entry := range entries {
+		date := entry.Date
+		if len(date) > 10 {
+			date = date[:10]
+		}
+		if entry.Holiday_hours > 0 {
+			holidays[date] = true
+		}
+		if entry.Client_hours <= 0 {
+			continue
+		}
+
+		if !worked[date] {
+			worked[date] = true
+			stats.DaysWorked++
+		}
+		stats.TotalClientHours += entry.Client_hours
+
+		client, ok := byClient[entry.Client_name]
+		if !ok {
+			client = &ClientStats{Name: entry.Client_name}
+			byClient[entry.Client_name] = client
+		}
+		client.Hours += entry.Client_hours
+		client.Days++
+	}
+
+	if stats.DaysWorked > 0 {
+		stats.AverageClientHours = float64(stats.TotalClientHours) / float64(stats.DaysWorked)
+	}
+
+	for _, client := range byClient {
+		if stats.TotalClientHours > 0 {
+			client.Percent = float64(client.Hours) * 100 / float64(stats.TotalClientHours)
+		}
+		stats.Clients = append(stats.Clients, *client)
+	}
+	sort.Slice(stats.Clients, func(i, j int) bool {
+		if stats.Clients[i].Hours != stats.Clients[j].Hours {
+			return stats.Clients[i].Hours > stats.Clients[j].Hours
+		}
+		return stats.Clients[i].Name < stats.Clients[j].Name
+	})
+
+	// Walk the calendar to find the longest run of worked workdays
+	var current int
+	var currentStart string
+	first := time.Date(year, 1, 1, 0, 0, 0, 0, time.UTC)
+	last := time.Date(year, 12, 31, 0, 0, 0, 0, time.UTC)
+	for day := first; !day.After(last); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		switch {
+		case worked[date]:
+			if current == 0 {
+				currentStart = date
+			}
+			current++
+			if current > stats.LongestStreak {
+				stats.LongestStreak = current
+				stats.LongestStreakStart = currentStart
+				stats.LongestStreakEnd = date
+			}
+		case schedule[day.Weekday()] == 0 || holidays[date]:
+			// Not a workday, so it doesn't break the streak
+		default:
+			current = 0
+		}
+	}
+
+	return stats
+}
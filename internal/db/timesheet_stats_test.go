@@ -0,0 +1,57 @@
+package db
+
+import (
+	"testing"
+	"timesheet/internal/workschedule"
+)
+
+func TestComputeTimesheetStats(t *testing.T) {
+	entries := []TimesheetEntry{
+		{Date: "2024-01-01", Holiday_hours: 8},
+		{Date: "2024-01-02", Client_name: "Acme", Client_hours: 8},
+		{Date: "2024-01-03", Client_name: "Acme", Client_hours: 8},
+		// Thursday is not on the default schedule
+		{Date: "2024-01-05", Client_name: "Beta", Client_hours: 4},
+		{Date: "2024-01-08", Client_name: "Acme", Client_hours: 6},
+		// Tuesday 2024-01-09 missing: breaks the streak
+		{Date: "2024-01-10", Client_name: "Acme", Vacation_hours: 8},
+		{Date: "2024-01-12", Client_name: "Beta", Client_hours: 6},
+	}
+
+	stats := ComputeTimesheetStats(2024, entries, workschedule.Default())
+
+	if stats.DaysWorked != 5 {
+		t.Errorf("Expected 5 days worked, got %d", stats.DaysWorked)
+	}
+	if stats.TotalClientHours != 32 {
+		t.Errorf("Expected 32 client hours, got %g", stats.TotalClientHours)
+	}
+	if stats.AverageClientHours != 6.4 {
+		t.Errorf("Expected 6.4 average client hours, got %v", stats.AverageClientHours)
+	}
+	if stats.LongestStreak != 4 || stats.LongestStreakStart != "2024-01-02" || stats.LongestStreakEnd != "2024-01-08" {
+		t.Errorf("Expected streak of 4 from 2024-01-02 to 2024-01-08, got %d (%s - %s)",
+			stats.LongestStreak, stats.LongestStreakStart, stats.LongestStreakEnd)
+	}
+
+	if len(stats.Clients) != 2 {
+		t.Fatalf("Expected 2 clients, got %d", len(stats.Clients))
+	}
+	acme, beta := stats.Clients[0], stats.Clients[1]
+	if acme.Name != "Acme" || acme.Hours != 22 || acme.Days != 3 || acme.Percent != 68.75 {
+		t.Errorf("Unexpected Acme stats: %+v", acme)
+	}
+	if beta.Name != "Beta" || beta.Hours != 10 || beta.Days != 2 || beta.Percent != 31.25 {
+		t.Errorf("Unexpected Beta stats: %+v", beta)
+	}
+}
+
+func TestComputeTimesheetStatsEmpty(t *testing.T) {
+	stats := ComputeTimesheetStats(2024, nil, workschedule.Default())
+	if stats.DaysWorked != 0 || stats.LongestStreak != 0 || stats.AverageClientHours != 0 {
+		t.Errorf("Expected zero stats, got %+v", stats)
+	}
+	if stats.Clients == nil {
+		t.Errorf("Expected an empty, non-nil client list")
+	}
+}
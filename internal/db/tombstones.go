@@ -16,12 +16,19 @@ const (
 	TombstoneTableBufferHours       = "buffer_hours"
 )
 
-// TombstoneKeyClientRate, TombstoneKeyTrainingBudget,
+// TombstoneKeyTimesheet, TombstoneKeyClientRate, TombstoneKeyTrainingBudget,
 // TombstoneKeyVacationCarryover, and TombstoneKeyBufferHours encode a row's
 // natural sync key as a string. These MUST match the keys the sync package
 // builds when mapping rows side-to-side, otherwise tombstones won't line up
 // with the rows they're supposed to bury.
 
+// TombstoneKeyTimesheet keys a client block of a day. Tombstones written
+// before a day could have several blocks hold only the date; the sync
+// treats those as covering every block on that date.
+func TombstoneKeyTimesheet(date, clientName string) string {
+	return date + "|" + clientName
+}
+
 func TombstoneKeyClientRate(clientName, effectiveDate string) string {
 	return clientName + "|" + effectiveDate
 }
@@ -35,8 +35,8 @@ func TestDeleteTimesheetEntryByDate_WritesTombstone(t *testing.T) {
 		t.Fatalf("delete: %v", err)
 	}
 
-	if !tombstoneExists(t, TombstoneTableTimesheet, "2026-06-14") {
-		t.Fatal("expected tombstone for timesheet/2026-06-14")
+	if !tombstoneExists(t, TombstoneTableTimesheet, TombstoneKeyTimesheet("2026-06-14", "Acme")) {
+		t.Fatal("expected tombstone for timesheet/2026-06-14|Acme")
 	}
 }
 
@@ -48,12 +48,12 @@ func TestDeleteTimesheetEntryByDate_NoRowNoTombstone(t *testing.T) {
 		t.Fatalf("delete (no row): %v", err)
 	}
 
-	if tombstoneExists(t, TombstoneTableTimesheet, "2026-06-14") {
+	if tombstoneExists(t, TombstoneTableTimesheet, TombstoneKeyTimesheet("2026-06-14", "Acme")) {
 		t.Fatal("did not expect tombstone when no row was deleted")
 	}
 }
 
-func TestDeleteTimesheetEntry_WritesTombstoneByDate(t *testing.T) {
+func TestDeleteTimesheetEntry_WritesTombstoneByDateClient(t *testing.T) {
 	setupTestDB(t)
 	defer teardownTestDB(t, "")
 
@@ -73,8 +73,33 @@ func TestDeleteTimesheetEntry_WritesTombstoneByDate(t *testing.T) {
 		t.Fatalf("delete: %v", err)
 	}
 
-	if !tombstoneExists(t, TombstoneTableTimesheet, "2026-06-14") {
-		t.Fatal("expected tombstone for timesheet/2026-06-14")
+	if !tombstoneExists(t, TombstoneTableTimesheet, TombstoneKeyTimesheet("2026-06-14", "Acme")) {
+		t.Fatal("expected tombstone for timesheet/2026-06-14|Acme")
+	}
+}
+
+func TestUpdateTimesheetEntry_ClientChangeWritesTombstone(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t, "")
+
+	if err := AddTimesheetEntry(TimesheetEntry{Date: "2026-06-14", Client_name: "Acme", Client_hours: 8}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	entry, err := firstEntry(GetTimesheetEntriesByDate("2026-06-14"))
+	if err != nil {
+		t.Fatalf("lookup: %v", err)
+	}
+
+	// Moving the block to another client retires the old key
+	entry.Client_name = "Globex"
+	if err := UpdateTimesheetEntry(entry); err != nil {
+		t.Fatalf("update: %v", err)
+	}
+	if !tombstoneExists(t, TombstoneTableTimesheet, TombstoneKeyTimesheet("2026-06-14", "Acme")) {
+		t.Fatal("expected tombstone for timesheet/2026-06-14|Acme")
+	}
+	if tombstoneExists(t, TombstoneTableTimesheet, TombstoneKeyTimesheet("2026-06-14", "Globex")) {
+		t.Fatal("did not expect tombstone for the block's new client")
 	}
 }
 
@@ -0,0 +1,149 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// SaveTrainingWithBudget stores a timesheet entry with training hours
+// together with the training budget entry for the same day, so a course is
+// logged once instead of in both places. The two are linked by date: an
+// existing entry for the date (the entry's client block, in the timesheet)
+// is updated rather than duplicated, in either table. The budget entry gets the entry's training hours.
+//
+// Data layers backed by a database write both in one transaction. The
+// remote API has no transactions; there the timesheet entry is put back the
+// way it was when the budget entry can't be saved.
+func SaveTrainingWithBudget(dl DataLayer, entry TimesheetEntry, budget TrainingBudgetEntry) error {
+	if entry.Training_hours <= 0 {
+		return errors.New("a linked training budget entry needs training hours")
+	}
+	if strings.TrimSpace(budget.Training_name) == "" {
+		return errors.New("training name is required")
+	}
+	if budget.Cost_without_vat < 0 {
+		return errors.New("training cost can't be negative")
+	}
+	if t, ok := dl.(Transactor); ok {
+		return t.WithTransaction(func(tx *sql.Tx) error {
+			return saveTrainingWithBudgetTx(tx, entry, budget)
+		})
+	}
+	return saveTrainingWithBudgetRemote(dl, entry, budget)
+}
+
+// saveTrainingWithBudgetTx writes both entries inside tx. It uses $N
+// placeholders, which both PostgreSQL and the SQLite driver accept.
+func saveTrainingWithBudgetTx(tx *sql.Tx, entry TimesheetEntry, budget TrainingBudgetEntry) error {
+	now := NowTimestamp()
+
+	entry, err := prepareTimesheetEntry(tx, entry)
+	if err != nil {
+		return err
+	}
+	budget.Date = entry.Date
+	budget.Hours = entry.Training_hours
+
+	if _, err := upsertTimesheetEntryTx(tx, entry); err != nil {
+		return err
+	}
+
+	var id int
+	err = tx.QueryRow(`SELECT id FROM training_budget WHERE date = $1 ORDER BY id LIMIT 1`, budget.Date).Scan(&id)
+	switch {
+	case errors.Is(err, sql.ErrNoRows):
+		_, err = tx.Exec(`INSERT INTO training_budget (date, training_name, hours, cost_without_vat, receipt_path, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7)`,
+			budget.Date, budget.Training_name, budget.Hours, budget.Cost_without_vat, budget.Receipt_path, now, now)
+	case err == nil:
+		// Keep a receipt that's already attached
+		_, err = tx.Exec(`UPDATE training_budget
+			SET training_name = $1, hours = $2, cost_without_vat = $3, updated_at = $4
+			WHERE id = $5`,
+			budget.Training_name, budget.Hours, budget.Cost_without_vat, now, id)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to save training budget entry for %s: %w", budget.Date, err)
+	}
+	return nil
+}
+
+// upsertTimesheetEntryTx saves the block of entry.Client_name on entry.Date
+// inside tx, adding it when the date has none, and reports whether it was
+// added. It uses $N placeholders, which both PostgreSQL and the SQLite
+// driver accept.
+func upsertTimesheetEntryTx(tx *sql.Tx, entry TimesheetEntry) (bool, error) {
+	now := NowTimestamp()
+
+	var id int
+	err := tx.QueryRow(`SELECT id FROM timesheet WHERE date = $1 AND client_name = $2`, entry.Date, entry.Client_name).Scan(&id)
+	created := errors.Is(err, sql.ErrNoRows)
+	switch {
+	case created:
+		_, err = tx.Exec(`INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, note, created_at, updated_at)
+			VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`,
+			entry.Date, entry.Client_name, entry.Client_hours, entry.Vacation_hours, entry.Idle_hours,
+			entry.Training_hours, entry.Sick_hours, entry.Holiday_hours, entry.Note, now, now)
+	case err == nil:
+		_, err = tx.Exec(`UPDATE timesheet
+			SET client_name = $1, client_hours = $2, vacation_hours = $3, idle_hours = $4,
+			    training_hours = $5, sick_hours = $6, holiday_hours = $7, note = $8, updated_at = $9
+			WHERE id = $10`,
+			entry.Client_name, entry.Client_hours, entry.Vacation_hours, entry.Idle_hours,
+			entry.Training_hours, entry.Sick_hours, entry.Holiday_hours, entry.Note, now, id)
+	}
+	if err != nil {
+		return false, fmt.Errorf("failed to save entry for %s: %w", entry.Date, err)
+	}
+	return created, nil
+}
+
+// saveTrainingWithBudgetRemote saves the entries one after the other and
+// restores the timesheet entry when the budget entry fails
+func saveTrainingWithBudgetRemote(dl DataLayer, entry TimesheetEntry, budget TrainingBudgetEntry) error {
+	onDate, err := dl.GetTimesheetEntriesByDate(entry.Date)
+	if err != nil {
+		return err
+	}
+	entry, err = prepareTimesheetEntryWith(entry, OtherBlocksHours(onDate, entry))
+	if err != nil {
+		return err
+	}
+	budget.Date = entry.Date
+	budget.Hours = entry.Training_hours
+	previous, existed := FindClientEntry(onDate, entry.Client_name)
+
+	if existed {
+		entry.Id = previous.Id
+		err = dl.UpdateTimesheetEntry(entry)
+	} else {
+		err = dl.AddTimesheetEntry(entry)
+	}
+	if err != nil {
+		return err
+	}
+
+	if existing, lookupErr := dl.GetTrainingBudgetEntryByDate(budget.Date); lookupErr == nil {
+		budget.Id = existing.Id
+		budget.Receipt_path = existing.Receipt_path
+		err = dl.UpdateTrainingBudgetEntry(budget)
+	} else {
+		err = dl.AddTrainingBudgetEntry(budget)
+	}
+	if err == nil {
+		return nil
+	}
+
+	var restoreErr error
+	if existed {
+		restoreErr = dl.UpdateTimesheetEntry(previous)
+	} else {
+		restoreErr = DeleteClientEntry(dl, entry.Date, entry.Client_name)
+	}
+	if restoreErr != nil {
+		return fmt.Errorf("failed to save training budget entry: %w (restoring the timesheet entry failed: %v)", err, restoreErr)
+	}
+	return fmt.Errorf("failed to save training budget entry: %w", err)
+}
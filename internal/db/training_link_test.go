@@ -0,0 +1,80 @@
+package db
+
+import (
+	"errors"
+	"testing"
+)
+
+// remoteLayer hides WithTransaction, like the API client, and can fail
+// budget writes
+type remoteLayer struct {
+	DataLayer
+	failBudget bool
+}
+
+func (r remoteLayer) AddTrainingBudgetEntry(entry TrainingBudgetEntry) error {
+	if r.failBudget {
+		return errors.New("budget unavailable")
+	}
+	return r.DataLayer.AddTrainingBudgetEntry(entry)
+}
+
+func TestSaveTrainingWithBudget(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	entry := TimesheetEntry{Date: "2024-03-04", Client_name: "-", Training_hours: 8}
+	budget := TrainingBudgetEntry{Training_name: "Go workshop", Cost_without_vat: 450}
+	if err := SaveTrainingWithBudget(&LocalDBLayer{}, entry, budget); err != nil {
+		t.Fatalf("SaveTrainingWithBudget failed: %v", err)
+	}
+
+	saved, err := GetTrainingBudgetEntryByDate("2024-03-04")
+	if err != nil {
+		t.Fatalf("Expected a training budget entry: %v", err)
+	}
+	if saved.Training_name != "Go workshop" || saved.Hours != 8 || saved.Cost_without_vat != 450 {
+		t.Errorf("Unexpected training budget entry: %+v", saved)
+	}
+
+	// Saving the day again updates both instead of adding duplicates
+	entry.Training_hours = 4
+	budget.Cost_without_vat = 500
+	if err := SaveTrainingWithBudget(&LocalDBLayer{}, entry, budget); err != nil {
+		t.Fatalf("SaveTrainingWithBudget failed: %v", err)
+	}
+	budgets, _ := GetTrainingBudgetEntriesForYear(2024)
+	if len(budgets) != 1 || budgets[0].Hours != 4 || budgets[0].Cost_without_vat != 500 {
+		t.Errorf("Expected one updated budget entry, got %+v", budgets)
+	}
+	if stored, _ := firstEntry(GetTimesheetEntriesByDate("2024-03-04")); stored.Training_hours != 4 {
+		t.Errorf("Expected 4 training hours, got %+v", stored)
+	}
+
+	if err := SaveTrainingWithBudget(&LocalDBLayer{}, TimesheetEntry{Date: "2024-03-05", Client_name: "-", Vacation_hours: 8}, budget); err == nil {
+		t.Error("Expected an error linking a budget entry to a day without training hours")
+	}
+}
+
+func TestSaveTrainingWithBudgetRemoteRollback(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	remote := remoteLayer{DataLayer: &LocalDBLayer{}, failBudget: true}
+	entry := TimesheetEntry{Date: "2024-03-04", Client_name: "-", Training_hours: 8}
+	err := SaveTrainingWithBudget(remote, entry, TrainingBudgetEntry{Training_name: "Go workshop"})
+	if err == nil {
+		t.Fatal("Expected the failing budget write to be reported")
+	}
+	if _, err := firstEntry(GetTimesheetEntriesByDate("2024-03-04")); err == nil {
+		t.Error("Expected the timesheet entry to be removed again")
+	}
+
+	remote.failBudget = false
+	if err := SaveTrainingWithBudget(remote, entry, TrainingBudgetEntry{Training_name: "Go workshop"}); err != nil {
+		t.Fatalf("SaveTrainingWithBudget failed: %v", err)
+	}
+	if _, err := GetTrainingBudgetEntryByDate("2024-03-04"); err != nil {
+		t.Errorf("Expected a training budget entry: %v", err)
+	}
+}
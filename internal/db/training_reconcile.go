@@ -0,0 +1,97 @@
+package db
+
+import (
+	"sort"
+	"strings"
+)
+
+// Training reconciliation statuses
+const (
+	TrainingMatched    = "matched"    // Same hours logged and budgeted
+	TrainingUnbudgeted = "unbudgeted" // Logged on the timesheet, missing from the budget
+	TrainingUnlogged   = "unlogged"   // In the budget, missing from the timesheet
+	TrainingMismatch   = "mismatch"   // On both sides with different hours
+)
+
+// TrainingReconciliationRow compares training for a single date
+type TrainingReconciliationRow struct {
+	Date          string
+	LoggedHours   float64 // training_hours on the timesheet
+	BudgetedHours float64 // hours recorded in training_budget
+	TrainingNames string  // Budget entry names for the date, comma separated
+	Status        string
+}
+
+// TrainingReconciliation cross-checks timesheet training hours against the
+// training budget for a year
+type TrainingReconciliation struct {
+	Year               int
+	TotalLoggedHours   float64
+	TotalBudgetedHours float64
+	Rows               []TrainingReconciliationRow
+}
+
+// Discrepancies returns the rows that don't match
+func (r TrainingReconciliation) Discrepancies() []TrainingReconciliationRow {
+	var rows []TrainingReconciliationRow
+	for _, row := range r.Rows {
+		if row.Status != TrainingMatched {
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// ReconcileTraining joins timesheet training entries (time spent) with
+// training budget entries (money spent) by date. Each date that appears on
+// either side yields one row, ordered by date.
+func ReconcileTraining(year int, timesheetEntries []TimesheetEntry, budgetEntries []TrainingBudgetEntry) TrainingReconciliation {
+	byDate := map[string]*TrainingReconciliationRow{}
+	names := map[string][]string{}
+
+	rowFor := func(date string) *TrainingReconciliationRow {
+		row, ok := byDate[date]
+		if !ok {
+			row = &TrainingReconciliationRow{Date: date}
+			byDate[date] = row
+		}
+		return row
+	}
+
+	result := TrainingReconciliation{Year: year}
+
+	for _, entry := range timesheetEntries {
+		if entry.Training_hours == 0 {
+			continue
+		}
+		rowFor(entry.Date).LoggedHours += entry.Training_hours
+		result.TotalLoggedHours += entry.Training_hours
+	}
+
+	for _, entry := range budgetEntries {
+		rowFor(entry.Date).BudgetedHours += entry.Hours
+		names[entry.Date] = append(names[entry.Date], entry.Training_name)
+		result.TotalBudgetedHours += entry.Hours
+	}
+
+	for date, row := range byDate {
+		row.TrainingNames = strings.Join(names[date], ", ")
+		switch {
+		case row.LoggedHours == row.BudgetedHours:
+			row.Status = TrainingMatched
+		case row.BudgetedHours == 0:
+			row.Status = TrainingUnbudgeted
+		case row.LoggedHours == 0:
+			row.Status = TrainingUnlogged
+		default:
+			row.Status = TrainingMismatch
+		}
+		result.Rows = append(result.Rows, *row)
+	}
+
+	sort.Slice(result.Rows, func(i, j int) bool {
+		return result.Rows[i].Date < result.Rows[j].Date
+	})
+
+	return result
+}
@@ -0,0 +1,50 @@
+package db
+
+import "testing"
+
+func TestReconcileTraining(t *testing.T) {
+	timesheet := []TimesheetEntry{
+		{Date: "2024-02-01", Training_hours: 8},
+		{Date: "2024-03-05", Training_hours: 4},
+		{Date: "2024-04-10", Training_hours: 8},
+		{Date: "2024-04-11", Client_hours: 8}, // no training, ignored
+	}
+	budget := []TrainingBudgetEntry{
+		{Date: "2024-02-01", Training_name: "Go Workshop", Hours: 8},
+		{Date: "2024-04-10", Training_name: "Conference", Hours: 6},
+		{Date: "2024-05-20", Training_name: "Kubernetes", Hours: 16},
+	}
+
+	r := ReconcileTraining(2024, timesheet, budget)
+
+	if r.TotalLoggedHours != 20 {
+		t.Errorf("TotalLoggedHours = %g, want 20", r.TotalLoggedHours)
+	}
+	if r.TotalBudgetedHours != 30 {
+		t.Errorf("TotalBudgetedHours = %g, want 30", r.TotalBudgetedHours)
+	}
+
+	want := []struct {
+		date   string
+		status string
+	}{
+		{"2024-02-01", TrainingMatched},
+		{"2024-03-05", TrainingUnbudgeted},
+		{"2024-04-10", TrainingMismatch},
+		{"2024-05-20", TrainingUnlogged},
+	}
+	if len(r.Rows) != len(want) {
+		t.Fatalf("Expected %d rows, got %d: %+v", len(want), len(r.Rows), r.Rows)
+	}
+	for i, w := range want {
+		if r.Rows[i].Date != w.date || r.Rows[i].Status != w.status {
+			t.Errorf("Row %d = %s/%s, want %s/%s", i, r.Rows[i].Date, r.Rows[i].Status, w.date, w.status)
+		}
+	}
+	if r.Rows[3].TrainingNames != "Kubernetes" {
+		t.Errorf("Expected training name Kubernetes, got %q", r.Rows[3].TrainingNames)
+	}
+	if got := len(r.Discrepancies()); got != 3 {
+		t.Errorf("Expected 3 discrepancies, got %d", got)
+	}
+}
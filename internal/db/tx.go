@@ -0,0 +1,66 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// Transactor is implemented by data layers backed by a SQL database. Bulk
+// operations (merges, copies, backfills) type-assert a DataLayer to it so
+// their writes are all-or-nothing; the remote API layer doesn't implement it.
+type Transactor interface {
+	WithTransaction(fn func(*sql.Tx) error) error
+}
+
+// WithTransaction runs fn inside a transaction on the local SQLite database.
+// The transaction is committed when fn returns nil and rolled back when it
+// returns an error or panics. fn must do all its work through tx: the
+// package-level helpers use a separate connection and won't see (or may wait
+// on) the uncommitted writes.
+func WithTransaction(fn func(*sql.Tx) error) error {
+	if db == nil {
+		return errors.New("database not initialized")
+	}
+	return runInTransaction(db, fn)
+}
+
+// WithTransaction runs fn inside a transaction on the local SQLite database
+func (l *LocalDBLayer) WithTransaction(fn func(*sql.Tx) error) error {
+	return WithTransaction(fn)
+}
+
+// WithTransaction runs fn inside a transaction on the Postgres database. See
+// the package-level WithTransaction for the commit and rollback rules.
+func (p *PostgresDBLayer) WithTransaction(fn func(*sql.Tx) error) error {
+	if pgDB == nil {
+		return errors.New("postgres database not initialized")
+	}
+	return runInTransaction(pgDB, fn)
+}
+
+// runInTransaction begins a transaction on conn, runs fn and commits, rolling
+// back if fn fails or panics
+func runInTransaction(conn *sql.DB, fn func(*sql.Tx) error) (err error) {
+	tx, err := conn.Begin()
+	if err != nil {
+		return fmt.Errorf("failed to begin tx: %w", err)
+	}
+	defer func() {
+		if p := recover(); p != nil {
+			tx.Rollback()
+			panic(p)
+		}
+	}()
+
+	if err := fn(tx); err != nil {
+		if rbErr := tx.Rollback(); rbErr != nil {
+			return fmt.Errorf("%w (rollback failed: %v)", err, rbErr)
+		}
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit tx: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"database/sql"
+	"errors"
+	"testing"
+)
+
+func TestWithTransaction_Commits(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	err := WithTransaction(func(tx *sql.Tx) error {
+		for _, date := range []string{"2024-03-04", "2024-03-05"} {
+			if _, err := tx.Exec(`INSERT INTO timesheet (date, client_name, client_hours) VALUES (?, 'Acme', 8)`, date); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("WithTransaction failed: %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM timesheet`).Scan(&count); err != nil {
+		t.Fatalf("Failed to count entries: %v", err)
+	}
+	if count != 2 {
+		t.Errorf("Expected 2 committed entries, got %d", count)
+	}
+}
+
+func TestWithTransaction_RollsBackOnError(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	failure := errors.New("second write failed")
+	err := WithTransaction(func(tx *sql.Tx) error {
+		if _, err := tx.Exec(`INSERT INTO timesheet (date, client_name, client_hours) VALUES ('2024-03-04', 'Acme', 8)`); err != nil {
+			return err
+		}
+		return failure
+	})
+	if !errors.Is(err, failure) {
+		t.Fatalf("Expected the callback's error, got %v", err)
+	}
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM timesheet`).Scan(&count); err != nil {
+		t.Fatalf("Failed to count entries: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected the insert to be rolled back, found %d entries", count)
+	}
+}
+
+func TestWithTransaction_RollsBackOnPanic(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	func() {
+		defer func() {
+			if recover() == nil {
+				t.Error("Expected the panic to be re-raised")
+			}
+		}()
+		WithTransaction(func(tx *sql.Tx) error {
+			if _, err := tx.Exec(`INSERT INTO timesheet (date, client_name, client_hours) VALUES ('2024-03-04', 'Acme', 8)`); err != nil {
+				return err
+			}
+			panic("boom")
+		})
+	}()
+
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM timesheet`).Scan(&count); err != nil {
+		t.Fatalf("Failed to count entries: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected the insert to be rolled back, found %d entries", count)
+	}
+}
+
+func TestLocalDBLayerIsTransactor(t *testing.T) {
+	var dl DataLayer = &LocalDBLayer{}
+	if _, ok := dl.(Transactor); !ok {
+		t.Error("Expected LocalDBLayer to implement Transactor")
+	}
+	dl = &PostgresDBLayer{}
+	if _, ok := dl.(Transactor); !ok {
+		t.Error("Expected PostgresDBLayer to implement Transactor")
+	}
+}
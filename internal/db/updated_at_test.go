@@ -58,7 +58,7 @@ func TestUpdatedAtBumpedOnTimesheetUpdateById(t *testing.T) {
 	if err := AddTimesheetEntry(entry); err != nil {
 		t.Fatalf("add: %v", err)
 	}
-	row, err := GetTimesheetEntryByDate("2024-02-15")
+	row, err := firstEntry(GetTimesheetEntriesByDate("2024-02-15"))
 	if err != nil {
 		t.Fatalf("get: %v", err)
 	}
@@ -217,3 +217,34 @@ func intToStr(i int) string {
 	}
 	return string(buf[pos:])
 }
+
+// TestBackfillSyncTimestamps verifies that rows written before the timestamp
+// columns existed get them filled in the NowTimestamp layout when the schema
+// is applied, and that rows that already have them are left alone.
+func TestBackfillSyncTimestamps(t *testing.T) {
+	setupTestDB(t)
+	defer teardownTestDB(t, "")
+
+	if _, err := db.Exec(`INSERT INTO timesheet (date, client_name, client_hours) VALUES ('2024-03-04', 'Old', 8)`); err != nil {
+		t.Fatalf("insert legacy row: %v", err)
+	}
+	if err := AddTimesheetEntry(TimesheetEntry{Date: "2024-03-05", Client_name: "New", Client_hours: 8}); err != nil {
+		t.Fatalf("add: %v", err)
+	}
+	kept := readTimestamp(t, "timesheet", "updated_at", "date", "2024-03-05")
+
+	defer func() { nowFunc = time.Now }()
+	nowFunc = func() time.Time { return time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC) }
+	if err := ApplySQLiteSchema(db); err != nil {
+		t.Fatalf("ApplySQLiteSchema failed: %v", err)
+	}
+
+	for _, column := range []string{"created_at", "updated_at"} {
+		if got := readTimestamp(t, "timesheet", column, "date", "2024-03-04"); got != "2030-01-02 03:04:05" {
+			t.Errorf("Expected %s to be backfilled, got %q", column, got)
+		}
+	}
+	if got := readTimestamp(t, "timesheet", "updated_at", "date", "2024-03-05"); got != kept {
+		t.Errorf("Expected updated_at to stay %q, got %q", kept, got)
+	}
+}
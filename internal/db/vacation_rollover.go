@@ -0,0 +1,50 @@
+package db
+
+import (
+	"fmt"
+	"math"
+	"timesheet/internal/config"
+)
+
+// VacationRollover reports what a year-end rollover carried over
+type VacationRollover struct {
+	FromYear       int     `json:"from_year"`
+	ToYear         int     `json:"to_year"`
+	RemainingHours float64 `json:"remaining_hours"` // Balance left in FromYear
+	CarryoverHours int     `json:"carryover_hours"` // Hours recorded as ToYear's carryover
+	Capped         bool    `json:"capped"`          // Whether the maxCarryover setting cut the balance
+}
+
+// RolloverVacation carries the remaining vacation balance of fromYear into
+// fromYear+1 as an explicit carryover record. Partial hours are dropped, a
+// negative balance carries nothing and the vacationHours.maxCarryover
+// setting caps the amount. Re-running replaces the record instead of adding
+// to it.
+func RolloverVacation(dl DataLayer, fromYear int) (VacationRollover, error) {
+	summary, err := dl.GetVacationSummaryForYear(fromYear)
+	if err != nil {
+		return VacationRollover{}, fmt.Errorf("failed to get vacation summary for %d: %w", fromYear, err)
+	}
+
+	rollover := VacationRollover{
+		FromYear:       fromYear,
+		ToYear:         fromYear + 1,
+		RemainingHours: summary.RemainingTotal,
+		CarryoverHours: int(math.Floor(math.Max(summary.RemainingTotal, 0))),
+	}
+	if maxCarryover := config.GetVacationMaxCarryover(); maxCarryover > 0 && rollover.CarryoverHours > maxCarryover {
+		rollover.CarryoverHours = maxCarryover
+		rollover.Capped = true
+	}
+
+	carryover := VacationCarryover{
+		Year:           rollover.ToYear,
+		CarryoverHours: rollover.CarryoverHours,
+		SourceYear:     fromYear,
+		Notes:          fmt.Sprintf("Rolled over from %d", fromYear),
+	}
+	if err := dl.SetVacationCarryover(carryover); err != nil {
+		return VacationRollover{}, err
+	}
+	return rollover, nil
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"testing"
+	"timesheet/internal/config"
+)
+
+func TestRolloverVacation(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+	cleanup := setupTestConfig(t, 100)
+	defer cleanup()
+
+	// Carryover into 2025 of 10 hours, 25.5 hours used: 84.5 remaining
+	if err := SetVacationCarryover(VacationCarryover{Year: 2025, CarryoverHours: 10, SourceYear: 2024}); err != nil {
+		t.Fatal(err)
+	}
+	for date, hours := range map[string]float64{"2025-03-03": 8, "2025-03-04": 17.5} {
+		if err := AddTimesheetEntry(TimesheetEntry{Date: date, Client_name: "Vacation", Vacation_hours: hours}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	dl := &LocalDBLayer{}
+	rollover, err := RolloverVacation(dl, 2025)
+	if err != nil {
+		t.Fatalf("RolloverVacation: %v", err)
+	}
+	if rollover.RemainingHours != 84.5 || rollover.CarryoverHours != 84 || rollover.Capped {
+		t.Errorf("rollover = %+v, want 84.5 remaining carried as 84 uncapped", rollover)
+	}
+
+	// Re-running replaces the record rather than adding to it
+	if _, err := RolloverVacation(dl, 2025); err != nil {
+		t.Fatal(err)
+	}
+	carryover, err := GetVacationCarryoverForYear(2026)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if carryover.CarryoverHours != 84 || carryover.SourceYear != 2025 {
+		t.Errorf("2026 carryover = %+v, want 84 hours from 2025", carryover)
+	}
+	summary, err := GetVacationSummaryForYear(2026)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if summary.CarryoverHours != 84 {
+		t.Errorf("2026 summary carryover = %g, want 84", summary.CarryoverHours)
+	}
+
+	// A configured cap limits the amount
+	cfg, err := config.GetConfig()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg.VacationHours.MaxCarryover = 40
+	if err := config.SaveConfig(cfg); err != nil {
+		t.Fatal(err)
+	}
+	rollover, err = RolloverVacation(dl, 2025)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rollover.CarryoverHours != 40 || !rollover.Capped {
+		t.Errorf("capped rollover = %+v, want 40 capped", rollover)
+	}
+	if carryover, _ := GetVacationCarryoverForYear(2026); carryover.CarryoverHours != 40 {
+		t.Errorf("2026 carryover after capped rollover = %d, want 40", carryover.CarryoverHours)
+	}
+}
+
+func TestRolloverVacation_OverdrawnCarriesNothing(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+	cleanup := setupTestConfig(t, 16)
+	defer cleanup()
+
+	// Nothing carried into 2025, 24 hours used of 16
+	if err := SetVacationCarryover(VacationCarryover{Year: 2025, SourceYear: 2024}); err != nil {
+		t.Fatal(err)
+	}
+
+	for date, hours := range map[string]float64{"2025-06-02": 12, "2025-06-03": 12} {
+		if err := AddTimesheetEntry(TimesheetEntry{Date: date, Client_name: "Vacation", Vacation_hours: hours}); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	rollover, err := RolloverVacation(&LocalDBLayer{}, 2025)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if rollover.RemainingHours != -8 || rollover.CarryoverHours != 0 {
+		t.Errorf("rollover = %+v, want -8 remaining and nothing carried", rollover)
+	}
+	carryover, err := GetVacationCarryoverForYear(2026)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if carryover.Id == 0 || carryover.CarryoverHours != 0 {
+		t.Errorf("2026 carryover = %+v, want an explicit zero record", carryover)
+	}
+}
@@ -0,0 +1,161 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+	"timesheet/internal/config"
+)
+
+// MaxDailyHours is the most hours a single day can hold
+const MaxDailyHours = 24
+
+// ErrDailyLimitExceeded is returned for an entry with more than
+// MaxDailyHours when overLimitBehavior is reject
+var ErrDailyLimitExceeded = fmt.Errorf("total hours exceed %d hours in a day", MaxDailyHours)
+
+// hourIncrement is the granularity hours are logged in
+const hourIncrement = 0.5
+
+// Violation is one rule a submitted entry breaks
+type Violation struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// TimesheetDraft is a timesheet entry as submitted, before it's validated.
+// Field names match TimesheetEntry's JSON.
+type TimesheetDraft struct {
+	Date           string
+	Client_name    string
+	Client_hours   float64
+	Vacation_hours float64
+	Idle_hours     float64
+	Training_hours float64
+	Sick_hours     float64
+	Holiday_hours  float64
+}
+
+// ValidateTimesheetDraft checks a submitted entry against every rule a
+// stored entry must follow and returns all violations, not just the first.
+// otherHours are the hours the date's other client blocks already hold,
+// which count towards MaxDailyHours. It doesn't touch the database.
+func ValidateTimesheetDraft(draft TimesheetDraft, otherHours float64) []Violation {
+	violations := []Violation{}
+
+	if _, err := time.Parse("2006-01-02", draft.Date); err != nil {
+		violations = append(violations, Violation{"Date", fmt.Sprintf("invalid date %q (expected YYYY-MM-DD)", draft.Date)})
+	}
+
+	hours := []struct {
+		field string
+		value float64
+	}{
+		{"Client_hours", draft.Client_hours},
+		{"Vacation_hours", draft.Vacation_hours},
+		{"Idle_hours", draft.Idle_hours},
+		{"Training_hours", draft.Training_hours},
+		{"Sick_hours", draft.Sick_hours},
+		{"Holiday_hours", draft.Holiday_hours},
+	}
+	total := 0.0
+	for _, h := range hours {
+		total += h.value
+		if h.value < 0 {
+			violations = append(violations, Violation{h.field, "hours can't be negative"})
+		} else if !validHours(h.value) {
+			violations = append(violations, Violation{h.field, fmt.Sprintf("hours must be whole multiples of %g", hourIncrement)})
+		}
+	}
+	if day := total + otherHours; day > MaxDailyHours && config.GetOverLimitBehavior() == config.OverLimitReject {
+		violations = append(violations, Violation{"Total_hours", fmt.Sprintf("total of %g hours exceeds %d hours in a day", day, MaxDailyHours)})
+	}
+
+	if draft.Client_hours > 0 && strings.TrimSpace(draft.Client_name) == "" {
+		violations = append(violations, Violation{"Client_name", ErrClientNameRequired.Error()})
+	}
+
+	return violations
+}
+
+// ApplyDailyLimit applies the configured overLimitBehavior to an entry.
+// otherHours are the hours the date's other client blocks hold; the day as
+// a whole must stay within MaxDailyHours. With reject a day over the limit
+// returns ErrDailyLimitExceeded; with clamp the entry is trimmed until the
+// day fits and a warning says what was cut. Entries within the limit are
+// returned unchanged.
+func ApplyDailyLimit(entry TimesheetEntry, otherHours float64) (TimesheetEntry, string, error) {
+	return applyDailyLimit(entry, otherHours, config.GetOverLimitBehavior())
+}
+
+func applyDailyLimit(entry TimesheetEntry, otherHours float64, behavior string) (TimesheetEntry, string, error) {
+	total := entryHours(entry) + otherHours
+	if total <= MaxDailyHours {
+		return entry, "", nil
+	}
+
+	switch behavior {
+	case config.OverLimitAllow:
+		return entry, "", nil
+	case config.OverLimitClamp:
+		return clampDailyHours(entry, total-MaxDailyHours),
+			fmt.Sprintf("%s: %g hours trimmed to %d", entry.Date, total, MaxDailyHours), nil
+	default:
+		return entry, "", fmt.Errorf("%s: %w (%g logged)", entry.Date, ErrDailyLimitExceeded, total)
+	}
+}
+
+// entryHours adds up the hours of every category of entry
+func entryHours(entry TimesheetEntry) float64 {
+	return entry.Client_hours + entry.Vacation_hours + entry.Idle_hours +
+		entry.Training_hours + entry.Sick_hours + entry.Holiday_hours
+}
+
+// OtherBlocksHours adds up the hours of the blocks in onDate other than the
+// one entry saves: blocks of other clients, and not the row entry updates
+// by Id
+func OtherBlocksHours(onDate []TimesheetEntry, entry TimesheetEntry) float64 {
+	var hours float64
+	for _, other := range onDate {
+		if other.Client_name == entry.Client_name || (entry.Id != 0 && other.Id == entry.Id) {
+			continue
+		}
+		hours += entryHours(other)
+	}
+	return hours
+}
+
+// rowQuerier is what *sql.DB and *sql.Tx share for single-row queries
+type rowQuerier interface {
+	QueryRow(query string, args ...any) *sql.Row
+}
+
+// otherBlocksHours is OtherBlocksHours read from q in one query. It uses $N
+// placeholders, which both PostgreSQL and the SQLite driver accept.
+func otherBlocksHours(q rowQuerier, entry TimesheetEntry) (float64, error) {
+	var hours float64
+	err := q.QueryRow(`
+		SELECT COALESCE(SUM(COALESCE(client_hours, 0) + COALESCE(vacation_hours, 0) + COALESCE(idle_hours, 0) +
+		       COALESCE(training_hours, 0) + COALESCE(sick_hours, 0) + COALESCE(holiday_hours, 0)), 0)
+		FROM timesheet
+		WHERE date = $1 AND client_name <> $2 AND id <> $3`,
+		entry.Date, entry.Client_name, entry.Id).Scan(&hours)
+	if err != nil {
+		return 0, fmt.Errorf("failed to total the other blocks of %s: %w", entry.Date, err)
+	}
+	return hours, nil
+}
+
+// clampDailyHours takes excess hours off an entry, starting with the
+// categories at the end of the form so client hours are cut last
+func clampDailyHours(entry TimesheetEntry, excess float64) TimesheetEntry {
+	categories := []*float64{&entry.Holiday_hours, &entry.Sick_hours, &entry.Training_hours,
+		&entry.Idle_hours, &entry.Vacation_hours, &entry.Client_hours}
+	for _, hours := range categories {
+		cut := min(*hours, excess)
+		*hours -= cut
+		excess -= cut
+	}
+	return entry
+}
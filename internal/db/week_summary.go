@@ -0,0 +1,87 @@
+package db
+
+import (
+	"fmt"
+	"time"
+	"timesheet/internal/workschedule"
+)
+
+// WeekSummary totals the hours logged in one week
+type WeekSummary struct {
+	Start         string  `json:"start"` // First day of the week (YYYY-MM-DD)
+	End           string  `json:"end"`   // Last day of the week
+	ISOYear       int     `json:"iso_year"`
+	ISOWeek       int     `json:"iso_week"`
+	ClientHours   float64 `json:"client_hours"`
+	VacationHours float64 `json:"vacation_hours"`
+	IdleHours     float64 `json:"idle_hours"`
+	TrainingHours float64 `json:"training_hours"`
+	SickHours     float64 `json:"sick_hours"`
+	HolidayHours  float64 `json:"holiday_hours"`
+	TotalHours    float64 `json:"total_hours"`
+	ExpectedHours int     `json:"expected_hours"` // From the work schedule
+	DaysLogged    int     `json:"days_logged"`    // Days with any hours
+}
+
+// SummarizeWeek totals the entries that fall in the week from start to
+// start+6 days. Entries outside the week are ignored.
+func SummarizeWeek(start time.Time, entries []TimesheetEntry, schedule workschedule.Schedule) WeekSummary {
+	end := start.AddDate(0, 0, 6)
+	summary := WeekSummary{
+		Start: start.Format("2006-01-02"),
+		End:   end.Format("2006-01-02"),
+	}
+	// The ISO week is the one holding the week's Thursday-equivalent: the
+	// middle day of a seven-day week
+	summary.ISOYear, summary.ISOWeek = start.AddDate(0, 0, 3).ISOWeek()
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		summary.ExpectedHours += schedule[day.Weekday()]
+	}
+
+	// A day split across clients has several entries but counts once
+	logged := map[string]bool{}
+	for _, entry := range entries {
+		date := entry.Date
+		if len(date) > 10 {
+			date = date[:10]
+		}
+		if date < summary.Start || date > summary.End {
+			continue
+		}
+		total := entry.Client_hours + entry.Vacation_hours + entry.Idle_hours +
+			entry.Training_hours + entry.Sick_hours + entry.Holiday_hours
+		summary.ClientHours += entry.Client_hours
+		summary.VacationHours += entry.Vacation_hours
+		summary.IdleHours += entry.Idle_hours
+		summary.TrainingHours += entry.Training_hours
+		summary.SickHours += entry.Sick_hours
+		summary.HolidayHours += entry.Holiday_hours
+		summary.TotalHours += total
+		if total > 0 && !logged[date] {
+			logged[date] = true
+			summary.DaysLogged++
+		}
+	}
+	return summary
+}
+
+// GetWeekSummary loads and totals the week containing date, for weeks that
+// begin on weekStart. A week spanning two months (or years) reads both.
+func GetWeekSummary(dl DataLayer, date time.Time, weekStart time.Weekday, schedule workschedule.Schedule) (WeekSummary, error) {
+	start, end := workschedule.WeekRange(date, weekStart)
+
+	entries, err := dl.GetAllTimesheetEntries(start.Year(), start.Month())
+	if err != nil {
+		return WeekSummary{}, fmt.Errorf("failed to load timesheet entries: %w", err)
+	}
+	if end.Month() != start.Month() {
+		more, err := dl.GetAllTimesheetEntries(end.Year(), end.Month())
+		if err != nil {
+			return WeekSummary{}, fmt.Errorf("failed to load timesheet entries: %w", err)
+		}
+		entries = append(entries, more...)
+	}
+
+	return SummarizeWeek(start, entries, schedule), nil
+}
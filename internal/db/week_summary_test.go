@@ -0,0 +1,58 @@
+package db
+
+import (
+	"testing"
+	"time"
+	"timesheet/internal/workschedule"
+)
+
+func TestGetWeekSummary(t *testing.T) {
+	dbPath := setupTestDB(t)
+	defer teardownTestDB(t, dbPath)
+
+	// The week of Wednesday 31 January 2024 spans January and February
+	for _, entry := range []TimesheetEntry{
+		{Date: "2024-01-28", Client_name: "Acme", Client_hours: 8}, // Previous Sunday
+		{Date: "2024-01-29", Client_name: "Acme", Client_hours: 8},
+		{Date: "2024-01-30", Client_name: "Acme", Client_hours: 6, Training_hours: 2},
+		{Date: "2024-02-01", Vacation_hours: 8},
+		{Date: "2024-02-02", Client_name: "Acme", Client_hours: 6},
+		{Date: "2024-02-02", Client_name: "Globex", Client_hours: 2}, // Same day, another client
+		{Date: "2024-02-05", Client_name: "Acme", Client_hours: 8},   // Next Monday
+	} {
+		if err := AddTimesheetEntry(entry); err != nil {
+			t.Fatalf("Failed to add entry: %v", err)
+		}
+	}
+
+	date := time.Date(2024, time.January, 31, 0, 0, 0, 0, time.Local)
+	summary, err := GetWeekSummary(&LocalDBLayer{}, date, time.Monday, workschedule.Default())
+	if err != nil {
+		t.Fatalf("GetWeekSummary failed: %v", err)
+	}
+
+	if summary.Start != "2024-01-29" || summary.End != "2024-02-04" {
+		t.Errorf("Expected the week of 2024-01-29 to 2024-02-04, got %s to %s", summary.Start, summary.End)
+	}
+	if summary.ISOYear != 2024 || summary.ISOWeek != 5 {
+		t.Errorf("Expected ISO week 5 of 2024, got %d of %d", summary.ISOWeek, summary.ISOYear)
+	}
+	if summary.ClientHours != 22 || summary.TrainingHours != 2 || summary.VacationHours != 8 || summary.TotalHours != 32 {
+		t.Errorf("Unexpected totals: %+v", summary)
+	}
+	if summary.DaysLogged != 4 {
+		t.Errorf("Expected 4 days logged with the split day counted once, got %d", summary.DaysLogged)
+	}
+	if summary.ExpectedHours != 36 {
+		t.Errorf("Expected 36 expected hours, got %d", summary.ExpectedHours)
+	}
+
+	// A week starting on Sunday runs from the 28th to 3 February
+	summary, err = GetWeekSummary(&LocalDBLayer{}, date, time.Sunday, workschedule.Default())
+	if err != nil {
+		t.Fatalf("GetWeekSummary failed: %v", err)
+	}
+	if summary.Start != "2024-01-28" || summary.TotalHours != 40 {
+		t.Errorf("Expected the Sunday week from 2024-01-28 with 40 hours, got %s with %g", summary.Start, summary.TotalHours)
+	}
+}
@@ -0,0 +1,100 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// HourTotals adds up the hours of each category
+type HourTotals struct {
+	ClientHours   float64 `json:"client_hours"`
+	VacationHours float64 `json:"vacation_hours"`
+	IdleHours     float64 `json:"idle_hours"`
+	TrainingHours float64 `json:"training_hours"`
+	SickHours     float64 `json:"sick_hours"`
+	HolidayHours  float64 `json:"holiday_hours"`
+	TotalHours    float64 `json:"total_hours"`
+}
+
+// add counts the hours of entry
+func (t *HourTotals) add(entry TimesheetEntry) {
+	t.ClientHours += entry.Client_hours
+	t.VacationHours += entry.Vacation_hours
+	t.IdleHours += entry.Idle_hours
+	t.TrainingHours += entry.Training_hours
+	t.SickHours += entry.Sick_hours
+	t.HolidayHours += entry.Holiday_hours
+	t.TotalHours += entry.Client_hours + entry.Vacation_hours + entry.Idle_hours +
+		entry.Training_hours + entry.Sick_hours + entry.Holiday_hours
+}
+
+// SumHourTotals adds up the hours of entries
+func SumHourTotals(entries []TimesheetEntry) HourTotals {
+	var totals HourTotals
+	for _, entry := range entries {
+		totals.add(entry)
+	}
+	return totals
+}
+
+// GetMonthlyTotals returns the hours of each category logged in a month
+func GetMonthlyTotals(year int, month time.Month) (HourTotals, error) {
+	return queryMonthlyTotals(db, func(int) string { return "?" }, year, month)
+}
+
+// queryMonthlyTotals runs GetMonthlyTotals on conn as a single SUM query.
+// placeholder returns the driver's nth parameter ("?" or "$n").
+func queryMonthlyTotals(conn *sql.DB, placeholder func(n int) string, year int, month time.Month) (HourTotals, error) {
+	var totals HourTotals
+	start, end := expenseDateBounds(year, int(month))
+	err := conn.QueryRow(`
+		SELECT COALESCE(SUM(client_hours), 0), COALESCE(SUM(vacation_hours), 0),
+		       COALESCE(SUM(idle_hours), 0), COALESCE(SUM(training_hours), 0),
+		       COALESCE(SUM(sick_hours), 0), COALESCE(SUM(holiday_hours), 0)
+		FROM timesheet
+		WHERE date >= `+placeholder(1)+` AND date < `+placeholder(2), start, end).Scan(
+		&totals.ClientHours, &totals.VacationHours, &totals.IdleHours,
+		&totals.TrainingHours, &totals.SickHours, &totals.HolidayHours)
+	if err != nil {
+		return HourTotals{}, fmt.Errorf("failed to total %s %d: %w", month, year, err)
+	}
+	totals.TotalHours = totals.ClientHours + totals.VacationHours + totals.IdleHours +
+		totals.TrainingHours + totals.SickHours + totals.HolidayHours
+	return totals, nil
+}
+
+// MonthHours holds the hour totals of one month
+type MonthHours struct {
+	Month int `json:"month"` // 1-12
+	HourTotals
+}
+
+// YearHours totals a year's hours per category, for the whole year and for
+// each month
+type YearHours struct {
+	Year   int          `json:"year"`
+	Totals HourTotals   `json:"totals"`
+	Months []MonthHours `json:"months"` // All twelve, January first
+}
+
+// SummarizeYearHours totals the entries dated in year. Entries of other
+// years are ignored; months without entries have zero totals.
+func SummarizeYearHours(year int, entries []TimesheetEntry) YearHours {
+	summary := YearHours{Year: year, Months: make([]MonthHours, 12)}
+	for i := range summary.Months {
+		summary.Months[i].Month = i + 1
+	}
+
+	prefix := fmt.Sprintf("%04d-", year)
+	for _, entry := range entries {
+		month := dateMonth(entry.Date)
+		if month == 0 || !strings.HasPrefix(entry.Date, prefix) {
+			continue
+		}
+		summary.Totals.add(entry)
+		summary.Months[month-1].add(entry)
+	}
+	return summary
+}
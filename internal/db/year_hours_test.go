@@ -0,0 +1,33 @@
+package db
+
+import "testing"
+
+func TestSummarizeYearHours(t *testing.T) {
+	entries := []TimesheetEntry{
+		{Date: "2023-12-29", Client_name: "Acme", Client_hours: 8}, // Previous year
+		{Date: "2024-01-02", Client_name: "Acme", Client_hours: 6, Idle_hours: 2},
+		{Date: "2024-01-03", Client_name: "-", Sick_hours: 8},
+		{Date: "2024-03-04", Client_name: "Globex", Client_hours: 4, Training_hours: 4},
+		{Date: "2024-03-05T00:00:00Z", Client_name: "-", Holiday_hours: 8, Vacation_hours: 0.5},
+	}
+
+	summary := SummarizeYearHours(2024, entries)
+
+	want := HourTotals{ClientHours: 10, IdleHours: 2, SickHours: 8, TrainingHours: 4, HolidayHours: 8, VacationHours: 0.5, TotalHours: 32.5}
+	if summary.Totals != want {
+		t.Errorf("Totals = %+v, want %+v", summary.Totals, want)
+	}
+	if len(summary.Months) != 12 {
+		t.Fatalf("Expected 12 months, got %d", len(summary.Months))
+	}
+	january := summary.Months[0]
+	if january.Month != 1 || january.ClientHours != 6 || january.IdleHours != 2 || january.SickHours != 8 || january.TotalHours != 16 {
+		t.Errorf("Unexpected January totals %+v", january)
+	}
+	if march := summary.Months[2]; march.HolidayHours != 8 || march.TotalHours != 16.5 {
+		t.Errorf("Unexpected March totals %+v", march)
+	}
+	if february := summary.Months[1]; february.Month != 2 || february.TotalHours != 0 {
+		t.Errorf("Expected an empty February, got %+v", february)
+	}
+}
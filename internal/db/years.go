@@ -0,0 +1,57 @@
+package db
+
+import (
+	"database/sql"
+	"fmt"
+	"strconv"
+)
+
+// yearsQuery returns the query for the distinct years with data. yearExpr
+// extracts the year from a date column in the backend's SQL dialect.
+func yearsQuery(yearExpr string, includeTraining bool) string {
+	query := `SELECT DISTINCT ` + yearExpr + ` AS year FROM timesheet`
+	if includeTraining {
+		query += ` UNION SELECT DISTINCT ` + yearExpr + ` AS year FROM training_budget`
+	}
+	return query + ` ORDER BY year`
+}
+
+// scanYears reads a column of year strings into sorted ints, skipping rows
+// whose date isn't a valid YYYY-MM-DD
+func scanYears(rows *sql.Rows) ([]int, error) {
+	defer rows.Close()
+
+	years := []int{}
+	for rows.Next() {
+		var year sql.NullString
+		if err := rows.Scan(&year); err != nil {
+			return nil, fmt.Errorf("failed to scan year: %w", err)
+		}
+		y, err := strconv.Atoi(year.String)
+		if err != nil {
+			continue
+		}
+		years = append(years, y)
+	}
+	return years, rows.Err()
+}
+
+// GetYearsWithData returns the years that have timesheet entries, sorted.
+// With includeTraining, years that only have training budget entries are
+// included too.
+func GetYearsWithData(includeTraining bool) ([]int, error) {
+	rows, err := db.Query(yearsQuery(`strftime('%Y', date)`, includeTraining))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query years: %w", err)
+	}
+	return scanYears(rows)
+}
+
+// GetYearsWithData returns the years that have timesheet entries, sorted
+func (p *PostgresDBLayer) GetYearsWithData(includeTraining bool) ([]int, error) {
+	rows, err := pgDB.Query(yearsQuery(`SUBSTRING(date FROM 1 FOR 4)`, includeTraining))
+	if err != nil {
+		return nil, fmt.Errorf("failed to query years: %w", err)
+	}
+	return scanYears(rows)
+}
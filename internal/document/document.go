@@ -0,0 +1,146 @@
+// Package document builds the content of generated timesheet documents,
+// independent of how they're rendered. The PDF and Excel exporters, the TUI
+// and the API all start from the same Timesheet, so a document has the same
+// content wherever it's produced.
+package document
+
+import (
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+	"timesheet/internal/config"
+	"timesheet/internal/db"
+	"timesheet/internal/utils"
+	"timesheet/internal/workschedule"
+)
+
+// Hours are the hours of a day, or the totals of a month, per category
+type Hours struct {
+	Client   float64 `json:"client"`
+	Training float64 `json:"training"`
+	Vacation float64 `json:"vacation"`
+	Idle     float64 `json:"idle"`
+	Holiday  float64 `json:"holiday"`
+	Sick     float64 `json:"sick"`
+	Total    float64 `json:"total"`
+}
+
+// Day is one day of the month in a timesheet document
+type Day struct {
+	Date       string `json:"date"` // YYYY-MM-DD
+	Weekday    string `json:"weekday"`
+	Weekend    bool   `json:"weekend"`
+	Logged     bool   `json:"logged"` // False for days without an entry; their hours are zero
+	ClientName string `json:"client_name,omitempty"`
+	Hours      Hours  `json:"hours"`
+}
+
+// Timesheet is the content of a monthly timesheet document
+type Timesheet struct {
+	Year          int        `json:"year"`
+	Month         time.Month `json:"month"`
+	Name          string     `json:"name"`
+	Company       string     `json:"company"`
+	FreeSpeech    string     `json:"free_speech"`
+	Days          []Day      `json:"days"` // Every day of the month, in order
+	Totals        Hours      `json:"totals"`
+	ExpectedHours int        `json:"expected_hours"` // From the work schedule
+}
+
+// Title returns the document's title, e.g. "March 2024"
+func (t Timesheet) Title() string {
+	return fmt.Sprintf("%s %d", t.Month, t.Year)
+}
+
+// LoggedDays returns the days that have an entry
+func (t Timesheet) LoggedDays() []Day {
+	var days []Day
+	for _, day := range t.Days {
+		if day.Logged {
+			days = append(days, day)
+		}
+	}
+	return days
+}
+
+// BuildTimesheet collects the entries of a month from dl into a timesheet
+// document, along with the user's details from the config
+func BuildTimesheet(dl db.DataLayer, year int, month time.Month) (Timesheet, error) {
+	if month < time.January || month > time.December {
+		return Timesheet{}, fmt.Errorf("invalid month %d", month)
+	}
+
+	entries, err := dl.GetAllTimesheetEntries(year, month)
+	if err != nil {
+		return Timesheet{}, fmt.Errorf("error fetching timesheet entries: %v", err)
+	}
+
+	name, company, freeSpeech, err := config.GetUserConfig()
+	if err != nil {
+		name = "Unknown User"
+		company = "Unknown Company"
+		freeSpeech = "Free Speech"
+	}
+
+	doc := Timesheet{
+		Year:          year,
+		Month:         month,
+		Name:          name,
+		Company:       company,
+		FreeSpeech:    freeSpeech,
+		ExpectedHours: workschedule.ExpectedHoursForMonth(year, month, config.GetWorkSchedule()),
+	}
+	doc.Days = buildDays(year, month, entries, config.GetWeekendDays())
+	for _, day := range doc.Days {
+		doc.Totals.add(day.Hours)
+	}
+	return doc, nil
+}
+
+// buildDays lists every day of the month with its entries, if any, marking
+// the weekdays in weekend
+func buildDays(year int, month time.Month, entries []db.TimesheetEntry, weekend []time.Weekday) []Day {
+	entriesByDate := make(map[string][]db.TimesheetEntry, len(entries))
+	for _, entry := range entries {
+		entriesByDate[entry.Date] = append(entriesByDate[entry.Date], entry)
+	}
+
+	var days []Day
+	first := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	for date := first; date.Month() == month; date = date.AddDate(0, 0, 1) {
+		day := Day{
+			Date:    date.Format("2006-01-02"),
+			Weekday: date.Weekday().String(),
+			Weekend: slices.Contains(weekend, date.Weekday()),
+		}
+		// A day split between clients is one line with all their hours
+		var clients []string
+		for _, entry := range entriesByDate[day.Date] {
+			day.Logged = true
+			clients = append(clients, utils.ClientLabel(entry.Client_name))
+			day.Hours.add(Hours{
+				Client:   entry.Client_hours,
+				Training: entry.Training_hours,
+				Vacation: entry.Vacation_hours,
+				Idle:     entry.Idle_hours,
+				Holiday:  entry.Holiday_hours,
+				Sick:     entry.Sick_hours,
+				Total:    entry.Total_hours,
+			})
+		}
+		day.ClientName = strings.Join(clients, ", ")
+		days = append(days, day)
+	}
+	return days
+}
+
+func (h *Hours) add(o Hours) {
+	h.Client += o.Client
+	h.Training += o.Training
+	h.Vacation += o.Vacation
+	h.Idle += o.Idle
+	h.Holiday += o.Holiday
+	h.Sick += o.Sick
+	h.Total += o.Total
+}
@@ -0,0 +1,68 @@
+package document
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+	"timesheet/internal/config"
+	"timesheet/internal/db"
+)
+
+func TestBuildTimesheet(t *testing.T) {
+	if err := db.InitializeDatabase(":memory:"); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+	if err := config.SaveConfig(config.Config{Name: "Jane", CompanyName: "Acme"}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-02-05", Client_name: "Client A", Client_hours: 8})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-02-06", Client_name: "Client A", Client_hours: 6, Training_hours: 2})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-02-06", Client_name: "Client B", Client_hours: 2})
+	db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-03-01", Client_name: "Client A", Client_hours: 8})
+
+	doc, err := BuildTimesheet(&db.LocalDBLayer{}, 2024, time.February)
+	if err != nil {
+		t.Fatalf("BuildTimesheet failed: %v", err)
+	}
+
+	if doc.Name != "Jane" || doc.Company != "Acme" || doc.Title() != "February 2024" {
+		t.Errorf("Unexpected document details: %+v", doc)
+	}
+	if len(doc.Days) != 29 {
+		t.Fatalf("Expected every day of February 2024 (29), got %d", len(doc.Days))
+	}
+	if day := doc.Days[3]; day.Date != "2024-02-04" || !day.Weekend || day.Logged {
+		t.Errorf("Expected 4 February to be an unlogged Sunday, got %+v", day)
+	}
+	if day := doc.Days[5]; !day.Logged || day.ClientName != "Client A, Client B" || day.Hours.Training != 2 || day.Hours.Total != 10 {
+		t.Errorf("Expected 6 February's entries for both clients, got %+v", day)
+	}
+	if len(doc.LoggedDays()) != 2 {
+		t.Errorf("Expected 2 logged days, got %d", len(doc.LoggedDays()))
+	}
+	want := Hours{Client: 16, Training: 2, Total: 18}
+	if doc.Totals != want {
+		t.Errorf("Expected totals %+v, got %+v", want, doc.Totals)
+	}
+
+	// A Friday and Saturday weekend
+	if err := config.SaveConfig(config.Config{WeekendDays: []string{"friday", "saturday"}}); err != nil {
+		t.Fatalf("Failed to save config: %v", err)
+	}
+	doc, err = BuildTimesheet(&db.LocalDBLayer{}, 2024, time.February)
+	if err != nil {
+		t.Fatalf("BuildTimesheet failed: %v", err)
+	}
+	if !doc.Days[1].Weekend || !doc.Days[2].Weekend || doc.Days[3].Weekend {
+		t.Errorf("Expected 2 and 3 February to be the weekend and not 4 February, got %+v", doc.Days[1:4])
+	}
+
+	if _, err := BuildTimesheet(&db.LocalDBLayer{}, 2024, 13); err == nil {
+		t.Error("Expected an invalid month to be rejected")
+	}
+}
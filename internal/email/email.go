@@ -1,39 +1,105 @@
 package email
 
 import (
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"path/filepath"
+	"time"
 	"timesheet/internal/config"
 
 	"github.com/resend/resend-go/v2"
 )
 
-func EmailAttachment(filename string) {
+// ErrNotConfigured is returned when the recipient or the Resend API key is
+// missing from the config
+var ErrNotConfigured = errors.New("email isn't set up: set the Recipient Email and Resend API Key in the Config tab")
+
+// FailureKind tells why Resend didn't send an email
+type FailureKind string
+
+const (
+	FailureAuth      FailureKind = "auth"       // The API key was refused
+	FailureRateLimit FailureKind = "rate_limit" // Too many requests
+	FailureNetwork   FailureKind = "network"    // Resend couldn't be reached
+	FailureServer    FailureKind = "server"     // Resend had an internal error
+	FailureRejected  FailureKind = "rejected"   // The email itself was refused, e.g. an invalid address
+)
+
+// SendError describes a failed send after any retries
+type SendError struct {
+	Kind     FailureKind
+	Attempts int
+	Err      error
+}
+
+func (e *SendError) Error() string {
+	switch e.Kind {
+	case FailureAuth:
+		return fmt.Sprintf("Resend refused the API key, check the Resend API Key in the Config tab: %v", e.Err)
+	case FailureRateLimit:
+		return fmt.Sprintf("Resend rate limit reached after %d attempts, try again later", e.Attempts)
+	case FailureNetwork:
+		return fmt.Sprintf("couldn't reach Resend after %d attempts: %v", e.Attempts, e.Err)
+	case FailureServer:
+		return fmt.Sprintf("Resend failed after %d attempts: %v", e.Attempts, e.Err)
+	}
+	return fmt.Sprintf("Resend rejected the email: %v", e.Err)
+}
+
+func (e *SendError) Unwrap() error {
+	return e.Err
+}
+
+// transient reports whether trying again may succeed
+func (e *SendError) transient() bool {
+	return e.Kind == FailureRateLimit || e.Kind == FailureNetwork || e.Kind == FailureServer
+}
+
+// maxAttempts is how often a send is tried before giving up on a transient
+// failure; retryDelay is the wait before the first retry, doubled after each
+var (
+	maxAttempts = 3
+	retryDelay  = time.Second
+)
+
+// CheckConfig returns ErrNotConfigured when an email can't be sent with the
+// current config
+func CheckConfig() error {
+	_, _, recipientEmail, _, _, apiKey, err := config.GetEmailConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load email configuration: %w", err)
+	}
+	if recipientEmail == "" || apiKey == "" {
+		return ErrNotConfigured
+	}
+	return nil
+}
+
+// EmailAttachment emails the file to the configured recipient through
+// Resend. Transient failures (rate limits, network and server errors) are
+// retried; the returned error is a *SendError when Resend was tried.
+func EmailAttachment(filename string) error {
 	// Get email configuration from config
 	name, sendToOthers, recipientEmail, senderEmail, replyToEmail, apiKey, err := config.GetEmailConfig()
 	if err != nil {
-		fmt.Println("Error loading email configuration:", err.Error())
-		return
+		return fmt.Errorf("failed to load email configuration: %w", err)
 	}
-	// Check if user wants to send EmailAttachment
-	if !sendToOthers {
-		fmt.Println("not sending to others")
+	if recipientEmail == "" || apiKey == "" {
+		return ErrNotConfigured
 	}
 
-	client := resend.NewClient(apiKey)
-
 	// Read attachment file
-	pwd, _ := os.Getwd()
-	f, err := os.ReadFile(pwd + "/" + filename)
+	f, err := os.ReadFile(filename)
 	if err != nil {
-		fmt.Println("Error reading attachment file:", err.Error())
-		return
+		return fmt.Errorf("failed to read attachment: %w", err)
 	}
 
 	// Create attachments objects
 	pdfAttachmentFromLocalFile := &resend.Attachment{
 		Content:     f,
-		Filename:    filename,
+		Filename:    filepath.Base(filename),
 		ContentType: "application/image",
 	}
 
@@ -56,10 +122,62 @@ func EmailAttachment(filename string) {
 		Attachments: []*resend.Attachment{pdfAttachmentFromLocalFile},
 	}
 
-	sent, err := client.Emails.Send(params)
-	if err != nil {
-		fmt.Println("Error sending email:", err.Error())
-		return
+	client, status := newClient(apiKey)
+	return send(client, status, params)
+}
+
+// statusRecorder keeps the HTTP status of the last response, which the
+// Resend client doesn't report in its errors
+type statusRecorder struct {
+	next   http.RoundTripper
+	status int
+}
+
+func (r *statusRecorder) RoundTrip(req *http.Request) (*http.Response, error) {
+	r.status = 0
+	resp, err := r.next.RoundTrip(req)
+	if resp != nil {
+		r.status = resp.StatusCode
+	}
+	return resp, err
+}
+
+// newClient creates a Resend client whose response statuses are recorded
+func newClient(apiKey string) (*resend.Client, *statusRecorder) {
+	status := &statusRecorder{next: http.DefaultTransport}
+	httpClient := &http.Client{Timeout: time.Minute, Transport: status}
+	return resend.NewCustomClient(httpClient, apiKey), status
+}
+
+// send delivers params, retrying transient failures with a growing delay
+func send(client *resend.Client, status *statusRecorder, params *resend.SendEmailRequest) error {
+	delay := retryDelay
+	for attempt := 1; ; attempt++ {
+		_, err := client.Emails.Send(params)
+		if err == nil {
+			return nil
+		}
+		sendErr := &SendError{Kind: classify(status.status), Attempts: attempt, Err: err}
+		if !sendErr.transient() || attempt >= maxAttempts {
+			return sendErr
+		}
+		time.Sleep(delay)
+		delay *= 2
+	}
+}
+
+// classify maps the HTTP status of a failed send to a FailureKind; 0 means
+// no response arrived
+func classify(status int) FailureKind {
+	switch {
+	case status == 0:
+		return FailureNetwork
+	case status == http.StatusUnauthorized || status == http.StatusForbidden:
+		return FailureAuth
+	case status == http.StatusTooManyRequests:
+		return FailureRateLimit
+	case status >= 500:
+		return FailureServer
 	}
-	fmt.Println("Email sent successfully, ID:", sent.Id)
+	return FailureRejected
 }
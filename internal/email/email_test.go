@@ -0,0 +1,87 @@
+package email
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	"github.com/resend/resend-go/v2"
+)
+
+// testClient points a recording Resend client at a server answering with
+// statuses in turn (the last one repeats) and counts the requests
+func testClient(t *testing.T, statuses ...int) (*resend.Client, *statusRecorder, *int) {
+	t.Helper()
+	requests := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		status := statuses[min(requests, len(statuses)-1)]
+		requests++
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(status)
+		if status == http.StatusOK {
+			w.Write([]byte(`{"id": "email-1"}`))
+			return
+		}
+		w.Write([]byte(`{"message": "nope"}`))
+	}))
+	t.Cleanup(server.Close)
+
+	previous := retryDelay
+	retryDelay = 0
+	t.Cleanup(func() { retryDelay = previous })
+
+	client, status := newClient("re_test")
+	client.BaseURL, _ = url.Parse(server.URL + "/")
+	return client, status, &requests
+}
+
+func TestSendRetriesTransientFailures(t *testing.T) {
+	client, status, requests := testClient(t, http.StatusInternalServerError, http.StatusTooManyRequests, http.StatusOK)
+
+	if err := send(client, status, &resend.SendEmailRequest{}); err != nil {
+		t.Fatalf("send failed: %v", err)
+	}
+	if *requests != 3 {
+		t.Errorf("expected 3 requests, got %d", *requests)
+	}
+}
+
+func TestSendErrors(t *testing.T) {
+	tests := []struct {
+		status       int
+		wantKind     FailureKind
+		wantRequests int
+	}{
+		{http.StatusUnauthorized, FailureAuth, 1},
+		{http.StatusUnprocessableEntity, FailureRejected, 1},
+		{http.StatusTooManyRequests, FailureRateLimit, maxAttempts},
+		{http.StatusBadGateway, FailureServer, maxAttempts},
+	}
+	for _, tt := range tests {
+		client, status, requests := testClient(t, tt.status)
+
+		err := send(client, status, &resend.SendEmailRequest{})
+		var sendErr *SendError
+		if !errors.As(err, &sendErr) {
+			t.Fatalf("status %d: expected a SendError, got %v", tt.status, err)
+		}
+		if sendErr.Kind != tt.wantKind || *requests != tt.wantRequests || sendErr.Attempts != tt.wantRequests {
+			t.Errorf("status %d: got %s after %d requests (%d attempts), want %s after %d",
+				tt.status, sendErr.Kind, *requests, sendErr.Attempts, tt.wantKind, tt.wantRequests)
+		}
+	}
+}
+
+func TestSendNetworkError(t *testing.T) {
+	client, status, _ := testClient(t, http.StatusOK)
+	// Nothing listens on the discard port
+	client.BaseURL, _ = url.Parse("http://127.0.0.1:9/")
+
+	err := send(client, status, &resend.SendEmailRequest{})
+	var sendErr *SendError
+	if !errors.As(err, &sendErr) || sendErr.Kind != FailureNetwork || sendErr.Attempts != maxAttempts {
+		t.Errorf("expected a network failure after %d attempts, got %v", maxAttempts, err)
+	}
+}
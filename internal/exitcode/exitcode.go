@@ -0,0 +1,47 @@
+// Package exitcode defines the exit statuses of the timesheet command, so
+// scripts can tell why a run failed. Every command that ends the process
+// early goes through Fail or Exit.
+//
+//	0  OK          The command succeeded
+//	1  Failure     Any other failure, e.g. a statement that no longer
+//	               matches its data or a crash of the TUI
+//	2  Config      The config file is missing, unreadable or incomplete
+//	               (e.g. no apiPort or PostgreSQL URL)
+//	3  Database    The database couldn't be opened, read or written, or a
+//	               sync or import failed
+//	4  Validation  The command line or its input is invalid: unknown flags,
+//	               bad flag values, or options that can't be combined
+package exitcode
+
+import (
+	"fmt"
+	"log"
+	"os"
+)
+
+// Code is an exit status of the timesheet command
+type Code int
+
+const (
+	OK         Code = 0
+	Failure    Code = 1
+	Config     Code = 2
+	Database   Code = 3
+	Validation Code = 4
+)
+
+// exit ends the process; tests replace it
+var exit = os.Exit
+
+// Exit ends the process with code
+func Exit(code Code) {
+	exit(int(code))
+}
+
+// Fail reports a failure on stderr and in the log (which a CLI user doesn't
+// see), then exits with code
+func Fail(code Code, format string, v ...any) {
+	log.Printf(format, v...)
+	fmt.Fprintf(os.Stderr, format+"\n", v...)
+	Exit(code)
+}
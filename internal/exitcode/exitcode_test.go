@@ -0,0 +1,28 @@
+package exitcode
+
+import (
+	"io"
+	"log"
+	"os"
+	"testing"
+)
+
+func TestFail(t *testing.T) {
+	logOutput := log.Writer()
+	log.SetOutput(io.Discard)
+	defer log.SetOutput(logOutput)
+
+	var got int
+	exit = func(code int) { got = code }
+	defer func() { exit = os.Exit }()
+
+	Fail(Database, "Failed to open database: %v", "locked")
+	if got != int(Database) {
+		t.Errorf("Expected exit status %d, got %d", Database, got)
+	}
+
+	Exit(OK)
+	if got != 0 {
+		t.Errorf("Expected exit status 0, got %d", got)
+	}
+}
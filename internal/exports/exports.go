@@ -0,0 +1,114 @@
+// Package exports manages the directory generated documents are saved to
+package exports
+
+import (
+	"fmt"
+	"log"
+	"os"
+	"path/filepath"
+	"regexp"
+	"time"
+	"timesheet/internal/config"
+)
+
+// ArchiveDirName is the subdirectory of the export directory old exports are
+// moved to when archiving is enabled
+const ArchiveDirName = "archive"
+
+// exportNames matches the filenames written by the PDF, Excel and CSV
+// exporters. Pruning never touches anything else in the export directory, so
+// a shared folder such as ~/Documents is safe to use.
+var exportNames = []*regexp.Regexp{
+	regexp.MustCompile(`^timesheet_\d{2}-\d{4}\.pdf$`),
+	regexp.MustCompile(`^(Timesheet|Urensheet)_.+_(internal|intern)_[^_]+_\d{4}\.xlsx$`),
+	regexp.MustCompile(`^Timesheet\.csv$`),
+}
+
+// isExport reports whether name is a file this tool exports
+func isExport(name string) bool {
+	for _, re := range exportNames {
+		if re.MatchString(name) {
+			return true
+		}
+	}
+	return false
+}
+
+// Path returns where an export named filename should be saved, creating the
+// configured export directory when needed
+func Path(filename string) (string, error) {
+	dir := config.GetExportDir()
+	if dir == "" {
+		return filename, nil
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("failed to create export directory: %w", err)
+	}
+	return filepath.Join(dir, filename), nil
+}
+
+// Prune removes exports in dir last modified more than maxAge before now, or
+// moves them to dir/archive when archive is set. Only files named like the
+// exporters' output are considered. It returns the paths of the files it
+// pruned.
+func Prune(dir string, maxAge time.Duration, archive bool, now time.Time) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, fmt.Errorf("failed to read export directory: %w", err)
+	}
+
+	archiveDir := filepath.Join(dir, ArchiveDirName)
+	cutoff := now.Add(-maxAge)
+
+	var pruned []string
+	for _, entry := range entries {
+		if !entry.Type().IsRegular() || !isExport(entry.Name()) {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil || !info.ModTime().Before(cutoff) {
+			continue
+		}
+
+		path := filepath.Join(dir, entry.Name())
+		if archive {
+			if err := os.MkdirAll(archiveDir, 0755); err != nil {
+				return pruned, fmt.Errorf("failed to create archive directory: %w", err)
+			}
+			if err := os.Rename(path, filepath.Join(archiveDir, entry.Name())); err != nil {
+				return pruned, fmt.Errorf("failed to archive %s: %w", entry.Name(), err)
+			}
+		} else if err := os.Remove(path); err != nil {
+			return pruned, fmt.Errorf("failed to remove %s: %w", entry.Name(), err)
+		}
+		pruned = append(pruned, path)
+	}
+
+	return pruned, nil
+}
+
+// PruneOnStartup applies the configured export retention and logs what it
+// pruned. Nothing happens unless both an export directory and a retention
+// period are configured, so exports in the working directory are never touched.
+func PruneOnStartup() {
+	dir := config.GetExportDir()
+	days, archive := config.GetExportRetention()
+	if dir == "" || days <= 0 {
+		return
+	}
+
+	pruned, err := Prune(dir, time.Duration(days)*24*time.Hour, archive, time.Now())
+	action := "Removed"
+	if archive {
+		action = "Archived"
+	}
+	for _, path := range pruned {
+		log.Printf("%s export older than %d days: %s", action, days, path)
+	}
+	if err != nil {
+		log.Printf("Failed to prune old exports: %v", err)
+	}
+}
@@ -0,0 +1,70 @@
+package exports
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// writeAged creates a file in dir with a modification time age before now
+func writeAged(t *testing.T, dir, name string, now time.Time, age time.Duration) string {
+	t.Helper()
+	path := filepath.Join(dir, name)
+	if err := os.WriteFile(path, []byte("export"), 0644); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	modTime := now.Add(-age)
+	if err := os.Chtimes(path, modTime, modTime); err != nil {
+		t.Fatalf("Failed to set mtime on %s: %v", name, err)
+	}
+	return path
+}
+
+func exists(path string) bool {
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func TestPrune(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	day := 24 * time.Hour
+
+	for _, archive := range []bool{false, true} {
+		dir := t.TempDir()
+		oldPDF := writeAged(t, dir, "timesheet_01-2024.pdf", now, 60*day)
+		oldXLSX := writeAged(t, dir, "Timesheet_Acme_internal_Jan_2024.xlsx", now, 45*day)
+		recent := writeAged(t, dir, "timesheet_05-2024.pdf", now, 2*day)
+		other := writeAged(t, dir, "notes.txt", now, 90*day)
+		foreignPDF := writeAged(t, dir, "passport-scan.pdf", now, 90*day)
+
+		pruned, err := Prune(dir, 30*day, archive, now)
+		if err != nil {
+			t.Fatalf("Prune(archive=%v) failed: %v", archive, err)
+		}
+		if len(pruned) != 2 {
+			t.Errorf("archive=%v: expected 2 pruned exports, got %v", archive, pruned)
+		}
+		if exists(oldPDF) || exists(oldXLSX) {
+			t.Errorf("archive=%v: expected old exports to be gone from the export directory", archive)
+		}
+		if !exists(recent) {
+			t.Errorf("archive=%v: expected recent export to be kept", archive)
+		}
+		if !exists(other) || !exists(foreignPDF) {
+			t.Errorf("archive=%v: expected files not written by the exporters to be left alone", archive)
+		}
+
+		archived := exists(filepath.Join(dir, ArchiveDirName, "timesheet_01-2024.pdf"))
+		if archived != archive {
+			t.Errorf("archive=%v: archived copy present = %v", archive, archived)
+		}
+	}
+}
+
+func TestPruneMissingDir(t *testing.T) {
+	pruned, err := Prune(filepath.Join(t.TempDir(), "missing"), time.Hour, false, time.Now())
+	if err != nil || len(pruned) != 0 {
+		t.Errorf("Expected a missing directory to be a no-op, got %v, %v", pruned, err)
+	}
+}
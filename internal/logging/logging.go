@@ -1,16 +1,33 @@
 package logging
 
 import (
+	"bytes"
+	"encoding/json"
 	"fmt"
+	"io"
 	"log"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 )
 
+// Level is the severity of a LogFields message
+type Level string
+
+const (
+	LevelDebug Level = "debug"
+	LevelInfo  Level = "info"
+	LevelWarn  Level = "warn"
+	LevelError Level = "error"
+)
+
 var (
-	verbose bool
-	logFile *os.File
+	verbose    bool
+	jsonFormat bool
+	logFile    *os.File
+	console    io.Writer = os.Stdout
 )
 
 // SetVerbose sets the verbose mode
@@ -18,16 +35,49 @@ func SetVerbose(v bool) {
 	verbose = v
 }
 
+// SetConsole sets where verbose messages are printed (stdout by default)
+func SetConsole(w io.Writer) {
+	console = w
+}
+
 // IsVerbose returns whether verbose mode is enabled
 func IsVerbose() bool {
 	return verbose
 }
 
+// SetJSON switches between plain text and JSON output. In JSON mode every
+// message, including those of the standard log package, is written as one
+// JSON object per line with level, time and msg keys.
+func SetJSON(enabled bool) {
+	if enabled == jsonFormat {
+		return
+	}
+	jsonFormat = enabled
+	if enabled {
+		log.SetFlags(0)
+		log.SetOutput(&jsonLineWriter{out: log.Writer()})
+		return
+	}
+	if w, ok := log.Writer().(*jsonLineWriter); ok {
+		log.SetOutput(w.out)
+	}
+	log.SetFlags(log.LstdFlags)
+}
+
+// IsJSON returns whether JSON output is enabled
+func IsJSON() bool {
+	return jsonFormat
+}
+
 // Log prints a message if verbose mode is enabled
 func Log(format string, v ...interface{}) {
 	if verbose {
+		if jsonFormat {
+			writeJSON(LevelInfo, fmt.Sprintf(format, v...), nil)
+			return
+		}
 		// Print to console
-		fmt.Printf(format+"\n", v...)
+		fmt.Fprintf(console, format+"\n", v...)
 		// Also log to file (only if logFile is not stderr)
 		if logFile != nil && logFile != os.Stderr {
 			log.Printf(format, v...)
@@ -35,6 +85,85 @@ func Log(format string, v ...interface{}) {
 	}
 }
 
+// LogFields prints a message with structured fields if verbose mode is
+// enabled. In JSON mode the fields become keys of the line's object;
+// otherwise they're appended to the message as sorted key=value pairs.
+func LogFields(level Level, msg string, fields map[string]any) {
+	if !verbose {
+		return
+	}
+	if jsonFormat {
+		writeJSON(level, msg, fields)
+		return
+	}
+	line := msg + formatFields(fields)
+	fmt.Fprintln(console, line)
+	if logFile != nil && logFile != os.Stderr {
+		log.Print(line)
+	}
+}
+
+// formatFields renders fields as " key=value" pairs in key order
+func formatFields(fields map[string]any) string {
+	keys := make([]string, 0, len(fields))
+	for key := range fields {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		fmt.Fprintf(&b, " %s=%+v", key, fields[key])
+	}
+	return b.String()
+}
+
+// jsonLine encodes a message as a JSON object. Fields named level, time or
+// msg are overridden; errors are written as their message.
+func jsonLine(level Level, msg string, fields map[string]any) []byte {
+	object := make(map[string]any, len(fields)+3)
+	for key, value := range fields {
+		if err, ok := value.(error); ok {
+			value = err.Error()
+		}
+		object[key] = value
+	}
+	object["level"] = level
+	object["time"] = time.Now().Format(time.RFC3339)
+	object["msg"] = msg
+
+	line, err := json.Marshal(object)
+	if err != nil {
+		// Fall back to the message alone when a field can't be encoded
+		line, _ = json.Marshal(map[string]any{
+			"level": level, "time": object["time"], "msg": msg, "fieldsError": err.Error(),
+		})
+	}
+	return append(line, '\n')
+}
+
+// writeJSON prints a JSON line to the console and the log file
+func writeJSON(level Level, msg string, fields map[string]any) {
+	line := jsonLine(level, msg, fields)
+	console.Write(line)
+	if logFile != nil && logFile != os.Stderr {
+		logFile.Write(line)
+	}
+}
+
+// jsonLineWriter wraps the standard log package's lines as JSON info lines
+type jsonLineWriter struct {
+	out io.Writer
+}
+
+func (w *jsonLineWriter) Write(p []byte) (int, error) {
+	msg := string(bytes.TrimRight(p, "\n"))
+	if _, err := w.out.Write(jsonLine(LevelInfo, msg, nil)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
 // SetupLogging initializes logging and returns the log file.
 func SetupLogging() *os.File {
 	homeDir, err := os.UserHomeDir()
@@ -61,6 +190,7 @@ func SetupLogging() *os.File {
 	}
 
 	// Create a multi-writer to write to both file and console
+	logFile = f
 	log.SetOutput(f)
 	log.Printf("Logging initialized at %s", time.Now().Format("15:04:05"))
 
@@ -0,0 +1,80 @@
+package logging
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// captureConsole enables verbose output into a buffer for the test
+func captureConsole(t *testing.T) *bytes.Buffer {
+	t.Helper()
+	var buf bytes.Buffer
+	previous := console
+	console = &buf
+	SetVerbose(true)
+	t.Cleanup(func() {
+		console = previous
+		SetVerbose(false)
+		SetJSON(false)
+	})
+	return &buf
+}
+
+func TestLogFields_Text(t *testing.T) {
+	buf := captureConsole(t)
+
+	LogFields(LevelWarn, "DUAL MODE: Entry count mismatch", map[string]any{"remote": 2, "local": 3, "operation": "GetAll"})
+
+	want := "DUAL MODE: Entry count mismatch local=3 operation=GetAll remote=2\n"
+	if buf.String() != want {
+		t.Errorf("got %q, want %q", buf.String(), want)
+	}
+}
+
+func TestLogFields_JSON(t *testing.T) {
+	buf := captureConsole(t)
+	SetJSON(true)
+
+	LogFields(LevelError, "Error syncing table", map[string]any{
+		"table": "timesheet", "error": errors.New("connection refused"), "msg": "ignored",
+	})
+	Log("Sync completed in %s", "1s")
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected 2 lines, got %d: %q", len(lines), buf.String())
+	}
+
+	var first map[string]any
+	if err := json.Unmarshal([]byte(lines[0]), &first); err != nil {
+		t.Fatalf("line isn't JSON: %v", err)
+	}
+	if first["level"] != "error" || first["msg"] != "Error syncing table" ||
+		first["table"] != "timesheet" || first["error"] != "connection refused" {
+		t.Errorf("unexpected fields: %v", first)
+	}
+	if _, ok := first["time"].(string); !ok {
+		t.Errorf("expected a time, got %v", first["time"])
+	}
+
+	var second map[string]any
+	if err := json.Unmarshal([]byte(lines[1]), &second); err != nil {
+		t.Fatalf("line isn't JSON: %v", err)
+	}
+	if second["level"] != "info" || second["msg"] != "Sync completed in 1s" {
+		t.Errorf("unexpected fields: %v", second)
+	}
+}
+
+func TestLogFields_QuietWithoutVerbose(t *testing.T) {
+	buf := captureConsole(t)
+	SetVerbose(false)
+
+	LogFields(LevelWarn, "mismatch", nil)
+	if buf.Len() != 0 {
+		t.Errorf("expected no output, got %q", buf.String())
+	}
+}
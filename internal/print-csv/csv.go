@@ -0,0 +1,65 @@
+package printCSV
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"timesheet/internal/document"
+	"timesheet/internal/exports"
+	"timesheet/internal/utils"
+)
+
+// csvHeader matches the columns of the timesheet table in the TUI
+var csvHeader = []string{"Date", "Day", "Client", "Hours", "Training", "Vacation", "Idle", "Holiday", "Sick", "Total"}
+
+// utf8BOM makes Excel read the file as UTF-8 instead of the system code
+// page, so client names with accents come through intact
+const utf8BOM = "\ufeff"
+
+// TimesheetToCSV saves a timesheet document as a CSV file and returns its
+// path
+func TimesheetToCSV(doc document.Timesheet) (string, error) {
+	filename, err := exports.Path("Timesheet.csv")
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Create(filename)
+	if err != nil {
+		return "", fmt.Errorf("failed to create csv file: %w", err)
+	}
+	if err := writeTimesheetCSV(f, doc); err != nil {
+		f.Close()
+		return "", fmt.Errorf("failed to write csv file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return "", fmt.Errorf("failed to save csv file: %w", err)
+	}
+	return filename, nil
+}
+
+// writeTimesheetCSV writes a header and one row per day of the month. The
+// hour cells of days without an entry are left empty.
+func writeTimesheetCSV(w io.Writer, doc document.Timesheet) error {
+	if _, err := io.WriteString(w, utf8BOM); err != nil {
+		return err
+	}
+	writer := csv.NewWriter(w)
+	if err := writer.Write(csvHeader); err != nil {
+		return err
+	}
+	for _, day := range doc.Days {
+		row := []string{day.Date, day.Weekday, day.ClientName, "", "", "", "", "", "", ""}
+		if day.Logged {
+			hours := day.Hours
+			for i, h := range []float64{hours.Client, hours.Training, hours.Vacation, hours.Idle, hours.Holiday, hours.Sick, hours.Total} {
+				row[3+i] = utils.FormatHours(h)
+			}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
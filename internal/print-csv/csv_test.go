@@ -0,0 +1,32 @@
+package printCSV
+
+import (
+	"strings"
+	"testing"
+	"time"
+	"timesheet/internal/document"
+)
+
+func TestWriteTimesheetCSV(t *testing.T) {
+	doc := document.Timesheet{
+		Year:  2024,
+		Month: time.March,
+		Days: []document.Day{
+			{Date: "2024-03-01", Weekday: "Friday", Logged: true, ClientName: "Café, Inc", Hours: document.Hours{Client: 7.5, Idle: 0.5, Total: 8}},
+			{Date: "2024-03-02", Weekday: "Saturday", Weekend: true},
+		},
+	}
+
+	var out strings.Builder
+	if err := writeTimesheetCSV(&out, doc); err != nil {
+		t.Fatalf("writeTimesheetCSV failed: %v", err)
+	}
+
+	want := "\ufeff" +
+		"Date,Day,Client,Hours,Training,Vacation,Idle,Holiday,Sick,Total\n" +
+		"2024-03-01,Friday,\"Café, Inc\",7.5,0,0,0.5,0,0,8\n" +
+		"2024-03-02,Saturday,,,,,,,,\n"
+	if out.String() != want {
+		t.Errorf("Unexpected CSV:\n%q\nwant\n%q", out.String(), want)
+	}
+}
@@ -7,6 +7,8 @@ import (
 	"strings"
 	"time"
 	"timesheet/internal/config"
+	"timesheet/internal/document"
+	"timesheet/internal/exports"
 
 	"github.com/xuri/excelize/v2"
 )
@@ -67,7 +69,27 @@ func getTranslations(lang string) excelTranslations {
 	}
 }
 
-func TimesheetToExcel(timesheetData []TimesheetRow, year int, month time.Month) (string, error) {
+// TimesheetRows returns the logged days of a timesheet document as rows
+func TimesheetRows(doc document.Timesheet) []TimesheetRow {
+	var rows []TimesheetRow
+	for _, day := range doc.LoggedDays() {
+		rows = append(rows, TimesheetRow{
+			Date:          day.Date,
+			ClientName:    day.ClientName,
+			ClientHours:   day.Hours.Client,
+			TrainingHours: day.Hours.Training,
+			VacationHours: day.Hours.Vacation,
+			IdleHours:     day.Hours.Idle,
+			HolidayHours:  day.Hours.Holiday,
+			SickHours:     day.Hours.Sick,
+		})
+	}
+	return rows
+}
+
+// TimesheetToExcel saves a timesheet document as an Excel file and returns
+// its path
+func TimesheetToExcel(doc document.Timesheet) (string, error) {
 	f := excelize.NewFile()
 	defer func() {
 		if err := f.Close(); err != nil {
@@ -75,12 +97,9 @@ func TimesheetToExcel(timesheetData []TimesheetRow, year int, month time.Month)
 		}
 	}()
 
-	// Get user configuration
-	name, company, _, err := config.GetUserConfig()
-	if err != nil {
-		name = "Unknown User"
-		company = "Unknown Company"
-	}
+	timesheetData := TimesheetRows(doc)
+	year, month := doc.Year, doc.Month
+	name, company := doc.Name, doc.Company
 
 	// Get client name from first entry (or empty)
 	clientName := ""
@@ -441,9 +460,8 @@ func TimesheetToExcel(timesheetData []TimesheetRow, year int, month time.Month)
 		// Set row height (1.5x)
 		f.SetRowHeight(sheetName, excelRow, rowHeight)
 
-		// Check if this day is a weekend (Saturday=6, Sunday=0)
-		date := time.Date(year, month, day, 0, 0, 0, 0, time.UTC)
-		isWeekend := date.Weekday() == time.Saturday || date.Weekday() == time.Sunday
+		// The document marks the configured weekend days
+		isWeekend := doc.Days[day-1].Weekend
 
 		// Day number
 		f.SetCellValue(sheetName, fmt.Sprintf("B%d", excelRow), day)
@@ -575,7 +593,10 @@ func TimesheetToExcel(timesheetData []TimesheetRow, year int, month time.Month)
 	// Generate filename with month and year
 	monthAbbrev := t.MonthAbbrevs[month-1]
 	companyClean := strings.ReplaceAll(company, " ", "")
-	filename := fmt.Sprintf("%s_%s_%s_%s_%d.xlsx", t.FilePrefix, companyClean, t.FileIntern, monthAbbrev, year)
+	filename, err := exports.Path(fmt.Sprintf("%s_%s_%s_%s_%d.xlsx", t.FilePrefix, companyClean, t.FileIntern, monthAbbrev, year))
+	if err != nil {
+		return "", err
+	}
 	if err := f.SaveAs(filename); err != nil {
 		return "", fmt.Errorf("failed to save excel file: %w", err)
 	}
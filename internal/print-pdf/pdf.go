@@ -5,9 +5,10 @@ import (
 	"os"
 	"regexp"
 	"strings"
-	"time"
-	"timesheet/internal/config"
+	"timesheet/internal/document"
 	"timesheet/internal/email"
+	"timesheet/internal/exports"
+	"timesheet/internal/utils"
 	"unicode"
 
 	"github.com/jung-kurt/gofpdf"
@@ -56,8 +57,68 @@ func stripANSI(str string) string {
 	return result.String()
 }
 
-// TimesheetToPDF converts a timesheet view to a PDF file
-func TimesheetToPDF(viewContent string, sendAsEmail bool) (string, error) {
+// timesheetColumns are the column titles of the timesheet table
+var timesheetColumns = []string{"Date", "Day", "Client", "Hours", "Training", "Vacation", "Idle", "Holiday", "Sick", "Total"}
+
+// timesheetLines lays out a timesheet document as lines of a plain-text
+// table, one row per day of the month followed by the totals
+func timesheetLines(doc document.Timesheet) []string {
+	hourCells := func(h document.Hours) []string {
+		return []string{
+			utils.FormatHours(h.Client), utils.FormatHours(h.Training), utils.FormatHours(h.Vacation),
+			utils.FormatHours(h.Idle), utils.FormatHours(h.Holiday), utils.FormatHours(h.Sick), utils.FormatHours(h.Total),
+		}
+	}
+
+	rows := [][]string{timesheetColumns}
+	for _, day := range doc.Days {
+		row := []string{day.Date, day.Weekday, "-"}
+		if day.Logged {
+			row[2] = day.ClientName
+			row = append(row, hourCells(day.Hours)...)
+		} else {
+			for range 7 {
+				row = append(row, "-")
+			}
+		}
+		rows = append(rows, row)
+	}
+	rows = append(rows, append([]string{"Total:", "", ""}, hourCells(doc.Totals)...))
+
+	widths := make([]int, len(timesheetColumns))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], len(cell))
+		}
+	}
+	format := func(row []string) string {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = fmt.Sprintf("%-*s", widths[i], cell)
+		}
+		return strings.TrimRight(strings.Join(cells, "  "), " ")
+	}
+
+	tableWidth := len(widths)*2 - 2
+	for _, w := range widths {
+		tableWidth += w
+	}
+	separator := strings.Repeat("-", tableWidth)
+
+	lines := []string{format(rows[0]), separator}
+	for _, row := range rows[1 : len(rows)-1] {
+		lines = append(lines, format(row))
+	}
+	lines = append(lines, separator, format(rows[len(rows)-1]), "")
+
+	delta := doc.Totals.Total - float64(doc.ExpectedHours)
+	lines = append(lines, fmt.Sprintf("Expected: %dh    Difference: %+gh", doc.ExpectedHours, delta))
+	return lines
+}
+
+// TimesheetToPDF saves a timesheet document as a PDF file, emails it when
+// sendAsEmail is set, and returns its path
+func TimesheetToPDF(doc document.Timesheet, sendAsEmail bool) (string, error) {
 	pdf := gofpdf.New("P", "mm", "A4", "")
 	pdf.AddPage()
 	pdf.SetFont("Courier", "", 10) // Monospaced font works better for tabular data
@@ -71,62 +132,38 @@ func TimesheetToPDF(viewContent string, sendAsEmail bool) (string, error) {
 		pdf.Image(logoPath, 10, 10, 30, 0, false, "", 0, "")
 	}
 
-	// Get user configuration
-	name, company, freeSpeech, err := config.GetUserConfig()
-	if err != nil {
-		// Use default values if config cannot be read
-		name = "Unknown User"
-		company = "Unknown Company"
-		freeSpeech = "Free Speech"
-	}
-
 	pdf.SetTextColor(255, 20, 147)
-	pdf.Text(60, 12, "Name: "+name)
-	pdf.Text(60, 20, "Company: "+company)
-	pdf.Text(60, 28, freeSpeech)
+	pdf.Text(60, 12, "Name: "+doc.Name)
+	pdf.Text(60, 20, "Company: "+doc.Company)
+	pdf.Text(60, 28, doc.FreeSpeech)
+	pdf.Text(60, 36, doc.Title())
 
 	pdf.SetFont("Courier", "", 6) // Monospaced font works better for tabular data
 	pdf.SetTextColor(0, 0, 0)
 
-	// Clean the view content
-	viewContent = stripANSI(viewContent)
-	lines := strings.Split(viewContent, "\n")
-
-	// Remove the last line (if there are any lines)
-	if len(lines) > 0 {
-		lines = lines[:len(lines)-1]
-	}
-
 	// Set starting position
 	y := 50.0
 	lineHeight := 5.0
 
-	// Add each line to the PDF
-	for _, line := range lines {
-		// Special formatting for the total line
-		if strings.HasPrefix(line, "    Total:") {
-			parts := strings.SplitN(line, ":", 2)
-			if len(parts) == 2 {
-				pdf.Text(10, y, "   Total:")
-				pdf.Text(124, y, strings.TrimSpace(parts[1])) // Position the numbers at x=50
-			} else {
-				pdf.Text(10, y, line)
-			}
-		} else {
-			pdf.Text(10, y, line)
-		}
+	for _, line := range timesheetLines(doc) {
+		pdf.Text(10, y, stripANSI(line))
 		y += lineHeight
 	}
 
 	// Save the PDF with a more descriptive filename
-	filename := fmt.Sprintf("timesheet_%s.pdf", time.Now().Format("01-2006"))
+	filename, err := exports.Path(fmt.Sprintf("timesheet_%02d-%d.pdf", doc.Month, doc.Year))
+	if err != nil {
+		return "", err
+	}
 	err = pdf.OutputFileAndClose(filename)
 	if err != nil {
 		return "", err
 	}
 
 	if sendAsEmail {
-		email.EmailAttachment(filename)
+		if err := email.EmailAttachment(filename); err != nil {
+			return filename, fmt.Errorf("saved to %s but not sent: %w", filename, err)
+		}
 	}
 
 	return filename, nil
@@ -3,6 +3,8 @@ package printPDF
 import (
 	"strings"
 	"testing"
+	"time"
+	"timesheet/internal/document"
 )
 
 func TestStripANSI(t *testing.T) {
@@ -79,3 +81,31 @@ func BenchmarkStripANSI(b *testing.B) {
 		stripANSI(input)
 	}
 }
+
+func TestTimesheetLines(t *testing.T) {
+	doc := document.Timesheet{
+		Year:  2024,
+		Month: time.March,
+		Days: []document.Day{
+			{Date: "2024-03-01", Weekday: "Friday", Logged: true, ClientName: "Acme Corp", Hours: document.Hours{Client: 8, Total: 8}},
+			{Date: "2024-03-02", Weekday: "Saturday", Weekend: true},
+		},
+		Totals:        document.Hours{Client: 8, Total: 8},
+		ExpectedHours: 16,
+	}
+
+	lines := timesheetLines(doc)
+	want := []string{
+		"Date        Day       Client     Hours  Training  Vacation  Idle  Holiday  Sick  Total",
+		"--------------------------------------------------------------------------------------",
+		"2024-03-01  Friday    Acme Corp  8      0         0         0     0        0     8",
+		"2024-03-02  Saturday  -          -      -         -         -     -        -     -",
+		"--------------------------------------------------------------------------------------",
+		"Total:                           8      0         0         0     0        0     8",
+		"",
+		"Expected: 16h    Difference: -8h",
+	}
+	if strings.Join(lines, "\n") != strings.Join(want, "\n") {
+		t.Errorf("timesheetLines() =\n%s\nwant\n%s", strings.Join(lines, "\n"), strings.Join(want, "\n"))
+	}
+}
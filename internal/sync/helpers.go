@@ -3,18 +3,21 @@ package sync
 import (
 	"database/sql"
 	"fmt"
-	"time"
 
 	"timesheet/internal/db"
 )
 
 // Internal record types with timestamps for sync
 type clientRecord struct {
-	Id        int
-	Name      string
-	CreatedAt string
-	UpdatedAt string
-	IsActive  int
+	Id               int
+	Name             string
+	CreatedAt        string
+	UpdatedAt        string
+	IsActive         int
+	Currency         string
+	MinBillableHours float64
+	MonthlyHourCap   int
+	TotalHourCap     int
 }
 
 type clientRateRecord struct {
@@ -22,6 +25,7 @@ type clientRateRecord struct {
 	ClientId      int
 	HourlyRate    float64
 	EffectiveDate string
+	EndDate       string
 	Notes         string
 	CreatedAt     string
 	UpdatedAt     string
@@ -31,12 +35,13 @@ type timesheetRecord struct {
 	Id            int
 	Date          string
 	ClientName    string
-	ClientHours   sql.NullInt64
-	VacationHours sql.NullInt64
-	IdleHours     sql.NullInt64
-	TrainingHours sql.NullInt64
-	SickHours     sql.NullInt64
-	HolidayHours  sql.NullInt64
+	ClientHours   sql.NullFloat64
+	VacationHours sql.NullFloat64
+	IdleHours     sql.NullFloat64
+	TrainingHours sql.NullFloat64
+	SickHours     sql.NullFloat64
+	HolidayHours  sql.NullFloat64
+	Note          sql.NullString
 	ClientId      sql.NullInt64
 	CreatedAt     string
 	UpdatedAt     string
@@ -46,8 +51,9 @@ type trainingBudgetRecord struct {
 	Id             int
 	Date           string
 	TrainingName   string
-	Hours          int
+	Hours          float64
 	CostWithoutVat float64
+	ReceiptPath    string
 	CreatedAt      string
 	UpdatedAt      string
 }
@@ -55,7 +61,7 @@ type trainingBudgetRecord struct {
 // ============== Clients ==============
 
 func (s *SyncService) getClientsFromDB(dbConn *sql.DB, dbType string) ([]clientRecord, error) {
-	query := `SELECT id, name, COALESCE(created_at, ''), COALESCE(updated_at, ''), COALESCE(is_active, 1) FROM clients`
+	query := `SELECT id, name, COALESCE(created_at, ''), COALESCE(updated_at, ''), COALESCE(is_active, 1), COALESCE(currency, ''), COALESCE(min_billable_hours, 0), COALESCE(monthly_hour_cap, 0), COALESCE(total_hour_cap, 0) FROM clients`
 	rows, err := dbConn.Query(query)
 	if err != nil {
 		return nil, err
@@ -65,7 +71,7 @@ func (s *SyncService) getClientsFromDB(dbConn *sql.DB, dbType string) ([]clientR
 	var clients []clientRecord
 	for rows.Next() {
 		var c clientRecord
-		if err := rows.Scan(&c.Id, &c.Name, &c.CreatedAt, &c.UpdatedAt, &c.IsActive); err != nil {
+		if err := rows.Scan(&c.Id, &c.Name, &c.CreatedAt, &c.UpdatedAt, &c.IsActive, &c.Currency, &c.MinBillableHours, &c.MonthlyHourCap, &c.TotalHourCap); err != nil {
 			return nil, err
 		}
 		clients = append(clients, c)
@@ -94,33 +100,33 @@ func (s *SyncService) getClientIdMap(dbConn *sql.DB, dbType string) (map[string]
 }
 
 func (s *SyncService) insertClientToRemote(c clientRecord) error {
-	query := `INSERT INTO clients (name, created_at, updated_at, is_active) VALUES ($1, $2, $3, $4)`
-	_, err := s.remoteDB.Exec(query, c.Name, c.CreatedAt, c.UpdatedAt, c.IsActive)
+	query := `INSERT INTO clients (name, created_at, updated_at, is_active, currency, min_billable_hours, monthly_hour_cap, total_hour_cap) VALUES ($1, $2, $3, $4, $5, $6, $7, $8)`
+	_, err := s.remoteDB.Exec(query, c.Name, c.CreatedAt, c.UpdatedAt, c.IsActive, c.Currency, c.MinBillableHours, c.MonthlyHourCap, c.TotalHourCap)
 	return err
 }
 
 func (s *SyncService) updateClientInRemote(c clientRecord, remoteId int) error {
-	query := `UPDATE clients SET name = $1, updated_at = $2, is_active = $3 WHERE id = $4`
-	_, err := s.remoteDB.Exec(query, c.Name, c.UpdatedAt, c.IsActive, remoteId)
+	query := `UPDATE clients SET name = $1, updated_at = $2, is_active = $3, currency = $4, min_billable_hours = $5, monthly_hour_cap = $6, total_hour_cap = $7 WHERE id = $8`
+	_, err := s.remoteDB.Exec(query, c.Name, c.UpdatedAt, c.IsActive, c.Currency, c.MinBillableHours, c.MonthlyHourCap, c.TotalHourCap, remoteId)
 	return err
 }
 
 func (s *SyncService) insertClientToLocal(c clientRecord) error {
-	query := `INSERT INTO clients (name, created_at, updated_at, is_active) VALUES (?, ?, ?, ?)`
-	_, err := s.localDB.Exec(query, c.Name, c.CreatedAt, c.UpdatedAt, c.IsActive)
+	query := `INSERT INTO clients (name, created_at, updated_at, is_active, currency, min_billable_hours, monthly_hour_cap, total_hour_cap) VALUES (?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := s.localDB.Exec(query, c.Name, c.CreatedAt, c.UpdatedAt, c.IsActive, c.Currency, c.MinBillableHours, c.MonthlyHourCap, c.TotalHourCap)
 	return err
 }
 
 func (s *SyncService) updateClientInLocal(c clientRecord, localId int) error {
-	query := `UPDATE clients SET name = ?, updated_at = ?, is_active = ? WHERE id = ?`
-	_, err := s.localDB.Exec(query, c.Name, c.UpdatedAt, c.IsActive, localId)
+	query := `UPDATE clients SET name = ?, updated_at = ?, is_active = ?, currency = ?, min_billable_hours = ?, monthly_hour_cap = ?, total_hour_cap = ? WHERE id = ?`
+	_, err := s.localDB.Exec(query, c.Name, c.UpdatedAt, c.IsActive, c.Currency, c.MinBillableHours, c.MonthlyHourCap, c.TotalHourCap, localId)
 	return err
 }
 
 // ============== Client Rates ==============
 
 func (s *SyncService) getClientRatesFromDB(dbConn *sql.DB, dbType string) ([]clientRateRecord, error) {
-	query := `SELECT id, client_id, hourly_rate, effective_date, COALESCE(notes, ''), COALESCE(created_at, ''), COALESCE(updated_at, '') FROM client_rates`
+	query := `SELECT id, client_id, hourly_rate, effective_date, COALESCE(end_date, ''), COALESCE(notes, ''), COALESCE(created_at, ''), COALESCE(updated_at, '') FROM client_rates`
 	rows, err := dbConn.Query(query)
 	if err != nil {
 		return nil, err
@@ -130,7 +136,7 @@ func (s *SyncService) getClientRatesFromDB(dbConn *sql.DB, dbType string) ([]cli
 	var rates []clientRateRecord
 	for rows.Next() {
 		var r clientRateRecord
-		if err := rows.Scan(&r.Id, &r.ClientId, &r.HourlyRate, &r.EffectiveDate, &r.Notes, &r.CreatedAt, &r.UpdatedAt); err != nil {
+		if err := rows.Scan(&r.Id, &r.ClientId, &r.HourlyRate, &r.EffectiveDate, &r.EndDate, &r.Notes, &r.CreatedAt, &r.UpdatedAt); err != nil {
 			return nil, err
 		}
 		rates = append(rates, r)
@@ -139,33 +145,33 @@ func (s *SyncService) getClientRatesFromDB(dbConn *sql.DB, dbType string) ([]cli
 }
 
 func (s *SyncService) insertClientRateToRemote(r clientRateRecord, remoteClientId int) error {
-	query := `INSERT INTO client_rates (client_id, hourly_rate, effective_date, notes, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := s.remoteDB.Exec(query, remoteClientId, r.HourlyRate, r.EffectiveDate, r.Notes, r.CreatedAt, r.UpdatedAt)
+	query := `INSERT INTO client_rates (client_id, hourly_rate, effective_date, end_date, notes, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := s.remoteDB.Exec(query, remoteClientId, r.HourlyRate, r.EffectiveDate, r.EndDate, r.Notes, r.CreatedAt, r.UpdatedAt)
 	return err
 }
 
 func (s *SyncService) updateClientRateInRemote(r clientRateRecord, remoteId int, remoteClientId int) error {
-	query := `UPDATE client_rates SET client_id = $1, hourly_rate = $2, effective_date = $3, notes = $4, updated_at = $5 WHERE id = $6`
-	_, err := s.remoteDB.Exec(query, remoteClientId, r.HourlyRate, r.EffectiveDate, r.Notes, r.UpdatedAt, remoteId)
+	query := `UPDATE client_rates SET client_id = $1, hourly_rate = $2, effective_date = $3, end_date = $4, notes = $5, updated_at = $6 WHERE id = $7`
+	_, err := s.remoteDB.Exec(query, remoteClientId, r.HourlyRate, r.EffectiveDate, r.EndDate, r.Notes, r.UpdatedAt, remoteId)
 	return err
 }
 
 func (s *SyncService) insertClientRateToLocal(r clientRateRecord, localClientId int) error {
-	query := `INSERT INTO client_rates (client_id, hourly_rate, effective_date, notes, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`
-	_, err := s.localDB.Exec(query, localClientId, r.HourlyRate, r.EffectiveDate, r.Notes, r.CreatedAt, r.UpdatedAt)
+	query := `INSERT INTO client_rates (client_id, hourly_rate, effective_date, end_date, notes, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err := s.localDB.Exec(query, localClientId, r.HourlyRate, r.EffectiveDate, r.EndDate, r.Notes, r.CreatedAt, r.UpdatedAt)
 	return err
 }
 
 func (s *SyncService) updateClientRateInLocal(r clientRateRecord, localId int, localClientId int) error {
-	query := `UPDATE client_rates SET client_id = ?, hourly_rate = ?, effective_date = ?, notes = ?, updated_at = ? WHERE id = ?`
-	_, err := s.localDB.Exec(query, localClientId, r.HourlyRate, r.EffectiveDate, r.Notes, r.UpdatedAt, localId)
+	query := `UPDATE client_rates SET client_id = ?, hourly_rate = ?, effective_date = ?, end_date = ?, notes = ?, updated_at = ? WHERE id = ?`
+	_, err := s.localDB.Exec(query, localClientId, r.HourlyRate, r.EffectiveDate, r.EndDate, r.Notes, r.UpdatedAt, localId)
 	return err
 }
 
 // ============== Timesheet ==============
 
 func (s *SyncService) getTimesheetFromDB(dbConn *sql.DB, dbType string) ([]timesheetRecord, error) {
-	query := `SELECT id, date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, client_id, COALESCE(created_at, ''), COALESCE(updated_at, '') FROM timesheet`
+	query := `SELECT id, date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, note, client_id, COALESCE(created_at, ''), COALESCE(updated_at, '') FROM timesheet`
 	rows, err := dbConn.Query(query)
 	if err != nil {
 		return nil, err
@@ -175,7 +181,7 @@ func (s *SyncService) getTimesheetFromDB(dbConn *sql.DB, dbType string) ([]times
 	var entries []timesheetRecord
 	for rows.Next() {
 		var e timesheetRecord
-		if err := rows.Scan(&e.Id, &e.Date, &e.ClientName, &e.ClientHours, &e.VacationHours, &e.IdleHours, &e.TrainingHours, &e.SickHours, &e.HolidayHours, &e.ClientId, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		if err := rows.Scan(&e.Id, &e.Date, &e.ClientName, &e.ClientHours, &e.VacationHours, &e.IdleHours, &e.TrainingHours, &e.SickHours, &e.HolidayHours, &e.Note, &e.ClientId, &e.CreatedAt, &e.UpdatedAt); err != nil {
 			return nil, err
 		}
 		entries = append(entries, e)
@@ -184,33 +190,33 @@ func (s *SyncService) getTimesheetFromDB(dbConn *sql.DB, dbType string) ([]times
 }
 
 func (s *SyncService) insertTimesheetToRemote(e timesheetRecord) error {
-	query := `INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, client_id, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)`
-	_, err := s.remoteDB.Exec(query, e.Date, e.ClientName, e.ClientHours, e.VacationHours, e.IdleHours, e.TrainingHours, e.SickHours, e.HolidayHours, e.ClientId, e.CreatedAt, e.UpdatedAt)
+	query := `INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, note, client_id, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12)`
+	_, err := s.remoteDB.Exec(query, e.Date, e.ClientName, e.ClientHours, e.VacationHours, e.IdleHours, e.TrainingHours, e.SickHours, e.HolidayHours, e.Note, e.ClientId, e.CreatedAt, e.UpdatedAt)
 	return err
 }
 
 func (s *SyncService) updateTimesheetInRemote(e timesheetRecord, remoteId int) error {
-	query := `UPDATE timesheet SET date = $1, client_name = $2, client_hours = $3, vacation_hours = $4, idle_hours = $5, training_hours = $6, sick_hours = $7, holiday_hours = $8, client_id = $9, updated_at = $10 WHERE id = $11`
-	_, err := s.remoteDB.Exec(query, e.Date, e.ClientName, e.ClientHours, e.VacationHours, e.IdleHours, e.TrainingHours, e.SickHours, e.HolidayHours, e.ClientId, e.UpdatedAt, remoteId)
+	query := `UPDATE timesheet SET date = $1, client_name = $2, client_hours = $3, vacation_hours = $4, idle_hours = $5, training_hours = $6, sick_hours = $7, holiday_hours = $8, note = $9, client_id = $10, updated_at = $11 WHERE id = $12`
+	_, err := s.remoteDB.Exec(query, e.Date, e.ClientName, e.ClientHours, e.VacationHours, e.IdleHours, e.TrainingHours, e.SickHours, e.HolidayHours, e.Note, e.ClientId, e.UpdatedAt, remoteId)
 	return err
 }
 
 func (s *SyncService) insertTimesheetToLocal(e timesheetRecord) error {
-	query := `INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, client_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
-	_, err := s.localDB.Exec(query, e.Date, e.ClientName, e.ClientHours, e.VacationHours, e.IdleHours, e.TrainingHours, e.SickHours, e.HolidayHours, e.ClientId, e.CreatedAt, e.UpdatedAt)
+	query := `INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, note, client_id, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?)`
+	_, err := s.localDB.Exec(query, e.Date, e.ClientName, e.ClientHours, e.VacationHours, e.IdleHours, e.TrainingHours, e.SickHours, e.HolidayHours, e.Note, e.ClientId, e.CreatedAt, e.UpdatedAt)
 	return err
 }
 
 func (s *SyncService) updateTimesheetInLocal(e timesheetRecord, localId int) error {
-	query := `UPDATE timesheet SET date = ?, client_name = ?, client_hours = ?, vacation_hours = ?, idle_hours = ?, training_hours = ?, sick_hours = ?, holiday_hours = ?, client_id = ?, updated_at = ? WHERE id = ?`
-	_, err := s.localDB.Exec(query, e.Date, e.ClientName, e.ClientHours, e.VacationHours, e.IdleHours, e.TrainingHours, e.SickHours, e.HolidayHours, e.ClientId, e.UpdatedAt, localId)
+	query := `UPDATE timesheet SET date = ?, client_name = ?, client_hours = ?, vacation_hours = ?, idle_hours = ?, training_hours = ?, sick_hours = ?, holiday_hours = ?, note = ?, client_id = ?, updated_at = ? WHERE id = ?`
+	_, err := s.localDB.Exec(query, e.Date, e.ClientName, e.ClientHours, e.VacationHours, e.IdleHours, e.TrainingHours, e.SickHours, e.HolidayHours, e.Note, e.ClientId, e.UpdatedAt, localId)
 	return err
 }
 
 // ============== Training Budget ==============
 
 func (s *SyncService) getTrainingBudgetFromDB(dbConn *sql.DB, dbType string) ([]trainingBudgetRecord, error) {
-	query := `SELECT id, date, training_name, hours, cost_without_vat, COALESCE(created_at, ''), COALESCE(updated_at, '') FROM training_budget`
+	query := `SELECT id, date, training_name, hours, cost_without_vat, COALESCE(receipt_path, ''), COALESCE(created_at, ''), COALESCE(updated_at, '') FROM training_budget`
 	rows, err := dbConn.Query(query)
 	if err != nil {
 		return nil, err
@@ -220,7 +226,7 @@ func (s *SyncService) getTrainingBudgetFromDB(dbConn *sql.DB, dbType string) ([]
 	var entries []trainingBudgetRecord
 	for rows.Next() {
 		var e trainingBudgetRecord
-		if err := rows.Scan(&e.Id, &e.Date, &e.TrainingName, &e.Hours, &e.CostWithoutVat, &e.CreatedAt, &e.UpdatedAt); err != nil {
+		if err := rows.Scan(&e.Id, &e.Date, &e.TrainingName, &e.Hours, &e.CostWithoutVat, &e.ReceiptPath, &e.CreatedAt, &e.UpdatedAt); err != nil {
 			return nil, err
 		}
 		entries = append(entries, e)
@@ -229,26 +235,26 @@ func (s *SyncService) getTrainingBudgetFromDB(dbConn *sql.DB, dbType string) ([]
 }
 
 func (s *SyncService) insertTrainingBudgetToRemote(e trainingBudgetRecord) error {
-	query := `INSERT INTO training_budget (date, training_name, hours, cost_without_vat, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6)`
-	_, err := s.remoteDB.Exec(query, e.Date, e.TrainingName, e.Hours, e.CostWithoutVat, e.CreatedAt, e.UpdatedAt)
+	query := `INSERT INTO training_budget (date, training_name, hours, cost_without_vat, receipt_path, created_at, updated_at) VALUES ($1, $2, $3, $4, $5, $6, $7)`
+	_, err := s.remoteDB.Exec(query, e.Date, e.TrainingName, e.Hours, e.CostWithoutVat, e.ReceiptPath, e.CreatedAt, e.UpdatedAt)
 	return err
 }
 
 func (s *SyncService) updateTrainingBudgetInRemote(e trainingBudgetRecord, remoteId int) error {
-	query := `UPDATE training_budget SET date = $1, training_name = $2, hours = $3, cost_without_vat = $4, updated_at = $5 WHERE id = $6`
-	_, err := s.remoteDB.Exec(query, e.Date, e.TrainingName, e.Hours, e.CostWithoutVat, e.UpdatedAt, remoteId)
+	query := `UPDATE training_budget SET date = $1, training_name = $2, hours = $3, cost_without_vat = $4, receipt_path = $5, updated_at = $6 WHERE id = $7`
+	_, err := s.remoteDB.Exec(query, e.Date, e.TrainingName, e.Hours, e.CostWithoutVat, e.ReceiptPath, e.UpdatedAt, remoteId)
 	return err
 }
 
 func (s *SyncService) insertTrainingBudgetToLocal(e trainingBudgetRecord) error {
-	query := `INSERT INTO training_budget (date, training_name, hours, cost_without_vat, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?)`
-	_, err := s.localDB.Exec(query, e.Date, e.TrainingName, e.Hours, e.CostWithoutVat, e.CreatedAt, e.UpdatedAt)
+	query := `INSERT INTO training_budget (date, training_name, hours, cost_without_vat, receipt_path, created_at, updated_at) VALUES (?, ?, ?, ?, ?, ?, ?)`
+	_, err := s.localDB.Exec(query, e.Date, e.TrainingName, e.Hours, e.CostWithoutVat, e.ReceiptPath, e.CreatedAt, e.UpdatedAt)
 	return err
 }
 
 func (s *SyncService) updateTrainingBudgetInLocal(e trainingBudgetRecord, localId int) error {
-	query := `UPDATE training_budget SET date = ?, training_name = ?, hours = ?, cost_without_vat = ?, updated_at = ? WHERE id = ?`
-	_, err := s.localDB.Exec(query, e.Date, e.TrainingName, e.Hours, e.CostWithoutVat, e.UpdatedAt, localId)
+	query := `UPDATE training_budget SET date = ?, training_name = ?, hours = ?, cost_without_vat = ?, receipt_path = ?, updated_at = ? WHERE id = ?`
+	_, err := s.localDB.Exec(query, e.Date, e.TrainingName, e.Hours, e.CostWithoutVat, e.ReceiptPath, e.UpdatedAt, localId)
 	return err
 }
 
@@ -515,19 +521,3 @@ func (s *SyncService) reconcileTombstones(
 
 	return result, nil
 }
-
-// InitialMigration performs a one-time migration from local to remote
-// This is used when setting up sync for the first time
-func (s *SyncService) InitialMigration() error {
-	stats := SyncStats{StartTime: time.Now()}
-
-	// Push all local data to remote (one direction only)
-	if err := s.Sync(SyncPushOnly); err != nil {
-		return fmt.Errorf("initial migration failed: %w", err)
-	}
-
-	stats.EndTime = time.Now()
-	stats.Duration = stats.EndTime.Sub(stats.StartTime)
-
-	return nil
-}
@@ -0,0 +1,42 @@
+package sync
+
+import (
+	"sync"
+	"time"
+)
+
+// Metrics holds cumulative sync counters for the lifetime of the process.
+// The API server exposes them on /metrics.
+type Metrics struct {
+	SyncCount     int
+	ErrorCount    int
+	RecordsPushed int
+	RecordsPulled int
+	LastDuration  time.Duration
+	LastSyncTime  time.Time
+}
+
+var (
+	metricsMu sync.Mutex
+	metrics   Metrics
+)
+
+// recordMetrics folds the stats of a finished sync into the process-wide counters
+func recordMetrics(stats SyncStats) {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+
+	metrics.SyncCount++
+	metrics.ErrorCount += len(stats.Errors)
+	metrics.RecordsPushed += stats.RecordsPushed
+	metrics.RecordsPulled += stats.RecordsPulled
+	metrics.LastDuration = stats.Duration
+	metrics.LastSyncTime = stats.EndTime
+}
+
+// GetMetrics returns a snapshot of the sync counters
+func GetMetrics() Metrics {
+	metricsMu.Lock()
+	defer metricsMu.Unlock()
+	return metrics
+}
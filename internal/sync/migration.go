@@ -0,0 +1,243 @@
+package sync
+
+import (
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"syscall"
+	"time"
+
+	"timesheet/internal/logging"
+)
+
+const (
+	defaultMigrationTries = 5
+	defaultMigrationDelay = 2 * time.Second
+)
+
+// TableProgress records how far the initial migration got with one table
+type TableProgress struct {
+	Name          string
+	Done          bool
+	RecordsPushed int // Across all attempts
+	Attempts      int
+	LastError     string
+}
+
+// MigrationProgress records how far the initial migration got, table by table
+type MigrationProgress struct {
+	Tables []TableProgress
+}
+
+// Complete reports whether every table has been migrated
+func (p MigrationProgress) Complete() bool {
+	if len(p.Tables) == 0 {
+		return false
+	}
+	for _, table := range p.Tables {
+		if !table.Done {
+			return false
+		}
+	}
+	return true
+}
+
+// GetMigrationProgress returns the progress of the initial migration
+func (s *SyncService) GetMigrationProgress() MigrationProgress {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return MigrationProgress{Tables: append([]TableProgress(nil), s.migration.Tables...)}
+}
+
+// InitialMigration performs a one-time migration from local to remote
+// This is used when setting up sync for the first time.
+//
+// Tables are pushed in order and a transient failure (dropped connection,
+// timeout) is retried with backoff. Retrying a table only sends the records
+// the remote doesn't have yet, because the push pass skips rows that are
+// already up to date there. If a table still fails, the error is returned
+// and calling InitialMigration again resumes with that table; tables that
+// finished are not pushed again. Progress is saved in the local database,
+// so this holds across restarts too.
+func (s *SyncService) InitialMigration() error {
+	return s.runMigration(s.syncTables())
+}
+
+// runMigration pushes tables that haven't been migrated yet; after a
+// completed migration it starts over
+func (s *SyncService) runMigration(tables []syncTable) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if s.migrating {
+		return errors.New("initial migration is already running")
+	}
+	s.migrating = true
+	defer func() { s.migrating = false }()
+
+	if len(s.migration.Tables) != len(tables) {
+		s.migration = s.loadMigrationProgress(tables)
+	}
+	if s.migration.Complete() {
+		s.migration = MigrationProgress{}
+		for _, table := range tables {
+			s.migration.Tables = append(s.migration.Tables, TableProgress{Name: table.name})
+		}
+		s.clearMigrationProgress()
+	}
+
+	stats := SyncStats{StartTime: time.Now()}
+	defer func() {
+		stats.EndTime = time.Now()
+		stats.Duration = stats.EndTime.Sub(stats.StartTime)
+		s.lastSyncStats = stats
+		recordMetrics(stats)
+	}()
+
+	for i, table := range tables {
+		progress := &s.migration.Tables[i]
+		if progress.Done {
+			continue
+		}
+
+		if err := s.migrateTable(table, progress, &stats); err != nil {
+			stats.Errors = append(stats.Errors, fmt.Sprintf("Error migrating %s: %v", table.name, err))
+			return fmt.Errorf("initial migration failed at %s: %w", table.name, err)
+		}
+		stats.TablesProcessed++
+	}
+
+	s.lastSyncTime = time.Now()
+	logging.Log("Initial migration completed (pushed: %d)", stats.RecordsPushed)
+	return nil
+}
+
+// migrateTable pushes one table, retrying transient failures. The caller
+// holds s.mu; it is released while waiting to retry so syncs and progress
+// reads aren't blocked for the whole backoff.
+func (s *SyncService) migrateTable(table syncTable, progress *TableProgress, stats *SyncStats) error {
+	delay := s.migrationDelay
+	for attempt := 1; ; attempt++ {
+		progress.Attempts++
+
+		// Records pushed before a failure are on the remote now, so they
+		// count even when the attempt fails
+		var attemptStats SyncStats
+		err := table.syncFunc(SyncPushOnly, &attemptStats)
+		progress.RecordsPushed += attemptStats.RecordsPushed
+		stats.RecordsPushed += attemptStats.RecordsPushed
+
+		if err == nil {
+			progress.Done = true
+			progress.LastError = ""
+			s.saveTableProgress(*progress)
+			return nil
+		}
+		progress.LastError = err.Error()
+		s.saveTableProgress(*progress)
+
+		if !isTransientError(err) || attempt >= s.migrationTries {
+			return err
+		}
+		logging.LogFields(logging.LevelWarn, "Migration failed, retrying", map[string]any{
+			"table": table.name, "attempt": attempt, "tries": s.migrationTries,
+			"retryInMs": delay.Milliseconds(), "error": err,
+		})
+		s.mu.Unlock()
+		time.Sleep(delay)
+		s.mu.Lock()
+		delay *= 2
+	}
+}
+
+// loadMigrationProgress returns the saved progress of the migration of
+// tables. Tables without saved progress start from scratch; if it can't be
+// read the migration starts over.
+func (s *SyncService) loadMigrationProgress(tables []syncTable) MigrationProgress {
+	saved := map[string]TableProgress{}
+	rows, err := s.localDB.Query(`SELECT table_name, done, records_pushed, attempts, last_error FROM sync_migration`)
+	if err != nil {
+		logging.LogFields(logging.LevelWarn, "Could not load migration progress", map[string]any{"error": err})
+	} else {
+		defer rows.Close()
+		for rows.Next() {
+			var p TableProgress
+			if err := rows.Scan(&p.Name, &p.Done, &p.RecordsPushed, &p.Attempts, &p.LastError); err != nil {
+				logging.LogFields(logging.LevelWarn, "Could not load migration progress", map[string]any{"error": err})
+				saved = map[string]TableProgress{}
+				break
+			}
+			saved[p.Name] = p
+		}
+	}
+
+	var progress MigrationProgress
+	for _, table := range tables {
+		p, ok := saved[table.name]
+		if !ok {
+			p = TableProgress{Name: table.name}
+		}
+		progress.Tables = append(progress.Tables, p)
+	}
+	return progress
+}
+
+// saveTableProgress stores one table's migration progress. A failure is
+// logged rather than returned: it only costs a resumed migration some
+// repeated work.
+func (s *SyncService) saveTableProgress(p TableProgress) {
+	_, err := s.localDB.Exec(`INSERT INTO sync_migration (table_name, done, records_pushed, attempts, last_error)
+		VALUES (?, ?, ?, ?, ?)
+		ON CONFLICT(table_name) DO UPDATE SET done = excluded.done, records_pushed = excluded.records_pushed,
+			attempts = excluded.attempts, last_error = excluded.last_error`,
+		p.Name, p.Done, p.RecordsPushed, p.Attempts, p.LastError)
+	if err != nil {
+		logging.LogFields(logging.LevelWarn, "Could not save migration progress", map[string]any{
+			"table": p.Name, "error": err,
+		})
+	}
+}
+
+// clearMigrationProgress forgets the saved progress, so the next migration
+// starts over
+func (s *SyncService) clearMigrationProgress() {
+	if _, err := s.localDB.Exec(`DELETE FROM sync_migration`); err != nil {
+		logging.LogFields(logging.LevelWarn, "Could not reset migration progress", map[string]any{"error": err})
+	}
+}
+
+// transientErrorMessages are driver error texts that mean the connection,
+// not the data, was the problem
+var transientErrorMessages = []string{
+	"bad connection",
+	"broken pipe",
+	"connection refused",
+	"connection reset",
+	"database is locked",
+	"i/o timeout",
+	"server closed the connection",
+	"unexpected eof",
+}
+
+// isTransientError reports whether err is worth retrying
+func isTransientError(err error) bool {
+	if errors.Is(err, driver.ErrBadConn) || errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF) ||
+		errors.Is(err, syscall.ECONNRESET) || errors.Is(err, syscall.ECONNREFUSED) || errors.Is(err, syscall.EPIPE) {
+		return true
+	}
+	var netErr net.Error
+	if errors.As(err, &netErr) && netErr.Timeout() {
+		return true
+	}
+
+	msg := strings.ToLower(err.Error())
+	for _, transient := range transientErrorMessages {
+		if strings.Contains(msg, transient) {
+			return true
+		}
+	}
+	return false
+}
@@ -27,6 +27,16 @@ type SyncService struct {
 
 	// Stats
 	lastSyncStats SyncStats
+
+	// How a record present on both sides is resolved
+	conflictStrategy ConflictStrategy
+
+	// Initial migration state, kept so a failed migration resumes where it
+	// stopped
+	migration      MigrationProgress
+	migrating      bool          // A migration is running, possibly waiting to retry
+	migrationTries int           // Attempts per table before giving up
+	migrationDelay time.Duration // Wait before the first retry; doubles each time
 }
 
 // SyncStats contains statistics about the last sync operation
@@ -49,13 +59,37 @@ const (
 	SyncPullOnly                    // Remote -> Local
 )
 
+// String returns the direction's name as used in log fields
+func (d SyncDirection) String() string {
+	switch d {
+	case SyncPushOnly:
+		return "push"
+	case SyncPullOnly:
+		return "pull"
+	default:
+		return "bidirectional"
+	}
+}
+
+// ConflictStrategy decides which side wins when a record exists in both
+// databases
+type ConflictStrategy int
+
+const (
+	NewestWins ConflictStrategy = iota // The record with the later updated_at wins
+	LocalWins                          // The SQLite record always wins
+	RemoteWins                         // The PostgreSQL record always wins
+)
+
 // NewSyncService creates a new sync service
 func NewSyncService(localDB, remoteDB *sql.DB, interval time.Duration) *SyncService {
 	return &SyncService{
-		localDB:      localDB,
-		remoteDB:     remoteDB,
-		syncInterval: interval,
-		stopChan:     make(chan struct{}),
+		localDB:        localDB,
+		remoteDB:       remoteDB,
+		syncInterval:   interval,
+		stopChan:       make(chan struct{}),
+		migrationTries: defaultMigrationTries,
+		migrationDelay: defaultMigrationDelay,
 	}
 }
 
@@ -122,6 +156,48 @@ func (s *SyncService) GetLastSyncStats() SyncStats {
 	return s.lastSyncStats
 }
 
+// SetConflictStrategy sets how records present on both sides are resolved
+// from the next sync on
+func (s *SyncService) SetConflictStrategy(strategy ConflictStrategy) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.conflictStrategy = strategy
+}
+
+// GetConflictStrategy returns how records present on both sides are resolved
+func (s *SyncService) GetConflictStrategy() ConflictStrategy {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.conflictStrategy
+}
+
+// localWins reports whether a record present on both sides should be pushed
+// to remote. The update copies updated_at along, so a record that already
+// matches isn't pushed again on the next sync.
+func (s *SyncService) localWins(localUpdatedAt, remoteUpdatedAt string) bool {
+	switch s.conflictStrategy {
+	case LocalWins:
+		return localUpdatedAt != remoteUpdatedAt
+	case RemoteWins:
+		return false
+	default:
+		return localUpdatedAt > remoteUpdatedAt
+	}
+}
+
+// remoteWins reports whether a record present on both sides should be pulled
+// into local
+func (s *SyncService) remoteWins(localUpdatedAt, remoteUpdatedAt string) bool {
+	switch s.conflictStrategy {
+	case LocalWins:
+		return false
+	case RemoteWins:
+		return localUpdatedAt != remoteUpdatedAt
+	default:
+		return remoteUpdatedAt > localUpdatedAt
+	}
+}
+
 // Sync performs synchronization between databases
 func (s *SyncService) Sync(direction SyncDirection) error {
 	s.mu.Lock()
@@ -134,23 +210,13 @@ func (s *SyncService) Sync(direction SyncDirection) error {
 	logging.Log("Starting sync...")
 
 	// Sync each table
-	tables := []struct {
-		name     string
-		syncFunc func(SyncDirection, *SyncStats) error
-	}{
-		{"clients", s.syncClients},
-		{"client_rates", s.syncClientRates},
-		{"timesheet", s.syncTimesheet},
-		{"training_budget", s.syncTrainingBudget},
-		{"vacation_carryover", s.syncVacationCarryover},
-		{"buffer_hours", s.syncBufferHours},
-	}
-
-	for _, table := range tables {
+	for _, table := range s.syncTables() {
 		if err := table.syncFunc(direction, &stats); err != nil {
 			errMsg := fmt.Sprintf("Error syncing %s: %v", table.name, err)
 			stats.Errors = append(stats.Errors, errMsg)
-			logging.Log("%s", errMsg)
+			logging.LogFields(logging.LevelError, "Error syncing table", map[string]any{
+				"table": table.name, "direction": direction.String(), "error": err,
+			})
 		} else {
 			stats.TablesProcessed++
 		}
@@ -161,9 +227,15 @@ func (s *SyncService) Sync(direction SyncDirection) error {
 
 	s.lastSyncTime = time.Now()
 	s.lastSyncStats = stats
+	recordMetrics(stats)
 
-	logging.Log("Sync completed in %v (pushed: %d, pulled: %d, errors: %d)",
-		stats.Duration, stats.RecordsPushed, stats.RecordsPulled, len(stats.Errors))
+	logging.LogFields(logging.LevelInfo, "Sync completed", map[string]any{
+		"direction":  direction.String(),
+		"durationMs": stats.Duration.Milliseconds(),
+		"pushed":     stats.RecordsPushed,
+		"pulled":     stats.RecordsPulled,
+		"errors":     len(stats.Errors),
+	})
 
 	if len(stats.Errors) > 0 {
 		return fmt.Errorf("sync completed with %d errors", len(stats.Errors))
@@ -171,6 +243,25 @@ func (s *SyncService) Sync(direction SyncDirection) error {
 	return nil
 }
 
+// syncTable is one table's sync step
+type syncTable struct {
+	name     string
+	syncFunc func(SyncDirection, *SyncStats) error
+}
+
+// syncTables lists the tables in the order they must be synced: client
+// rates refer to clients, so clients go first
+func (s *SyncService) syncTables() []syncTable {
+	return []syncTable{
+		{"clients", s.syncClients},
+		{"client_rates", s.syncClientRates},
+		{"timesheet", s.syncTimesheet},
+		{"training_budget", s.syncTrainingBudget},
+		{"vacation_carryover", s.syncVacationCarryover},
+		{"buffer_hours", s.syncBufferHours},
+	}
+}
+
 // syncClients synchronizes the clients table
 func (s *SyncService) syncClients(direction SyncDirection, stats *SyncStats) error {
 	// Get all clients from both databases
@@ -244,7 +335,7 @@ func (s *SyncService) syncClients(direction SyncDirection, stats *SyncStats) err
 					return fmt.Errorf("failed to insert client %s to remote: %w", name, err)
 				}
 				stats.RecordsPushed++
-			} else if local.UpdatedAt > remote.UpdatedAt {
+			} else if s.localWins(local.UpdatedAt, remote.UpdatedAt) {
 				// Update remote with local data
 				if err := s.updateClientInRemote(local, remote.Id); err != nil {
 					return fmt.Errorf("failed to update client %s in remote: %w", name, err)
@@ -267,7 +358,7 @@ func (s *SyncService) syncClients(direction SyncDirection, stats *SyncStats) err
 					return fmt.Errorf("failed to insert client %s to local: %w", name, err)
 				}
 				stats.RecordsPulled++
-			} else if remote.UpdatedAt > local.UpdatedAt {
+			} else if s.remoteWins(local.UpdatedAt, remote.UpdatedAt) {
 				// Update local with remote data
 				if err := s.updateClientInLocal(remote, local.Id); err != nil {
 					return fmt.Errorf("failed to update client %s in local: %w", name, err)
@@ -403,7 +494,7 @@ func (s *SyncService) syncClientRates(direction SyncDirection, stats *SyncStats)
 					return fmt.Errorf("failed to insert rate to remote: %w", err)
 				}
 				stats.RecordsPushed++
-			} else if local.UpdatedAt > remote.UpdatedAt {
+			} else if s.localWins(local.UpdatedAt, remote.UpdatedAt) {
 				if err := s.updateClientRateInRemote(local, remote.Id, remoteClientId); err != nil {
 					return fmt.Errorf("failed to update rate in remote: %w", err)
 				}
@@ -430,7 +521,7 @@ func (s *SyncService) syncClientRates(direction SyncDirection, stats *SyncStats)
 					return fmt.Errorf("failed to insert rate to local: %w", err)
 				}
 				stats.RecordsPulled++
-			} else if remote.UpdatedAt > local.UpdatedAt {
+			} else if s.remoteWins(local.UpdatedAt, remote.UpdatedAt) {
 				if err := s.updateClientRateInLocal(remote, local.Id, localClientId); err != nil {
 					return fmt.Errorf("failed to update rate in local: %w", err)
 				}
@@ -453,8 +544,9 @@ func splitRateKey(key string) (name, date string, ok bool) {
 	return key[:i], key[i+1:], true
 }
 
-// splitTrainingKey splits a "date|trainingName" key back into its parts.
-func splitTrainingKey(key string) (date, name string, ok bool) {
+// splitDateKey splits a "date|name" key, such as a timesheet or training
+// budget key, back into its parts.
+func splitDateKey(key string) (date, name string, ok bool) {
 	i := strings.Index(key, "|")
 	if i < 0 {
 		return "", "", false
@@ -462,6 +554,29 @@ func splitTrainingKey(key string) (date, name string, ok bool) {
 	return key[:i], key[i+1:], true
 }
 
+// expandDateTombstones rewrites timesheet tombstones keyed by date alone,
+// written before a day could hold several client blocks, into one
+// date|client tombstone per block on that date on either side. It returns
+// the date-only keys it replaced.
+func expandDateTombstones(ts map[string]string, rows ...map[string]timesheetRecord) []string {
+	var legacy []string
+	for key, deletedAt := range ts {
+		if strings.Contains(key, "|") {
+			continue
+		}
+		legacy = append(legacy, key)
+		delete(ts, key)
+		for _, m := range rows {
+			for k, e := range m {
+				if e.Date == key && deletedAt > ts[k] {
+					ts[k] = deletedAt
+				}
+			}
+		}
+	}
+	return legacy
+}
+
 // parseBufferKey parses a "YYYY-MM" key back into year and month.
 func parseBufferKey(key string) (year, month int, ok bool) {
 	i := strings.Index(key, "-")
@@ -491,15 +606,15 @@ func (s *SyncService) syncTimesheet(direction SyncDirection, stats *SyncStats) e
 		return fmt.Errorf("failed to get remote timesheet: %w", err)
 	}
 
-	// Use date as the unique key (one entry per date)
+	// Use date + client_name as composite key (one block per client per day)
 	localMap := make(map[string]timesheetRecord)
 	for _, e := range localEntries {
-		localMap[e.Date] = e
+		localMap[db.TombstoneKeyTimesheet(e.Date, e.ClientName)] = e
 	}
 
 	remoteMap := make(map[string]timesheetRecord)
 	for _, e := range remoteEntries {
-		remoteMap[e.Date] = e
+		remoteMap[db.TombstoneKeyTimesheet(e.Date, e.ClientName)] = e
 	}
 
 	// Tombstone pass.
@@ -511,6 +626,8 @@ func (s *SyncService) syncTimesheet(direction SyncDirection, stats *SyncStats) e
 	if err != nil {
 		return fmt.Errorf("failed to get remote timesheet tombstones: %w", err)
 	}
+	legacyLocal := expandDateTombstones(localTs, localMap, remoteMap)
+	legacyRemote := expandDateTombstones(remoteTs, localMap, remoteMap)
 	rec, err := s.reconcileTombstones(
 		db.TombstoneTableTimesheet,
 		localTs, remoteTs,
@@ -523,12 +640,20 @@ func (s *SyncService) syncTimesheet(direction SyncDirection, stats *SyncStats) e
 			return e.UpdatedAt, ok
 		},
 		func(key string) error {
-			_, err := s.localDB.Exec(`DELETE FROM timesheet WHERE date = ?`, key)
+			date, client, ok := splitDateKey(key)
+			if !ok {
+				return nil
+			}
+			_, err := s.localDB.Exec(`DELETE FROM timesheet WHERE date = ? AND client_name = ?`, date, client)
 			delete(localMap, key)
 			return err
 		},
 		func(key string) error {
-			_, err := s.remoteDB.Exec(`DELETE FROM timesheet WHERE date = $1`, key)
+			date, client, ok := splitDateKey(key)
+			if !ok {
+				return nil
+			}
+			_, err := s.remoteDB.Exec(`DELETE FROM timesheet WHERE date = $1 AND client_name = $2`, date, client)
 			delete(remoteMap, key)
 			return err
 		},
@@ -536,22 +661,33 @@ func (s *SyncService) syncTimesheet(direction SyncDirection, stats *SyncStats) e
 	if err != nil {
 		return err
 	}
+	// The date-only tombstones now live on as per-block keys.
+	for _, key := range legacyLocal {
+		if err := s.deleteTombstoneFromLocal(db.TombstoneTableTimesheet, key); err != nil {
+			return fmt.Errorf("failed to drop local timesheet tombstone %s: %w", key, err)
+		}
+	}
+	for _, key := range legacyRemote {
+		if err := s.deleteTombstoneFromRemote(db.TombstoneTableTimesheet, key); err != nil {
+			return fmt.Errorf("failed to drop remote timesheet tombstone %s: %w", key, err)
+		}
+	}
 
 	// Push local -> remote
 	if direction == SyncBidirectional || direction == SyncPushOnly {
-		for date, local := range localMap {
-			if rec.isKilled(date) {
+		for key, local := range localMap {
+			if rec.isKilled(key) {
 				continue
 			}
-			remote, exists := remoteMap[date]
+			remote, exists := remoteMap[key]
 			if !exists {
 				if err := s.insertTimesheetToRemote(local); err != nil {
-					return fmt.Errorf("failed to insert timesheet %s to remote: %w", date, err)
+					return fmt.Errorf("failed to insert timesheet %s to remote: %w", key, err)
 				}
 				stats.RecordsPushed++
-			} else if local.UpdatedAt > remote.UpdatedAt {
+			} else if s.localWins(local.UpdatedAt, remote.UpdatedAt) {
 				if err := s.updateTimesheetInRemote(local, remote.Id); err != nil {
-					return fmt.Errorf("failed to update timesheet %s in remote: %w", date, err)
+					return fmt.Errorf("failed to update timesheet %s in remote: %w", key, err)
 				}
 				stats.RecordsPushed++
 			}
@@ -560,19 +696,19 @@ func (s *SyncService) syncTimesheet(direction SyncDirection, stats *SyncStats) e
 
 	// Pull remote -> local
 	if direction == SyncBidirectional || direction == SyncPullOnly {
-		for date, remote := range remoteMap {
-			if rec.isKilled(date) {
+		for key, remote := range remoteMap {
+			if rec.isKilled(key) {
 				continue
 			}
-			local, exists := localMap[date]
+			local, exists := localMap[key]
 			if !exists {
 				if err := s.insertTimesheetToLocal(remote); err != nil {
-					return fmt.Errorf("failed to insert timesheet %s to local: %w", date, err)
+					return fmt.Errorf("failed to insert timesheet %s to local: %w", key, err)
 				}
 				stats.RecordsPulled++
-			} else if remote.UpdatedAt > local.UpdatedAt {
+			} else if s.remoteWins(local.UpdatedAt, remote.UpdatedAt) {
 				if err := s.updateTimesheetInLocal(remote, local.Id); err != nil {
-					return fmt.Errorf("failed to update timesheet %s in local: %w", date, err)
+					return fmt.Errorf("failed to update timesheet %s in local: %w", key, err)
 				}
 				stats.RecordsPulled++
 			}
@@ -628,7 +764,7 @@ func (s *SyncService) syncTrainingBudget(direction SyncDirection, stats *SyncSta
 			return e.UpdatedAt, ok
 		},
 		func(key string) error {
-			date, name, ok := splitTrainingKey(key)
+			date, name, ok := splitDateKey(key)
 			if !ok {
 				return nil
 			}
@@ -637,7 +773,7 @@ func (s *SyncService) syncTrainingBudget(direction SyncDirection, stats *SyncSta
 			return err
 		},
 		func(key string) error {
-			date, name, ok := splitTrainingKey(key)
+			date, name, ok := splitDateKey(key)
 			if !ok {
 				return nil
 			}
@@ -662,7 +798,7 @@ func (s *SyncService) syncTrainingBudget(direction SyncDirection, stats *SyncSta
 					return fmt.Errorf("failed to insert training budget to remote: %w", err)
 				}
 				stats.RecordsPushed++
-			} else if local.UpdatedAt > remote.UpdatedAt {
+			} else if s.localWins(local.UpdatedAt, remote.UpdatedAt) {
 				if err := s.updateTrainingBudgetInRemote(local, remote.Id); err != nil {
 					return fmt.Errorf("failed to update training budget in remote: %w", err)
 				}
@@ -683,7 +819,7 @@ func (s *SyncService) syncTrainingBudget(direction SyncDirection, stats *SyncSta
 					return fmt.Errorf("failed to insert training budget to local: %w", err)
 				}
 				stats.RecordsPulled++
-			} else if remote.UpdatedAt > local.UpdatedAt {
+			} else if s.remoteWins(local.UpdatedAt, remote.UpdatedAt) {
 				if err := s.updateTrainingBudgetInLocal(remote, local.Id); err != nil {
 					return fmt.Errorf("failed to update training budget in local: %w", err)
 				}
@@ -781,7 +917,7 @@ func (s *SyncService) syncBufferHours(direction SyncDirection, stats *SyncStats)
 					return fmt.Errorf("failed to insert buffer %d-%02d to remote: %w", k.year, k.month, err)
 				}
 				stats.RecordsPushed++
-			} else if local.UpdatedAt > remote.UpdatedAt {
+			} else if s.localWins(local.UpdatedAt, remote.UpdatedAt) {
 				if err := s.updateBufferHoursInRemote(local, remote.Id); err != nil {
 					return fmt.Errorf("failed to update buffer %d-%02d in remote: %w", k.year, k.month, err)
 				}
@@ -801,7 +937,7 @@ func (s *SyncService) syncBufferHours(direction SyncDirection, stats *SyncStats)
 					return fmt.Errorf("failed to insert buffer %d-%02d to local: %w", k.year, k.month, err)
 				}
 				stats.RecordsPulled++
-			} else if remote.UpdatedAt > local.UpdatedAt {
+			} else if s.remoteWins(local.UpdatedAt, remote.UpdatedAt) {
 				if err := s.updateBufferHoursInLocal(remote, local.Id); err != nil {
 					return fmt.Errorf("failed to update buffer %d-%02d in local: %w", k.year, k.month, err)
 				}
@@ -899,7 +1035,7 @@ func (s *SyncService) syncVacationCarryover(direction SyncDirection, stats *Sync
 					return fmt.Errorf("failed to insert vacation carryover %d to remote: %w", year, err)
 				}
 				stats.RecordsPushed++
-			} else if local.UpdatedAt > remote.UpdatedAt {
+			} else if s.localWins(local.UpdatedAt, remote.UpdatedAt) {
 				if err := s.updateVacationCarryoverInRemote(local, remote.Id); err != nil {
 					return fmt.Errorf("failed to update vacation carryover %d in remote: %w", year, err)
 				}
@@ -920,7 +1056,7 @@ func (s *SyncService) syncVacationCarryover(direction SyncDirection, stats *Sync
 					return fmt.Errorf("failed to insert vacation carryover %d to local: %w", year, err)
 				}
 				stats.RecordsPulled++
-			} else if remote.UpdatedAt > local.UpdatedAt {
+			} else if s.remoteWins(local.UpdatedAt, remote.UpdatedAt) {
 				if err := s.updateVacationCarryoverInLocal(remote, local.Id); err != nil {
 					return fmt.Errorf("failed to update vacation carryover %d in local: %w", year, err)
 				}
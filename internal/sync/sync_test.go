@@ -2,6 +2,8 @@ package sync
 
 import (
 	"database/sql"
+	"database/sql/driver"
+	"errors"
 	"testing"
 	"time"
 
@@ -110,7 +112,7 @@ func TestSync_DeletePropagatesFromRemoteToLocal(t *testing.T) {
 	seedTimesheetRow(t, remoteDB, "postgres", date, t0)
 
 	// Simulate "remote deleted, tombstone written on remote at t1".
-	writeTombstone(t, remoteDB, "postgres", db.TombstoneTableTimesheet, date, t1)
+	writeTombstone(t, remoteDB, "postgres", db.TombstoneTableTimesheet, db.TombstoneKeyTimesheet(date, "Acme"), t1)
 	if _, err := remoteDB.Exec(`DELETE FROM timesheet WHERE date = $1`, date); err != nil {
 		t.Fatalf("delete remote row: %v", err)
 	}
@@ -125,10 +127,10 @@ func TestSync_DeletePropagatesFromRemoteToLocal(t *testing.T) {
 	if got := countTimesheetRows(t, remoteDB, date); got != 0 {
 		t.Errorf("remote row should stay deleted, found %d", got)
 	}
-	if got := countTombstones(t, localDB, db.TombstoneTableTimesheet, date); got != 1 {
+	if got := countTombstones(t, localDB, db.TombstoneTableTimesheet, db.TombstoneKeyTimesheet(date, "Acme")); got != 1 {
 		t.Errorf("expected tombstone propagated to local, found %d", got)
 	}
-	if got := countTombstones(t, remoteDB, db.TombstoneTableTimesheet, date); got != 1 {
+	if got := countTombstones(t, remoteDB, db.TombstoneTableTimesheet, db.TombstoneKeyTimesheet(date, "Acme")); got != 1 {
 		t.Errorf("expected tombstone still on remote, found %d", got)
 	}
 }
@@ -143,7 +145,7 @@ func TestSync_DeletePropagatesFromLocalToRemote(t *testing.T) {
 	seedTimesheetRow(t, localDB, "sqlite", date, t0)
 	seedTimesheetRow(t, remoteDB, "postgres", date, t0)
 
-	writeTombstone(t, localDB, "sqlite", db.TombstoneTableTimesheet, date, t1)
+	writeTombstone(t, localDB, "sqlite", db.TombstoneTableTimesheet, db.TombstoneKeyTimesheet(date, "Acme"), t1)
 	if _, err := localDB.Exec(`DELETE FROM timesheet WHERE date = ?`, date); err != nil {
 		t.Fatalf("delete local row: %v", err)
 	}
@@ -158,11 +160,49 @@ func TestSync_DeletePropagatesFromLocalToRemote(t *testing.T) {
 	if got := countTimesheetRows(t, remoteDB, date); got != 0 {
 		t.Errorf("remote row should be deleted after sync, found %d", got)
 	}
-	if got := countTombstones(t, remoteDB, db.TombstoneTableTimesheet, date); got != 1 {
+	if got := countTombstones(t, remoteDB, db.TombstoneTableTimesheet, db.TombstoneKeyTimesheet(date, "Acme")); got != 1 {
 		t.Errorf("expected tombstone propagated to remote, found %d", got)
 	}
 }
 
+// TestSync_AppDeleteReachesRemote: an entry deleted through the db
+// package, as the TUI and API do, is removed from remote by the next sync
+// and isn't pulled back by the one after.
+func TestSync_AppDeleteReachesRemote(t *testing.T) {
+	if err := db.InitializeDatabase(":memory:"); err != nil {
+		t.Fatalf("init local: %v", err)
+	}
+	t.Cleanup(db.Close)
+	_, _, remoteDB := newSyncPair(t)
+	svc := NewSyncService(db.GetSQLiteDB(), remoteDB, time.Minute)
+
+	const date = "2026-06-15"
+	if err := db.AddTimesheetEntry(db.TimesheetEntry{Date: date, Client_name: "Acme", Client_hours: 8}); err != nil {
+		t.Fatalf("add entry: %v", err)
+	}
+	if err := svc.Sync(SyncBidirectional); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if got := countTimesheetRows(t, remoteDB, date); got != 1 {
+		t.Fatalf("expected the entry on remote, found %d rows", got)
+	}
+
+	if err := db.DeleteTimesheetEntryByDate(date); err != nil {
+		t.Fatalf("delete entry: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if err := svc.Sync(SyncBidirectional); err != nil {
+			t.Fatalf("sync %d: %v", i+1, err)
+		}
+	}
+	if got := countTimesheetRows(t, remoteDB, date); got != 0 {
+		t.Errorf("remote row should be deleted after sync, found %d", got)
+	}
+	if got := countTimesheetRows(t, db.GetSQLiteDB(), date); got != 0 {
+		t.Errorf("local row should stay deleted, found %d", got)
+	}
+}
+
 // TestSync_EditBeatsDelete: when one side has a tombstone but the other
 // side's row has been updated AFTER the tombstone, the edit wins. The
 // tombstone is dropped and the row is restored on the deleted side.
@@ -175,7 +215,7 @@ func TestSync_EditBeatsDelete(t *testing.T) {
 	const t2 = "2026-06-14 10:00:10" // remote edit time, AFTER local delete
 
 	// Local already deleted at t1 and only has the tombstone now.
-	writeTombstone(t, localDB, "sqlite", db.TombstoneTableTimesheet, date, t1)
+	writeTombstone(t, localDB, "sqlite", db.TombstoneTableTimesheet, db.TombstoneKeyTimesheet(date, "Acme"), t1)
 	// Remote edited the row at t2 — a write more recent than the delete.
 	seedTimesheetRow(t, remoteDB, "postgres", date, t2)
 
@@ -189,10 +229,10 @@ func TestSync_EditBeatsDelete(t *testing.T) {
 	if got := countTimesheetRows(t, remoteDB, date); got != 1 {
 		t.Errorf("expected remote row preserved, found %d", got)
 	}
-	if got := countTombstones(t, localDB, db.TombstoneTableTimesheet, date); got != 0 {
+	if got := countTombstones(t, localDB, db.TombstoneTableTimesheet, db.TombstoneKeyTimesheet(date, "Acme")); got != 0 {
 		t.Errorf("losing local tombstone should be dropped, found %d", got)
 	}
-	if got := countTombstones(t, remoteDB, db.TombstoneTableTimesheet, date); got != 0 {
+	if got := countTombstones(t, remoteDB, db.TombstoneTableTimesheet, db.TombstoneKeyTimesheet(date, "Acme")); got != 0 {
 		t.Errorf("remote should have no tombstone, found %d", got)
 	}
 }
@@ -208,7 +248,7 @@ func TestSync_RepeatedSyncConverges(t *testing.T) {
 
 	seedTimesheetRow(t, localDB, "sqlite", date, t0)
 	seedTimesheetRow(t, remoteDB, "postgres", date, t0)
-	writeTombstone(t, remoteDB, "postgres", db.TombstoneTableTimesheet, date, t1)
+	writeTombstone(t, remoteDB, "postgres", db.TombstoneTableTimesheet, db.TombstoneKeyTimesheet(date, "Acme"), t1)
 	if _, err := remoteDB.Exec(`DELETE FROM timesheet WHERE date = $1`, date); err != nil {
 		t.Fatalf("delete remote row: %v", err)
 	}
@@ -244,7 +284,7 @@ func TestSync_DeleteOnlyOnOneSide_RowOnlyOnOther(t *testing.T) {
 
 	// Local has the row at t0; remote has only a tombstone at t1 (later).
 	seedTimesheetRow(t, localDB, "sqlite", date, t0)
-	writeTombstone(t, remoteDB, "postgres", db.TombstoneTableTimesheet, date, t1)
+	writeTombstone(t, remoteDB, "postgres", db.TombstoneTableTimesheet, db.TombstoneKeyTimesheet(date, "Acme"), t1)
 
 	if err := svc.Sync(SyncBidirectional); err != nil {
 		t.Fatalf("sync: %v", err)
@@ -253,11 +293,207 @@ func TestSync_DeleteOnlyOnOneSide_RowOnlyOnOther(t *testing.T) {
 	if got := countTimesheetRows(t, localDB, date); got != 0 {
 		t.Errorf("local row should be deleted, found %d", got)
 	}
-	if got := countTombstones(t, localDB, db.TombstoneTableTimesheet, date); got != 1 {
+	if got := countTombstones(t, localDB, db.TombstoneTableTimesheet, db.TombstoneKeyTimesheet(date, "Acme")); got != 1 {
 		t.Errorf("tombstone should be on local, found %d", got)
 	}
 }
 
+// TestSync_ClientBlocksOnOneDay checks that blocks of different clients on
+// the same date sync separately, and a deleted block leaves the other alone.
+func TestSync_ClientBlocksOnOneDay(t *testing.T) {
+	svc, localDB, remoteDB := newSyncPair(t)
+
+	const date = "2026-06-15"
+	const t0 = "2026-06-15 10:00:00"
+	const t1 = "2026-06-15 10:00:05"
+
+	seedTimesheetRow(t, localDB, "sqlite", date, t0)
+	if _, err := localDB.Exec(`INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, created_at, updated_at) VALUES (?, 'Globex', 3, 0, 0, 0, 0, 0, ?, ?)`, date, t0, t0); err != nil {
+		t.Fatalf("seed second block: %v", err)
+	}
+	if err := svc.Sync(SyncBidirectional); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if got := countTimesheetRows(t, remoteDB, date); got != 2 {
+		t.Fatalf("remote has %d blocks on %s, want 2", got, date)
+	}
+
+	writeTombstone(t, localDB, "sqlite", db.TombstoneTableTimesheet, db.TombstoneKeyTimesheet(date, "Globex"), t1)
+	if _, err := localDB.Exec(`DELETE FROM timesheet WHERE date = ? AND client_name = 'Globex'`, date); err != nil {
+		t.Fatalf("local delete: %v", err)
+	}
+	if err := svc.Sync(SyncBidirectional); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	var client string
+	if err := remoteDB.QueryRow(`SELECT client_name FROM timesheet WHERE date = ?`, date).Scan(&client); err != nil {
+		t.Fatalf("remote block: %v", err)
+	}
+	if client != "Acme" {
+		t.Errorf("remote kept the %s block, want Acme", client)
+	}
+}
+
+// TestSync_DateOnlyTombstone checks that a tombstone keyed by date alone,
+// written before a day could hold several client blocks, still buries every
+// block on that date instead of letting the sync pull them back.
+func TestSync_DateOnlyTombstone(t *testing.T) {
+	svc, localDB, remoteDB := newSyncPair(t)
+
+	const date = "2026-06-17"
+	const t0 = "2026-06-17 10:00:00"
+	const t1 = "2026-06-17 10:00:05"
+
+	seedTimesheetRow(t, localDB, "sqlite", date, t0)
+	if _, err := localDB.Exec(`INSERT INTO timesheet (date, client_name, client_hours, vacation_hours, idle_hours, training_hours, sick_hours, holiday_hours, created_at, updated_at) VALUES (?, 'Globex', 3, 0, 0, 0, 0, 0, ?, ?)`, date, t0, t0); err != nil {
+		t.Fatalf("seed second block: %v", err)
+	}
+	writeTombstone(t, remoteDB, "postgres", db.TombstoneTableTimesheet, date, t1)
+
+	for i := 0; i < 2; i++ {
+		if err := svc.Sync(SyncBidirectional); err != nil {
+			t.Fatalf("sync %d: %v", i+1, err)
+		}
+	}
+
+	if got := countTimesheetRows(t, localDB, date); got != 0 {
+		t.Errorf("local has %d blocks on %s, want 0", got, date)
+	}
+	if got := countTimesheetRows(t, remoteDB, date); got != 0 {
+		t.Errorf("remote has %d blocks on %s, want 0", got, date)
+	}
+	for _, conn := range []*sql.DB{localDB, remoteDB} {
+		if got := countTombstones(t, conn, db.TombstoneTableTimesheet, date); got != 0 {
+			t.Errorf("date-only tombstone left behind: %d", got)
+		}
+		for _, client := range []string{"Acme", "Globex"} {
+			if got := countTombstones(t, conn, db.TombstoneTableTimesheet, db.TombstoneKeyTimesheet(date, client)); got != 1 {
+				t.Errorf("expected %s tombstone, found %d", client, got)
+			}
+		}
+	}
+}
+
+func TestSync_TimesheetNote(t *testing.T) {
+	svc, localDB, remoteDB := newSyncPair(t)
+
+	const date = "2026-06-16"
+	if _, err := localDB.Exec(`INSERT INTO timesheet (date, client_name, idle_hours, note, created_at, updated_at) VALUES (?, '-', 8, 'Waiting for access', ?, ?)`, date, "2026-06-16 09:00:00", "2026-06-16 09:00:00"); err != nil {
+		t.Fatalf("seed local timesheet: %v", err)
+	}
+	if err := svc.Sync(SyncBidirectional); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	var note string
+	if err := remoteDB.QueryRow(`SELECT note FROM timesheet WHERE date = ?`, date).Scan(&note); err != nil {
+		t.Fatalf("remote note: %v", err)
+	}
+	if note != "Waiting for access" {
+		t.Errorf("remote note = %q, want it pushed", note)
+	}
+
+	if _, err := remoteDB.Exec(`UPDATE timesheet SET note = 'Access granted', updated_at = ? WHERE date = ?`, "2026-06-16 12:00:00", date); err != nil {
+		t.Fatalf("remote update: %v", err)
+	}
+	if err := svc.Sync(SyncBidirectional); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if err := localDB.QueryRow(`SELECT note FROM timesheet WHERE date = ?`, date).Scan(&note); err != nil {
+		t.Fatalf("local note: %v", err)
+	}
+	if note != "Access granted" {
+		t.Errorf("local note = %q, want it pulled", note)
+	}
+}
+
+func TestSync_TrainingReceiptPath(t *testing.T) {
+	svc, localDB, remoteDB := newSyncPair(t)
+
+	const date = "2026-06-18"
+	if _, err := localDB.Exec(`INSERT INTO training_budget (date, training_name, hours, cost_without_vat, receipt_path, created_at, updated_at) VALUES (?, 'GopherCon', 8, 450, '/receipts/gophercon.pdf', ?, ?)`, date, "2026-06-18 09:00:00", "2026-06-18 09:00:00"); err != nil {
+		t.Fatalf("seed local training: %v", err)
+	}
+	if err := svc.Sync(SyncBidirectional); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	var receipt string
+	if err := remoteDB.QueryRow(`SELECT receipt_path FROM training_budget WHERE date = ?`, date).Scan(&receipt); err != nil {
+		t.Fatalf("remote receipt: %v", err)
+	}
+	if receipt != "/receipts/gophercon.pdf" {
+		t.Errorf("remote receipt_path = %q, want it pushed", receipt)
+	}
+
+	if _, err := remoteDB.Exec(`UPDATE training_budget SET receipt_path = '/receipts/gophercon-final.pdf', updated_at = ? WHERE date = ?`, "2026-06-18 12:00:00", date); err != nil {
+		t.Fatalf("remote update: %v", err)
+	}
+	if err := svc.Sync(SyncBidirectional); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	if err := localDB.QueryRow(`SELECT receipt_path FROM training_budget WHERE date = ?`, date).Scan(&receipt); err != nil {
+		t.Fatalf("local receipt: %v", err)
+	}
+	if receipt != "/receipts/gophercon-final.pdf" {
+		t.Errorf("local receipt_path = %q, want it pulled", receipt)
+	}
+}
+
+// TestSync_ConflictStrategy: the row is newer on local but differs on
+// both sides. NewestWins keeps the local hours, RemoteWins overwrites them
+// with the remote's, and the next sync has nothing left to do.
+func TestSync_ConflictStrategy(t *testing.T) {
+	const date = "2026-06-14"
+	const t0 = "2026-06-14 10:00:00"
+	const t1 = "2026-06-14 10:00:05"
+
+	tests := []struct {
+		strategy  ConflictStrategy
+		wantHours float64
+		pushed    int
+		pulled    int
+	}{
+		{NewestWins, 6, 1, 0},
+		{LocalWins, 6, 1, 0},
+		{RemoteWins, 8, 0, 1},
+	}
+	for _, tt := range tests {
+		svc, localDB, remoteDB := newSyncPair(t)
+		svc.SetConflictStrategy(tt.strategy)
+
+		seedTimesheetRow(t, localDB, "sqlite", date, t1)
+		seedTimesheetRow(t, remoteDB, "postgres", date, t0)
+		if _, err := localDB.Exec(`UPDATE timesheet SET client_hours = 6 WHERE date = ?`, date); err != nil {
+			t.Fatalf("edit local row: %v", err)
+		}
+
+		if err := svc.Sync(SyncBidirectional); err != nil {
+			t.Fatalf("strategy %d: sync: %v", tt.strategy, err)
+		}
+		stats := svc.GetLastSyncStats()
+		if stats.RecordsPushed != tt.pushed || stats.RecordsPulled != tt.pulled {
+			t.Errorf("strategy %d: got pushed=%d pulled=%d, want pushed=%d pulled=%d",
+				tt.strategy, stats.RecordsPushed, stats.RecordsPulled, tt.pushed, tt.pulled)
+		}
+		for name, conn := range map[string]*sql.DB{"local": localDB, "remote": remoteDB} {
+			var hours float64
+			if err := conn.QueryRow(`SELECT client_hours FROM timesheet WHERE date = ?`, date).Scan(&hours); err != nil {
+				t.Fatalf("read %s row: %v", name, err)
+			}
+			if hours != tt.wantHours {
+				t.Errorf("strategy %d: %s has %v client hours, want %v", tt.strategy, name, hours, tt.wantHours)
+			}
+		}
+
+		if err := svc.Sync(SyncBidirectional); err != nil {
+			t.Fatalf("strategy %d: second sync: %v", tt.strategy, err)
+		}
+		if stats := svc.GetLastSyncStats(); stats.RecordsPushed != 0 || stats.RecordsPulled != 0 {
+			t.Errorf("strategy %d: second sync should be a no-op; got pushed=%d pulled=%d",
+				tt.strategy, stats.RecordsPushed, stats.RecordsPulled)
+		}
+	}
+}
+
 // TestSync_BufferDeletePropagates exercises a different table (buffer_hours)
 // to make sure the wiring is consistent across the six syncs, not just
 // timesheet-specific.
@@ -320,3 +556,154 @@ func TestSync_BufferDeletePropagates(t *testing.T) {
 	}
 }
 
+func TestSyncRecordsMetrics(t *testing.T) {
+	svc, localDB, _ := newSyncPair(t)
+	seedTimesheetRow(t, localDB, "sqlite", "2024-03-01", "2024-03-01 10:00:00")
+
+	before := GetMetrics()
+	if err := svc.Sync(SyncBidirectional); err != nil {
+		t.Fatalf("sync: %v", err)
+	}
+	after := GetMetrics()
+
+	if after.SyncCount != before.SyncCount+1 {
+		t.Errorf("SyncCount = %d, want %d", after.SyncCount, before.SyncCount+1)
+	}
+	if after.RecordsPushed <= before.RecordsPushed {
+		t.Errorf("RecordsPushed did not increase: before %d, after %d", before.RecordsPushed, after.RecordsPushed)
+	}
+	if after.LastSyncTime.IsZero() {
+		t.Error("LastSyncTime not set")
+	}
+}
+
+func TestInitialMigrationPushesLocalData(t *testing.T) {
+	svc, localDB, remoteDB := newSyncPair(t)
+	seedTimesheetRow(t, localDB, "sqlite", "2024-03-04", "2024-03-04 10:00:00")
+	seedTimesheetRow(t, localDB, "sqlite", "2024-03-05", "2024-03-05 10:00:00")
+
+	if err := svc.InitialMigration(); err != nil {
+		t.Fatalf("InitialMigration failed: %v", err)
+	}
+	for _, date := range []string{"2024-03-04", "2024-03-05"} {
+		if n := countTimesheetRows(t, remoteDB, date); n != 1 {
+			t.Errorf("expected %s on remote, found %d rows", date, n)
+		}
+	}
+	if !svc.GetMigrationProgress().Complete() {
+		t.Errorf("expected migration to be complete, got %+v", svc.GetMigrationProgress())
+	}
+}
+
+func TestInitialMigrationRetriesAndResumes(t *testing.T) {
+	svc, _, _ := newSyncPair(t)
+	svc.migrationDelay = 0
+
+	calls := map[string]int{}
+	failures := map[string]error{
+		"first":  driver.ErrBadConn,                  // transient: retried
+		"second": errors.New("constraint violation"), // permanent: stops the migration
+	}
+	table := func(name string) syncTable {
+		return syncTable{name, func(_ SyncDirection, stats *SyncStats) error {
+			calls[name]++
+			stats.RecordsPushed++
+			if err := failures[name]; err != nil {
+				delete(failures, name)
+				return err
+			}
+			return nil
+		}}
+	}
+	tables := []syncTable{table("first"), table("second"), table("third")}
+
+	err := svc.runMigration(tables)
+	if err == nil {
+		t.Fatal("expected the permanent failure to stop the migration")
+	}
+	if calls["first"] != 2 || calls["second"] != 1 || calls["third"] != 0 {
+		t.Errorf("unexpected calls after first run: %v", calls)
+	}
+	progress := svc.GetMigrationProgress()
+	if !progress.Tables[0].Done || progress.Tables[0].RecordsPushed != 2 || progress.Tables[1].Done {
+		t.Errorf("unexpected progress after first run: %+v", progress)
+	}
+
+	// Running again resumes at the table that failed
+	if err := svc.runMigration(tables); err != nil {
+		t.Fatalf("resumed migration failed: %v", err)
+	}
+	if calls["first"] != 2 || calls["second"] != 2 || calls["third"] != 1 {
+		t.Errorf("unexpected calls after resuming: %v", calls)
+	}
+	if !svc.GetMigrationProgress().Complete() {
+		t.Errorf("expected migration to be complete, got %+v", svc.GetMigrationProgress())
+	}
+}
+
+func TestInitialMigrationResumesAfterRestart(t *testing.T) {
+	svc, localDB, remoteDB := newSyncPair(t)
+	svc.migrationDelay = 0
+
+	calls := map[string]int{}
+	failSecond := true
+	tables := []syncTable{
+		{"first", func(_ SyncDirection, _ *SyncStats) error { calls["first"]++; return nil }},
+		{"second", func(_ SyncDirection, _ *SyncStats) error {
+			calls["second"]++
+			if failSecond {
+				return errors.New("constraint violation")
+			}
+			return nil
+		}},
+	}
+	if err := svc.runMigration(tables); err == nil {
+		t.Fatal("expected the failure to stop the migration")
+	}
+
+	// A new service on the same database stands in for a restarted process
+	failSecond = false
+	restarted := NewSyncService(localDB, remoteDB, time.Minute)
+	if err := restarted.runMigration(tables); err != nil {
+		t.Fatalf("resumed migration failed: %v", err)
+	}
+	if calls["first"] != 1 || calls["second"] != 2 {
+		t.Errorf("expected the restart to resume at the failed table, calls: %v", calls)
+	}
+	progress := restarted.GetMigrationProgress()
+	if !progress.Complete() || progress.Tables[1].Attempts != 2 {
+		t.Errorf("unexpected progress after resuming: %+v", progress)
+	}
+}
+
+func TestInitialMigrationReleasesLockWhileRetrying(t *testing.T) {
+	svc, _, _ := newSyncPair(t)
+	svc.migrationDelay = 200 * time.Millisecond
+
+	failed := make(chan struct{})
+	attempts := 0
+	tables := []syncTable{{"flaky", func(_ SyncDirection, _ *SyncStats) error {
+		attempts++
+		if attempts == 1 {
+			close(failed)
+			return driver.ErrBadConn
+		}
+		return nil
+	}}}
+
+	done := make(chan error)
+	go func() { done <- svc.runMigration(tables) }()
+
+	<-failed
+	// Without the lock released during the backoff this would only return
+	// once the retry had succeeded
+	if progress := svc.GetMigrationProgress(); len(progress.Tables) != 1 || progress.Tables[0].Done {
+		t.Errorf("expected to read progress while the migration waits to retry, got %+v", progress)
+	}
+	if err := svc.InitialMigration(); err == nil {
+		t.Error("expected a second migration to be refused while one is running")
+	}
+	if err := <-done; err != nil {
+		t.Fatalf("migration failed: %v", err)
+	}
+}
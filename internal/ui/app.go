@@ -2,10 +2,12 @@ package ui
 
 import (
 	"fmt"
+	"log"
 	"strings"
 	"time"
 	"timesheet/internal/config"
 	"timesheet/internal/datalayer"
+	"timesheet/internal/db"
 	"timesheet/internal/sync"
 
 	"github.com/charmbracelet/bubbles/help"
@@ -79,6 +81,13 @@ type AppModel struct {
 	syncEnabled  bool
 	lastSyncTime time.Time
 	syncStatus   string // "Synced", "Syncing...", "Sync error", etc.
+	// Session undo stack for destructive actions (most recent last)
+	undoStack []UndoAction
+	// Terminal size, 0 until the first tea.WindowSizeMsg
+	width  int
+	height int
+	// Called after the TUI changes data, nil when nobody listens
+	notifyChange func()
 }
 
 func NewAppModel(addMode bool) AppModel {
@@ -105,14 +114,73 @@ func NewAppModel(addMode bool) AppModel {
 		model.ActiveMode = FormMode
 		model.FormModel = InitialFormModel()
 	} else {
-		// Restore last active tab from persisted state
+		// Open the configured default view, or else restore the last
+		// active tab from persisted state
 		state := LoadAppState()
 		model.ActiveMode = StringToAppMode(state.ActiveTab)
+		if view := config.GetDefaultView(); view != "" {
+			if mode, ok := ParseAppMode(view); ok {
+				model.ActiveMode = mode
+			} else {
+				log.Printf("Ignoring defaultView %q: unknown view", view)
+			}
+		}
 	}
 
 	return model
 }
 
+// SetChangeNotifier registers a function that's called whenever the TUI
+// changes data, so listeners outside the program can follow along
+func (m *AppModel) SetChangeNotifier(notify func()) {
+	m.notifyChange = notify
+}
+
+// reloadViews rebuilds the data views after the database changed underneath
+// them. The timesheet rebuilds from the user's current month/cursor so a
+// refresh never yanks the selection back to today (or back to the current
+// month if they were browsing history).
+func (m *AppModel) reloadViews() {
+	tsYear, tsMonth := m.TimesheetModel.currentYear, m.TimesheetModel.currentMonth
+	tsSelected := ""
+	if rows := m.TimesheetModel.table.Rows(); len(rows) > 0 {
+		if c := m.TimesheetModel.table.Cursor(); c >= 0 && c < len(rows) {
+			tsSelected = rows[c][0]
+		}
+	}
+	m.OverviewModel = InitialOverviewModel()
+	m.TimesheetModel = InitialTimesheetModelForMonth(tsYear, tsMonth, tsSelected)
+	m.TrainingModel = InitialTrainingModel()
+	m.TrainingBudgetModel = InitialTrainingBudgetModel()
+	m.VacationModel = InitialVacationModel()
+	m.BufferModel = InitialBufferModel()
+	m.ClientsModel = InitialClientsModel()
+	m.EarningsModel = InitialEarningsModel()
+}
+
+// undoLast pops the most recent destructive action and restores the state
+// it recorded.
+func (m AppModel) undoLast() (tea.Model, tea.Cmd) {
+	if len(m.undoStack) == 0 {
+		return m, SetStatus("Nothing to undo")
+	}
+
+	last := m.undoStack[len(m.undoStack)-1]
+	m.undoStack = m.undoStack[:len(m.undoStack)-1]
+
+	if err := last.Restore(); err != nil {
+		// Keep the action so the undo can be retried
+		m.undoStack = append(m.undoStack, last)
+		return m, SetStatus(fmt.Sprintf("Undo failed: %v", err))
+	}
+
+	m.reloadViews()
+	if m.ActiveMode == ClientRatesModalMode {
+		m.ClientRatesModalModel.loadRates()
+	}
+	return m, tea.Batch(SetStatus("Undid "+last.Description), TriggerSync())
+}
+
 func (m AppModel) Init() tea.Cmd {
 	// Always check for updates on startup
 	updateCmd := CheckForUpdatesCmd()
@@ -180,7 +248,34 @@ func ReturnToTrainingBudget() tea.Cmd {
 	}
 }
 
+// Update handles a message and then lays the views out for the terminal
+// size. Views are rebuilt from scratch on tab switches and refreshes, so the
+// size is re-applied after every message rather than only on resize.
 func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.width, m.height = size.Width, size.Height
+		m.Help.Width = size.Width
+		m.resizeViews()
+		return m, nil
+	}
+
+	model, cmd := m.update(msg)
+	if app, ok := model.(AppModel); ok && app.height > 0 {
+		app.resizeViews()
+		return app, cmd
+	}
+	return model, cmd
+}
+
+// resizeViews gives each view the space below the tab row and status bar.
+// Views skip the work when their size didn't change.
+func (m *AppModel) resizeViews() {
+	height := m.height - appHeaderHeight
+	m.TimesheetModel.setSize(m.width, height)
+	m.ConfigModel.setSize(m.width, height)
+}
+
+func (m AppModel) update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle global keys first
 	if keyMsg, ok := msg.(tea.KeyMsg); ok {
 		// Global quit handler
@@ -188,9 +283,15 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 		}
 
+		// The rates modal deletes rows too, so let it undo them in place
+		if keyMsg.String() == "ctrl+z" && m.ActiveMode == ClientRatesModalMode {
+			return m.undoLast()
+		}
+
 		// Only handle special keys when not in form modes or client form/modal or config editing
 		configEditing := m.ActiveMode == ConfigMode && m.ConfigModel.IsEditing()
-		if m.ActiveMode != FormMode && m.ActiveMode != TrainingBudgetFormMode && m.ActiveMode != ClientFormMode && m.ActiveMode != ClientRatesModalMode && m.ActiveMode != BufferFormMode && !configEditing {
+		timesheetEditing := m.ActiveMode == TimesheetMode && m.TimesheetModel.IsEditing()
+		if m.ActiveMode != FormMode && m.ActiveMode != TrainingBudgetFormMode && m.ActiveMode != ClientFormMode && m.ActiveMode != ClientRatesModalMode && m.ActiveMode != BufferFormMode && !configEditing && !timesheetEditing {
 			// Handle tab switching
 			switch keyMsg.String() {
 			case "<":
@@ -218,7 +319,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.ActiveMode = EarningsMode
 				}
 				// Save active tab state
-				SaveAppState(AppState{ActiveTab: AppModeToString(m.ActiveMode)})
+				saveActiveTab(m.ActiveMode)
 				// Refresh models when switching to them
 				if m.ActiveMode == TimesheetMode && prevMode != TimesheetMode {
 					m.TimesheetModel = InitialTimesheetModel()
@@ -259,7 +360,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.ActiveMode = TimesheetMode
 				}
 				// Save active tab state
-				SaveAppState(AppState{ActiveTab: AppModeToString(m.ActiveMode)})
+				saveActiveTab(m.ActiveMode)
 				// Refresh models when switching to them
 				if m.ActiveMode == TimesheetMode && prevMode != TimesheetMode {
 					m.TimesheetModel = InitialTimesheetModel()
@@ -278,13 +379,13 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			case "$":
 				// Switch to training budget view
 				m.ActiveMode = TrainingBudgetMode
-				SaveAppState(AppState{ActiveTab: AppModeToString(m.ActiveMode)})
+				saveActiveTab(m.ActiveMode)
 			case "v":
 				// Switch to vacation view (but not when in ClientsMode, where 'v' views rates)
 				if m.ActiveMode != ClientsMode {
 					m.ActiveMode = VacationMode
 					m.VacationModel = InitialVacationModel()
-					SaveAppState(AppState{ActiveTab: AppModeToString(m.ActiveMode)})
+					saveActiveTab(m.ActiveMode)
 				}
 			case "r":
 				// Refresh all views
@@ -298,10 +399,26 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				m.EarningsModel = InitialEarningsModel()
 				m.ConfigModel = InitialConfigModel()
 				return m, nil
+			case "ctrl+z":
+				return m.undoLast()
+			case "U":
+				// The timesheet's own undo key, next to c(lear) and p(aste)
+				if m.ActiveMode == TimesheetMode {
+					return m.undoLast()
+				}
 			}
 		}
 	}
 
+	// Remember destructive actions so they can be undone with ctrl+z
+	if pushMsg, ok := msg.(PushUndoMsg); ok {
+		m.undoStack = append(m.undoStack, pushMsg.Action)
+		if len(m.undoStack) > maxUndoActions {
+			m.undoStack = m.undoStack[len(m.undoStack)-maxUndoActions:]
+		}
+		return m, nil
+	}
+
 	// Handle refresh message
 	if _, ok := msg.(RefreshMsg); ok {
 		// Refresh all views
@@ -375,6 +492,11 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	// Handle event-driven sync requests (after create/update/delete).
 	// The periodic ticker keeps running independently.
 	if _, ok := msg.(TriggerSyncMsg); ok {
+		// Write handlers dispatch TriggerSyncMsg, so it doubles as the
+		// change notification
+		if m.notifyChange != nil {
+			m.notifyChange()
+		}
 		if m.syncEnabled && m.syncService != nil {
 			m.syncStatus = "Syncing…"
 			return m, DoSyncCmd(m.syncService)
@@ -389,25 +511,8 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.syncStatus = "Sync error"
 		} else {
 			m.syncStatus = FormatSyncStatus(m.lastSyncTime, false, false)
-			// Refresh views to show any synced data. The timesheet rebuilds
-			// from the user's current month/cursor so a sync never yanks the
-			// selection back to today (or back to the current month if they
-			// were browsing history).
-			tsYear, tsMonth := m.TimesheetModel.currentYear, m.TimesheetModel.currentMonth
-			tsSelected := ""
-			if rows := m.TimesheetModel.table.Rows(); len(rows) > 0 {
-				if c := m.TimesheetModel.table.Cursor(); c >= 0 && c < len(rows) {
-					tsSelected = rows[c][0]
-				}
-			}
-			m.OverviewModel = InitialOverviewModel()
-			m.TimesheetModel = InitialTimesheetModelForMonth(tsYear, tsMonth, tsSelected)
-			m.TrainingModel = InitialTrainingModel()
-			m.TrainingBudgetModel = InitialTrainingBudgetModel()
-			m.VacationModel = InitialVacationModel()
-			m.BufferModel = InitialBufferModel()
-			m.ClientsModel = InitialClientsModel()
-			m.EarningsModel = InitialEarningsModel()
+			// Refresh views to show any synced data
+			m.reloadViews()
 		}
 		return m, nil
 	}
@@ -416,7 +521,7 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	switch m.ActiveMode {
 	case TimesheetMode:
 		// Special handling for switching to form mode
-		if keyMsg, ok := msg.(tea.KeyMsg); ok {
+		if keyMsg, ok := msg.(tea.KeyMsg); ok && !m.TimesheetModel.IsEditing() {
 			if keyMsg.String() == "a" {
 				m.ActiveMode = FormMode
 				// Use the selected row's date for the form
@@ -435,10 +540,15 @@ func (m AppModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			date := editMsg.Date
 			m.FormModel = InitialFormModelWithDate(date)
 
-			// Try to load existing data
+			// Try to load existing data: the selected client's block, or
+			// the date's first one
 			dataLayer := datalayer.GetDataLayer()
-			entry, err := dataLayer.GetTimesheetEntryByDate(date)
-			if err == nil {
+			entries, err := dataLayer.GetTimesheetEntriesByDate(date)
+			if err == nil && len(entries) > 0 {
+				entry, ok := db.FindClientEntry(entries, editMsg.Client)
+				if !ok {
+					entry = entries[0]
+				}
 				// Entry found, populate form fields
 				m.FormModel.prefillFromEntry(entry)
 				m.FormModel.isEditing = true
@@ -214,7 +214,7 @@ func (m BufferModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, tea.Printf("Error deleting buffer entry: %v", err)
 				}
 				m.reload(m.currentYear)
-				return m, TriggerSync()
+				return m, tea.Batch(PushUndo(bufferEntryUndo(entry)), TriggerSync())
 			}
 		case key.Matches(msg, m.keys.Down):
 			last := m.lastSelectableRowIndex()
@@ -1,6 +1,9 @@
 package ui
 
 import (
+	"fmt"
+	"strconv"
+	"strings"
 	"timesheet/internal/datalayer"
 	"timesheet/internal/db"
 
@@ -25,23 +28,75 @@ type ClientFormModel struct {
 	err        error
 }
 
+// Client form fields
+const (
+	clientNameField = iota
+	clientCurrencyField
+	clientMinHoursField
+	clientMonthlyCapField
+	clientTotalCapField
+	clientFieldCount
+)
+
 func InitialClientFormModel() ClientFormModel {
 	m := ClientFormModel{
-		inputs:   make([]textinput.Model, 1),
+		inputs:   make([]textinput.Model, clientFieldCount),
 		isActive: true, // Default to active for new clients
 	}
 
+	m.inputs[clientNameField] = newClientFormInput("Client Name", 100)
+	m.inputs[clientNameField].Focus()
+	m.inputs[clientCurrencyField] = newClientFormInput("Currency (e.g. USD, empty = base currency)", 3)
+	m.inputs[clientMinHoursField] = newClientFormInput("Minimum billable hours per day (empty = none)", 5)
+	m.inputs[clientMonthlyCapField] = newClientFormInput("Monthly hour cap (empty = none)", 5)
+	m.inputs[clientTotalCapField] = newClientFormInput("Total hour cap (empty = none)", 6)
+
+	return m
+}
+
+func newClientFormInput(placeholder string, charLimit int) textinput.Model {
 	t := textinput.New()
 	t.Cursor.Style = lipgloss.NewStyle().Foreground(lipgloss.Color("212"))
-	t.CharLimit = 100
-	t.Placeholder = "Client Name"
-	t.Focus()
+	t.CharLimit = charLimit
+	t.Placeholder = placeholder
 	t.PromptStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
 	t.TextStyle = lipgloss.NewStyle().Foreground(lipgloss.Color("205"))
+	return t
+}
 
-	m.inputs[0] = t
+// parseMinBillableHours reads the minimum billable hours field; empty means
+// no minimum
+func parseMinBillableHours(value string) (float64, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	hours, err := strconv.ParseFloat(value, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid minimum billable hours %q", value)
+	}
+	return hours, db.ValidateMinBillableHours(hours)
+}
 
-	return m
+// parseHourCap reads an hour cap field; empty means no cap
+func parseHourCap(name, value string) (int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return 0, nil
+	}
+	hours, err := strconv.Atoi(value)
+	if err != nil || hours < 0 {
+		return 0, fmt.Errorf("invalid %s hour cap %q", name, value)
+	}
+	return hours, nil
+}
+
+// formatOptionalHours shows 0 (none) as an empty field
+func formatOptionalHours(hours float64) string {
+	if hours <= 0 {
+		return ""
+	}
+	return strconv.FormatFloat(hours, 'f', -1, 64)
 }
 
 func (m ClientFormModel) Init() tea.Cmd {
@@ -62,19 +117,43 @@ func (m ClientFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		case "enter":
 			// Submit the form
-			clientName := m.inputs[0].Value()
+			clientName := m.inputs[clientNameField].Value()
 			if clientName == "" {
 				m.err = nil
 				return m, nil
 			}
+			currency, err := db.NormalizeCurrency(m.inputs[clientCurrencyField].Value())
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			minHours, err := parseMinBillableHours(m.inputs[clientMinHoursField].Value())
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			monthlyCap, err := parseHourCap("monthly", m.inputs[clientMonthlyCapField].Value())
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
+			totalCap, err := parseHourCap("total", m.inputs[clientTotalCapField].Value())
+			if err != nil {
+				m.err = err
+				return m, nil
+			}
 
 			dataLayer := datalayer.GetDataLayer()
 
 			if m.mode == ClientFormAdd {
 				// Add new client
 				client := db.Client{
-					Name:     clientName,
-					IsActive: m.isActive,
+					Name:             clientName,
+					IsActive:         m.isActive,
+					Currency:         currency,
+					MinBillableHours: minHours,
+					MonthlyHourCap:   monthlyCap,
+					TotalHourCap:     totalCap,
 				}
 
 				_, err := dataLayer.AddClient(client)
@@ -86,6 +165,10 @@ func (m ClientFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Edit existing client
 				m.client.Name = clientName
 				m.client.IsActive = m.isActive
+				m.client.Currency = currency
+				m.client.MinBillableHours = minHours
+				m.client.MonthlyHourCap = monthlyCap
+				m.client.TotalHourCap = totalCap
 
 				err := dataLayer.UpdateClient(m.client)
 				if err != nil {
@@ -104,6 +187,16 @@ func (m ClientFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case "tab":
 			// Toggle active status
 			m.isActive = !m.isActive
+
+		case "up", "down":
+			// Move between the fields
+			m.inputs[m.focusIndex].Blur()
+			if msg.String() == "up" {
+				m.focusIndex = (m.focusIndex + len(m.inputs) - 1) % len(m.inputs)
+			} else {
+				m.focusIndex = (m.focusIndex + 1) % len(m.inputs)
+			}
+			return m, m.inputs[m.focusIndex].Focus()
 		}
 	}
 
@@ -115,9 +208,11 @@ func (m ClientFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m *ClientFormModel) updateInputs(msg tea.Msg) tea.Cmd {
-	var cmd tea.Cmd
-	m.inputs[0], cmd = m.inputs[0].Update(msg)
-	return cmd
+	cmds := make([]tea.Cmd, len(m.inputs))
+	for i := range m.inputs {
+		m.inputs[i], cmds[i] = m.inputs[i].Update(msg)
+	}
+	return tea.Batch(cmds...)
 }
 
 func (m ClientFormModel) View() string {
@@ -129,7 +224,10 @@ func (m ClientFormModel) View() string {
 		s += titleStyle.Render("Edit Client") + "\n\n"
 	}
 
-	s += m.inputs[0].View() + "\n\n"
+	for _, input := range m.inputs {
+		s += input.View() + "\n"
+	}
+	s += "\n"
 
 	// Active status toggle
 	activeStatus := "[ ] Active"
@@ -143,7 +241,7 @@ func (m ClientFormModel) View() string {
 		s += lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render("Error: "+m.err.Error()) + "\n\n"
 	}
 
-	s += helpStyle.Render("Enter: Save • Esc: Cancel") + "\n"
+	s += helpStyle.Render("Enter: Save • ↑/↓: Switch field • Esc: Cancel") + "\n"
 
 	return baseStyle.Render(s)
 }
@@ -151,8 +249,10 @@ func (m ClientFormModel) View() string {
 func (m *ClientFormModel) SetAddMode() {
 	m.mode = ClientFormAdd
 	m.isActive = true
-	m.inputs[0].SetValue("")
-	m.inputs[0].Focus()
+	for i := range m.inputs {
+		m.inputs[i].SetValue("")
+	}
+	m.focusFirstField()
 	m.err = nil
 }
 
@@ -160,10 +260,23 @@ func (m *ClientFormModel) SetEditMode(client db.Client) {
 	m.mode = ClientFormEdit
 	m.client = client
 	m.isActive = client.IsActive
-	m.inputs[0].SetValue(client.Name)
-	m.inputs[0].Focus()
+	m.inputs[clientNameField].SetValue(client.Name)
+	m.inputs[clientCurrencyField].SetValue(client.Currency)
+	m.inputs[clientMinHoursField].SetValue(formatOptionalHours(client.MinBillableHours))
+	m.inputs[clientMonthlyCapField].SetValue(formatOptionalHours(float64(client.MonthlyHourCap)))
+	m.inputs[clientTotalCapField].SetValue(formatOptionalHours(float64(client.TotalHourCap)))
+	m.focusFirstField()
 	m.err = nil
 }
 
+// focusFirstField moves the cursor to the client name
+func (m *ClientFormModel) focusFirstField() {
+	m.focusIndex = clientNameField
+	for i := range m.inputs {
+		m.inputs[i].Blur()
+	}
+	m.inputs[clientNameField].Focus()
+}
+
 // SwitchToClientsMsg signals to return to clients view
 type SwitchToClientsMsg struct{}
@@ -21,6 +21,7 @@ type ClientRatesModalKeyMap struct {
 	Down    key.Binding
 	Quit    key.Binding
 	Add     key.Binding
+	Edit    key.Binding
 	Delete  key.Binding
 	HelpKey key.Binding
 }
@@ -44,6 +45,10 @@ func DefaultClientRatesModalKeyMap() ClientRatesModalKeyMap {
 			key.WithKeys("a", "n"),
 			key.WithHelp("a/n", "add rate"),
 		),
+		Edit: key.NewBinding(
+			key.WithKeys("e", "enter"),
+			key.WithHelp("e/enter", "edit rate"),
+		),
 		Delete: key.NewBinding(
 			key.WithKeys("d"),
 			key.WithHelp("d", "delete"),
@@ -57,13 +62,13 @@ func DefaultClientRatesModalKeyMap() ClientRatesModalKeyMap {
 
 // ShortHelp returns keybindings to be shown in the mini help view
 func (k ClientRatesModalKeyMap) ShortHelp() []key.Binding {
-	return []key.Binding{k.Up, k.Down, k.Add, k.Quit}
+	return []key.Binding{k.Up, k.Down, k.Add, k.Edit, k.Quit}
 }
 
 // FullHelp returns keybindings for the expanded help view
 func (k ClientRatesModalKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Add, k.Delete},
+		{k.Up, k.Down, k.Add, k.Edit, k.Delete},
 		{k.HelpKey, k.Quit},
 	}
 }
@@ -85,6 +90,7 @@ type ClientRatesModalModel struct {
 	mode       ClientRatesViewMode
 	inputs     []textinput.Model
 	focusIndex int
+	editing    *db.ClientRate // Rate being edited in RatesAddMode; nil when adding
 	err        error
 }
 
@@ -96,6 +102,7 @@ func InitialClientRatesModalModel(clientId int) ClientRatesModalModel {
 	columns := []table.Column{
 		{Title: "Effective Date", Width: 15},
 		{Title: "Hourly Rate", Width: 15},
+		{Title: "End Date", Width: 12},
 		{Title: "Notes", Width: 40},
 	}
 
@@ -118,8 +125,8 @@ func InitialClientRatesModalModel(clientId int) ClientRatesModalModel {
 
 	t.SetStyles(s)
 
-	// Create inputs for adding rates
-	inputs := make([]textinput.Model, 3)
+	// Create inputs for adding and editing rates
+	inputs := make([]textinput.Model, 4)
 	inputs[0] = textinput.New()
 	inputs[0].Placeholder = "YYYY-MM-DD"
 	inputs[0].CharLimit = 10
@@ -130,8 +137,12 @@ func InitialClientRatesModalModel(clientId int) ClientRatesModalModel {
 	inputs[1].CharLimit = 10
 
 	inputs[2] = textinput.New()
-	inputs[2].Placeholder = "Optional notes"
-	inputs[2].CharLimit = 100
+	inputs[2].Placeholder = "YYYY-MM-DD (optional)"
+	inputs[2].CharLimit = 10
+
+	inputs[3] = textinput.New()
+	inputs[3].Placeholder = "Optional notes"
+	inputs[3].CharLimit = 100
 
 	model := ClientRatesModalModel{
 		client:   client,
@@ -158,7 +169,8 @@ func (m *ClientRatesModalModel) loadRates() {
 	for _, rate := range rates {
 		rows = append(rows, table.Row{
 			rate.EffectiveDate,
-			utils.FormatEuro(rate.HourlyRate),
+			utils.FormatMoney(rate.HourlyRate, clientMoneyFormat(m.client)),
+			rateEndLabel(rate),
 			rate.Notes,
 		})
 	}
@@ -190,14 +202,12 @@ func (m ClientRatesModalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return CloseClientRatesModalMsg{}
 			}
 		case key.Matches(msg, m.keys.Add):
-			m.mode = RatesAddMode
-			m.focusIndex = 0
-			// Clear inputs
-			for i := range m.inputs {
-				m.inputs[i].SetValue("")
+			return m, m.openForm(nil)
+		case key.Matches(msg, m.keys.Edit):
+			if len(m.rates) > 0 && m.table.Cursor() < len(m.rates) {
+				rate := m.rates[m.table.Cursor()]
+				return m, m.openForm(&rate)
 			}
-			m.inputs[0].Focus()
-			return m, textinput.Blink
 		case key.Matches(msg, m.keys.Delete):
 			if len(m.rates) > 0 && m.table.Cursor() < len(m.rates) {
 				rate := m.rates[m.table.Cursor()]
@@ -206,7 +216,7 @@ func (m ClientRatesModalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					m.err = err
 				} else {
 					m.loadRates()
-					return m, TriggerSync()
+					return m, tea.Batch(PushUndo(clientRateUndo(rate)), TriggerSync())
 				}
 			}
 		case key.Matches(msg, m.keys.Up):
@@ -228,6 +238,27 @@ func (m ClientRatesModalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// openForm switches to the rate form, filled with rate when editing one and
+// empty when rate is nil
+func (m *ClientRatesModalModel) openForm(rate *db.ClientRate) tea.Cmd {
+	m.mode = RatesAddMode
+	m.editing = rate
+	m.err = nil
+	m.focusIndex = 0
+	for i := range m.inputs {
+		m.inputs[i].SetValue("")
+		m.inputs[i].Blur()
+	}
+	if rate != nil {
+		m.inputs[0].SetValue(rate.EffectiveDate)
+		m.inputs[1].SetValue(strconv.FormatFloat(rate.HourlyRate, 'f', -1, 64))
+		m.inputs[2].SetValue(rate.EndDate)
+		m.inputs[3].SetValue(rate.Notes)
+	}
+	m.inputs[0].Focus()
+	return textinput.Blink
+}
+
 func (m ClientRatesModalModel) updateAddMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmds []tea.Cmd
 
@@ -236,6 +267,7 @@ func (m ClientRatesModalModel) updateAddMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 		switch msg.String() {
 		case "esc":
 			m.mode = RatesViewMode
+			m.editing = nil
 			m.err = nil
 			return m, nil
 
@@ -244,7 +276,8 @@ func (m ClientRatesModalModel) updateAddMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 				// Submit the form
 				effectiveDate := m.inputs[0].Value()
 				rateStr := m.inputs[1].Value()
-				notes := m.inputs[2].Value()
+				endDate := m.inputs[2].Value()
+				notes := m.inputs[3].Value()
 
 				if effectiveDate == "" || rateStr == "" {
 					m.err = fmt.Errorf("effective date and rate are required")
@@ -262,16 +295,25 @@ func (m ClientRatesModalModel) updateAddMode(msg tea.Msg) (tea.Model, tea.Cmd) {
 					ClientId:      m.client.Id,
 					HourlyRate:    rate,
 					EffectiveDate: effectiveDate,
+					EndDate:       endDate,
 					Notes:         notes,
 				}
 
-				if err := dataLayer.AddClientRate(clientRate); err != nil {
+				if m.editing != nil {
+					clientRate.Id = m.editing.Id
+					clientRate.CreatedAt = m.editing.CreatedAt
+					err = dataLayer.UpdateClientRate(clientRate)
+				} else {
+					err = dataLayer.AddClientRate(clientRate)
+				}
+				if err != nil {
 					m.err = err
 					return m, nil
 				}
 
 				m.loadRates()
 				m.mode = RatesViewMode
+				m.editing = nil
 				m.err = nil
 				return m, TriggerSync()
 			}
@@ -331,7 +373,7 @@ func (m ClientRatesModalModel) View() string {
 
 	var s string
 
-	s += titleStyle.Render(fmt.Sprintf("Rates for %s", m.client.Name)) + "\n\n"
+	s += titleStyle.Render(fmt.Sprintf("Rates for %s", utils.ClientLabel(m.client.Name))) + "\n\n"
 	s += m.table.View() + "\n\n"
 
 	if m.err != nil {
@@ -350,9 +392,13 @@ func (m ClientRatesModalModel) View() string {
 func (m ClientRatesModalModel) viewAddMode() string {
 	var s string
 
-	s += titleStyle.Render(fmt.Sprintf("Add Rate for %s", m.client.Name)) + "\n\n"
+	title := "Add Rate for %s"
+	if m.editing != nil {
+		title = "Edit Rate for %s"
+	}
+	s += titleStyle.Render(fmt.Sprintf(title, utils.ClientLabel(m.client.Name))) + "\n\n"
 
-	labels := []string{"Effective Date:", "Hourly Rate:", "Notes:"}
+	labels := []string{"Effective Date:", "Hourly Rate:", "End Date:", "Notes:"}
 	for i, input := range m.inputs {
 		s += labels[i] + "\n"
 		s += input.View() + "\n\n"
@@ -369,3 +415,11 @@ func (m ClientRatesModalModel) viewAddMode() string {
 
 // CloseClientRatesModalMsg signals to close the client rates modal
 type CloseClientRatesModalMsg struct{}
+
+// rateEndLabel shows a rate's end date, or "-" for an open-ended rate
+func rateEndLabel(rate db.ClientRate) string {
+	if rate.EndDate == "" {
+		return "-"
+	}
+	return rate.EndDate
+}
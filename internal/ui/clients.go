@@ -3,6 +3,7 @@ package ui
 import (
 	"strconv"
 	"time"
+	"timesheet/internal/config"
 	"timesheet/internal/datalayer"
 	"timesheet/internal/db"
 	"timesheet/internal/utils"
@@ -251,8 +252,8 @@ func (m *ClientsModel) loadClients() {
 					}
 				}
 			}
-			if latestRate != nil {
-				currentRate = utils.FormatEuro(latestRate.HourlyRate)
+			if latestRate != nil && !latestRate.Ended(today) {
+				currentRate = utils.FormatMoney(latestRate.HourlyRate, clientMoneyFormat(client))
 			}
 		}
 
@@ -263,7 +264,7 @@ func (m *ClientsModel) loadClients() {
 
 		rows = append(rows, table.Row{
 			strconv.Itoa(client.Id),
-			client.Name,
+			utils.ClientLabel(client.Name),
 			currentRate,
 			activeStr,
 		})
@@ -277,6 +278,15 @@ func (m *ClientsModel) loadClients() {
 	}
 }
 
+// clientMoneyFormat returns the format for amounts in a client's currency,
+// the base currency when it has none
+func clientMoneyFormat(client db.Client) utils.MoneyFormat {
+	if client.Currency == "" {
+		return utils.MoneyFormatFor(config.GetBaseCurrency())
+	}
+	return utils.MoneyFormatFor(client.Currency)
+}
+
 func (m ClientsModel) Init() tea.Cmd {
 	return RefreshClientsCmd()
 }
@@ -20,12 +20,8 @@ import (
 	"github.com/rmhubbert/bubbletea-overlay"
 )
 
-// Package-level cache for update check results (1-hour cache to avoid rate limiting)
-var (
-	lastCheckTime time.Time
-	cachedResult  updateCheckResultMsg
-	checkMutex    sync.Mutex
-)
+// checkMutex keeps the app and the config tab from checking at the same time
+var checkMutex sync.Mutex
 
 // updateCheckResultMsg contains the result of checking for updates
 type updateCheckResultMsg struct {
@@ -34,35 +30,23 @@ type updateCheckResultMsg struct {
 	err             error
 }
 
-// CheckForUpdatesCmd returns a command that checks for updates
+// CheckForUpdatesCmd returns a command that checks for updates in the
+// background. GitHub is asked at most once a day; the result is cached on
+// disk in between (see updater.CheckForUpdateCached).
 // Can be called from AppModel.Init() to check on startup
 func CheckForUpdatesCmd() tea.Cmd {
 	return func() tea.Msg {
 		checkMutex.Lock()
 		defer checkMutex.Unlock()
 
-		// Return cached result if checked within last hour
-		if time.Since(lastCheckTime) < time.Hour && cachedResult.latestVersion != "" {
-			return cachedResult
-		}
-
-		// Perform actual check
 		checker := updater.NewUpdateChecker("joelgrimberg", "timesheetz")
-		latest, available, err := checker.CheckForUpdate(version.Version)
+		latest, available, err := checker.CheckForUpdateCached(version.Version, updater.DefaultCachePath())
 
-		result := updateCheckResultMsg{
+		return updateCheckResultMsg{
 			latestVersion:   latest,
 			updateAvailable: available,
 			err:             err,
 		}
-
-		// Cache successful results
-		if err == nil {
-			cachedResult = result
-			lastCheckTime = time.Now()
-		}
-
-		return result
 	}
 }
 
@@ -267,12 +251,31 @@ type ConfigModel struct {
 	vacationTargetRowIdx   int
 	vacationCategoryRowIdx int
 	workScheduleRowIdx     [7]int // indexed by time.Weekday
+	standardDayRowIdx      int
 
 	// Update checking fields
 	latestVersion   string
 	updateAvailable bool
 	checkingUpdate  bool
 	updateCheckErr  error
+
+	// Space the view has, 0 until the terminal size is known
+	width  int
+	height int
+}
+
+// configChromeHeight is the number of lines the config view renders around
+// its table: the table border, a blank line and the help line
+const configChromeHeight = 4
+
+// setSize fits the table and help to the space the view has
+func (m *ConfigModel) setSize(width, height int) {
+	if width == m.width && height == m.height {
+		return
+	}
+	m.width, m.height = width, height
+	m.help.Width = width
+	m.table.SetHeight(fitTableHeight(height, configChromeHeight))
 }
 
 // IsEditing returns true if a modal is active (text input or mode selection)
@@ -368,6 +371,7 @@ func InitialConfigModel() ConfigModel {
 		vacationTargetRowIdx:   indices.vacationTargetRowIdx,
 		vacationCategoryRowIdx: indices.vacationCategoryRowIdx,
 		workScheduleRowIdx:     indices.workScheduleRowIdx,
+		standardDayRowIdx:      indices.standardDayRowIdx,
 	}
 }
 
@@ -708,13 +712,15 @@ type DocumentTypeSelectedMsg struct {
 // DocumentTypeCancelledMsg is sent when document type modal is cancelled
 type DocumentTypeCancelledMsg struct{}
 
+// documentTypes are the sendDocumentType values the modal offers
+var documentTypes = []string{"excel", "pdf", "csv"}
+
 func InitialDocumentTypeModalModel(currentType string) *DocumentTypeModalModel {
 	if currentType == "" {
 		currentType = "excel"
 	}
 	typeCursor := 0
-	types := []string{"excel", "pdf"}
-	for i, t := range types {
+	for i, t := range documentTypes {
 		if t == currentType {
 			typeCursor = i
 			break
@@ -741,19 +747,18 @@ func (m DocumentTypeModalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		case key.Matches(msg, m.keys.Up):
 			m.cursor--
 			if m.cursor < 0 {
-				m.cursor = 1
+				m.cursor = len(documentTypes) - 1
 			}
 			return m, nil
 		case key.Matches(msg, m.keys.Down):
 			m.cursor++
-			if m.cursor > 1 {
+			if m.cursor >= len(documentTypes) {
 				m.cursor = 0
 			}
 			return m, nil
 		case key.Matches(msg, m.keys.Enter):
-			types := []string{"excel", "pdf"}
 			return m, func() tea.Msg {
-				return DocumentTypeSelectedMsg{DocumentType: types[m.cursor]}
+				return DocumentTypeSelectedMsg{DocumentType: documentTypes[m.cursor]}
 			}
 		}
 	}
@@ -761,17 +766,17 @@ func (m DocumentTypeModalModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 }
 
 func (m DocumentTypeModalModel) View() string {
-	types := []string{"excel", "pdf"}
 	typeDescriptions := []string{
 		"Excel spreadsheet (.xlsx)",
 		"PDF document (.pdf)",
+		"Comma-separated values (.csv)",
 	}
 
 	var modalRows []string
 	modalRows = append(modalRows, lipgloss.NewStyle().Bold(true).Render("Select Document Type:"))
 	modalRows = append(modalRows, "")
 
-	for i, t := range types {
+	for i, t := range documentTypes {
 		var style lipgloss.Style
 		if i == m.cursor {
 			style = lipgloss.NewStyle().
@@ -985,6 +990,7 @@ type configRowIndices struct {
 	vacationTargetRowIdx   int
 	vacationCategoryRowIdx int
 	workScheduleRowIdx     [7]int // indexed by time.Weekday
+	standardDayRowIdx      int
 }
 
 // buildTableRows builds the configuration table rows with update info
@@ -1164,6 +1170,12 @@ func (m ConfigModel) buildTableRows(cfg *config.Config) ([]table.Row, configRowI
 		indices.workScheduleRowIdx[d.wd] = len(rows)
 		rows = append(rows, table.Row{d.label, strconv.Itoa(d.hours)})
 	}
+	indices.standardDayRowIdx = len(rows)
+	standardDay := strconv.Itoa(config.GetStandardDailyHours())
+	if cfg.StandardDailyHours <= 0 {
+		standardDay += " (default)"
+	}
+	rows = append(rows, table.Row{"  Standard Day (hours)", standardDay})
 
 	return rows, indices
 }
@@ -1176,6 +1188,11 @@ func (m ConfigModel) Init() tea.Cmd {
 func (m ConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
+	if size, ok := msg.(tea.WindowSizeMsg); ok {
+		m.setSize(size.Width, size.Height)
+		return m, nil
+	}
+
 	// Handle text input modal FIRST - capture all input when modal is active
 	if m.textModal != nil {
 		// Check for save/cancel messages
@@ -1247,6 +1264,10 @@ func (m ConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					if h, err := strconv.Atoi(strings.TrimSpace(saveMsg.Value)); err == nil {
 						cfg.WorkSchedule.Sunday = h
 					}
+				case "Standard daily hours":
+					if h, err := strconv.Atoi(strings.TrimSpace(saveMsg.Value)); err == nil && h >= 0 {
+						cfg.StandardDailyHours = h
+					}
 				}
 				config.SaveConfig(cfg)
 				// Rebuild the table with updated values
@@ -1507,6 +1528,10 @@ func (m ConfigModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 					return m, m.textModal.Init()
 				}
 			}
+			if cursor == m.standardDayRowIdx {
+				m.textModal = InitialTextInputModal("Standard daily hours", strconv.Itoa(config.GetStandardDailyHours()))
+				return m, m.textModal.Init()
+			}
 
 			// Boolean toggle fields
 			if cursor == m.startAPIServerRowIdx {
@@ -1,7 +1,7 @@
 package ui
 
 import (
-	"fmt"
+	"strings"
 	"time"
 	"timesheet/internal/datalayer"
 	"timesheet/internal/db"
@@ -231,42 +231,49 @@ func (m *EarningsModel) loadEarnings() {
 	// Convert entries to table rows
 	var rows []table.Row
 	for _, entry := range overview.Entries {
+		format := utils.MoneyFormatFor(entry.Currency)
 		if m.summaryMode && !m.monthlyView {
 			// Summary mode: no date column
 			rows = append(rows, table.Row{
-				entry.ClientName,
-				utils.FormatEuro(entry.HourlyRate),
-				fmt.Sprintf("%d", entry.ClientHours),
-				utils.FormatEuro(entry.Earnings),
+				utils.ClientLabel(entry.ClientName),
+				utils.FormatMoney(entry.HourlyRate, format),
+				utils.FormatHours(entry.ClientHours),
+				utils.FormatMoney(entry.Earnings, format),
 			})
 		} else {
 			// Detailed mode: include date
 			rows = append(rows, table.Row{
 				entry.Date,
-				entry.ClientName,
-				fmt.Sprintf("%d", entry.ClientHours),
-				utils.FormatEuro(entry.HourlyRate),
-				utils.FormatEuro(entry.Earnings),
+				utils.ClientLabel(entry.ClientName),
+				utils.FormatHours(entry.ClientHours),
+				utils.FormatMoney(entry.HourlyRate, format),
+				utils.FormatMoney(entry.Earnings, format),
 			})
 		}
 	}
 
-	// Add total row
-	if m.summaryMode && !m.monthlyView {
-		rows = append(rows, table.Row{
-			"TOTAL",
-			"",
-			fmt.Sprintf("%d", overview.TotalHours),
-			utils.FormatEuro(overview.TotalEarnings),
-		})
-	} else {
-		rows = append(rows, table.Row{
-			"TOTAL",
-			"",
-			fmt.Sprintf("%d", overview.TotalHours),
-			"",
-			utils.FormatEuro(overview.TotalEarnings),
-		})
+	// With clients in several currencies, subtotal each before the
+	// converted grand total
+	if len(overview.ByCurrency) > 1 {
+		for _, total := range overview.ByCurrency {
+			rows = append(rows, m.totalRow("Total "+total.Currency, total.Hours,
+				utils.FormatMoney(total.Earnings, utils.MoneyFormatFor(total.Currency))))
+		}
+	}
+
+	// Add total row; currencies without an exchange rate aren't in it
+	label := "TOTAL"
+	if len(overview.Unconverted) > 0 {
+		label = "TOTAL (excl. " + strings.Join(overview.Unconverted, ", ") + ")"
+	}
+	rows = append(rows, m.totalRow(label, overview.TotalHours,
+		utils.FormatMoney(overview.TotalEarnings, utils.MoneyFormatFor(overview.Currency))))
+
+	// Billable expenses come on top of the hours, when there are any
+	if overview.BillableExpenses != 0 {
+		baseFormat := utils.MoneyFormatFor(overview.Currency)
+		rows = append(rows, m.totalRow("Expenses", 0, utils.FormatMoney(overview.BillableExpenses, baseFormat)))
+		rows = append(rows, m.totalRow("TOTAL INVOICED", overview.TotalHours, utils.FormatMoney(overview.TotalInvoiced, baseFormat)))
 	}
 
 	m.table.SetRows(rows)
@@ -280,6 +287,14 @@ func (m *EarningsModel) loadEarnings() {
 	}
 }
 
+// totalRow builds a totals row for the current table layout
+func (m *EarningsModel) totalRow(label string, hours float64, earnings string) table.Row {
+	if m.summaryMode && !m.monthlyView {
+		return table.Row{label, "", utils.FormatHours(hours), earnings}
+	}
+	return table.Row{label, "", utils.FormatHours(hours), "", earnings}
+}
+
 func (m EarningsModel) Init() tea.Cmd {
 	return RefreshEarningsCmd()
 }
@@ -0,0 +1,96 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+	"timesheet/internal/config"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// EmailConfirmModel asks for a yes before the month's timesheet is emailed
+type EmailConfirmModel struct {
+	year      int
+	month     time.Month
+	recipient string
+	confirm   key.Binding
+	cancel    key.Binding
+}
+
+// EmailConfirmedMsg is sent when the user agrees to email the timesheet
+type EmailConfirmedMsg struct {
+	Year  int
+	Month time.Month
+}
+
+// EmailCancelledMsg is sent when the confirmation is closed without sending
+type EmailCancelledMsg struct{}
+
+// NewEmailConfirmModel creates the confirmation for emailing a month, showing
+// the recipient from the config
+func NewEmailConfirmModel(year int, month time.Month) *EmailConfirmModel {
+	_, _, recipient, _, _, _, _ := config.GetEmailConfig()
+	return &EmailConfirmModel{
+		year:      year,
+		month:     month,
+		recipient: recipient,
+		confirm: key.NewBinding(
+			key.WithKeys("y"),
+			key.WithHelp("y", "send"),
+		),
+		cancel: key.NewBinding(
+			key.WithKeys("esc", "n", "q"),
+			key.WithHelp("esc/n", "cancel"),
+		),
+	}
+}
+
+func (m EmailConfirmModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update sends EmailConfirmedMsg on "y" and EmailCancelledMsg on esc or "n";
+// other keys are ignored so a stray enter doesn't send anything
+func (m EmailConfirmModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+	switch {
+	case key.Matches(keyMsg, m.confirm):
+		return m, func() tea.Msg {
+			return EmailConfirmedMsg{Year: m.year, Month: m.month}
+		}
+	case key.Matches(keyMsg, m.cancel):
+		return m, func() tea.Msg {
+			return EmailCancelledMsg{}
+		}
+	}
+	return m, nil
+}
+
+func (m EmailConfirmModel) View() string {
+	recipient := m.recipient
+	if recipient == "" {
+		recipient = "(no recipient configured)"
+	}
+
+	rows := []string{
+		lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Email the timesheet of %s %d?", m.month, m.year)),
+		"",
+		fmt.Sprintf("To: %s", recipient),
+		"",
+		lipgloss.NewStyle().
+			Foreground(lipgloss.Color("240")).
+			Render("y: Send • Esc/n: Cancel"),
+	}
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(1, 2).
+		Width(60).
+		Render(lipgloss.JoinVertical(lipgloss.Left, rows...))
+}
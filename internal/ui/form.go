@@ -2,11 +2,14 @@ package ui
 
 import (
 	"fmt"
+	"math"
 	"strconv"
 	"strings"
 	"time"
+	"timesheet/internal/config"
 	"timesheet/internal/datalayer"
 	"timesheet/internal/db"
+	"timesheet/internal/utils"
 
 	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
@@ -23,11 +26,16 @@ const (
 	IdleHoursField
 	HolidayHoursField
 	SickHoursField
+	NoteField
+	// Only present with linkTrainingBudget
+	TrainingNameField
+	TrainingCostField
 )
 
 // Add to your message types
 type EditEntryMsg struct {
-	Date string
+	Date   string
+	Client string // Client of the block to edit; the date's first block when empty
 }
 
 type errMsg error
@@ -39,16 +47,17 @@ type FormModel struct {
 	error             string
 	success           string
 	isEditing         bool
+	entryId           int // ID of the entry being edited, so a changed client renames that block
 	quitAfterSubmit   bool
 	activeClients     []db.Client
 	currentSuggestion string
+	linkTraining      bool // Ask for a training budget entry with training hours
 }
 
 // Create a new form with initial values
 func InitialFormModel() FormModel {
-	// Default to today's date
-	today := time.Now().Format("2006-01-02")
-	return InitialFormModelWithDate(today)
+	// Default to today's date (or yesterday before dayBoundaryHour)
+	return InitialFormModelWithDate(entryToday())
 }
 
 // Create a new form with a specific date
@@ -80,6 +89,28 @@ func InitialFormModelWithDate(date string) FormModel {
 		inputs = append(inputs, i)
 	}
 
+	// Note field, e.g. why a day had idle hours
+	noteInput := textinput.New()
+	noteInput.Placeholder = "Optional note"
+	noteInput.CharLimit = 200
+	noteInput.Width = 40
+	inputs = append(inputs, noteInput)
+
+	// Training budget details, saved with the entry when training hours are
+	// logged
+	linkTraining := config.GetLinkTrainingBudget()
+	if linkTraining {
+		nameInput := textinput.New()
+		nameInput.Placeholder = "Training name (leave empty to skip)"
+		nameInput.CharLimit = 100
+		nameInput.Width = 40
+		costInput := textinput.New()
+		costInput.Placeholder = "Cost (without VAT)"
+		costInput.CharLimit = 10
+		costInput.Width = 10
+		inputs = append(inputs, nameInput, costInput)
+	}
+
 	// Load active clients for autocomplete
 	dataLayer := datalayer.GetDataLayer()
 	activeClients, err := dataLayer.GetActiveClients()
@@ -94,22 +125,36 @@ func InitialFormModelWithDate(date string) FormModel {
 		quitAfterSubmit:   false,
 		activeClients:     activeClients,
 		currentSuggestion: "",
+		linkTraining:      linkTraining,
 	}
 }
 
 // Prefill the form with existing entry data
 func (m *FormModel) prefillFromEntry(entry db.TimesheetEntry) {
-	m.inputs[ClientField].SetValue(entry.Client_name)
-	m.inputs[ClientHoursField].SetValue(strconv.Itoa(entry.Client_hours))
-	m.inputs[TrainingHoursField].SetValue(strconv.Itoa(entry.Training_hours))
-	m.inputs[VacationHoursField].SetValue(strconv.Itoa(entry.Vacation_hours))
-	m.inputs[IdleHoursField].SetValue(strconv.Itoa(entry.Idle_hours))
-	m.inputs[HolidayHoursField].SetValue(strconv.Itoa(entry.Holiday_hours))
-	m.inputs[SickHoursField].SetValue(strconv.Itoa(entry.Sick_hours))
+	m.entryId = entry.Id
+	m.inputs[ClientField].SetValue(utils.ClientLabel(entry.Client_name))
+	m.inputs[ClientHoursField].SetValue(utils.FormatHours(entry.Client_hours))
+	m.inputs[TrainingHoursField].SetValue(utils.FormatHours(entry.Training_hours))
+	m.inputs[VacationHoursField].SetValue(utils.FormatHours(entry.Vacation_hours))
+	m.inputs[IdleHoursField].SetValue(utils.FormatHours(entry.Idle_hours))
+	m.inputs[HolidayHoursField].SetValue(utils.FormatHours(entry.Holiday_hours))
+	m.inputs[SickHoursField].SetValue(utils.FormatHours(entry.Sick_hours))
+	m.inputs[NoteField].SetValue(entry.Note)
+	if m.linkTraining {
+		budget, err := datalayer.GetDataLayer().GetTrainingBudgetEntryByDate(entry.Date)
+		if err == nil {
+			m.inputs[TrainingNameField].SetValue(budget.Training_name)
+			m.inputs[TrainingCostField].SetValue(fmt.Sprintf("%.2f", budget.Cost_without_vat))
+		} else {
+			m.inputs[TrainingNameField].SetValue("")
+			m.inputs[TrainingCostField].SetValue("")
+		}
+	}
 }
 
 // Clear all form fields except the date
 func (m *FormModel) clearForm() {
+	m.entryId = 0
 	m.inputs[ClientField].SetValue("")
 	m.inputs[ClientHoursField].SetValue("")
 	m.inputs[TrainingHoursField].SetValue("")
@@ -117,6 +162,11 @@ func (m *FormModel) clearForm() {
 	m.inputs[IdleHoursField].SetValue("")
 	m.inputs[HolidayHoursField].SetValue("")
 	m.inputs[SickHoursField].SetValue("")
+	m.inputs[NoteField].SetValue("")
+	if m.linkTraining {
+		m.inputs[TrainingNameField].SetValue("")
+		m.inputs[TrainingCostField].SetValue("")
+	}
 }
 
 // SetFocus sets focus to a specific field
@@ -177,12 +227,12 @@ func (m FormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if m.focused == DateField {
 				date := m.inputs[DateField].Value()
 				if isValidDate(date) {
-					// Try to load existing entry for this date
+					// Try to load the first existing entry for this date
 					dataLayer := datalayer.GetDataLayer()
-					entry, err := dataLayer.GetTimesheetEntryByDate(date)
-					if err == nil {
+					entries, err := dataLayer.GetTimesheetEntriesByDate(date)
+					if err == nil && len(entries) > 0 {
 						// Entry exists, populate the form
-						m.prefillFromEntry(entry)
+						m.prefillFromEntry(entries[0])
 						m.isEditing = true
 					} else {
 						// No entry exists, clear the form
@@ -305,7 +355,7 @@ func (m FormModel) handleSubmit() tea.Cmd {
 		}
 	}
 
-	clientName := m.inputs[ClientField].Value()
+	clientName := utils.RealClientName(m.inputs[ClientField].Value())
 
 	// Validate and parse hours
 	clientHours, err := parseHours(m.inputs[ClientHoursField].Value())
@@ -383,14 +433,24 @@ func (m FormModel) handleSubmit() tea.Cmd {
 		Holiday_hours:  holidayHours,
 		Sick_hours:     sickHours,
 		Total_hours:    totalHours,
+		Note:           strings.TrimSpace(m.inputs[NoteField].Value()),
+	}
+
+	budget, linked, err := m.linkedTrainingBudget(trainingHours)
+	if err != nil {
+		return func() tea.Msg {
+			return errMsg(err)
+		}
 	}
 
 	var saveErr error
-	if m.isEditing {
-		dataLayer := datalayer.GetDataLayer()
+	dataLayer := datalayer.GetDataLayer()
+	if linked {
+		saveErr = db.SaveTrainingWithBudget(dataLayer, entry, budget)
+	} else if m.isEditing {
+		entry.Id = m.entryId
 		saveErr = dataLayer.UpdateTimesheetEntry(entry)
 	} else {
-		dataLayer := datalayer.GetDataLayer()
 		saveErr = dataLayer.AddTimesheetEntry(entry)
 	}
 
@@ -407,7 +467,34 @@ func (m FormModel) handleSubmit() tea.Cmd {
 
 	// Otherwise return to timesheet view; trigger sync so the change
 	// reaches other devices without waiting for the periodic tick.
-	return tea.Batch(ReturnToTimesheet(entry.Date), TriggerSync())
+	cmds := []tea.Cmd{ReturnToTimesheet(entry.Date), TriggerSync()}
+	if warning, err := db.CapWarning(dataLayer, entry); err == nil && warning != "" {
+		cmds = append(cmds, SetStatus(warning))
+	}
+	return tea.Batch(cmds...)
+}
+
+// linkedTrainingBudget returns the training budget entry to save along
+// with the timesheet entry. There's none unless linkTrainingBudget is on,
+// training hours are logged and a training name is filled in.
+func (m FormModel) linkedTrainingBudget(trainingHours float64) (db.TrainingBudgetEntry, bool, error) {
+	if !m.linkTraining || trainingHours == 0 {
+		return db.TrainingBudgetEntry{}, false, nil
+	}
+	name := strings.TrimSpace(m.inputs[TrainingNameField].Value())
+	if name == "" {
+		return db.TrainingBudgetEntry{}, false, nil
+	}
+
+	var cost float64
+	if costStr := strings.TrimSpace(m.inputs[TrainingCostField].Value()); costStr != "" {
+		var err error
+		cost, err = strconv.ParseFloat(costStr, 64)
+		if err != nil || cost < 0 {
+			return db.TrainingBudgetEntry{}, false, fmt.Errorf("invalid training cost: must be a positive number")
+		}
+	}
+	return db.TrainingBudgetEntry{Training_name: name, Cost_without_vat: cost}, true, nil
 }
 
 // Helper functions
@@ -422,6 +509,9 @@ func fieldLabel(i int) string {
 		"Idle Hours:",
 		"Holiday Hours:",
 		"Sick Hours:",
+		"Note:",
+		"Training Name:",
+		"Training Cost (excl. VAT):",
 	}
 	return labels[i]
 }
@@ -431,12 +521,12 @@ func isValidDate(date string) bool {
 	return err == nil
 }
 
-func parseHours(input string) (int, error) {
+func parseHours(input string) (float64, error) {
 	if input == "" {
 		return 0, nil
 	}
 
-	hours, err := strconv.Atoi(input)
+	hours, err := utils.ParseHours(input)
 	if err != nil {
 		return 0, fmt.Errorf("must be a number")
 	}
@@ -445,6 +535,10 @@ func parseHours(input string) (int, error) {
 		return 0, fmt.Errorf("cannot be negative")
 	}
 
+	if math.Mod(hours, 0.5) != 0 {
+		return 0, fmt.Errorf("must be in steps of 0.5")
+	}
+
 	return hours, nil
 }
 
@@ -455,7 +549,7 @@ func (m *FormModel) updateAutocompleteSuggestion() {
 	// If nothing is typed, show the first active client as suggestion
 	if typedText == "" {
 		if len(m.activeClients) > 0 {
-			m.currentSuggestion = m.activeClients[0].Name
+			m.currentSuggestion = utils.ClientLabel(m.activeClients[0].Name)
 		} else {
 			m.currentSuggestion = ""
 		}
@@ -465,11 +559,13 @@ func (m *FormModel) updateAutocompleteSuggestion() {
 	// Find first matching client (case-insensitive)
 	typedLower := strings.ToLower(typedText)
 
+	// Match against the labels shown on screen, so anonymized client names
+	// are never suggested
 	for _, client := range m.activeClients {
-		clientNameLower := strings.ToLower(client.Name)
-		if strings.HasPrefix(clientNameLower, typedLower) {
-			// Found a match - store the full client name as suggestion
-			m.currentSuggestion = client.Name
+		label := utils.ClientLabel(client.Name)
+		if strings.HasPrefix(strings.ToLower(label), typedLower) {
+			// Found a match - store the full label as suggestion
+			m.currentSuggestion = label
 			return
 		}
 	}
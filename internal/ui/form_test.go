@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"testing"
+	"timesheet/internal/db"
+	"timesheet/internal/utils"
+
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+func TestFormAnonymizesClientNames(t *testing.T) {
+	utils.SetAnonymize(true, []string{"Acme", "Globex"})
+	defer utils.SetAnonymize(false, nil)
+
+	m := FormModel{
+		inputs:        make([]textinput.Model, NoteField+1),
+		focused:       ClientField,
+		activeClients: []db.Client{{Name: "Acme"}, {Name: "Globex"}},
+	}
+	for i := range m.inputs {
+		m.inputs[i] = textinput.New()
+	}
+
+	m.prefillFromEntry(db.TimesheetEntry{Date: "2024-06-03", Client_name: "Globex", Client_hours: 8})
+	if got := m.inputs[ClientField].Value(); got != "Client B" {
+		t.Errorf("Prefilled client = %q, want the pseudonym Client B", got)
+	}
+	if got := utils.RealClientName(m.inputs[ClientField].Value()); got != "Globex" {
+		t.Errorf("Client saved from the form = %q, want Globex", got)
+	}
+
+	for typed, want := range map[string]string{"": "Client A", "client b": "Client B", "Ac": ""} {
+		m.inputs[ClientField].SetValue(typed)
+		m.updateAutocompleteSuggestion()
+		if m.currentSuggestion != want {
+			t.Errorf("Suggestion for %q = %q, want %q", typed, m.currentSuggestion, want)
+		}
+	}
+}
@@ -0,0 +1,39 @@
+package ui
+
+import (
+	"errors"
+	"fmt"
+	"time"
+	"timesheet/internal/config"
+	"timesheet/internal/datalayer"
+	"timesheet/internal/db"
+)
+
+// fillHolidays imports the configured holiday calendar for the year and
+// records holiday hours on the month's holidays that have nothing logged.
+// It returns an action that restores what the filled dates held before.
+func fillHolidays(year int, month time.Month) (db.BulkResult, UndoAction, error) {
+	source := config.GetHolidaySource()
+	if source == "" {
+		return db.BulkResult{}, UndoAction{}, errors.New("no holiday calendar; set holidaySource in the config")
+	}
+	holidays, err := db.LoadHolidaySource(source, year)
+	if err != nil {
+		return db.BulkResult{}, UndoAction{}, err
+	}
+	if _, err := db.ImportHolidays(holidays); err != nil {
+		return db.BulkResult{}, UndoAction{}, err
+	}
+
+	dl := datalayer.GetDataLayer()
+	planned, err := db.PlanHolidayFillForMonth(dl, year, month, config.GetWorkSchedule())
+	if err != nil {
+		return db.BulkResult{}, UndoAction{}, err
+	}
+	undo, err := restoreDatesUndo(fmt.Sprintf("holiday fill of %d day(s)", len(planned)), planned)
+	if err != nil {
+		return db.BulkResult{}, UndoAction{}, err
+	}
+	result, err := db.BulkSaveTimesheetEntries(dl, planned, db.OverwriteMerge)
+	return result, undo, err
+}
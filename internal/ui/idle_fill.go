@@ -0,0 +1,185 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"timesheet/internal/config"
+	"timesheet/internal/datalayer"
+	"timesheet/internal/db"
+	"timesheet/internal/utils"
+
+	"github.com/charmbracelet/bubbles/key"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// IdleFillKeyMap defines the keys of the idle fill preview
+type IdleFillKeyMap struct {
+	Up     key.Binding
+	Down   key.Binding
+	Toggle key.Binding
+	Enter  key.Binding
+	Escape key.Binding
+}
+
+// DefaultIdleFillKeyMap returns the default keys of the idle fill preview
+func DefaultIdleFillKeyMap() IdleFillKeyMap {
+	return IdleFillKeyMap{
+		Up: key.NewBinding(
+			key.WithKeys("up", "k"),
+			key.WithHelp("↑/k", "move up"),
+		),
+		Down: key.NewBinding(
+			key.WithKeys("down", "j"),
+			key.WithHelp("↓/j", "move down"),
+		),
+		Toggle: key.NewBinding(
+			key.WithKeys(" ", "x"),
+			key.WithHelp("space", "select/deselect day"),
+		),
+		Enter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "fill selected days"),
+		),
+		Escape: key.NewBinding(
+			key.WithKeys("esc", "q"),
+			key.WithHelp("esc", "cancel"),
+		),
+	}
+}
+
+// IdleFillModalModel previews the days idle auto-fill would record and
+// lets the user deselect days before anything is written
+type IdleFillModalModel struct {
+	title    string
+	entries  []db.TimesheetEntry
+	selected []bool
+	cursor   int
+	keys     IdleFillKeyMap
+}
+
+// IdleFillConfirmedMsg is sent with the entries the user kept selected
+type IdleFillConfirmedMsg struct {
+	Entries []db.TimesheetEntry
+}
+
+// IdleFillCancelledMsg is sent when the preview is closed without filling
+type IdleFillCancelledMsg struct{}
+
+// NewIdleFillModalModel creates a preview of planned idle entries, all
+// selected
+func NewIdleFillModalModel(title string, entries []db.TimesheetEntry) *IdleFillModalModel {
+	selected := make([]bool, len(entries))
+	for i := range selected {
+		selected[i] = true
+	}
+	return &IdleFillModalModel{
+		title:    title,
+		entries:  entries,
+		selected: selected,
+		keys:     DefaultIdleFillKeyMap(),
+	}
+}
+
+// planIdleFill opens the preview for the empty working days of a month up
+// to yesterday. It returns nil and a message when there's nothing to show.
+func planIdleFill(year int, month time.Month) (*IdleFillModalModel, string) {
+	settings := config.GetIdleAutoFill()
+	if !settings.Enabled {
+		return nil, "Idle auto-fill is disabled; enable idleAutoFill in the config"
+	}
+
+	// Today may still get logged, so only days before it are filled
+	through := time.Now().AddDate(0, 0, -1)
+	planned, err := db.PlanIdleFillForMonth(datalayer.GetDataLayer(), year, month, config.GetWorkSchedule(), settings.Hours, through)
+	if err != nil {
+		return nil, fmt.Sprintf("Error: %v", err)
+	}
+	if len(planned) == 0 {
+		return nil, fmt.Sprintf("No empty working days to fill in %s %d", month, year)
+	}
+	return NewIdleFillModalModel(fmt.Sprintf("Fill idle days in %s %d", month, year), planned), ""
+}
+
+// Selected returns the entries that are still selected
+func (m *IdleFillModalModel) Selected() []db.TimesheetEntry {
+	var entries []db.TimesheetEntry
+	for i, entry := range m.entries {
+		if m.selected[i] {
+			entries = append(entries, entry)
+		}
+	}
+	return entries
+}
+
+func (m *IdleFillModalModel) Update(msg tea.Msg) (*IdleFillModalModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.keys.Escape):
+		return m, func() tea.Msg { return IdleFillCancelledMsg{} }
+	case key.Matches(keyMsg, m.keys.Up):
+		if m.cursor > 0 {
+			m.cursor--
+		}
+	case key.Matches(keyMsg, m.keys.Down):
+		if m.cursor < len(m.entries)-1 {
+			m.cursor++
+		}
+	case key.Matches(keyMsg, m.keys.Toggle):
+		m.selected[m.cursor] = !m.selected[m.cursor]
+	case key.Matches(keyMsg, m.keys.Enter):
+		entries := m.Selected()
+		return m, func() tea.Msg { return IdleFillConfirmedMsg{Entries: entries} }
+	}
+	return m, nil
+}
+
+func (m *IdleFillModalModel) View() string {
+	var rows []string
+	rows = append(rows, lipgloss.NewStyle().Bold(true).Render(m.title))
+	rows = append(rows, "")
+
+	for i, entry := range m.entries {
+		check := "[ ]"
+		if m.selected[i] {
+			check = "[x]"
+		}
+		day, _ := time.Parse("2006-01-02", entry.Date)
+		row := fmt.Sprintf("%s %s %-9s %3sh idle", check, entry.Date, day.Weekday(), utils.FormatHours(entry.Idle_hours))
+		if i == m.cursor {
+			row = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("229")).
+				Background(lipgloss.Color("57")).
+				Render(row)
+		}
+		rows = append(rows, row)
+	}
+
+	rows = append(rows, "")
+	rows = append(rows, fmt.Sprintf("%d of %d days selected", len(m.Selected()), len(m.entries)))
+	rows = append(rows, lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Render("↑/↓: Select • Space: Toggle • Enter: Fill • Esc: Cancel"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(1, 2).
+		Render(strings.Join(rows, "\n"))
+}
+
+// saveIdleFill records the confirmed idle entries and returns an action that
+// restores what the dates held before
+func saveIdleFill(entries []db.TimesheetEntry) (db.BulkResult, UndoAction, error) {
+	undo, err := restoreDatesUndo(fmt.Sprintf("idle fill of %d day(s)", len(entries)), entries)
+	if err != nil {
+		return db.BulkResult{}, UndoAction{}, err
+	}
+	result, err := db.SaveIdleFill(datalayer.GetDataLayer(), entries)
+	return result, undo, err
+}
@@ -0,0 +1,34 @@
+package ui
+
+import (
+	"testing"
+	"timesheet/internal/db"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestIdleFillModalDeselect(t *testing.T) {
+	modal := NewIdleFillModalModel("Fill idle days", []db.TimesheetEntry{
+		{Date: "2024-06-03", Idle_hours: 8},
+		{Date: "2024-06-04", Idle_hours: 8},
+		{Date: "2024-06-05", Idle_hours: 8},
+	})
+
+	// Deselect the second day
+	modal, _ = modal.Update(tea.KeyMsg{Type: tea.KeyDown})
+	modal, _ = modal.Update(tea.KeyMsg{Type: tea.KeySpace, Runes: []rune{' '}})
+
+	_, cmd := modal.Update(tea.KeyMsg{Type: tea.KeyEnter})
+	confirmed, ok := cmd().(IdleFillConfirmedMsg)
+	if !ok {
+		t.Fatalf("Expected IdleFillConfirmedMsg on enter, got %T", cmd())
+	}
+	if len(confirmed.Entries) != 2 || confirmed.Entries[0].Date != "2024-06-03" || confirmed.Entries[1].Date != "2024-06-05" {
+		t.Errorf("Expected 2024-06-03 and 2024-06-05 to stay selected, got %+v", confirmed.Entries)
+	}
+
+	_, cmd = modal.Update(tea.KeyMsg{Type: tea.KeyEsc})
+	if _, ok := cmd().(IdleFillCancelledMsg); !ok {
+		t.Errorf("Expected IdleFillCancelledMsg on esc, got %T", cmd())
+	}
+}
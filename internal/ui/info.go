@@ -2,10 +2,14 @@ package ui
 
 import (
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
 	"timesheet/internal/config"
 	"timesheet/internal/datalayer"
 	"timesheet/internal/db"
+	"timesheet/internal/utils"
+	"timesheet/internal/workschedule"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
@@ -101,6 +105,16 @@ func (k InfoKeyMap) FullHelp() [][]key.Binding {
 	}
 }
 
+// infoTableHeights are the heights of the training, vacation, training
+// budget and reconciliation tables when the terminal is tall enough
+var infoTableHeights = [4]int{8, 8, 8, 6}
+
+// infoChromeHeight is the number of lines the info view renders besides its
+// tables: the title, the section headings, borders and blank lines, the
+// sick and idle table (which always has two rows), the monthly bars and the
+// help line
+const infoChromeHeight = 44
+
 // InfoModel represents the combined info view (Training, Vacation, Training Budget)
 type InfoModel struct {
 	// Training table
@@ -113,19 +127,33 @@ type InfoModel struct {
 	vacationYearlyTarget int
 	vacationCurrentYear  int
 	vacationEntries      []db.TimesheetEntry
-	vacationTotalHours   int
-	vacationRemaining    int
+	vacationTotalHours   float64
+	vacationRemaining    float64
+
+	// Sick and idle hours against their optional yearly targets
+	sickIdleTable    table.Model
+	sickYearlyTarget int
+	idleYearlyTarget int
 
 	// Training Budget table (only this one can be selected)
 	trainingBudgetTable       table.Model
 	trainingBudgetCurrentYear int
 
+	// Monthly target vs actual client hours (index 0 = January)
+	monthlyActual [12]float64
+	monthlyTarget [12]int
+
+	// Training hours logged vs training budget, discrepancies only
+	reconciliationTable table.Model
+
 	// Common fields
 	currentYear int
 	keys        InfoKeyMap
 	help        help.Model
 	showHelp    bool
 	ready       bool
+	width       int // Space the view has, 0 until the terminal size is known
+	height      int
 
 	// Data loading tracking
 	dataLoadedFlags map[string]bool
@@ -175,7 +203,7 @@ func InitialInfoModel() InfoModel {
 	trainingTable := table.New(
 		table.WithColumns(trainingColumns),
 		table.WithFocused(false), // Not selectable
-		table.WithHeight(8),
+		table.WithHeight(infoTableHeights[0]),
 	)
 
 	// Create vacation table
@@ -186,19 +214,47 @@ func InitialInfoModel() InfoModel {
 	vacationTable := table.New(
 		table.WithColumns(vacationColumns),
 		table.WithFocused(false), // Not selectable
-		table.WithHeight(8),
+		table.WithHeight(infoTableHeights[1]),
+	)
+
+	// Create sick and idle table
+	sickIdleColumns := []table.Column{
+		{Title: "Category", Width: 10},
+		{Title: "Used", Width: 8},
+		{Title: "Target", Width: 8},
+		{Title: "Remaining", Width: 10},
+	}
+	sickIdleTable := table.New(
+		table.WithColumns(sickIdleColumns),
+		table.WithFocused(false), // Not selectable
+		table.WithHeight(2),
 	)
 
 	// Create training budget table
 	trainingBudgetColumns := []table.Column{
 		{Title: "Date", Width: 12},
-		{Title: "Training", Width: 34},
+		{Title: "Training", Width: 26},
 		{Title: "Cost (€)", Width: 16},
+		{Title: "Receipt", Width: 8},
 	}
 	trainingBudgetTable := table.New(
 		table.WithColumns(trainingBudgetColumns),
 		table.WithFocused(true), // Only this one is selectable
-		table.WithHeight(8),
+		table.WithHeight(infoTableHeights[2]),
+	)
+
+	// Create training reconciliation table
+	reconciliationColumns := []table.Column{
+		{Title: "Date", Width: 12},
+		{Title: "Logged", Width: 8},
+		{Title: "Budget", Width: 8},
+		{Title: "Status", Width: 12},
+		{Title: "Training", Width: 24},
+	}
+	reconciliationTable := table.New(
+		table.WithColumns(reconciliationColumns),
+		table.WithFocused(false), // Not selectable
+		table.WithHeight(infoTableHeights[3]),
 	)
 
 	// Set styles for all tables
@@ -217,14 +273,20 @@ func InitialInfoModel() InfoModel {
 
 	trainingTable.SetStyles(tableStyles)
 	vacationTable.SetStyles(tableStyles)
+	sickIdleTable.SetStyles(tableStyles)
 	trainingBudgetTable.SetStyles(tableStyles)
+	reconciliationTable.SetStyles(tableStyles)
 
 	return InfoModel{
+		reconciliationTable:       reconciliationTable,
 		trainingTable:             trainingTable,
 		vacationTable:             vacationTable,
+		sickIdleTable:             sickIdleTable,
 		trainingBudgetTable:       trainingBudgetTable,
 		trainingYearlyTarget:      configFile.TrainingHours.YearlyTarget,
 		vacationYearlyTarget:      configFile.VacationHours.YearlyTarget,
+		sickYearlyTarget:          configFile.SickTarget,
+		idleYearlyTarget:          configFile.IdleTarget,
 		trainingCurrentYear:       currentYear,
 		vacationCurrentYear:       currentYear,
 		trainingBudgetCurrentYear: currentYear,
@@ -241,7 +303,10 @@ func (m *InfoModel) Init() tea.Cmd {
 	return tea.Batch(
 		m.loadTrainingData,
 		m.loadVacationData,
+		m.loadSickIdleData,
 		m.loadTrainingBudgetData,
+		m.loadMonthlyData,
+		m.loadReconciliationData,
 	)
 }
 
@@ -249,6 +314,10 @@ func (m *InfoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+		return m, nil
+
 	case ChangeInfoYearMsg:
 		// Update all years
 		m.trainingCurrentYear = msg.Year
@@ -261,7 +330,10 @@ func (m *InfoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, tea.Batch(
 			m.loadTrainingData,
 			m.loadVacationData,
+			m.loadSickIdleData,
 			m.loadTrainingBudgetData,
+			m.loadMonthlyData,
+			m.loadReconciliationData,
 		)
 
 	case trainingDataLoadedMsg:
@@ -283,6 +355,14 @@ func (m *InfoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.ready = true
 		}
 		return m, nil
+	case sickIdleDataLoadedMsg:
+		// Sick and idle totals loaded
+		m.sickIdleTable.SetRows(msg.rows)
+		m.dataLoadedFlags["sickIdle"] = true
+		if m.checkAllDataLoaded() {
+			m.ready = true
+		}
+		return m, nil
 	case trainingBudgetDataLoadedMsg:
 		// Training budget data loaded
 		m.trainingBudgetTable.SetRows(msg.rows)
@@ -300,6 +380,23 @@ func (m *InfoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			m.ready = true
 		}
 		return m, nil
+	case monthlyDataLoadedMsg:
+		// Monthly target vs actual data loaded
+		m.monthlyActual = msg.actual
+		m.monthlyTarget = msg.target
+		m.dataLoadedFlags["monthly"] = true
+		if m.checkAllDataLoaded() {
+			m.ready = true
+		}
+		return m, nil
+	case reconciliationDataLoadedMsg:
+		// Training reconciliation data loaded
+		m.reconciliationTable.SetRows(msg.rows)
+		m.dataLoadedFlags["reconciliation"] = true
+		if m.checkAllDataLoaded() {
+			m.ready = true
+		}
+		return m, nil
 
 	case tea.KeyMsg:
 		switch {
@@ -326,6 +423,30 @@ func (m *InfoModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// setSize fits the help to the width and shrinks the tables in proportion
+// when the view is too short to show them at their full height
+func (m *InfoModel) setSize(width, height int) {
+	if width == m.width && height == m.height {
+		return
+	}
+	m.width, m.height = width, height
+	m.help.Width = width
+
+	total := 0
+	for _, h := range infoTableHeights {
+		total += h
+	}
+	available := height - infoChromeHeight
+	tables := []*table.Model{&m.trainingTable, &m.vacationTable, &m.trainingBudgetTable, &m.reconciliationTable}
+	for i, t := range tables {
+		h := infoTableHeights[i]
+		if available < total {
+			h = h * available / total
+		}
+		t.SetHeight(max(h, minTableHeight))
+	}
+}
+
 func (m *InfoModel) View() string {
 	if !m.ready {
 		return "Loading info data..."
@@ -345,10 +466,22 @@ func (m *InfoModel) View() string {
 	s += lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Render("Vacation") + "\n"
 	s += baseStyle.Render(m.vacationTable.View()) + "\n\n"
 
+	// Sick and idle section
+	s += lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Render("Sick & Idle") + "\n"
+	s += baseStyle.Render(m.sickIdleTable.View()) + "\n\n"
+
 	// Training Budget section
 	s += lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Render("Training Budget") + "\n"
 	s += baseStyle.Render(m.trainingBudgetTable.View()) + "\n\n"
 
+	// Monthly target vs actual section
+	s += lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Render("Client Hours vs Target") + "\n"
+	s += baseStyle.Render(renderMonthlyBars(m.monthlyActual, m.monthlyTarget, monthlyBarWidth)) + "\n\n"
+
+	// Training hours vs training budget section
+	s += lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("205")).Render("Training Hours vs Budget") + "\n"
+	s += baseStyle.Render(m.reconciliationTable.View()) + "\n\n"
+
 	// Help text
 	if m.showHelp {
 		s += m.help.FullHelpView(m.keys.FullHelp())
@@ -366,7 +499,10 @@ type SwitchToTrainingBudgetFormMsg struct{}
 func (m *InfoModel) checkAllDataLoaded() bool {
 	return m.dataLoadedFlags["training"] &&
 		m.dataLoadedFlags["vacation"] &&
-		m.dataLoadedFlags["trainingBudget"]
+		m.dataLoadedFlags["sickIdle"] &&
+		m.dataLoadedFlags["trainingBudget"] &&
+		m.dataLoadedFlags["monthly"] &&
+		m.dataLoadedFlags["reconciliation"]
 }
 
 // loadTrainingData loads training data for the current year
@@ -381,11 +517,11 @@ func (m *InfoModel) loadTrainingData() tea.Msg {
 
 	// Convert entries to table rows
 	var rows []table.Row
-	var totalHours int
+	var totalHours float64
 	for _, entry := range entries {
 		rows = append(rows, table.Row{
 			entry.Date,
-			fmt.Sprintf("%d", entry.Training_hours),
+			utils.FormatHours(entry.Training_hours),
 		})
 		totalHours += entry.Training_hours
 	}
@@ -393,7 +529,7 @@ func (m *InfoModel) loadTrainingData() tea.Msg {
 	// Add total row
 	rows = append(rows, table.Row{
 		"Total",
-		fmt.Sprintf("%d/%d", totalHours, m.trainingYearlyTarget),
+		fmt.Sprintf("%s/%d", utils.FormatHours(totalHours), m.trainingYearlyTarget),
 	})
 
 	return trainingDataLoadedMsg{rows: rows}
@@ -416,11 +552,11 @@ func (m *InfoModel) loadVacationData() tea.Msg {
 
 	// Convert entries to table rows
 	var rows []table.Row
-	var totalHours int
+	var totalHours float64
 	for _, entry := range entries {
 		rows = append(rows, table.Row{
 			entry.Date,
-			fmt.Sprintf("%d", entry.Vacation_hours),
+			utils.FormatHours(entry.Vacation_hours),
 		})
 		totalHours += entry.Vacation_hours
 	}
@@ -428,15 +564,37 @@ func (m *InfoModel) loadVacationData() tea.Msg {
 	// Add total row
 	rows = append(rows, table.Row{
 		"Total",
-		fmt.Sprintf("%d/%d", totalHours, m.vacationYearlyTarget),
+		fmt.Sprintf("%s/%d", utils.FormatHours(totalHours), m.vacationYearlyTarget),
 	})
 
 	return vacationDataLoadedMsg{
 		rows:       rows,
 		entries:    nil,
 		totalHours: totalHours,
-		remaining:  m.vacationYearlyTarget - totalHours,
+		remaining:  float64(m.vacationYearlyTarget) - totalHours,
+	}
+}
+
+// loadSickIdleData totals the sick and idle hours of the current year
+func (m *InfoModel) loadSickIdleData() tea.Msg {
+	dataLayer := datalayer.GetDataLayer()
+	// If a query fails, show no hours rather than keep the view loading
+	sick, _ := dataLayer.GetSickHoursForYear(m.currentYear)
+	idle, _ := dataLayer.GetIdleHoursForYear(m.currentYear)
+
+	return sickIdleDataLoadedMsg{rows: []table.Row{
+		sickIdleRow("Sick", sick, m.sickYearlyTarget),
+		sickIdleRow("Idle", idle, m.idleYearlyTarget),
+	}}
+}
+
+// sickIdleRow shows the hours used of a category with its target and what's
+// left of it, or dashes when no target is set
+func sickIdleRow(category string, used float64, target int) table.Row {
+	if target <= 0 {
+		return table.Row{category, utils.FormatHours(used), "-", "-"}
 	}
+	return table.Row{category, utils.FormatHours(used), strconv.Itoa(target), utils.FormatHours(float64(target) - used)}
 }
 
 // loadTrainingBudgetData loads training budget data for the current year
@@ -460,6 +618,7 @@ func (m *InfoModel) loadTrainingBudgetData() tea.Msg {
 			entry.Date,
 			entry.Training_name,
 			fmt.Sprintf("%.2f", entry.Cost_without_vat),
+			receiptIndicator(entry),
 		})
 		totalCost += entry.Cost_without_vat
 	}
@@ -469,6 +628,7 @@ func (m *InfoModel) loadTrainingBudgetData() tea.Msg {
 		"Total",
 		"",
 		fmt.Sprintf("%.2f", totalCost),
+		"",
 	})
 
 	return trainingBudgetDataLoadedMsg{
@@ -477,6 +637,126 @@ func (m *InfoModel) loadTrainingBudgetData() tea.Msg {
 	}
 }
 
+// receiptIndicator marks training budget entries that have a receipt attached
+func receiptIndicator(entry db.TrainingBudgetEntry) string {
+	if entry.Receipt_path == "" {
+		return ""
+	}
+	return "✓"
+}
+
+// loadMonthlyData sums client hours per month for the current year and pairs
+// them with the expected hours from the configured work schedule
+func (m *InfoModel) loadMonthlyData() tea.Msg {
+	var msg monthlyDataLoadedMsg
+
+	schedule := config.GetWorkSchedule()
+	for i := 0; i < 12; i++ {
+		msg.target[i] = workschedule.ExpectedHoursForMonth(m.currentYear, time.Month(i+1), schedule)
+	}
+
+	dataLayer := datalayer.GetDataLayer()
+	entries, err := dataLayer.GetAllTimesheetEntries(m.currentYear, 0)
+	if err != nil {
+		// If database query fails, still show the targets so the view becomes ready
+		return msg
+	}
+
+	for _, entry := range entries {
+		if len(entry.Date) < 7 {
+			continue
+		}
+		month, err := strconv.Atoi(entry.Date[5:7])
+		if err != nil || month < 1 || month > 12 {
+			continue
+		}
+		msg.actual[month-1] += entry.Client_hours
+	}
+
+	return msg
+}
+
+// loadReconciliationData cross-checks training hours logged on the timesheet
+// with the training budget and lists the dates that don't match
+func (m *InfoModel) loadReconciliationData() tea.Msg {
+	dataLayer := datalayer.GetDataLayer()
+	trainingEntries, err := dataLayer.GetTrainingEntriesForYear(m.currentYear)
+	if err != nil {
+		// If database query fails, return empty data instead of error
+		return reconciliationDataLoadedMsg{rows: []table.Row{}}
+	}
+	budgetEntries, err := dataLayer.GetTrainingBudgetEntriesForYear(m.currentYear)
+	if err != nil {
+		return reconciliationDataLoadedMsg{rows: []table.Row{}}
+	}
+
+	report := db.ReconcileTraining(m.currentYear, trainingEntries, budgetEntries)
+
+	var rows []table.Row
+	for _, row := range report.Discrepancies() {
+		rows = append(rows, table.Row{
+			row.Date,
+			utils.FormatHours(row.LoggedHours),
+			utils.FormatHours(row.BudgetedHours),
+			row.Status,
+			row.TrainingNames,
+		})
+	}
+
+	// Add total row
+	rows = append(rows, table.Row{
+		"Total",
+		utils.FormatHours(report.TotalLoggedHours),
+		utils.FormatHours(report.TotalBudgetedHours),
+		"",
+		"",
+	})
+
+	return reconciliationDataLoadedMsg{rows: rows}
+}
+
+// monthlyBarWidth is the width (in cells) of the longest bar in the chart
+const monthlyBarWidth = 40
+
+// renderMonthlyBars draws one horizontal bar per month. Bars share a single
+// scale (the largest actual or target value) so months are comparable; the
+// filled part is the actual hours and the shaded remainder runs up to target.
+func renderMonthlyBars(actual [12]float64, target [12]int, width int) string {
+	scale := 0.0
+	for i := 0; i < 12; i++ {
+		scale = max(scale, actual[i], float64(target[i]))
+	}
+
+	metStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("78"))
+	shortStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("214"))
+	remainderStyle := lipgloss.NewStyle().Foreground(lipgloss.Color("240"))
+
+	lines := make([]string, 0, 12)
+	for i := 0; i < 12; i++ {
+		filled, goal := 0, 0
+		if scale > 0 {
+			filled = int(actual[i] * float64(width) / scale)
+			goal = int(float64(target[i]*width) / scale)
+		}
+
+		barStyle := metStyle
+		if actual[i] < float64(target[i]) {
+			barStyle = shortStyle
+		}
+
+		bar := barStyle.Render(strings.Repeat("█", filled))
+		if goal > filled {
+			bar += remainderStyle.Render(strings.Repeat("░", goal-filled))
+		}
+		padding := width - max(filled, goal)
+
+		lines = append(lines, fmt.Sprintf("%s %s%s %4s/%d",
+			time.Month(i + 1).String()[:3], bar, strings.Repeat(" ", padding), utils.FormatHours(actual[i]), target[i]))
+	}
+
+	return strings.Join(lines, "\n")
+}
+
 // Messages for data loading
 type trainingDataLoadedMsg struct {
 	rows []table.Row
@@ -484,10 +764,20 @@ type trainingDataLoadedMsg struct {
 type vacationDataLoadedMsg struct {
 	rows       []table.Row
 	entries    []db.TimesheetEntry
-	totalHours int
-	remaining  int
+	totalHours float64
+	remaining  float64
+}
+type sickIdleDataLoadedMsg struct {
+	rows []table.Row
 }
 type trainingBudgetDataLoadedMsg struct {
 	rows    []table.Row
 	entries []db.TrainingBudgetEntry
 }
+type monthlyDataLoadedMsg struct {
+	actual [12]float64
+	target [12]int
+}
+type reconciliationDataLoadedMsg struct {
+	rows []table.Row
+}
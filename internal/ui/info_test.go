@@ -0,0 +1,25 @@
+package ui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/charmbracelet/bubbles/table"
+)
+
+func TestSickIdleRow(t *testing.T) {
+	tests := []struct {
+		used   float64
+		target int
+		want   table.Row
+	}{
+		{12.5, 40, table.Row{"Sick", "12.5", "40", "27.5"}},
+		{48, 40, table.Row{"Sick", "48", "40", "-8"}},
+		{16, 0, table.Row{"Sick", "16", "-", "-"}},
+	}
+	for _, tt := range tests {
+		if got := sickIdleRow("Sick", tt.used, tt.target); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("sickIdleRow(%g, %d) = %v, want %v", tt.used, tt.target, got, tt.want)
+		}
+	}
+}
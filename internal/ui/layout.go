@@ -0,0 +1,18 @@
+package ui
+
+// appHeaderHeight is the number of lines the tab row and the status bar take
+// above the active view; both are rendered with a border
+const appHeaderHeight = 6
+
+// minTableHeight keeps a table usable (header plus a few rows) in a terminal
+// that's too short to fit everything
+const minTableHeight = 5
+
+// fitTableHeight returns the height a table can take in a view of the given
+// height once the lines the view renders around it are reserved
+func fitTableHeight(height, reserved int) int {
+	if h := height - reserved; h > minTableHeight {
+		return h
+	}
+	return minTableHeight
+}
@@ -5,6 +5,7 @@ import (
 	"time"
 	"timesheet/internal/config"
 	"timesheet/internal/datalayer"
+	"timesheet/internal/utils"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
@@ -80,8 +81,8 @@ func (k OverviewKeyMap) FullHelp() [][]key.Binding {
 
 // OverviewModel represents the overview view
 type OverviewModel struct {
-	trainingHoursLeft int
-	vacationHoursLeft int
+	trainingHoursLeft float64
+	vacationHoursLeft float64
 	currentYear       int
 	keys              OverviewKeyMap
 	help              help.Model
@@ -120,17 +121,17 @@ func InitialOverviewModel() OverviewModel {
 	// Calculate training hours left
 	dataLayer := datalayer.GetDataLayer()
 	trainingEntries, err := dataLayer.GetTrainingEntriesForYear(currentYear)
-	var totalTrainingHours int
+	var totalTrainingHours float64
 	if err == nil {
 		for _, entry := range trainingEntries {
 			totalTrainingHours += entry.Training_hours
 		}
 	}
-	trainingHoursLeft := configFile.TrainingHours.YearlyTarget - totalTrainingHours
+	trainingHoursLeft := float64(configFile.TrainingHours.YearlyTarget) - totalTrainingHours
 
 	// Calculate vacation hours left (includes carryover)
 	vacationSummary, err := dataLayer.GetVacationSummaryForYear(currentYear)
-	var vacationHoursLeft int
+	var vacationHoursLeft float64
 	if err == nil {
 		vacationHoursLeft = vacationSummary.RemainingTotal
 	} else {
@@ -168,13 +169,13 @@ func (m OverviewModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Calculate training hours left
 		dataLayer := datalayer.GetDataLayer()
 		trainingEntries, err := dataLayer.GetTrainingEntriesForYear(msg.Year)
-		var totalTrainingHours int
+		var totalTrainingHours float64
 		if err == nil {
 			for _, entry := range trainingEntries {
 				totalTrainingHours += entry.Training_hours
 			}
 		}
-		trainingHoursLeft := configFile.TrainingHours.YearlyTarget - totalTrainingHours
+		trainingHoursLeft := float64(configFile.TrainingHours.YearlyTarget) - totalTrainingHours
 		m.trainingHoursLeft = trainingHoursLeft
 
 		// Calculate vacation hours left (includes carryover)
@@ -226,9 +227,9 @@ func (m OverviewModel) View() string {
 			fmt.Sprintf(
 				"%s\n%s\n\n%s\n%s",
 				lipgloss.NewStyle().Foreground(lipgloss.Color("86")).Render("Training Hours Remaining:"),
-				lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("78")).Render(fmt.Sprintf("  %d hours", m.trainingHoursLeft)),
+				lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("78")).Render(fmt.Sprintf("  %s hours", utils.FormatHours(m.trainingHoursLeft))),
 				lipgloss.NewStyle().Foreground(lipgloss.Color("86")).Render("Vacation Hours Remaining:"),
-				lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("78")).Render(fmt.Sprintf("  %d hours", m.vacationHoursLeft)),
+				lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("78")).Render(fmt.Sprintf("  %s hours", utils.FormatHours(m.vacationHoursLeft))),
 			),
 		)
 
@@ -0,0 +1,205 @@
+package ui
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"timesheet/internal/datalayer"
+	"timesheet/internal/db"
+	"timesheet/internal/utils"
+
+	"github.com/charmbracelet/bubbles/key"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/charmbracelet/lipgloss"
+)
+
+// rangeFillKinds are the hour categories a range can be filled with
+var rangeFillKinds = []string{"vacation", "sick", "idle", "training", "holiday"}
+
+// RangeFillKeyMap defines the keys of the range fill template
+type RangeFillKeyMap struct {
+	NextKind key.Binding
+	PrevKind key.Binding
+	Enter    key.Binding
+	Escape   key.Binding
+}
+
+// DefaultRangeFillKeyMap returns the default keys of the range fill template
+func DefaultRangeFillKeyMap() RangeFillKeyMap {
+	return RangeFillKeyMap{
+		NextKind: key.NewBinding(
+			key.WithKeys("tab", "right"),
+			key.WithHelp("tab/→", "next kind"),
+		),
+		PrevKind: key.NewBinding(
+			key.WithKeys("shift+tab", "left"),
+			key.WithHelp("shift+tab/←", "previous kind"),
+		),
+		Enter: key.NewBinding(
+			key.WithKeys("enter"),
+			key.WithHelp("enter", "fill the range"),
+		),
+		Escape: key.NewBinding(
+			key.WithKeys("esc"),
+			key.WithHelp("esc", "cancel"),
+		),
+	}
+}
+
+// RangeFillModalModel asks for the entry every day of a range gets: an hour
+// category and the hours per day
+type RangeFillModalModel struct {
+	from        string
+	to          string
+	weekendDays []time.Weekday
+	days        int // Days that will be filled
+	kind        int // Index in rangeFillKinds
+	hours       textinput.Model
+	err         string
+	keys        RangeFillKeyMap
+}
+
+// RangeFillConfirmedMsg is sent with the planned entries of the range
+type RangeFillConfirmedMsg struct {
+	Entries []db.TimesheetEntry
+	Label   string // e.g. "8h vacation"
+}
+
+// RangeFillCancelledMsg is sent when the template is closed without filling
+type RangeFillCancelledMsg struct{}
+
+// NewRangeFillModalModel creates the template for filling from through to,
+// skipping weekendDays, with hours per day as the default
+func NewRangeFillModalModel(from, to string, weekendDays []time.Weekday, hours int) (*RangeFillModalModel, error) {
+	planned, err := db.PlanRangeFill(from, to, db.TimesheetEntry{}, weekendDays)
+	if err != nil {
+		return nil, err
+	}
+	if to < from {
+		from, to = to, from
+	}
+
+	input := textinput.New()
+	input.Prompt = ""
+	input.CharLimit = 5
+	input.Width = 6
+	input.SetValue(strconv.Itoa(hours))
+	input.Focus()
+
+	return &RangeFillModalModel{
+		from:        from,
+		to:          to,
+		weekendDays: weekendDays,
+		days:        len(planned),
+		hours:       input,
+		keys:        DefaultRangeFillKeyMap(),
+	}, nil
+}
+
+// template returns the entry of one day of the range
+func (m *RangeFillModalModel) template() (db.TimesheetEntry, error) {
+	hours, err := strconv.ParseFloat(strings.TrimSpace(m.hours.Value()), 64)
+	if err != nil || hours <= 0 || hours > 24 {
+		return db.TimesheetEntry{}, fmt.Errorf("hours must be a number between 0 and 24")
+	}
+
+	entry := db.TimesheetEntry{Total_hours: hours}
+	switch rangeFillKinds[m.kind] {
+	case "vacation":
+		entry.Vacation_hours = hours
+	case "sick":
+		entry.Sick_hours = hours
+	case "idle":
+		entry.Idle_hours = hours
+	case "training":
+		entry.Training_hours = hours
+	case "holiday":
+		entry.Holiday_hours = hours
+	}
+	return entry, nil
+}
+
+func (m *RangeFillModalModel) Update(msg tea.Msg) (*RangeFillModalModel, tea.Cmd) {
+	keyMsg, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return m, nil
+	}
+
+	switch {
+	case key.Matches(keyMsg, m.keys.Escape):
+		return m, func() tea.Msg { return RangeFillCancelledMsg{} }
+	case key.Matches(keyMsg, m.keys.NextKind):
+		m.kind = (m.kind + 1) % len(rangeFillKinds)
+		return m, nil
+	case key.Matches(keyMsg, m.keys.PrevKind):
+		m.kind = (m.kind + len(rangeFillKinds) - 1) % len(rangeFillKinds)
+		return m, nil
+	case key.Matches(keyMsg, m.keys.Enter):
+		template, err := m.template()
+		if err != nil {
+			m.err = err.Error()
+			return m, nil
+		}
+		entries, err := db.PlanRangeFill(m.from, m.to, template, m.weekendDays)
+		if err != nil {
+			m.err = err.Error()
+			return m, nil
+		}
+		label := fmt.Sprintf("%sh %s", utils.FormatHours(template.Total_hours), rangeFillKinds[m.kind])
+		return m, func() tea.Msg { return RangeFillConfirmedMsg{Entries: entries, Label: label} }
+	}
+
+	var cmd tea.Cmd
+	m.hours, cmd = m.hours.Update(msg)
+	m.err = ""
+	return m, cmd
+}
+
+func (m *RangeFillModalModel) View() string {
+	var kinds []string
+	for i, kind := range rangeFillKinds {
+		if i == m.kind {
+			kind = lipgloss.NewStyle().
+				Foreground(lipgloss.Color("229")).
+				Background(lipgloss.Color("57")).
+				Render(" " + kind + " ")
+		} else {
+			kind = " " + kind + " "
+		}
+		kinds = append(kinds, kind)
+	}
+
+	rows := []string{
+		lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("Fill %s through %s", m.from, m.to)),
+		"",
+		fmt.Sprintf("%d day(s), weekends skipped", m.days),
+		"",
+		"Kind:  " + strings.Join(kinds, ""),
+		"Hours: " + m.hours.View(),
+	}
+	if m.err != "" {
+		rows = append(rows, "", lipgloss.NewStyle().Foreground(lipgloss.Color("196")).Render(m.err))
+	}
+	rows = append(rows, "", lipgloss.NewStyle().
+		Foreground(lipgloss.Color("240")).
+		Render("Tab/←/→: Kind • Enter: Fill • Esc: Cancel"))
+
+	return lipgloss.NewStyle().
+		Border(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(1, 2).
+		Render(strings.Join(rows, "\n"))
+}
+
+// saveRangeFill records the planned entries of a range and returns an action
+// that restores what the dates held before
+func saveRangeFill(entries []db.TimesheetEntry) (db.BulkResult, UndoAction, error) {
+	undo, err := restoreDatesUndo(fmt.Sprintf("range fill of %d day(s)", len(entries)), entries)
+	if err != nil {
+		return db.BulkResult{}, UndoAction{}, err
+	}
+	result, err := db.SaveRangeFill(datalayer.GetDataLayer(), entries)
+	return result, undo, err
+}
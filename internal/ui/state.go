@@ -4,11 +4,13 @@ import (
 	"encoding/json"
 	"os"
 	"path/filepath"
+	"time"
 )
 
 // AppState represents persisted application state
 type AppState struct {
-	ActiveTab string `json:"activeTab"`
+	ActiveTab      string `json:"activeTab"`
+	TimesheetMonth string `json:"timesheetMonth,omitempty"` // Last month shown in the timesheet, YYYY-MM
 }
 
 // getStatePath returns the path to the state file
@@ -61,6 +63,33 @@ func SaveAppState(state AppState) error {
 	return os.WriteFile(statePath, data, 0644)
 }
 
+// saveActiveTab persists the active tab, keeping the rest of the state
+func saveActiveTab(mode AppMode) {
+	state := LoadAppState()
+	state.ActiveTab = AppModeToString(mode)
+	SaveAppState(state)
+}
+
+// saveTimesheetMonth persists the month shown in the timesheet, keeping the
+// rest of the state
+func saveTimesheetMonth(year int, month time.Month) {
+	state := LoadAppState()
+	state.TimesheetMonth = time.Date(year, month, 1, 0, 0, 0, 0, time.Local).Format("2006-01")
+	SaveAppState(state)
+}
+
+// restoredTimesheetMonth returns the month the timesheet should open on: the
+// stored one, or the month of now when none is stored, it can't be parsed or
+// it lies after the month of now
+func restoredTimesheetMonth(stored string, now time.Time) (int, time.Month) {
+	month, err := time.ParseInLocation("2006-01", stored, now.Location())
+	current := time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location())
+	if err != nil || month.After(current) {
+		return now.Year(), now.Month()
+	}
+	return month.Year(), month.Month()
+}
+
 // AppModeToString converts AppMode to a string for persistence
 func AppModeToString(mode AppMode) string {
 	switch mode {
@@ -87,28 +116,39 @@ func AppModeToString(mode AppMode) string {
 	}
 }
 
-// StringToAppMode converts a string back to AppMode
+// StringToAppMode converts a string back to AppMode, falling back to the
+// timesheet for unknown names
 func StringToAppMode(s string) AppMode {
+	mode, ok := ParseAppMode(s)
+	if !ok {
+		return TimesheetMode
+	}
+	return mode
+}
+
+// ParseAppMode converts a view name, as written by AppModeToString, to its
+// AppMode and reports whether the name is known
+func ParseAppMode(s string) (AppMode, bool) {
 	switch s {
 	case "timesheet":
-		return TimesheetMode
+		return TimesheetMode, true
 	case "overview":
-		return OverviewMode
+		return OverviewMode, true
 	case "training":
-		return TrainingMode
+		return TrainingMode, true
 	case "training_budget":
-		return TrainingBudgetMode
+		return TrainingBudgetMode, true
 	case "vacation":
-		return VacationMode
+		return VacationMode, true
 	case "buffer":
-		return BufferMode
+		return BufferMode, true
 	case "clients":
-		return ClientsMode
+		return ClientsMode, true
 	case "earnings":
-		return EarningsMode
+		return EarningsMode, true
 	case "config":
-		return ConfigMode
+		return ConfigMode, true
 	default:
-		return TimesheetMode
+		return TimesheetMode, false
 	}
 }
@@ -0,0 +1,63 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseAppMode(t *testing.T) {
+	for _, mode := range []AppMode{TimesheetMode, OverviewMode, TrainingMode, TrainingBudgetMode, VacationMode, BufferMode, ClientsMode, EarningsMode, ConfigMode} {
+		name := AppModeToString(mode)
+		got, ok := ParseAppMode(name)
+		if !ok || got != mode {
+			t.Errorf("ParseAppMode(%q) = %v, %v, want %v, true", name, got, ok, mode)
+		}
+	}
+
+	if _, ok := ParseAppMode("info"); ok {
+		t.Error("ParseAppMode(info) should report an unknown view")
+	}
+	if got := StringToAppMode("info"); got != TimesheetMode {
+		t.Errorf("StringToAppMode(info) = %v, want the timesheet", got)
+	}
+}
+
+func TestRestoredTimesheetMonth(t *testing.T) {
+	now := time.Date(2024, time.March, 28, 10, 0, 0, 0, time.Local)
+	tests := []struct {
+		stored string
+		year   int
+		month  time.Month
+	}{
+		{"2024-02", 2024, time.February},
+		{"2023-12", 2023, time.December},
+		{"2024-03", 2024, time.March},
+		{"2024-04", 2024, time.March}, // Future months are clamped
+		{"2025-01", 2024, time.March},
+		{"", 2024, time.March},
+		{"March", 2024, time.March},
+	}
+	for _, tt := range tests {
+		year, month := restoredTimesheetMonth(tt.stored, now)
+		if year != tt.year || month != tt.month {
+			t.Errorf("restoredTimesheetMonth(%q) = %d-%02d, want %d-%02d", tt.stored, year, month, tt.year, tt.month)
+		}
+	}
+}
+
+func TestSaveTimesheetMonthKeepsActiveTab(t *testing.T) {
+	t.Setenv("HOME", t.TempDir())
+
+	saveActiveTab(EarningsMode)
+	saveTimesheetMonth(2024, time.February)
+
+	state := LoadAppState()
+	if state.ActiveTab != "earnings" || state.TimesheetMonth != "2024-02" {
+		t.Errorf("Unexpected state: %+v", state)
+	}
+
+	saveActiveTab(OverviewMode)
+	if state := LoadAppState(); state.TimesheetMonth != "2024-02" {
+		t.Errorf("Expected the month to survive a tab switch, got %+v", state)
+	}
+}
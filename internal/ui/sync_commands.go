@@ -27,13 +27,9 @@ func InitSyncServiceCmd() tea.Cmd {
 		sqliteDB := db.GetSQLiteDB()
 		if sqliteDB == nil {
 			// Try to connect to SQLite
-			dbPath := db.GetDBPath()
-			if err := db.Connect(dbPath); err != nil {
-				return syncInitResultMsg{enabled: false, err: "Failed to connect to SQLite: " + err.Error()}
-			}
-			// Initialize SQLite schema to ensure sync columns exist
-			if err := db.InitializeDatabase(dbPath); err != nil {
-				return syncInitResultMsg{enabled: false, err: "Failed to initialize SQLite: " + err.Error()}
+			// Opening also brings the schema up to date so sync columns exist
+			if _, err := db.OpenBackend(db.GetDBPath()); err != nil {
+				return syncInitResultMsg{enabled: false, err: "Failed to open SQLite: " + err.Error()}
 			}
 			sqliteDB = db.GetSQLiteDB()
 		}
@@ -46,12 +42,11 @@ func InitSyncServiceCmd() tea.Cmd {
 		postgresDB := db.GetPostgresDB()
 		if postgresDB == nil {
 			// Try to connect
-			if err := db.ConnectPostgres(postgresURL); err != nil {
-				return syncInitResultMsg{enabled: false, err: "Failed to connect to PostgreSQL: " + err.Error()}
+			if !db.IsPostgresDSN(postgresURL) {
+				return syncInitResultMsg{enabled: false, err: "PostgreSQL URL is not a PostgreSQL connection string"}
 			}
-			// Initialize PostgreSQL schema
-			if err := db.InitializePostgresDatabase(); err != nil {
-				return syncInitResultMsg{enabled: false, err: "Failed to initialize PostgreSQL: " + err.Error()}
+			if _, err := db.OpenBackend(postgresURL); err != nil {
+				return syncInitResultMsg{enabled: false, err: "Failed to open PostgreSQL: " + err.Error()}
 			}
 			postgresDB = db.GetPostgresDB()
 		}
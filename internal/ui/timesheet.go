@@ -28,45 +28,61 @@ package ui
 import (
 	"fmt"
 	"log"
-	"strconv"
+	"slices"
 	"strings"
 	"time"
 	"timesheet/internal/config"
 	"timesheet/internal/datalayer"
 	"timesheet/internal/db"
+	"timesheet/internal/document"
+	"timesheet/internal/email"
+	"timesheet/internal/exitcode"
+	printCSV "timesheet/internal/print-csv"
 	printExcel "timesheet/internal/print-excel"
 	printPDF "timesheet/internal/print-pdf"
+	"timesheet/internal/utils"
 	"timesheet/internal/workschedule"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
 	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
+	"github.com/rmhubbert/bubbletea-overlay"
 )
 
 // Key bindings
 type TimesheetKeyMap struct {
-	Up          key.Binding
-	Down        key.Binding
-	Left        key.Binding
-	Right       key.Binding
-	GotoToday   key.Binding
-	Help        key.Binding
-	Quit        key.Binding
-	Enter       key.Binding
-	PrevMonth   key.Binding
-	NextMonth   key.Binding
-	AddEntry    key.Binding
-	JumpUp      key.Binding
-	JumpDown    key.Binding
-	ClearEntry  key.Binding
-	YankEntry   key.Binding
-	MoveEntry   key.Binding
-	PasteEntry  key.Binding
-	Print       key.Binding
-	SendAsEmail key.Binding
-	ExportExcel key.Binding
+	Up           key.Binding
+	Down         key.Binding
+	Left         key.Binding
+	Right        key.Binding
+	GotoToday    key.Binding
+	Help         key.Binding
+	Quit         key.Binding
+	Enter        key.Binding
+	PrevMonth    key.Binding
+	NextMonth    key.Binding
+	AddEntry     key.Binding
+	JumpUp       key.Binding
+	JumpDown     key.Binding
+	ClearEntry   key.Binding
+	YankEntry    key.Binding
+	MoveEntry    key.Binding
+	PasteEntry   key.Binding
+	RepeatDay    key.Binding
+	RangeFill    key.Binding
+	Print        key.Binding
+	SendAsEmail  key.Binding
+	ExportExcel  key.Binding
+	Undo         key.Binding
+	VacationDay  key.Binding
+	SickDay      key.Binding
+	FillIdle     key.Binding
+	FillHolidays key.Binding
+	DeleteMonth  key.Binding
+	Filter       key.Binding
 }
 
 // Default keybindings for the timesheet view
@@ -126,6 +142,12 @@ func DefaultTimesheetKeyMap() TimesheetKeyMap {
 		PasteEntry: key.NewBinding(
 			key.WithKeys("p"),
 			key.WithHelp("p", "paste entry")),
+		RepeatDay: key.NewBinding(
+			key.WithKeys("."),
+			key.WithHelp(".", "repeat previous day")),
+		RangeFill: key.NewBinding(
+			key.WithKeys("R"),
+			key.WithHelp("R", "fill a range of days")),
 		Print: key.NewBinding(
 			key.WithKeys("P"),
 			key.WithHelp("P", "print timesheet")),
@@ -135,6 +157,27 @@ func DefaultTimesheetKeyMap() TimesheetKeyMap {
 		ExportExcel: key.NewBinding(
 			key.WithKeys("x"),
 			key.WithHelp("x", "export to Excel")),
+		Undo: key.NewBinding(
+			key.WithKeys("U", "ctrl+z"),
+			key.WithHelp("U/ctrl+z", "undo last change")),
+		VacationDay: key.NewBinding(
+			key.WithKeys("V"),
+			key.WithHelp("V", "full vacation day")),
+		SickDay: key.NewBinding(
+			key.WithKeys("K"),
+			key.WithHelp("K", "full sick day")),
+		FillIdle: key.NewBinding(
+			key.WithKeys("I"),
+			key.WithHelp("I", "fill idle days")),
+		FillHolidays: key.NewBinding(
+			key.WithKeys("H"),
+			key.WithHelp("H", "fill public holidays")),
+		DeleteMonth: key.NewBinding(
+			key.WithKeys("D"),
+			key.WithHelp("D", "delete whole month")),
+		Filter: key.NewBinding(
+			key.WithKeys("/"),
+			key.WithHelp("/", "filter by client")),
 	}
 }
 
@@ -169,10 +212,10 @@ func (k TimesheetKeyMap) ShortHelp() []key.Binding {
 // FullHelp returns keybindings for the expanded help view.
 func (k TimesheetKeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
-		{k.Up, k.Down, k.Left, k.Right, k.JumpUp, k.JumpDown},                            // first column
-		{k.PrevMonth, k.NextMonth},                                                       // second column - month navigation
-		{k.GotoToday, k.Enter, k.AddEntry, k.ClearEntry},                                 // third column
-		{k.YankEntry, k.MoveEntry, k.PasteEntry, k.Print, k.ExportExcel, k.SendAsEmail, k.Help, k.Quit}, // fourth column
+		{k.Up, k.Down, k.Left, k.Right, k.JumpUp, k.JumpDown}, // first column
+		{k.PrevMonth, k.NextMonth},                            // second column - month navigation
+		{k.GotoToday, k.Enter, k.AddEntry, k.ClearEntry, k.DeleteMonth, k.VacationDay, k.SickDay, k.RangeFill, k.FillIdle, k.FillHolidays, k.Undo}, // third column
+		{k.YankEntry, k.MoveEntry, k.PasteEntry, k.RepeatDay, k.Filter, k.Print, k.ExportExcel, k.SendAsEmail, k.Help, k.Quit},                     // fourth column
 		{
 			key.NewBinding(
 				key.WithKeys("<"),
@@ -194,27 +237,45 @@ func (k TimesheetKeyMap) FullHelp() [][]key.Binding {
 type YankedEntry struct {
 	Date          string
 	ClientName    string
-	ClientHours   int
-	TrainingHours int
-	VacationHours int
-	IdleHours     int
-	HolidayHours  int
-	SickHours     int
+	ClientHours   float64
+	TrainingHours float64
+	VacationHours float64
+	IdleHours     float64
+	HolidayHours  float64
+	SickHours     float64
+	Note          string
 }
 
 // TimesheetModel represents the timesheet view
 type TimesheetModel struct {
-	table        table.Model
-	keys         TimesheetKeyMap
-	help         help.Model
-	showHelp     bool
-	currentYear  int
-	currentMonth time.Month
-	cursorRow    int            // Track the current cursor position
-	columnTotals map[string]int // Store column sums
-	yankedEntry  *YankedEntry   // Store yanked entry data
+	table         table.Model
+	keys          TimesheetKeyMap
+	help          help.Model
+	showHelp      bool
+	currentYear   int
+	currentMonth  time.Month
+	cursorRow     int                  // Track the current cursor position
+	columnTotals  map[string]float64   // Store column sums
+	weekTotals    []weekTotal          // Subtotals per week of the month
+	yankedEntry   *YankedEntry         // Store yanked entry data
+	weekendDays   []time.Weekday       // Days marked as weekend, read from the config once
+	idleFillModal *IdleFillModalModel  // Idle fill preview, nil when closed
+	rangeStart    string               // First day of the range being selected after R, "" when not selecting
+	rangeFill     *RangeFillModalModel // Template for the selected range, nil when closed
+	monthRows     []table.Row          // Every day of the month; the table shows those matching the filter
+	filterInput   textinput.Model      // Client filter typed after "/"
+	filtering     bool                 // Whether the filter input takes the keys
+	confirmDelete string               // Delete month prompt awaiting y/n, "" when not asking
+	emailConfirm  *EmailConfirmModel   // Confirmation before emailing the month, nil when closed
+	width         int                  // Space the view has, 0 until the terminal size is known
+	height        int
 }
 
+// timesheetChromeHeight is the number of lines the timesheet renders around
+// its table: the table border, the totals footer, the expected hours line
+// with its blank line, and the help line
+const timesheetChromeHeight = 6
+
 // ChangeMonthMsg is used to change the month
 type ChangeMonthMsg struct {
 	Year       int
@@ -252,14 +313,15 @@ func RefreshPreservingCursor(year int, month time.Month, cursorRow int) tea.Cmd
 
 // Create the initial timesheet model
 func InitialTimesheetModel() TimesheetModel {
-	// Start with the current month
+	// Start with the month shown last, or else the current month
 	now := time.Now()
-	currentYear, currentMonth := now.Year(), now.Month()
+	currentYear, currentMonth := restoredTimesheetMonth(LoadAppState().TimesheetMonth, now)
 
 	// Generate initial table and column totals
-	t, totals, err := generateMonthTable(currentYear, currentMonth)
+	weekendDays := config.GetWeekendDays()
+	t, totals, weeks, err := generateMonthTable(currentYear, currentMonth, weekendDays)
 	if err != nil {
-		log.Fatalf("Error generating table: %v", err)
+		exitcode.Fail(exitcode.Database, "Error generating table: %v", err)
 	}
 
 	// Create model
@@ -272,10 +334,14 @@ func InitialTimesheetModel() TimesheetModel {
 		currentMonth: currentMonth,
 		cursorRow:    0,
 		columnTotals: totals,
+		weekTotals:   weeks,
 		yankedEntry:  nil,
+		weekendDays:  weekendDays,
+		monthRows:    t.Rows(),
+		filterInput:  newClientFilterInput(),
 	}
 
-	// Select today's date
+	// Select today's date; in an earlier month the first day stays selected
 	today := now.Format("2006-01-02")
 	for i, row := range model.table.Rows() {
 		if row[0] == today {
@@ -290,10 +356,13 @@ func InitialTimesheetModel() TimesheetModel {
 
 // Create a timesheet model for a specific year/month and select a date
 func InitialTimesheetModelForMonth(year int, month time.Month, selectDate string) TimesheetModel {
+	saveTimesheetMonth(year, month)
+
 	// Generate initial table and column totals
-	t, totals, err := generateMonthTable(year, month)
+	weekendDays := config.GetWeekendDays()
+	t, totals, weeks, err := generateMonthTable(year, month, weekendDays)
 	if err != nil {
-		log.Fatalf("Error generating table: %v", err)
+		exitcode.Fail(exitcode.Database, "Error generating table: %v", err)
 	}
 
 	model := TimesheetModel{
@@ -305,7 +374,11 @@ func InitialTimesheetModelForMonth(year int, month time.Month, selectDate string
 		currentMonth: month,
 		cursorRow:    0,
 		columnTotals: totals,
+		weekTotals:   weeks,
 		yankedEntry:  nil,
+		weekendDays:  weekendDays,
+		monthRows:    t.Rows(),
+		filterInput:  newClientFilterInput(),
 	}
 
 	// Try to select the given date
@@ -335,18 +408,115 @@ func (m TimesheetModel) RefreshCmd() tea.Cmd {
 	return RefreshPreservingCursor(m.currentYear, m.currentMonth, cursorRow)
 }
 
-// Helper function to parse an int from a string with default value of 0
-func parseIntWithDefault(s string) int {
+// upsertTimesheetEntry saves entry over the block stored for its date and
+// client, or adds it as a new block when the client has none on that date
+func upsertTimesheetEntry(entry db.TimesheetEntry) error {
+	dataLayer := datalayer.GetDataLayer()
+	entries, err := dataLayer.GetTimesheetEntriesByDate(entry.Date)
+	if err != nil {
+		return err
+	}
+	if existingEntry, ok := db.FindClientEntry(entries, entry.Client_name); ok {
+		entry.Id = existingEntry.Id // Keep the same ID
+		return dataLayer.UpdateTimesheetEntry(entry)
+	}
+	return dataLayer.AddTimesheetEntry(entry)
+}
+
+// replaceTimesheetDay saves entry as the only block of its date, removing
+// the blocks of all clients
+func replaceTimesheetDay(entry db.TimesheetEntry) error {
+	dataLayer := datalayer.GetDataLayer()
+	if err := dataLayer.DeleteTimesheetEntryByDate(entry.Date); err != nil {
+		return err
+	}
+	return dataLayer.AddTimesheetEntry(entry)
+}
+
+// selectedClientBlock returns the date and client of the selected row, the
+// block that clear, move and edit act on
+func (m TimesheetModel) selectedClientBlock() (date, clientName string) {
+	row := m.table.SelectedRow()
+	return row[0], utils.RealClientName(row[2])
+}
+
+// selectedNote returns the note of the selected client block. The table
+// only shows the start of it.
+func (m TimesheetModel) selectedNote() string {
+	date, clientName := m.selectedClientBlock()
+	entries, err := datalayer.GetDataLayer().GetTimesheetEntriesByDate(date)
+	if err != nil {
+		return ""
+	}
+	entry, _ := db.FindClientEntry(entries, clientName)
+	return entry.Note
+}
+
+// absenceEntry returns a full vacation (or sick) day for date, replacing
+// whatever was logged. A full day is the schedule's hours for that weekday,
+// or standardHours on weekdays the schedule has no hours for.
+func absenceEntry(date string, schedule workschedule.Schedule, standardHours int, sick bool) (db.TimesheetEntry, error) {
+	day, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return db.TimesheetEntry{}, fmt.Errorf("invalid date %q", date)
+	}
+
+	hours := float64(schedule[day.Weekday()])
+	if hours <= 0 {
+		hours = float64(standardHours)
+	}
+
+	entry := db.TimesheetEntry{Date: date, Total_hours: hours}
+	if sick {
+		entry.Sick_hours = hours
+	} else {
+		entry.Vacation_hours = hours
+	}
+	return entry, nil
+}
+
+// parseHoursWithDefault parses an hour cell of the month table, such as
+// "8" or "4.5", returning 0 for "-" and anything unparseable
+func parseHoursWithDefault(s string) float64 {
 	if s == "-" {
 		return 0
 	}
-	val, err := strconv.Atoi(s)
+	val, err := utils.ParseHours(s)
 	if err != nil {
 		return 0
 	}
 	return val
 }
 
+// missingWorkdays returns the working days of the shown month, up to today,
+// that have no entry. Days hidden by the client filter still count.
+func (m TimesheetModel) missingWorkdays(schedule workschedule.Schedule) []string {
+	logged := map[string]bool{}
+	for _, row := range m.monthRows {
+		if row[9] != "-" {
+			logged[row[0]] = true
+		}
+	}
+	return workschedule.MissingDays(m.currentYear, m.currentMonth, logged, schedule, time.Now())
+}
+
+// previousRowDate returns the date of the day shown above the selected row,
+// skipping other client blocks of the selected day
+func (m TimesheetModel) previousRowDate() (string, bool) {
+	rows := m.table.Rows()
+	cursor := m.table.Cursor()
+	if cursor < 0 || cursor >= len(rows) {
+		return "", false
+	}
+	selectedDate := rows[cursor][0]
+	for i := cursor - 1; i >= 0; i-- {
+		if rows[i][0] != selectedDate {
+			return rows[i][0], true
+		}
+	}
+	return "", false
+}
+
 // Helper function to check if the row has any data to yank
 func hasYankableData(row []string) bool {
 	// Check if there's actual data in any hours column (3-9)
@@ -361,62 +531,27 @@ func hasYankableData(row []string) bool {
 }
 
 func exportToExcel(year int, month time.Month) (string, error) {
-	dataLayer := datalayer.GetDataLayer()
-	entries, err := dataLayer.GetAllTimesheetEntries(year, month)
+	doc, err := document.BuildTimesheet(datalayer.GetDataLayer(), year, month)
 	if err != nil {
-		return "", fmt.Errorf("error fetching timesheet entries: %v", err)
+		return "", err
 	}
-
-	var timesheetRows []printExcel.TimesheetRow
-	for _, entry := range entries {
-		row := printExcel.TimesheetRow{
-			Date:          entry.Date,
-			ClientName:    entry.Client_name,
-			ClientHours:   float64(entry.Client_hours),
-			TrainingHours: float64(entry.Training_hours),
-			VacationHours: float64(entry.Vacation_hours),
-			IdleHours:     float64(entry.Idle_hours),
-			HolidayHours:  float64(entry.Holiday_hours),
-			SickHours:     float64(entry.Sick_hours),
-		}
-		timesheetRows = append(timesheetRows, row)
-	}
-
-	return printExcel.TimesheetToExcel(timesheetRows, year, month)
+	return printExcel.TimesheetToExcel(doc)
 }
 
-func sendDocument(content string, sendAsEmail bool, year int, month time.Month) (string, error) {
-	format := config.GetDocumentType()
-
-	if format == "excel" {
-		// Fetch timesheet entries
-		dataLayer := datalayer.GetDataLayer()
-		entries, err := dataLayer.GetAllTimesheetEntries(year, month)
-		if err != nil {
-			return "", fmt.Errorf("error fetching timesheet entries: %v", err)
-		}
-
-		// Convert database entries to TimesheetRow objects
-		var timesheetRows []printExcel.TimesheetRow
-		for _, entry := range entries {
-			row := printExcel.TimesheetRow{
-				Date:          entry.Date,
-				ClientName:    entry.Client_name,
-				ClientHours:   float64(entry.Client_hours),
-				TrainingHours: float64(entry.Training_hours),
-				VacationHours: float64(entry.Vacation_hours),
-				IdleHours:     float64(entry.Idle_hours),
-				HolidayHours:  float64(entry.Holiday_hours),
-				SickHours:     float64(entry.Sick_hours),
-			}
-			timesheetRows = append(timesheetRows, row)
-		}
-
-		// Export to Excel
-		return printExcel.TimesheetToExcel(timesheetRows, year, month)
-	} else {
-		return printPDF.TimesheetToPDF(content, sendAsEmail)
+// sendDocument saves the month as the configured document type (PDF or
+// Excel), emailing a PDF when sendAsEmail is set
+func sendDocument(sendAsEmail bool, year int, month time.Month) (string, error) {
+	doc, err := document.BuildTimesheet(datalayer.GetDataLayer(), year, month)
+	if err != nil {
+		return "", err
+	}
+	switch config.GetDocumentType() {
+	case "excel":
+		return printExcel.TimesheetToExcel(doc)
+	case "csv":
+		return printCSV.TimesheetToCSV(doc)
 	}
+	return printPDF.TimesheetToPDF(doc, sendAsEmail)
 }
 
 // ClearEntryMsg is sent when an entry is cleared
@@ -428,19 +563,96 @@ func (m TimesheetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	var cmd tea.Cmd
 
 	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		m.setSize(msg.Width, msg.Height)
+		return m, nil
+
+	case IdleFillCancelledMsg:
+		m.idleFillModal = nil
+		return m, nil
+
+	case RangeFillCancelledMsg:
+		m.rangeFill = nil
+		return m, nil
+
+	case RangeFillConfirmedMsg:
+		m.rangeFill = nil
+		result, undo, err := saveRangeFill(msg.Entries)
+		if err != nil {
+			return m, SetStatus(fmt.Sprintf("Error filling range: %v", err))
+		}
+		filled := len(result.Created) + len(result.Replaced)
+		return m, tea.Batch(
+			RefreshPreservingCursor(m.currentYear, m.currentMonth, m.table.Cursor()),
+			PushUndo(undo),
+			TriggerSync(),
+			SetStatus(fmt.Sprintf("Recorded %s on %d day(s)", msg.Label, filled)),
+		)
+
+	case EmailCancelledMsg:
+		m.emailConfirm = nil
+		return m, SetStatus("Email cancelled")
+
+	case EmailConfirmedMsg:
+		m.emailConfirm = nil
+		// Send as email (PDF, Excel or CSV based on configuration)
+		sendAsEmail := true
+		filename, err := sendDocument(sendAsEmail, msg.Year, msg.Month)
+		if err != nil {
+			return m, SetStatus(fmt.Sprintf("Error sending timesheet: %v", err))
+		}
+		return m, SetStatus(fmt.Sprintf("Timesheet saved to %s and sent as email", filename))
+
+	case IdleFillConfirmedMsg:
+		m.idleFillModal = nil
+		if len(msg.Entries) == 0 {
+			return m, SetStatus("No days selected, nothing filled")
+		}
+		result, undo, err := saveIdleFill(msg.Entries)
+		if err != nil {
+			return m, SetStatus(fmt.Sprintf("Error filling idle days: %v", err))
+		}
+		filled := len(result.Created) + len(result.Merged)
+		return m, tea.Batch(
+			RefreshPreservingCursor(m.currentYear, m.currentMonth, m.table.Cursor()),
+			PushUndo(undo),
+			TriggerSync(),
+			SetStatus(fmt.Sprintf("Recorded idle hours on %d day(s)", filled)),
+		)
+
 	case ChangeMonthMsg:
+		// Reopen on this month next time
+		monthChanged := msg.Year != m.currentYear || msg.Month != m.currentMonth
+		if monthChanged {
+			saveTimesheetMonth(msg.Year, msg.Month)
+		}
+
 		// Update the current year and month in the model
 		m.currentYear = msg.Year
 		m.currentMonth = msg.Month
 
 		// Generate a new table for the selected month and get column totals
-		newTable, totals, err := generateMonthTable(msg.Year, msg.Month)
+		newTable, totals, weeks, err := generateMonthTable(msg.Year, msg.Month, m.weekendDays)
 		if err != nil {
 			return m, tea.Printf("Error: %v", err)
 		}
 
 		m.table = newTable
 		m.columnTotals = totals
+		m.weekTotals = weeks
+		if m.height > 0 {
+			m.table.SetHeight(fitTableHeight(m.height, timesheetChromeHeight))
+		}
+
+		// A refresh keeps the client filter; another month starts without it.
+		// The totals always cover the whole month.
+		m.monthRows = newTable.Rows()
+		if monthChanged {
+			m.filtering = false
+			m.filterInput.Blur()
+			m.filterInput.Reset()
+		}
+		m.table.SetRows(filterRowsByClient(m.monthRows, m.filterInput.Value()))
 
 		// If a specific date was requested, try to select it
 		if msg.SelectDate != "" {
@@ -467,27 +679,97 @@ func (m TimesheetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		return m, SetStatus("")
 
 	case tea.KeyMsg:
+		// The idle fill preview takes all keys while it's open
+		if m.idleFillModal != nil {
+			m.idleFillModal, cmd = m.idleFillModal.Update(msg)
+			return m, cmd
+		}
+
+		// So does the range fill template
+		if m.rangeFill != nil {
+			m.rangeFill, cmd = m.rangeFill.Update(msg)
+			return m, cmd
+		}
+
+		// The email confirmation takes all keys while it's open
+		if m.emailConfirm != nil {
+			updated, cmd := m.emailConfirm.Update(msg)
+			confirm := updated.(EmailConfirmModel)
+			m.emailConfirm = &confirm
+			return m, cmd
+		}
+
+		// Deleting the month needs a "y"; any other key cancels it
+		if m.confirmDelete != "" {
+			m.confirmDelete = ""
+			if msg.String() != "y" {
+				return m, SetStatus("Delete cancelled")
+			}
+			deleted, undo, err := deleteTimesheetMonth(m.currentYear, m.currentMonth)
+			if err != nil {
+				return m, SetStatus(fmt.Sprintf("Error deleting month: %v", err))
+			}
+			return m, tea.Batch(
+				RefreshPreservingCursor(m.currentYear, m.currentMonth, m.table.Cursor()),
+				PushUndo(undo),
+				TriggerSync(),
+				SetStatus(fmt.Sprintf("Deleted %d entries from %s %d", deleted, m.currentMonth, m.currentYear)),
+			)
+		}
+
+		// The client filter takes all keys while it's typed
+		if m.filtering {
+			switch msg.Type {
+			case tea.KeyEsc:
+				m.clearClientFilter()
+				return m, nil
+			case tea.KeyEnter:
+				m.filtering = false
+				m.filterInput.Blur()
+				return m, nil
+			}
+			m.filterInput, cmd = m.filterInput.Update(msg)
+			m.applyClientFilter()
+			return m, cmd
+		}
+
 		switch {
 		case msg.Type == tea.KeyEsc:
-			// Clear yanked entry if any
+			// Cancel a range being selected, then clear the yanked entry
+			// and the client filter
+			if m.rangeStart != "" {
+				m.rangeStart = ""
+				return m, SetStatus("Range fill cancelled")
+			}
 			if m.yankedEntry != nil {
 				m.yankedEntry = nil
 				return m, nil
 			}
+			if m.filterInput.Value() != "" {
+				m.clearClientFilter()
+				return m, nil
+			}
+
+		case key.Matches(msg, m.keys.Filter):
+			m.filtering = true
+			return m, m.filterInput.Focus()
+
+		case len(m.table.Rows()) == 0 && !key.Matches(msg, m.keys.Help, m.keys.Quit, m.keys.PrevMonth, m.keys.NextMonth, m.keys.GotoToday):
+			// No day matches the filter, so there's no row to act on
+			return m, SetStatus("No days match the filter, esc clears it")
 
 		case key.Matches(msg, m.keys.SendAsEmail):
-			// Send as email (PDF or Excel based on configuration)
-			sendAsEmail := true
-			filename, err := sendDocument(m.View(), sendAsEmail, m.currentYear, m.currentMonth)
-			if err != nil {
-				return m, tea.Printf("Error sending timesheet: %v", err)
+			// Ask before anything is sent, once there's somewhere to send it
+			if err := email.CheckConfig(); err != nil {
+				return m, SetStatus(err.Error())
 			}
-			return m, tea.Printf("Timesheet saved to %s and sent as email", filename)
+			m.emailConfirm = NewEmailConfirmModel(m.currentYear, m.currentMonth)
+			return m, nil
 
 		case key.Matches(msg, m.keys.Print):
-			// Print without emailing (PDF or Excel based on configuration)
+			// Print without emailing (PDF, Excel or CSV based on configuration)
 			sendAsEmail := false
-			filename, err := sendDocument(m.View(), sendAsEmail, m.currentYear, m.currentMonth)
+			filename, err := sendDocument(sendAsEmail, m.currentYear, m.currentMonth)
 			if err != nil {
 				return m, tea.Printf("Error printing timesheet: %v", err)
 			}
@@ -511,22 +793,23 @@ func (m TimesheetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			// Store the data in the yankedEntry
-			clientHours := parseIntWithDefault(row[3])
-			trainingHours := parseIntWithDefault(row[4])
-			vacationHours := parseIntWithDefault(row[5])
-			idleHours := parseIntWithDefault(row[6])
-			holidayHours := parseIntWithDefault(row[7])
-			sickHours := parseIntWithDefault(row[8])
+			clientHours := parseHoursWithDefault(row[3])
+			trainingHours := parseHoursWithDefault(row[4])
+			vacationHours := parseHoursWithDefault(row[5])
+			idleHours := parseHoursWithDefault(row[6])
+			holidayHours := parseHoursWithDefault(row[7])
+			sickHours := parseHoursWithDefault(row[8])
 
 			m.yankedEntry = &YankedEntry{
 				Date:          row[0],
-				ClientName:    row[2],
+				ClientName:    utils.RealClientName(row[2]),
 				ClientHours:   clientHours,
 				TrainingHours: trainingHours,
 				VacationHours: vacationHours,
 				IdleHours:     idleHours,
 				HolidayHours:  holidayHours,
 				SickHours:     sickHours,
+				Note:          m.selectedNote(),
 			}
 
 			return m, tea.Printf("Entry yanked: %s", row[2])
@@ -541,33 +824,38 @@ func (m TimesheetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			}
 
 			// Store the data in the yankedEntry (same as yank)
-			clientHours := parseIntWithDefault(row[3])
-			trainingHours := parseIntWithDefault(row[4])
-			vacationHours := parseIntWithDefault(row[5])
-			idleHours := parseIntWithDefault(row[6])
-			holidayHours := parseIntWithDefault(row[7])
-			sickHours := parseIntWithDefault(row[8])
+			clientHours := parseHoursWithDefault(row[3])
+			trainingHours := parseHoursWithDefault(row[4])
+			vacationHours := parseHoursWithDefault(row[5])
+			idleHours := parseHoursWithDefault(row[6])
+			holidayHours := parseHoursWithDefault(row[7])
+			sickHours := parseHoursWithDefault(row[8])
 
 			m.yankedEntry = &YankedEntry{
 				Date:          row[0],
-				ClientName:    row[2],
+				ClientName:    utils.RealClientName(row[2]),
 				ClientHours:   clientHours,
 				TrainingHours: trainingHours,
 				VacationHours: vacationHours,
 				IdleHours:     idleHours,
 				HolidayHours:  holidayHours,
 				SickHours:     sickHours,
+				Note:          m.selectedNote(),
 			}
 
-			// Delete the original entry from the database
-			selectedDate := row[0]
-			dataLayer := datalayer.GetDataLayer()
-			err := dataLayer.DeleteTimesheetEntryByDate(selectedDate)
+			// Delete the original block from the database, leaving the
+			// other clients of the day alone
+			selectedDate, clientName := m.selectedClientBlock()
+			undo, err := timesheetEntryUndo("move "+selectedDate, selectedDate)
+			if err != nil {
+				return m, tea.Printf("Error moving entry: %v", err)
+			}
+			err = db.DeleteClientEntry(datalayer.GetDataLayer(), selectedDate, clientName)
 			if err != nil {
 				return m, tea.Printf("Error moving entry: %v", err)
 			}
 
-			return m, tea.Batch(tea.Printf("Entry moved: %s", row[2]), TriggerSync())
+			return m, tea.Batch(tea.Printf("Entry moved: %s", row[2]), PushUndo(undo), TriggerSync())
 
 		case key.Matches(msg, m.keys.PasteEntry):
 			// Check if we have any yanked data
@@ -598,30 +886,143 @@ func (m TimesheetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				Holiday_hours:  m.yankedEntry.HolidayHours,
 				Sick_hours:     m.yankedEntry.SickHours,
 				Total_hours:    totalHours,
+				Note:           m.yankedEntry.Note,
 			}
 
-			// Check if an entry already exists for this date
-			dataLayer := datalayer.GetDataLayer()
-			existingEntry, err := dataLayer.GetTimesheetEntryByDate(selectedDate)
-			if err == nil {
-				// Entry exists, update it
-				entry.Id = existingEntry.Id // Keep the same ID
-				err = dataLayer.UpdateTimesheetEntry(entry)
-			} else {
-				// Entry doesn't exist, add a new one
-				err = dataLayer.AddTimesheetEntry(entry)
+			// Remember what was there so the paste can be undone
+			undo, err := timesheetEntryUndo("paste "+selectedDate, selectedDate)
+			if err != nil {
+				return m, tea.Printf("Error saving entry: %v", err)
 			}
 
-			if err != nil {
+			if err := upsertTimesheetEntry(entry); err != nil {
 				return m, tea.Printf("Error saving entry: %v", err)
 			}
 
 			// Refresh the table but maintain cursor position; trigger sync.
 			return m, tea.Batch(
 				RefreshPreservingCursor(m.currentYear, m.currentMonth, cursorRow),
+				PushUndo(undo),
 				TriggerSync(),
 			)
 
+		case key.Matches(msg, m.keys.RepeatDay):
+			selectedDate := m.table.SelectedRow()[0]
+			cursorRow := m.table.Cursor()
+
+			previousDate, ok := m.previousRowDate()
+			if !ok {
+				return m, SetStatus("No previous day to repeat")
+			}
+			entries, err := datalayer.GetDataLayer().GetTimesheetEntriesByDate(previousDate)
+			if err != nil {
+				return m, SetStatus(fmt.Sprintf("Error loading %s: %v", previousDate, err))
+			}
+			if len(entries) == 0 {
+				return m, SetStatus(fmt.Sprintf("Nothing to repeat: %s has no entry", previousDate))
+			}
+
+			undo, err := timesheetEntryUndo("repeat "+selectedDate, selectedDate)
+			if err != nil {
+				return m, SetStatus(fmt.Sprintf("Error saving entry: %v", err))
+			}
+			for _, entry := range entries {
+				entry.Id = 0
+				entry.Date = selectedDate
+				if err := upsertTimesheetEntry(entry); err != nil {
+					return m, SetStatus(fmt.Sprintf("Error saving entry: %v", err))
+				}
+			}
+
+			return m, tea.Batch(
+				RefreshPreservingCursor(m.currentYear, m.currentMonth, cursorRow),
+				PushUndo(undo),
+				SetStatus(fmt.Sprintf("Repeated %s on %s", previousDate, selectedDate)),
+				TriggerSync(),
+			)
+
+		case key.Matches(msg, m.keys.VacationDay), key.Matches(msg, m.keys.SickDay):
+			selectedDate := m.table.SelectedRow()[0]
+			cursorRow := m.table.Cursor()
+			sick := key.Matches(msg, m.keys.SickDay)
+
+			entry, err := absenceEntry(selectedDate, config.GetWorkSchedule(), config.GetStandardDailyHours(), sick)
+			if err != nil {
+				return m, tea.Printf("Error: %v", err)
+			}
+
+			kind := "vacation"
+			if sick {
+				kind = "sick"
+			}
+			undo, err := timesheetEntryUndo(kind+" day "+selectedDate, selectedDate)
+			if err != nil {
+				return m, tea.Printf("Error saving entry: %v", err)
+			}
+			if err := replaceTimesheetDay(entry); err != nil {
+				return m, tea.Printf("Error saving entry: %v", err)
+			}
+
+			return m, tea.Batch(
+				RefreshPreservingCursor(m.currentYear, m.currentMonth, cursorRow),
+				PushUndo(undo),
+				TriggerSync(),
+			)
+
+		case key.Matches(msg, m.keys.RangeFill):
+			// The first R marks the start of the range, the second the end
+			selectedDate := m.table.SelectedRow()[0]
+			if m.rangeStart == "" {
+				m.rangeStart = selectedDate
+				return m, nil
+			}
+			start := m.rangeStart
+			m.rangeStart = ""
+			modal, err := NewRangeFillModalModel(start, selectedDate, m.weekendDays, config.GetStandardDailyHours())
+			if err != nil {
+				return m, SetStatus(fmt.Sprintf("Error: %v", err))
+			}
+			if modal.days == 0 {
+				return m, SetStatus("No working days in the range, nothing to fill")
+			}
+			m.rangeFill = modal
+			return m, textinput.Blink
+
+		case key.Matches(msg, m.keys.FillIdle):
+			modal, status := planIdleFill(m.currentYear, m.currentMonth)
+			if modal == nil {
+				return m, SetStatus(status)
+			}
+			m.idleFillModal = modal
+			return m, nil
+
+		case key.Matches(msg, m.keys.DeleteMonth):
+			prompt, err := deleteMonthPrompt(m.currentYear, m.currentMonth)
+			if err != nil {
+				return m, SetStatus(fmt.Sprintf("Error: %v", err))
+			}
+			if prompt == "" {
+				return m, SetStatus(fmt.Sprintf("No entries to delete in %s %d", m.currentMonth, m.currentYear))
+			}
+			m.confirmDelete = prompt
+			return m, nil
+
+		case key.Matches(msg, m.keys.FillHolidays):
+			result, undo, err := fillHolidays(m.currentYear, m.currentMonth)
+			if err != nil {
+				return m, SetStatus(fmt.Sprintf("Error filling holidays: %v", err))
+			}
+			filled := len(result.Created) + len(result.Merged)
+			if filled == 0 {
+				return m, SetStatus(fmt.Sprintf("No holidays to fill in %s %d", m.currentMonth, m.currentYear))
+			}
+			return m, tea.Batch(
+				RefreshPreservingCursor(m.currentYear, m.currentMonth, m.table.Cursor()),
+				PushUndo(undo),
+				TriggerSync(),
+				SetStatus(fmt.Sprintf("Recorded holiday hours on %d day(s)", filled)),
+			)
+
 		case key.Matches(msg, m.keys.Help):
 			m.showHelp = !m.showHelp
 			return m, nil
@@ -630,32 +1031,41 @@ func (m TimesheetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			return m, tea.Quit
 
 		case key.Matches(msg, m.keys.GotoToday):
-			// Open edit form for today's date directly
-			today := time.Now().Format("2006-01-02")
-			return m, func() tea.Msg {
-				return EditEntryMsg{Date: today}
-			}
+			// Jump to today's month, which may not be the one restored
+			// at startup, and open the edit form for today's date (or
+			// yesterday's before dayBoundaryHour)
+			today := entryToday()
+			date, _ := time.Parse("2006-01-02", today)
+			return m, tea.Sequence(
+				ChangeMonth(date.Year(), date.Month(), today),
+				func() tea.Msg {
+					return EditEntryMsg{Date: today}
+				},
+			)
 
 		case key.Matches(msg, m.keys.Enter):
-			// Get the date from the selected row
-			selectedDate := m.table.SelectedRow()[0]
+			// Edit the selected client block of the date
+			selectedDate, clientName := m.selectedClientBlock()
 			return m, func() tea.Msg {
-				return EditEntryMsg{Date: selectedDate}
+				return EditEntryMsg{Date: selectedDate, Client: clientName}
 			}
 
 		case key.Matches(msg, m.keys.ClearEntry):
-			// Get the date from the selected row
-			selectedDate := m.table.SelectedRow()[0]
+			// Clear the selected client block of the date
+			selectedDate, clientName := m.selectedClientBlock()
 			cursorRow := m.table.Cursor()
-			// Delete the entry
-			dataLayer := datalayer.GetDataLayer()
-			err := dataLayer.DeleteTimesheetEntryByDate(selectedDate)
+			undo, err := timesheetEntryUndo("clear "+selectedDate, selectedDate)
+			if err != nil {
+				return m, tea.Printf("Error clearing entry: %v", err)
+			}
+			err = db.DeleteClientEntry(datalayer.GetDataLayer(), selectedDate, clientName)
 			if err != nil {
 				return m, tea.Printf("Error clearing entry: %v", err)
 			}
 			// Refresh the table but maintain cursor position; trigger sync.
 			return m, tea.Batch(
 				RefreshPreservingCursor(m.currentYear, m.currentMonth, cursorRow),
+				PushUndo(undo),
 				TriggerSync(),
 			)
 
@@ -689,7 +1099,41 @@ func (m TimesheetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 	return m, cmd
 }
 
+// setSize fits the table and help to the space the view has
+func (m *TimesheetModel) setSize(width, height int) {
+	if width == m.width && height == m.height {
+		return
+	}
+	m.width, m.height = width, height
+	m.help.Width = width
+	m.table.SetHeight(fitTableHeight(height, timesheetChromeHeight))
+}
+
+// IsEditing reports whether a modal (the idle fill preview, the range fill
+// template or the email confirmation), the client filter input or the
+// delete month prompt takes the keys
+func (m TimesheetModel) IsEditing() bool {
+	return m.idleFillModal != nil || m.rangeFill != nil || m.emailConfirm != nil || m.filtering || m.confirmDelete != ""
+}
+
 func (m TimesheetModel) View() string {
+	// If the idle fill preview is open, show only the preview
+	if m.idleFillModal != nil {
+		return m.idleFillModal.View()
+	}
+
+	// Likewise for the range fill template
+	if m.rangeFill != nil {
+		return m.rangeFill.View()
+	}
+
+	// Show the email confirmation over the timesheet
+	if m.emailConfirm != nil {
+		background := m
+		background.emailConfirm = nil
+		return overlay.New(m.emailConfirm, background, overlay.Center, overlay.Center, 0, 0).View()
+	}
+
 	var s string
 
 	// Get the table view
@@ -699,9 +1143,8 @@ func (m TimesheetModel) View() string {
 	if m.yankedEntry != nil {
 		rows := m.table.Rows()
 		for i, row := range rows {
-			date := row[0]
-			// Check if this row matches the yanked entry's date
-			if date == m.yankedEntry.Date {
+			// Check if this row is the yanked entry's client block
+			if row[0] == m.yankedEntry.Date && utils.RealClientName(row[2]) == m.yankedEntry.ClientName {
 				// Split the table view into lines
 				lines := strings.Split(tableView, "\n")
 				// The table has 2 header lines (border + column names)
@@ -717,26 +1160,29 @@ func (m TimesheetModel) View() string {
 		}
 	}
 
-	// Render the table
-	s += baseStyle.Render(tableView) + "\n"
+	// Render the table, with the weekly subtotals beside it when they fit
+	tableView = baseStyle.Render(tableView)
+	weeksView := renderWeekTotals(m.weekTotals)
+	if m.width == 0 || lipgloss.Width(tableView)+2+lipgloss.Width(weeksView) <= m.width {
+		tableView = lipgloss.JoinHorizontal(lipgloss.Top, tableView, "  ", weeksView)
+	}
+	s += tableView + "\n"
 
-	// Render the footer with totals
-	footerContent := fmt.Sprintf("%-12s %-10s %-20s", "Total:", "", "")
-	footerContent += fmt.Sprintf("%*d", 15-len(fmt.Sprintf("%d", m.columnTotals["clientHours"])), m.columnTotals["clientHours"])
-	footerContent += fmt.Sprintf("%*d", 13-len(fmt.Sprintf("%d", m.columnTotals["trainingHours"])), m.columnTotals["trainingHours"])
-	footerContent += fmt.Sprintf("%*d", 13-len(fmt.Sprintf("%d", m.columnTotals["vacationHours"])), m.columnTotals["vacationHours"])
-	footerContent += fmt.Sprintf("%*d", 13-len(fmt.Sprintf("%d", m.columnTotals["idleHours"])), m.columnTotals["idleHours"])
-	footerContent += fmt.Sprintf("%*d", 13-len(fmt.Sprintf("%d", m.columnTotals["holidayHours"])), m.columnTotals["holidayHours"])
-	footerContent += fmt.Sprintf("%*d", 14-len(fmt.Sprintf("%d", m.columnTotals["sickHours"])), m.columnTotals["sickHours"])
-	footerContent += fmt.Sprintf("%*d", 14-len(fmt.Sprintf("%d", m.columnTotals["totalHours"])), m.columnTotals["totalHours"])
+	// Render the footer with totals, lined up under the table's columns
+	totals := monthTotalsRow(m.columnTotals)
+	footerContent := " " // The table's left border
+	for i, column := range m.table.Columns() {
+		footerContent += " " + lipgloss.NewStyle().Width(column.Width).Render(totals[i]) + " "
+	}
 
 	s += footerStyle.Render(footerContent) + "\n"
 
 	// Expected vs. logged hours for this month, driven by the user's
 	// configured work schedule. Δ is positive when over the target,
 	// negative when behind.
-	expected := workschedule.ExpectedHoursForMonth(m.currentYear, m.currentMonth, config.GetWorkSchedule())
-	delta := m.columnTotals["totalHours"] - expected
+	schedule := config.GetWorkSchedule()
+	expected := workschedule.ExpectedHoursForMonth(m.currentYear, m.currentMonth, schedule)
+	delta := m.columnTotals["totalHours"] - float64(expected)
 
 	expectedLabel := lipgloss.NewStyle().Foreground(lipgloss.Color("86")).Render("Expected:")
 	expectedValue := lipgloss.NewStyle().Bold(true).Render(fmt.Sprintf("%dh", expected))
@@ -745,16 +1191,47 @@ func (m TimesheetModel) View() string {
 	switch {
 	case delta < 0:
 		deltaStr = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("196")).
-			Render(fmt.Sprintf("Δ %dh", delta)) // negative sign comes from the number
+			Render(fmt.Sprintf("Δ %sh", utils.FormatHours(delta))) // negative sign comes from the number
 	case delta > 0:
 		deltaStr = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("220")).
-			Render(fmt.Sprintf("Δ +%dh", delta))
+			Render(fmt.Sprintf("Δ +%sh", utils.FormatHours(delta)))
 	default:
 		deltaStr = lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("78")).
 			Render("Δ 0h ✓")
 	}
 
-	s += fmt.Sprintf("%s %s    %s\n\n", expectedLabel, expectedValue, deltaStr)
+	// Working days up to today without an entry. Non-working weekdays are
+	// expected to be empty and never count as missing.
+	var missingStr string
+	if missing := m.missingWorkdays(schedule); len(missing) > 0 {
+		missingStr = "    " + lipgloss.NewStyle().Foreground(lipgloss.Color("196")).
+			Render(fmt.Sprintf("Missing: %d working day(s)", len(missing)))
+	}
+
+	// The client filter being typed, or the one applied
+	var filterStr string
+	switch {
+	case m.filtering:
+		filterStr = "    " + m.filterInput.View()
+	case m.filterInput.Value() != "":
+		filterStr = "    " + lipgloss.NewStyle().Foreground(lipgloss.Color("86")).
+			Render(fmt.Sprintf("Filter: %s (esc clears)", m.filterInput.Value()))
+	}
+
+	// The delete month prompt waiting for an answer
+	var confirmStr string
+	if m.confirmDelete != "" {
+		confirmStr = "    " + lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("196")).Render(m.confirmDelete)
+	}
+
+	// The start of a range fill waiting for its last day
+	var rangeStr string
+	if m.rangeStart != "" {
+		rangeStr = "    " + lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("86")).
+			Render(fmt.Sprintf("Range from %s: move to the last day and press R (esc cancels)", m.rangeStart))
+	}
+
+	s += fmt.Sprintf("%s %s    %s%s%s%s%s\n\n", expectedLabel, expectedValue, deltaStr, missingStr, filterStr, confirmStr, rangeStr)
 
 	if m.showHelp {
 		// Full help view
@@ -767,23 +1244,42 @@ func (m TimesheetModel) View() string {
 	return s
 }
 
-// Generate table for a specific month
-func generateMonthTable(year int, month time.Month) (table.Model, map[string]int, error) {
+// clientSubRowMarker fills the Day column of the sub-rows listing the extra
+// clients of a day
+const clientSubRowMarker = "  ↳"
+
+// maxNoteWidth is the number of characters of a note the table shows
+const maxNoteWidth = 30
+
+// noteLabel shortens a note to fit the table's Note column
+func noteLabel(note string) string {
+	runes := []rune(note)
+	if len(runes) <= maxNoteWidth {
+		return note
+	}
+	return string(runes[:maxNoteWidth-1]) + "…"
+}
+
+// Generate table for a specific month, marking the days in weekendDays. The
+// month's totals are returned per column and per week.
+func generateMonthTable(year int, month time.Month, weekendDays []time.Weekday) (table.Model, map[string]float64, []weekTotal, error) {
+	// Widths are fitted to the month's data once the rows are built
 	columns := []table.Column{
-		{Title: "Date", Width: 12},
-		{Title: "Day", Width: 15},
-		{Title: "Client", Width: 20},
-		{Title: "Hours", Width: 10},
-		{Title: "Training", Width: 10},
-		{Title: "Vacation", Width: 10},
-		{Title: "Idle", Width: 10},
-		{Title: "Holiday", Width: 10},
-		{Title: "Sick", Width: 10},
-		{Title: "Total", Width: 10},
+		{Title: "Date"},
+		{Title: "Day"},
+		{Title: "Client"},
+		{Title: "Hours"},
+		{Title: "Training"},
+		{Title: "Vacation"},
+		{Title: "Idle"},
+		{Title: "Holiday"},
+		{Title: "Sick"},
+		{Title: "Total"},
+		{Title: "Note"},
 	}
 
 	// Initialize column totals
-	columnTotals := map[string]int{
+	columnTotals := map[string]float64{
 		"clientHours":   0,
 		"trainingHours": 0,
 		"vacationHours": 0,
@@ -802,10 +1298,10 @@ func generateMonthTable(year int, month time.Month) (table.Model, map[string]int
 		entries = []db.TimesheetEntry{}
 	}
 
-	// Create a map of entries by date for faster lookup
-	entriesByDate := make(map[string]db.TimesheetEntry)
+	// Group the entries by date, one block per client, for faster lookup
+	entriesByDate := make(map[string][]db.TimesheetEntry)
 	for _, entry := range entries {
-		entriesByDate[entry.Date] = entry
+		entriesByDate[entry.Date] = append(entriesByDate[entry.Date], entry)
 
 		// Add to totals
 		columnTotals["clientHours"] += entry.Client_hours
@@ -821,54 +1317,48 @@ func generateMonthTable(year int, month time.Month) (table.Model, map[string]int
 	firstDay := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
 	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.Local)
 
-	// Create table rows for each day of the month
+	// Create table rows for each day of the month. A day split between
+	// clients gets a sub-row per extra client, repeating the date so the
+	// keys act on that client's block.
 	rows := []table.Row{}
 	for day := firstDay; !day.After(lastDay); day = day.AddDate(0, 0, 1) {
 		dateStr := day.Format("2006-01-02")
 		weekday := day.Weekday().String()
 
-		// Default values for days without entries
-		clientName := "-"
-		clientHours := "-"
-		training := "-"
-		vacation := "-"
-		idle := "-"
-		holiday := "-"
-		sick := "-"
-		totalHours := "-"
-
-		// If we have an entry for this date, use its data
-		if entry, exists := entriesByDate[dateStr]; exists {
-			clientName = entry.Client_name
-			clientHours = fmt.Sprintf("%d", entry.Client_hours)
-			training = fmt.Sprintf("%d", entry.Training_hours)
-			vacation = fmt.Sprintf("%d", entry.Vacation_hours)
-			idle = fmt.Sprintf("%d", entry.Idle_hours)
-			holiday = fmt.Sprintf("%d", entry.Holiday_hours)
-			sick = fmt.Sprintf("%d", entry.Sick_hours)
-			totalHours = fmt.Sprintf("%d", entry.Total_hours)
-		}
-
 		// Weekend styling - make them visually distinct
-		if day.Weekday() == time.Saturday || day.Weekday() == time.Sunday {
+		if slices.Contains(weekendDays, day.Weekday()) {
 			weekday = "💤 " + weekday // Add emoji for weekends
 		}
 
-		row := table.Row{
-			dateStr,
-			weekday,
-			clientName,
-			clientHours,
-			training,
-			vacation,
-			idle,
-			holiday,
-			sick,
-			totalHours,
+		blocks := entriesByDate[dateStr]
+		if len(blocks) == 0 {
+			// Default values for days without entries
+			rows = append(rows, table.Row{dateStr, weekday, "-", "-", "-", "-", "-", "-", "-", "-", ""})
+			continue
+		}
+
+		for i, entry := range blocks {
+			if i > 0 {
+				weekday = clientSubRowMarker
+			}
+			rows = append(rows, table.Row{
+				dateStr,
+				weekday,
+				utils.ClientLabel(entry.Client_name),
+				utils.FormatHours(entry.Client_hours),
+				utils.FormatHours(entry.Training_hours),
+				utils.FormatHours(entry.Vacation_hours),
+				utils.FormatHours(entry.Idle_hours),
+				utils.FormatHours(entry.Holiday_hours),
+				utils.FormatHours(entry.Sick_hours),
+				utils.FormatHours(entry.Total_hours),
+				noteLabel(entry.Note),
+			})
 		}
-		rows = append(rows, row)
 	}
 
+	fitColumnWidths(columns, append(rows, monthTotalsRow(columnTotals)))
+
 	t := table.New(
 		table.WithColumns(columns),
 		table.WithRows(rows),
@@ -888,7 +1378,32 @@ func generateMonthTable(year int, month time.Month) (table.Model, map[string]int
 		Bold(true)
 	t.SetStyles(s)
 
-	return t, columnTotals, nil
+	weeks := monthWeekTotals(year, month, entries, config.GetWeekStart(), config.GetWorkSchedule())
+	return t, columnTotals, weeks, nil
+}
+
+// monthTotalsRow formats the column totals as a row matching the month
+// table's columns
+func monthTotalsRow(totals map[string]float64) table.Row {
+	row := table.Row{"Total:", "", ""}
+	for _, key := range []string{"clientHours", "trainingHours", "vacationHours", "idleHours", "holidayHours", "sickHours", "totalHours"} {
+		row = append(row, utils.FormatHours(totals[key]))
+	}
+	return append(row, "") // Note
+}
+
+// fitColumnWidths sizes each column to its widest cell or title, so long
+// client names aren't cut off and hour columns don't waste space
+func fitColumnWidths(columns []table.Column, rows []table.Row) {
+	for i := range columns {
+		width := lipgloss.Width(columns[i].Title)
+		for _, row := range rows {
+			if i < len(row) {
+				width = max(width, lipgloss.Width(row[i]))
+			}
+		}
+		columns[i].Width = width
+	}
 }
 
 // GetSelectedDate returns the date of the currently selected row in the table
@@ -0,0 +1,33 @@
+package ui
+
+import (
+	"fmt"
+	"time"
+	"timesheet/internal/datalayer"
+)
+
+// deleteMonthPrompt asks to confirm deleting the month's entries. It returns
+// "" when the month has nothing to delete.
+func deleteMonthPrompt(year int, month time.Month) (string, error) {
+	entries, err := datalayer.GetDataLayer().GetAllTimesheetEntries(year, month)
+	if err != nil || len(entries) == 0 {
+		return "", err
+	}
+	return fmt.Sprintf("Delete all %d entries of %s %d? (y/N)", len(entries), month, year), nil
+}
+
+// deleteTimesheetMonth removes every entry of a month and returns an action
+// that puts them back
+func deleteTimesheetMonth(year int, month time.Month) (int, UndoAction, error) {
+	dl := datalayer.GetDataLayer()
+	entries, err := dl.GetAllTimesheetEntries(year, month)
+	if err != nil {
+		return 0, UndoAction{}, err
+	}
+	undo, err := restoreDatesUndo(fmt.Sprintf("delete %s %d", month, year), entries)
+	if err != nil {
+		return 0, UndoAction{}, err
+	}
+	deleted, err := dl.DeleteTimesheetEntriesForMonth(year, month)
+	return deleted, undo, err
+}
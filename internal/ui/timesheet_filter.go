@@ -0,0 +1,62 @@
+package ui
+
+import (
+	"strings"
+
+	"github.com/charmbracelet/bubbles/table"
+	"github.com/charmbracelet/bubbles/textinput"
+)
+
+// newClientFilterInput creates the input typed after "/" in the timesheet
+func newClientFilterInput() textinput.Model {
+	input := textinput.New()
+	input.Prompt = "/"
+	input.Placeholder = "client"
+	input.CharLimit = 50
+	return input
+}
+
+// filterRowsByClient returns the rows whose Client column contains filter,
+// ignoring case. An empty filter returns all rows.
+func filterRowsByClient(rows []table.Row, filter string) []table.Row {
+	filter = strings.ToLower(strings.TrimSpace(filter))
+	if filter == "" {
+		return rows
+	}
+	var matches []table.Row
+	for _, row := range rows {
+		if len(row) > 2 && strings.Contains(strings.ToLower(row[2]), filter) {
+			matches = append(matches, row)
+		}
+	}
+	return matches
+}
+
+// applyClientFilter shows the days of the month matching the client filter.
+// The selected date stays selected when it's still shown.
+func (m *TimesheetModel) applyClientFilter() {
+	var selected string
+	if row := m.table.SelectedRow(); len(row) > 0 {
+		selected = row[0]
+	}
+
+	rows := filterRowsByClient(m.monthRows, m.filterInput.Value())
+	m.table.SetRows(rows)
+	cursor := 0
+	for i, row := range rows {
+		if row[0] == selected {
+			cursor = i
+			break
+		}
+	}
+	m.table.SetCursor(cursor)
+	m.cursorRow = m.table.Cursor()
+}
+
+// clearClientFilter empties the client filter and shows the whole month again
+func (m *TimesheetModel) clearClientFilter() {
+	m.filtering = false
+	m.filterInput.Blur()
+	m.filterInput.Reset()
+	m.applyClientFilter()
+}
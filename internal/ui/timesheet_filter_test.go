@@ -0,0 +1,125 @@
+package ui
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+	"timesheet/internal/config"
+	"timesheet/internal/db"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestFilterRowsByClient(t *testing.T) {
+	rows := []table.Row{
+		{"2024-03-01", "Friday", "Acme Corp"},
+		{"2024-03-02", "Saturday", "-"},
+		{"2024-03-04", "Monday", "Globex"},
+		{"2024-03-05", "Tuesday", "acme labs"},
+	}
+	tests := []struct {
+		filter string
+		dates  []string
+	}{
+		{"", []string{"2024-03-01", "2024-03-02", "2024-03-04", "2024-03-05"}},
+		{"acme", []string{"2024-03-01", "2024-03-05"}},
+		{" GLOB ", []string{"2024-03-04"}},
+		{"initech", nil},
+	}
+	for _, tt := range tests {
+		var dates []string
+		for _, row := range filterRowsByClient(rows, tt.filter) {
+			dates = append(dates, row[0])
+		}
+		if len(dates) != len(tt.dates) {
+			t.Errorf("filterRowsByClient(%q) = %v, want %v", tt.filter, dates, tt.dates)
+			continue
+		}
+		for i := range dates {
+			if dates[i] != tt.dates[i] {
+				t.Errorf("filterRowsByClient(%q) = %v, want %v", tt.filter, dates, tt.dates)
+				break
+			}
+		}
+	}
+}
+
+func TestTimesheetClientFilter(t *testing.T) {
+	if err := db.InitializeDatabase(":memory:"); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+	t.Setenv("HOME", t.TempDir())
+
+	for _, entry := range []db.TimesheetEntry{
+		{Date: "2024-03-04", Client_name: "Acme Corp", Client_hours: 8},
+		{Date: "2024-03-05", Client_name: "Globex", Client_hours: 6},
+		{Date: "2024-03-06", Client_name: "Acme Corp", Client_hours: 4},
+	} {
+		if err := db.AddTimesheetEntry(entry); err != nil {
+			t.Fatalf("AddTimesheetEntry failed: %v", err)
+		}
+	}
+
+	m := InitialTimesheetModelForMonth(2024, time.March, "2024-03-06")
+	send := func(msg tea.Msg) {
+		t.Helper()
+		updated, _ := m.Update(msg)
+		m = updated.(TimesheetModel)
+	}
+	typeKeys := func(keys string) {
+		t.Helper()
+		for _, r := range keys {
+			send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune{r}})
+		}
+	}
+
+	missing := len(m.missingWorkdays(config.GetWorkSchedule()))
+
+	typeKeys("/")
+	if !m.IsEditing() {
+		t.Fatal("Expected the filter input to take the keys after /")
+	}
+	typeKeys("acme")
+	if got := len(m.table.Rows()); got != 2 {
+		t.Fatalf("Expected 2 days for acme, got %d", got)
+	}
+	if got := m.GetSelectedDate(); got != "2024-03-06" {
+		t.Errorf("Expected the selected date to stay selected, got %s", got)
+	}
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if m.IsEditing() {
+		t.Error("Expected enter to hand the keys back to the table")
+	}
+	if got := m.columnTotals["clientHours"]; got != 18 {
+		t.Errorf("Expected the totals to cover the whole month, got %v client hours", got)
+	}
+	if got := len(m.missingWorkdays(config.GetWorkSchedule())); got != missing {
+		t.Errorf("Expected %d missing days with the filter on, got %d", missing, got)
+	}
+
+	// A refresh of the same month keeps the filter
+	send(ChangeMonthMsg{Year: 2024, Month: time.March, Preserve: true})
+	if got := len(m.table.Rows()); got != 2 {
+		t.Errorf("Expected the filter to survive a refresh, got %d rows", got)
+	}
+
+	send(tea.KeyMsg{Type: tea.KeyEsc})
+	if got := len(m.table.Rows()); got != 31 {
+		t.Errorf("Expected esc to show all 31 days, got %d", got)
+	}
+
+	// Another month starts without the filter
+	typeKeys("/globex")
+	send(tea.KeyMsg{Type: tea.KeyEnter})
+	if got := len(m.table.Rows()); got != 1 {
+		t.Fatalf("Expected 1 day for globex, got %d", got)
+	}
+	send(ChangeMonthMsg{Year: 2024, Month: time.April})
+	if got := len(m.table.Rows()); got != 30 || m.filterInput.Value() != "" {
+		t.Errorf("Expected the filter to reset on a month change, got %d rows and %q", got, m.filterInput.Value())
+	}
+}
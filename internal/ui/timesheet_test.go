@@ -0,0 +1,480 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+	"timesheet/internal/config"
+	"timesheet/internal/db"
+	"timesheet/internal/workschedule"
+
+	"github.com/charmbracelet/bubbles/table"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+func TestAbsenceEntry(t *testing.T) {
+	schedule := workschedule.Schedule{time.Monday: 9, time.Tuesday: 6}
+
+	tests := []struct {
+		date         string
+		sick         bool
+		wantVacation float64
+		wantSick     float64
+	}{
+		{"2024-06-03", false, 9, 0}, // Monday
+		{"2024-06-04", true, 0, 6},  // Tuesday, part-time
+		{"2024-06-06", false, 8, 0}, // Thursday, not in the schedule
+	}
+
+	for _, tt := range tests {
+		entry, err := absenceEntry(tt.date, schedule, 8, tt.sick)
+		if err != nil {
+			t.Fatalf("absenceEntry(%s) failed: %v", tt.date, err)
+		}
+		if entry.Vacation_hours != tt.wantVacation || entry.Sick_hours != tt.wantSick {
+			t.Errorf("%s: vacation/sick = %g/%g, want %g/%g", tt.date,
+				entry.Vacation_hours, entry.Sick_hours, tt.wantVacation, tt.wantSick)
+		}
+		if entry.Client_hours != 0 || entry.Total_hours != tt.wantVacation+tt.wantSick {
+			t.Errorf("%s: expected only the absence hours, got %+v", tt.date, entry)
+		}
+	}
+
+	if _, err := absenceEntry("not a date", schedule, 8, false); err == nil {
+		t.Error("Expected an error for an invalid date")
+	}
+}
+
+func TestFitColumnWidths(t *testing.T) {
+	columns := []table.Column{{Title: "Client"}, {Title: "Hours"}}
+	fitColumnWidths(columns, []table.Row{
+		{"Acme", "8"},
+		{"Very Descriptive Client Name B.V.", "12"},
+	})
+
+	if columns[0].Width != len("Very Descriptive Client Name B.V.") {
+		t.Errorf("Client width = %d, want the longest name", columns[0].Width)
+	}
+	if columns[1].Width != len("Hours") {
+		t.Errorf("Hours width = %d, want the title width", columns[1].Width)
+	}
+}
+
+func TestTimesheetModelWindowSize(t *testing.T) {
+	columns := table.WithColumns([]table.Column{{Title: "Date", Width: 10}})
+	m := TimesheetModel{table: table.New(columns)}
+	// Table heights include the header, Height() doesn't
+	header := 10 - table.New(columns, table.WithHeight(10)).Height()
+
+	updated, _ := m.Update(tea.WindowSizeMsg{Width: 100, Height: 30})
+	m = updated.(TimesheetModel)
+	if got, want := m.table.Height(), 30-timesheetChromeHeight-header; got != want {
+		t.Errorf("table height = %d, want %d", got, want)
+	}
+	if m.help.Width != 100 {
+		t.Errorf("help width = %d, want 100", m.help.Width)
+	}
+
+	updated, _ = m.Update(tea.WindowSizeMsg{Width: 100, Height: 4})
+	m = updated.(TimesheetModel)
+	if got, want := m.table.Height(), minTableHeight-header; got != want {
+		t.Errorf("table height in a short terminal = %d, want %d", got, want)
+	}
+}
+
+func TestParseHoursWithDefault(t *testing.T) {
+	tests := map[string]float64{"8": 8, "4.5": 4.5, "0.5": 0.5, "-": 0, "abc": 0}
+	for input, want := range tests {
+		if got := parseHoursWithDefault(input); got != want {
+			t.Errorf("parseHoursWithDefault(%q) = %g, want %g", input, got, want)
+		}
+	}
+}
+
+func TestMonthTotalsRow(t *testing.T) {
+	row := monthTotalsRow(map[string]float64{"clientHours": 36.5, "trainingHours": 4, "totalHours": 40.5})
+	if row[3] != "36.5" || row[4] != "4" || row[5] != "0" || row[9] != "40.5" {
+		t.Errorf("Unexpected totals row %v", row)
+	}
+}
+
+func TestParseFormHours(t *testing.T) {
+	tests := []struct {
+		input   string
+		want    float64
+		wantErr bool
+	}{
+		{"", 0, false},
+		{"8", 8, false},
+		{"4.5", 4.5, false},
+		{"4,5", 4.5, false},
+		{"4.25", 0, true},
+		{"-1", 0, true},
+		{"eight", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := parseHours(tt.input)
+		if (err != nil) != tt.wantErr || got != tt.want {
+			t.Errorf("parseHours(%q) = %g, %v; want %g (error: %v)", tt.input, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestGenerateMonthTableWeekendDays(t *testing.T) {
+	if err := db.InitializeDatabase(":memory:"); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+
+	// A Friday and Saturday weekend; 1 March 2024 is a Friday right after
+	// the month boundary
+	for _, month := range []time.Month{time.February, time.March} {
+		tbl, _, _, err := generateMonthTable(2024, month, []time.Weekday{time.Friday, time.Saturday})
+		if err != nil {
+			t.Fatalf("generateMonthTable failed: %v", err)
+		}
+		for _, row := range tbl.Rows() {
+			day, _ := time.Parse("2006-01-02", row[0])
+			weekend := day.Weekday() == time.Friday || day.Weekday() == time.Saturday
+			if strings.HasPrefix(row[1], "💤") != weekend {
+				t.Errorf("%s (%s): weekend marker = %v, want %v", row[0], row[1], !weekend, weekend)
+			}
+		}
+	}
+}
+
+func TestTimesheetClientBlocks(t *testing.T) {
+	if err := db.InitializeDatabase(":memory:"); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+	t.Setenv("HOME", t.TempDir())
+
+	for _, entry := range []db.TimesheetEntry{
+		{Date: "2024-03-04", Client_name: "Acme Corp", Client_hours: 5},
+		{Date: "2024-03-04", Client_name: "Globex", Client_hours: 3},
+		{Date: "2024-03-05", Client_name: "Acme Corp", Client_hours: 8},
+	} {
+		if err := db.AddTimesheetEntry(entry); err != nil {
+			t.Fatalf("AddTimesheetEntry failed: %v", err)
+		}
+	}
+
+	m := InitialTimesheetModelForMonth(2024, time.March, "2024-03-04")
+	send := func(msg tea.Msg) {
+		t.Helper()
+		updated, _ := m.Update(msg)
+		m = updated.(TimesheetModel)
+	}
+	press := func(key string) {
+		t.Helper()
+		send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+
+	// The second client of a day is a sub-row under the date
+	rows := m.table.Rows()
+	if len(rows) != 32 {
+		t.Fatalf("Expected 31 days and a sub-row, got %d rows", len(rows))
+	}
+	if rows[3][2] != "Acme Corp" || rows[4][0] != "2024-03-04" || rows[4][1] != clientSubRowMarker || rows[4][2] != "Globex" {
+		t.Errorf("Expected Globex as a sub-row of 2024-03-04, got %v and %v", rows[3], rows[4])
+	}
+	if got := m.columnTotals["clientHours"]; got != 16 {
+		t.Errorf("Expected 16 client hours in the totals, got %v", got)
+	}
+
+	// Clearing the sub-row only removes Globex's block
+	m.table.SetCursor(4)
+	press("c")
+	send(ChangeMonthMsg{Year: 2024, Month: time.March, Preserve: true})
+	entries, _ := db.GetTimesheetEntriesByDate("2024-03-04")
+	if len(entries) != 1 || entries[0].Client_name != "Acme Corp" {
+		t.Fatalf("Expected only Acme Corp left on 2024-03-04, got %+v", entries)
+	}
+
+	// Pasting another client onto a day adds a block; the same client
+	// replaces its hours
+	m.table.SetCursor(4) // 2024-03-05
+	press("y")
+	if m.yankedEntry == nil || m.yankedEntry.ClientHours != 8 {
+		t.Fatalf("Expected 2024-03-05's entry to be yanked, got %+v", m.yankedEntry)
+	}
+	m.yankedEntry.ClientName, m.yankedEntry.ClientHours = "Globex", 2
+	m.table.SetCursor(3) // 2024-03-04
+	press("p")
+	m.yankedEntry.ClientName, m.yankedEntry.ClientHours = "Acme Corp", 6
+	press("p")
+	entries, _ = db.GetTimesheetEntriesByDate("2024-03-04")
+	if len(entries) != 2 || entries[0].Client_hours != 6 || entries[1].Client_name != "Globex" || entries[1].Client_hours != 2 {
+		t.Errorf("Expected Acme Corp with 6 and Globex with 2 hours, got %+v", entries)
+	}
+}
+
+func TestMonthWeekTotals(t *testing.T) {
+	entries := []db.TimesheetEntry{
+		{Date: "2024-03-01", Client_hours: 8, Total_hours: 8},
+		{Date: "2024-03-04", Client_hours: 5, Total_hours: 5},
+		{Date: "2024-03-04", Client_hours: 3, Total_hours: 3},
+		{Date: "2024-03-05", Vacation_hours: 9, Total_hours: 9},
+		{Date: "2024-03-31", Sick_hours: 4, Total_hours: 4},
+	}
+
+	// 1 March 2024 is a Friday, so the first and last weeks are cut off by
+	// the month
+	weeks := monthWeekTotals(2024, time.March, entries, time.Monday, workschedule.Default())
+	if len(weeks) != 5 {
+		t.Fatalf("Expected 5 weeks, got %+v", weeks)
+	}
+	want := []struct {
+		week, first, last int
+		total             float64
+		expected          int
+	}{
+		{9, 1, 3, 8, 9},
+		{10, 4, 10, 17, 36},
+		{11, 11, 17, 0, 36},
+		{12, 18, 24, 0, 36},
+		{13, 25, 31, 4, 36},
+	}
+	for i, w := range want {
+		got := weeks[i]
+		if got.Week != w.week || got.FirstDay != w.first || got.LastDay != w.last || got.TotalHours != w.total || got.ExpectedHours != w.expected {
+			t.Errorf("week %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if weeks[1].ClientHours != 8 || weeks[1].VacationHours != 9 {
+		t.Errorf("Expected 8 client and 9 vacation hours in week 10, got %+v", weeks[1])
+	}
+
+	// With Sunday as the first day the 31st starts a week of its own
+	weeks = monthWeekTotals(2024, time.March, entries, time.Sunday, workschedule.Default())
+	if last := weeks[len(weeks)-1]; last.FirstDay != 31 || last.Week != 14 || last.SickHours != 4 {
+		t.Errorf("Expected the 31st to start week 14, got %+v", last)
+	}
+}
+
+func TestTimesheetDeleteMonth(t *testing.T) {
+	if err := db.InitializeDatabase(":memory:"); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+	t.Setenv("HOME", t.TempDir())
+
+	for _, entry := range []db.TimesheetEntry{
+		{Date: "2024-03-04", Client_name: "Acme Corp", Client_hours: 8},
+		{Date: "2024-03-05", Client_name: "Acme Corp", Client_hours: 8},
+		{Date: "2024-04-01", Client_name: "Acme Corp", Client_hours: 8},
+	} {
+		if err := db.AddTimesheetEntry(entry); err != nil {
+			t.Fatalf("AddTimesheetEntry failed: %v", err)
+		}
+	}
+
+	m := InitialTimesheetModelForMonth(2024, time.March, "2024-03-04")
+	press := func(key string) {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		m = updated.(TimesheetModel)
+	}
+	monthEntries := func(month time.Month) int {
+		t.Helper()
+		entries, err := db.GetAllTimesheetEntries(2024, month)
+		if err != nil {
+			t.Fatalf("GetAllTimesheetEntries failed: %v", err)
+		}
+		return len(entries)
+	}
+
+	// Any key but y cancels
+	press("D")
+	if !m.IsEditing() {
+		t.Fatal("Expected the prompt to take the keys after D")
+	}
+	press("n")
+	if m.IsEditing() || monthEntries(time.March) != 2 {
+		t.Fatalf("Expected n to cancel, got %d entries", monthEntries(time.March))
+	}
+
+	press("D")
+	press("y")
+	if got := monthEntries(time.March); got != 0 {
+		t.Errorf("Expected March to be deleted, got %d entries", got)
+	}
+	if got := monthEntries(time.April); got != 1 {
+		t.Errorf("Expected April to be kept, got %d entries", got)
+	}
+}
+
+func TestTimesheetRepeatDay(t *testing.T) {
+	if err := db.InitializeDatabase(":memory:"); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+	t.Setenv("HOME", t.TempDir())
+
+	if err := db.AddTimesheetEntry(db.TimesheetEntry{Date: "2024-03-04", Client_name: "Acme Corp", Client_hours: 6, Training_hours: 2, Total_hours: 8, Note: "standup"}); err != nil {
+		t.Fatalf("AddTimesheetEntry failed: %v", err)
+	}
+	press := func(m TimesheetModel, key string) TimesheetModel {
+		t.Helper()
+		updated, _ := m.Update(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+		return updated.(TimesheetModel)
+	}
+
+	press(InitialTimesheetModelForMonth(2024, time.March, "2024-03-05"), ".")
+	entries, err := db.GetTimesheetEntriesByDate("2024-03-05")
+	if err != nil {
+		t.Fatalf("GetTimesheetEntriesByDate failed: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("Expected the previous day to be repeated, got %d entries", len(entries))
+	}
+	got := entries[0]
+	if got.Client_name != "Acme Corp" || got.Client_hours != 6 || got.Training_hours != 2 || got.Note != "standup" {
+		t.Errorf("Repeated entry differs from the previous day: %+v", got)
+	}
+
+	// The 6th is empty, so repeating it onto the 7th does nothing
+	press(InitialTimesheetModelForMonth(2024, time.March, "2024-03-07"), ".")
+	entries, err = db.GetTimesheetEntriesByDate("2024-03-07")
+	if err != nil {
+		t.Fatalf("GetTimesheetEntriesByDate failed: %v", err)
+	}
+	if len(entries) != 0 {
+		t.Errorf("Expected no entry after repeating an empty day, got %d", len(entries))
+	}
+}
+
+func TestTimesheetRangeFill(t *testing.T) {
+	if err := db.InitializeDatabase(":memory:"); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+	t.Setenv("HOME", t.TempDir())
+
+	// 2024-03-08 is a Friday
+	m := InitialTimesheetModelForMonth(2024, time.March, "2024-03-08")
+	send := func(msg tea.Msg) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(msg)
+		m = updated.(TimesheetModel)
+		return cmd
+	}
+	press := func(key string) tea.Cmd {
+		t.Helper()
+		return send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune(key)})
+	}
+
+	press("R")
+	for range 4 {
+		press("j")
+	}
+	press("R")
+	if !m.IsEditing() {
+		t.Fatal("Expected the range fill template after the second R")
+	}
+	send(tea.KeyMsg{Type: tea.KeyTab})
+	cmd := send(tea.KeyMsg{Type: tea.KeyEnter})
+	if cmd == nil {
+		t.Fatal("Expected enter to fill the range")
+	}
+	send(cmd())
+
+	want := map[string]float64{"2024-03-08": 8, "2024-03-09": 0, "2024-03-10": 0, "2024-03-11": 8, "2024-03-12": 8}
+	for date, hours := range want {
+		entries, err := db.GetTimesheetEntriesByDate(date)
+		if err != nil {
+			t.Fatalf("GetTimesheetEntriesByDate failed: %v", err)
+		}
+		var got float64
+		for _, entry := range entries {
+			got += entry.Sick_hours
+		}
+		if got != hours {
+			t.Errorf("%s: sick hours = %g, want %g", date, got, hours)
+		}
+	}
+
+	// Esc drops a range that was started
+	press("R")
+	send(tea.KeyMsg{Type: tea.KeyEsc})
+	if m.rangeStart != "" {
+		t.Errorf("Expected esc to cancel the range, still starting at %s", m.rangeStart)
+	}
+}
+
+func TestTimesheetEmailConfirm(t *testing.T) {
+	if err := db.InitializeDatabase(":memory:"); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+	t.Setenv("HOME", t.TempDir())
+	if err := config.SaveConfig(config.Config{RecipientEmail: "finance@example.com"}); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	m := InitialTimesheetModelForMonth(2024, time.March, "2024-03-04")
+	send := func(msg tea.Msg) tea.Cmd {
+		t.Helper()
+		updated, cmd := m.Update(msg)
+		m = updated.(TimesheetModel)
+		return cmd
+	}
+
+	// Without an API key there's nothing to confirm
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	if m.IsEditing() {
+		t.Fatal("Expected S to only report the missing email setup")
+	}
+
+	if err := config.SaveConfig(config.Config{RecipientEmail: "finance@example.com", ResendAPIKey: "re_test"}); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	if !m.IsEditing() {
+		t.Fatal("Expected S to open the confirmation")
+	}
+	if !strings.Contains(m.View(), "finance@example.com") {
+		t.Error("Expected the confirmation to show the recipient")
+	}
+
+	// Enter isn't a yes
+	if cmd := send(tea.KeyMsg{Type: tea.KeyEnter}); cmd != nil || !m.IsEditing() {
+		t.Fatal("Expected enter to leave the confirmation open")
+	}
+
+	cmd := send(tea.KeyMsg{Type: tea.KeyEsc})
+	if cmd == nil {
+		t.Fatal("Expected esc to cancel")
+	}
+	msg := cmd()
+	if _, ok := msg.(EmailCancelledMsg); !ok {
+		t.Fatalf("Expected EmailCancelledMsg, got %T", msg)
+	}
+	send(msg)
+	if m.IsEditing() {
+		t.Error("Expected the confirmation to close after esc")
+	}
+
+	send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("S")})
+	cmd = send(tea.KeyMsg{Type: tea.KeyRunes, Runes: []rune("y")})
+	if cmd == nil {
+		t.Fatal("Expected y to confirm")
+	}
+	if confirmed, ok := cmd().(EmailConfirmedMsg); !ok || confirmed.Year != 2024 || confirmed.Month != time.March {
+		t.Errorf("Expected EmailConfirmedMsg for March 2024, got %#v", confirmed)
+	}
+}
@@ -0,0 +1,111 @@
+package ui
+
+import (
+	"fmt"
+	"strings"
+	"time"
+	"timesheet/internal/db"
+	"timesheet/internal/utils"
+	"timesheet/internal/workschedule"
+
+	"github.com/charmbracelet/lipgloss"
+)
+
+// weekTotal sums the hours of one week of the displayed month. A week split
+// across months only covers its days within the month.
+type weekTotal struct {
+	Week          int // ISO week number
+	FirstDay      int // First and last day of the month in the week
+	LastDay       int
+	ClientHours   float64
+	TrainingHours float64
+	VacationHours float64
+	IdleHours     float64
+	HolidayHours  float64
+	SickHours     float64
+	TotalHours    float64
+	ExpectedHours int // The work schedule's hours for the week's days
+}
+
+// monthWeekTotals groups a month's entries into weeks starting on weekStart
+func monthWeekTotals(year int, month time.Month, entries []db.TimesheetEntry, weekStart time.Weekday, schedule workschedule.Schedule) []weekTotal {
+	firstDay := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.Local)
+
+	var weeks []weekTotal
+	byDay := map[int]int{} // Day of the month to its index in weeks
+	for day := firstDay; !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		if len(weeks) == 0 || day.Weekday() == weekStart {
+			// The ISO week is the one holding the middle day of the full week
+			start := day.AddDate(0, 0, -int((day.Weekday()-weekStart+7)%7))
+			_, week := start.AddDate(0, 0, 3).ISOWeek()
+			weeks = append(weeks, weekTotal{Week: week, FirstDay: day.Day()})
+		}
+		current := &weeks[len(weeks)-1]
+		current.LastDay = day.Day()
+		current.ExpectedHours += schedule[day.Weekday()]
+		byDay[day.Day()] = len(weeks) - 1
+	}
+
+	for _, entry := range entries {
+		date, err := time.Parse("2006-01-02", entry.Date)
+		if err != nil || date.Year() != year || date.Month() != month {
+			continue
+		}
+		week := &weeks[byDay[date.Day()]]
+		week.ClientHours += entry.Client_hours
+		week.TrainingHours += entry.Training_hours
+		week.VacationHours += entry.Vacation_hours
+		week.IdleHours += entry.Idle_hours
+		week.HolidayHours += entry.Holiday_hours
+		week.SickHours += entry.Sick_hours
+		week.TotalHours += entry.Total_hours
+	}
+	return weeks
+}
+
+// renderWeekTotals renders the weekly subtotals shown beside the month table
+func renderWeekTotals(weeks []weekTotal) string {
+	headers := []string{"Week", "Days", "Hours", "Train", "Vac", "Idle", "Hol", "Sick", "Total", "Exp"}
+	rows := [][]string{headers}
+	for _, week := range weeks {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", week.Week),
+			fmt.Sprintf("%02d-%02d", week.FirstDay, week.LastDay),
+			utils.FormatHours(week.ClientHours),
+			utils.FormatHours(week.TrainingHours),
+			utils.FormatHours(week.VacationHours),
+			utils.FormatHours(week.IdleHours),
+			utils.FormatHours(week.HolidayHours),
+			utils.FormatHours(week.SickHours),
+			utils.FormatHours(week.TotalHours),
+			fmt.Sprintf("%d", week.ExpectedHours),
+		})
+	}
+
+	widths := make([]int, len(headers))
+	for _, row := range rows {
+		for i, cell := range row {
+			widths[i] = max(widths[i], lipgloss.Width(cell))
+		}
+	}
+
+	lines := []string{lipgloss.NewStyle().Foreground(lipgloss.Color("86")).Render("Weekly totals"), ""}
+	for r, row := range rows {
+		cells := make([]string, len(row))
+		for i, cell := range row {
+			cells[i] = lipgloss.NewStyle().Width(widths[i]).Align(lipgloss.Right).Render(cell)
+		}
+		line := strings.Join(cells, " ")
+		if r == 0 {
+			line = lipgloss.NewStyle().Foreground(lipgloss.Color("240")).Render(line)
+		}
+		lines = append(lines, line)
+	}
+
+	return lipgloss.NewStyle().
+		BorderStyle(lipgloss.RoundedBorder()).
+		BorderForeground(lipgloss.Color("62")).
+		Padding(0, 1).
+		Render(strings.Join(lines, "\n"))
+}
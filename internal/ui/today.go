@@ -0,0 +1,21 @@
+package ui
+
+import (
+	"time"
+	"timesheet/internal/config"
+)
+
+// entryDay returns the day work done at now belongs to. Before boundaryHour
+// it's still the previous day, so late work is logged on the day it started.
+func entryDay(now time.Time, boundaryHour int) time.Time {
+	if now.Hour() < boundaryHour {
+		return now.AddDate(0, 0, -1)
+	}
+	return now
+}
+
+// entryToday returns the date new entries default to (YYYY-MM-DD), taking
+// the configured dayBoundaryHour into account
+func entryToday() string {
+	return entryDay(time.Now(), config.GetDayBoundaryHour()).Format("2006-01-02")
+}
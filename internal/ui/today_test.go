@@ -0,0 +1,27 @@
+package ui
+
+import (
+	"testing"
+	"time"
+)
+
+func TestEntryDay(t *testing.T) {
+	tests := []struct {
+		name     string
+		now      time.Time
+		boundary int
+		want     string
+	}{
+		{"midnight boundary", time.Date(2024, 3, 5, 0, 30, 0, 0, time.Local), 0, "2024-03-05"},
+		{"before boundary", time.Date(2024, 3, 5, 1, 30, 0, 0, time.Local), 4, "2024-03-04"},
+		{"at boundary", time.Date(2024, 3, 5, 4, 0, 0, 0, time.Local), 4, "2024-03-05"},
+		{"across a month", time.Date(2024, 3, 1, 2, 0, 0, 0, time.Local), 4, "2024-02-29"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := entryDay(tt.now, tt.boundary).Format("2006-01-02"); got != tt.want {
+				t.Errorf("entryDay() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
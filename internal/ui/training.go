@@ -6,6 +6,7 @@ import (
 	"timesheet/internal/config"
 	"timesheet/internal/datalayer"
 	"timesheet/internal/db"
+	"timesheet/internal/utils"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
@@ -190,11 +191,11 @@ func InitialTrainingModel() TrainingModel {
 
 	// Convert entries to table rows
 	var rows []table.Row
-	var totalHours int
+	var totalHours float64
 	for _, entry := range entries {
 		rows = append(rows, table.Row{
 			entry.Date,
-			fmt.Sprintf("%d", entry.Training_hours),
+			utils.FormatHours(entry.Training_hours),
 		})
 		totalHours += entry.Training_hours
 	}
@@ -202,7 +203,7 @@ func InitialTrainingModel() TrainingModel {
 	// Add total row
 	rows = append(rows, table.Row{
 		"Total",
-		fmt.Sprintf("%d/%d", totalHours, configFile.TrainingHours.YearlyTarget),
+		fmt.Sprintf("%s/%d", utils.FormatHours(totalHours), configFile.TrainingHours.YearlyTarget),
 	})
 
 	t.SetRows(rows)
@@ -245,11 +246,11 @@ func (m TrainingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 
 		// Convert entries to table rows
 		var rows []table.Row
-		var totalHours int
+		var totalHours float64
 		for _, entry := range entries {
 			rows = append(rows, table.Row{
 				entry.Date,
-				fmt.Sprintf("%d", entry.Training_hours),
+				utils.FormatHours(entry.Training_hours),
 			})
 			totalHours += entry.Training_hours
 		}
@@ -257,7 +258,7 @@ func (m TrainingModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		// Add total row
 		rows = append(rows, table.Row{
 			"Total",
-			fmt.Sprintf("%d/%d", totalHours, m.yearlyTarget),
+			fmt.Sprintf("%s/%d", utils.FormatHours(totalHours), m.yearlyTarget),
 		})
 
 		m.table.SetRows(rows)
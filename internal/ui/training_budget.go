@@ -345,7 +345,8 @@ func (m TrainingBudgetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 			if cursorPos < len(m.table.Rows())-1 { // Don't allow clearing the total row
 				// Use cursor position to get the entry ID from stored entries
 				if cursorPos >= 0 && cursorPos < len(m.entries) {
-					entryID := m.entries[cursorPos].Id
+					deleted := m.entries[cursorPos]
+					entryID := deleted.Id
 
 					// Delete the entry using its ID
 					dataLayer := datalayer.GetDataLayer()
@@ -395,7 +396,7 @@ func (m TrainingBudgetModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 						m.table.SetCursor(len(rows) - 1)
 					}
 
-					return m, TriggerSync()
+					return m, tea.Batch(PushUndo(trainingBudgetEntryUndo(deleted)), TriggerSync())
 				}
 			}
 		case key.Matches(msg, m.keys.Yank):
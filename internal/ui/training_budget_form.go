@@ -20,6 +20,7 @@ type TrainingBudgetFormModel struct {
 	err          error
 	isEditing    bool
 	entryID      int
+	receiptPath  string // Kept as-is on edit; receipts are attached through the API
 }
 
 func InitialTrainingBudgetFormModel() TrainingBudgetFormModel {
@@ -58,6 +59,7 @@ func InitialTrainingBudgetFormModelForEdit(entry db.TrainingBudgetEntry) Trainin
 	m := InitialTrainingBudgetFormModel()
 	m.isEditing = true
 	m.entryID = entry.Id
+	m.receiptPath = entry.Receipt_path
 
 	// Pre-fill the form fields
 	m.inputs[0].SetValue(entry.Date)
@@ -87,6 +89,7 @@ func (m TrainingBudgetFormModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				Training_name:    m.inputs[1].Value(),
 				Hours:            0,
 				Cost_without_vat: parseTrainingCost(m.inputs[2].Value()),
+				Receipt_path:     m.receiptPath,
 			}
 
 			dataLayer := datalayer.GetDataLayer()
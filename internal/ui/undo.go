@@ -0,0 +1,139 @@
+package ui
+
+import (
+	"fmt"
+	"timesheet/internal/datalayer"
+	"timesheet/internal/db"
+
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// maxUndoActions bounds the session undo stack so a long session doesn't
+// keep every deleted row in memory.
+const maxUndoActions = 20
+
+// UndoAction captures enough state to reverse a single destructive
+// operation (clear, paste-over, delete). Restore writes the previous state
+// back through the data layer.
+type UndoAction struct {
+	Description string
+	Restore     func() error
+}
+
+// PushUndoMsg asks the app model to remember an action so it can be undone
+// later with ctrl+z.
+type PushUndoMsg struct {
+	Action UndoAction
+}
+
+// PushUndo returns a command that records an undoable action
+func PushUndo(action UndoAction) tea.Cmd {
+	return func() tea.Msg {
+		return PushUndoMsg{Action: action}
+	}
+}
+
+// timesheetEntryUndo snapshots the entries stored for date, one per client,
+// and returns an action that puts them back. Whatever ends up on the date
+// in the meantime is removed, so undoing a paste onto an empty day leaves
+// it empty again. When the snapshot can't be read there's nothing to undo
+// to, so the caller should leave the day alone.
+func timesheetEntryUndo(description, date string) (UndoAction, error) {
+	previous, err := datalayer.GetDataLayer().GetTimesheetEntriesByDate(date)
+	if err != nil {
+		return UndoAction{}, fmt.Errorf("can't remember %s for undo: %w", date, err)
+	}
+
+	return UndoAction{
+		Description: description,
+		Restore: func() error {
+			dl := datalayer.GetDataLayer()
+			current, err := dl.GetTimesheetEntriesByDate(date)
+			if err != nil {
+				return fmt.Errorf("failed to read %s: %w", date, err)
+			}
+			if err := replaceDayEntries(dl, date, previous); err != nil {
+				// Put back what the day held before the undo started
+				if rbErr := replaceDayEntries(dl, date, current); rbErr != nil {
+					return fmt.Errorf("%w; putting %s back also failed: %v", err, date, rbErr)
+				}
+				return fmt.Errorf("%w; %s is unchanged", err, date)
+			}
+			return nil
+		},
+	}, nil
+}
+
+// replaceDayEntries swaps the entries of date for entries, stopping at the
+// first one that can't be written
+func replaceDayEntries(dl db.DataLayer, date string, entries []db.TimesheetEntry) error {
+	if err := dl.DeleteTimesheetEntryByDate(date); err != nil {
+		return err
+	}
+	for _, entry := range entries {
+		if err := dl.AddTimesheetEntry(entry); err != nil {
+			return fmt.Errorf("failed to restore %s on %s: %w", entry.Client_name, date, err)
+		}
+	}
+	return nil
+}
+
+// restoreDatesUndo snapshots every date of entries (see timesheetEntryUndo)
+// and returns an action that puts them all back
+func restoreDatesUndo(description string, entries []db.TimesheetEntry) (UndoAction, error) {
+	var undos []UndoAction
+	seen := map[string]bool{}
+	for _, entry := range entries {
+		if seen[entry.Date] {
+			continue
+		}
+		seen[entry.Date] = true
+		undo, err := timesheetEntryUndo("", entry.Date)
+		if err != nil {
+			return UndoAction{}, err
+		}
+		undos = append(undos, undo)
+	}
+	return UndoAction{
+		Description: description,
+		Restore: func() error {
+			for _, u := range undos {
+				if err := u.Restore(); err != nil {
+					return err
+				}
+			}
+			return nil
+		},
+	}, nil
+}
+
+// trainingBudgetEntryUndo returns an action that re-adds a deleted training
+// budget entry. The restored row gets a new ID.
+func trainingBudgetEntryUndo(entry db.TrainingBudgetEntry) UndoAction {
+	return UndoAction{
+		Description: "delete training " + entry.Training_name,
+		Restore: func() error {
+			return datalayer.GetDataLayer().AddTrainingBudgetEntry(entry)
+		},
+	}
+}
+
+// bufferEntryUndo returns an action that re-creates a deleted buffer entry
+func bufferEntryUndo(entry db.BufferEntry) UndoAction {
+	return UndoAction{
+		Description: "delete buffer entry",
+		Restore: func() error {
+			return datalayer.GetDataLayer().UpsertBufferEntry(entry)
+		},
+	}
+}
+
+// clientRateUndo returns an action that re-adds a deleted client rate
+func clientRateUndo(rate db.ClientRate) UndoAction {
+	return UndoAction{
+		Description: "delete rate " + rate.EffectiveDate,
+		Restore: func() error {
+			return datalayer.GetDataLayer().AddClientRate(rate)
+		},
+	}
+}
@@ -0,0 +1,68 @@
+package ui
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"timesheet/internal/config"
+	"timesheet/internal/db"
+)
+
+func TestTimesheetEntryUndoNeedsSnapshot(t *testing.T) {
+	if err := db.InitializeDatabase(":memory:"); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+
+	// Without a snapshot the undo would clear the day, so none is given
+	db.Close()
+	if _, err := timesheetEntryUndo("clear 2024-03-04", "2024-03-04"); err == nil {
+		t.Error("Expected an error when the day can't be read")
+	}
+}
+
+func TestTimesheetEntryUndoRestoreFailure(t *testing.T) {
+	if err := db.InitializeDatabase(":memory:"); err != nil {
+		t.Fatalf("Failed to initialize database: %v", err)
+	}
+	defer db.Close()
+	config.SetConfigPathOverride(filepath.Join(t.TempDir(), "config.json"))
+	defer config.SetConfigPathOverride("")
+
+	const date = "2024-03-04"
+	if err := config.SaveConfig(config.Config{OverLimitBehavior: config.OverLimitAllow}); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+	for _, entry := range []db.TimesheetEntry{
+		{Date: date, Client_name: "Acme", Client_hours: 20},
+		{Date: date, Client_name: "Globex", Client_hours: 10},
+	} {
+		if err := db.AddTimesheetEntry(entry); err != nil {
+			t.Fatalf("AddTimesheetEntry failed: %v", err)
+		}
+	}
+	undo, err := timesheetEntryUndo("paste "+date, date)
+	if err != nil {
+		t.Fatalf("timesheetEntryUndo failed: %v", err)
+	}
+
+	// Paste over the day, then make the 30-hour day it held invalid
+	if err := replaceDayEntries(&db.LocalDBLayer{}, date, []db.TimesheetEntry{{Date: date, Client_name: "Initech", Client_hours: 4}}); err != nil {
+		t.Fatalf("replaceDayEntries failed: %v", err)
+	}
+	if err := config.SaveConfig(config.Config{OverLimitBehavior: config.OverLimitReject}); err != nil {
+		t.Fatalf("SaveConfig failed: %v", err)
+	}
+
+	if err := undo.Restore(); err == nil || !strings.Contains(err.Error(), "unchanged") {
+		t.Fatalf("Expected the restore to fail and leave the day unchanged, got %v", err)
+	}
+	entries, err := db.GetTimesheetEntriesByDate(date)
+	if err != nil {
+		t.Fatalf("GetTimesheetEntriesByDate failed: %v", err)
+	}
+	if len(entries) != 1 || entries[0].Client_name != "Initech" || entries[0].Client_hours != 4 {
+		t.Errorf("Expected the pasted day to be put back, got %+v", entries)
+	}
+}
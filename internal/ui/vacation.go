@@ -7,6 +7,7 @@ import (
 	"timesheet/internal/config"
 	"timesheet/internal/datalayer"
 	"timesheet/internal/db"
+	"timesheet/internal/utils"
 
 	"github.com/charmbracelet/bubbles/help"
 	"github.com/charmbracelet/bubbles/key"
@@ -199,14 +200,14 @@ func InitialVacationModel() VacationModel {
 	for _, entry := range entries {
 		rows = append(rows, table.Row{
 			entry.Date,
-			fmt.Sprintf("%d", entry.Vacation_hours),
+			utils.FormatHours(entry.Vacation_hours),
 		})
 	}
 
 	// Add total row showing used hours and total available
 	rows = append(rows, table.Row{
 		"Total",
-		fmt.Sprintf("%d/%d", summary.UsedHours, summary.TotalAvailable),
+		utils.FormatHours(summary.UsedHours) + "/" + utils.FormatHours(summary.TotalAvailable),
 	})
 
 	t.SetRows(rows)
@@ -277,14 +278,14 @@ func (m VacationModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 		for _, entry := range entries {
 			rows = append(rows, table.Row{
 				entry.Date,
-				fmt.Sprintf("%d", entry.Vacation_hours),
+				utils.FormatHours(entry.Vacation_hours),
 			})
 		}
 
 		// Add total row showing used hours and total available
 		rows = append(rows, table.Row{
 			"Total",
-			fmt.Sprintf("%d/%d", m.summary.UsedHours, m.summary.TotalAvailable),
+			utils.FormatHours(m.summary.UsedHours) + "/" + utils.FormatHours(m.summary.TotalAvailable),
 		})
 
 		m.table.SetRows(rows)
@@ -370,23 +371,23 @@ func (m VacationModel) View() string {
 	bigStyle := lipgloss.NewStyle().Bold(true).Foreground(lipgloss.Color("78"))
 
 	var availLines []string
-	availLines = append(availLines, "  "+valueStyle.Render(fmt.Sprintf("Current Year (%d): %d hours", m.currentYear, m.summary.YearlyTarget)))
+	availLines = append(availLines, "  "+valueStyle.Render(fmt.Sprintf("Current Year (%d): %s hours", m.currentYear, utils.FormatHours(m.summary.YearlyTarget))))
 	if m.summary.CarryoverHours > 0 {
-		availLines = append(availLines, "  "+valueStyle.Render(fmt.Sprintf("Carryover from %d: %d hours", m.summary.Year-1, m.summary.CarryoverHours)))
+		availLines = append(availLines, "  "+valueStyle.Render(fmt.Sprintf("Carryover from %d: %s hours", m.summary.Year-1, utils.FormatHours(m.summary.CarryoverHours))))
 	}
 	if m.summary.BufferHours > 0 {
-		availLines = append(availLines, "  "+valueStyle.Render(fmt.Sprintf("Buffer banked: %d hours", m.summary.BufferHours)))
+		availLines = append(availLines, "  "+valueStyle.Render(fmt.Sprintf("Buffer banked: %s hours", utils.FormatHours(m.summary.BufferHours))))
 	}
 
 	var usedLines []string
 	if m.summary.UsedFromCarryover > 0 {
-		usedLines = append(usedLines, "  "+valueStyle.Render(fmt.Sprintf("From Carryover: %d hours", m.summary.UsedFromCarryover)))
+		usedLines = append(usedLines, "  "+valueStyle.Render(fmt.Sprintf("From Carryover: %s hours", utils.FormatHours(m.summary.UsedFromCarryover))))
 	}
 	if m.summary.UsedFromBuffer > 0 {
-		usedLines = append(usedLines, "  "+valueStyle.Render(fmt.Sprintf("From Buffer: %d hours", m.summary.UsedFromBuffer)))
+		usedLines = append(usedLines, "  "+valueStyle.Render(fmt.Sprintf("From Buffer: %s hours", utils.FormatHours(m.summary.UsedFromBuffer))))
 	}
 	if m.summary.UsedFromCurrent > 0 || len(usedLines) == 0 {
-		usedLines = append(usedLines, "  "+valueStyle.Render(fmt.Sprintf("From Current Year: %d hours", m.summary.UsedFromCurrent)))
+		usedLines = append(usedLines, "  "+valueStyle.Render(fmt.Sprintf("From Current Year: %s hours", utils.FormatHours(m.summary.UsedFromCurrent))))
 	}
 
 	summaryContent := fmt.Sprintf(
@@ -396,7 +397,7 @@ func (m VacationModel) View() string {
 		labelStyle.Render("Used:"),
 		strings.Join(usedLines, "\n"),
 		labelStyle.Render("Remaining:"),
-		bigStyle.Render(fmt.Sprintf("%d hours", m.summary.RemainingTotal)),
+		bigStyle.Render(fmt.Sprintf("%s hours", utils.FormatHours(m.summary.RemainingTotal))),
 	)
 
 	summaryBox := lipgloss.NewStyle().
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"fmt"
 	"net/http"
+	"os"
+	"path/filepath"
 	"strconv"
 	"strings"
 	"time"
@@ -12,6 +14,7 @@ import (
 // UpdateChecker checks for new releases on GitHub
 type UpdateChecker struct {
 	httpClient *http.Client
+	baseURL    string // GitHub API root, replaced in tests
 	repoOwner  string
 	repoName   string
 }
@@ -28,6 +31,7 @@ func NewUpdateChecker(owner, repo string) *UpdateChecker {
 		httpClient: &http.Client{
 			Timeout: 5 * time.Second, // Shorter timeout for non-critical operation
 		},
+		baseURL:   "https://api.github.com",
 		repoOwner: owner,
 		repoName:  repo,
 	}
@@ -36,8 +40,8 @@ func NewUpdateChecker(owner, repo string) *UpdateChecker {
 // CheckForUpdate queries GitHub for the latest release and compares it with the current version
 // Returns: latestVersion, updateAvailable, error
 func (uc *UpdateChecker) CheckForUpdate(currentVersion string) (string, bool, error) {
-	url := fmt.Sprintf("https://api.github.com/repos/%s/%s/releases/latest",
-		uc.repoOwner, uc.repoName)
+	url := fmt.Sprintf("%s/repos/%s/%s/releases/latest",
+		uc.baseURL, uc.repoOwner, uc.repoName)
 
 	req, err := http.NewRequest("GET", url, nil)
 	if err != nil {
@@ -73,6 +77,70 @@ func (uc *UpdateChecker) CheckForUpdate(currentVersion string) (string, bool, er
 	return release.TagName, updateAvailable, nil
 }
 
+// CacheTTL is how long the latest release found is reused before GitHub is
+// asked again
+const CacheTTL = 24 * time.Hour
+
+// cachedCheck is the result of the last successful check, stored as JSON
+type cachedCheck struct {
+	Latest    string    `json:"latest"`
+	CheckedAt time.Time `json:"checkedAt"`
+}
+
+// DefaultCachePath returns where checks are cached, next to the app state
+func DefaultCachePath() string {
+	homeDir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+	return filepath.Join(homeDir, ".config", "timesheetz", "update_check.json")
+}
+
+// CheckForUpdateCached is CheckForUpdate with the latest release kept in
+// cachePath for CacheTTL, so frequent startups don't run into GitHub's rate
+// limit. Failed checks aren't cached; an empty cachePath always asks GitHub.
+func (uc *UpdateChecker) CheckForUpdateCached(currentVersion, cachePath string) (string, bool, error) {
+	if cached, ok := readCachedCheck(cachePath); ok && time.Since(cached.CheckedAt) < CacheTTL {
+		return cached.Latest, compareVersions(currentVersion, cached.Latest), nil
+	}
+
+	latest, available, err := uc.CheckForUpdate(currentVersion)
+	if err != nil {
+		return "", false, err
+	}
+	writeCachedCheck(cachePath, cachedCheck{Latest: latest, CheckedAt: time.Now()})
+	return latest, available, nil
+}
+
+// readCachedCheck loads the cached check, if there is a usable one
+func readCachedCheck(cachePath string) (cachedCheck, bool) {
+	var cached cachedCheck
+	if cachePath == "" {
+		return cached, false
+	}
+	data, err := os.ReadFile(cachePath)
+	if err != nil || json.Unmarshal(data, &cached) != nil || cached.Latest == "" {
+		return cached, false
+	}
+	return cached, true
+}
+
+// writeCachedCheck stores a check; failing to do so only means the next
+// check asks GitHub again
+func writeCachedCheck(cachePath string, cached cachedCheck) {
+	if cachePath == "" {
+		return
+	}
+	data, err := json.Marshal(cached)
+	if err != nil {
+		return
+	}
+	if err := os.MkdirAll(filepath.Dir(cachePath), 0755); err != nil {
+		return
+	}
+	os.WriteFile(cachePath, data, 0644)
+}
+
 // compareVersions returns true if latest > current
 func compareVersions(current, latest string) bool {
 	// Remove 'v' prefix if present
@@ -4,7 +4,10 @@ import (
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestCompareVersions(t *testing.T) {
@@ -201,3 +204,55 @@ func TestNewUpdateChecker(t *testing.T) {
 		t.Error("httpClient timeout not set")
 	}
 }
+
+func TestCheckForUpdateCached(t *testing.T) {
+	requests := 0
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		json.NewEncoder(w).Encode(GitHubRelease{TagName: "v1.10.0"})
+	}))
+	defer ts.Close()
+
+	checker := NewUpdateChecker("owner", "repo")
+	checker.baseURL = ts.URL
+	cachePath := filepath.Join(t.TempDir(), "update_check.json")
+
+	for _, current := range []string{"v1.9.0", "v1.10.0"} {
+		latest, available, err := checker.CheckForUpdateCached(current, cachePath)
+		if err != nil {
+			t.Fatalf("CheckForUpdateCached(%q) failed: %v", current, err)
+		}
+		if latest != "v1.10.0" || available != (current == "v1.9.0") {
+			t.Errorf("CheckForUpdateCached(%q) = %q, %v", current, latest, available)
+		}
+	}
+	if requests != 1 {
+		t.Errorf("Expected the second check to use the cache, got %d requests", requests)
+	}
+
+	// A check older than a day asks GitHub again
+	stale := cachedCheck{Latest: "v1.9.0", CheckedAt: time.Now().Add(-CacheTTL - time.Minute)}
+	writeCachedCheck(cachePath, stale)
+	if latest, _, err := checker.CheckForUpdateCached("v1.9.0", cachePath); err != nil || latest != "v1.10.0" {
+		t.Errorf("Expected a stale cache to be refreshed, got %q, %v", latest, err)
+	}
+	if requests != 2 {
+		t.Errorf("Expected 2 requests, got %d", requests)
+	}
+}
+
+func TestCheckForUpdateCachedOffline(t *testing.T) {
+	ts := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	ts.Close() // Nothing listens any more
+
+	checker := NewUpdateChecker("owner", "repo")
+	checker.baseURL = ts.URL
+	cachePath := filepath.Join(t.TempDir(), "update_check.json")
+
+	if _, _, err := checker.CheckForUpdateCached("v1.9.0", cachePath); err == nil {
+		t.Fatal("Expected an error when GitHub can't be reached")
+	}
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Errorf("Expected a failed check not to be cached, got %v", err)
+	}
+}
@@ -0,0 +1,100 @@
+package utils
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Anonymize mode replaces client names with pseudonyms ("Client A",
+// "Client B", ...) wherever they are displayed or exported, so screenshots
+// and demos don't reveal who the work was for. Stored data is never changed.
+var (
+	anonymizeMu sync.Mutex
+	anonymizeOn bool
+	pseudonyms  = map[string]string{} // real name -> pseudonym
+	realNames   = map[string]string{} // pseudonym -> real name
+)
+
+// SetAnonymize turns anonymize mode on or off. known is the list of client
+// names to assign pseudonyms to up front; they are handed out in
+// alphabetical order so a client keeps the same pseudonym across runs as
+// long as the client list doesn't change. Names not in known get the next
+// free pseudonym the first time they are shown.
+func SetAnonymize(enabled bool, known []string) {
+	anonymizeMu.Lock()
+	defer anonymizeMu.Unlock()
+
+	anonymizeOn = enabled
+	pseudonyms = map[string]string{}
+	realNames = map[string]string{}
+
+	names := append([]string(nil), known...)
+	sort.Strings(names)
+	for _, name := range names {
+		assignPseudonym(name)
+	}
+}
+
+// Anonymizing reports whether anonymize mode is on
+func Anonymizing() bool {
+	anonymizeMu.Lock()
+	defer anonymizeMu.Unlock()
+	return anonymizeOn
+}
+
+// ClientLabel returns the name to display for a client: the name itself,
+// or its pseudonym in anonymize mode. Empty names and the "-" placeholder
+// are returned unchanged.
+func ClientLabel(name string) string {
+	anonymizeMu.Lock()
+	defer anonymizeMu.Unlock()
+
+	if !anonymizeOn || isPlaceholderName(name) {
+		return name
+	}
+	return assignPseudonym(name)
+}
+
+// RealClientName maps a label returned by ClientLabel back to the client
+// name it stands for, so values read back from the screen can be saved
+func RealClientName(label string) string {
+	anonymizeMu.Lock()
+	defer anonymizeMu.Unlock()
+
+	if real, ok := realNames[label]; ok {
+		return real
+	}
+	return label
+}
+
+// assignPseudonym returns the pseudonym for name, allocating the next one
+// if needed. The caller holds anonymizeMu.
+func assignPseudonym(name string) string {
+	if isPlaceholderName(name) {
+		return name
+	}
+	if pseudonym, ok := pseudonyms[name]; ok {
+		return pseudonym
+	}
+	pseudonym := "Client " + pseudonymLetters(len(pseudonyms))
+	pseudonyms[name] = pseudonym
+	realNames[pseudonym] = name
+	return pseudonym
+}
+
+// pseudonymLetters numbers pseudonyms like spreadsheet columns:
+// 0 -> A, 25 -> Z, 26 -> AA
+func pseudonymLetters(n int) string {
+	letters := ""
+	for n >= 0 {
+		letters = string(rune('A'+n%26)) + letters
+		n = n/26 - 1
+	}
+	return letters
+}
+
+func isPlaceholderName(name string) bool {
+	trimmed := strings.TrimSpace(name)
+	return trimmed == "" || trimmed == "-"
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestClientLabel(t *testing.T) {
+	defer SetAnonymize(false, nil)
+
+	SetAnonymize(false, []string{"Acme"})
+	if got := ClientLabel("Acme"); got != "Acme" {
+		t.Errorf("ClientLabel with anonymize off = %v, want Acme", got)
+	}
+
+	SetAnonymize(true, []string{"Globex", "Acme"})
+	tests := []struct {
+		name     string
+		expected string
+	}{
+		{"Acme", "Client A"},
+		{"Globex", "Client B"},
+		{"Initech", "Client C"},
+		{"Acme", "Client A"},
+		{"-", "-"},
+		{"", ""},
+	}
+	for _, tt := range tests {
+		if got := ClientLabel(tt.name); got != tt.expected {
+			t.Errorf("ClientLabel(%q) = %v, want %v", tt.name, got, tt.expected)
+		}
+	}
+
+	if got := RealClientName("Client C"); got != "Initech" {
+		t.Errorf("RealClientName(Client C) = %v, want Initech", got)
+	}
+	if got := RealClientName("Acme"); got != "Acme" {
+		t.Errorf("RealClientName(Acme) = %v, want Acme", got)
+	}
+}
+
+func TestPseudonymLetters(t *testing.T) {
+	tests := map[int]string{0: "A", 25: "Z", 26: "AA", 27: "AB", 701: "ZZ", 702: "AAA"}
+	for n, expected := range tests {
+		if got := pseudonymLetters(n); got != expected {
+			t.Errorf("pseudonymLetters(%d) = %v, want %v", n, got, expected)
+		}
+	}
+}
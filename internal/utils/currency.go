@@ -4,16 +4,155 @@ import (
 	"fmt"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
+// MoneyFormat describes how an amount is rendered as text
+type MoneyFormat struct {
+	Symbol       string // Prefix such as "€"; empty for plain numbers (CSV)
+	Decimals     int    // Number of digits after the decimal separator
+	DecimalSep   string // Separator between whole and fractional part
+	ThousandsSep string // Grouping separator every three digits; empty disables grouping
+	SignFirst    bool   // Put a minus sign before the symbol ("-$5.00") instead of after it
+}
+
+// DefaultMoneyFormat is the Euro format used throughout the app and API
+// Example: 100.5 -> "€100,50"
+var DefaultMoneyFormat = MoneyFormat{
+	Symbol:     "€",
+	Decimals:   2,
+	DecimalSep: ",",
+}
+
+// CurrencySymbol returns the prefix for amounts in an ISO 4217 currency:
+// "€", "$" or "£" for EUR, USD and GBP, otherwise the code and a space
+func CurrencySymbol(code string) string {
+	switch code {
+	case "", "EUR":
+		return "€"
+	case "USD":
+		return "$"
+	case "GBP":
+		return "£"
+	default:
+		return code + " "
+	}
+}
+
+// currencyFormats are the conventions of the currencies with a symbol. EUR
+// keeps the app's long-standing format without grouping.
+var currencyFormats = map[string]MoneyFormat{
+	"EUR": DefaultMoneyFormat,
+	"USD": {Symbol: "$", Decimals: 2, DecimalSep: ".", ThousandsSep: ",", SignFirst: true},
+	"GBP": {Symbol: "£", Decimals: 2, DecimalSep: ".", ThousandsSep: ",", SignFirst: true},
+}
+
+// MoneyFormatFor returns the format for amounts in currency code: its own
+// symbol, separators and sign placement for EUR, USD and GBP (e.g.
+// "$1,234.50"), otherwise the default format prefixed with the code. An
+// empty code is EUR.
+func MoneyFormatFor(code string) MoneyFormat {
+	code = strings.ToUpper(strings.TrimSpace(code))
+	if code == "" {
+		code = "EUR"
+	}
+	if f, ok := currencyFormats[code]; ok {
+		return f
+	}
+	f := DefaultMoneyFormat
+	f.Symbol = CurrencySymbol(code)
+	return f
+}
+
+// FormatMoney formats an amount using the given format
+// Example: FormatMoney(1234.5, MoneyFormat{Decimals: 2, DecimalSep: ".", ThousandsSep: ","}) -> "1,234.50"
+func FormatMoney(amount float64, f MoneyFormat) string {
+	decimals := f.Decimals
+	if decimals < 0 {
+		decimals = 0
+	}
+	formatted := strconv.FormatFloat(amount, 'f', decimals, 64)
+
+	sign := ""
+	if strings.HasPrefix(formatted, "-") {
+		sign = "-"
+		formatted = formatted[1:]
+	}
+
+	whole, frac, _ := strings.Cut(formatted, ".")
+	if f.ThousandsSep != "" {
+		whole = groupThousands(whole, f.ThousandsSep)
+	}
+
+	result := whole
+	if frac != "" {
+		result += f.DecimalSep + frac
+	}
+	if f.SignFirst {
+		return sign + f.Symbol + result
+	}
+	return f.Symbol + sign + result
+}
+
+// groupThousands inserts sep between every group of three digits
+func groupThousands(digits, sep string) string {
+	if len(digits) <= 3 {
+		return digits
+	}
+	var b strings.Builder
+	head := len(digits) % 3
+	if head > 0 {
+		b.WriteString(digits[:head])
+	}
+	for i := head; i < len(digits); i += 3 {
+		if b.Len() > 0 {
+			b.WriteString(sep)
+		}
+		b.WriteString(digits[i : i+3])
+	}
+	return b.String()
+}
+
 // FormatEuro formats a float as Euro currency
 // Example: 100.5 -> "€100,50"
 func FormatEuro(amount float64) string {
-	// Format with 2 decimal places
-	formatted := fmt.Sprintf("%.2f", amount)
-	// Replace . with , for Euro format
-	formatted = strings.Replace(formatted, ".", ",", 1)
-	return "€" + formatted
+	return FormatMoney(amount, DefaultMoneyFormat)
+}
+
+// ParseMoney parses an amount written by FormatMoney in any currency's
+// format, e.g. "€1234,50", "$1,234.50", "-£5.00" or "CHF 80,00". The last
+// "." or "," is the decimal separator unless exactly three digits follow it,
+// in which case it groups thousands.
+func ParseMoney(s string) (float64, error) {
+	negative := strings.Contains(s, "-")
+	var digits strings.Builder
+	for _, r := range s {
+		if unicode.IsDigit(r) || r == '.' || r == ',' {
+			digits.WriteRune(r)
+		}
+	}
+	number := digits.String()
+	if number == "" {
+		return 0, fmt.Errorf("no amount in %q", s)
+	}
+
+	whole, frac := number, ""
+	if i := strings.LastIndexAny(number, ".,"); i >= 0 && len(number)-i-1 != 3 {
+		whole, frac = number[:i], number[i+1:]
+	}
+	whole = strings.NewReplacer(".", "", ",", "").Replace(whole)
+	if frac != "" {
+		whole += "." + frac
+	}
+
+	amount, err := strconv.ParseFloat(whole, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid amount %q", s)
+	}
+	if negative {
+		amount = -amount
+	}
+	return amount, nil
 }
 
 // ParseEuro parses a Euro-formatted string to float64
@@ -28,6 +28,32 @@ func TestFormatEuro(t *testing.T) {
 	}
 }
 
+func TestFormatMoney(t *testing.T) {
+	tests := []struct {
+		name     string
+		amount   float64
+		format   MoneyFormat
+		expected string
+	}{
+		{"default", 1234.5, DefaultMoneyFormat, "€1234,50"},
+		{"no symbol", 1234.5, MoneyFormat{Decimals: 2, DecimalSep: ","}, "1234,50"},
+		{"no decimals", 1234.6, MoneyFormat{Symbol: "€", Decimals: 0, DecimalSep: ","}, "€1235"},
+		{"grouping", 1234567.891, MoneyFormat{Symbol: "$", Decimals: 2, DecimalSep: ".", ThousandsSep: ","}, "$1,234,567.89"},
+		{"grouping exact thousands", 100000, MoneyFormat{Decimals: 0, ThousandsSep: "."}, "100.000"},
+		{"grouping negative", -1234.5, MoneyFormat{Symbol: "€", Decimals: 2, DecimalSep: ",", ThousandsSep: "."}, "€-1.234,50"},
+		{"three decimals", 1.2345, MoneyFormat{Decimals: 3, DecimalSep: "."}, "1.234"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			result := FormatMoney(tt.amount, tt.format)
+			if result != tt.expected {
+				t.Errorf("FormatMoney(%v) = %v, want %v", tt.amount, result, tt.expected)
+			}
+		})
+	}
+}
+
 func TestParseEuro(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -79,3 +105,62 @@ func TestFormatParseRoundtrip(t *testing.T) {
 		}
 	}
 }
+
+func TestMoneyFormatFor(t *testing.T) {
+	tests := []struct {
+		currency string
+		amount   float64
+		expected string
+	}{
+		{"", 1234.5, "€1234,50"},
+		{"EUR", 1234.5, "€1234,50"},
+		{"usd", 1234567.5, "$1,234,567.50"},
+		{"USD", -5, "-$5.00"},
+		{"GBP", 1234.5, "£1,234.50"},
+		{"CHF", 80, "CHF 80,00"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.currency, func(t *testing.T) {
+			if result := FormatMoney(tt.amount, MoneyFormatFor(tt.currency)); result != tt.expected {
+				t.Errorf("FormatMoney(%v, %q) = %v, want %v", tt.amount, tt.currency, result, tt.expected)
+			}
+		})
+	}
+}
+
+func TestParseMoney(t *testing.T) {
+	tests := []struct {
+		input     string
+		expected  float64
+		shouldErr bool
+	}{
+		{"€100,50", 100.50, false},
+		{"€-50,25", -50.25, false},
+		{"$1,234,567.50", 1234567.50, false},
+		{"-$5.00", -5, false},
+		{"£1,234.50", 1234.50, false},
+		{"CHF 80,00", 80, false},
+		{"€1.234", 1234, false},
+		{"€1235", 1235, false},
+		{"n/a", 0, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.input, func(t *testing.T) {
+			result, err := ParseMoney(tt.input)
+			if tt.shouldErr {
+				if err == nil {
+					t.Errorf("ParseMoney(%q) expected error, got nil", tt.input)
+				}
+				return
+			}
+			if err != nil {
+				t.Errorf("ParseMoney(%q) unexpected error: %v", tt.input, err)
+			}
+			if result != tt.expected {
+				t.Errorf("ParseMoney(%q) = %v, want %v", tt.input, result, tt.expected)
+			}
+		})
+	}
+}
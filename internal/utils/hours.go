@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"strconv"
+	"strings"
+)
+
+// FormatHours renders an hour count without trailing zeros
+// Example: 8 -> "8", 4.5 -> "4.5"
+func FormatHours(hours float64) string {
+	return strconv.FormatFloat(hours, 'f', -1, 64)
+}
+
+// ParseHours parses an hour count typed as "4.5" or "4,5"
+func ParseHours(s string) (float64, error) {
+	return strconv.ParseFloat(strings.Replace(strings.TrimSpace(s), ",", ".", 1), 64)
+}
@@ -0,0 +1,40 @@
+package utils
+
+import (
+	"testing"
+)
+
+func TestFormatHours(t *testing.T) {
+	tests := map[float64]string{0: "0", 8: "8", 4.5: "4.5", 0.5: "0.5", 160.5: "160.5", -1.5: "-1.5"}
+	for hours, expected := range tests {
+		if result := FormatHours(hours); result != expected {
+			t.Errorf("FormatHours(%v) = %v, want %v", hours, result, expected)
+		}
+	}
+}
+
+func TestParseHours(t *testing.T) {
+	tests := []struct {
+		input    string
+		expected float64
+		wantErr  bool
+	}{
+		{"8", 8, false},
+		{"4.5", 4.5, false},
+		{"4,5", 4.5, false},
+		{" 0.5 ", 0.5, false},
+		{"", 0, true},
+		{"abc", 0, true},
+	}
+
+	for _, tt := range tests {
+		result, err := ParseHours(tt.input)
+		if (err != nil) != tt.wantErr {
+			t.Errorf("ParseHours(%q) error = %v, wantErr %v", tt.input, err, tt.wantErr)
+			continue
+		}
+		if !tt.wantErr && result != tt.expected {
+			t.Errorf("ParseHours(%q) = %v, want %v", tt.input, result, tt.expected)
+		}
+	}
+}
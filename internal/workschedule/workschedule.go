@@ -2,7 +2,11 @@
 // weekday) and computes how many hours a given month is expected to contain.
 package workschedule
 
-import "time"
+import (
+	"fmt"
+	"strings"
+	"time"
+)
 
 // Schedule holds the expected hours for each weekday, indexed by time.Weekday
 // (Sunday=0 .. Saturday=6).
@@ -55,3 +59,64 @@ func ExpectedHoursForMonth(year int, month time.Month, s Schedule) int {
 	}
 	return total
 }
+
+// IsWorkingDay reports whether the schedule expects hours on weekday d.
+// Non-working weekdays are expected to stay empty.
+func (s Schedule) IsWorkingDay(d time.Weekday) bool {
+	return s[d] > 0
+}
+
+// WithWorkingDays returns a copy of s with every weekday not in days set to
+// zero hours. An empty days list leaves s unchanged.
+func (s Schedule) WithWorkingDays(days []time.Weekday) Schedule {
+	if len(days) == 0 {
+		return s
+	}
+	var restricted Schedule
+	for _, d := range days {
+		restricted[d] = s[d]
+	}
+	return restricted
+}
+
+// ParseWeekday parses an English weekday name ("monday", "Mon", …)
+func ParseWeekday(name string) (time.Weekday, error) {
+	name = strings.ToLower(strings.TrimSpace(name))
+	if len(name) >= 3 {
+		for d := time.Sunday; d <= time.Saturday; d++ {
+			if strings.HasPrefix(strings.ToLower(d.String()), name) {
+				return d, nil
+			}
+		}
+	}
+	return 0, fmt.Errorf("invalid weekday %q", name)
+}
+
+// WeekRange returns the first and last day of the week containing date, for
+// weeks that begin on start. Both are at midnight local time.
+func WeekRange(date time.Time, start time.Weekday) (first, last time.Time) {
+	day := time.Date(date.Year(), date.Month(), date.Day(), 0, 0, 0, 0, time.Local)
+	offset := (int(day.Weekday()) - int(start) + 7) % 7
+	first = day.AddDate(0, 0, -offset)
+	return first, first.AddDate(0, 0, 6)
+}
+
+// MissingDays returns the working days of the month, up to and including
+// through, that have no entry in logged (keyed by YYYY-MM-DD). Days the
+// schedule has no hours for are never reported.
+func MissingDays(year int, month time.Month, logged map[string]bool, s Schedule, through time.Time) []string {
+	firstDay := time.Date(year, month, 1, 0, 0, 0, 0, time.Local)
+	lastDay := time.Date(year, month+1, 0, 0, 0, 0, 0, time.Local)
+	if end := time.Date(through.Year(), through.Month(), through.Day(), 0, 0, 0, 0, time.Local); end.Before(lastDay) {
+		lastDay = end
+	}
+
+	var missing []string
+	for day := firstDay; !day.After(lastDay); day = day.AddDate(0, 0, 1) {
+		date := day.Format("2006-01-02")
+		if s.IsWorkingDay(day.Weekday()) && !logged[date] {
+			missing = append(missing, date)
+		}
+	}
+	return missing
+}
@@ -87,3 +87,87 @@ func TestExpectedHoursForMonth_CustomSchedule(t *testing.T) {
 		t.Errorf("40h-week schedule on June 2026 = %d, want 176", got)
 	}
 }
+
+func TestWithWorkingDays(t *testing.T) {
+	full := Schedule{0, 8, 8, 8, 8, 8, 0}
+	fourDays := full.WithWorkingDays([]time.Weekday{time.Monday, time.Tuesday, time.Wednesday, time.Thursday})
+
+	if fourDays.WeeklyTotal() != 32 {
+		t.Errorf("WeeklyTotal() = %d, want 32", fourDays.WeeklyTotal())
+	}
+	if fourDays.IsWorkingDay(time.Friday) {
+		t.Error("Friday should not be a working day")
+	}
+	if !fourDays.IsWorkingDay(time.Thursday) {
+		t.Error("Thursday should be a working day")
+	}
+	if full.WithWorkingDays(nil) != full {
+		t.Error("an empty working days list should leave the schedule unchanged")
+	}
+}
+
+func TestParseWeekday(t *testing.T) {
+	tests := []struct {
+		in      string
+		want    time.Weekday
+		wantErr bool
+	}{
+		{"monday", time.Monday, false},
+		{"Fri", time.Friday, false},
+		{" SUNDAY ", time.Sunday, false},
+		{"mo", 0, true},
+		{"funday", 0, true},
+	}
+	for _, tt := range tests {
+		got, err := ParseWeekday(tt.in)
+		if (err != nil) != tt.wantErr || (!tt.wantErr && got != tt.want) {
+			t.Errorf("ParseWeekday(%q) = %v, %v; want %v, error %v", tt.in, got, err, tt.want, tt.wantErr)
+		}
+	}
+}
+
+func TestMissingDays(t *testing.T) {
+	// June 2026 starts on a Monday. Default schedule: Mon/Tue/Wed/Fri.
+	logged := map[string]bool{"2026-06-01": true, "2026-06-03": true}
+	through := time.Date(2026, time.June, 8, 17, 0, 0, 0, time.Local)
+
+	got := MissingDays(2026, time.June, logged, Default(), through)
+	want := []string{"2026-06-02", "2026-06-05", "2026-06-08"}
+	if len(got) != len(want) {
+		t.Fatalf("MissingDays() = %v, want %v", got, want)
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("MissingDays()[%d] = %s, want %s", i, got[i], want[i])
+		}
+	}
+
+	// A month entirely in the future has nothing missing yet
+	if got := MissingDays(2026, time.July, nil, Default(), through); len(got) != 0 {
+		t.Errorf("MissingDays() for a future month = %v, want none", got)
+	}
+}
+
+func TestWeekRange(t *testing.T) {
+	// Wednesday 13 March 2024
+	date := time.Date(2024, time.March, 13, 15, 30, 0, 0, time.Local)
+	tests := []struct {
+		start     time.Weekday
+		wantFirst string
+		wantLast  string
+	}{
+		{time.Monday, "2024-03-11", "2024-03-17"},
+		{time.Sunday, "2024-03-10", "2024-03-16"},
+		{time.Wednesday, "2024-03-13", "2024-03-19"},
+		{time.Thursday, "2024-03-07", "2024-03-13"},
+	}
+	for _, tt := range tests {
+		first, last := WeekRange(date, tt.start)
+		if got := first.Format("2006-01-02"); got != tt.wantFirst {
+			t.Errorf("WeekRange(%v) first = %s, want %s", tt.start, got, tt.wantFirst)
+		}
+		if got := last.Format("2006-01-02"); got != tt.wantLast {
+			t.Errorf("WeekRange(%v) last = %s, want %s", tt.start, got, tt.wantLast)
+		}
+	}
+}